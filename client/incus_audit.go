@@ -0,0 +1,70 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Audit log handling functions
+
+// GetAuditEntries returns the entries of the audit log.
+func (r *ProtocolIncus) GetAuditEntries() ([]api.AuditEntry, error) {
+	return r.GetAuditEntriesWithFilter(nil)
+}
+
+// GetAuditEntriesWithFilter returns the entries of the audit log matching the given filters.
+func (r *ProtocolIncus) GetAuditEntriesWithFilter(filters []string) ([]api.AuditEntry, error) {
+	return r.GetAuditEntriesWithArgs(&AuditEntriesArgs{Filters: filters})
+}
+
+// GetAuditEntriesWithArgs returns the entries of the audit log selected by the given arguments.
+func (r *ProtocolIncus) GetAuditEntriesWithArgs(args *AuditEntriesArgs) ([]api.AuditEntry, error) {
+	if !r.HasExtension("audit_log") {
+		return nil, errors.New("The server is missing the required \"audit_log\" API extension")
+	}
+
+	entries := []api.AuditEntry{}
+
+	v := url.Values{}
+	if args != nil {
+		if len(args.Filters) > 0 {
+			v.Set("filter", parseFilters(args.Filters))
+		}
+
+		if !args.Start.IsZero() {
+			v.Set("start", args.Start.Format(time.RFC3339))
+		}
+
+		if !args.End.IsZero() {
+			v.Set("end", args.End.Format(time.RFC3339))
+		}
+
+		if args.Username != "" {
+			v.Set("username", args.Username)
+		}
+
+		if args.Project != "" {
+			v.Set("entity-project", args.Project)
+		}
+
+		if args.After > 0 {
+			v.Set("after", strconv.FormatInt(args.After, 10))
+		}
+
+		if args.Limit > 0 {
+			v.Set("limit", strconv.Itoa(args.Limit))
+		}
+	}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/audit?%s", v.Encode()), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,71 @@
+package incus
+
+import (
+	"errors"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Backup repository handling functions
+
+// GetBackupRepositoryBackups returns the backups stored in the repository whose name starts with the prefix.
+func (r *ProtocolIncus) GetBackupRepositoryBackups(target api.BackupTarget, prefix string) ([]api.BackupRepositoryBackup, error) {
+	if !r.HasExtension("backup_repository") {
+		return nil, errors.New("The server is missing the required \"backup_repository\" API extension")
+	}
+
+	backups := []api.BackupRepositoryBackup{}
+
+	req := api.BackupRepositoryPost{
+		Action: "list",
+		Target: target,
+		Prefix: prefix,
+	}
+
+	_, err := r.queryStruct("POST", "/backup-repository", req, "", &backups)
+	if err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// VerifyBackupRepository checks the integrity of the backups stored in the repository whose name starts with the prefix.
+func (r *ProtocolIncus) VerifyBackupRepository(target api.BackupTarget, prefix string) (Operation, error) {
+	if !r.HasExtension("backup_repository") {
+		return nil, errors.New("The server is missing the required \"backup_repository\" API extension")
+	}
+
+	req := api.BackupRepositoryPost{
+		Action: "verify",
+		Target: target,
+		Prefix: prefix,
+	}
+
+	op, _, err := r.queryOperation("POST", "/backup-repository", req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// PruneBackupRepository deletes the given backups from the repository and removes the data no longer used by any backup.
+func (r *ProtocolIncus) PruneBackupRepository(target api.BackupTarget, backups []string) (Operation, error) {
+	if !r.HasExtension("backup_repository") {
+		return nil, errors.New("The server is missing the required \"backup_repository\" API extension")
+	}
+
+	req := api.BackupRepositoryPost{
+		Action: "prune",
+		Target: target,
+		Delete: backups,
+	}
+
+	op, _, err := r.queryOperation("POST", "/backup-repository", req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -526,7 +526,21 @@ func (r *ProtocolIncus) CreateInstanceFromBackup(args InstanceBackupArgs) (Opera
 		return nil, err
 	}
 
-	if args.PoolName == "" && args.Name == "" && args.Config == nil && args.Devices == nil {
+	// Backups restored from a repository are identified by the repository sent as the request body.
+	if args.Repository != nil {
+		if !r.HasExtension("backup_repository") {
+			return nil, errors.New(`The server is missing the required "backup_repository" API extension`)
+		}
+
+		data, err := json.Marshal(args.Repository)
+		if err != nil {
+			return nil, err
+		}
+
+		args.BackupFile = bytes.NewReader(data)
+	}
+
+	if args.PoolName == "" && args.Name == "" && args.Config == nil && args.Devices == nil && !args.Chain && args.Repository == nil {
 		// Send the request
 		op, _, err := r.queryOperation("POST", path, args.BackupFile, "")
 		if err != nil {
@@ -548,6 +562,10 @@ func (r *ProtocolIncus) CreateInstanceFromBackup(args InstanceBackupArgs) (Opera
 		return nil, errors.New(`The server is missing the required "backup_override_config" API extension`)
 	}
 
+	if args.Chain && !r.HasExtension("backup_incremental") {
+		return nil, errors.New(`The server is missing the required "backup_incremental" API extension`)
+	}
+
 	// Prepare the HTTP request
 	reqURL, err := r.setQueryAttributes(fmt.Sprintf("%s/1.0%s", r.httpBaseURL.String(), path))
 	if err != nil {
@@ -579,6 +597,14 @@ func (r *ProtocolIncus) CreateInstanceFromBackup(args InstanceBackupArgs) (Opera
 		req.Header.Set("X-Incus-devices", devicesOverride)
 	}
 
+	if args.Chain {
+		req.Header.Set("X-Incus-chain", "true")
+	}
+
+	if args.Repository != nil {
+		req.Header.Set("X-Incus-repository-backup", args.RepositoryBackup)
+	}
+
 	// Send the request
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -2954,6 +2980,10 @@ func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.Ins
 		return nil, errors.New("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if backup.IncrementalFrom != "" && !r.HasExtension("backup_incremental") {
+		return nil, errors.New(`The server is missing the required "backup_incremental" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "")
 	if err != nil {
@@ -3078,6 +3108,10 @@ func (r *ProtocolIncus) CreateInstanceBackupStream(instanceName string, backup a
 		return errors.New("The server is missing the required \"direct_backup\" API extension")
 	}
 
+	if backup.IncrementalFrom != "" && !r.HasExtension("backup_incremental") {
+		return errors.New(`The server is missing the required "backup_incremental" API extension`)
+	}
+
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
 	if err != nil {
 		return err
@@ -3304,6 +3338,27 @@ func (r *ProtocolIncus) RepairInstance(name string, repair api.InstanceDebugRepa
 	return nil
 }
 
+// PromoteInstance turns a replica into a regular instance.
+func (r *ProtocolIncus) PromoteInstance(name string) error {
+	err := r.CheckExtension("replication")
+	if err != nil {
+		return err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query("POST", fmt.Sprintf("%s/%s/promote", path, url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *ProtocolIncus) getInstanceNVRAM(name string, guid string, varName string, accept string) (*http.Response, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
 	if err != nil {
@@ -0,0 +1,106 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// GetNetworkReservationAddresses returns a list of network reservation addresses.
+func (r *ProtocolIncus) GetNetworkReservationAddresses(networkName string) ([]string, error) {
+	if !r.HasExtension("network_reservations") {
+		return nil, errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := fmt.Sprintf("/networks/%s/reservations", url.PathEscape(networkName))
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetNetworkReservations returns a list of network reservation structs.
+func (r *ProtocolIncus) GetNetworkReservations(networkName string) ([]api.NetworkReservation, error) {
+	if !r.HasExtension("network_reservations") {
+		return nil, errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	reservations := []api.NetworkReservation{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/reservations?recursion=1", url.PathEscape(networkName)), nil, "", &reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// GetNetworkReservation returns a network reservation entry for the provided network and address.
+func (r *ProtocolIncus) GetNetworkReservation(networkName string, address string) (*api.NetworkReservation, string, error) {
+	if !r.HasExtension("network_reservations") {
+		return nil, "", errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	reservation := api.NetworkReservation{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/reservations/%s", url.PathEscape(networkName), url.PathEscape(address)), nil, "", &reservation)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &reservation, etag, nil
+}
+
+// CreateNetworkReservation defines a new network reservation using the provided struct.
+func (r *ProtocolIncus) CreateNetworkReservation(networkName string, reservation api.NetworkReservationsPost) error {
+	if !r.HasExtension("network_reservations") {
+		return errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/networks/%s/reservations", url.PathEscape(networkName)), reservation, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateNetworkReservation updates the network reservation to match the provided struct.
+func (r *ProtocolIncus) UpdateNetworkReservation(networkName string, address string, reservation api.NetworkReservationPut, ETag string) error {
+	if !r.HasExtension("network_reservations") {
+		return errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/networks/%s/reservations/%s", url.PathEscape(networkName), url.PathEscape(address)), reservation, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNetworkReservation deletes an existing network reservation.
+func (r *ProtocolIncus) DeleteNetworkReservation(networkName string, address string) error {
+	if !r.HasExtension("network_reservations") {
+		return errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/networks/%s/reservations/%s", url.PathEscape(networkName), url.PathEscape(address)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
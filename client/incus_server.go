@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"slices"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/websocket"
 
@@ -195,6 +198,85 @@ func (r *ProtocolIncus) GetMetrics() (string, error) {
 	return string(content), nil
 }
 
+// GetMetricsHistory returns the recorded history of the instance and host metrics.
+func (r *ProtocolIncus) GetMetricsHistory(args *MetricsHistoryArgs) (*api.MetricsHistory, error) {
+	err := r.CheckExtension("metrics_history")
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	if args != nil {
+		if args.Instance != "" {
+			v.Set("instance", args.Instance)
+		}
+
+		if args.Metric != "" {
+			v.Set("metric", args.Metric)
+		}
+
+		if !args.Start.IsZero() {
+			v.Set("start", args.Start.Format(time.RFC3339))
+		}
+
+		if !args.End.IsZero() {
+			v.Set("end", args.End.Format(time.RFC3339))
+		}
+
+		if args.Step > 0 {
+			v.Set("step", strconv.FormatInt(int64(args.Step/time.Second), 10))
+		}
+
+		if args.AllProjects {
+			v.Set("all-projects", "true")
+		}
+	}
+
+	history := api.MetricsHistory{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("/metrics/history?%s", v.Encode()), nil, "", &history)
+	if err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// PlanManifest returns the changes needed to converge the server to the provided manifest.
+func (r *ProtocolIncus) PlanManifest(manifest api.ManifestPost) (*api.ManifestPlan, error) {
+	err := r.CheckExtension("manifest_apply")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := api.ManifestPlan{}
+	manifest.DryRun = true
+
+	_, err = r.queryStruct("POST", "/manifest", manifest, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// ApplyManifest converges the server to the provided manifest.
+func (r *ProtocolIncus) ApplyManifest(manifest api.ManifestPost) (Operation, error) {
+	err := r.CheckExtension("manifest_apply")
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.DryRun = false
+
+	op, _, err := r.queryOperation("POST", "/manifest", manifest, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // ApplyServerPreseed configures a target Incus server with the provided server and cluster configuration.
 func (r *ProtocolIncus) ApplyServerPreseed(config api.InitPreseed) error {
 	// Apply server configuration.
@@ -413,6 +413,42 @@ func (r *ProtocolIncus) DeleteStoragePoolBucketKey(poolName string, bucketName s
 	return nil
 }
 
+// GetStoragePoolBucketLifecycle returns the lifecycle configuration of a storage bucket.
+func (r *ProtocolIncus) GetStoragePoolBucketLifecycle(poolName string, bucketName string) (*api.StorageBucketLifecycle, string, error) {
+	err := r.CheckExtension("storage_bucket_lifecycle")
+	if err != nil {
+		return nil, "", err
+	}
+
+	lifecycle := api.StorageBucketLifecycle{}
+
+	// Fetch the raw value.
+	u := api.NewURL().Path("storage-pools", poolName, "buckets", bucketName, "lifecycle")
+	etag, err := r.queryStruct("GET", u.String(), nil, "", &lifecycle)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &lifecycle, etag, nil
+}
+
+// UpdateStoragePoolBucketLifecycle replaces the lifecycle configuration of a storage bucket.
+func (r *ProtocolIncus) UpdateStoragePoolBucketLifecycle(poolName string, bucketName string, lifecycle api.StorageBucketLifecycle, ETag string) error {
+	err := r.CheckExtension("storage_bucket_lifecycle")
+	if err != nil {
+		return err
+	}
+
+	// Send the request.
+	u := api.NewURL().Path("storage-pools", poolName, "buckets", bucketName, "lifecycle")
+	_, _, err = r.query("PUT", u.String(), lifecycle, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateStoragePoolBucketBackup creates a new storage bucket backup.
 func (r *ProtocolIncus) CreateStoragePoolBucketBackup(poolName string, bucketName string, backup api.StorageBucketBackupsPost) (Operation, error) {
 	err := r.CheckExtension("storage_bucket_backup")
@@ -938,6 +938,42 @@ func (r *ProtocolIncus) RebuildStoragePoolVolume(pool string, volType string, na
 	return op, nil
 }
 
+// RekeyStoragePoolVolume rotates the passphrase unlocking an encrypted storage volume.
+func (r *ProtocolIncus) RekeyStoragePoolVolume(pool string, volType string, name string) (Operation, error) {
+	err := r.CheckExtension("storage_volume_encryption")
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/rekey", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name))
+
+	// Send the request.
+	op, _, err := r.queryOperation("POST", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// PromoteStoragePoolVolume turns a replicated storage volume into a regular volume.
+func (r *ProtocolIncus) PromoteStoragePoolVolume(pool string, volType string, name string) error {
+	err := r.CheckExtension("replication")
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/promote", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name))
+
+	// Send the request.
+	_, _, err = r.query("POST", path, nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RenameStoragePoolVolume renames a storage volume.
 func (r *ProtocolIncus) RenameStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePost) error {
 	if !r.HasExtension("storage_api_volume_rename") {
@@ -1012,6 +1048,10 @@ func (r *ProtocolIncus) CreateStorageVolumeBackup(pool string, volName string, b
 		return nil, errors.New("The server is missing the required \"custom_volume_backup\" API extension")
 	}
 
+	if backup.IncrementalFrom != "" && !r.HasExtension("backup_incremental") {
+		return nil, errors.New(`The server is missing the required "backup_incremental" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups", url.PathEscape(pool), url.PathEscape(volName)), backup, "")
 	if err != nil {
@@ -1124,6 +1164,10 @@ func (r *ProtocolIncus) CreateStorageVolumeBackupStream(pool string, volName str
 		return errors.New("The server is missing the required \"direct_backup\" API extension")
 	}
 
+	if backup.IncrementalFrom != "" && !r.HasExtension("backup_incremental") {
+		return errors.New(`The server is missing the required "backup_incremental" API extension`)
+	}
+
 	// Build the URL
 	uri := fmt.Sprintf("%s/1.0/storage-pools/%s/volumes/custom/%s/backups", r.httpBaseURL.String(), url.PathEscape(pool), url.PathEscape(volName))
 	if r.project != "" {
@@ -1262,6 +1306,24 @@ func (r *ProtocolIncus) CreateStoragePoolVolumeFromBackup(pool string, args Stor
 		return nil, errors.New(`The server is missing the required "backup_override_name" API extension`)
 	}
 
+	if args.Chain && !r.HasExtension("backup_incremental") {
+		return nil, errors.New(`The server is missing the required "backup_incremental" API extension`)
+	}
+
+	// Backups restored from a repository are identified by the repository sent as the request body.
+	if args.Repository != nil {
+		if !r.HasExtension("backup_repository") {
+			return nil, errors.New(`The server is missing the required "backup_repository" API extension`)
+		}
+
+		data, err := json.Marshal(args.Repository)
+		if err != nil {
+			return nil, err
+		}
+
+		args.BackupFile = bytes.NewReader(data)
+	}
+
 	path := fmt.Sprintf("/storage-pools/%s/volumes/custom", url.PathEscape(pool))
 
 	// Prepare the HTTP request.
@@ -1281,6 +1343,14 @@ func (r *ProtocolIncus) CreateStoragePoolVolumeFromBackup(pool string, args Stor
 		req.Header.Set("X-Incus-name", args.Name)
 	}
 
+	if args.Chain {
+		req.Header.Set("X-Incus-chain", "true")
+	}
+
+	if args.Repository != nil {
+		req.Header.Set("X-Incus-repository-backup", args.RepositoryBackup)
+	}
+
 	// Send the request.
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -76,10 +77,13 @@ type InstanceServer interface {
 
 	// Server functions
 	GetMetrics() (metrics string, err error)
+	GetMetricsHistory(args *MetricsHistoryArgs) (history *api.MetricsHistory, err error)
 	GetServer() (server *api.Server, ETag string, err error)
 	GetServerResources() (resources *api.Resources, err error)
 	UpdateServer(server api.ServerPut, ETag string) (err error)
 	ApplyServerPreseed(config api.InitPreseed) error
+	PlanManifest(manifest api.ManifestPost) (plan *api.ManifestPlan, err error)
+	ApplyManifest(manifest api.ManifestPost) (op Operation, err error)
 	HasExtension(extension string) (exists bool)
 	RequireAuthenticated(authenticated bool)
 	IsClustered() (clustered bool)
@@ -119,6 +123,7 @@ type InstanceServer interface {
 	UpdateInstances(state api.InstancesPut, ETag string) (op Operation, err error)
 	RebuildInstance(instanceName string, req api.InstanceRebuildPost) (op Operation, err error)
 	RebuildInstanceFromImage(source ImageServer, image api.Image, instanceName string, req api.InstanceRebuildPost) (op RemoteOperation, err error)
+	PromoteInstance(name string) (err error)
 
 	ExecInstance(instanceName string, exec api.InstanceExecPost, args *InstanceExecArgs) (op Operation, err error)
 	ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (op Operation, err error)
@@ -232,6 +237,14 @@ type InstanceServer interface {
 	UpdateNetworkForward(networkName string, listenAddress string, forward api.NetworkForwardPut, ETag string) (err error)
 	DeleteNetworkForward(networkName string, listenAddress string) (err error)
 
+	// Network reservation functions ("network_reservations" API extension)
+	GetNetworkReservationAddresses(networkName string) ([]string, error)
+	GetNetworkReservations(networkName string) ([]api.NetworkReservation, error)
+	GetNetworkReservation(networkName string, address string) (reservation *api.NetworkReservation, ETag string, err error)
+	CreateNetworkReservation(networkName string, reservation api.NetworkReservationsPost) error
+	UpdateNetworkReservation(networkName string, address string, reservation api.NetworkReservationPut, ETag string) (err error)
+	DeleteNetworkReservation(networkName string, address string) (err error)
+
 	// Network load balancer functions ("network_load_balancer" API extension)
 	GetNetworkLoadBalancerAddresses(networkName string) ([]string, error)
 	GetNetworkLoadBalancers(networkName string) ([]api.NetworkLoadBalancer, error)
@@ -373,6 +386,10 @@ type InstanceServer interface {
 	CreateStoragePoolBucketBackupStream(pool string, bucketName string, backup api.StorageBucketBackupsPost, req *BackupFileRequest) (err error)
 	CreateStoragePoolBucketFromBackup(pool string, args StoragePoolBucketBackupArgs) (op Operation, err error)
 
+	// Storage bucket lifecycle functions ("storage_bucket_lifecycle" API extension)
+	GetStoragePoolBucketLifecycle(poolName string, bucketName string) (lifecycle *api.StorageBucketLifecycle, ETag string, err error)
+	UpdateStoragePoolBucketLifecycle(poolName string, bucketName string, lifecycle api.StorageBucketLifecycle, ETag string) (err error)
+
 	// Storage volume functions ("storage" API extension)
 	GetStoragePoolVolumeNames(pool string) (names []string, err error)
 	GetStoragePoolVolumeNamesAllProjects(pool string) (names map[string][]string, err error)
@@ -397,6 +414,8 @@ type InstanceServer interface {
 
 	// Storage volume rebuild ("storage_volumes_rebuild" API extension)
 	RebuildStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumeRebuildPost) (op Operation, err error)
+	RekeyStoragePoolVolume(pool string, volType string, name string) (op Operation, err error)
+	PromoteStoragePoolVolume(pool string, volType string, name string) (err error)
 
 	// Storage volume snapshot functions ("storage_api_volume_snapshots" API extension)
 	CreateStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshot api.StorageVolumeSnapshotsPost) (op Operation, err error)
@@ -471,6 +490,16 @@ type InstanceServer interface {
 	UpdateWarning(UUID string, warning api.WarningPut, ETag string) (err error)
 	DeleteWarning(UUID string) (err error)
 
+	// Audit log functions
+	GetAuditEntries() (entries []api.AuditEntry, err error)
+	GetAuditEntriesWithFilter(filters []string) (entries []api.AuditEntry, err error)
+	GetAuditEntriesWithArgs(args *AuditEntriesArgs) (entries []api.AuditEntry, err error)
+
+	// Backup repository functions
+	GetBackupRepositoryBackups(target api.BackupTarget, prefix string) (backups []api.BackupRepositoryBackup, err error)
+	VerifyBackupRepository(target api.BackupTarget, prefix string) (op Operation, err error)
+	PruneBackupRepository(target api.BackupTarget, backups []string) (op Operation, err error)
+
 	// Internal functions (for internal use)
 	RawQuery(method string, path string, data any, queryETag string) (resp *api.Response, ETag string, err error)
 	RawWebsocket(path string) (conn *websocket.Conn, err error)
@@ -621,6 +650,58 @@ type StorageVolumeBackupArgs struct {
 
 	// Name to import backup as
 	Name string
+
+	// Whether the backup file is a backup chain (API extension: backup_incremental)
+	Chain bool
+
+	// Backup repository to restore the backup from instead of the backup file (API extension: backup_repository)
+	Repository *api.BackupTarget
+
+	// Name of the backup in the repository (API extension: backup_repository)
+	RepositoryBackup string
+}
+
+// The MetricsHistoryArgs struct is used to filter the metrics history.
+// API extension: metrics_history.
+type MetricsHistoryArgs struct {
+	// Only retrieve the history of this instance
+	Instance string
+
+	// Only retrieve the history of this metric
+	Metric string
+
+	// Time range (defaults to the last hour)
+	Start time.Time
+	End   time.Time
+
+	// Interval between points (defaults to the recorded resolution)
+	Step time.Duration
+
+	// Retrieve the history of instances from all projects
+	AllProjects bool
+}
+
+// The AuditEntriesArgs struct is used to select the audit log entries to retrieve.
+// API extension: audit_log.
+type AuditEntriesArgs struct {
+	// Only retrieve the entries matching these filters
+	Filters []string
+
+	// Time range (defaults to the whole log)
+	Start time.Time
+	End   time.Time
+
+	// Only retrieve the calls made by this user
+	Username string
+
+	// Only retrieve the calls applying to this project
+	Project string
+
+	// Only retrieve the entries following the one with this identifier
+	After int64
+
+	// Maximum number of entries to retrieve (defaults to all of them)
+	Limit int
 }
 
 // The InstanceBackupArgs struct is used when creating a instance from a backup.
@@ -639,6 +720,15 @@ type InstanceBackupArgs struct {
 
 	// Device overrides.
 	Devices []string
+
+	// Whether the backup file is a backup chain (API extension: backup_incremental)
+	Chain bool
+
+	// Backup repository to restore the backup from instead of the backup file (API extension: backup_repository)
+	Repository *api.BackupTarget
+
+	// Name of the backup in the repository (API extension: backup_repository)
+	RepositoryBackup string
 }
 
 // The InstanceCopyArgs struct is used to pass additional options during instance copy.
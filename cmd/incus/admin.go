@@ -20,6 +20,10 @@ func (c *cmdAdmin) command() *cobra.Command {
 	cmd.Short = i18n.G("Manage incus daemon")
 	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Manage incus daemon`))
 
+	// audit
+	adminAuditCmd := cmdAdminAudit{global: c.global}
+	cmd.AddCommand(adminAuditCmd.command())
+
 	// cluster
 	adminClusterCmd := cmdAdminCluster{global: c.global}
 	cmd.AddCommand(adminClusterCmd.command())
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+)
+
+type auditColumn struct {
+	Name string
+	Data func(api.AuditEntry) string
+}
+
+type cmdAdminAudit struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAdminAudit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("audit")
+	cmd.Short = i18n.G("Inspect the audit log")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Inspect the audit log
+
+The audit log records every mutating API call along with its caller and result.`,
+	))
+
+	// List
+	adminAuditListCmd := cmdAdminAuditList{global: c.global, audit: c}
+	cmd.AddCommand(adminAuditListCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdAdminAuditList struct {
+	global *cmdGlobal
+	audit  *cmdAdminAudit
+
+	flagColumns       string
+	flagFormat        string
+	flagStart         string
+	flagEnd           string
+	flagUser          string
+	flagEntityProject string
+	flagLimit         int
+}
+
+const defaultAuditColumns = "dLumesE"
+
+var cmdAdminAuditListUsage = u.Usage{u.RemoteColonOpt, u.Filter.List(0)}
+
+func (c *cmdAdminAuditList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdAdminAuditListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the audit log entries")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`List the audit log entries
+
+Filters may be of the <key>=<value> form, for example requestor.username=foo or method=DELETE.
+
+The -c option takes a (optionally comma-separated) list of arguments
+that control which entry attributes to output when displaying in table
+or csv format.
+
+Default column layout is: dLumesE
+
+Column shorthand chars:
+    a - Address
+    d - Date
+    D - Body digest
+    e - Entity URL
+    E - Error
+    L - Location
+    m - Method
+    o - Operation
+    p - Project
+    P - Protocol
+    s - Status code
+    u - Username`,
+	))
+
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus admin audit list requestor.username=foo
+    List the calls made by the user foo
+
+incus admin audit list method=DELETE project=web -f yaml
+    Show the details of the deletions made in the web project
+
+incus admin audit list --user foo --start 2021-03-23T00:00:00Z --limit 100
+    List the first hundred calls made by the user foo since the given date`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagColumns, "columns|c", defaultAuditColumns, "", i18n.G("Columns"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+	cli.AddStringFlag(cmd.Flags(), &c.flagStart, "start", "", "", i18n.G("Only list the calls made at or after this date (format: rfc3339)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagEnd, "end", "", "", i18n.G("Only list the calls made before this date (format: rfc3339)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagUser, "user", "", "", i18n.G("Only list the calls made by this user"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagEntityProject, "entity-project", "", "", i18n.G("Only list the calls applying to this project"))
+	cli.AddIntFlag(cmd.Flags(), &c.flagLimit, "limit", i18n.G("Maximum number of entries to list"), 0)
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAdminAuditList) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAdminAuditListUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	filters := parsed[1].StringList
+
+	query := incus.AuditEntriesArgs{
+		Filters:  filters,
+		Username: c.flagUser,
+		Project:  c.flagEntityProject,
+		Limit:    c.flagLimit,
+	}
+
+	if c.flagStart != "" {
+		query.Start, err = time.Parse(time.RFC3339, c.flagStart)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid start date: %w"), err)
+		}
+	}
+
+	if c.flagEnd != "" {
+		query.End, err = time.Parse(time.RFC3339, c.flagEnd)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid end date: %w"), err)
+		}
+	}
+
+	entries, err := d.GetAuditEntriesWithArgs(&query)
+	if err != nil {
+		return err
+	}
+
+	// Process the columns
+	columns, err := c.parseColumns(d.IsClustered())
+	if err != nil {
+		return err
+	}
+
+	// Render the table, keeping the entries in chronological order.
+	data := [][]string{}
+	for _, entry := range entries {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, column.Data(entry))
+		}
+
+		data = append(data, row)
+	}
+
+	rawData := make([]*api.AuditEntry, len(entries))
+	for i := range entries {
+		rawData[i] = &entries[i]
+	}
+
+	headers := []string{}
+	for _, column := range columns {
+		headers = append(headers, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, headers, data, rawData)
+}
+
+func (c *cmdAdminAuditList) addressColumnData(entry api.AuditEntry) string {
+	return entry.Requestor.Address
+}
+
+func (c *cmdAdminAuditList) dateColumnData(entry api.AuditEntry) string {
+	return entry.Timestamp.Local().Format(dateLayout)
+}
+
+func (c *cmdAdminAuditList) digestColumnData(entry api.AuditEntry) string {
+	return entry.BodyDigest
+}
+
+func (c *cmdAdminAuditList) entityURLColumnData(entry api.AuditEntry) string {
+	return entry.EntityURL
+}
+
+func (c *cmdAdminAuditList) errorColumnData(entry api.AuditEntry) string {
+	return entry.Error
+}
+
+func (c *cmdAdminAuditList) locationColumnData(entry api.AuditEntry) string {
+	return entry.Location
+}
+
+func (c *cmdAdminAuditList) methodColumnData(entry api.AuditEntry) string {
+	return entry.Method
+}
+
+func (c *cmdAdminAuditList) operationColumnData(entry api.AuditEntry) string {
+	return entry.Operation
+}
+
+func (c *cmdAdminAuditList) projectColumnData(entry api.AuditEntry) string {
+	return entry.Project
+}
+
+func (c *cmdAdminAuditList) protocolColumnData(entry api.AuditEntry) string {
+	return entry.Requestor.Protocol
+}
+
+func (c *cmdAdminAuditList) statusCodeColumnData(entry api.AuditEntry) string {
+	return strconv.Itoa(entry.StatusCode)
+}
+
+func (c *cmdAdminAuditList) usernameColumnData(entry api.AuditEntry) string {
+	return entry.Requestor.Username
+}
+
+func (c *cmdAdminAuditList) parseColumns(clustered bool) ([]auditColumn, error) {
+	columnsShorthandMap := map[rune]auditColumn{
+		'a': {i18n.G("ADDRESS"), c.addressColumnData},
+		'd': {i18n.G("DATE"), c.dateColumnData},
+		'D': {i18n.G("BODY DIGEST"), c.digestColumnData},
+		'e': {i18n.G("ENTITY"), c.entityURLColumnData},
+		'E': {i18n.G("ERROR"), c.errorColumnData},
+		'm': {i18n.G("METHOD"), c.methodColumnData},
+		'o': {i18n.G("OPERATION"), c.operationColumnData},
+		'p': {i18n.G("PROJECT"), c.projectColumnData},
+		'P': {i18n.G("PROTOCOL"), c.protocolColumnData},
+		's': {i18n.G("STATUS CODE"), c.statusCodeColumnData},
+		'u': {i18n.G("USERNAME"), c.usernameColumnData},
+	}
+
+	if clustered {
+		columnsShorthandMap['L'] = auditColumn{i18n.G("LOCATION"), c.locationColumnData}
+	} else {
+		if c.flagColumns != defaultAuditColumns {
+			if strings.ContainsAny(c.flagColumns, "L") {
+				return nil, errors.New(i18n.G("Can't specify column L when not clustered"))
+			}
+		}
+
+		c.flagColumns = strings.ReplaceAll(c.flagColumns, "L", "")
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+
+	columns := []auditColumn{}
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
@@ -22,6 +22,10 @@ func (c *cmdAdmin) command() *cobra.Command {
 		`Manage incus daemon`,
 	))
 
+	// audit
+	adminAuditCmd := cmdAdminAudit{global: c.global}
+	cmd.AddCommand(adminAuditCmd.command())
+
 	// os
 	adminOSCmd := cmdAdminOS{global: c.global}
 	cmd.AddCommand(adminOSCmd.command())
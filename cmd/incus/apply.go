@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+)
+
+type cmdApply struct {
+	global *cmdGlobal
+
+	flagFile   string
+	flagPrune  bool
+	flagDryRun bool
+}
+
+var cmdApplyUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdApply) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("apply", cmdApplyUsage...)
+	cmd.Short = i18n.G("Apply a manifest of resources")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Apply a manifest of resources
+
+The manifest describes projects, network ACLs, networks, custom storage volumes,
+profiles and instances. The changes needed to converge the server to it are
+printed and then applied.
+
+Configuration keys and devices which are listed in the manifest are enforced,
+others are left untouched. When the manifest has a name, the resources it
+manages are tagged with it and --prune deletes those it no longer lists.`,
+	))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus apply -f manifest.yaml
+    Converge the local server to the manifest.
+
+incus apply -f manifest.yaml --dry-run
+    Only show the changes that would be made.
+
+cat manifest.yaml | incus apply remote: -f - --prune
+    Converge the remote server, deleting resources no longer in the manifest.`,
+	))
+
+	cmd.RunE = c.run
+	cli.AddStringFlag(cmd.Flags(), &c.flagFile, "file|f", "", "", i18n.G("Manifest file (- for stdin)"))
+	cli.AddBoolFlag(cmd.Flags(), &c.flagPrune, "prune", i18n.G("Delete resources owned by the manifest which it no longer lists"))
+	cli.AddBoolFlag(cmd.Flags(), &c.flagDryRun, "dry-run", i18n.G("Only show the changes that would be made"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdApply) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdApplyUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+
+	if c.flagFile == "" {
+		return errors.New(i18n.G("A manifest file must be provided with --file"))
+	}
+
+	// Read the manifest.
+	var content []byte
+	if c.flagFile == "-" {
+		content, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed to read from stdin: %w"), err)
+		}
+	} else {
+		content, err = os.ReadFile(c.flagFile)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed to read from file: %w"), err)
+		}
+	}
+
+	manifest := api.Manifest{}
+
+	// Use strict checking to notify about unknown keys.
+	err = yaml.Load(content, &manifest, yaml.WithKnownFields())
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed to parse the manifest: %w"), err)
+	}
+
+	req := api.ManifestPost{Manifest: manifest, Prune: c.flagPrune}
+
+	// Compute and show the plan.
+	plan, err := d.PlanManifest(req)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Changes) == 0 {
+		if !c.global.flagQuiet {
+			fmt.Println(i18n.G("No changes needed"))
+		}
+
+		return nil
+	}
+
+	if !c.global.flagQuiet || c.flagDryRun {
+		printManifestPlan(plan)
+	}
+
+	if c.flagDryRun {
+		return nil
+	}
+
+	op, err := d.ApplyManifest(req)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// printManifestPlan prints the changes of a manifest plan.
+func printManifestPlan(plan *api.ManifestPlan) {
+	symbols := map[string]string{"create": "+", "update": "~", "delete": "-"}
+	counts := map[string]int{}
+
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+
+		location := change.Project
+		if change.Pool != "" {
+			location = change.Pool + ", " + location
+		}
+
+		fmt.Printf("%s %s %s (%s)\n", symbols[change.Action], change.Type, change.Name, location)
+		for _, field := range change.Fields {
+			fmt.Printf("    %s: %q -> %q\n", field.Key, field.Old, field.New)
+		}
+	}
+
+	fmt.Printf(i18n.G("Plan: %d to create, %d to update, %d to delete")+"\n", counts["create"], counts["update"], counts["delete"])
+}
@@ -29,6 +29,7 @@ type cmdExport struct {
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
 	flagForce                bool
+	flagIncrementalFrom      string
 }
 
 var cmdExportUsage = u.Usage{u.Instance.Remote(), u.Target(u.File).Optional()}
@@ -45,7 +46,10 @@ func (c *cmdExport) command() *cobra.Command {
 	Download a backup tarball of the u1 instance.
 
 incus export u1 -
-	Download a backup tarball with it written to the standard output.`,
+	Download a backup tarball with it written to the standard output.
+
+incus export v1 backup1.tar.gz --incremental-from bitmap0
+	Download an incremental backup of the v1 virtual machine holding the blocks changed since bitmap0 was created.`,
 	))
 
 	cmd.RunE = c.run
@@ -54,6 +58,7 @@ incus export u1 -
 	cli.AddBoolFlag(cmd.Flags(), &c.flagOptimizedStorage, "optimized-storage", i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cli.AddStringFlag(cmd.Flags(), &c.flagCompressionAlgorithm, "compression", "", "", i18n.G("Compression algorithm to use (none for uncompressed)"))
 	cli.AddBoolFlag(cmd.Flags(), &c.flagForce, "force|f", i18n.G("Force overwriting existing backup file"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagIncrementalFrom, "incremental-from", "", "", i18n.G("Only backup the blocks changed since the bitmap was created (virtual machines only)"))
 
 	return cmd
 }
@@ -86,6 +91,7 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 		RootOnly:             c.flagRootOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		IncrementalFrom:      c.flagIncrementalFrom,
 	}
 
 	var getter func(backupReq *incus.BackupFileRequest) error
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -19,9 +21,11 @@ import (
 type cmdImport struct {
 	global *cmdGlobal
 
-	flagStorage string
-	flagConfig  []string
-	flagDevice  []string
+	flagStorage     string
+	flagConfig      []string
+	flagDevice      []string
+	flagIncremental []string
+	flagRepository  string
 }
 
 var cmdImportUsage = u.Usage{u.RemoteColonOpt, u.BackupFile, u.NewName(u.Instance).Optional()}
@@ -35,13 +39,21 @@ func (c *cmdImport) command() *cobra.Command {
 	))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`,
+    Create a new instance using backup0.tar.gz as the source.
+
+incus import backup0.tar.gz --incremental backup1.tar.gz --incremental backup2.tar.gz
+    Create a new instance using the full backup0.tar.gz and the incremental backups made on top of it as the source.
+
+incus import instances/default/c1/backup0 --repository repository.yaml
+    Create a new instance using the backup stored in the repository defined in repository.yaml as the source.`,
 	))
 
 	cmd.RunE = c.run
 	cli.AddStringFlag(cmd.Flags(), &c.flagStorage, "storage|s", "", "", i18n.G("Storage pool name"))
 	cli.AddStringArrayFlag(cmd.Flags(), &c.flagConfig, "config|c", i18n.G("Config key/value to apply to the new instance (may be passed multiple times)"))
 	cli.AddStringArrayFlag(cmd.Flags(), &c.flagDevice, "device|d", i18n.G("New key/value to apply to a specific device (may be passed multiple times)"))
+	cli.AddStringArrayFlag(cmd.Flags(), &c.flagIncremental, "incremental", i18n.G("Incremental backup to apply on top of the backup, in order (may be passed multiple times)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagRepository, "repository", "", "", i18n.G("Backup repository definition (YAML file) to restore the backup from"))
 
 	return cmd
 }
@@ -56,24 +68,42 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 	backupFile := parsed[1].String
 	instanceName := parsed[2].String
 
-	var file *os.File
+	if c.flagRepository != "" && len(c.flagIncremental) > 0 {
+		return errors.New(i18n.G("Backups restored from a repository can't have incremental backups"))
+	}
+
+	var file io.ReadCloser
+	var fileSize int64
 	usePercentage := true
-	if isStdin(backupFile) {
+	if c.flagRepository != "" {
+		// The backup file is then the name of the backup in the repository.
+		usePercentage = false
+	} else if len(c.flagIncremental) > 0 {
+		file, fileSize, err = backupChainReader(append([]string{backupFile}, c.flagIncremental...))
+		if err != nil {
+			return err
+		}
+
+		defer logger.WarnOnError(file.Close, "Failed to close backup chain")
+	} else if isStdin(backupFile) {
 		file = os.Stdin
 		usePercentage = false
 	} else {
-		file, err = os.Open(backupFile)
+		f, err := os.Open(backupFile)
 		if err != nil {
 			return err
 		}
 
 		// The HTTP transport closes the request body, so only warn on unexpected errors.
-		defer logger.WarnOnErrorExcept(file.Close, []error{os.ErrClosed}, "Failed to close file")
-	}
+		defer logger.WarnOnErrorExcept(f.Close, []error{os.ErrClosed}, "Failed to close file")
 
-	fstat, err := file.Stat()
-	if err != nil {
-		return err
+		fstat, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		file = f
+		fileSize = fstat.Size()
 	}
 
 	progress := cli.ProgressRenderer{
@@ -85,7 +115,7 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 		BackupFile: &ioprogress.ProgressReader{
 			ReadCloser: file,
 			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
+				Length: fileSize,
 				Handler: func(v int64, speed int64) {
 					if usePercentage {
 						progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", v, units.GetByteSizeString(speed, 2))})
@@ -99,6 +129,17 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 		Name:     instanceName,
 		Config:   c.flagConfig,
 		Devices:  c.flagDevice,
+		Chain:    len(c.flagIncremental) > 0,
+	}
+
+	if c.flagRepository != "" {
+		createArgs.Repository, err = backupRepositoryTarget(c.flagRepository)
+		if err != nil {
+			return err
+		}
+
+		createArgs.RepositoryBackup = backupFile
+		createArgs.BackupFile = nil
 	}
 
 	op, err := d.CreateInstanceFromBackup(createArgs)
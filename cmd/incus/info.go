@@ -7,6 +7,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.yaml.in/yaml/v4"
@@ -655,6 +656,62 @@ func (c *cmdInfo) remoteInfo(d incus.InstanceServer) error {
 	return nil
 }
 
+// renderHistory shows the recorded history of the CPU, memory and network usage of an instance.
+func (c *cmdInfo) renderHistory(history *api.MetricsHistory) {
+	metrics := []struct {
+		name   string
+		label  string
+		format func(value float64) string
+	}{
+		{"cpu", i18n.G("CPU"), func(value float64) string { return fmt.Sprintf(i18n.G("%.2f cores"), value) }},
+		{"memory", i18n.G("Memory"), func(value float64) string { return units.GetByteSizeStringIEC(int64(value), 2) }},
+		{"network_receive", i18n.G("Network received"), func(value float64) string { return units.GetByteSizeString(int64(value), 2) + "/s" }},
+		{"network_transmit", i18n.G("Network sent"), func(value float64) string { return units.GetByteSizeString(int64(value), 2) + "/s" }},
+	}
+
+	var historyInfo strings.Builder
+	for _, metric := range metrics {
+		for _, series := range history.Series {
+			if series.Metric != metric.name || len(series.Points) == 0 {
+				continue
+			}
+
+			values := make([]float64, 0, len(series.Points))
+			for _, point := range series.Points {
+				values = append(values, point.Value)
+			}
+
+			fmt.Fprintf(&historyInfo, "  %s: %s %s\n", metric.label, sparkline(values, len(values)), metric.format(values[len(values)-1]))
+		}
+	}
+
+	if historyInfo.String() != "" {
+		fmt.Println("\n" + i18n.G("History (last hour):"))
+		fmt.Print(historyInfo.String())
+	}
+}
+
+// renderReplicationState prints the replication state of an instance or storage volume.
+func renderReplicationState(state *api.ReplicationState) {
+	if state == nil {
+		return
+	}
+
+	if state.Source != "" {
+		fmt.Printf(i18n.G("Replica of: %s")+"\n", state.Source)
+		return
+	}
+
+	fmt.Printf(i18n.G("Replication target: %s")+"\n", state.Target)
+	if !state.LastSuccess.IsZero() {
+		fmt.Printf(i18n.G("Last replication: %s (lag: %s)")+"\n", state.LastSuccess.Local().Format(dateLayout), time.Duration(state.Lag)*time.Second)
+	}
+
+	if state.LastError != "" {
+		fmt.Printf(i18n.G("Replication error: %s")+"\n", state.LastError)
+	}
+}
+
 func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog string) error {
 	// Quick checks.
 	if c.flagTarget != "" {
@@ -704,11 +761,21 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog stri
 		fmt.Printf(i18n.G("Last Used: %s")+"\n", inst.LastUsedAt.Local().Format(dateLayout))
 	}
 
+	renderReplicationState(inst.State.Replication)
+
 	if inst.State.Pid != 0 {
 		if !inst.State.StartedAt.IsZero() {
 			fmt.Printf(i18n.G("Started: %s")+"\n", inst.State.StartedAt.Local().Format(dateLayout))
 		}
 
+		if inst.State.Health != nil {
+			if inst.State.Health.LastError != "" && inst.State.Health.Status != "healthy" {
+				fmt.Printf(i18n.G("Health: %s (%s)")+"\n", inst.State.Health.Status, inst.State.Health.LastError)
+			} else {
+				fmt.Printf(i18n.G("Health: %s")+"\n", inst.State.Health.Status)
+			}
+		}
+
 		// Operating System info
 		if inst.State.OSInfo != nil {
 			fmt.Println("\n" + i18n.G("Operating System:"))
@@ -822,6 +889,14 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog stri
 			fmt.Printf("  %s\n", i18n.G("Network usage:"))
 			fmt.Print(networkInfo.String())
 		}
+
+		// Metrics history
+		if d.HasExtension("metrics_history") {
+			history, err := d.GetMetricsHistory(&incus.MetricsHistoryArgs{Instance: name, Step: 3 * time.Minute})
+			if err == nil {
+				c.renderHistory(history)
+			}
+		}
 	}
 
 	// List snapshots
@@ -130,6 +130,7 @@ Pre-defined column shorthand chars:
   L - Location of the instance (e.g. its cluster member)
   f - Base Image Fingerprint (short)
   F - Base Image Fingerprint (long)
+  H - Health
 
 Custom columns are defined with "[config:|devices:]key[:name][:maxWidth]":
   KEY: The (extended) config or devices key to display. If [config:|devices:] is omitted then it defaults to config key.
@@ -542,6 +543,7 @@ func (c *cmdList) parseColumns(clustered bool) ([]column, bool, error) {
 		'e': {i18n.G("PROJECT"), c.projectColumnData, false, false},
 		'f': {i18n.G("BASE IMAGE"), c.baseImageColumnData, false, false},
 		'F': {i18n.G("BASE IMAGE"), c.baseImageFullColumnData, false, false},
+		'H': {i18n.G("HEALTH"), c.healthColumnData, true, false},
 		'l': {i18n.G("LAST USED AT"), c.lastUsedColumnData, false, false},
 		'm': {i18n.G("MEMORY USAGE"), c.memoryUsageColumnData, true, false},
 		'M': {i18n.G("MEMORY USAGE%"), c.memoryUsagePercentColumnData, true, false},
@@ -930,6 +932,14 @@ func (c *cmdList) startedColumnData(cInfo api.InstanceFull) string {
 	return ""
 }
 
+func (c *cmdList) healthColumnData(cInfo api.InstanceFull) string {
+	if cInfo.State != nil && cInfo.State.Health != nil {
+		return strings.ToUpper(cInfo.State.Health.Status)
+	}
+
+	return ""
+}
+
 func (c *cmdList) lastUsedColumnData(cInfo api.InstanceFull) string {
 	if !cInfo.LastUsedAt.IsZero() {
 		return cInfo.LastUsedAt.Local().Format(dateLayout)
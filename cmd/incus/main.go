@@ -194,6 +194,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	adminCmd := cmdAdmin{global: &globalCmd}
 	app.AddCommand(adminCmd.command())
 
+	// apply sub-command
+	applyCmd := cmdApply{global: &globalCmd}
+	app.AddCommand(applyCmd.command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.command())
@@ -294,6 +298,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	projectCmd := cmdProject{global: &globalCmd}
 	app.AddCommand(projectCmd.command())
 
+	// promote sub-command
+	promoteCmd := cmdPromote{global: &globalCmd}
+	app.AddCommand(promoteCmd.command())
+
 	// query sub-command
 	queryCmd := cmdQuery{global: &globalCmd}
 	app.AddCommand(queryCmd.command())
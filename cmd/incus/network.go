@@ -131,6 +131,10 @@ func (c *cmdNetwork) command() *cobra.Command {
 	networkPeerCmd := cmdNetworkPeer{global: c.global}
 	cmd.AddCommand(networkPeerCmd.command())
 
+	// Reservation
+	networkReservationCmd := cmdNetworkReservation{global: c.global}
+	cmd.AddCommand(networkReservationCmd.command())
+
 	// Zone
 	networkZoneCmd := cmdNetworkZone{global: c.global}
 	cmd.AddCommand(networkZoneCmd.command())
@@ -1003,6 +1007,27 @@ func (c *cmdNetworkInfo) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// WireGuard information.
+	if state.WireGuard != nil {
+		fmt.Println("")
+		fmt.Println(i18n.G("WireGuard:"))
+		fmt.Printf("  %s: %s\n", i18n.G("Public key"), state.WireGuard.PublicKey)
+		fmt.Printf("  %s: %d\n", i18n.G("Listen port"), state.WireGuard.ListenPort)
+
+		for _, peer := range state.WireGuard.Peers {
+			fmt.Printf("  %s:\n", peer.Member)
+			fmt.Printf("    %s: %s\n", i18n.G("Public key"), peer.PublicKey)
+			fmt.Printf("    %s: %s\n", i18n.G("Endpoint"), peer.Endpoint)
+
+			if !peer.LatestHandshake.IsZero() {
+				fmt.Printf("    %s: %s\n", i18n.G("Latest handshake"), peer.LatestHandshake.Local().Format(dateLayout))
+			}
+
+			fmt.Printf("    %s: %s\n", i18n.G("Bytes received"), units.GetByteSizeString(peer.BytesReceived, 2))
+			fmt.Printf("    %s: %s\n", i18n.G("Bytes sent"), units.GetByteSizeString(peer.BytesSent, 2))
+		}
+	}
+
 	return nil
 }
 
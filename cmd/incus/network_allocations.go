@@ -212,6 +212,11 @@ func (c *cmdNetworkListAllocations) renderSummary(allocations []api.NetworkAlloc
 	networks := make(map[string]*netData)
 
 	for _, alloc := range allocations {
+		// Reservations only keep addresses out of dynamic allocation, they aren't in use.
+		if alloc.Type == "network-reservation" {
+			continue
+		}
+
 		if networks[alloc.Network] == nil {
 			networks[alloc.Network] = &netData{}
 		}
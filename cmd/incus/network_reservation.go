@@ -0,0 +1,510 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/termios"
+)
+
+type cmdNetworkReservation struct {
+	global *cmdGlobal
+}
+
+func (c *cmdNetworkReservation) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("reservation")
+	cmd.Short = i18n.G("Manage network address reservations")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G("Manage network address reservations"))
+
+	// List.
+	networkReservationListCmd := cmdNetworkReservationList{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationListCmd.command())
+
+	// Show.
+	networkReservationShowCmd := cmdNetworkReservationShow{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationShowCmd.command())
+
+	// Create.
+	networkReservationCreateCmd := cmdNetworkReservationCreate{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationCreateCmd.command())
+
+	// Edit.
+	networkReservationEditCmd := cmdNetworkReservationEdit{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationEditCmd.command())
+
+	// Delete.
+	networkReservationDeleteCmd := cmdNetworkReservationDelete{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationDeleteCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdNetworkReservationList struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+
+	flagFormat  string
+	flagColumns string
+}
+
+type networkReservationColumn struct {
+	Name string
+	Data func(api.NetworkReservation) string
+}
+
+var cmdNetworkReservationListUsage = u.Usage{u.Network.Remote()}
+
+func (c *cmdNetworkReservationList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdNetworkReservationListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List available network address reservations")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`List available network address reservations
+
+Default column layout: aod
+
+== Columns ==
+The -c option takes a comma separated list of arguments that control
+which network address reservation attributes to output when displaying
+in table or csv format.
+
+Commas between consecutive shorthand chars are optional.
+
+Pre-defined column shorthand chars:
+  a - Address
+  o - Owner
+  d - Description`,
+	))
+
+	cmd.RunE = c.run
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+	cli.AddStringFlag(cmd.Flags(), &c.flagColumns, "columns|c", defaultNetworkReservationListColumns, "", i18n.G("Columns"))
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+const defaultNetworkReservationListColumns = "aod"
+
+func (c *cmdNetworkReservationList) parseColumns() ([]networkReservationColumn, error) {
+	columnsShorthandMap := map[rune]networkReservationColumn{
+		'a': {i18n.G("ADDRESS"), c.addressColumnData},
+		'o': {i18n.G("OWNER"), c.ownerColumnData},
+		'd': {i18n.G("DESCRIPTION"), c.descriptionColumnData},
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+	columns := []networkReservationColumn{}
+
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func (c *cmdNetworkReservationList) addressColumnData(reservation api.NetworkReservation) string {
+	return reservation.Address
+}
+
+func (c *cmdNetworkReservationList) ownerColumnData(reservation api.NetworkReservation) string {
+	return reservation.Owner
+}
+
+func (c *cmdNetworkReservationList) descriptionColumnData(reservation api.NetworkReservation) string {
+	return reservation.Description
+}
+
+func (c *cmdNetworkReservationList) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdNetworkReservationListUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+
+	reservations, err := d.GetNetworkReservations(networkName)
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := c.parseColumns()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, reservation := range reservations {
+		line := []string{}
+		for _, column := range columns {
+			line = append(line, column.Data(reservation))
+		}
+
+		data = append(data, line)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, reservations)
+}
+
+// Show.
+type cmdNetworkReservationShow struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+}
+
+var cmdNetworkReservationShowUsage = u.Usage{u.Network.Remote(), u.Address}
+
+func (c *cmdNetworkReservationShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdNetworkReservationShowUsage...)
+	cmd.Short = i18n.G("Show network address reservations")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G("Show network address reservations"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkReservationShow) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdNetworkReservationShowUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// Show the network address reservation.
+	reservation, _, err := d.GetNetworkReservation(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&reservation, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Create.
+type cmdNetworkReservationCreate struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+
+	flagOwner       string
+	flagDescription string
+}
+
+var cmdNetworkReservationCreateUsage = u.Usage{u.Network.Remote(), u.Address}
+
+func (c *cmdNetworkReservationCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdNetworkReservationCreateUsage...)
+	cmd.Aliases = []string{"add"}
+	cmd.Short = i18n.G("Reserve network addresses")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Reserve network addresses
+
+Reserved addresses are never handed out dynamically. Only instances whose
+name matches the owner of the reservation may use them as static addresses.
+Instances of another project than the network's are referred to as
+<project>/<instance>.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus network reservation create incusbr0 10.0.0.10 --owner=db01
+    Reserve 10.0.0.10 on network "incusbr0" for instance "db01"
+
+incus network reservation create incusbr0 10.0.0.100-10.0.0.150 --description="Future use"
+    Keep a range of addresses on network "incusbr0" out of dynamic allocation`))
+
+	cmd.RunE = c.run
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagOwner, "owner", "", "", i18n.G("Reservation owner"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Reservation description"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkReservationCreate) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdNetworkReservationCreateUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// If stdin isn't a terminal, read yaml from it.
+	var reservationPut api.NetworkReservationPut
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin, yaml.WithKnownFields())
+		if err != nil {
+			return err
+		}
+
+		err = loader.Load(&reservationPut)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+
+	if c.flagOwner != "" {
+		reservationPut.Owner = c.flagOwner
+	}
+
+	if c.flagDescription != "" {
+		reservationPut.Description = c.flagDescription
+	}
+
+	// Create the network address reservation.
+	reservation := api.NetworkReservationsPost{
+		Address:               address,
+		NetworkReservationPut: reservationPut,
+	}
+
+	err = d.CreateNetworkReservation(networkName, reservation)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network address reservation %s created")+"\n", reservation.Address)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdNetworkReservationEdit struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+}
+
+var cmdNetworkReservationEditUsage = u.Usage{u.Network.Remote(), u.Address}
+
+func (c *cmdNetworkReservationEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdNetworkReservationEditUsage...)
+	cmd.Short = i18n.G("Edit network address reservations as YAML")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G("Edit network address reservations as YAML"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkReservationEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the network address reservation.
+### Any line starting with a '# will be ignored.
+###
+### An example would look like:
+### address: 10.0.0.100-10.0.0.150
+### owner: db01
+### description: Addresses for the database cluster
+###
+### Note that the address cannot be changed.`,
+	)
+}
+
+func (c *cmdNetworkReservationEdit) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdNetworkReservationEditUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin, yaml.WithKnownFields())
+		if err != nil {
+			return err
+		}
+
+		// Allow output of `incus network reservation show` command to be passed in here, but only take the
+		// contents of the NetworkReservationPut fields when updating. The other fields are silently discarded.
+		newData := api.NetworkReservation{}
+		err = loader.Load(&newData)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return d.UpdateNetworkReservation(networkName, address, newData.NetworkReservationPut, "")
+	}
+
+	// Get the current config.
+	reservation, etag, err := d.GetNetworkReservation(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&reservation, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newData := api.NetworkReservation{} // We show the full info, but only send the writable fields.
+		err = yaml.Load(content, &newData, yaml.WithKnownFields())
+		if err == nil {
+			err = d.UpdateNetworkReservation(networkName, address, newData.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdNetworkReservationDelete struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+}
+
+var cmdNetworkReservationDeleteUsage = u.Usage{u.Network.Remote(), u.Address}
+
+func (c *cmdNetworkReservationDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("delete", cmdNetworkReservationDeleteUsage...)
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Release network address reservations")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G("Release network address reservations"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkReservationDelete) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdNetworkReservationDeleteUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// Delete the network address reservation.
+	err = d.DeleteNetworkReservation(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network address reservation %s deleted")+"\n", address)
+	}
+
+	return nil
+}
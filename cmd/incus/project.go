@@ -1048,6 +1048,7 @@ func (c *cmdProjectInfo) run(cmd *cobra.Command, args []string) error {
 
 	// Render the output
 	byteLimits := []string{"disk", "memory"}
+	bitLimits := []string{"network"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		shortKey, _, _ := strings.Cut(k, ".")
@@ -1056,6 +1057,8 @@ func (c *cmdProjectInfo) run(cmd *cobra.Command, args []string) error {
 		if v.Limit >= 0 {
 			if slices.Contains(byteLimits, shortKey) {
 				limit = units.GetByteSizeStringIEC(v.Limit, 2)
+			} else if slices.Contains(bitLimits, shortKey) {
+				limit = units.GetBitSizeString(v.Limit, 2)
 			} else {
 				limit = fmt.Sprintf("%d", v.Limit)
 			}
@@ -1064,6 +1067,8 @@ func (c *cmdProjectInfo) run(cmd *cobra.Command, args []string) error {
 		usage := ""
 		if slices.Contains(byteLimits, shortKey) {
 			usage = units.GetByteSizeStringIEC(v.Usage, 2)
+		} else if slices.Contains(bitLimits, shortKey) {
+			usage = units.GetBitSizeString(v.Usage, 2)
 		} else {
 			usage = fmt.Sprintf("%d", v.Usage)
 		}
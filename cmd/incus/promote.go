@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+)
+
+type cmdPromote struct {
+	global *cmdGlobal
+}
+
+var cmdPromoteUsage = u.Usage{u.Instance.Remote()}
+
+func (c *cmdPromote) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("promote", cmdPromoteUsage...)
+	cmd.Short = i18n.G("Promote replicas")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Turn a replica into a regular instance which can be started.
+
+A promoted instance doesn't accept further replication from its source.`))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdPromote) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdPromoteUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	instanceName := parsed[0].RemoteObject.String
+
+	err = d.PromoteInstance(instanceName)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance %s promoted")+"\n", instanceName)
+	}
+
+	return nil
+}
@@ -608,6 +608,32 @@ func (c *cmdStorageInfo) run(cmd *cobra.Command, args []string) error {
 		poolinfo[infostring][spaceusedstring] = units.GetByteSizeStringIEC(int64(res.Space.Used), 2)
 	}
 
+	// Build up the health map
+	if res.Health != nil {
+		healthstring := i18n.G("health")
+		poolinfo[healthstring] = map[string]string{}
+		poolinfo[healthstring][i18n.G("status")] = res.Health.Status
+
+		if res.Health.Message != "" {
+			poolinfo[healthstring][i18n.G("message")] = res.Health.Message
+		}
+
+		if res.Health.Metadata != nil {
+			if c.flagBytes {
+				poolinfo[healthstring][i18n.G("total metadata space")] = strconv.FormatUint(res.Health.Metadata.Total, 10)
+				poolinfo[healthstring][i18n.G("metadata space used")] = strconv.FormatUint(res.Health.Metadata.Used, 10)
+			} else {
+				poolinfo[healthstring][i18n.G("total metadata space")] = units.GetByteSizeStringIEC(int64(res.Health.Metadata.Total), 2)
+				poolinfo[healthstring][i18n.G("metadata space used")] = units.GetByteSizeStringIEC(int64(res.Health.Metadata.Used), 2)
+			}
+		}
+
+		for _, device := range res.Health.Devices {
+			deviceKey := fmt.Sprintf(i18n.G("device %s"), device.Name)
+			poolinfo[healthstring][deviceKey] = fmt.Sprintf(i18n.G("%s (read errors: %d, write errors: %d, checksum errors: %d)"), device.Status, device.ReadErrors, device.WriteErrors, device.ChecksumErrors)
+		}
+	}
+
 	poolinfodata, err := yaml.Dump(poolinfo, yaml.WithV2Defaults())
 	if err != nil {
 		return err
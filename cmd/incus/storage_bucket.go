@@ -76,6 +76,10 @@ func (c *cmdStorageBucket) command() *cobra.Command {
 	storageBucketKeyCmd := cmdStorageBucketKey{global: c.global, storageBucket: c}
 	cmd.AddCommand(storageBucketKeyCmd.command())
 
+	// Lifecycle.
+	storageBucketLifecycleCmd := cmdStorageBucketLifecycle{global: c.global, storageBucket: c}
+	cmd.AddCommand(storageBucketLifecycleCmd.command())
+
 	// Export.
 	storageBucketExportCmd := cmdStorageBucketExport{global: c.global, storageBucket: c}
 	cmd.AddCommand(storageBucketExportCmd.command())
@@ -1283,6 +1287,211 @@ func (c *cmdStorageBucketKeyShow) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Lifecycle commands.
+type cmdStorageBucketLifecycle struct {
+	global        *cmdGlobal
+	storageBucket *cmdStorageBucket
+
+	flagTarget string
+}
+
+func (c *cmdStorageBucketLifecycle) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("lifecycle")
+	cmd.Short = i18n.G("Manage storage bucket lifecycle rules")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Manage storage bucket lifecycle rules.
+
+Lifecycle rules expire objects, noncurrent object versions and
+incomplete multipart uploads after a number of days.`))
+
+	// Edit.
+	storageBucketLifecycleEditCmd := cmdStorageBucketLifecycleEdit{global: c.global, storageBucketLifecycle: c}
+	cmd.AddCommand(storageBucketLifecycleEditCmd.command())
+
+	// Show.
+	storageBucketLifecycleShowCmd := cmdStorageBucketLifecycleShow{global: c.global, storageBucketLifecycle: c}
+	cmd.AddCommand(storageBucketLifecycleShowCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Edit Lifecycle.
+type cmdStorageBucketLifecycleEdit struct {
+	global                 *cmdGlobal
+	storageBucketLifecycle *cmdStorageBucketLifecycle
+}
+
+var cmdStorageBucketLifecycleEditUsage = u.Usage{u.Pool.Remote(), u.Bucket}
+
+func (c *cmdStorageBucketLifecycleEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdStorageBucketLifecycleEditUsage...)
+	cmd.Short = i18n.G("Edit storage bucket lifecycle rules as YAML")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Edit storage bucket lifecycle rules as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus storage bucket lifecycle edit [<remote>:]<pool> <bucket> < lifecycle.yaml
+    Replace the lifecycle rules of a storage bucket with the content of lifecycle.yaml.`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.storageBucketLifecycle.flagTarget, "target", "", "", i18n.G("Cluster member name"))
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdStorageBucketLifecycleEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the lifecycle rules of a storage bucket.
+### Any line starting with a '# will be ignored.
+###
+### Each rule applies to the objects whose key starts with its prefix.
+### Actions with a number of days of 0 are disabled.
+###
+### rules:
+### - id: expire-artifacts
+###   prefix: artifacts/
+###   enabled: true
+###   expiration_days: 30
+###   noncurrent_version_expiration_days: 7
+###   abort_incomplete_multipart_upload_days: 1`,
+	)
+}
+
+func (c *cmdStorageBucketLifecycleEdit) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdStorageBucketLifecycleEditUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	poolName := parsed[0].RemoteObject.String
+	bucketName := parsed[1].String
+
+	// If a target was specified, edit the bucket on the given member.
+	if c.storageBucketLifecycle.flagTarget != "" {
+		d = d.UseTarget(c.storageBucketLifecycle.flagTarget)
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.StorageBucketLifecycle{}
+		err = loader.Load(&newdata)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return d.UpdateStoragePoolBucketLifecycle(poolName, bucketName, newdata, "")
+	}
+
+	// Get the current config.
+	lifecycle, etag, err := d.GetStoragePoolBucketLifecycle(poolName, bucketName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&lifecycle, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.StorageBucketLifecycle{}
+		err = yaml.Load(content, &newdata)
+		if err == nil {
+			err = d.UpdateStoragePoolBucketLifecycle(poolName, bucketName, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Show Lifecycle.
+type cmdStorageBucketLifecycleShow struct {
+	global                 *cmdGlobal
+	storageBucketLifecycle *cmdStorageBucketLifecycle
+}
+
+var cmdStorageBucketLifecycleShowUsage = u.Usage{u.Pool.Remote(), u.Bucket}
+
+func (c *cmdStorageBucketLifecycleShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdStorageBucketLifecycleShowUsage...)
+	cmd.Short = i18n.G("Show storage bucket lifecycle rules")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Show storage bucket lifecycle rules`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus storage bucket lifecycle show default data
+    Will show the lifecycle rules of a bucket called "data" in the "default" pool.`,
+	))
+
+	cli.AddStringFlag(cmd.Flags(), &c.storageBucketLifecycle.flagTarget, "target", "", "", i18n.G("Cluster member name"))
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdStorageBucketLifecycleShow) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdStorageBucketLifecycleShowUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	poolName := parsed[0].RemoteObject.String
+	bucketName := parsed[1].String
+
+	// If a target member was specified, get the bucket with the matching name on that member, if any.
+	if c.storageBucketLifecycle.flagTarget != "" {
+		d = d.UseTarget(c.storageBucketLifecycle.flagTarget)
+	}
+
+	lifecycle, _, err := d.GetStoragePoolBucketLifecycle(poolName, bucketName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&lifecycle, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
 type cmdStorageBucketExport struct {
 	global        *cmdGlobal
 	storageBucket *cmdStorageBucket
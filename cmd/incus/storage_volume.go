@@ -130,10 +130,18 @@ Unless specified through a prefix, all volume operations affect "custom" (user c
 	storageVolumeNBDCmd := cmdStorageVolumeNBD{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeNBDCmd.Command())
 
+	// Promote
+	storageVolumePromoteCmd := cmdStorageVolumePromote{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumePromoteCmd.command())
+
 	// Rebuild
 	storageVolumeRebuildCmd := cmdStorageVolumeRebuild{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeRebuildCmd.command())
 
+	// Rekey
+	storageVolumeRekeyCmd := cmdStorageVolumeRekey{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeRekeyCmd.command())
+
 	// Rename
 	storageVolumeRenameCmd := cmdStorageVolumeRename{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeRenameCmd.command())
@@ -1380,6 +1388,10 @@ func (c *cmdStorageVolumeInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf(i18n.G("Created: %s")+"\n", vol.CreatedAt.Local().Format(dateLayout))
 	}
 
+	if volState != nil {
+		renderReplicationState(volState.Replication)
+	}
+
 	// List snapshots
 	firstSnapshot := true
 	if len(volSnapshots) > 0 {
@@ -1866,6 +1878,161 @@ func (c *cmdStorageVolumeRebuild) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Rekey (passphrase rotation).
+type cmdStorageVolumeRekey struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+}
+
+var cmdStorageVolumeRekeyUsage = u.Usage{u.Pool.Remote(), u.MakePath(u.StorageVolumeType.Optional(), u.Volume)}
+
+func (c *cmdStorageVolumeRekey) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("rekey", cmdStorageVolumeRekeyUsage...)
+	cmd.Short = i18n.G("Rotate the encryption passphrase of storage volumes")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Rotate the passphrase unlocking an encrypted custom or virtual-machine storage volume.
+
+The volume key encrypting the data stays the same, so existing snapshots and copies
+of the volume can still be unlocked with the passphrase they were taken with.`,
+	))
+
+	cli.AddStringFlag(cmd.Flags(), &c.storage.flagTarget, "target", "", "", i18n.G("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpStoragePools(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpStoragePoolVolumes(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageVolumeRekey) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdStorageVolumeRekeyUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	poolName := parsed[0].RemoteObject.String
+	volType := parsed[1].List[0].Get("custom")
+	volName := parsed[1].List[1].String
+
+	if volType != "custom" && volType != "virtual-machine" {
+		return errors.New(i18n.G("Only \"custom\" and \"virtual-machine\" volumes can be rekeyed"))
+	}
+
+	if c.storage.flagTarget != "" {
+		d = d.UseTarget(c.storage.flagTarget)
+	}
+
+	op, err := d.RekeyStoragePoolVolume(poolName, volType, volName)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Quiet: c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Storage volume %s rekeyed")+"\n", volName)
+	}
+
+	return nil
+}
+
+// Promote.
+type cmdStorageVolumePromote struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+}
+
+var cmdStorageVolumePromoteUsage = u.Usage{u.Pool.Remote(), u.MakePath(u.StorageVolumeType.Optional(), u.Volume)}
+
+func (c *cmdStorageVolumePromote) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("promote", cmdStorageVolumePromoteUsage...)
+	cmd.Short = i18n.G("Promote replicated storage volumes")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Turn a replicated custom storage volume into a regular volume.
+
+A promoted volume doesn't accept further replication from its source.`,
+	))
+
+	cli.AddStringFlag(cmd.Flags(), &c.storage.flagTarget, "target", "", "", i18n.G("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpStoragePools(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpStoragePoolVolumes(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageVolumePromote) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdStorageVolumePromoteUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	poolName := parsed[0].RemoteObject.String
+	volType := parsed[1].List[0].Get("custom")
+	volName := parsed[1].List[1].String
+
+	if volType != "custom" {
+		return errors.New(i18n.G("Only \"custom\" volumes can be promoted"))
+	}
+
+	if c.storage.flagTarget != "" {
+		d = d.UseTarget(c.storage.flagTarget)
+	}
+
+	err = d.PromoteStoragePoolVolume(poolName, volType, volName)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Storage volume %s promoted")+"\n", volName)
+	}
+
+	return nil
+}
+
 // Rename.
 type cmdStorageVolumeRename struct {
 	global        *cmdGlobal
@@ -2248,6 +2415,7 @@ type cmdStorageVolumeExport struct {
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
 	flagForce                bool
+	flagIncrementalFrom      string
 }
 
 var cmdStorageVolumeExportUsage = u.Usage{u.Pool.Remote(), u.Volume, u.Target(u.File).Optional()}
@@ -2263,6 +2431,7 @@ func (c *cmdStorageVolumeExport) command() *cobra.Command {
 	cli.AddStringFlag(cmd.Flags(), &c.flagCompressionAlgorithm, "compression", "", "", i18n.G("Compression algorithm to use (none for uncompressed, ignored for ISO storage volumes)"))
 	cli.AddStringFlag(cmd.Flags(), &c.storage.flagTarget, "target", "", "", i18n.G("Cluster member name"))
 	cli.AddBoolFlag(cmd.Flags(), &c.flagForce, "force|f", i18n.G("Force overwriting existing backup file"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagIncrementalFrom, "incremental-from", "", "", i18n.G("Only backup the blocks changed since the bitmap was created (block volumes only)"))
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -2324,6 +2493,7 @@ func (c *cmdStorageVolumeExport) run(cmd *cobra.Command, args []string) error {
 		VolumeOnly:           volumeOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		IncrementalFrom:      c.flagIncrementalFrom,
 	}
 
 	var getter func(backupReq *incus.BackupFileRequest) error
@@ -2454,7 +2624,9 @@ type cmdStorageVolumeImport struct {
 	storage       *cmdStorage
 	storageVolume *cmdStorageVolume
 
-	flagType string
+	flagType        string
+	flagIncremental []string
+	flagRepository  string
 }
 
 var cmdStorageVolumeImportUsage = u.Usage{u.Pool.Remote(), u.BackupFile, u.NewName(u.Volume).Optional()}
@@ -2467,11 +2639,19 @@ func (c *cmdStorageVolumeImport) command() *cobra.Command {
 	cmd.Example = cli.FormatSection("", i18n.G(`incus storage volume import default backup0.tar.gz
     Create a new custom volume using backup0.tar.gz as the source
 
+incus storage volume import default backup0.tar.gz --incremental backup1.tar.gz
+    Create a new custom volume using the full backup0.tar.gz and the incremental backup made on top of it as the source
+
+incus storage volume import default volumes/default/default/vol1/backup0 --repository repository.yaml
+    Create a new custom volume using the backup stored in the repository defined in repository.yaml as the source
+
 incus storage volume import default some-installer.iso installer --type=iso
     Create a new custom volume storing some-installer.iso for use as a CD-ROM image`))
 	cli.AddStringFlag(cmd.Flags(), &c.storage.flagTarget, "target", "", "", i18n.G("Cluster member name"))
 	cmd.RunE = c.run
 	cli.AddStringFlag(cmd.Flags(), &c.flagType, "type|t", "", "", i18n.G("Import type, backup or iso (default \"backup\")"))
+	cli.AddStringArrayFlag(cmd.Flags(), &c.flagIncremental, "incremental", i18n.G("Incremental backup to apply on top of the backup, in order (may be passed multiple times)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagRepository, "repository", "", "", i18n.G("Backup repository definition (YAML file) to restore the backup from"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -2501,24 +2681,50 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 		d = d.UseTarget(c.storage.flagTarget)
 	}
 
-	var file *os.File
+	if c.flagRepository != "" {
+		if len(c.flagIncremental) > 0 {
+			return errors.New(i18n.G("Backups restored from a repository can't have incremental backups"))
+		}
+
+		if c.flagType == "iso" {
+			return errors.New(i18n.G("ISO imports can't be restored from a repository"))
+		}
+
+		c.flagType = "backup"
+	}
+
+	var file io.ReadCloser
+	var fileSize int64
 	usePercentage := true
-	if isStdin(backupFile) {
+	if c.flagRepository != "" {
+		// The backup file is then the name of the backup in the repository.
+		usePercentage = false
+	} else if len(c.flagIncremental) > 0 {
+		file, fileSize, err = backupChainReader(append([]string{backupFile}, c.flagIncremental...))
+		if err != nil {
+			return err
+		}
+
+		defer logger.WarnOnError(file.Close, "Failed to close backup chain")
+	} else if isStdin(backupFile) {
 		file = os.Stdin
 		usePercentage = false
 	} else {
-		file, err = os.Open(backupFile)
+		f, err := os.Open(backupFile)
 		if err != nil {
 			return err
 		}
 
 		// The HTTP transport closes the request body, so only warn on unexpected errors.
-		defer logger.WarnOnErrorExcept(file.Close, []error{os.ErrClosed}, "Failed to close file")
-	}
+		defer logger.WarnOnErrorExcept(f.Close, []error{os.ErrClosed}, "Failed to close file")
 
-	fstat, err := file.Stat()
-	if err != nil {
-		return err
+		fstat, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		file = f
+		fileSize = fstat.Size()
 	}
 
 	if c.flagType == "" {
@@ -2539,6 +2745,10 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 		volName = strings.TrimSuffix(filepath.Base(backupFile), filepath.Ext(backupFile))
 	}
 
+	if c.flagType == "iso" && len(c.flagIncremental) > 0 {
+		return errors.New(i18n.G("Incremental backups can't be applied to ISO imports"))
+	}
+
 	progress := cli.ProgressRenderer{
 		Format: i18n.G("Importing custom volume: %s"),
 		Quiet:  c.global.flagQuiet,
@@ -2548,7 +2758,7 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 		BackupFile: &ioprogress.ProgressReader{
 			ReadCloser: file,
 			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
+				Length: fileSize,
 				Handler: func(v int64, speed int64) {
 					if usePercentage {
 						progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", v, units.GetByteSizeString(speed, 2))})
@@ -2558,7 +2768,18 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 				},
 			},
 		},
-		Name: volName,
+		Name:  volName,
+		Chain: len(c.flagIncremental) > 0,
+	}
+
+	if c.flagRepository != "" {
+		createArgs.Repository, err = backupRepositoryTarget(c.flagRepository)
+		if err != nil {
+			return err
+		}
+
+		createArgs.RepositoryBackup = backupFile
+		createArgs.BackupFile = nil
 	}
 
 	var op incus.Operation
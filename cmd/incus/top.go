@@ -59,6 +59,8 @@ Commas between consecutive shorthand chars are optional.
 Column shorthand chars:
   D - disk usage
   e - Project name
+  h - CPU usage history over the last hour
+  H - Memory usage history over the last hour
   m - Memory usage
   n - Instance name
   u - CPU usage (in seconds)`,
@@ -78,6 +80,9 @@ const (
 	defaultTopColumnsAllProjects = "enumD"
 )
 
+// topHistoryWidth is the number of points shown in the history columns.
+const topHistoryWidth = 20
+
 func (c *cmdTop) parseColumns() ([]topColumn, error) {
 	columnsShorthandMap := map[rune]topColumn{
 		'e': {i18n.G("PROJECT"), c.projectColumnData},
@@ -85,6 +90,8 @@ func (c *cmdTop) parseColumns() ([]topColumn, error) {
 		'u': {i18n.G("CPU TIME(s)"), c.cpuUsageColumnData},
 		'm': {i18n.G("MEMORY"), c.memoryUsageColumnData},
 		'D': {i18n.G("DISK"), c.diskUsageColumnData},
+		'h': {i18n.G("CPU HISTORY"), c.cpuHistoryColumnData},
+		'H': {i18n.G("MEMORY HISTORY"), c.memoryHistoryColumnData},
 	}
 
 	columnList := strings.Split(c.flagColumns, ",")
@@ -137,6 +144,14 @@ func (c *cmdTop) diskUsageColumnData(dd displayData) string {
 	return ""
 }
 
+func (c *cmdTop) cpuHistoryColumnData(dd displayData) string {
+	return sparkline(dd.cpuHistory, topHistoryWidth)
+}
+
+func (c *cmdTop) memoryHistoryColumnData(dd displayData) string {
+	return sparkline(dd.memoryHistory, topHistoryWidth)
+}
+
 // This function implements the `top` command. It queries the metrics API at (/1.0/metrics) and renders a list of
 // instances with their CPU, memory and disk usage columns.
 func (c *cmdTop) run(cmd *cobra.Command, args []string) error {
@@ -323,6 +338,9 @@ type displayData struct {
 	cpuUsage     float64
 	memoryUsage  float64
 	diskUsage    float64
+
+	cpuHistory    []float64
+	memoryHistory []float64
 }
 
 func sortBySortingType(data []displayData, sortingType sortType) {
@@ -381,6 +399,15 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 		return err
 	}
 
+	// Get the metrics history if shown.
+	history := map[string]map[string][]float64{}
+	if strings.ContainsAny(c.flagColumns, "hH") {
+		history, err = c.getHistory(d)
+		if err != nil {
+			return err
+		}
+	}
+
 	data := []displayData{}
 	for projectName, names := range entries {
 		for _, currentName := range names {
@@ -398,6 +425,9 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 				cpuUsage:     cpuSeconds,
 				memoryUsage:  memoryTotal - memoryFree,
 				diskUsage:    diskTotal - diskFree,
+
+				cpuHistory:    history[projectName+"/"+currentName]["cpu"],
+				memoryHistory: history[projectName+"/"+currentName]["memory"],
 			})
 		}
 	}
@@ -442,6 +472,51 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 	return nil
 }
 
+// getHistory returns the CPU and memory history of the instances over the last hour, indexed by
+// "project/instance" and then by metric.
+func (c *cmdTop) getHistory(d incus.InstanceServer) (map[string]map[string][]float64, error) {
+	servers := []incus.InstanceServer{d}
+	if c.targets != nil {
+		servers = servers[:0]
+		for _, target := range c.targets {
+			servers = append(servers, d.UseTarget(target))
+		}
+	}
+
+	end := time.Now()
+	args := incus.MetricsHistoryArgs{
+		Start:       end.Add(-time.Hour),
+		End:         end,
+		Step:        time.Hour / topHistoryWidth,
+		AllProjects: c.flagAllProjects,
+	}
+
+	history := map[string]map[string][]float64{}
+	for _, server := range servers {
+		result, err := server.GetMetricsHistory(&args)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, series := range result.Series {
+			if series.Instance == "" || (series.Metric != "cpu" && series.Metric != "memory") {
+				continue
+			}
+
+			key := series.Project + "/" + series.Instance
+			if history[key] == nil {
+				history[key] = map[string][]float64{}
+			}
+
+			for _, point := range series.Points {
+				history[key][series.Metric] = append(history[key][series.Metric], point.Value)
+			}
+		}
+	}
+
+	return history, nil
+}
+
 type sample struct {
 	labels map[string]string
 	value  float64
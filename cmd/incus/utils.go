@@ -1,6 +1,7 @@
 package main
 
 import (
+	"archive/tar"
 	"context"
 	"errors"
 	"fmt"
@@ -18,6 +19,7 @@ import (
 	"sync"
 
 	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
 	"golang.org/x/crypto/ssh"
 
 	incus "github.com/lxc/incus/v7/client"
@@ -759,3 +761,105 @@ func isStdin(p string) bool {
 func isStdout(p string) bool {
 	return slices.Contains([]string{"-", "/dev/stdout", "/dev/fd/1"}, p)
 }
+
+// backupChainReader returns a reader streaming the backup chain made of the given backup files, an uncompressed
+// tarball holding each of them in order, along with the total size of the backup files.
+func backupChainReader(paths []string) (io.ReadCloser, int64, error) {
+	var size int64
+	for _, p := range paths {
+		if isStdin(p) {
+			return nil, 0, errors.New(i18n.G("Backup chains can't be read from standard input"))
+		}
+
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		size += fi.Size()
+	}
+
+	writeFile := func(tw *tar.Writer, name string, p string) error {
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+
+		defer logger.WarnOnError(file.Close, "Failed to close file")
+
+		fi, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		err = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: fi.Size(), Mode: 0o600, ModTime: fi.ModTime()})
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tw, file)
+
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(writer)
+		for i, p := range paths {
+			err := writeFile(tw, fmt.Sprintf("%d", i), p)
+			if err != nil {
+				_ = writer.CloseWithError(err)
+				return
+			}
+		}
+
+		_ = writer.CloseWithError(tw.Close())
+	}()
+
+	return reader, size, nil
+}
+
+// backupRepositoryTarget reads the definition of the backup repository to import a backup from.
+func backupRepositoryTarget(p string) (*api.BackupTarget, error) {
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	target := api.BackupTarget{}
+
+	err = yaml.Unmarshal(content, &target)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed parsing backup repository definition: %w"), err)
+	}
+
+	target.Format = "repository"
+
+	return &target, nil
+}
+
+// sparkline renders the last width values as a line of block characters scaled to their maximum.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	peak := slices.Max(values)
+
+	var sb strings.Builder
+	for _, value := range values {
+		index := 0
+		if peak > 0 && value > 0 {
+			index = min(int(value/peak*float64(len(blocks)-1)+0.5), len(blocks)-1)
+		}
+
+		sb.WriteRune(blocks[index])
+	}
+
+	return sb.String()
+}
@@ -90,3 +90,10 @@ func (s *utilsTestSuite) TestGetServerSupportedFilters() {
 	s.Equal([]string{"foo", "user.blah=a"}, supportedFilters)
 	s.Equal([]string{"type=container", "status=running,stopped"}, unsupportedFilters)
 }
+
+func (s *utilsTestSuite) TestSparkline() {
+	s.Equal("", sparkline(nil, 10))
+	s.Equal("▁▁▁", sparkline([]float64{0, 0, 0}, 10))
+	s.Equal("▁▅█", sparkline([]float64{0, 5, 10}, 10))
+	s.Equal("▅█", sparkline([]float64{0, 5, 10}, 2))
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	clusterRequest "github.com/lxc/incus/v7/internal/server/cluster/request"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
@@ -153,6 +155,8 @@ func restServer(d *Daemon) *http.Server {
 		}
 	}
 
+	d.manifestRouter = newManifestRouter(d, api10)
+
 	for _, c := range apiInternal {
 		d.createCmd(router, "internal", c)
 	}
@@ -217,6 +221,7 @@ func metricsServer(d *Daemon) *http.Server {
 
 	d.createCmd(router, "1.0", api10Cmd)
 	d.createCmd(router, "1.0", metricsCmd)
+	d.createCmd(router, "1.0", metricsHistoryCmd)
 
 	notFoundHandler := func(w http.ResponseWriter, r *http.Request) {
 		logger.Info("Sending top level 404", logger.Ctx{"url": r.URL, "method": r.Method, "remote": r.RemoteAddr})
@@ -392,9 +397,39 @@ func serveLocalBucket(d *Daemon, w http.ResponseWriter, r *http.Request, bucket
 		return local.MigrateMinioBucket(bucketDir, bucket.Name)
 	}
 
+	// Versioning is set through the bucket configuration, which
+	// PutBucketVersioning updates.
+	srv.Versioning = localBucketVersioning(bucket.Config)
+
+	srv.OnVersioningChange = func(status local.VersioningStatus) error {
+		bucketPut := bucket.StorageBucketPut
+		bucketPut.Config = util.CloneMap(bucket.Config)
+		bucketPut.Config["versioning"] = strconv.FormatBool(status == local.VersioningEnabled)
+
+		err := pool.UpdateBucket(bucket.Project, bucket.Name, bucketPut, nil)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(bucket.Project, lifecycle.StorageBucketUpdated.Event(pool, bucket.Project, bucket.Name, nil, nil))
+
+		return nil
+	}
+
 	srv.ServeHTTP(w, r)
 }
 
+// localBucketVersioning returns the versioning state of a local bucket from its configuration.
+func localBucketVersioning(config map[string]string) local.VersioningStatus {
+	if util.IsTrue(config["versioning"]) {
+		return local.VersioningEnabled
+	} else if util.IsFalse(config["versioning"]) {
+		return local.VersioningSuspended
+	}
+
+	return local.VersioningUnversioned
+}
+
 type httpServer struct {
 	r *http.ServeMux
 	d *Daemon
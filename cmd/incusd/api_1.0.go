@@ -23,6 +23,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	scriptletLoad "github.com/lxc/incus/v7/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v7/internal/server/tracing"
 	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
@@ -43,6 +44,8 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	auditCmd,
+	backupRepositoryCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -72,6 +75,7 @@ var api10 = []APIEndpoint{
 	instanceNVRAMGUIDCmd,
 	instanceNVRAMGUIDVarCmd,
 	instancePortForwardCmd,
+	instancePromoteCmd,
 	instanceRebuildCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
@@ -85,6 +89,7 @@ var api10 = []APIEndpoint{
 	imageAliasesCmd,
 	imageCmd,
 	imagesCmd,
+	manifestCmd,
 	metadataConfigurationCmd,
 	networkCmd,
 	networkLeasesCmd,
@@ -105,6 +110,8 @@ var api10 = []APIEndpoint{
 	networkLoadBalancersCmd,
 	networkPeerCmd,
 	networkPeersCmd,
+	networkReservationCmd,
+	networkReservationsCmd,
 	networkZoneCmd,
 	networkZonesCmd,
 	networkZoneRecordCmd,
@@ -126,6 +133,7 @@ var api10 = []APIEndpoint{
 	storagePoolBucketCmd,
 	storagePoolBucketKeysCmd,
 	storagePoolBucketKeyCmd,
+	storagePoolBucketLifecycleCmd,
 	storagePoolBucketBackupsCmd,
 	storagePoolBucketBackupCmd,
 	storagePoolBucketBackupsExportCmd,
@@ -143,10 +151,13 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
 	storagePoolVolumeTypeRebuildCmd,
+	storagePoolVolumeTypeRekeyCmd,
+	storagePoolVolumeTypePromoteCmd,
 	storagePoolVolumeTypeStateCmd,
 	warningsCmd,
 	warningCmd,
 	metricsCmd,
+	metricsHistoryCmd,
 }
 
 // swagger:operation GET /1.0?public server server_get_untrusted
@@ -847,6 +858,7 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	linstorChanged := false
 	ovsChanged := false
 	syslogChanged := false
+	tracingChanged := false
 	loggingChanges := map[string]struct{}{}
 
 	for key := range clusterChanged {
@@ -876,6 +888,9 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "core.proxy_http", "core.proxy_https", "core.proxy_ignore_hosts":
 			daemonConfigSetProxy(d, clusterConf)
 
+		case "core.tracing.endpoint", "core.tracing.sampling_rate":
+			tracingChanged = true
+
 		case "images.auto_update_interval", "images.remote_cache_expiry":
 			if !s.OS.MockMode {
 				d.taskPruneImages.Reset()
@@ -1049,6 +1064,15 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			return err
 		}
 	}
+	if tracingChanged {
+		tracingEndpoint, tracingRatio := clusterConf.Tracing()
+
+		err := tracing.Setup(s.ShutdownCtx, tracingEndpoint, tracingRatio, s.ServerName)
+		if err != nil {
+			return fmt.Errorf("Failed configuring tracing: %w", err)
+		}
+	}
+
 	if oidcChanged {
 		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := clusterConf.OIDCServer()
 
@@ -64,6 +64,7 @@ var apiInternal = []APIEndpoint{
 	internalGarbageCollectorCmd,
 	internalImageOptimizeCmd,
 	internalImageRefreshCmd,
+	internalNetworkWireguardSyncCmd,
 	internalRAFTSnapshotCmd,
 	internalRebalanceLoadCmd,
 	internalReadyCmd,
@@ -968,7 +969,8 @@ func internalImportFromBackup(ctx context.Context, s *state.State, projectName s
 		return err
 	}
 
-	_, instOp, cleanup, err := instance.CreateInternal(s, *instDBArgs, nil, true, true, false)
+	// Like recovered instances, imported ones may reference instances and devices which don't exist on this server.
+	_, instOp, cleanup, err := instance.CreateInternal(s, *instDBArgs, nil, true, true, true)
 	if err != nil {
 		return fmt.Errorf("Failed creating instance record: %w", err)
 	}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/manifest"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+var manifestCmd = APIEndpoint{
+	Path: "manifest",
+
+	Post: APIEndpointAction{Handler: manifestPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation POST /1.0/manifest manifest manifest_post
+//
+//	Apply a manifest
+//
+//	Computes the changes needed to converge the server to the provided manifest
+//	and applies them, unless `dry_run` is set in which case only the plan is returned.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: manifest
+//	    description: Manifest
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ManifestPost"
+//	responses:
+//	  "200":
+//	    description: Plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ManifestPlan"
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func manifestPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ManifestPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = manifest.Normalize(&req.Manifest)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Make each change through the regular API handlers, on behalf of the caller. The context outlives the
+	// request as the changes are applied in the background.
+	client, err := incus.ConnectIncusHTTPWithContext(context.WithoutCancel(r.Context()), &incus.ConnectionArgs{SkipGetEvents: true}, &http.Client{Transport: &manifestTransport{handler: d.manifestRouter, remoteAddr: r.RemoteAddr}})
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	plan, err := manifest.NewPlan(client, req.Manifest, req.Prune)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if req.DryRun {
+		return response.SyncResponse(true, plan.Changes())
+	}
+
+	run := func(op *operations.Operation) error {
+		applied, err := plan.Apply()
+
+		// Record the changes which were actually made.
+		metaErr := op.UpdateMetadata(map[string]any{"changes": applied.Changes})
+		if metaErr != nil {
+			logger.Warn("Failed updating manifest operation metadata", logger.Ctx{"err": metaErr})
+		}
+
+		return err
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ManifestApply, nil, map[string]any{"changes": plan.Changes().Changes}, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Failed creating manifest operation: %w", err))
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// manifestTransport is an HTTP transport serving the requests of the manifest client from a local handler.
+type manifestTransport struct {
+	handler    http.Handler
+	remoteAddr string
+}
+
+// RoundTrip serves the request on behalf of the client of the manifest request and returns the recorded response.
+func (t *manifestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.RemoteAddr = t.remoteAddr
+
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+
+	return recorder.Result(), nil
+}
+
+// newManifestRouter returns a router serving the API endpoints without authenticating the requests. They carry the
+// context of the manifest request, so the access checks and the audit log apply to its caller.
+func newManifestRouter(d *Daemon, endpoints []APIEndpoint) *http.ServeMux {
+	router := http.NewServeMux()
+
+	for _, c := range endpoints {
+		uri := "/" + version.APIVersion
+		if c.Path != "" {
+			uri = uri + "/" + c.Path
+		}
+
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			protocol, _ := r.Context().Value(request.CtxProtocol).(string)
+			d.serveAPIRequest(w, r, version.APIVersion, uri, c, true, protocol)
+		}
+
+		router.HandleFunc(uri, handler)
+		if len(c.SuffixActions) > 0 {
+			router.HandleFunc(uri+"/", handler)
+		}
+	}
+
+	return router
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/state"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v7/internal/server/storage/drivers"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/logger"
 )
@@ -165,6 +166,35 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		labels := map[string]string{"pool": poolName, "driver": pool.Driver().Info().Name}
 		intMetrics.AddSamples(metrics.StoragePoolUsedBytes, metrics.Sample{Labels: labels, Value: float64(res.Space.Used)})
 		intMetrics.AddSamples(metrics.StoragePoolSizeBytes, metrics.Sample{Labels: labels, Value: float64(res.Space.Total)})
+
+		health, err := pool.GetHealth()
+		if err != nil {
+			if !errors.Is(err, storageDrivers.ErrNotSupported) {
+				logger.Warn("Failed getting storage pool health", logger.Ctx{"pool": poolName, "err": err})
+			}
+
+			continue
+		}
+
+		healthy := 0.0
+		if health.Status == api.StoragePoolHealthHealthy {
+			healthy = 1.0
+		}
+
+		intMetrics.AddSamples(metrics.StoragePoolHealthy, metrics.Sample{Labels: labels, Value: healthy})
+
+		for _, device := range health.Devices {
+			errorTypes := []string{"read", "write", "checksum"}
+			for i, count := range []uint64{device.ReadErrors, device.WriteErrors, device.ChecksumErrors} {
+				deviceLabels := map[string]string{"pool": poolName, "driver": labels["driver"], "device": device.Name, "type": errorTypes[i]}
+				intMetrics.AddSamples(metrics.StoragePoolErrorsTotal, metrics.Sample{Labels: deviceLabels, Value: float64(count)})
+			}
+		}
+
+		if health.Metadata != nil {
+			intMetrics.AddSamples(metrics.StoragePoolMetaUsedBytes, metrics.Sample{Labels: labels, Value: float64(health.Metadata.Used)})
+			intMetrics.AddSamples(metrics.StoragePoolMetaSizeBytes, metrics.Sample{Labels: labels, Value: float64(health.Metadata.Total)})
+		}
 	}
 
 	// invalidProjectFilters returns project filters which are either not in cache or have expired.
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v7/internal/server/instance/drivers"
+	"github.com/lxc/incus/v7/internal/server/metrics"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/task"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/resources"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// metricsHistory holds the history of the metrics of the local instances and host.
+var metricsHistory = metrics.NewHistory()
+
+// metricsHistorySaveInterval is the number of samples taken between saves of the history to disk.
+const metricsHistorySaveInterval = 5
+
+var metricsHistoryCmd = APIEndpoint{
+	Path: "metrics/history",
+
+	Get: APIEndpointAction{Handler: metricsHistoryGet, AccessHandler: allowMetrics, AllowUntrusted: true},
+}
+
+// swagger:operation GET /1.0/metrics/history metrics metrics_history_get
+//
+//	Get the metrics history
+//
+//	Returns the history of the instance and host metrics recorded by the cluster member.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve the history of instances from all projects
+//	    type: boolean
+//	  - in: query
+//	    name: instance
+//	    description: Only retrieve the history of this instance
+//	    type: string
+//	    x-example: c1
+//	  - in: query
+//	    name: metric
+//	    description: Only retrieve the history of this metric
+//	    type: string
+//	    x-example: cpu
+//	  - in: query
+//	    name: start
+//	    description: Start of the time range (RFC3339, defaults to an hour ago)
+//	    type: string
+//	    x-example: 2021-03-23T19:00:00-04:00
+//	  - in: query
+//	    name: end
+//	    description: End of the time range (RFC3339, defaults to now)
+//	    type: string
+//	    x-example: 2021-03-23T20:00:00-04:00
+//	  - in: query
+//	    name: step
+//	    description: Interval between points in seconds (defaults to the recorded resolution)
+//	    type: integer
+//	    x-example: 300
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    x-example: server01
+//	responses:
+//	  "200":
+//	    description: Metrics history
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/MetricsHistory"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func metricsHistoryGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	allProjects := util.IsTrue(request.QueryParam(r, "all-projects"))
+	instanceName := request.QueryParam(r, "instance")
+	metric := request.QueryParam(r, "metric")
+
+	if allProjects && instanceName != "" {
+		return response.BadRequest(errors.New("Cannot specify an instance when retrieving all projects"))
+	}
+
+	// Forward if requested or to the member running the instance.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	if instanceName != "" {
+		resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, instanceName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if resp != nil {
+			return resp
+		}
+	}
+
+	// Parse the time range.
+	end := time.Now()
+	start := end.Add(-time.Hour)
+	var step time.Duration
+
+	for key, value := range map[string]*time.Time{"start": &start, "end": &end} {
+		param := request.QueryParam(r, key)
+		if param == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %s time %q: %w", key, param, err))
+		}
+
+		*value = t
+	}
+
+	if !start.Before(end) {
+		return response.BadRequest(errors.New("The start time must be before the end time"))
+	}
+
+	stepParam := request.QueryParam(r, "step")
+	if stepParam != "" {
+		seconds, err := strconv.ParseInt(stepParam, 10, 64)
+		if err != nil || seconds < 0 {
+			return response.BadRequest(fmt.Errorf("Invalid step %q", stepParam))
+		}
+
+		step = time.Duration(seconds) * time.Second
+	}
+
+	// Get the permission checkers of the instance and host series.
+	userHasPermission := func(object auth.Object) bool { return true }
+	userHasServerPermission := func(object auth.Object) bool { return true }
+	if s.GlobalConfig.MetricsAuthentication() {
+		var err error
+
+		userHasPermission, err = s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanView, auth.ObjectTypeInstance)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusForbidden) {
+			return response.SmartError(err)
+		} else if err != nil {
+			userHasPermission, err = s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanViewMetrics, auth.ObjectTypeInstance)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
+		userHasServerPermission, err = s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanViewMetrics, auth.ObjectTypeServer)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	series := metricsHistory.Query(start, end, step, func(seriesProject string, seriesInstance string, seriesMetric string) bool {
+		if metric != "" && seriesMetric != metric {
+			return false
+		}
+
+		// Host series.
+		if seriesInstance == "" {
+			return instanceName == "" && userHasServerPermission(auth.ObjectServer())
+		}
+
+		if !allProjects && seriesProject != projectName {
+			return false
+		}
+
+		if instanceName != "" && seriesInstance != instanceName {
+			return false
+		}
+
+		return userHasPermission(auth.ObjectInstance(seriesProject, seriesInstance))
+	})
+
+	return response.SyncResponse(true, api.MetricsHistory{Location: s.ServerName, Series: series})
+}
+
+// metricsHistoryPath returns the path at which the metrics history is saved.
+func metricsHistoryPath() string {
+	return internalUtil.VarPath("metrics-history.json")
+}
+
+// metricsHistoryTask records the metrics of the local instances and host in the history.
+func metricsHistoryTask(d *Daemon) (task.Func, task.Schedule) {
+	err := metricsHistory.Load(metricsHistoryPath())
+	if err != nil {
+		logger.Warn("Failed loading metrics history", logger.Ctx{"err": err})
+	}
+
+	samples := 0
+
+	f := func(ctx context.Context) {
+		s := d.State()
+		now := time.Now()
+
+		// Record the host metrics.
+		hostValues := map[string]float64{}
+
+		cpuSeconds, err := metricsHistoryHostCPUSeconds()
+		if err != nil {
+			logger.Warn("Failed getting host CPU usage for metrics history", logger.Ctx{"err": err})
+		} else {
+			hostValues[metrics.HistoryCPU] = cpuSeconds
+		}
+
+		memory, err := resources.GetMemory()
+		if err != nil {
+			logger.Warn("Failed getting host memory usage for metrics history", logger.Ctx{"err": err})
+		} else {
+			hostValues[metrics.HistoryMemory] = float64(memory.Used)
+		}
+
+		metricsHistory.Record("", "", now, hostValues)
+
+		// Record the metrics of the running local instances.
+		var instances []instance.Instance
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instances for metrics history", logger.Ctx{"err": err})
+			return
+		}
+
+		hostInterfaces, _ := net.Interfaces()
+
+		for _, inst := range instances {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			instanceMetrics, err := inst.Metrics(hostInterfaces)
+			if err != nil {
+				if !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+					logger.Warn("Failed getting instance metrics for metrics history", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+				}
+
+				continue
+			}
+
+			metricsHistory.Record(inst.Project().Name, inst.Name(), now, metrics.HistoryValues(instanceMetrics))
+		}
+
+		// Forget about instances which have been gone for longer than the history is kept.
+		longest := metrics.HistoryTiers[len(metrics.HistoryTiers)-1]
+		metricsHistory.Expire(now.Add(-longest.Step * time.Duration(longest.Size)))
+
+		samples++
+		if samples%metricsHistorySaveInterval == 0 {
+			err := metricsHistory.Save(metricsHistoryPath())
+			if err != nil {
+				logger.Warn("Failed saving metrics history", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	return f, task.Every(metrics.HistoryTiers[0].Step)
+}
+
+// metricsHistoryHostCPUSeconds returns the total number of seconds the host CPUs spent busy.
+func metricsHistoryHostCPUSeconds() (float64, error) {
+	content, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[0] != "cpu" {
+			continue
+		}
+
+		var busy float64
+		for i, field := range fields[1:] {
+			// Skip idle and iowait as well as guest time which is already part of user time.
+			if i == 3 || i == 4 || i >= 8 {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return 0, fmt.Errorf("Failed parsing /proc/stat: %w", err)
+			}
+
+			busy += value
+		}
+
+		// Values are in USER_HZ, which is 100 on all supported architectures.
+		return busy / 100, nil
+	}
+
+	return 0, errors.New("Missing aggregated CPU line in /proc/stat")
+}
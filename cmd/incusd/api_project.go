@@ -1722,6 +1722,22 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.network.ingress)
+		// This value is the maximum value for the sum of the individual `limits.ingress` (or `limits.max`) configurations set on the NICs of the instances of the project.
+		// When set, all NICs of the project must have an ingress limit.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum aggregate ingress bandwidth of the NICs in the project
+		"limits.network.ingress": validate.Optional(validate.IsBitSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.network.egress)
+		// This value is the maximum value for the sum of the individual `limits.egress` (or `limits.max`) configurations set on the NICs of the instances of the project.
+		// When set, all NICs of the project must have an egress limit.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum aggregate egress bandwidth of the NICs in the project
+		"limits.network.egress": validate.Optional(validate.IsBitSize),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.networks)
 		//
 		// ---
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/filter"
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/task"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+var auditCmd = APIEndpoint{
+	Path: "audit",
+
+	Get: APIEndpointAction{Handler: auditGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// auditBody wraps the body of an audited request to compute its digest as it's read by the handler.
+type auditBody struct {
+	io.ReadCloser
+
+	hash hash.Hash
+	size int64
+}
+
+// newAuditBody returns a wrapper computing the digest of the given request body.
+func newAuditBody(body io.ReadCloser) *auditBody {
+	return &auditBody{ReadCloser: body, hash: sha256.New()}
+}
+
+// Read reads from the request body, adding the data to the digest.
+func (b *auditBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = b.hash.Write(p[:n])
+		b.size += int64(n)
+	}
+
+	return n, err
+}
+
+// digest returns the SHA-256 digest of the data read so far, or an empty string if nothing was read.
+func (b *auditBody) digest() string {
+	if b == nil || b.size == 0 {
+		return ""
+	}
+
+	return hex.EncodeToString(b.hash.Sum(nil))
+}
+
+// auditRequired returns whether a request must be recorded in the audit log.
+//
+// Only mutating calls to the main API are recorded. Requests coming from other cluster members are skipped as they
+// are either internal or already recorded by the member the client connected to.
+func auditRequired(apiVersion string, method string, protocol string) bool {
+	if apiVersion != "1.0" || protocol == "cluster" {
+		return false
+	}
+
+	return slices.Contains([]string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, method)
+}
+
+// auditRejected records a request rejected before reaching its handler in the audit log, along with the identity
+// it tried to authenticate as.
+func auditRejected(s *state.State, r *http.Request, apiVersion string, username string, protocol string, resp response.Response) {
+	if !auditRequired(apiVersion, r.Method, protocol) {
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), request.CtxUsername, username)
+	ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
+
+	auditRecord(s, r.WithContext(ctx), nil, resp)
+}
+
+// auditRecord records a handled request and its result in the audit log.
+// The body is nil for requests rejected before their body was read.
+func auditRecord(s *state.State, r *http.Request, body *auditBody, resp response.Response) {
+	requestor := request.CreateRequestor(r)
+	projectName := request.QueryParam(r, "project")
+
+	entityURL := url.URL{Path: r.URL.Path}
+	if projectName != "" {
+		entityURL.RawQuery = url.Values{"project": []string{projectName}}.Encode()
+	}
+
+	entry := cluster.AuditEntry{
+		Date:       time.Now().UTC(),
+		Node:       s.ServerName,
+		Username:   requestor.Username,
+		Protocol:   requestor.Protocol,
+		Address:    requestor.Address,
+		Method:     r.Method,
+		EntityURL:  entityURL.String(),
+		Project:    projectName,
+		BodyDigest: body.digest(),
+		StatusCode: resp.Code(),
+	}
+
+	if resp.Code() == http.StatusAccepted {
+		entry.Operation = resp.String()
+	} else if resp.Code() >= http.StatusBadRequest {
+		entry.Error = resp.String()
+	}
+
+	// The client may be gone by now, the entry must still be recorded.
+	ctx := context.WithoutCancel(r.Context())
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		entry.ID, err = cluster.CreateAuditEntry(ctx, tx.Tx(), entry)
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed recording audit log entry", logger.Ctx{"method": entry.Method, "url": entry.EntityURL, "err": err})
+		return
+	}
+
+	if entry.Operation == "" {
+		return
+	}
+
+	// Record the result of the background operation once it completes. Operations running on other members
+	// can't be followed and keep the accepted status.
+	op, err := operations.OperationGetInternal(entry.Operation)
+	if err != nil {
+		return
+	}
+
+	op.OnDone(func(status api.StatusCode, opErr error) {
+		statusCode, errorMessage := auditOperationResult(status, opErr)
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateAuditEntryResult(ctx, entry.ID, statusCode, errorMessage)
+		})
+		if err != nil {
+			logger.Error("Failed updating audit log entry", logger.Ctx{"id": entry.ID, "operation": entry.Operation, "err": err})
+		}
+	})
+}
+
+// auditOperationResult returns the status code and error to record for a completed operation.
+func auditOperationResult(status api.StatusCode, err error) (int, string) {
+	if err != nil {
+		resp := response.SmartError(err)
+
+		return resp.Code(), resp.String()
+	}
+
+	if status == api.Cancelled {
+		return http.StatusBadRequest, "Operation cancelled"
+	}
+
+	return http.StatusOK, ""
+}
+
+// swagger:operation GET /1.0/audit audit audit_get
+//
+//	Get the audit log
+//
+//	Returns the mutating API calls recorded in the audit log, oldest first.
+//	Long logs can be retrieved in pages using the limit and after parameters.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    x-example: requestor.username eq foo and method eq DELETE
+//	  - in: query
+//	    name: start
+//	    description: Only return entries recorded at or after this time (RFC3339)
+//	    type: string
+//	    x-example: 2021-03-23T19:00:00-04:00
+//	  - in: query
+//	    name: end
+//	    description: Only return entries recorded before this time (RFC3339)
+//	    type: string
+//	    x-example: 2021-03-23T20:00:00-04:00
+//	  - in: query
+//	    name: username
+//	    description: Only return the calls made by this user
+//	    type: string
+//	    x-example: foo
+//	  - in: query
+//	    name: entity-project
+//	    description: Only return the calls applying to this project
+//	    type: string
+//	    x-example: default
+//	  - in: query
+//	    name: after
+//	    description: Only return the entries following the one with this identifier
+//	    type: integer
+//	    x-example: 42
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return
+//	    type: integer
+//	    x-example: 100
+//	responses:
+//	  "200":
+//	    description: Audit log entries
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of audit log entries
+//	          items:
+//	            $ref: "#/definitions/AuditEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	clauses, err := filter.Parse(r.FormValue("filter"), filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	query := cluster.AuditEntryQuery{
+		Username: request.QueryParam(r, "username"),
+		Project:  request.QueryParam(r, "entity-project"),
+	}
+
+	for key, value := range map[string]**time.Time{"start": &query.Start, "end": &query.End} {
+		param := request.QueryParam(r, key)
+		if param == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %s time %q: %w", key, param, err))
+		}
+
+		*value = &t
+	}
+
+	afterParam := request.QueryParam(r, "after")
+	if afterParam != "" {
+		query.After, err = strconv.ParseInt(afterParam, 10, 64)
+		if err != nil || query.After < 0 {
+			return response.BadRequest(fmt.Errorf("Invalid entry identifier %q", afterParam))
+		}
+	}
+
+	limitParam := request.QueryParam(r, "limit")
+	if limitParam != "" {
+		query.Limit, err = strconv.Atoi(limitParam)
+		if err != nil || query.Limit < 0 {
+			return response.BadRequest(fmt.Errorf("Invalid limit %q", limitParam))
+		}
+	}
+
+	// Keep reading pages until enough entries match the filter, as it can only be applied once loaded.
+	var entries []api.AuditEntry
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		pageQuery := query
+		entries = []api.AuditEntry{}
+
+		for {
+			dbEntries, err := cluster.GetAuditEntriesPage(ctx, tx.Tx(), pageQuery)
+			if err != nil {
+				return err
+			}
+
+			for _, dbEntry := range dbEntries {
+				entry := dbEntry.ToAPI()
+
+				match, err := filter.Match(entry, *clauses)
+				if err != nil {
+					return err
+				}
+
+				if !match {
+					continue
+				}
+
+				entries = append(entries, entry)
+				if query.Limit > 0 && len(entries) == query.Limit {
+					return nil
+				}
+			}
+
+			if query.Limit == 0 || len(dbEntries) < pageQuery.Limit {
+				return nil
+			}
+
+			pageQuery.After = dbEntries[len(dbEntries)-1].ID
+			pageQuery.Limit = query.Limit - len(entries)
+		}
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, entries)
+}
+
+// pruneExpiredAuditEntriesTask deletes the audit log entries older than the configured retention.
+func pruneExpiredAuditEntriesTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		retention := s.GlobalConfig.AuditRetentionDays()
+		if retention == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return pruneExpiredAuditEntries(ctx, s, time.Now().AddDate(0, 0, -int(retention)))
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.AuditPrune, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating prune expired audit log entries operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Pruning expired audit log entries")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting prune expired audit log entries operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed pruning expired audit log entries", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done pruning expired audit log entries")
+	}
+
+	return f, task.Daily()
+}
+
+// pruneExpiredAuditEntries deletes the audit log entries recorded before the given date.
+func pruneExpiredAuditEntries(ctx context.Context, s *state.State, before time.Time) error {
+	var count int64
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		count, err = tx.DeleteAuditEntriesBefore(ctx, before.UTC())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Pruned expired audit log entries", logger.Ctx{"count": count})
+
+	return nil
+}
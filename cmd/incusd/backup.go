@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
 	"time"
 
 	"go.yaml.in/yaml/v4"
 
+	internalInstance "github.com/lxc/incus/v7/internal/instance"
 	"github.com/lxc/incus/v7/internal/instancewriter"
 	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/db"
@@ -19,6 +22,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/instance"
 	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/nbd"
 	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
 	"github.com/lxc/incus/v7/internal/server/state"
@@ -55,6 +59,21 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		args.OptimizedStorage = false
 	}
 
+	// Incremental backups only hold the changed blocks of the root disk.
+	if args.IncrementalFrom != "" {
+		if sourceInst.Type() != instancetype.VM {
+			return errors.New("Incremental backups are only supported for virtual machines")
+		}
+
+		if !sourceInst.IsRunning() {
+			return errors.New("Incremental backups require the instance to be running")
+		}
+
+		args.InstanceOnly = true
+		args.RootOnly = true
+		args.OptimizedStorage = false
+	}
+
 	var b *backup.InstanceBackup
 
 	if args.Name == "" {
@@ -195,7 +214,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), !b.RootOnly(), tarWriter)
+	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), !b.RootOnly(), args.IncrementalFrom, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -207,11 +226,37 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), !b.RootOnly(), nil)
+	var backupWriter instancewriter.InstanceWriter = tarWriter
+	var deltaWriter *backup.DeltaWriter
+
+	if args.IncrementalFrom != "" {
+		conn, disconnect, err := pool.GetInstanceNBD(sourceInst, false)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to instance disk: %w", err)
+		}
+
+		defer disconnect()
+
+		var cleanup func()
+		deltaWriter, cleanup, err = backupDeltaWriter(conn, tarWriter, backup.TypeVM, args.IncrementalFrom)
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+
+		backupWriter = deltaWriter
+	}
+
+	err = pool.BackupInstance(sourceInst, backupWriter, b.OptimizedStorage(), !b.InstanceOnly(), !b.RootOnly(), nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
 
+	if deltaWriter != nil && !deltaWriter.Written() {
+		return errors.New("Backup create: Instance disk wasn't found in the backup")
+	}
+
 	// Close off the tarball file.
 	err = tarWriter.Close()
 	if err != nil {
@@ -241,7 +286,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 }
 
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, dependentVolumes bool, tarWriter *instancewriter.InstanceTarWriter) error {
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, dependentVolumes bool, incrementalFrom string, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -276,6 +321,20 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Config:           config,
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	if sourceInst.Type() == instancetype.VM {
+		rootDiskName, _, err := internalInstance.GetRootDiskDevice(sourceInst.ExpandedDevices().CloneNative())
+		if err != nil {
+			return fmt.Errorf("Failed getting instance root disk: %w", err)
+		}
+
+		indexInfo.Bitmaps = backupDiskBitmaps(sourceInst, rootDiskName)
+	}
+
+	if incrementalFrom != "" {
+		indexInfo.Incremental = &backup.Incremental{Bitmap: incrementalFrom}
 	}
 
 	if snapshots {
@@ -309,6 +368,80 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 	return nil
 }
 
+// backupDiskBitmaps returns the names of the dirty bitmaps tracking changes to the instance disk. Those are only
+// available while the instance is running, failing to retrieve them doesn't prevent the backup from being made
+// but it can't then be used as the parent of an incremental backup.
+func backupDiskBitmaps(inst instance.Instance, deviceName string) []string {
+	if !inst.IsRunning() {
+		return nil
+	}
+
+	bitmaps, err := inst.GetBitmaps(deviceName)
+	if err != nil {
+		logger.Warn("Failed fetching disk bitmaps for backup", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "device": deviceName, "err": err})
+		return nil
+	}
+
+	names := []string{}
+	for _, bitmap := range bitmaps {
+		if !bitmap.Recording || bitmap.Inconsistent {
+			continue
+		}
+
+		names = append(names, bitmap.Name)
+	}
+
+	return names
+}
+
+// backupDeltaWriter sets up a writer replacing the disk exported over the NBD connection with the blocks changed
+// since the bitmap was created. The connection must come from a read-only export, which exposes a frozen view of the
+// disk along with its bitmaps so that the changed blocks are read as they were when the backup started.
+func backupDeltaWriter(conn net.Conn, writer instancewriter.InstanceWriter, backupType backup.Type, bitmap string) (*backup.DeltaWriter, func(), error) {
+	client, err := nbd.Connect(conn, "", []string{nbd.DirtyBitmapContext(bitmap)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := func() { _ = client.Close() }
+
+	extents, err := backup.ChangedExtents(client, bitmap)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	deltaWriter, err := backup.NewDeltaWriter(writer, backupType, client.Size(), extents, client)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return deltaWriter, cleanup, nil
+}
+
+// backupRebuildChain rebuilds the full backup matching the last backup of the uploaded backup chain.
+func backupRebuildChain(s *state.State, chainFile *os.File) (*os.File, error) {
+	_, err := chainFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	rebuiltFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_rebuild_", backup.WorkingDirPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	err = backup.RebuildChain(chainFile, s.OS, internalUtil.VarPath("backups"), rebuiltFile)
+	if err != nil {
+		_ = rebuiltFile.Close()
+		_ = os.Remove(rebuiltFile.Name())
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Failed rebuilding backup chain: %w", err)
+	}
+
+	return rebuiltFile, nil
+}
+
 func pruneExpiredBackupsTask(d *Daemon) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
 		s := d.State()
@@ -435,6 +568,16 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		args.OptimizedStorage = false
 	}
 
+	// Incremental backups only hold the changed blocks of the volume.
+	if args.IncrementalFrom != "" {
+		if contentType != drivers.ContentTypeBlock {
+			return errors.New("Incremental backups are only supported for block volumes")
+		}
+
+		args.VolumeOnly = true
+		args.OptimizedStorage = false
+	}
+
 	var backupRow db.StoragePoolVolumeBackup
 
 	if args.Name == "" {
@@ -544,7 +687,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 
 		// Write index file.
 		l.Debug("Adding backup index file")
-		err = volumeBackupWriteIndex(projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, tarWriter)
+		err = volumeBackupWriteIndex(s, projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, args.IncrementalFrom, tarWriter)
 
 		// Check compression errors.
 		if compressErr != nil {
@@ -556,11 +699,37 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 			return fmt.Errorf("Error writing backup index file: %w", err)
 		}
 
-		err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, backup.DefaultBackupPrefix, backupRow.OptimizedStorage, !backupRow.VolumeOnly, nil)
+		var backupWriter instancewriter.InstanceWriter = tarWriter
+		var deltaWriter *backup.DeltaWriter
+
+		if args.IncrementalFrom != "" {
+			conn, disconnect, err := pool.GetCustomVolumeNBD(projectName, volumeName, false)
+			if err != nil {
+				return fmt.Errorf("Failed connecting to volume: %w", err)
+			}
+
+			defer disconnect()
+
+			var cleanup func()
+			deltaWriter, cleanup, err = backupDeltaWriter(conn, tarWriter, backup.TypeCustom, args.IncrementalFrom)
+			if err != nil {
+				return err
+			}
+
+			defer cleanup()
+
+			backupWriter = deltaWriter
+		}
+
+		err = pool.BackupCustomVolume(projectName, volumeName, backupWriter, backup.DefaultBackupPrefix, backupRow.OptimizedStorage, !backupRow.VolumeOnly, nil)
 		if err != nil {
 			return fmt.Errorf("Backup create: %w", err)
 		}
 
+		if deltaWriter != nil && !deltaWriter.Written() {
+			return errors.New("Backup create: Volume disk wasn't found in the backup")
+		}
+
 		// Close off the tarball file.
 		err = tarWriter.Close()
 		if err != nil {
@@ -589,7 +758,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 }
 
 // volumeBackupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func volumeBackupWriteIndex(projectName string, volumeName string, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
+func volumeBackupWriteIndex(s *state.State, projectName string, volumeName string, pool storagePools.Pool, optimized bool, snapshots bool, incrementalFrom string, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -609,6 +778,19 @@ func volumeBackupWriteIndex(projectName string, volumeName string, pool storageP
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Type:             backup.TypeCustom,
 		Config:           config,
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	// Bitmaps are tracked by the instance the volume is attached to, if any.
+	if config.Volume.ContentType == db.StoragePoolVolumeContentTypeNameBlock {
+		inst, deviceName, err := storagePools.InstanceByVolumeName(s, pool.Name(), projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+		if err == nil {
+			indexInfo.Bitmaps = backupDiskBitmaps(inst, deviceName)
+		}
+	}
+
+	if incrementalFrom != "" {
+		indexInfo.Incremental = &backup.Incremental{Bitmap: incrementalFrom}
 	}
 
 	if snapshots {
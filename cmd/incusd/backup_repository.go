@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/backup"
+	"github.com/lxc/incus/v7/internal/server/backup/repository"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/storage/s3"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// backupTargetFormatRepository is the backup target format storing backups in a deduplicating repository.
+const backupTargetFormatRepository = "repository"
+
+var backupRepositoryCmd = APIEndpoint{
+	Path: "backup-repository",
+
+	Post: APIEndpointAction{Handler: backupRepositoryPost, AccessHandler: allowAuthenticated},
+}
+
+// backupTargetIsRepository returns whether the backups sent to the target are stored in a repository.
+func backupTargetIsRepository(target *api.BackupTarget) (bool, error) {
+	if target == nil {
+		return false, nil
+	}
+
+	switch target.Format {
+	case "", "tarball":
+		return false, nil
+	case backupTargetFormatRepository:
+		return true, nil
+	}
+
+	return false, fmt.Errorf("Unsupported backup target format %q", target.Format)
+}
+
+// backupRepositoryName returns the name of a backup stored in a repository.
+// The backups of an entity are grouped under the entity path, for example `instances/<project>/<instance>`.
+func backupRepositoryName(name string, entityPath ...string) string {
+	return path.Join(append(entityPath, name)...)
+}
+
+// backupRepositoryOpen returns the backup repository the target points at.
+// Repositories are either kept in an S3 bucket or in a directory of the server's backups directory.
+func backupRepositoryOpen(target *api.BackupTarget) (*repository.Repository, error) {
+	if target.Format != backupTargetFormatRepository {
+		return nil, fmt.Errorf("Backup target format must be %q", backupTargetFormatRepository)
+	}
+
+	switch target.Protocol {
+	case "s3":
+		client, err := backup.TargetClient(target)
+		if err != nil {
+			return nil, err
+		}
+
+		return repository.New(s3.NewObjectStore(client, target.BucketName, strings.Trim(target.Path, "/"))), nil
+	case "dir":
+		name := target.Path
+		if name == "" {
+			name = "default"
+		}
+
+		if strings.Contains(name, "/") || name == "." || name == ".." {
+			return nil, fmt.Errorf("Invalid backup repository name %q", name)
+		}
+
+		return repository.New(repository.NewDirStore(internalUtil.VarPath("backups", "repositories", name))), nil
+	}
+
+	return nil, fmt.Errorf("Unsupported backup repository protocol %q", target.Protocol)
+}
+
+// backupRepositoryCheckAccess checks that the requestor may use the backup repository.
+// Repositories kept on the server hold the backups of all projects so they're restricted to server
+// administrators, while access to those kept in a bucket is granted by the bucket credentials.
+func backupRepositoryCheckAccess(s *state.State, r *http.Request, target *api.BackupTarget) error {
+	if target.Protocol != "dir" {
+		return nil
+	}
+
+	return s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanEdit)
+}
+
+// backupRepositoryWrite stores the backup written by the create function in the repository of the target
+// under the given name and waits for it to be complete.
+func backupRepositoryWrite(ctx context.Context, target *api.BackupTarget, name string, create func(writer *io.PipeWriter) error) error {
+	repo, err := backupRepositoryOpen(target)
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+
+	writeRes := make(chan error, 1)
+	go func() {
+		_, err := repo.Write(ctx, name, reader)
+
+		// Make the backup creation fail too.
+		_ = reader.CloseWithError(err)
+		writeRes <- err
+	}()
+
+	// Closing the writer with an error makes the repository discard the incomplete backup.
+	err = create(writer)
+	_ = writer.CloseWithError(err)
+
+	writeErr := <-writeRes
+
+	if err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+
+	if writeErr != nil {
+		return fmt.Errorf("Failed storing backup %q in repository: %w", name, writeErr)
+	}
+
+	return err
+}
+
+// backupRepositoryPrune applies the retention to the scheduled backups stored in the repository of the target
+// under the prefix and then removes the data no longer used by any backup.
+func backupRepositoryPrune(ctx context.Context, target *api.BackupTarget, prefix string, retention backup.Retention) error {
+	if retention.IsEmpty() {
+		return nil
+	}
+
+	repo, err := backupRepositoryOpen(target)
+	if err != nil {
+		return err
+	}
+
+	backups, err := repo.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	scheduled := map[string]time.Time{}
+	for _, b := range backups {
+		if !strings.HasPrefix(path.Base(b.Name), backupScheduledPrefix) {
+			continue
+		}
+
+		scheduled[b.Name] = b.CreatedAt
+	}
+
+	expired := retention.Expired(scheduled)
+	if len(expired) == 0 {
+		return nil
+	}
+
+	err = repo.Delete(ctx, expired...)
+	if err != nil {
+		return err
+	}
+
+	// The repository may be in use by another backup, in which case the data is removed by a later prune.
+	_, err = repo.Prune(ctx)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusConflict) {
+		return err
+	}
+
+	return nil
+}
+
+// backupRepositoryReader returns a reader for the data of a backup stored in a repository, for imports made
+// with the X-Incus-repository-backup header. The request body then holds the backup target of the repository.
+func backupRepositoryReader(s *state.State, r *http.Request, body io.Reader) (io.ReadCloser, error) {
+	target := api.BackupTarget{}
+
+	err := json.NewDecoder(body).Decode(&target)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Failed parsing backup repository: %w", err)
+	}
+
+	err = backupRepositoryCheckAccess(s, r, &target)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := backupRepositoryOpen(&target)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "%w", err)
+	}
+
+	return repo.Read(r.Context(), r.Header.Get("X-Incus-repository-backup"))
+}
+
+// swagger:operation POST /1.0/backup-repository backup-repository backup_repository_post
+//
+//	Act on a backup repository
+//
+//	Lists, verifies or prunes the backups stored in a deduplicating backup repository.
+//
+//	The `list` action returns the backups directly, while the `verify` and `prune` actions
+//	run in the background.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: repository
+//	    description: Backup repository action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupRepositoryPost"
+//	responses:
+//	  "200":
+//	    description: Backups
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backups
+//	          items:
+//	            $ref: "#/definitions/BackupRepositoryBackup"
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupRepositoryPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.BackupRepositoryPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = backupRepositoryCheckAccess(s, r, &req.Target)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	repo, err := backupRepositoryOpen(&req.Target)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	switch req.Action {
+	case "list":
+		backups, err := repo.List(r.Context(), req.Prefix)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		result := make([]api.BackupRepositoryBackup, 0, len(backups))
+		for _, b := range backups {
+			result = append(result, api.BackupRepositoryBackup{
+				Name:      b.Name,
+				CreatedAt: b.CreatedAt,
+				Size:      b.Size,
+				Chunks:    b.Chunks,
+			})
+		}
+
+		return response.SyncResponse(true, result)
+	case "verify":
+		run := func(op *operations.Operation) error {
+			return repo.Verify(context.Background(), req.Prefix)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BackupRepositoryVerify, nil, nil, run, nil, nil, r)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		return operations.OperationResponse(op)
+	case "prune":
+		run := func(op *operations.Operation) error {
+			err := repo.Delete(context.Background(), req.Delete...)
+			if err != nil {
+				return err
+			}
+
+			result, err := repo.Prune(context.Background())
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Pruned backup repository", logger.Ctx{"chunks": result.Chunks, "incomplete": result.Incomplete})
+
+			return op.UpdateMetadata(map[string]any{"removed_chunks": result.Chunks, "removed_incomplete": result.Incomplete})
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BackupRepositoryPrune, nil, nil, run, nil, nil, r)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		return operations.OperationResponse(op)
+	}
+
+	return response.BadRequest(fmt.Errorf("Unknown backup repository action %q", req.Action))
+}
@@ -0,0 +1,600 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	internalInstance "github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/internal/server/backup"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/task"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// backupScheduledPrefix is the name prefix of the backups created on schedule.
+// Retention only ever applies to backups with this prefix.
+const backupScheduledPrefix = "scheduled-"
+
+// backupScheduledName returns the name of a backup created on schedule at the given time.
+func backupScheduledName(createdAt time.Time) string {
+	return backupScheduledPrefix + createdAt.UTC().Format("20060102-150405")
+}
+
+// backupScheduledTarget returns the backup target set through the `backups.target.*` keys of the config, or nil
+// if no target is set. For tarballs, the target path points at the directory holding the backups of the given
+// entity, while repositories hold the backups of all entities.
+func backupScheduledTarget(config map[string]string, entityPath ...string) (*api.BackupTarget, error) {
+	format := config["backups.target.format"]
+
+	if config["backups.target.url"] == "" {
+		// Repositories may be kept on the server.
+		if format == backupTargetFormatRepository {
+			return &api.BackupTarget{Protocol: "dir", Path: config["backups.target.path"], Format: format}, nil
+		}
+
+		return nil, nil
+	}
+
+	if config["backups.target.bucket"] == "" {
+		return nil, errors.New("backups.target.bucket is required when backups.target.url is set")
+	}
+
+	targetPath := config["backups.target.path"]
+	if format != backupTargetFormatRepository {
+		targetPath = strings.TrimPrefix(path.Join(append([]string{targetPath}, entityPath...)...), "/")
+	}
+
+	return &api.BackupTarget{
+		Protocol:   "s3",
+		URL:        config["backups.target.url"],
+		BucketName: config["backups.target.bucket"],
+		Path:       targetPath,
+		AccessKey:  config["backups.target.access_key"],
+		SecretKey:  config["backups.target.secret_key"],
+		Format:     format,
+	}, nil
+}
+
+// backupScheduledUpload uploads the backup written by the create function to the target as the named object
+// and waits for the upload to complete.
+func backupScheduledUpload(target *api.BackupTarget, name string, create func(writer *io.PipeWriter) error) error {
+	target = &api.BackupTarget{
+		Protocol:   target.Protocol,
+		URL:        target.URL,
+		BucketName: target.BucketName,
+		Path:       path.Join(target.Path, name+".backup"),
+		AccessKey:  target.AccessKey,
+		SecretKey:  target.SecretKey,
+	}
+
+	// The backup is copied from one pipe to the other, so that the upload can be made to fail (rather than
+	// complete with a truncated backup) when the backup creation fails part way.
+	backupReader, backupWriter := io.Pipe()
+	uploadReader, uploadWriter := io.Pipe()
+
+	createRes := make(chan error, 1)
+	copyDone := make(chan struct{})
+	go func() {
+		defer close(copyDone)
+
+		_, err := io.Copy(uploadWriter, backupReader)
+		if err != nil {
+			// Make the backup creation fail too.
+			_ = backupReader.CloseWithError(err)
+		} else {
+			err = <-createRes
+		}
+
+		_ = uploadWriter.CloseWithError(err)
+	}()
+
+	uploadRes := make(chan error, 1)
+	go func() {
+		uploadRes <- backup.Upload(uploadReader, target)
+	}()
+
+	err := create(backupWriter)
+	createRes <- err
+	_ = backupWriter.CloseWithError(err)
+
+	<-copyDone
+	uploadErr := <-uploadRes
+
+	if err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+
+	if uploadErr != nil {
+		return fmt.Errorf("Failed uploading backup to %q: %w", target.Path, uploadErr)
+	}
+
+	return err
+}
+
+func autoCreateAndPruneInstanceBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var instances []instance.Instance
+
+		// Get list of instances on the local member that are due to have backups creating.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				config := db.ExpandInstanceConfig(dbInst.Config, dbInst.Profiles)
+
+				// Check if instance has backup schedule enabled.
+				schedule := config["backups.schedule"]
+				if schedule == "" {
+					return nil
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, int64(dbInst.ID)) {
+					return nil
+				}
+
+				err := project.AllowBackupCreation(tx, p.Name)
+				if err != nil {
+					return nil
+				}
+
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for backup task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				// If backup should only be taken if instance is running, check if running.
+				if util.IsFalseOrEmpty(config["backups.schedule.stopped"]) && !inst.IsRunning() {
+					return nil
+				}
+
+				logger.Debug("Scheduling auto instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoCreateAndPruneInstanceBackups(ctx, s, instances, op)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BackupCreate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled instance backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Creating scheduled instance backups")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled instance backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled instance backups", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done creating scheduled instance backups")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoCreateAndPruneInstanceBackups creates a backup of each instance and then applies the backup retention.
+// A failure on one instance doesn't prevent the others from being backed up.
+func autoCreateAndPruneInstanceBackups(ctx context.Context, s *state.State, instances []instance.Instance, op *operations.Operation) error {
+	var errs []error
+
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = autoCreateAndPruneInstanceBackup(ctx, s, inst, op)
+		if err != nil {
+			logger.Error("Failed creating scheduled instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+			errs = append(errs, fmt.Errorf("Failed backing up instance %q in project %q: %w", inst.Name(), inst.Project().Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func autoCreateAndPruneInstanceBackup(ctx context.Context, s *state.State, inst instance.Instance, op *operations.Operation) error {
+	config := inst.ExpandedConfig()
+	now := time.Now()
+	name := backupScheduledName(now)
+
+	retention, err := backup.RetentionFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	entityPath := []string{"instances", inst.Project().Name, inst.Name()}
+
+	target, err := backupScheduledTarget(config, entityPath...)
+	if err != nil {
+		return err
+	}
+
+	args := db.InstanceBackup{
+		InstanceID:   inst.ID(),
+		CreationDate: now,
+		InstanceOnly: util.IsTrue(config["backups.instance_only"]),
+	}
+
+	// Store the backup in the repository and apply the retention there.
+	if target != nil && target.Format == backupTargetFormatRepository {
+		args.CompressionAlgorithm = "none"
+
+		err = backupRepositoryWrite(ctx, target, backupRepositoryName(name, entityPath...), func(writer *io.PipeWriter) error {
+			return backupCreate(s, args, inst, op, writer)
+		})
+		if err != nil {
+			return err
+		}
+
+		return backupRepositoryPrune(ctx, target, path.Join(entityPath...)+"/", retention)
+	}
+
+	// Upload the backup to the target and apply the retention there.
+	if target != nil {
+		err = backupScheduledUpload(target, name, func(writer *io.PipeWriter) error {
+			return backupCreate(s, args, inst, op, writer)
+		})
+		if err != nil {
+			return err
+		}
+
+		return backup.PruneTarget(ctx, target, backupScheduledPrefix, retention)
+	}
+
+	// Otherwise store the backup on the server.
+	args.Name = inst.Name() + internalInstance.SnapshotDelimiter + name
+	args.ExpiryDate, err = internalInstance.GetExpiry(now, config["backups.expiry"])
+	if err != nil {
+		return err
+	}
+
+	err = backupCreate(s, args, inst, op, nil)
+	if err != nil {
+		return err
+	}
+
+	if retention.IsEmpty() {
+		return nil
+	}
+
+	backups, err := inst.Backups()
+	if err != nil {
+		return err
+	}
+
+	scheduled := map[string]time.Time{}
+	backupsByName := map[string]backup.InstanceBackup{}
+	for _, b := range backups {
+		_, backupName, _ := api.GetParentAndSnapshotName(b.Name())
+		if !strings.HasPrefix(backupName, backupScheduledPrefix) {
+			continue
+		}
+
+		scheduled[b.Name()] = b.CreationDate()
+		backupsByName[b.Name()] = b
+	}
+
+	for _, backupName := range retention.Expired(scheduled) {
+		b := backupsByName[backupName]
+
+		err = b.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting backup %q: %w", backupName, err)
+		}
+	}
+
+	return nil
+}
+
+func autoCreateAndPruneCustomVolumeBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var volumes, remoteVolumes []db.StorageVolumeArgs
+		var memberCount int
+		var onlineMemberIDs []int64
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+			}
+
+			for _, v := range allVolumes {
+				schedule := v.Config["backups.schedule"]
+				if schedule == "" {
+					continue
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				err = project.AllowBackupCreation(tx, v.ProjectName)
+				if err != nil {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Keep a separate list of remote volumes in order to select a member to
+					// perform the backup later.
+					remoteVolumes = append(remoteVolumes, v)
+				} else {
+					logger.Debug("Scheduling local auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v) // Always include local volumes.
+				}
+			}
+
+			if len(remoteVolumes) > 0 {
+				// Get list of cluster members.
+				members, err := tx.GetNodes(ctx)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster members: %w", err)
+				}
+
+				memberCount = len(members)
+
+				// Filter to online members.
+				for _, member := range members {
+					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+						continue
+					}
+
+					onlineMemberIDs = append(onlineMemberIDs, member.ID)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		localMemberID := s.DB.Cluster.GetNodeID()
+
+		// Skip backing up remote custom volumes if there are no online members, as we can't be sure that
+		// the cluster isn't partitioned and we may end up attempting the backup on multiple members.
+		if len(remoteVolumes) > 0 && memberCount > 1 && len(onlineMemberIDs) <= 0 {
+			logger.Error("Skipping remote volumes for auto custom volume backup task due to no online members")
+		} else {
+			for _, v := range remoteVolumes {
+				// If there are multiple cluster members, a stable random member is chosen to perform
+				// the backup from.
+				if memberCount > 1 {
+					selectedNodeID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
+					if err != nil {
+						logger.Error("Failed scheduling remote auto custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+						continue
+					}
+
+					// Don't back up, if we're not the chosen one.
+					if localMemberID != selectedNodeID {
+						continue
+					}
+				}
+
+				logger.Debug("Scheduling remote auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v)
+			}
+		}
+
+		if len(volumes) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoCreateAndPruneCustomVolumeBackups(ctx, s, volumes)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled volume backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Creating scheduled volume backups")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled volume backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled custom volume backups", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done creating scheduled volume backups")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoCreateAndPruneCustomVolumeBackups creates a backup of each custom volume and then applies the backup
+// retention. A failure on one volume doesn't prevent the others from being backed up.
+func autoCreateAndPruneCustomVolumeBackups(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs) error {
+	var errs []error
+
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = autoCreateAndPruneCustomVolumeBackup(ctx, s, v)
+		if err != nil {
+			logger.Error("Failed creating scheduled custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+			errs = append(errs, fmt.Errorf("Failed backing up volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func autoCreateAndPruneCustomVolumeBackup(ctx context.Context, s *state.State, v db.StorageVolumeArgs) error {
+	now := time.Now()
+	name := backupScheduledName(now)
+
+	retention, err := backup.RetentionFromConfig(v.Config)
+	if err != nil {
+		return err
+	}
+
+	entityPath := []string{"volumes", v.ProjectName, v.PoolName, v.Name}
+
+	target, err := backupScheduledTarget(v.Config, entityPath...)
+	if err != nil {
+		return err
+	}
+
+	args := db.StoragePoolVolumeBackup{
+		VolumeID:     v.ID,
+		CreationDate: now,
+		VolumeOnly:   util.IsTrue(v.Config["backups.volume_only"]),
+	}
+
+	// Store the backup in the repository and apply the retention there.
+	if target != nil && target.Format == backupTargetFormatRepository {
+		args.CompressionAlgorithm = "none"
+
+		err = backupRepositoryWrite(ctx, target, backupRepositoryName(name, entityPath...), func(writer *io.PipeWriter) error {
+			return volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, writer)
+		})
+		if err != nil {
+			return err
+		}
+
+		return backupRepositoryPrune(ctx, target, path.Join(entityPath...)+"/", retention)
+	}
+
+	// Upload the backup to the target and apply the retention there.
+	if target != nil {
+		err = backupScheduledUpload(target, name, func(writer *io.PipeWriter) error {
+			return volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, writer)
+		})
+		if err != nil {
+			return err
+		}
+
+		return backup.PruneTarget(ctx, target, backupScheduledPrefix, retention)
+	}
+
+	// Otherwise store the backup on the server.
+	args.Name = v.Name + internalInstance.SnapshotDelimiter + name
+	args.ExpiryDate, err = internalInstance.GetExpiry(now, v.Config["backups.expiry"])
+	if err != nil {
+		return err
+	}
+
+	err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, nil)
+	if err != nil {
+		return err
+	}
+
+	if retention.IsEmpty() {
+		return nil
+	}
+
+	var backups []db.StoragePoolVolumeBackup
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, v.PoolName)
+		if err != nil {
+			return err
+		}
+
+		backups, err = tx.GetStoragePoolVolumeBackups(ctx, v.ProjectName, v.Name, poolID)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	scheduled := map[string]time.Time{}
+	backupsByName := map[string]db.StoragePoolVolumeBackup{}
+	for _, b := range backups {
+		_, backupName, _ := api.GetParentAndSnapshotName(b.Name)
+		if !strings.HasPrefix(backupName, backupScheduledPrefix) {
+			continue
+		}
+
+		scheduled[b.Name] = b.CreationDate
+		backupsByName[b.Name] = b
+	}
+
+	for _, backupName := range retention.Expired(scheduled) {
+		b := backupsByName[backupName]
+
+		volBackup := backup.NewVolumeBackup(s, v.ProjectName, v.PoolName, v.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+		err = volBackup.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting backup %q: %w", backupName, err)
+		}
+	}
+
+	return nil
+}
@@ -26,6 +26,8 @@ import (
 	cowsqlClient "github.com/cowsql/go-cowsql/client"
 	"github.com/cowsql/go-cowsql/driver"
 	liblxc "github.com/lxc/go-lxc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sys/unix"
 
 	internalIO "github.com/lxc/incus/v7/internal/io"
@@ -65,10 +67,12 @@ import (
 	"github.com/lxc/incus/v7/internal/server/state"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
 	storageDrivers "github.com/lxc/incus/v7/internal/server/storage/drivers"
+	"github.com/lxc/incus/v7/internal/server/storage/keystore"
 	"github.com/lxc/incus/v7/internal/server/storage/linstor"
 	"github.com/lxc/incus/v7/internal/server/sys"
 	"github.com/lxc/incus/v7/internal/server/syslog"
 	"github.com/lxc/incus/v7/internal/server/task"
+	"github.com/lxc/incus/v7/internal/server/tracing"
 	"github.com/lxc/incus/v7/internal/server/ucred"
 	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	"github.com/lxc/incus/v7/internal/server/warnings"
@@ -120,6 +124,9 @@ type Daemon struct {
 
 	proxy func(req *http.Request) (*url.URL, error)
 
+	// Router of the API requests made by manifests on behalf of their caller.
+	manifestRouter *http.ServeMux
+
 	oidcVerifier *oidc.Verifier
 
 	// Stores last heartbeat node information to detect node changes.
@@ -686,6 +693,7 @@ func (d *Daemon) State() *state.State {
 		OVN:                    d.getOVN,
 		OVS:                    d.getOVS,
 		Linstor:                d.getLinstor,
+		Keystore:               d.getKeystore,
 		Proxy:                  d.proxy,
 		ServerCert:             d.serverCert,
 		ServerClustered:        d.serverClustered,
@@ -709,28 +717,12 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
-		// Resolve the action set for this request. Suffix actions allow a
-		// single subtree route to serve sub-paths (e.g. "/export") that would
-		// otherwise conflict with another multi-segment wildcard route. A local
-		// copy is used so concurrent requests don't race on the shared endpoint.
-		ep := c
-		for _, suffix := range c.SuffixActions {
-			if strings.HasSuffix(r.URL.Path, suffix.Name) {
-				ep.Get = suffix.Get
-				ep.Head = suffix.Head
-				ep.Put = suffix.Put
-				ep.Post = suffix.Post
-				ep.Delete = suffix.Delete
-				ep.Patch = suffix.Patch
-				break
-			}
-		}
-
 		// Block on daemon startup except for the "internal" and "os" APIs.
 		if !slices.Contains([]string{"internal", "os"}, apiVersion) {
 			select {
 			case <-d.setupChan:
 			default:
+				// Not recorded in the audit log as the database may not be available yet.
 				_ = response.Unavailable(errors.New("Daemon is starting up")).Render(w)
 				return
 			}
@@ -746,7 +738,9 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 					_ = d.oidcVerifier.WriteHeaders(w)
 				}
 
-				_ = response.Unauthorized(err).Render(w)
+				resp := response.Unauthorized(err)
+				_ = resp.Render(w)
+				auditRejected(d.State(), r, apiVersion, username, protocol, resp)
 				return
 			}
 		}
@@ -820,7 +814,9 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 			}
 
 			logger.Warn("Rejecting request from untrusted client", logger.Ctx{"ip": r.RemoteAddr})
-			_ = response.Forbidden(nil).Render(w)
+			resp := response.Forbidden(nil)
+			_ = resp.Render(w)
+			auditRejected(d.State(), r, apiVersion, username, protocol, resp)
 			return
 		}
 
@@ -839,113 +835,178 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 			localUtil.DebugJSON("API Request", captured, logger.AddContext(logCtx))
 		}
 
-		// Actually process the request
-		var resp response.Response
+		d.serveAPIRequest(w, r, apiVersion, uri, c, trusted, protocol)
+	}
 
-		// Return Unavailable Error (503) if daemon is shutting down.
-		// There are some exceptions:
-		// - internal calls, e.g. shutdown
-		// - events endpoint as this is accessed when running `shutdown`
-		// - /1.0 endpoint
-		// - /1.0/operations endpoints
-		// - GET queries
-		allowedDuringShutdown := func() bool {
-			if apiVersion == "internal" {
-				return true
-			}
+	restAPI.HandleFunc(uri, handler)
 
-			if c.Path == "" || c.Path == "events" || c.Path == "operations" || strings.HasPrefix(c.Path, "operations/") {
-				return true
-			}
+	// Endpoints with suffix actions are registered as a subtree so they can
+	// also serve their sub-paths (e.g. "/export"). The exact route above keeps
+	// the canonical (no trailing slash) URL from being redirected.
+	if len(c.SuffixActions) > 0 {
+		restAPI.HandleFunc(uri+"/", handler)
+	}
+}
 
-			if r.Method == "GET" {
-				return true
-			}
+// serveAPIRequest dispatches an authenticated request to the handler of the endpoint action matching its method,
+// recording it in the audit log and tracing it.
+func (d *Daemon) serveAPIRequest(w http.ResponseWriter, r *http.Request, apiVersion string, uri string, c APIEndpoint, trusted bool, protocol string) {
+	// Resolve the action set for this request. Suffix actions allow a
+	// single subtree route to serve sub-paths (e.g. "/export") that would
+	// otherwise conflict with another multi-segment wildcard route. A local
+	// copy is used so concurrent requests don't race on the shared endpoint.
+	ep := c
+	for _, suffix := range c.SuffixActions {
+		if strings.HasSuffix(r.URL.Path, suffix.Name) {
+			ep.Get = suffix.Get
+			ep.Head = suffix.Head
+			ep.Put = suffix.Put
+			ep.Post = suffix.Post
+			ep.Delete = suffix.Delete
+			ep.Patch = suffix.Patch
+			break
+		}
+	}
+
+	// Compute the digest of the body of mutating requests for the audit log.
+	var auditedBody *auditBody
+	if auditRequired(apiVersion, r.Method, protocol) {
+		auditedBody = newAuditBody(r.Body)
+		r.Body = auditedBody
+	}
 
-			return false
+	// Actually process the request
+	var resp response.Response
+
+	// Return Unavailable Error (503) if daemon is shutting down.
+	// There are some exceptions:
+	// - internal calls, e.g. shutdown
+	// - events endpoint as this is accessed when running `shutdown`
+	// - /1.0 endpoint
+	// - /1.0/operations endpoints
+	// - GET queries
+	allowedDuringShutdown := func() bool {
+		if apiVersion == "internal" {
+			return true
 		}
 
-		if errors.Is(d.shutdownCtx.Err(), context.Canceled) && !allowedDuringShutdown() {
-			_ = response.Unavailable(errors.New("Incus is shutting down")).Render(w)
-			return
+		if c.Path == "" || c.Path == "events" || c.Path == "operations" || strings.HasPrefix(c.Path, "operations/") {
+			return true
 		}
 
-		handleRequest := func(action APIEndpointAction) response.Response {
-			if action.Handler == nil {
-				return response.NotImplemented(nil)
-			}
+		if r.Method == "GET" {
+			return true
+		}
 
-			// All APIEndpointActions should have an access handler or should allow untrusted requests.
-			if action.AccessHandler == nil && !action.AllowUntrusted {
-				return response.InternalError(fmt.Errorf("Access handler not defined for %s %s", r.Method, r.URL.RequestURI()))
-			}
+		return false
+	}
 
-			// If the request is not trusted, only call the handler if the action allows it.
-			if !trusted && !action.AllowUntrusted {
-				return response.Forbidden(errors.New("You must be authenticated"))
-			}
+	if errors.Is(d.shutdownCtx.Err(), context.Canceled) && !allowedDuringShutdown() {
+		resp = response.Unavailable(errors.New("Incus is shutting down"))
+		_ = resp.Render(w)
 
-			// Protect against CSRF when using UI with browser that supports Fetch metadata.
-			// Deny Sec-Fetch-Site when set to cross-origin or same-site.
-			if slices.Contains([]string{"cross-site", "same-site"}, r.Header.Get("Sec-Fetch-Site")) {
-				return response.ErrorResponse(http.StatusForbidden, "Forbidden Sec-Fetch-Site header value")
-			}
+		if auditedBody != nil {
+			auditRecord(d.State(), r, auditedBody, resp)
+		}
 
-			// Call the access handler if there is one.
-			if action.AccessHandler != nil {
-				resp := action.AccessHandler(d, r)
-				if resp != response.EmptySyncResponse {
-					return resp
-				}
-			}
+		return
+	}
 
-			// Limit request body size unless the endpoint requires a large body.
-			if !action.LargeRequest {
-				r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
-			}
+	handleRequest := func(action APIEndpointAction) response.Response {
+		if action.Handler == nil {
+			return response.NotImplemented(nil)
+		}
 
-			return action.Handler(d, r)
+		// All APIEndpointActions should have an access handler or should allow untrusted requests.
+		if action.AccessHandler == nil && !action.AllowUntrusted {
+			return response.InternalError(fmt.Errorf("Access handler not defined for %s %s", r.Method, r.URL.RequestURI()))
 		}
 
-		switch r.Method {
-		case "GET":
-			resp = handleRequest(ep.Get)
-		case "HEAD":
-			resp = handleRequest(ep.Head)
-		case "PUT":
-			resp = handleRequest(ep.Put)
-		case "POST":
-			resp = handleRequest(ep.Post)
-		case "DELETE":
-			resp = handleRequest(ep.Delete)
-		case "PATCH":
-			resp = handleRequest(ep.Patch)
-		default:
-			resp = response.NotFound(fmt.Errorf("Method %q not found", r.Method))
+		// If the request is not trusted, only call the handler if the action allows it.
+		if !trusted && !action.AllowUntrusted {
+			return response.Forbidden(errors.New("You must be authenticated"))
 		}
 
-		// If sending out Forbidden, make sure we have OIDC headers.
-		if resp.Code() == http.StatusForbidden && d.oidcVerifier != nil {
-			_ = d.oidcVerifier.WriteHeaders(w)
+		// Protect against CSRF when using UI with browser that supports Fetch metadata.
+		// Deny Sec-Fetch-Site when set to cross-origin or same-site.
+		if slices.Contains([]string{"cross-site", "same-site"}, r.Header.Get("Sec-Fetch-Site")) {
+			return response.ErrorResponse(http.StatusForbidden, "Forbidden Sec-Fetch-Site header value")
 		}
 
-		// Handle errors
-		err = resp.Render(w)
-		if err != nil {
-			writeErr := response.SmartError(err).Render(w)
-			if writeErr != nil {
-				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
+		// Call the access handler if there is one.
+		if action.AccessHandler != nil {
+			resp := action.AccessHandler(d, r)
+			if resp != response.EmptySyncResponse {
+				return resp
 			}
 		}
+
+		// Limit request body size unless the endpoint requires a large body.
+		if !action.LargeRequest {
+			r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+		}
+
+		return action.Handler(d, r)
 	}
 
-	restAPI.HandleFunc(uri, handler)
+	// Trace the request, continuing the trace of the forwarding cluster member if any. The trace context sent
+	// by other clients isn't trusted.
+	ctx := r.Context()
+	if protocol == "cluster" {
+		ctx = tracing.Extract(ctx, r.Header)
+	}
 
-	// Endpoints with suffix actions are registered as a subtree so they can
-	// also serve their sub-paths (e.g. "/export"). The exact route above keeps
-	// the canonical (no trailing slash) URL from being redirected.
-	if len(c.SuffixActions) > 0 {
-		restAPI.HandleFunc(uri+"/", handler)
+	ctx, span := tracing.Start(ctx, fmt.Sprintf("%s %s", r.Method, uri),
+		attribute.String("http.request.method", r.Method),
+		attribute.String("url.path", r.URL.Path),
+		attribute.String("incus.protocol", protocol),
+		attribute.String("incus.project", request.ProjectParam(r)),
+	)
+
+	r = r.WithContext(ctx)
+
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.Code()))
+		if resp.Code() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(resp.Code()))
+		}
+
+		span.End()
+	}()
+
+	switch r.Method {
+	case "GET":
+		resp = handleRequest(ep.Get)
+	case "HEAD":
+		resp = handleRequest(ep.Head)
+	case "PUT":
+		resp = handleRequest(ep.Put)
+	case "POST":
+		resp = handleRequest(ep.Post)
+	case "DELETE":
+		resp = handleRequest(ep.Delete)
+	case "PATCH":
+		resp = handleRequest(ep.Patch)
+	default:
+		resp = response.NotFound(fmt.Errorf("Method %q not found", r.Method))
+	}
+
+	// If sending out Forbidden, make sure we have OIDC headers.
+	if resp.Code() == http.StatusForbidden && d.oidcVerifier != nil {
+		_ = d.oidcVerifier.WriteHeaders(w)
+	}
+
+	// Handle errors
+	err := resp.Render(w)
+	if err != nil {
+		writeErr := response.SmartError(err).Render(w)
+		if writeErr != nil {
+			logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
+		}
+	}
+
+	if auditedBody != nil {
+		auditRecord(d.State(), r, auditedBody, resp)
 	}
 }
 
@@ -1437,6 +1498,7 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	tracingEndpoint, tracingRatio := d.globalConfig.Tracing()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
 
@@ -1450,6 +1512,12 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Setup tracing.
+	err = tracing.Setup(d.shutdownCtx, tracingEndpoint, tracingRatio, d.serverName)
+	if err != nil {
+		logger.Warn("Failed to configure tracing", logger.Ctx{"err": err})
+	}
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1497,6 +1565,12 @@ func (d *Daemon) init() error {
 		resp := &dns.Zone{}
 		resp.Info = *zoneInfo
 
+		resp.Keys, err = zone.Keys()
+		if err != nil {
+			logger.Errorf("Failed to load DNSSEC keys of DNS zone %q: %v", name, err)
+			return nil, err
+		}
+
 		if full {
 			// Full content was requested.
 			zoneBuilder, err := zone.Content()
@@ -1679,9 +1753,57 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Take scheduled instance backups and apply their retention (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateAndPruneInstanceBackupsTask(d))
+
+		// Take scheduled custom volume backups and apply their retention (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateAndPruneCustomVolumeBackupsTask(d))
+
+		// Replicate instances on schedule (minutely check of configurable cron expression)
+		d.tasks.Add(autoReplicateInstancesTask(d))
+
+		// Replicate custom volumes on schedule (minutely check of configurable cron expression)
+		d.tasks.Add(autoReplicateCustomVolumesTask(d))
+
+		// Check the health of storage pools (every 5 minutes)
+		d.tasks.Add(storagePoolHealthTask(d))
+
+		// Scrub storage pools on schedule (minutely check of configurable cron expression)
+		d.tasks.Add(autoScrubStoragePoolsTask(d))
+
+		// Run instance health checks (every 10s check of configurable interval)
+		d.tasks.Add(instanceHealthCheckTask(d))
+
+		// Run network load balancer health checks (every 5s check of configurable interval)
+		d.tasks.Add(networkLoadBalancerHealthCheckTask(d))
+
+		// Refresh the WireGuard meshes of bridge networks (every minute)
+		d.tasks.Add(networkWireguardRefreshTask(d))
+
+		// Refresh the EVPN routes and tunnels of bridge networks (every 10s)
+		d.tasks.Add(networkEVPNRefreshTask(d))
+
+		// Export the flows of bridge networks (every minute)
+		d.tasks.Add(networkFlowExportTask(d))
+
+		// Record the metrics history (every minute)
+		d.tasks.Add(metricsHistoryTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
+		// Remove expired audit log entries (daily)
+		d.tasks.Add(pruneExpiredAuditEntriesTask(d))
+
+		// Apply the lifecycle rules of local storage buckets (daily)
+		d.tasks.Add(applyBucketLifecycleTask(d))
+
+		// Roll over the DNSSEC keys of network zones (hourly)
+		d.tasks.Add(rolloverNetworkZoneKeysTask(d))
+
+		// Push the records of network zones to external DNS servers (every minute)
+		d.tasks.Add(pushNetworkZoneRecordsTask(d))
+
 		// Auto-renew server certificate (daily)
 		d.tasks.Add(autoRenewCertificateTask(d))
 
@@ -1800,6 +1922,11 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 		d.loggingController.Shutdown()
 	}
 
+	tracingErr := tracing.Shutdown(ctx)
+	if tracingErr != nil {
+		logger.Warn("Failed to stop tracing", logger.Ctx{"err": tracingErr})
+	}
+
 	if d.gateway != nil {
 		d.stopClusterTasks()
 
@@ -2802,6 +2929,19 @@ func (d *Daemon) getLinstor() (*linstor.Client, error) {
 	return d.linstor, nil
 }
 
+// getKeystore returns the store holding the keys of encrypted storage volumes.
+func (d *Daemon) getKeystore() (keystore.Store, error) {
+	d.globalConfigMu.Lock()
+	storeURL, caCert := d.globalConfig.StorageKeystore()
+	d.globalConfigMu.Unlock()
+
+	if storeURL != "" {
+		return keystore.NewHTTP(storeURL, caCert, d.endpoints.NetworkCert())
+	}
+
+	return keystore.NewLocal(internalUtil.VarPath("keystore")), nil
+}
+
 // clusterSyncCertificate retrieves the cluster certificate from the leader and applies it
 // locally if it's a newer certificate for our existing private key. This catches up members
 // which were offline during a cluster certificate renewal.
@@ -315,6 +315,55 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	isRepository, err := backupTargetIsRepository(req.Target)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Backups stored in a repository are kept under the name of the instance.
+	if isRepository {
+		err = backupRepositoryCheckAccess(s, r, req.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if req.Name == "" {
+			req.Name = "backup-" + time.Now().UTC().Format("20060102-150405")
+		}
+
+		if strings.Contains(req.Name, "/") {
+			return response.BadRequest(errors.New("Backup names may not contain slashes"))
+		}
+
+		repoName := backupRepositoryName(req.Name, "instances", projectName, name)
+
+		backup := func(op *operations.Operation) error {
+			// Compression would defeat the deduplication, the repository compresses the data itself.
+			args := db.InstanceBackup{
+				InstanceID:           inst.ID(),
+				CreationDate:         time.Now(),
+				InstanceOnly:         req.InstanceOnly,
+				RootOnly:             req.RootOnly,
+				CompressionAlgorithm: "none",
+				IncrementalFrom:      req.IncrementalFrom,
+			}
+
+			return backupRepositoryWrite(context.Background(), req.Target, repoName, func(writer *io.PipeWriter) error {
+				return backupCreate(s, args, inst, op, writer)
+			})
+		}
+
+		resources := map[string][]api.URL{}
+		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
+
+		op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupCreate, resources, nil, backup, nil, nil, r)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		return operations.OperationResponse(op)
+	}
+
 	var reader *io.PipeReader
 	var writer *io.PipeWriter
 	var fullName string
@@ -379,6 +428,7 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			RootOnly:             req.RootOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			IncrementalFrom:      req.IncrementalFrom,
 		}
 
 		if !direct && req.Target == nil {
@@ -2,7 +2,9 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	internalInstance "github.com/lxc/incus/v7/internal/instance"
 	"github.com/lxc/incus/v7/internal/server/db/operationtype"
@@ -84,6 +86,16 @@ func instanceDelete(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Instance is running"))
 	}
 
+	// Don't leave the instances depending on this one with a missing dependency.
+	dependents, err := instance.DependentInstances(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(dependents) > 0 {
+		return response.BadRequest(fmt.Errorf("Instance is listed in boot.depends_on of %s", strings.Join(dependents, ", ")))
+	}
+
 	run := func(op *operations.Operation) error {
 		inst.SetOperation(op)
 		return inst.Delete(false, true)
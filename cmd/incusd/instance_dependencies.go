@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	incus "github.com/lxc/incus/v7/client"
+	internalInstance "github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/internal/server/cluster"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/probe"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// instanceReadyDefaultTimeout is the default timeout (in seconds) to wait for a dependency to become ready.
+const instanceReadyDefaultTimeout = 120
+
+// instanceProbeTimeout is the timeout of a single readiness probe attempt.
+const instanceProbeTimeout = 5 * time.Second
+
+// instanceStartDependencies starts the stopped dependencies of the instance (recursively) and waits for all of
+// them to be ready.
+func instanceStartDependencies(s *state.State, inst instance.Instance) error {
+	visited := map[string]bool{inst.Name(): true}
+
+	return instanceStartDependenciesOf(s, inst.Project().Name, internalInstance.DependsOn(inst.ExpandedConfig()), visited)
+}
+
+// instanceStartDependenciesOf starts the named dependencies (and their own dependencies) and waits for them to
+// be ready. The visited map records the instances already handled so shared dependencies are only started once.
+func instanceStartDependenciesOf(s *state.State, projectName string, deps []string, visited map[string]bool) error {
+	for _, name := range deps {
+		if visited[name] {
+			continue
+		}
+
+		visited[name] = true
+
+		client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, nil)
+		if err != nil {
+			return fmt.Errorf("Failed loading dependency %q: %w", name, err)
+		}
+
+		if client != nil {
+			// The cluster member running the dependency handles its own dependencies.
+			err = instanceStartRemoteDependency(client, name)
+			if err != nil {
+				return err
+			}
+		} else {
+			dep, err := instance.LoadByProjectAndName(s, projectName, name)
+			if err != nil {
+				return fmt.Errorf("Failed loading dependency %q: %w", name, err)
+			}
+
+			if !dep.IsRunning() {
+				err = instanceStartDependenciesOf(s, projectName, internalInstance.DependsOn(dep.ExpandedConfig()), visited)
+				if err != nil {
+					return err
+				}
+
+				logger.Info("Starting instance dependency", logger.Ctx{"project": projectName, "instance": name})
+
+				err = dep.Start(false)
+				if err != nil && !dep.IsRunning() {
+					return fmt.Errorf("Failed starting dependency %q: %w", name, err)
+				}
+			}
+		}
+
+		err = instanceWaitReady(s, projectName, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instanceStartRemoteDependency starts a dependency running on another cluster member if it isn't running yet.
+func instanceStartRemoteDependency(client incus.InstanceServer, name string) error {
+	instState, _, err := client.GetInstanceState(name)
+	if err != nil {
+		return fmt.Errorf("Failed getting state of dependency %q: %w", name, err)
+	}
+
+	if instState.StatusCode == api.Running || instState.StatusCode == api.Ready {
+		return nil
+	}
+
+	op, err := client.UpdateInstanceState(name, api.InstanceStatePut{Action: "start", Timeout: -1}, "")
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed starting dependency %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// instanceWaitReady waits for the instance to be running and to pass its readiness probe.
+func instanceWaitReady(s *state.State, projectName string, name string) error {
+	timeout := instanceReadyDefaultTimeout
+
+	// The first check also retrieves the readiness settings of the instance.
+	config, err := instanceCheckReady(s.ShutdownCtx, s, projectName, name)
+	if err == nil {
+		return nil
+	}
+
+	if config != nil && config["boot.ready.timeout"] != "" {
+		timeout, _ = strconv.Atoi(config["boot.ready.timeout"])
+	}
+
+	logger.Info("Waiting for instance dependency to be ready", logger.Ctx{"project": projectName, "instance": name, "timeout": timeout})
+
+	ctx, cancel := context.WithTimeout(s.ShutdownCtx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Dependency %q didn't become ready: %w", name, err)
+		case <-time.After(time.Second):
+		}
+
+		_, err = instanceCheckReady(ctx, s, projectName, name)
+		if err == nil {
+			return nil
+		}
+	}
+}
+
+// instanceCheckReady checks whether the instance is running and passes its readiness probe.
+// The expanded config of the instance is returned when it could be loaded.
+func instanceCheckReady(ctx context.Context, s *state.State, projectName string, name string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, instanceProbeTimeout)
+	defer cancel()
+
+	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if client != nil {
+		return instanceCheckReadyRemote(ctx, client, name)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	config := inst.ExpandedConfig()
+	if !inst.IsRunning() {
+		return config, errors.New("Instance isn't running")
+	}
+
+	switch config["boot.ready.probe"] {
+	case "agent":
+		if util.IsFalseOrEmpty(inst.LocalConfig()["volatile.last_state.ready"]) {
+			return config, errors.New("Instance hasn't reported itself as ready")
+		}
+
+	case "tcp":
+		var instState *api.InstanceState
+		if strings.HasPrefix(config["boot.ready.address"], ":") {
+			instState, err = inst.RenderState(nil)
+			if err != nil {
+				return config, err
+			}
+		}
+
+		address, err := probe.Address(config["boot.ready.address"], instState)
+		if err != nil {
+			return config, err
+		}
+
+		return config, probe.TCP(ctx, address)
+
+	case "exec":
+		return config, probe.Exec(ctx, inst, config["boot.ready.command"])
+	}
+
+	return config, nil
+}
+
+// instanceCheckReadyRemote checks whether an instance running on another cluster member is running and passes
+// its readiness probe.
+func instanceCheckReadyRemote(ctx context.Context, client incus.InstanceServer, name string) (map[string]string, error) {
+	inst, _, err := client.GetInstanceFull(name)
+	if err != nil {
+		return nil, err
+	}
+
+	config := inst.ExpandedConfig
+	if inst.State == nil || (inst.State.StatusCode != api.Running && inst.State.StatusCode != api.Ready) {
+		return config, errors.New("Instance isn't running")
+	}
+
+	switch config["boot.ready.probe"] {
+	case "agent":
+		if inst.State.StatusCode != api.Ready {
+			return config, errors.New("Instance hasn't reported itself as ready")
+		}
+
+	case "tcp":
+		address, err := probe.Address(config["boot.ready.address"], inst.State)
+		if err != nil {
+			return config, err
+		}
+
+		return config, probe.TCP(ctx, address)
+
+	case "exec":
+		return config, probe.ExecRemote(ctx, client, name, config["boot.ready.command"])
+	}
+
+	return config, nil
+}
@@ -7,9 +7,11 @@ import (
 	"strconv"
 
 	internalInstance "github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/instance"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/shared/api"
 )
 
 // swagger:operation GET /1.0/instances/{name} instances instance_get
@@ -166,5 +168,19 @@ func instanceGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Don't disclose the backup target credentials.
+	switch inst := state.(type) {
+	case *api.Instance:
+		inst.Config = backup.HideTargetSecret(inst.Config)
+		inst.ExpandedConfig = backup.HideTargetSecret(inst.ExpandedConfig)
+	case *api.InstanceFull:
+		inst.Config = backup.HideTargetSecret(inst.Config)
+		inst.ExpandedConfig = backup.HideTargetSecret(inst.ExpandedConfig)
+		for i := range inst.Snapshots {
+			inst.Snapshots[i].Config = backup.HideTargetSecret(inst.Snapshots[i].Config)
+			inst.Snapshots[i].ExpandedConfig = backup.HideTargetSecret(inst.Snapshots[i].ExpandedConfig)
+		}
+	}
+
 	return response.SyncResponseETag(true, state, etag)
 }
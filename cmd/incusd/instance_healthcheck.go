@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/db/warningtype"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/healthcheck"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/task"
+	"github.com/lxc/incus/v7/internal/server/warnings"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// numParallelHealthChecks is the maximum number of health checks run at the same time.
+const numParallelHealthChecks = 10
+
+// healthCheckMaxRemediations is the maximum number of times the health check action is run on an instance which
+// doesn't become healthy again.
+const healthCheckMaxRemediations = 5
+
+// healthCheckRemediationBackoff returns how long to wait before running the health check action again after it
+// already ran the given number of times.
+func healthCheckRemediationBackoff(attempts int) time.Duration {
+	return time.Minute << (attempts - 1)
+}
+
+// instanceHealthCheckConfig returns the interval and number of retries configured for the health check.
+func instanceHealthCheckConfig(config map[string]string) (time.Duration, int) {
+	interval, err := strconv.Atoi(config["healthcheck.interval"])
+	if err != nil {
+		interval = 30
+	}
+
+	retries, err := strconv.Atoi(config["healthcheck.retries"])
+	if err != nil {
+		retries = 3
+	}
+
+	return time.Duration(interval) * time.Second, retries
+}
+
+func instanceHealthCheckTask(d *Daemon) (task.Func, task.Schedule) {
+	lastChecked := map[string]time.Time{}
+
+	f := func(ctx context.Context) {
+		s := d.State()
+		now := time.Now()
+
+		// Get the list of running instances on the local member which are due for a health check.
+		var instances []instance.Instance
+		seen := map[string]bool{}
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				config := db.ExpandInstanceConfig(dbInst.Config, dbInst.Profiles)
+				if config["healthcheck.type"] == "" {
+					return nil
+				}
+
+				key := project.Instance(dbInst.Project, dbInst.Name)
+				seen[key] = true
+
+				interval, _ := instanceHealthCheckConfig(config)
+				if now.Sub(lastChecked[key]) < interval {
+					return nil
+				}
+
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for health check task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				if !inst.IsRunning() {
+					delete(seen, key)
+					return nil
+				}
+
+				lastChecked[key] = now
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance health check info", logger.Ctx{"err": err})
+			return
+		}
+
+		// Forget about instances which are gone, stopped or no longer have a health check.
+		for key := range lastChecked {
+			if !seen[key] {
+				delete(lastChecked, key)
+			}
+		}
+
+		healthcheck.Prune(func(projectName string, instanceName string) bool {
+			return seen[project.Instance(projectName, instanceName)]
+		})
+
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(numParallelHealthChecks)
+
+		for _, inst := range instances {
+			group.Go(func() error {
+				instanceHealthCheck(groupCtx, s, inst)
+				return nil
+			})
+		}
+
+		_ = group.Wait()
+	}
+
+	schedule := task.Every(10 * time.Second)
+
+	return f, schedule
+}
+
+// instanceHealthCheck runs the health check of the instance, records the result and triggers the configured
+// action while the instance stays unhealthy.
+func instanceHealthCheck(ctx context.Context, s *state.State, inst instance.Instance) {
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+	config := inst.ExpandedConfig()
+	_, retries := instanceHealthCheckConfig(config)
+
+	previous := healthcheck.Get(inst.Project().Name, inst.Name())
+	current := api.InstanceStateHealth{
+		Status:        previous.Status,
+		Failures:      previous.Failures,
+		LastCheckedAt: time.Now(),
+	}
+
+	checkErr := healthcheck.Check(ctx, inst)
+	if checkErr != nil {
+		// Don't count checks interrupted by the daemon shutting down.
+		if ctx.Err() != nil {
+			return
+		}
+
+		current.Failures++
+		current.LastError = checkErr.Error()
+		if current.Failures >= retries {
+			current.Status = healthcheck.StatusUnhealthy
+		}
+	} else {
+		current.Failures = 0
+		current.Status = healthcheck.StatusHealthy
+	}
+
+	healthcheck.Set(inst.Project().Name, inst.Name(), current)
+
+	if current.Status == healthcheck.StatusHealthy {
+		if previous.Status == healthcheck.StatusHealthy {
+			return
+		}
+
+		healthcheck.ClearRemediation(inst.Project().Name, inst.Name())
+
+		// The warning is also resolved when the instance was stopped by the health check action and started
+		// again, as its health is forgotten while it's stopped.
+		err := warnings.ResolveWarningsByNodeAndProjectAndTypeAndEntity(s.DB.Cluster, s.ServerName, inst.Project().Name, warningtype.InstanceUnhealthy, dbCluster.TypeInstance, inst.ID())
+		if err != nil {
+			l.Warn("Failed resolving instance health check warning", logger.Ctx{"err": err})
+		}
+
+		if previous.Status == healthcheck.StatusUnhealthy {
+			l.Info("Instance is healthy again")
+			s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceHealthy.Event(inst, nil))
+		}
+
+		return
+	}
+
+	if current.Status != healthcheck.StatusUnhealthy {
+		return
+	}
+
+	action := config["healthcheck.action"]
+	if action == "" {
+		action = "none"
+	}
+
+	if previous.Status != healthcheck.StatusUnhealthy {
+		l.Warn("Instance is unhealthy", logger.Ctx{"err": checkErr, "failures": current.Failures, "action": action})
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarning(ctx, s.ServerName, inst.Project().Name, dbCluster.TypeInstance, inst.ID(), warningtype.InstanceUnhealthy, checkErr.Error())
+		})
+		if err != nil {
+			l.Warn("Failed recording instance health check warning", logger.Ctx{"err": err})
+		}
+
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceUnhealthy.Event(inst, map[string]any{"error": checkErr.Error(), "action": action}))
+	}
+
+	// Only act once the configured number of checks failed since the last action, backing off between actions
+	// and giving up after too many of them.
+	if action == "none" || current.Failures < retries {
+		return
+	}
+
+	remediation := healthcheck.GetRemediation(inst.Project().Name, inst.Name())
+	if remediation.Attempts >= healthCheckMaxRemediations || current.LastCheckedAt.Before(remediation.Next) {
+		return
+	}
+
+	err := instanceHealthCheckRemediate(ctx, s, inst, action)
+
+	remediation.Attempts++
+	remediation.Next = time.Now().Add(healthCheckRemediationBackoff(remediation.Attempts))
+	healthcheck.SetRemediation(inst.Project().Name, inst.Name(), remediation)
+
+	if remediation.Attempts == healthCheckMaxRemediations {
+		l.Warn("Not running the health check action again until the instance is healthy", logger.Ctx{"action": action, "attempts": remediation.Attempts})
+	}
+
+	if err != nil {
+		l.Error("Failed running instance health check action", logger.Ctx{"action": action, "attempts": remediation.Attempts, "err": err})
+		return
+	}
+
+	// Keep the instance unhealthy until a check succeeds, counting the failed checks from scratch.
+	current.Failures = 0
+	healthcheck.Set(inst.Project().Name, inst.Name(), current)
+}
+
+// instanceHealthCheckRemediate runs the health check action on the unhealthy instance.
+func instanceHealthCheckRemediate(ctx context.Context, s *state.State, inst instance.Instance, action string) error {
+	switch action {
+	case "restart":
+		return instanceHealthCheckRestart(inst)
+
+	case "stop":
+		return instanceShutdownOrForceStop(inst)
+
+	case "evacuate":
+		if !s.ServerClustered {
+			logger.Warn("Server isn't clustered, restarting unhealthy instance instead", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+			return instanceHealthCheckRestart(inst)
+		}
+
+		sourceMemberInfo, targetMemberInfo, err := evacuateClusterSelectTarget(ctx, s, inst)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				logger.Warn("No cluster member available, restarting unhealthy instance instead", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+				return instanceHealthCheckRestart(inst)
+			}
+
+			return err
+		}
+
+		return instanceHealthCheckEvacuate(ctx, s, inst, sourceMemberInfo, targetMemberInfo)
+	}
+
+	return fmt.Errorf("Unsupported health check action %q", action)
+}
+
+// instanceHealthCheckRestart restarts the instance, using the configured shutdown timeout.
+func instanceHealthCheckRestart(inst instance.Instance) error {
+	timeout, err := strconv.Atoi(inst.ExpandedConfig()["boot.host_shutdown_timeout"])
+	if err != nil {
+		timeout = instanceShutdownDefaultTimeout
+	}
+
+	err = inst.Restart(time.Duration(timeout) * time.Second)
+	if err != nil {
+		return fmt.Errorf("Failed restarting instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	return nil
+}
+
+// instanceHealthCheckEvacuate stops the instance and starts it back up on the target cluster member.
+func instanceHealthCheckEvacuate(ctx context.Context, s *state.State, inst instance.Instance, sourceMemberInfo *db.NodeInfo, targetMemberInfo *db.NodeInfo) error {
+	migrate := evacuateMigrateInstance(nil)
+
+	run := func(op *operations.Operation) error {
+		err := instanceShutdownOrForceStop(inst)
+		if err != nil {
+			return err
+		}
+
+		return migrate(ctx, s, inst, sourceMemberInfo, targetMemberInfo, "migrate", true, op)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name()).Project(inst.Project().Name)}
+
+	op, err := operations.OperationCreate(s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceMigrate, resources, nil, run, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Moving unhealthy instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "target": targetMemberInfo.Name})
+
+	err = op.Start()
+	if err != nil {
+		return err
+	}
+
+	return op.Wait(ctx)
+}
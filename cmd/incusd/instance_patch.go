@@ -10,6 +10,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v7/internal/instance"
 	"github.com/lxc/incus/v7/internal/jmap"
+	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/db/cluster"
 	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
@@ -172,6 +173,8 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 	if req.Config == nil {
 		req.Config = c.LocalConfig()
 	} else {
+		backup.KeepTargetSecret(req.Config, c.LocalConfig())
+
 		for k, v := range c.LocalConfig() {
 			_, ok := req.Config[k]
 			if !ok {
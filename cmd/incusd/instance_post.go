@@ -212,6 +212,18 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Don't leave the instances depending on this one with a missing dependency.
+	if req.Name != "" || req.Project != "" {
+		dependents, err := instance.DependentInstances(s, projectName, name)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if len(dependents) > 0 {
+			return response.BadRequest(fmt.Errorf("Instance is listed in boot.depends_on of %s", strings.Join(dependents, ", ")))
+		}
+	}
+
 	// Handle simple instance renaming.
 	if !req.Migration {
 		run := func(op *operations.Operation) error {
@@ -881,7 +893,7 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 	if targetMemberInfo != nil && inst.Location() != targetMemberInfo.Name {
 		// Get the client.
 		networkCert := s.Endpoints.NetworkCert()
-		target, err := cluster.Connect(targetMemberInfo.Address, networkCert, s.ServerCert(), nil, true)
+		target, err := cluster.ConnectWithContext(op.Context(), targetMemberInfo.Address, networkCert, s.ServerCert(), nil, true)
 		if err != nil {
 			return fmt.Errorf("Failed to connect to destination server %q: %w", targetMemberInfo.Address, err)
 		}
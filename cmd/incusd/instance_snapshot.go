@@ -13,6 +13,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v7/internal/instance"
 	"github.com/lxc/incus/v7/internal/jmap"
+	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/db/cluster"
 	"github.com/lxc/incus/v7/internal/server/db/operationtype"
@@ -208,7 +209,11 @@ func instanceSnapshotsGet(d *Daemon, r *http.Request) response.Response {
 				continue
 			}
 
-			resultMap = append(resultMap, render.(*api.InstanceSnapshot))
+			snapRender := render.(*api.InstanceSnapshot)
+			snapRender.Config = backup.HideTargetSecret(snapRender.Config)
+			snapRender.ExpandedConfig = backup.HideTargetSecret(snapRender.ExpandedConfig)
+
+			resultMap = append(resultMap, snapRender)
 		}
 	}
 
@@ -656,8 +661,12 @@ func snapshotGet(snapInst instance.Instance) response.Response {
 		return response.SmartError(err)
 	}
 
+	snapRender := render.(*api.InstanceSnapshot)
+	snapRender.Config = backup.HideTargetSecret(snapRender.Config)
+	snapRender.ExpandedConfig = backup.HideTargetSecret(snapRender.ExpandedConfig)
+
 	etag := []any{snapInst.ExpiryDate()}
-	return response.SyncResponseETag(true, render.(*api.InstanceSnapshot), etag)
+	return response.SyncResponseETag(true, snapRender, etag)
 }
 
 // swagger:operation POST /1.0/instances/{name}/snapshots/{snapshot} instances instance_snapshot_post
@@ -14,6 +14,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
 )
@@ -204,7 +205,7 @@ func instanceStatePut(d *Daemon, r *http.Request) response.Response {
 	do := func(op *operations.Operation) error {
 		inst.SetOperation(op)
 
-		return doInstanceStatePut(inst, req)
+		return doInstanceStatePut(s, inst, req)
 	}
 
 	resources := map[string][]api.URL{}
@@ -234,7 +235,7 @@ func instanceActionToOpType(action string) (operationtype.Type, error) {
 	}
 }
 
-func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error {
+func doInstanceStatePut(s *state.State, inst instance.Instance, req api.InstanceStatePut) error {
 	if req.Force {
 		// A zero timeout indicates to do a forced stop/restart.
 		req.Timeout = 0
@@ -248,6 +249,12 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 
 	switch internalInstance.InstanceAction(req.Action) {
 	case internalInstance.Start:
+		// Make sure the dependencies are running and ready.
+		err := instanceStartDependencies(s, inst)
+		if err != nil {
+			return err
+		}
+
 		return inst.Start(req.Stateful)
 	case internalInstance.Stop:
 		if req.Stateful {
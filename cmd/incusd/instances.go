@@ -16,6 +16,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	internalInstance "github.com/lxc/incus/v7/internal/instance"
 	"github.com/lxc/incus/v7/internal/server/auth"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/db/cluster"
@@ -59,6 +60,13 @@ var instanceRebuildCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: instanceRebuildPost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
 }
 
+var instancePromoteCmd = APIEndpoint{
+	Name: "instancePromote",
+	Path: "instances/{name}/promote",
+
+	Post: APIEndpointAction{Handler: instancePromotePost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 var instanceStateCmd = APIEndpoint{
 	Name: "instanceState",
 	Path: "instances/{name}/state",
@@ -302,6 +310,21 @@ func instanceStart(s *state.State, inst instance.Instance) error {
 
 	instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+	// Make sure the dependencies are running and ready.
+	err := instanceStartDependencies(s, inst)
+	if err != nil {
+		warnErr := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarning(ctx, s.ServerName, inst.Project().Name, cluster.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, fmt.Sprintf("%v", err))
+		})
+		if warnErr != nil {
+			instLogger.Warn("Failed to create instance autostart failure warning", logger.Ctx{"err": warnErr})
+		}
+
+		instLogger.Error("Failed to auto start instance dependencies", logger.Ctx{"err": err})
+
+		return nil
+	}
+
 	// Try to start the instance.
 	attempt := 0
 	for {
@@ -370,31 +393,68 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	instancesStartMu.Lock()
 	defer instancesStartMu.Unlock()
 
-	bulkInstances, sequentialInstances := bulkStartInstances(instances)
+	// Start the instances by dependency level so dependencies are started ahead of their dependents.
+	levels := instanceDependencyLevels(instances, false)
+	maxLevel := 0
+	for _, level := range levels {
+		maxLevel = max(maxLevel, level)
+	}
 
-	// Limit the number of concurrent tasks.
-	numParallel := max(runtime.NumCPU()/4, 1)
+	for level := 0; level <= maxLevel; level++ {
+		levelInstances := make([]instance.Instance, 0, len(instances))
+		for _, inst := range instances {
+			if levels[project.Instance(inst.Project().Name, inst.Name())] == level {
+				levelInstances = append(levelInstances, inst)
+			}
+		}
 
-	group := new(errgroup.Group)
-	group.SetLimit(numParallel)
+		bulkInstances, sequentialInstances := bulkStartInstances(levelInstances)
 
-	// Start instances that support bulk startup.
-	for _, inst := range bulkInstances {
-		i := inst
-		group.Go(func() error {
-			_ = instanceStart(s, i)
-			return nil
-		})
+		// Limit the number of concurrent tasks.
+		numParallel := max(runtime.NumCPU()/4, 1)
+
+		group := new(errgroup.Group)
+		group.SetLimit(numParallel)
+
+		// Start instances that support bulk startup.
+		for _, inst := range bulkInstances {
+			i := inst
+			group.Go(func() error {
+				_ = instanceStart(s, i)
+				return nil
+			})
+		}
+
+		_ = group.Wait()
+
+		// Sort based on instance boot priority.
+		sort.Sort(instanceAutostartList(sequentialInstances))
+
+		for _, inst := range sequentialInstances {
+			_ = instanceStart(s, inst)
+		}
 	}
+}
 
-	_ = group.Wait()
+// instanceDependencyLevels returns the dependency level of each instance, keyed by project and instance name.
+// Only dependencies between the provided instances are considered. When reverse is false, dependencies are at
+// a lower level than their dependents. When reverse is true, dependents are at a lower level than their dependencies.
+func instanceDependencyLevels(instances []instance.Instance, reverse bool) map[string]int {
+	graph := make(map[string][]string, len(instances))
+	for _, inst := range instances {
+		deps := internalInstance.DependsOn(inst.ExpandedConfig())
+		for i, dep := range deps {
+			deps[i] = project.Instance(inst.Project().Name, dep)
+		}
 
-	// Sort based on instance boot priority.
-	sort.Sort(instanceAutostartList(sequentialInstances))
+		graph[project.Instance(inst.Project().Name, inst.Name())] = deps
+	}
 
-	for _, inst := range sequentialInstances {
-		_ = instanceStart(s, inst)
+	if reverse {
+		graph = internalInstance.ReverseDependencies(graph)
 	}
+
+	return internalInstance.DependencyLevels(graph)
 }
 
 type instanceStopList []instance.Instance
@@ -493,6 +553,12 @@ func instancesOnDisk(s *state.State) ([]instance.Instance, error) {
 func instancesShutdown(instances []instance.Instance) {
 	sort.Sort(instanceStopList(instances))
 
+	// Stop dependent instances ahead of their dependencies, keeping the priority order within each level.
+	levels := instanceDependencyLevels(instances, true)
+	sort.SliceStable(instances, func(i, j int) bool {
+		return levels[project.Instance(instances[i].Project().Name, instances[i].Name())] < levels[project.Instance(instances[j].Project().Name, instances[j].Name())]
+	})
+
 	// Limit shutdown concurrency to number of instances or number of CPU cores (which ever is less).
 	var wg sync.WaitGroup
 	instShutdownCh := make(chan instance.Instance)
@@ -583,6 +649,7 @@ func instancesShutdown(instances []instance.Instance) {
 	}
 
 	var currentBatchPriority int
+	var currentBatchLevel int
 	for i, inst := range instances {
 		// Skip stopped instances.
 		if !inst.IsRunning() {
@@ -590,14 +657,16 @@ func instancesShutdown(instances []instance.Instance) {
 		}
 
 		priority, _ := strconv.Atoi(inst.ExpandedConfig()["boot.stop.priority"])
+		level := levels[project.Instance(inst.Project().Name, inst.Name())]
 
-		// Shutdown instances in priority batches, logging at the start of each batch.
-		if i == 0 || priority != currentBatchPriority {
+		// Shutdown instances in dependency level and priority batches, logging at the start of each batch.
+		if i == 0 || priority != currentBatchPriority || level != currentBatchLevel {
 			currentBatchPriority = priority
+			currentBatchLevel = level
 
-			// Wait for instances with higher priority to finish before starting next batch.
+			// Wait for dependent instances and instances with higher priority to finish before starting next batch.
 			wg.Wait()
-			logger.Info("Stopping instances", logger.Ctx{"stopPriority": currentBatchPriority})
+			logger.Info("Stopping instances", logger.Ctx{"stopPriority": currentBatchPriority, "dependencyLevel": currentBatchLevel})
 		}
 
 		wg.Add(1)
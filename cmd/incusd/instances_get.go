@@ -14,6 +14,7 @@ import (
 
 	"github.com/lxc/incus/v7/internal/filter"
 	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/cluster"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
@@ -458,6 +459,16 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 		return resultFullList[i].Project < resultFullList[j].Project
 	})
 
+	// Don't disclose the backup target credentials, including through filtering.
+	for _, inst := range resultFullList {
+		inst.Config = backup.HideTargetSecret(inst.Config)
+		inst.ExpandedConfig = backup.HideTargetSecret(inst.ExpandedConfig)
+		for i := range inst.Snapshots {
+			inst.Snapshots[i].Config = backup.HideTargetSecret(inst.Snapshots[i].Config)
+			inst.Snapshots[i].ExpandedConfig = backup.HideTargetSecret(inst.Snapshots[i].ExpandedConfig)
+		}
+	}
+
 	// Filter result list if needed.
 	if clauses != nil && len(clauses.Clauses) > 0 {
 		resultFullList, err = instance.FilterFull(resultFullList, *clauses)
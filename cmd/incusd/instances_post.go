@@ -30,6 +30,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/lifecycle"
 	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/replication"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/scriptlet"
@@ -319,6 +320,11 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 		return response.Conflict(fmt.Errorf("Instance %q already exists with a different type", req.Name))
 	}
 
+	// Refuse to replicate onto an instance which has been promoted.
+	if inst != nil && req.Config[replication.KeySource] != "" && inst.LocalConfig()[replication.KeySource] == "" {
+		return response.Conflict(fmt.Errorf("Instance %q isn't a replica, it may have been promoted", req.Name))
+	}
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -422,7 +428,7 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 		sink.instance.SetOperation(op)
 
 		// And finally run the migration.
-		err = sink.do(instOp)
+		err = sink.do(op, instOp)
 		if err != nil {
 			err = fmt.Errorf("Error transferring instance data: %w", err)
 			instOp.Done(err) // Complete operation that was created earlier, to release lock.
@@ -807,6 +813,18 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	reverter := revert.New()
 	defer reverter.Fail()
 
+	// Read the backup from a repository if requested.
+	if r.Header.Get("X-Incus-repository-backup") != "" {
+		reader, err := backupRepositoryReader(s, r, data)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		defer func() { _ = reader.Close() }()
+
+		data = reader
+	}
+
 	// Create temporary file to store uploaded backup data.
 	backupFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
 	if err != nil {
@@ -874,6 +892,21 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		backupFile = tarFile
 	}
 
+	// Rebuild the full backup when importing a backup chain.
+	if util.IsTrue(r.Header.Get("X-Incus-chain")) {
+		rebuiltFile, err := backupRebuildChain(s, backupFile)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// We don't need the backup chain anymore.
+		_ = backupFile.Close()
+		_ = os.Remove(backupFile.Name())
+
+		// Replace the backup file handle with the handle to the rebuilt backup.
+		backupFile = rebuiltFile
+	}
+
 	// Parse the backup information.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
@@ -885,6 +918,11 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(err)
 	}
 
+	// Incremental backups can't be restored on their own.
+	if bInfo.Incremental != nil {
+		return response.BadRequest(errors.New("Incremental backups must be imported along with the backup chain they belong to"))
+	}
+
 	// Detect broken legacy backups.
 	if bInfo.Config == nil || bInfo.Config.Container == nil {
 		return response.BadRequest(errors.New("Backup file is missing required information"))
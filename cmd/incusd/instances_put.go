@@ -168,7 +168,7 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 					defer wgAction.Done()
 
 					inst.SetOperation(op)
-					err := doInstanceStatePut(inst, *req.State)
+					err := doInstanceStatePut(s, inst, *req.State)
 					if err != nil {
 						failuresLock.Lock()
 						failures[inst.Name()] = err
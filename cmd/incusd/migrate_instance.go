@@ -10,11 +10,14 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/lxc/incus/v7/internal/server/instance"
 	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v7/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v7/internal/server/migration"
 	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/tracing"
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/logger"
@@ -87,12 +90,20 @@ func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool
 func (s *migrationSourceWs) do(migrateOp *operations.Operation) error {
 	l := logger.AddContext(logger.Ctx{"project": s.instance.Project().Name, "instance": s.instance.Name(), "live": s.live, "clusterMoveSourceName": s.clusterMoveSourceName, "push": s.pushOperationURL != ""})
 
+	traceAttrs := []attribute.KeyValue{
+		attribute.String("incus.project", s.instance.Project().Name),
+		attribute.String("incus.instance", s.instance.Name()),
+		attribute.Bool("incus.migration.live", s.live),
+	}
+
 	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*30)
 	defer cancel()
 
 	l.Debug("Waiting for migration control connection on source")
 
+	_, span := tracing.Start(migrateOp.Context(), "migration source connect", traceAttrs...)
 	_, err := s.conns[api.SecretNameControl].WebSocket(ctx)
+	tracing.End(span, err)
 	if err != nil {
 		return fmt.Errorf("Failed waiting for migration control connection on source: %w", err)
 	}
@@ -131,6 +142,8 @@ func (s *migrationSourceWs) do(migrateOp *operations.Operation) error {
 	}
 
 	s.instance.SetOperation(migrateOp)
+
+	_, span = tracing.Start(migrateOp.Context(), "migration source transfer", traceAttrs...)
 	err = s.instance.MigrateSend(instance.MigrateSendArgs{
 		MigrateArgs: instance.MigrateArgs{
 			ControlSend:    s.send,
@@ -153,6 +166,7 @@ func (s *migrationSourceWs) do(migrateOp *operations.Operation) error {
 		Devices:              s.devices,
 		SkipDependentVolumes: s.skipDependentVolumes,
 	})
+	tracing.End(span, err)
 	if err != nil {
 		l.Error("Failed migration on source", logger.Ctx{"err": err})
 
@@ -217,15 +231,23 @@ func newMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 	return &sink, nil
 }
 
-func (c *migrationSink) do(instOp *operationlock.InstanceOperation) error {
+func (c *migrationSink) do(migrateOp *operations.Operation, instOp *operationlock.InstanceOperation) error {
 	l := logger.AddContext(logger.Ctx{"project": c.instance.Project().Name, "instance": c.instance.Name(), "live": c.live, "clusterMoveSourceName": c.clusterMoveSourceName, "push": c.push})
 
+	traceAttrs := []attribute.KeyValue{
+		attribute.String("incus.project", c.instance.Project().Name),
+		attribute.String("incus.instance", c.instance.Name()),
+		attribute.Bool("incus.migration.live", c.live),
+	}
+
 	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*30)
 	defer cancel()
 
 	l.Debug("Waiting for migration control connection on target")
 
+	_, span := tracing.Start(migrateOp.Context(), "migration target connect", traceAttrs...)
 	_, err := c.conns[api.SecretNameControl].WebSocket(ctx)
+	tracing.End(span, err)
 	if err != nil {
 		return fmt.Errorf("Failed waiting for migration control connection on target: %w", err)
 	}
@@ -266,6 +288,7 @@ func (c *migrationSink) do(instOp *operationlock.InstanceOperation) error {
 		return wsConn, nil
 	}
 
+	_, span = tracing.Start(migrateOp.Context(), "migration target transfer", traceAttrs...)
 	err = c.instance.MigrateReceive(instance.MigrateReceiveArgs{
 		MigrateArgs: instance.MigrateArgs{
 			ControlSend:    c.send,
@@ -288,6 +311,7 @@ func (c *migrationSink) do(instOp *operationlock.InstanceOperation) error {
 		Refresh:             c.refresh,
 		RefreshExcludeOlder: c.refreshExcludeOlder,
 	})
+	tracing.End(span, err)
 	if err != nil {
 		l.Error("Failed migration on target", logger.Ctx{"err": err})
 
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"slices"
+	"strings"
 
 	clusterRequest "github.com/lxc/incus/v7/internal/server/cluster/request"
 	"github.com/lxc/incus/v7/internal/server/db"
@@ -28,7 +29,7 @@ var networkAllocationsCmd = APIEndpoint{
 
 // swagger:operation GET /1.0/network-allocations network-allocations network_allocations_get
 //
-//	Get the network allocations in use (`network`, `network-forward` and `load-balancer` and `instance`)
+//	Get the network allocations in use (`network`, `network-forward`, `load-balancer`, `network-reservation` and `instance`)
 //
 //	Returns a list of network allocations.
 //
@@ -271,6 +272,44 @@ func networkAllocationsGet(d *Daemon, r *http.Request) response.Response {
 					},
 				)
 			}
+
+			var dbReservations []dbCluster.NetworkReservation
+			err = d.db.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+				networkID := n.ID()
+
+				// Get the address reservations.
+				dbReservations, err = dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{NetworkID: &networkID})
+				if err != nil {
+					return err
+				}
+
+				return nil
+			})
+			if err != nil {
+				return response.SmartError(fmt.Errorf("Failed getting reservations for network %q in project %q: %w", networkName, projectName, err))
+			}
+
+			for _, reservation := range dbReservations {
+				// Ranges are reported as is, single addresses in their CIDR form.
+				address := reservation.Address
+				if !strings.Contains(address, "-") {
+					address, _, err = ipToCIDR(address, netConf)
+					if err != nil {
+						return response.SmartError(err)
+					}
+				}
+
+				result = append(
+					result,
+					api.NetworkAllocations{
+						Network: networkName,
+						Address: address,
+						UsedBy:  api.NewURL().Path(version.APIVersion, "networks", networkName, "reservations", reservation.Address).Project(projectName).String(),
+						Type:    "network-reservation",
+						NAT:     false, // Reserved addresses aren't in use.
+					},
+				)
+			}
 		}
 	}
 
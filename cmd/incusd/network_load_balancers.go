@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/lxc/incus/v7/internal/filter"
 	"github.com/lxc/incus/v7/internal/server/auth"
@@ -16,9 +17,11 @@ import (
 	"github.com/lxc/incus/v7/internal/server/project"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/task"
 	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
 )
 
 var networkLoadBalancersCmd = APIEndpoint{
@@ -936,3 +939,16 @@ func networkLoadBalancerStateGet(d *Daemon, r *http.Request) response.Response {
 
 	return response.SyncResponse(true, lbState)
 }
+
+// networkLoadBalancerHealthCheckTask runs the health checks of the load balancers of the bridge networks.
+// OVN networks are left alone as OVN checks the health of their backends itself.
+func networkLoadBalancerHealthCheckTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := network.LoadBalancerHealthCheck(ctx, d.State())
+		if err != nil {
+			logger.Error("Failed checking network load balancer health", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Second)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/lxc/incus/v7/internal/filter"
 	"github.com/lxc/incus/v7/internal/server/auth"
+	clusterRequest "github.com/lxc/incus/v7/internal/server/cluster/request"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
 	"github.com/lxc/incus/v7/internal/server/lifecycle"
@@ -372,7 +373,9 @@ func networkPeersPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Network driver %q does not support peering", n.Type()))
 	}
 
-	err = n.PeerCreate(req)
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.PeerCreate(req, clientType)
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed creating peer: %w", err))
 	}
@@ -458,7 +461,9 @@ func networkPeerDelete(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	err = n.PeerDelete(peerName)
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.PeerDelete(peerName, clientType)
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed deleting peer: %w", err))
 	}
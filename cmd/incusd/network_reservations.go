@@ -0,0 +1,653 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/lxc/incus/v7/internal/filter"
+	"github.com/lxc/incus/v7/internal/server/auth"
+	clusterRequest "github.com/lxc/incus/v7/internal/server/cluster/request"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/network"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+var networkReservationsCmd = APIEndpoint{
+	Path: "networks/{networkName}/reservations",
+
+	Get:  APIEndpointAction{Handler: networkReservationsGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+	Post: APIEndpointAction{Handler: networkReservationsPost, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
+var networkReservationCmd = APIEndpoint{
+	Path: "networks/{networkName}/reservations/{address}",
+
+	Delete: APIEndpointAction{Handler: networkReservationDelete, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+	Get:    APIEndpointAction{Handler: networkReservationGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+	Put:    APIEndpointAction{Handler: networkReservationPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+	Patch:  APIEndpointAction{Handler: networkReservationPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
+// networkReservationRequest loads the network and reservation address targeted by the request.
+func networkReservationRequest(s *state.State, r *http.Request) (network.Network, string, response.Response) {
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	networkName, err := pathVar(r, "networkName")
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return nil, "", response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return nil, "", response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	if !n.Info().Reservations {
+		return nil, "", response.BadRequest(fmt.Errorf("Network driver %q does not support address reservations", n.Type()))
+	}
+
+	address, err := pathVar(r, "address")
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	return n, address, nil
+}
+
+// networkReservationGetRecord returns the reservation of the network with the given address.
+func networkReservationGetRecord(ctx context.Context, cluster *db.Cluster, n network.Network, address string) (*api.NetworkReservation, error) {
+	var reservation *api.NetworkReservation
+
+	err := cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		dbRecords, err := dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{
+			NetworkID: &networkID,
+			Address:   &address,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(dbRecords) == 0 {
+			return api.StatusErrorf(http.StatusNotFound, "Network reservation not found")
+		}
+
+		reservation = dbRecords[0].ToAPI()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// API endpoints
+
+// swagger:operation GET /1.0/networks/{networkName}/reservations network-reservations network_reservations_get
+//
+//  Get the network address reservations
+//
+//  Returns a list of network address reservations (URLs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: path
+//      name: networkName
+//      description: Network name
+//      type: string
+//      required: true
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      x-example: default
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      x-example: default
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example:
+//              - /1.0/networks/mybr0/reservations/192.0.2.10
+//              - /1.0/networks/mybr0/reservations/192.0.2.100-192.0.2.150
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "404":
+//      $ref: "#/responses/NotFound"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/networks/{networkName}/reservations?recursion=1 network-reservations network_reservations_get_recursion1
+//
+//  Get the network address reservations
+//
+//  Returns a list of network address reservations (structs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: path
+//      name: networkName
+//      description: Network name
+//      type: string
+//      required: true
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      x-example: default
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      x-example: default
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of network address reservations
+//            items:
+//              $ref: "#/definitions/NetworkReservation"
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "404":
+//      $ref: "#/responses/NotFound"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+func networkReservationsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	networkName, err := pathVar(r, "networkName")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	if !n.Info().Reservations {
+		return response.BadRequest(fmt.Errorf("Network driver %q does not support address reservations", n.Type()))
+	}
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	// Parse filter value.
+	filterStr := r.FormValue("filter")
+	clauses, err := filter.Parse(filterStr, filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	var dbRecords []dbCluster.NetworkReservation
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		dbRecords, err = dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{
+			NetworkID: &networkID,
+		})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network reservations: %w", err))
+	}
+
+	linkResults := make([]string, 0, len(dbRecords))
+	fullResults := make([]api.NetworkReservation, 0, len(dbRecords))
+
+	for _, dbRecord := range dbRecords {
+		reservation := dbRecord.ToAPI()
+
+		if clauses != nil && len(clauses.Clauses) > 0 {
+			match, err := filter.Match(*reservation, *clauses)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if !match {
+				continue
+			}
+		}
+
+		fullResults = append(fullResults, *reservation)
+		linkResults = append(linkResults, fmt.Sprintf("/%s/networks/%s/reservations/%s", version.APIVersion, url.PathEscape(n.Name()), url.PathEscape(reservation.Address)))
+	}
+
+	if recursion {
+		return response.SyncResponse(true, fullResults)
+	}
+
+	return response.SyncResponse(true, linkResults)
+}
+
+// swagger:operation POST /1.0/networks/{networkName}/reservations network-reservations network_reservations_post
+//
+//	Add a network address reservation
+//
+//	Reserves a network address or range of addresses.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: networkName
+//	    description: Network name
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	  - in: body
+//	    name: reservation
+//	    description: Reservation
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkReservationsPost"
+//	responses:
+//	  "201":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "409":
+//	    $ref: "#/responses/Conflict"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Parse the request into a record.
+	req := api.NetworkReservationsPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	networkName, err := pathVar(r, "networkName")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	if !n.Info().Reservations {
+		return response.BadRequest(fmt.Errorf("Network driver %q does not support address reservations", n.Type()))
+	}
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.ReservationCreate(req, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed creating address reservation: %w", err))
+	}
+
+	lc := lifecycle.NetworkReservationCreated.Event(n, req.Address, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/networks/{networkName}/reservations/{address} network-reservations network_reservation_delete
+//
+//	Delete the network address reservation
+//
+//	Releases the network address reservation.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: networkName
+//	    description: Network name
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: address
+//	    description: Reserved address or range
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	n, address, resp := networkReservationRequest(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err := n.ReservationDelete(address, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed deleting address reservation: %w", err))
+	}
+
+	s.Events.SendLifecycle(n.Project(), lifecycle.NetworkReservationDeleted.Event(n, address, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/networks/{networkName}/reservations/{address} network-reservations network_reservation_get
+//
+//	Get the network address reservation
+//
+//	Gets a specific network address reservation.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: networkName
+//	    description: Network name
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: address
+//	    description: Reserved address or range
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	responses:
+//	  "200":
+//	    description: Address reservation
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkReservation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	n, address, resp := networkReservationRequest(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	reservation, err := networkReservationGetRecord(r.Context(), s.DB.Cluster, n, address)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, reservation, reservation.Etag())
+}
+
+// swagger:operation PATCH /1.0/networks/{networkName}/reservations/{address} network-reservations network_reservation_patch
+//
+//  Partially update the network address reservation
+//
+//  Updates a subset of the network address reservation configuration.
+//
+//  ---
+//  consumes:
+//    - application/json
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: path
+//      name: networkName
+//      description: Network name
+//      type: string
+//      required: true
+//    - in: path
+//      name: address
+//      description: Reserved address or range
+//      type: string
+//      required: true
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      x-example: default
+//    - in: body
+//      name: reservation
+//      description: Address reservation configuration
+//      required: true
+//      schema:
+//        $ref: "#/definitions/NetworkReservationPut"
+//  responses:
+//    "200":
+//      $ref: "#/responses/EmptySyncResponse"
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "404":
+//      $ref: "#/responses/NotFound"
+//    "409":
+//      $ref: "#/responses/Conflict"
+//    "412":
+//      $ref: "#/responses/PreconditionFailed"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/networks/{networkName}/reservations/{address} network-reservations network_reservation_put
+//
+//	Update the network address reservation
+//
+//	Updates the entire network address reservation configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: networkName
+//	    description: Network name
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: address
+//	    description: Reserved address or range
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	  - in: body
+//	    name: reservation
+//	    description: Address reservation configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkReservationPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "409":
+//	    $ref: "#/responses/Conflict"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	n, address, resp := networkReservationRequest(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	reservation, err := networkReservationGetRecord(r.Context(), s.DB.Cluster, n, address)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, reservation.Etag())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	// Decode the request, merging it on top of the current values for PATCH.
+	req := api.NetworkReservationPut{}
+	if r.Method == http.MethodPatch {
+		req = reservation.Writable()
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = n.ReservationUpdate(reservation.Address, req)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed updating address reservation: %w", err))
+	}
+
+	s.Events.SendLifecycle(n.Project(), lifecycle.NetworkReservationUpdated.Event(n, reservation.Address, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
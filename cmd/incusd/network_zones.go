@@ -6,17 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/lxc/incus/v7/internal/filter"
 	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/cluster"
 	clusterRequest "github.com/lxc/incus/v7/internal/server/cluster/request"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
 	"github.com/lxc/incus/v7/internal/server/lifecycle"
 	"github.com/lxc/incus/v7/internal/server/network/zone"
+	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/task"
 	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
@@ -238,6 +244,7 @@ func networkZonesGet(d *Daemon, r *http.Request) response.Response {
 
 			netzoneInfo := netzone.Info()
 			netzoneInfo.UsedBy, _ = netzone.UsedBy() // Ignore errors in UsedBy, will return nil.
+			netzoneInfo.DS, _ = netzone.DS()         // Ignore errors in DS, will return nil.
 			netzoneInfo.Project = projectName
 
 			if clauses != nil && len(clauses.Clauses) > 0 {
@@ -484,6 +491,11 @@ func networkZoneGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	info.DS, err = netzone.DS()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	return response.SyncResponseETag(true, info, netzone.Etag())
 }
 
@@ -629,3 +641,152 @@ func networkZonePut(d *Daemon, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+// rolloverNetworkZoneKeysTask rolls over the DNSSEC zone signing keys of the network zones reaching the end of
+// their lifetime. Keys are shared by all cluster members so only the leader runs it.
+func rolloverNetworkZoneKeysTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && s.LocalConfig.ClusterAddress() != leader {
+			logger.Debug("Skipping network zone key rollover task since we're not leader")
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return rolloverNetworkZoneKeys(ctx, s, time.Now())
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.NetworkZoneKeysRollover, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating network zone key rollover operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Debug("Rolling over network zone DNSSEC keys")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting network zone key rollover operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed rolling over network zone DNSSEC keys", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Debug("Done rolling over network zone DNSSEC keys")
+	}
+
+	return f, task.Hourly()
+}
+
+// rolloverNetworkZoneKeys rolls over the DNSSEC keys of all the network zones.
+func rolloverNetworkZoneKeys(ctx context.Context, s *state.State, now time.Time) error {
+	var dbZones []dbCluster.NetworkZone
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbZones, err = dbCluster.GetNetworkZones(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network zones: %w", err)
+	}
+
+	var errs []error
+	for _, dbZone := range dbZones {
+		netzone, err := zone.LoadByNameAndProject(s, dbZone.Project, dbZone.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed loading network zone %q: %w", dbZone.Name, err))
+			continue
+		}
+
+		err = netzone.RolloverKeys(now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed rolling over keys of network zone %q: %w", dbZone.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// pushNetworkZoneRecordsTask pushes the changes to the records of the network zones to the servers configured
+// through push.NAME.*, picking up the changes to zones, records, instances and DHCP leases and retrying failed
+// pushes. Only the leader pushes the records, starting with a full resynchronisation of the servers.
+func pushNetworkZoneRecordsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && s.LocalConfig.ClusterAddress() != leader {
+			// Resynchronise the servers in full if this member becomes the leader again.
+			s.DNS.RetainZonePush(nil)
+
+			logger.Debug("Skipping network zone push task since we're not leader")
+			return
+		}
+
+		err = pushNetworkZoneRecords(ctx, s)
+		if err != nil {
+			logger.Warn("Failed pushing network zone records", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+// pushNetworkZoneRecords pushes the records of all the network zones to their configured servers.
+func pushNetworkZoneRecords(ctx context.Context, s *state.State) error {
+	var dbZones []dbCluster.NetworkZone
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbZones, err = dbCluster.GetNetworkZones(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network zones: %w", err)
+	}
+
+	var errs []error
+	pushedZones := []string{}
+	for _, dbZone := range dbZones {
+		netzone, err := zone.LoadByNameAndProject(s, dbZone.Project, dbZone.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed loading network zone %q: %w", dbZone.Name, err))
+
+			// Keep what was pushed for the zone until it can be loaded again.
+			pushedZones = append(pushedZones, dbZone.Name)
+			continue
+		}
+
+		pushed, err := netzone.PushRecords()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if pushed {
+			pushedZones = append(pushedZones, dbZone.Name)
+		}
+	}
+
+	// Forget about the zones which were deleted or aren't pushed anymore.
+	s.DNS.RetainZonePush(pushedZones)
+
+	return errors.Join(errs...)
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/task"
 	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	"github.com/lxc/incus/v7/internal/server/warnings"
 	"github.com/lxc/incus/v7/internal/version"
@@ -2321,3 +2322,58 @@ func networkStateGet(d *Daemon, r *http.Request) response.Response {
 
 	return response.SyncResponse(true, networkState)
 }
+
+// networkWireguardRefreshTask keeps the WireGuard meshes of the bridge networks in line with the cluster members
+// and replaces the WireGuard keys of this member reaching the end of their lifetime.
+func networkWireguardRefreshTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := network.WireguardRefresh(ctx, d.State())
+		if err != nil {
+			logger.Error("Failed refreshing network WireGuard meshes", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+var internalNetworkWireguardSyncCmd = APIEndpoint{
+	Path: "networks/wireguard-sync",
+
+	Post: APIEndpointAction{Handler: internalNetworkWireguardSync, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// internalNetworkWireguardSync connects the bridge networks to the WireGuard peers of the other cluster members.
+// It's called by the members replacing their WireGuard key.
+func internalNetworkWireguardSync(d *Daemon, r *http.Request) response.Response {
+	err := network.WireguardSync(r.Context(), d.State())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// networkEVPNRefreshTask advertises the MAC addresses learned by the bridge networks using EVPN and programs
+// their VXLAN tunnels from the routes of the other VTEPs.
+func networkEVPNRefreshTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := network.EVPNRefresh(ctx, d.State())
+		if err != nil {
+			logger.Error("Failed refreshing network EVPN routes", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(10 * time.Second)
+}
+
+// networkFlowExportTask returns a task that exports the flows of bridge networks to their IPFIX collectors.
+func networkFlowExportTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := network.FlowExport(ctx, d.State())
+		if err != nil {
+			logger.Error("Failed exporting network flows", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/lxc/incus/v7/internal/filter"
 	"github.com/lxc/incus/v7/internal/jmap"
 	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/cluster"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
@@ -245,6 +246,7 @@ func profilesGet(d *Daemon, r *http.Request) response.Response {
 				}
 
 				apiProfile.UsedBy = project.FilterUsedBy(s.Authorizer, r, apiProfile.UsedBy)
+				apiProfile.Config = backup.HideTargetSecret(apiProfile.Config)
 
 				if clauses != nil && len(clauses.Clauses) > 0 {
 					match, err := filter.Match(*apiProfile, *clauses)
@@ -523,6 +525,8 @@ func profileGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	etag := []any{resp.Config, resp.Description, resp.Devices}
+	resp.Config = backup.HideTargetSecret(resp.Config)
+
 	return response.SyncResponseETag(true, resp, etag)
 }
 
@@ -627,6 +631,8 @@ func profilePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	backup.KeepTargetSecret(req.Config, profile.Config)
+
 	err = doProfileUpdate(r.Context(), s, *p, name, profile, req)
 
 	if err == nil && !isClusterNotification(r) {
@@ -762,6 +768,8 @@ func profilePatch(d *Daemon, r *http.Request) response.Response {
 	if req.Config == nil {
 		req.Config = profile.Config
 	} else {
+		backup.KeepTargetSecret(req.Config, profile.Config)
+
 		for k, v := range profile.Config {
 			_, ok := req.Config[k]
 			if !ok {
@@ -0,0 +1,678 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	incus "github.com/lxc/incus/v7/client"
+	internalInstance "github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/db/warningtype"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/replication"
+	"github.com/lxc/incus/v7/internal/server/state"
+	storagePools "github.com/lxc/incus/v7/internal/server/storage"
+	"github.com/lxc/incus/v7/internal/server/task"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/internal/server/warnings"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// replicationSourceName returns the name recorded on the replicas pushed from this server.
+func replicationSourceName(s *state.State) string {
+	if s.ServerClustered {
+		return s.ServerName
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return s.ServerName
+	}
+
+	return hostname
+}
+
+// replicationConnect connects to the remote server set through the `replication.target*` keys of the config.
+// The server certificate is used as client certificate so the remote server must trust it.
+func replicationConnect(s *state.State, config map[string]string, defaultProject string) (incus.InstanceServer, error) {
+	serverCert := s.ServerCert()
+
+	args := &incus.ConnectionArgs{
+		TLSServerCert: config["replication.target.certificate"],
+		TLSClientCert: string(serverCert.PublicKey()),
+		TLSClientKey:  string(serverCert.PrivateKey()),
+		UserAgent:     version.UserAgent,
+		Proxy:         s.Proxy,
+		SkipGetEvents: true,
+		SkipGetServer: true,
+	}
+
+	remote, err := incus.ConnectIncus(config["replication.target"], args)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to %q: %w", config["replication.target"], err)
+	}
+
+	projectName := config["replication.target.project"]
+	if projectName == "" {
+		projectName = defaultProject
+	}
+
+	return remote.UseProject(projectName), nil
+}
+
+// replicationPushTarget returns the migration target matching the push mode operation created on the remote server.
+func replicationPushTarget(config map[string]string, targetOp incus.Operation) (string, map[string]string) {
+	opAPI := targetOp.Get()
+
+	secrets := map[string]string{}
+	for k, v := range opAPI.Metadata {
+		val, ok := v.(string)
+		if ok {
+			secrets[k] = val
+		}
+	}
+
+	opURL := fmt.Sprintf("%s/1.0/operations/%s", strings.TrimSuffix(config["replication.target"], "/"), url.PathEscape(opAPI.ID))
+
+	return opURL, secrets
+}
+
+// replicationRecordWarning raises a warning for the entity if the replication failed and resolves it otherwise.
+func replicationRecordWarning(ctx context.Context, s *state.State, projectName string, entityType int, entityID int, replicationErr error) {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "entityType": entityType, "entityID": entityID})
+
+	if replicationErr == nil {
+		err := warnings.ResolveWarningsByNodeAndProjectAndTypeAndEntity(s.DB.Cluster, s.ServerName, projectName, warningtype.ReplicationFailed, entityType, entityID)
+		if err != nil {
+			l.Warn("Failed resolving replication warning", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarning(ctx, s.ServerName, projectName, entityType, entityID, warningtype.ReplicationFailed, replicationErr.Error())
+	})
+	if err != nil {
+		l.Warn("Failed recording replication warning", logger.Ctx{"err": err})
+	}
+}
+
+func autoReplicateInstancesTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var instances []instance.Instance
+
+		// Get list of instances on the local member that are due to be replicated.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				config := db.ExpandInstanceConfig(dbInst.Config, dbInst.Profiles)
+
+				// Check if instance has replication enabled.
+				schedule := config["replication.schedule"]
+				if schedule == "" || config["replication.target"] == "" {
+					return nil
+				}
+
+				// Check if replication is scheduled.
+				if !snapshotIsScheduledNow(schedule, int64(dbInst.ID)) {
+					return nil
+				}
+
+				err := project.AllowSnapshotCreation(&p)
+				if err != nil {
+					return nil
+				}
+
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for replication task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				logger.Debug("Scheduling instance replication", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance replication schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoReplicateInstances(ctx, s, instances, op)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.InstanceReplicate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Replicating instances")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled instance replication", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done replicating instances")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoReplicateInstances replicates each instance and records the outcome in its state.
+// A failure on one instance doesn't prevent the others from being replicated.
+func autoReplicateInstances(ctx context.Context, s *state.State, instances []instance.Instance, op *operations.Operation) error {
+	var errs []error
+
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		attemptedAt := time.Now()
+		replicationErr := replicateInstance(s, inst, attemptedAt, op)
+		if replicationErr != nil {
+			logger.Error("Failed replicating instance", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": replicationErr})
+			errs = append(errs, fmt.Errorf("Failed replicating instance %q in project %q: %w", inst.Name(), inst.Project().Name, replicationErr))
+		}
+
+		err = inst.VolatileSet(replication.StateChanges(attemptedAt, replicationErr))
+		if err != nil {
+			logger.Warn("Failed recording instance replication state", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+		}
+
+		replicationRecordWarning(ctx, s, inst.Project().Name, dbCluster.TypeInstance, inst.ID(), replicationErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+// replicateInstance snapshots the instance, pushes the changes since the previous replication to the remote
+// server and then applies the replication snapshot retention.
+func replicateInstance(s *state.State, inst instance.Instance, now time.Time, op *operations.Operation) error {
+	config := inst.ExpandedConfig()
+
+	if !replication.IsRemote(config["replication.target"]) {
+		return errors.New("Instances can only be replicated to a remote server")
+	}
+
+	retention, err := replication.Retention(config)
+	if err != nil {
+		return err
+	}
+
+	// The snapshot is the base of the next incremental transfer.
+	err = inst.Snapshot(replication.SnapshotName(now), time.Time{}, false)
+	if err != nil {
+		return fmt.Errorf("Failed creating replication snapshot: %w", err)
+	}
+
+	remote, err := replicationConnect(s, config, inst.Project().Name)
+	if err != nil {
+		return err
+	}
+
+	render, _, err := inst.Render()
+	if err != nil {
+		return err
+	}
+
+	instInfo, ok := render.(*api.Instance)
+	if !ok {
+		return errors.New("Unexpected instance render type")
+	}
+
+	// Create the replica or refresh it if it already exists.
+	req := api.InstancesPost{
+		Name:        inst.Name(),
+		InstancePut: instInfo.Writable(),
+		Type:        api.InstanceType(instInfo.Type),
+		Source: api.InstanceSource{
+			Type:      "migration",
+			Mode:      "push",
+			Refresh:   true,
+			BaseImage: config["volatile.base_image"],
+		},
+	}
+
+	req.Config = replication.ReplicaConfig(req.Config, replicationSourceName(s))
+
+	targetOp, err := remote.CreateInstance(req)
+	if err != nil {
+		return fmt.Errorf("Failed creating replica on %q: %w", config["replication.target"], err)
+	}
+
+	opURL, secrets := replicationPushTarget(config, targetOp)
+	pushTarget := &api.InstancePostTarget{
+		Certificate: config["replication.target.certificate"],
+		Operation:   opURL,
+		Websockets:  secrets,
+	}
+
+	ws, err := newMigrationSource(inst, false, false, false, "", "", nil, nil, pushTarget)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return err
+	}
+
+	err = ws.do(op)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return err
+	}
+
+	err = targetOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed refreshing replica on %q: %w", config["replication.target"], err)
+	}
+
+	// Apply the retention now that the latest snapshot is on the remote server.
+	// The deleted snapshots are removed from the replica on the next refresh.
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return err
+	}
+
+	snapshotNames := make([]string, 0, len(snapshots))
+	snapshotsByName := make(map[string]instance.Instance, len(snapshots))
+	for _, snap := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snap.Name())
+		snapshotNames = append(snapshotNames, snapName)
+		snapshotsByName[snapName] = snap
+	}
+
+	for _, snapName := range replication.Expired(snapshotNames, retention) {
+		err = snapshotsByName[snapName].Delete(true, true)
+		if err != nil {
+			return fmt.Errorf("Failed deleting replication snapshot %q: %w", snapName, err)
+		}
+	}
+
+	return nil
+}
+
+func autoReplicateCustomVolumesTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var volumes, remoteVolumes []db.StorageVolumeArgs
+		var memberCount int
+		var onlineMemberIDs []int64
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for custom volume replication task: %w", err)
+			}
+
+			for _, v := range allVolumes {
+				schedule := v.Config["replication.schedule"]
+				if schedule == "" || v.Config["replication.target"] == "" {
+					continue
+				}
+
+				// Check if replication is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Keep a separate list of remote volumes in order to select a member to
+					// perform the replication later.
+					remoteVolumes = append(remoteVolumes, v)
+				} else {
+					logger.Debug("Scheduling local custom volume replication", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v) // Always include local volumes.
+				}
+			}
+
+			if len(remoteVolumes) > 0 {
+				// Get list of cluster members.
+				members, err := tx.GetNodes(ctx)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster members: %w", err)
+				}
+
+				memberCount = len(members)
+
+				// Filter to online members.
+				for _, member := range members {
+					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+						continue
+					}
+
+					onlineMemberIDs = append(onlineMemberIDs, member.ID)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume replication schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		localMemberID := s.DB.Cluster.GetNodeID()
+
+		// Skip replicating remote custom volumes if there are no online members, as we can't be sure that
+		// the cluster isn't partitioned and we may end up attempting the replication on multiple members.
+		if len(remoteVolumes) > 0 && memberCount > 1 && len(onlineMemberIDs) <= 0 {
+			logger.Error("Skipping remote volumes for custom volume replication task due to no online members")
+		} else {
+			for _, v := range remoteVolumes {
+				// If there are multiple cluster members, a stable random member is chosen to perform
+				// the replication from.
+				if memberCount > 1 {
+					selectedNodeID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
+					if err != nil {
+						logger.Error("Failed scheduling remote custom volume replication", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+						continue
+					}
+
+					// Don't replicate, if we're not the chosen one.
+					if localMemberID != selectedNodeID {
+						continue
+					}
+				}
+
+				logger.Debug("Scheduling remote custom volume replication", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v)
+			}
+		}
+
+		if len(volumes) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoReplicateCustomVolumes(ctx, s, volumes, op)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.CustomVolumeReplicate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled volume replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Replicating volumes")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled volume replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled custom volume replication", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done replicating volumes")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoReplicateCustomVolumes replicates each custom volume and records the outcome in its config.
+// A failure on one volume doesn't prevent the others from being replicated.
+func autoReplicateCustomVolumes(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs, op *operations.Operation) error {
+	var errs []error
+
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		pool, err := storagePools.LoadByName(s, v.PoolName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed loading pool for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err))
+			continue
+		}
+
+		attemptedAt := time.Now()
+		replicationErr := replicateCustomVolume(ctx, s, pool, v, attemptedAt, op)
+		if replicationErr != nil {
+			logger.Error("Failed replicating custom volume", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": replicationErr})
+			errs = append(errs, fmt.Errorf("Failed replicating volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, replicationErr))
+		}
+
+		// Record the outcome in the volume config.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			dbVol, err := tx.GetStoragePoolVolume(ctx, pool.ID(), v.ProjectName, db.StoragePoolVolumeTypeCustom, v.Name, true)
+			if err != nil {
+				return err
+			}
+
+			for key, value := range replication.StateChanges(attemptedAt, replicationErr) {
+				dbVol.Config[key] = value
+			}
+
+			return tx.UpdateStoragePoolVolume(ctx, v.ProjectName, v.Name, db.StoragePoolVolumeTypeCustom, pool.ID(), dbVol.Description, dbVol.Config)
+		})
+		if err != nil {
+			logger.Warn("Failed recording custom volume replication state", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+		}
+
+		replicationRecordWarning(ctx, s, v.ProjectName, dbCluster.TypeStorageVolume, int(v.ID), replicationErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+// replicateCustomVolume snapshots the volume, pushes the changes since the previous replication to the
+// target pool or remote server and then applies the replication snapshot retention.
+func replicateCustomVolume(ctx context.Context, s *state.State, pool storagePools.Pool, v db.StorageVolumeArgs, now time.Time, op *operations.Operation) error {
+	retention, err := replication.Retention(v.Config)
+	if err != nil {
+		return err
+	}
+
+	// The snapshot is the base of the next incremental transfer.
+	err = pool.CreateCustomVolumeSnapshot(v.ProjectName, v.Name, replication.SnapshotName(now), time.Time{}, false, op)
+	if err != nil {
+		return fmt.Errorf("Failed creating replication snapshot: %w", err)
+	}
+
+	if replication.IsRemote(v.Config["replication.target"]) {
+		err = replicateCustomVolumeToRemote(ctx, s, pool, v, op)
+	} else {
+		err = replicateCustomVolumeToPool(ctx, s, pool, v, op)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Apply the retention now that the latest snapshot has been transferred.
+	// The deleted snapshots are removed from the replica on the next refresh.
+	var snapshots []db.StorageVolumeArgs
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		snapshots, err = tx.GetLocalStoragePoolVolumeSnapshotsWithType(ctx, v.ProjectName, v.Name, db.StoragePoolVolumeTypeCustom, pool.ID())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	snapshotNames := make([]string, 0, len(snapshots))
+	for _, snap := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snap.Name)
+		snapshotNames = append(snapshotNames, snapName)
+	}
+
+	for _, snapName := range replication.Expired(snapshotNames, retention) {
+		err = pool.DeleteCustomVolumeSnapshot(v.ProjectName, v.Name+internalInstance.SnapshotDelimiter+snapName, op)
+		if err != nil {
+			return fmt.Errorf("Failed deleting replication snapshot %q: %w", snapName, err)
+		}
+	}
+
+	return nil
+}
+
+// replicateCustomVolumeToPool creates or refreshes the replica of the volume in another local storage pool.
+func replicateCustomVolumeToPool(ctx context.Context, s *state.State, pool storagePools.Pool, v db.StorageVolumeArgs, op *operations.Operation) error {
+	targetPool, err := storagePools.LoadByName(s, v.Config["replication.target"])
+	if err != nil {
+		return fmt.Errorf("Failed loading replication target pool %q: %w", v.Config["replication.target"], err)
+	}
+
+	if targetPool.Name() == pool.Name() {
+		return errors.New("Volumes can't be replicated to their own storage pool")
+	}
+
+	var replica *db.StorageVolume
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		replica, err = tx.GetStoragePoolVolume(ctx, targetPool.ID(), v.ProjectName, db.StoragePoolVolumeTypeCustom, v.Name, true)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	replicaConfig := replication.ReplicaConfig(v.Config, replicationSourceName(s))
+
+	if replica == nil {
+		return targetPool.CreateCustomVolumeFromCopy(v.ProjectName, v.ProjectName, v.Name, v.Description, replicaConfig, pool.Name(), v.Name, true, op)
+	}
+
+	if replica.Config[replication.KeySource] == "" {
+		return fmt.Errorf("Volume %q in pool %q isn't a replica, it may have been promoted", v.Name, targetPool.Name())
+	}
+
+	return targetPool.RefreshCustomVolume(v.ProjectName, v.ProjectName, v.Name, v.Description, replicaConfig, pool.Name(), v.Name, true, false, op)
+}
+
+// replicateCustomVolumeToRemote creates or refreshes the replica of the volume on a remote server.
+func replicateCustomVolumeToRemote(ctx context.Context, s *state.State, pool storagePools.Pool, v db.StorageVolumeArgs, op *operations.Operation) error {
+	var dbVol *db.StorageVolume
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbVol, err = tx.GetStoragePoolVolume(ctx, pool.ID(), v.ProjectName, db.StoragePoolVolumeTypeCustom, v.Name, true)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	remote, err := replicationConnect(s, v.Config, v.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	remotePoolName := v.Config["replication.target.pool"]
+	if remotePoolName == "" {
+		remotePoolName = v.PoolName
+	}
+
+	_, _, err = remote.GetStoragePoolVolume(remotePoolName, db.StoragePoolVolumeTypeNameCustom, v.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed checking for replica on %q: %w", v.Config["replication.target"], err)
+	}
+
+	req := api.StorageVolumesPost{
+		Name:        v.Name,
+		Type:        db.StoragePoolVolumeTypeNameCustom,
+		ContentType: dbVol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config:      replication.ReplicaConfig(v.Config, replicationSourceName(s)),
+			Description: v.Description,
+		},
+		Source: api.StorageVolumeSource{
+			Type:    "migration",
+			Mode:    "push",
+			Refresh: err == nil,
+		},
+	}
+
+	targetOp, err := remote.CreateStoragePoolVolumeFromMigration(remotePoolName, req)
+	if err != nil {
+		return fmt.Errorf("Failed creating replica on %q: %w", v.Config["replication.target"], err)
+	}
+
+	opURL, secrets := replicationPushTarget(v.Config, targetOp)
+	pushTarget := &api.StorageVolumePostTarget{
+		Certificate: v.Config["replication.target.certificate"],
+		Operation:   opURL,
+		Websockets:  secrets,
+	}
+
+	ws, err := newStorageMigrationSource(false, pushTarget)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return err
+	}
+
+	err = ws.DoStorage(s, v.ProjectName, v.PoolName, v.Name, op)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return err
+	}
+
+	err = targetOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed refreshing replica on %q: %w", v.Config["replication.target"], err)
+	}
+
+	return nil
+}
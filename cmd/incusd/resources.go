@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/lxc/incus/v7/internal/server/auth"
 	"github.com/lxc/incus/v7/internal/server/response"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v7/internal/server/storage/drivers"
 	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
 	"github.com/lxc/incus/v7/shared/resources"
 )
 
@@ -164,5 +167,11 @@ func storagePoolResourcesGet(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(err)
 	}
 
+	// Failing to get the health shouldn't prevent reporting the usage.
+	res.Health, err = pool.GetHealth()
+	if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
+		logger.Warn("Failed getting storage pool health", logger.Ctx{"pool": poolName, "err": err})
+	}
+
 	return response.SyncResponse(true, res)
 }
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	storagePools "github.com/lxc/incus/v7/internal/server/storage"
+	"github.com/lxc/incus/v7/internal/server/storage/s3/local"
+	"github.com/lxc/incus/v7/internal/server/task"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+var storagePoolBucketLifecycleCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/buckets/{bucketName}/lifecycle",
+
+	Get: APIEndpointAction{Handler: storagePoolBucketLifecycleGet, AccessHandler: allowPermission(auth.ObjectTypeStorageBucket, auth.EntitlementCanView, "poolName", "bucketName", "location")},
+	Put: APIEndpointAction{Handler: storagePoolBucketLifecyclePut, AccessHandler: allowPermission(auth.ObjectTypeStorageBucket, auth.EntitlementCanEdit, "poolName", "bucketName", "location")},
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/buckets/{bucketName}/lifecycle storage storage_pool_bucket_lifecycle_get
+//
+//	Get the storage bucket lifecycle configuration
+//
+//	Gets the lifecycle rules applied to the objects of the storage bucket.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: poolName
+//	    description: Storage pool name
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: bucketName
+//	    description: Storage bucket name
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    x-example: server01
+//	responses:
+//	  "200":
+//	    description: Storage bucket lifecycle configuration
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StorageBucketLifecycle"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolBucketLifecycleGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	bucketProjectName, err := project.StorageBucketProject(r.Context(), s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	poolName, err := pathVar(r, "poolName")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading storage pool: %w", err))
+	}
+
+	if !pool.Driver().Info().Buckets {
+		return response.BadRequest(errors.New("Storage pool does not support buckets"))
+	}
+
+	bucketName, err := pathVar(r, "bucketName")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp = forwardedResponseIfBucketIsRemote(s, r, poolName, bucketProjectName, bucketName)
+	if resp != nil {
+		return resp
+	}
+
+	bucketLifecycle, err := pool.GetBucketLifecycle(bucketProjectName, bucketName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed getting storage bucket lifecycle: %w", err))
+	}
+
+	return response.SyncResponseETag(true, bucketLifecycle, bucketLifecycle)
+}
+
+// swagger:operation PUT /1.0/storage-pools/{poolName}/buckets/{bucketName}/lifecycle storage storage_pool_bucket_lifecycle_put
+//
+//	Update the storage bucket lifecycle configuration
+//
+//	Replaces the lifecycle rules applied to the objects of the storage bucket.
+//	An empty list of rules removes the lifecycle configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: poolName
+//	    description: Storage pool name
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: bucketName
+//	    description: Storage bucket name
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    x-example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    x-example: server01
+//	  - in: body
+//	    name: lifecycle
+//	    description: Storage bucket lifecycle configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StorageBucketLifecycle"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolBucketLifecyclePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	bucketProjectName, err := project.StorageBucketProject(r.Context(), s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	poolName, err := pathVar(r, "poolName")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading storage pool: %w", err))
+	}
+
+	if !pool.Driver().Info().Buckets {
+		return response.BadRequest(errors.New("Storage pool does not support buckets"))
+	}
+
+	bucketName, err := pathVar(r, "bucketName")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp = forwardedResponseIfBucketIsRemote(s, r, poolName, bucketProjectName, bucketName)
+	if resp != nil {
+		return resp
+	}
+
+	current, err := pool.GetBucketLifecycle(bucketProjectName, bucketName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed getting storage bucket lifecycle: %w", err))
+	}
+
+	err = localUtil.EtagCheck(r, current)
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	// Decode the request.
+	req := api.StorageBucketLifecycle{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = pool.UpdateBucketLifecycle(bucketProjectName, bucketName, req, nil)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed updating storage bucket lifecycle: %w", err))
+	}
+
+	s.Events.SendLifecycle(bucketProjectName, lifecycle.StorageBucketUpdated.Event(pool, bucketProjectName, bucketName, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// applyBucketLifecycleTask applies the lifecycle rules of the buckets served by the built-in S3 server.
+// Buckets on remote pools are left alone as their backend applies the rules itself.
+func applyBucketLifecycleTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		opRun := func(op *operations.Operation) error {
+			return applyBucketLifecycle(ctx, s, time.Now())
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BucketLifecycleApply, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating bucket lifecycle operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Debug("Applying bucket lifecycle rules")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting bucket lifecycle operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed applying bucket lifecycle rules", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Debug("Done applying bucket lifecycle rules")
+	}
+
+	return f, task.Daily()
+}
+
+// applyBucketLifecycle applies the lifecycle rules of the local buckets of this member.
+func applyBucketLifecycle(ctx context.Context, s *state.State, now time.Time) error {
+	var buckets []*db.StorageBucket
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		buckets, err = tx.GetStoragePoolBuckets(ctx, true)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading storage buckets: %w", err)
+	}
+
+	var errs []error
+	for _, bucket := range buckets {
+		pool, err := storagePools.LoadByName(s, bucket.PoolName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed loading storage pool %q: %w", bucket.PoolName, err))
+			continue
+		}
+
+		if pool.Driver().Info().Remote {
+			continue
+		}
+
+		err = applyLocalBucketLifecycle(pool, bucket, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed applying lifecycle of bucket %q in project %q: %w", bucket.Name, bucket.Project, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyLocalBucketLifecycle applies the lifecycle rules of a single local bucket.
+func applyLocalBucketLifecycle(pool storagePools.Pool, bucket *db.StorageBucket, now time.Time) error {
+	bucketDir, unmount, err := pool.MountLocalBucket(bucket.Project, bucket.Name, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = unmount() }()
+
+	srv := local.NewServer(bucketDir, nil)
+	srv.Versioning = localBucketVersioning(bucket.Config)
+
+	return srv.ApplyLifecycle(now)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/db/warningtype"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	storagePools "github.com/lxc/incus/v7/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v7/internal/server/storage/drivers"
+	"github.com/lxc/incus/v7/internal/server/task"
+	"github.com/lxc/incus/v7/internal/server/warnings"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// storagePoolMetadataWarningThreshold is the share of thin pool metadata space above which a warning is raised.
+const storagePoolMetadataWarningThreshold = 0.8
+
+// storagePoolsLoadAvailable returns the created storage pools which are available on the local member.
+func storagePoolsLoadAvailable(ctx context.Context, s *state.State) ([]storagePools.Pool, error) {
+	var poolNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+		return err
+	})
+	if err != nil && !response.IsNotFoundError(err) {
+		return nil, fmt.Errorf("Failed loading storage pools: %w", err)
+	}
+
+	pools := make([]storagePools.Pool, 0, len(poolNames))
+	for _, poolName := range poolNames {
+		if !storagePools.IsAvailable(poolName) {
+			continue
+		}
+
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+// storagePoolRecordWarning raises a warning of the given type for the pool if the message isn't empty and resolves it otherwise.
+func storagePoolRecordWarning(ctx context.Context, s *state.State, pool storagePools.Pool, warningType warningtype.Type, message string) {
+	l := logger.AddContext(logger.Ctx{"pool": pool.Name(), "warning": warningtype.TypeNames[warningType]})
+
+	if message == "" {
+		err := warnings.ResolveWarningsByNodeAndProjectAndTypeAndEntity(s.DB.Cluster, s.ServerName, "", warningType, cluster.TypeStoragePool, int(pool.ID()))
+		if err != nil {
+			l.Warn("Failed resolving storage pool warning", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarning(ctx, s.ServerName, "", cluster.TypeStoragePool, int(pool.ID()), warningType, message)
+	})
+	if err != nil {
+		l.Warn("Failed recording storage pool warning", logger.Ctx{"err": err})
+	}
+}
+
+// storagePoolHealthWarnings returns the messages of the degraded and low metadata space warnings matching the
+// health of a pool, empty when the warning doesn't apply.
+func storagePoolHealthWarnings(health *api.ResourcesStoragePoolHealth) (string, string) {
+	degraded := ""
+	if health.Status != api.StoragePoolHealthHealthy {
+		degraded = fmt.Sprintf("Storage pool is %s", health.Status)
+		if health.Message != "" {
+			degraded = fmt.Sprintf("%s: %s", degraded, health.Message)
+		}
+	}
+
+	metadataLow := ""
+	if health.Metadata != nil && health.Metadata.Total > 0 {
+		usage := float64(health.Metadata.Used) / float64(health.Metadata.Total)
+		if usage >= storagePoolMetadataWarningThreshold {
+			metadataLow = fmt.Sprintf("Thin pool metadata space is %.0f%% used", usage*100)
+		}
+	}
+
+	return degraded, metadataLow
+}
+
+func storagePoolHealthTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		pools, err := storagePoolsLoadAvailable(ctx, s)
+		if err != nil {
+			logger.Error("Failed getting storage pools for health check", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, pool := range pools {
+			health, err := pool.GetHealth()
+			if err != nil {
+				if !errors.Is(err, storageDrivers.ErrNotSupported) {
+					logger.Warn("Failed getting storage pool health", logger.Ctx{"pool": pool.Name(), "err": err})
+				}
+
+				continue
+			}
+
+			degraded, metadataLow := storagePoolHealthWarnings(health)
+			if degraded != "" {
+				logger.Warn("Storage pool not healthy", logger.Ctx{"pool": pool.Name(), "status": health.Status, "message": health.Message})
+			}
+
+			storagePoolRecordWarning(ctx, s, pool, warningtype.StoragePoolDegraded, degraded)
+			storagePoolRecordWarning(ctx, s, pool, warningtype.StoragePoolMetadataLow, metadataLow)
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+func autoScrubStoragePoolsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		allPools, err := storagePoolsLoadAvailable(ctx, s)
+		if err != nil {
+			logger.Error("Failed getting storage pools for scrub task", logger.Ctx{"err": err})
+			return
+		}
+
+		// Get the pools that are due to be scrubbed.
+		var pools []storagePools.Pool
+		for _, pool := range allPools {
+			schedule := pool.Driver().Config()["scrub.schedule"]
+			if schedule == "" || !snapshotIsScheduledNow(schedule, pool.ID()) {
+				continue
+			}
+
+			pools = append(pools, pool)
+		}
+
+		if len(pools) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			var errs []error
+
+			for _, pool := range pools {
+				l := logger.AddContext(logger.Ctx{"pool": pool.Name()})
+				l.Info("Scrubbing storage pool")
+
+				err := pool.Scrub(op)
+				if err != nil {
+					l.Error("Failed scrubbing storage pool", logger.Ctx{"err": err})
+					errs = append(errs, fmt.Errorf("Failed scrubbing storage pool %q: %w", pool.Name(), err))
+					continue
+				}
+
+				l.Info("Done scrubbing storage pool")
+			}
+
+			return errors.Join(errs...)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.StoragePoolScrub, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating storage pool scrub operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting storage pool scrub operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled storage pool scrub", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/instance"
 	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/replication"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/state"
@@ -571,6 +572,11 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Don't disclose the backup target credentials, including through filtering.
+	for i := range dbVolumes {
+		dbVolumes[i].Config = backup.HideTargetSecret(dbVolumes[i].Config)
+	}
+
 	// Filter the results.
 	dbVolumes, err = filterVolumes(dbVolumes, clauses, allProjects, projectImages)
 	if err != nil {
@@ -939,6 +945,11 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.Conflict(errors.New("Volume by that name already exists"))
 	}
 
+	// Refuse to replicate onto a volume which has been promoted.
+	if dbVolume != nil && req.Config[replication.KeySource] != "" && dbVolume.Config[replication.KeySource] == "" {
+		return response.Conflict(fmt.Errorf("Volume %q isn't a replica, it may have been promoted", req.Name))
+	}
+
 	// Check if we need to switch to migration
 	serverName := s.ServerName
 	var nodeAddress string
@@ -2127,6 +2138,7 @@ func storagePoolVolumeGet(d *Daemon, r *http.Request) response.Response {
 
 	dbVolume.UsedBy = project.FilterUsedBy(s.Authorizer, r, volumeUsedBy)
 	etag := []any{volumeName, dbVolume.Type, dbVolume.Config}
+	dbVolume.Config = backup.HideTargetSecret(dbVolume.Config)
 
 	// Prepare the response.
 	if localUtil.IsRecursionRequest(r) {
@@ -2188,7 +2200,7 @@ func getVolumeFull(ctx context.Context, s *state.State, poolName string, vol api
 			_, snapName, _ := api.GetParentAndSnapshotName(entry.Name)
 
 			snap := api.StorageVolumeSnapshot{}
-			snap.Config = entry.Config
+			snap.Config = backup.HideTargetSecret(entry.Config)
 			snap.Description = entry.Description
 			snap.Name = snapName
 			snap.CreatedAt = entry.CreationDate
@@ -2244,6 +2256,10 @@ func getVolumeFull(ctx context.Context, s *state.State, poolName string, vol api
 		}
 	}
 
+	if volType == db.StoragePoolVolumeTypeCustom {
+		volState.Replication = replication.State(vol.Config, time.Now())
+	}
+
 	resp.State = &volState
 
 	return &resp, nil
@@ -2383,6 +2399,8 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	backup.KeepTargetSecret(req.Config, dbVolume.Config)
+
 	// Use an empty operation for this sync response to pass the requestor
 	op := &operations.Operation{}
 	op.SetRequestor(r)
@@ -2583,6 +2601,8 @@ func storagePoolVolumePatch(d *Daemon, r *http.Request) response.Response {
 		req.Config = map[string]string{}
 	}
 
+	backup.KeepTargetSecret(req.Config, dbVolume.Config)
+
 	// Merge current config with requested changes.
 	for k, v := range dbVolume.Config {
 		_, ok := req.Config[k]
@@ -2854,6 +2874,18 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 	reverter := revert.New()
 	defer reverter.Fail()
 
+	// Read the backup from a repository if requested.
+	if r.Header.Get("X-Incus-repository-backup") != "" {
+		reader, err := backupRepositoryReader(s, r, data)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		defer func() { _ = reader.Close() }()
+
+		data = reader
+	}
+
 	// Create temporary file to store uploaded backup data.
 	backupFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
 	if err != nil {
@@ -2921,6 +2953,21 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		backupFile = tarFile
 	}
 
+	// Rebuild the full backup when importing a backup chain.
+	if util.IsTrue(r.Header.Get("X-Incus-chain")) {
+		rebuiltFile, err := backupRebuildChain(s, backupFile)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// We don't need the backup chain anymore.
+		_ = backupFile.Close()
+		_ = os.Remove(backupFile.Name())
+
+		// Replace the backup file handle with the handle to the rebuilt backup.
+		backupFile = rebuiltFile
+	}
+
 	// Parse the backup information.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
@@ -2933,6 +2980,11 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		return response.BadRequest(err)
 	}
 
+	// Incremental backups can't be restored on their own.
+	if bInfo.Incremental != nil {
+		return response.BadRequest(errors.New("Incremental backups must be imported along with the backup chain they belong to"))
+	}
+
 	bInfo.Project = projectName
 
 	// Override pool.
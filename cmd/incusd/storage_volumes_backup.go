@@ -462,6 +462,54 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		}
 	}
 
+	isRepository, err := backupTargetIsRepository(req.Target)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Backups stored in a repository are kept under the name of the volume.
+	if isRepository {
+		err = backupRepositoryCheckAccess(s, r, req.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if req.Name == "" {
+			req.Name = "backup-" + time.Now().UTC().Format("20060102-150405")
+		}
+
+		if strings.Contains(req.Name, "/") {
+			return response.BadRequest(errors.New("Backup names may not contain slashes"))
+		}
+
+		repoName := backupRepositoryName(req.Name, "volumes", projectName, poolName, volumeName)
+
+		backup := func(op *operations.Operation) error {
+			// Compression would defeat the deduplication, the repository compresses the data itself.
+			args := db.StoragePoolVolumeBackup{
+				VolumeID:             dbVolume.ID,
+				CreationDate:         time.Now(),
+				VolumeOnly:           req.VolumeOnly,
+				CompressionAlgorithm: "none",
+				IncrementalFrom:      req.IncrementalFrom,
+			}
+
+			return backupRepositoryWrite(context.Background(), req.Target, repoName, func(writer *io.PipeWriter) error {
+				return volumeBackupCreate(s, args, projectName, poolName, volumeName, writer)
+			})
+		}
+
+		resources := map[string][]api.URL{}
+		resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName)}
+
+		op, err := operations.OperationCreate(s, request.ProjectParam(r), operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, resources, nil, backup, nil, nil, r)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		return operations.OperationResponse(op)
+	}
+
 	var reader *io.PipeReader
 	var writer *io.PipeWriter
 	var fullName string
@@ -531,6 +579,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			VolumeOnly:           req.VolumeOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			IncrementalFrom:      req.IncrementalFrom,
 		}
 
 		if !direct && req.Target == nil {
@@ -16,6 +16,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v7/internal/instance"
 	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/backup"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
 	"github.com/lxc/incus/v7/internal/server/db/operationtype"
@@ -548,7 +549,7 @@ func storagePoolVolumeSnapshotsTypeGet(d *Daemon, r *http.Request) response.Resp
 			vol.UsedBy = project.FilterUsedBy(s.Authorizer, r, volumeUsedBy)
 
 			tmp := &api.StorageVolumeSnapshot{}
-			tmp.Config = vol.Config
+			tmp.Config = backup.HideTargetSecret(vol.Config)
 			tmp.Description = vol.Description
 			tmp.Name = vol.Name
 			tmp.CreatedAt = vol.CreatedAt
@@ -881,7 +882,7 @@ func storagePoolVolumeSnapshotTypeGet(d *Daemon, r *http.Request) response.Respo
 	}
 
 	snapshot := api.StorageVolumeSnapshot{}
-	snapshot.Config = dbVolume.Config
+	snapshot.Config = backup.HideTargetSecret(dbVolume.Config)
 	snapshot.Description = dbVolume.Description
 	snapshot.Name = snapshotName
 	snapshot.ExpiresAt = &expiry
@@ -1,15 +1,18 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/lxc/incus/v7/internal/server/auth"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/instance"
 	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/replication"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
@@ -135,7 +138,20 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 
 	// Fetch the current usage.
 	var usage *storagePools.VolumeUsage
+	var replicationState *api.ReplicationState
 	if volumeType == db.StoragePoolVolumeTypeCustom {
+		// Replication state.
+		var dbVolume *db.StorageVolume
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, volumeType, volumeName, true)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		replicationState = replication.State(dbVolume.Config, time.Now())
+
 		// Custom volumes.
 		usage, err = pool.GetCustomVolumeUsage(projectName, volumeName)
 		if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
@@ -164,7 +180,9 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 	}
 
 	// Prepare the state struct.
-	state := api.StorageVolumeState{}
+	state := api.StorageVolumeState{
+		Replication: replicationState,
+	}
 
 	if usage != nil {
 		state.Usage = &api.StorageVolumeStateUsage{}
@@ -3,6 +3,8 @@ package instance
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -36,6 +38,34 @@ func isResolvConfValue(value string) error {
 	return nil
 }
 
+// isProbeAddress validates a probe address in the form `[HOST]:PORT`.
+func isProbeAddress(value string) error {
+	_, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return err
+	}
+
+	return validate.IsNetworkPort(port)
+}
+
+// isProbeURL validates a probe URL, allowing the host to be omitted.
+func isProbeURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("Only HTTP and HTTPS URLs are supported")
+	}
+
+	if u.Port() != "" {
+		return validate.IsNetworkPort(u.Port())
+	}
+
+	return nil
+}
+
 // ConfigVolatilePrefix indicates the prefix used for volatile config keys.
 const ConfigVolatilePrefix = "volatile."
 
@@ -47,6 +77,134 @@ var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// gendoc:generate(entity=instance, group=backups, key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	//
+	// Scheduled backups are stored on the server unless {config:option}`instance-backups:backups.target.url` is set, in which case they are uploaded to the backup target instead.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.schedule.stopped)
+	//
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether to automatically back up stopped instances
+	"backups.schedule.stopped": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	//
+	// This value is used to compute the expiry date of scheduled backups stored on the server.
+	// It doesn't apply to backups uploaded to a backup target.
+	//
+	// See {config:option}`instance-snapshots:snapshots.expiry` for the supported units.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: When scheduled backups are to be deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.instance_only)
+	//
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether to leave instance snapshots out of scheduled backups
+	"backups.instance_only": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.retention.last)
+	// The most recent scheduled backups are kept, older ones get deleted.
+	// Retention applies to backups stored on the server as well as to those on the backup target.
+	//
+	// When no `backups.retention.*` key is set, all scheduled backups are kept.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of most recent scheduled backups to keep
+	"backups.retention.last": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.retention.daily)
+	// The most recent scheduled backup of each of the given number of most recent days (with a backup) is kept.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of daily scheduled backups to keep
+	"backups.retention.daily": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.retention.weekly)
+	// The most recent scheduled backup of each of the given number of most recent weeks (with a backup) is kept.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of weekly scheduled backups to keep
+	"backups.retention.weekly": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target.format)
+	// Specify `tarball` to upload each scheduled backup as a standalone export file, or `repository` to store
+	// scheduled backups in a deduplicating backup repository, where the data shared between backups is only stored once.
+	//
+	// Repositories are kept in the S3 bucket when {config:option}`instance-backups:backups.target.url` is set,
+	// otherwise on the server, in the repository named by {config:option}`instance-backups:backups.target.path` (`default` if empty).
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  liveupdate: no
+	//  shortdesc: How scheduled backups are stored on the backup target
+	"backups.target.format": validate.Optional(validate.IsOneOf("tarball", "repository")),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target.url)
+	// URL of the S3 server to upload scheduled backups to, for example `https://s3.example.net`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: S3 server for scheduled backups
+	"backups.target.url": validate.Optional(validate.IsRequestURL),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: S3 bucket for scheduled backups
+	"backups.target.bucket": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target.path)
+	// Backups are stored as `<path>/instances/<project>/<instance>/<date>.backup` in the bucket.
+	// Repositories are stored under `<path>` in the bucket.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+	"backups.target.path": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: S3 access key for scheduled backups
+	"backups.target.access_key": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: S3 secret key for scheduled backups
+	"backups.target.secret_key": validate.IsAny,
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.autorestart)
 	// If set to `true` will attempt up to 10 restarts over a 1 minute period upon unexpected instance exit.
 	// ---
@@ -82,6 +240,61 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on)
+	// Comma-separated list of instances (in the same project) that must be running and ready
+	// before this instance is started. Stopped dependencies are started first.
+	// When the host shuts down, this instance is stopped before its dependencies.
+	//
+	// The instances must already exist when the key is set and dependency cycles are rejected.
+	// Instances listed as a dependency can't be deleted or renamed.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances that must be ready before this one is started
+	"boot.depends_on": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.ready.probe)
+	// How to determine that the instance is ready for its dependents to be started.
+	//
+	// Valid values are:
+	//   - `agent`: Wait for the instance to report itself as ready (through the guest API or the agent)
+	//   - `tcp`: Wait for a TCP connection to `boot.ready.address` to succeed
+	//   - `exec`: Wait for `boot.ready.command` to succeed inside of the instance
+	//
+	// When unset, the instance is considered ready as soon as it's running.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: How to check that the instance is ready
+	"boot.ready.probe": validate.Optional(validate.IsOneOf("agent", "tcp", "exec")),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.ready.address)
+	// Address and port to connect to when `boot.ready.probe` is set to `tcp`.
+	// If the address is omitted (`:PORT`), the first global address of the instance is used.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Address to connect to for the `tcp` readiness probe
+	"boot.ready.address": validate.Optional(isProbeAddress),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.ready.command)
+	// Command to run inside of the instance when `boot.ready.probe` is set to `exec`.
+	// The instance is considered ready once the command exits with a zero return code.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Command to run for the `exec` readiness probe
+	"boot.ready.command": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.ready.timeout)
+	// Number of seconds dependent instances wait for this instance to become ready.
+	// ---
+	//  type: integer
+	//  defaultdesc: 120
+	//  liveupdate: yes
+	//  shortdesc: How long to wait for the instance to become ready
+	"boot.ready.timeout": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -199,6 +412,95 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "refresh-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.type)
+	// Type of health check to run against the instance while it's running.
+	//
+	// Valid values are:
+	//   - `exec`: Run `healthcheck.command` inside of the instance and expect a zero return code
+	//   - `tcp`: Connect to `healthcheck.address`
+	//   - `http`: Send a `GET` request to `healthcheck.url` and expect a `2xx` or `3xx` response
+	//
+	// When unset, no health check is performed.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Type of health check
+	"healthcheck.type": validate.Optional(validate.IsOneOf("exec", "tcp", "http")),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.command)
+	// Command to run inside of the instance for the `exec` health check.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Command to run for the `exec` health check
+	"healthcheck.command": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.address)
+	// Address and port to connect to for the `tcp` health check.
+	// If the address is omitted (`:PORT`), the first global address of the instance is used.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Address to connect to for the `tcp` health check
+	"healthcheck.address": validate.Optional(isProbeAddress),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.url)
+	// URL to query for the `http` health check.
+	// If the host is omitted (for example `http://:8080/health`), the first global address of the instance is used.
+	// Certificates of HTTPS endpoints aren't verified.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: URL to query for the `http` health check
+	"healthcheck.url": validate.Optional(isProbeURL),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.interval)
+	// Number of seconds between two health checks.
+	// ---
+	//  type: integer
+	//  defaultdesc: 30
+	//  liveupdate: yes
+	//  shortdesc: How often to run the health check
+	"healthcheck.interval": validate.Optional(validate.IsInRange(10, 86400)),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.timeout)
+	// Number of seconds after which a health check is considered failed.
+	// ---
+	//  type: integer
+	//  defaultdesc: 5
+	//  liveupdate: yes
+	//  shortdesc: How long to wait for the health check to complete
+	"healthcheck.timeout": validate.Optional(validate.IsInRange(1, 3600)),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.retries)
+	// Number of consecutive failed health checks after which the instance is considered unhealthy.
+	// ---
+	//  type: integer
+	//  defaultdesc: 3
+	//  liveupdate: yes
+	//  shortdesc: Number of failures before the instance is unhealthy
+	"healthcheck.retries": validate.Optional(validate.IsInRange(1, 100)),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.action)
+	// Action to take when the instance becomes unhealthy.
+	//
+	// Valid values are:
+	//   - `none`: Only record a warning and emit a lifecycle event
+	//   - `restart`: Restart the instance
+	//   - `stop`: Stop the instance
+	//   - `evacuate`: Move the instance to another cluster member and start it there
+	//     (restarts the instance in place when no other cluster member is available)
+	//
+	// The instance stays unhealthy until a check succeeds. Until then, the action is run again
+	// each time `healthcheck.retries` more checks fail, up to 5 times. It waits at least a minute after
+	// the first action and twice as long after each following one.
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: yes
+	//  shortdesc: What to do when the instance becomes unhealthy
+	"healthcheck.action": validate.Optional(validate.IsOneOf("none", "restart", "stop", "evacuate")),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	// For virtual machines, a CPU topology of the form `sockets=2,cores=4,threads=2` may also be provided.
@@ -322,6 +624,51 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Raw idmap configuration
 	"raw.idmap": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=replication, key=replication.target)
+	// URL of the remote server to replicate the instance to, for example `https://dr.example.net:8443`.
+	// The remote server must trust the certificate of this server.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Remote server to replicate the instance to
+	"replication.target": validate.Optional(validate.IsRequestURL),
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.target.certificate)
+	// PEM encoded certificate of the remote server. When unset, the certificate must be trusted by the system.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Certificate of the remote server
+	"replication.target.certificate": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  defaultdesc: same project as the instance
+	//  liveupdate: yes
+	//  shortdesc: Project on the remote server to replicate the instance to
+	"replication.target.project": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`) or a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`).
+	// Each run creates a snapshot of the instance and pushes the changes since the previous run to the target.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: yes
+	//  shortdesc: Schedule for the replication of the instance
+	"replication.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.retention)
+	// The most recent replication snapshot is the base of the next incremental transfer so at least one is always kept.
+	// ---
+	//  type: integer
+	//  defaultdesc: 1
+	//  liveupdate: yes
+	//  shortdesc: Number of replication snapshots to keep
+	"replication.retention": validate.Optional(validate.IsInRange(1, 100)),
+
 	// gendoc:generate(entity=instance, group=security, key=security.guestapi)
 	// See {ref}`dev-incus` for more information.
 	// ---
@@ -549,6 +896,34 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Timestamp of last move by automatic live-migration
 	"volatile.rebalance.last_move": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.last_attempt)
+	//
+	// ---
+	//  type: integer
+	//  shortdesc: Timestamp of the last replication attempt
+	"volatile.replication.last_attempt": validate.Optional(validate.IsInt64),
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.last_error)
+	//
+	// ---
+	//  type: string
+	//  shortdesc: Error returned by the last failed replication
+	"volatile.replication.last_error": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.last_success)
+	//
+	// ---
+	//  type: integer
+	//  shortdesc: Timestamp of the last successful replication
+	"volatile.replication.last_success": validate.Optional(validate.IsInt64),
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.source)
+	// Set on replicas, which can't be started until they are promoted.
+	// ---
+	//  type: string
+	//  shortdesc: Server the replica is received from
+	"volatile.replication.source": validate.IsAny,
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.uuid)
 	// The instance UUID is globally unique across all servers and projects.
 	// ---
@@ -0,0 +1,139 @@
+package instance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// DependsOn returns the list of instances the instance with the given config depends on.
+func DependsOn(config map[string]string) []string {
+	return util.SplitNTrimSpace(config["boot.depends_on"], ",", -1, true)
+}
+
+// DependencyCycle looks for dependency cycles going through the named instance.
+// The graph maps instance names to the names of the instances they depend on.
+// Each instance is only walked once and every dependency leading back to the named instance is reported, so that
+// all the cycles preventing the instance from starting are returned in a single error. Cycles between other
+// instances are left alone as they can't be introduced nor fixed by changing the named instance.
+func DependencyCycle(graph map[string][]string, name string) error {
+	visited := map[string]bool{}
+	path := []string{}
+	cycles := []string{}
+
+	var walk func(current string)
+	walk = func(current string) {
+		visited[current] = true
+		path = append(path, current)
+
+		for _, dep := range graph[current] {
+			// A dependency on the named instance closes a cycle.
+			if dep == name {
+				cycles = append(cycles, strings.Join(append(slices.Clone(path), dep), " -> "))
+				continue
+			}
+
+			if visited[dep] {
+				continue
+			}
+
+			walk(dep)
+		}
+
+		path = path[:len(path)-1]
+	}
+
+	walk(name)
+
+	if len(cycles) == 1 {
+		return fmt.Errorf("Dependency cycle detected: %s", cycles[0])
+	} else if len(cycles) > 1 {
+		return fmt.Errorf("Dependency cycles detected: %s", strings.Join(cycles, "; "))
+	}
+
+	return nil
+}
+
+// DependencyLevels returns the depth of each instance in the dependency graph.
+// Instances without dependencies (within the graph) are at level 0 and every other instance is one level
+// above its deepest dependency. Dependencies that aren't part of the graph are ignored.
+// Instances that are part of a cycle are all placed after the last level of the acyclic part of the graph.
+func DependencyLevels(graph map[string][]string) map[string]int {
+	levels := make(map[string]int, len(graph))
+	visiting := make(map[string]bool, len(graph))
+
+	var level func(name string) int
+	level = func(name string) int {
+		l, ok := levels[name]
+		if ok {
+			return l
+		}
+
+		if visiting[name] {
+			return -1
+		}
+
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		result := 0
+		for _, dep := range graph[name] {
+			_, ok := graph[dep]
+			if !ok {
+				continue
+			}
+
+			depLevel := level(dep)
+			if depLevel < 0 {
+				return -1
+			}
+
+			result = max(result, depLevel+1)
+		}
+
+		levels[name] = result
+
+		return result
+	}
+
+	cyclic := []string{}
+	maxLevel := 0
+	for name := range graph {
+		l := level(name)
+		if l < 0 {
+			cyclic = append(cyclic, name)
+			continue
+		}
+
+		maxLevel = max(maxLevel, l)
+	}
+
+	for _, name := range cyclic {
+		_, ok := levels[name]
+		if !ok {
+			levels[name] = maxLevel + 1
+		}
+	}
+
+	return levels
+}
+
+// ReverseDependencies returns the graph with all the dependency edges inverted.
+// This maps each instance to the instances that depend on it.
+func ReverseDependencies(graph map[string][]string) map[string][]string {
+	reverse := make(map[string][]string, len(graph))
+	for name, deps := range graph {
+		_, ok := reverse[name]
+		if !ok {
+			reverse[name] = []string{}
+		}
+
+		for _, dep := range deps {
+			reverse[dep] = append(reverse[dep], name)
+		}
+	}
+
+	return reverse
+}
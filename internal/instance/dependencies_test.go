@@ -0,0 +1,87 @@
+package instance
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependsOn(t *testing.T) {
+	assert.Nil(t, DependsOn(map[string]string{}))
+	assert.Equal(t, []string{"db", "cache"}, DependsOn(map[string]string{"boot.depends_on": "db, cache"}))
+}
+
+func TestDependencyCycle(t *testing.T) {
+	graph := map[string][]string{
+		"web":   {"db", "cache"},
+		"db":    {"storage"},
+		"cache": {},
+	}
+
+	assert.NoError(t, DependencyCycle(graph, "web"))
+	assert.NoError(t, DependencyCycle(graph, "db"))
+
+	graph["storage"] = []string{"web"}
+	err := DependencyCycle(graph, "web")
+	assert.EqualError(t, err, "Dependency cycle detected: web -> db -> storage -> web")
+
+	graph = map[string][]string{"self": {"self"}}
+	assert.EqualError(t, DependencyCycle(graph, "self"), "Dependency cycle detected: self -> self")
+
+	// Only the cycles going through the instance are reported.
+	graph = map[string][]string{
+		"web":     {"db", "cache"},
+		"db":      {"web"},
+		"cache":   {"queue"},
+		"queue":   {"worker"},
+		"worker":  {"queue", "web"},
+		"unknown": {"unknown"},
+	}
+
+	err = DependencyCycle(graph, "web")
+	assert.EqualError(t, err, "Dependency cycles detected: web -> db -> web; web -> cache -> queue -> worker -> web")
+
+	// Existing cycles between the dependencies of the instance don't prevent changing it.
+	graph["worker"] = []string{"queue"}
+	graph["db"] = nil
+	assert.NoError(t, DependencyCycle(graph, "web"))
+
+	// Shared dependencies are only walked once.
+	graph = map[string][]string{}
+	for i := range 64 {
+		graph[fmt.Sprintf("a%d", i)] = []string{fmt.Sprintf("a%d", i+1), fmt.Sprintf("b%d", i+1)}
+		graph[fmt.Sprintf("b%d", i)] = []string{fmt.Sprintf("a%d", i+1), fmt.Sprintf("b%d", i+1)}
+	}
+
+	assert.NoError(t, DependencyCycle(graph, "a0"))
+}
+
+func TestDependencyLevels(t *testing.T) {
+	graph := map[string][]string{
+		"web":   {"db", "cache"},
+		"db":    {"storage"},
+		"cache": {"remote"},
+		"other": {},
+	}
+
+	levels := DependencyLevels(graph)
+	assert.Equal(t, map[string]int{"web": 1, "db": 0, "cache": 0, "other": 0}, levels)
+
+	graph["storage"] = nil
+	levels = DependencyLevels(graph)
+	assert.Equal(t, map[string]int{"web": 2, "db": 1, "cache": 0, "other": 0, "storage": 0}, levels)
+}
+
+func TestReverseDependencies(t *testing.T) {
+	graph := map[string][]string{
+		"web": {"db"},
+		"api": {"db"},
+		"db":  {},
+	}
+
+	reverse := ReverseDependencies(graph)
+	assert.ElementsMatch(t, []string{"web", "api"}, reverse["db"])
+	assert.Empty(t, reverse["web"])
+	assert.Empty(t, reverse["api"])
+}
@@ -54,10 +54,11 @@ func handleWriter(out io.Writer, hand func(int64, int64)) io.Writer {
 }
 
 // QemuImg runs qemu-img with an AppArmor profile based on the imgPath and dstPath supplied.
+// Reading the backingPaths is also allowed, for images that are part of a backing chain.
 // The first element of the cmd slice is expected to be a priority limiting command (such as nice or prlimit) and
 // will be added as an allowed command to the AppArmor profile. The remaining elements of the cmd slice are
 // expected to be the qemu-img command and its arguments.
-func QemuImg(sysOS *sys.OS, cmd []string, imgPath string, dstPath string, tracker *ioprogress.ProgressTracker) (string, error) {
+func QemuImg(sysOS *sys.OS, cmd []string, imgPath string, dstPath string, tracker *ioprogress.ProgressTracker, backingPaths ...string) (string, error) {
 	// It is assumed that command starts with a program which sets resource limits, like prlimit or nice
 	allowedCmds := []string{"qemu-img", cmd[0]}
 
@@ -89,7 +90,17 @@ func QemuImg(sysOS *sys.OS, cmd []string, imgPath string, dstPath string, tracke
 		}
 	}
 
-	profileName, err := qemuImgProfileLoad(sysOS, imgPath, dstPath, allowedCmdPaths)
+	backingFullPaths := make([]string, 0, len(backingPaths))
+	for _, backingPath := range backingPaths {
+		backingFullPath, err := filepath.EvalSymlinks(backingPath)
+		if err == nil {
+			backingPath = backingFullPath
+		}
+
+		backingFullPaths = append(backingFullPaths, backingPath)
+	}
+
+	profileName, err := qemuImgProfileLoad(sysOS, imgPath, dstPath, allowedCmdPaths, backingFullPaths)
 	if err != nil {
 		return "", fmt.Errorf("Failed to load qemu-img profile: %w", err)
 	}
@@ -122,7 +133,7 @@ func QemuImg(sysOS *sys.OS, cmd []string, imgPath string, dstPath string, tracke
 }
 
 // qemuImgProfileLoad ensures that the qemu-img's policy is loaded into the kernel.
-func qemuImgProfileLoad(sysOS *sys.OS, imgPath string, dstPath string, allowedCmdPaths []string) (string, error) {
+func qemuImgProfileLoad(sysOS *sys.OS, imgPath string, dstPath string, allowedCmdPaths []string, backingPaths []string) (string, error) {
 	name := fmt.Sprintf("<%s>_<%s>", strings.ReplaceAll(strings.Trim(imgPath, "/"), "/", "-"), strings.ReplaceAll(strings.Trim(dstPath, "/"), "/", "-"))
 	profileName := profileName("qemu-img", name)
 	profilePath := filepath.Join(aaPath, "profiles", profileName)
@@ -131,7 +142,7 @@ func qemuImgProfileLoad(sysOS *sys.OS, imgPath string, dstPath string, allowedCm
 		return "", err
 	}
 
-	updated, err := qemuImgProfile(profileName, imgPath, dstPath, allowedCmdPaths)
+	updated, err := qemuImgProfile(profileName, imgPath, dstPath, allowedCmdPaths, backingPaths)
 	if err != nil {
 		return "", err
 	}
@@ -152,13 +163,14 @@ func qemuImgProfileLoad(sysOS *sys.OS, imgPath string, dstPath string, allowedCm
 }
 
 // qemuImgProfile generates the AppArmor profile template from the given destination path.
-func qemuImgProfile(profileName string, imgPath string, dstPath string, allowedCmdPaths []string) (string, error) {
+func qemuImgProfile(profileName string, imgPath string, dstPath string, allowedCmdPaths []string, backingPaths []string) (string, error) {
 	// Render the profile.
 	sb := &strings.Builder{}
 	err := qemuImgProfileTpl.Execute(sb, map[string]any{
 		"name":            profileName,
 		"pathToImg":       imgPath,
 		"dstPath":         dstPath,
+		"backingPaths":    backingPaths,
 		"allowedCmdPaths": allowedCmdPaths,
 		"libraryPath":     strings.Split(os.Getenv("LD_LIBRARY_PATH"), ":"),
 	})
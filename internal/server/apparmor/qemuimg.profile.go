@@ -23,6 +23,9 @@ profile "{{ .name }}" flags=(attach_disconnected,mediate_deleted) {
 {{- end }}
 
   {{ .pathToImg }} rk,
+{{- range $index, $element := .backingPaths}}
+  {{$element}} rk,
+{{- end }}
 
 {{- if .dstPath }}
   {{ .dstPath }} rwk,
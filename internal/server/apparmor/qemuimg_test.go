@@ -3,6 +3,7 @@ package apparmor
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -42,3 +43,21 @@ func TestHandleWriter(t *testing.T) {
 		t.Fatal(output)
 	}
 }
+
+func TestQemuImgProfileBackingPaths(t *testing.T) {
+	profile, err := qemuImgProfile("test", "/var/lib/incus/vm/root.img", "/tmp/export/rootfs.img", []string{"/usr/bin/qemu-img"}, []string{"/var/lib/incus/vm-snapshots/snap0/root.img", "/var/lib/incus/vm-snapshots/snap1/root.img"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rule := range []string{
+		"  /var/lib/incus/vm/root.img rk,\n",
+		"  /var/lib/incus/vm-snapshots/snap0/root.img rk,\n",
+		"  /var/lib/incus/vm-snapshots/snap1/root.img rk,\n",
+		"  /tmp/export/rootfs.img rwk,\n",
+	} {
+		if !strings.Contains(profile, rule) {
+			t.Errorf("Profile is missing rule %q:\n%s", rule, profile)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package backup
+
+import (
+	"archive/tar"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/internal/instancewriter"
+	"github.com/lxc/incus/v7/internal/server/nbd"
+	"github.com/lxc/incus/v7/internal/server/sys"
+)
+
+// Incremental represents the information about the parent of an incremental backup.
+type Incremental struct {
+	// Bitmap is the dirty bitmap the changed blocks were read from. The parent backup must have been made
+	// while the bitmap was already present on the disk.
+	Bitmap string `json:"bitmap" yaml:"bitmap"`
+}
+
+// Extent represents a range of a disk.
+type Extent struct {
+	Offset int64
+	Length int64
+}
+
+// deltaMagic identifies the file holding the changed blocks of an incremental backup.
+const deltaMagic = "INCUSDLT"
+
+// deltaVersion is the version of the delta file format.
+const deltaVersion = 1
+
+// deltaHeaderSize is the size of the header of a delta file (magic, version, reserved and disk size).
+const deltaHeaderSize = 24
+
+// deltaRecordHeaderSize is the size of the header of each changed extent (offset and length).
+const deltaRecordHeaderSize = 16
+
+// deltaEnd is the offset marking the end of a delta file.
+const deltaEnd = math.MaxUint64
+
+// BlockPrefix returns the path prefix of the disk within a backup of the given type. The full disk is stored
+// with an ".img" extension and the changed blocks of an incremental backup with a ".delta" extension.
+func BlockPrefix(backupType Type) (string, error) {
+	switch backupType {
+	case TypeVM:
+		return DefaultBackupPrefix + "/virtual-machine", nil
+	case TypeCustom:
+		return DefaultBackupPrefix + "/volume", nil
+	}
+
+	return "", fmt.Errorf("Incremental backups aren't supported for backups of type %q", backupType)
+}
+
+// ValidateChain checks that the backups form a chain starting with a full backup, each subsequent
+// incremental backup having been made on top of the previous one.
+func ValidateChain(chain []*Info) error {
+	if len(chain) == 0 {
+		return errors.New("The backup chain is empty")
+	}
+
+	full := chain[0]
+	if full.Incremental != nil {
+		return errors.New("The backup chain must start with a full backup")
+	}
+
+	_, err := BlockPrefix(full.Type)
+	if err != nil {
+		return err
+	}
+
+	if full.OptimizedStorage != nil && *full.OptimizedStorage {
+		return errors.New("Optimized backups can't be used as the base of a backup chain")
+	}
+
+	for i, info := range chain[1:] {
+		parent := chain[i]
+
+		if info.Incremental == nil {
+			return fmt.Errorf("Backup %d of the chain isn't an incremental backup", i+1)
+		}
+
+		if info.Type != full.Type || info.Name != full.Name {
+			return fmt.Errorf("Backup %d of the chain is of %s %q rather than %s %q", i+1, info.Type, info.Name, full.Type, full.Name)
+		}
+
+		if !slices.Contains(parent.Bitmaps, info.Incremental.Bitmap) {
+			return fmt.Errorf("Backup %d of the chain tracks changes from bitmap %q which wasn't present when the previous backup was made", i+1, info.Incremental.Bitmap)
+		}
+
+		if info.CreatedAt.IsZero() || !info.CreatedAt.After(parent.CreatedAt) {
+			return fmt.Errorf("Backup %d of the chain wasn't made after the previous backup", i+1)
+		}
+	}
+
+	return nil
+}
+
+// ChangedExtents returns the extents of the disk exported by the NBD client which changed since the dirty
+// bitmap was created.
+func ChangedExtents(client *nbd.Client, bitmap string) ([]Extent, error) {
+	metaContext := nbd.DirtyBitmapContext(bitmap)
+	if !client.HasContext(metaContext) {
+		return nil, fmt.Errorf("Bitmap %q isn't available on the disk", bitmap)
+	}
+
+	dirty, err := client.DirtyExtents(metaContext)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching changed blocks from bitmap %q: %w", bitmap, err)
+	}
+
+	extents := make([]Extent, 0, len(dirty))
+	for _, extent := range dirty {
+		extents = append(extents, Extent{Offset: extent.Offset, Length: extent.Length})
+	}
+
+	return extents, nil
+}
+
+// DeltaWriter is an instance writer replacing the disk of a backup with a delta file holding only its changed
+// blocks. Everything else is passed through to the underlying writer.
+type DeltaWriter struct {
+	instancewriter.InstanceWriter
+
+	prefix  string
+	size    int64
+	extents []Extent
+	src     io.ReaderAt
+	written bool
+}
+
+// NewDeltaWriter returns a DeltaWriter writing the given extents of the disk read from src.
+func NewDeltaWriter(writer instancewriter.InstanceWriter, backupType Type, size int64, extents []Extent, src io.ReaderAt) (*DeltaWriter, error) {
+	prefix, err := BlockPrefix(backupType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeltaWriter{
+		InstanceWriter: writer,
+		prefix:         prefix,
+		size:           size,
+		extents:        extents,
+		src:            src,
+	}, nil
+}
+
+// WriteFileFromReader writes the delta file in place of the disk and passes any other file through.
+func (w *DeltaWriter) WriteFileFromReader(src io.Reader, fi os.FileInfo) error {
+	if fi.Name() != w.prefix+".img" {
+		return w.InstanceWriter.WriteFileFromReader(src, fi)
+	}
+
+	if fi.Size() != w.size {
+		return fmt.Errorf("Disk is of %d bytes rather than the %d bytes tracked by the bitmap", fi.Size(), w.size)
+	}
+
+	deltaReader, deltaWriter := io.Pipe()
+	go func() {
+		_ = deltaWriter.CloseWithError(WriteDelta(deltaWriter, w.size, w.extents, w.src))
+	}()
+
+	defer func() { _ = deltaReader.Close() }()
+
+	err := w.InstanceWriter.WriteFileFromReader(deltaReader, &instancewriter.FileInfo{
+		FileName:    w.prefix + ".delta",
+		FileSize:    DeltaSize(w.extents),
+		FileMode:    fi.Mode(),
+		FileModTime: fi.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.written = true
+
+	return nil
+}
+
+// Written returns whether the delta file was written.
+func (w *DeltaWriter) Written() bool {
+	return w.written
+}
+
+// DeltaSize returns the size of the delta file holding the given extents.
+func DeltaSize(extents []Extent) int64 {
+	size := int64(deltaHeaderSize + deltaRecordHeaderSize)
+	for _, extent := range extents {
+		size += deltaRecordHeaderSize + extent.Length
+	}
+
+	return size
+}
+
+// WriteDelta writes the given extents of the disk read from src as a delta file.
+func WriteDelta(w io.Writer, size int64, extents []Extent, src io.ReaderAt) error {
+	header := append([]byte(deltaMagic), make([]byte, deltaHeaderSize-len(deltaMagic))...)
+	binary.BigEndian.PutUint32(header[8:], deltaVersion)
+	binary.BigEndian.PutUint64(header[16:], uint64(size))
+
+	_, err := w.Write(header)
+	if err != nil {
+		return err
+	}
+
+	record := make([]byte, deltaRecordHeaderSize)
+	for _, extent := range extents {
+		if extent.Offset < 0 || extent.Length < 0 || extent.Offset+extent.Length > size {
+			return fmt.Errorf("Extent of %d bytes at offset %d is beyond the end of the disk", extent.Length, extent.Offset)
+		}
+
+		binary.BigEndian.PutUint64(record[0:], uint64(extent.Offset))
+		binary.BigEndian.PutUint64(record[8:], uint64(extent.Length))
+
+		_, err = w.Write(record)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(w, io.NewSectionReader(src, extent.Offset, extent.Length))
+		if err != nil {
+			return fmt.Errorf("Failed copying extent of %d bytes at offset %d: %w", extent.Length, extent.Offset, err)
+		}
+	}
+
+	binary.BigEndian.PutUint64(record[0:], deltaEnd)
+	binary.BigEndian.PutUint64(record[8:], 0)
+
+	_, err = w.Write(record)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ApplyDelta writes the changed blocks read from the delta file onto the disk of the given size.
+func ApplyDelta(r io.Reader, dst io.WriterAt, size int64) error {
+	header := make([]byte, deltaHeaderSize)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return fmt.Errorf("Failed reading delta header: %w", err)
+	}
+
+	if string(header[:len(deltaMagic)]) != deltaMagic {
+		return errors.New("Invalid delta header")
+	}
+
+	version := binary.BigEndian.Uint32(header[8:])
+	if version != deltaVersion {
+		return fmt.Errorf("Unsupported delta version %d", version)
+	}
+
+	deltaSize := int64(binary.BigEndian.Uint64(header[16:]))
+	if deltaSize != size {
+		return fmt.Errorf("Incremental backup is of a disk of %d bytes rather than %d bytes", deltaSize, size)
+	}
+
+	record := make([]byte, deltaRecordHeaderSize)
+	for {
+		_, err = io.ReadFull(r, record)
+		if err != nil {
+			return fmt.Errorf("Failed reading delta record: %w", err)
+		}
+
+		offset := binary.BigEndian.Uint64(record[0:])
+		length := binary.BigEndian.Uint64(record[8:])
+
+		if offset == deltaEnd {
+			return nil
+		}
+
+		if offset > uint64(size) || length > uint64(size)-offset {
+			return fmt.Errorf("Extent of %d bytes at offset %d is beyond the end of the disk", length, offset)
+		}
+
+		_, err = io.CopyN(io.NewOffsetWriter(dst, int64(offset)), r, int64(length))
+		if err != nil {
+			return fmt.Errorf("Failed applying extent of %d bytes at offset %d: %w", length, offset, err)
+		}
+	}
+}
+
+// RebuildChain reads a tar archive holding a full backup followed by its incremental backups, in order, and
+// writes the full backup matching the last incremental one as an uncompressed tarball. Temporary files are
+// created in workDir.
+//
+// The rebuilt backup is made of the disk of the full backup with the changed blocks applied on top of it and
+// of everything else from the last incremental backup. As such, it doesn't include any snapshot.
+func RebuildChain(r io.Reader, sysOS *sys.OS, workDir string, w io.Writer) error {
+	// Extract the backups of the chain.
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	chainReader := tar.NewReader(r)
+	for {
+		hdr, err := chainReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed reading backup chain: %w", err)
+		}
+
+		f, err := os.CreateTemp(workDir, fmt.Sprintf("%s_chain_", WorkingDirPrefix))
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+
+		_, err = io.Copy(f, chainReader)
+		if err != nil {
+			return fmt.Errorf("Failed extracting backup %q of the chain: %w", hdr.Name, err)
+		}
+	}
+
+	if len(files) < 2 {
+		return errors.New("A backup chain must hold a full backup followed by at least one incremental backup")
+	}
+
+	chain := make([]*Info, 0, len(files))
+	for _, f := range files {
+		info, err := GetInfo(f, sysOS, f.Name())
+		if err != nil {
+			return err
+		}
+
+		chain = append(chain, info)
+	}
+
+	err := ValidateChain(chain)
+	if err != nil {
+		return err
+	}
+
+	prefix, err := BlockPrefix(chain[0].Type)
+	if err != nil {
+		return err
+	}
+
+	// Extract the disk of the full backup.
+	disk, err := os.CreateTemp(workDir, fmt.Sprintf("%s_disk_", WorkingDirPrefix))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = disk.Close()
+		_ = os.Remove(disk.Name())
+	}()
+
+	var diskSize int64
+	found := false
+	err = forEachBackupFile(files[0], sysOS, func(hdr *tar.Header, tr *tar.Reader) error {
+		if hdr.Name != prefix+".img" {
+			return nil
+		}
+
+		found = true
+		diskSize = hdr.Size
+		_, err := io.Copy(disk, tr)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed extracting disk of the full backup: %w", err)
+	}
+
+	if !found {
+		return fmt.Errorf("Full backup is missing %q", prefix+".img")
+	}
+
+	// Apply the changed blocks of each incremental backup.
+	for i, f := range files[1:] {
+		found = false
+		err = forEachBackupFile(f, sysOS, func(hdr *tar.Header, tr *tar.Reader) error {
+			if hdr.Name != prefix+".delta" {
+				return nil
+			}
+
+			found = true
+
+			return ApplyDelta(tr, disk, diskSize)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed applying backup %d of the chain: %w", i+1, err)
+		}
+
+		if !found {
+			return fmt.Errorf("Backup %d of the chain is missing %q", i+1, prefix+".delta")
+		}
+	}
+
+	// Write the rebuilt backup.
+	tw := tar.NewWriter(w)
+
+	err = forEachBackupFile(files[len(files)-1], sysOS, func(hdr *tar.Header, tr *tar.Reader) error {
+		if hdr.Name == prefix+".delta" {
+			return nil
+		}
+
+		if hdr.Name == backupIndexPath {
+			info := *chain[len(chain)-1]
+			info.Incremental = nil
+
+			indexData, err := yaml.Dump(&info, yaml.WithV2Defaults())
+			if err != nil {
+				return err
+			}
+
+			hdr.Size = int64(len(indexData))
+
+			err = tw.WriteHeader(hdr)
+			if err != nil {
+				return err
+			}
+
+			_, err = tw.Write(indexData)
+
+			return err
+		}
+
+		err := tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tw, tr)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed writing rebuilt backup: %w", err)
+	}
+
+	_, err = disk.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     prefix + ".img",
+		Size:     diskSize,
+		Mode:     0o600,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, disk)
+	if err != nil {
+		return fmt.Errorf("Failed writing rebuilt disk: %w", err)
+	}
+
+	return tw.Close()
+}
+
+// forEachBackupFile calls the handler for each file of the backup tarball.
+func forEachBackupFile(f *os.File, sysOS *sys.OS, handler func(hdr *tar.Header, tr *tar.Reader) error) error {
+	tr, cancelFunc, err := TarReader(f, sysOS, f.Name())
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = handler(hdr, tr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
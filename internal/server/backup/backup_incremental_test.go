@@ -0,0 +1,219 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/internal/instancewriter"
+)
+
+// writerAt is an in-memory io.WriterAt.
+type writerAt []byte
+
+func (w writerAt) WriteAt(p []byte, off int64) (int, error) {
+	return copy(w[off:], p), nil
+}
+
+func TestDelta(t *testing.T) {
+	src := bytes.Repeat([]byte("abcdefgh"), 4)
+	extents := []Extent{{Offset: 2, Length: 3}, {Offset: 16, Length: 8}}
+
+	var delta bytes.Buffer
+	err := WriteDelta(&delta, int64(len(src)), extents, bytes.NewReader(src))
+	require.NoError(t, err)
+	assert.Equal(t, DeltaSize(extents), int64(delta.Len()))
+
+	dst := writerAt(make([]byte, len(src)))
+	err = ApplyDelta(bytes.NewReader(delta.Bytes()), dst, int64(len(src)))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x00\x00cde\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00abcdefgh\x00\x00\x00\x00\x00\x00\x00\x00"), []byte(dst))
+
+	// The disk size must match.
+	err = ApplyDelta(bytes.NewReader(delta.Bytes()), dst, int64(len(src))+1)
+	assert.Error(t, err)
+
+	// Truncated deltas are rejected.
+	err = ApplyDelta(bytes.NewReader(delta.Bytes()[:delta.Len()-1]), dst, int64(len(src)))
+	assert.Error(t, err)
+
+	// Extents must be within the disk.
+	err = WriteDelta(io.Discard, 8, []Extent{{Offset: 4, Length: 8}}, bytes.NewReader(src))
+	assert.Error(t, err)
+}
+
+func TestDeltaWriter(t *testing.T) {
+	disk := []byte("0123456789abcdef")
+	extents := []Extent{{Offset: 4, Length: 4}}
+
+	var buf bytes.Buffer
+	tarWriter := instancewriter.NewInstanceTarWriter(&buf, nil)
+
+	w, err := NewDeltaWriter(tarWriter, TypeVM, int64(len(disk)), extents, bytes.NewReader(disk))
+	require.NoError(t, err)
+
+	config := []byte("config")
+	err = w.WriteFileFromReader(bytes.NewReader(config), &instancewriter.FileInfo{FileName: "backup/virtual-machine/backup.yaml", FileSize: int64(len(config)), FileMode: 0o600})
+	require.NoError(t, err)
+	assert.False(t, w.Written())
+
+	err = w.WriteFileFromReader(bytes.NewReader(disk), &instancewriter.FileInfo{FileName: "backup/virtual-machine.img", FileSize: int64(len(disk)), FileMode: 0o600})
+	require.NoError(t, err)
+	assert.True(t, w.Written())
+	require.NoError(t, tarWriter.Close())
+
+	names := []string{}
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+
+		if hdr.Name == "backup/virtual-machine.delta" {
+			assert.Equal(t, DeltaSize(extents), hdr.Size)
+
+			dst := writerAt(make([]byte, len(disk)))
+			require.NoError(t, ApplyDelta(tr, dst, int64(len(disk))))
+			assert.Equal(t, []byte("\x00\x00\x00\x004567\x00\x00\x00\x00\x00\x00\x00\x00"), []byte(dst))
+		}
+	}
+
+	assert.Equal(t, []string{"backup/virtual-machine/backup.yaml", "backup/virtual-machine.delta"}, names)
+
+	// The disk must be of the size tracked by the bitmap.
+	w, err = NewDeltaWriter(tarWriter, TypeVM, int64(len(disk))+1, extents, bytes.NewReader(disk))
+	require.NoError(t, err)
+	err = w.WriteFileFromReader(bytes.NewReader(disk), &instancewriter.FileInfo{FileName: "backup/virtual-machine.img", FileSize: int64(len(disk)), FileMode: 0o600})
+	assert.Error(t, err)
+}
+
+func TestValidateChain(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	full := &Info{Name: "vm", Type: TypeVM, CreatedAt: createdAt, Bitmaps: []string{"bitmap0"}}
+	inc1 := &Info{Name: "vm", Type: TypeVM, CreatedAt: createdAt.Add(time.Hour), Bitmaps: []string{"bitmap0", "bitmap1"}, Incremental: &Incremental{Bitmap: "bitmap0"}}
+	inc2 := &Info{Name: "vm", Type: TypeVM, CreatedAt: createdAt.Add(2 * time.Hour), Incremental: &Incremental{Bitmap: "bitmap1"}}
+
+	assert.NoError(t, ValidateChain([]*Info{full, inc1, inc2}))
+	assert.NoError(t, ValidateChain([]*Info{full, inc1}))
+
+	// Chains must start with a full backup.
+	assert.Error(t, ValidateChain([]*Info{inc1, inc2}))
+
+	// The bitmap must have been present in the parent.
+	assert.Error(t, ValidateChain([]*Info{full, inc2}))
+
+	// Backups must be in order.
+	assert.Error(t, ValidateChain([]*Info{full, inc1, inc1}))
+
+	// Backups must all be of the same instance.
+	other := *inc1
+	other.Name = "other"
+	assert.Error(t, ValidateChain([]*Info{full, &other}))
+
+	// Containers can't be backed up incrementally.
+	container := *full
+	container.Type = TypeContainer
+	assert.Error(t, ValidateChain([]*Info{&container}))
+}
+
+// testBackup returns an uncompressed backup tarball with the given index and files.
+func testBackup(t *testing.T, info *Info, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	indexData, err := yaml.Dump(info, yaml.WithV2Defaults())
+	require.NoError(t, err)
+
+	files[backupIndexPath] = indexData
+	for _, name := range []string{backupIndexPath, "backup/virtual-machine/backup.yaml", "backup/virtual-machine.img", "backup/virtual-machine.delta"} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: int64(len(data)), Mode: 0o600}))
+		_, err = tw.Write(data)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func TestRebuildChain(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	disk := []byte("0123456789abcdef")
+
+	delta := func(extents []Extent, data []byte) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, WriteDelta(&buf, int64(len(data)), extents, bytes.NewReader(data)))
+		return buf.Bytes()
+	}
+
+	full := testBackup(t, &Info{Name: "vm", Type: TypeVM, CreatedAt: createdAt, Bitmaps: []string{"bitmap0"}}, map[string][]byte{
+		"backup/virtual-machine/backup.yaml": []byte("old"),
+		"backup/virtual-machine.img":         disk,
+	})
+
+	inc1 := testBackup(t, &Info{Name: "vm", Type: TypeVM, CreatedAt: createdAt.Add(time.Hour), Bitmaps: []string{"bitmap0"}, Incremental: &Incremental{Bitmap: "bitmap0"}}, map[string][]byte{
+		"backup/virtual-machine/backup.yaml": []byte("mid"),
+		"backup/virtual-machine.delta":       delta([]Extent{{Offset: 0, Length: 4}}, []byte("ABCD456789abcdef")),
+	})
+
+	inc2 := testBackup(t, &Info{Name: "vm", Type: TypeVM, CreatedAt: createdAt.Add(2 * time.Hour), Bitmaps: []string{"bitmap0"}, Incremental: &Incremental{Bitmap: "bitmap0"}}, map[string][]byte{
+		"backup/virtual-machine/backup.yaml": []byte("new"),
+		"backup/virtual-machine.delta":       delta([]Extent{{Offset: 2, Length: 2}, {Offset: 12, Length: 4}}, []byte("ABXY456789abWXYZ")),
+	})
+
+	// Bundle the chain.
+	var chain bytes.Buffer
+	tw := tar.NewWriter(&chain)
+	for i, data := range [][]byte{full, inc1, inc2} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: string(rune('0' + i)), Size: int64(len(data)), Mode: 0o600}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+
+	var rebuilt bytes.Buffer
+	err := RebuildChain(&chain, nil, t.TempDir(), &rebuilt)
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(&rebuilt)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		files[hdr.Name] = data
+	}
+
+	assert.Equal(t, []byte("ABXY456789abWXYZ"), files["backup/virtual-machine.img"])
+	assert.Equal(t, []byte("new"), files["backup/virtual-machine/backup.yaml"])
+	assert.NotContains(t, files, "backup/virtual-machine.delta")
+
+	var info Info
+	require.NoError(t, yaml.Load(files[backupIndexPath], &info))
+	assert.Nil(t, info.Incremental)
+	assert.Equal(t, createdAt.Add(2*time.Hour), info.CreatedAt)
+}
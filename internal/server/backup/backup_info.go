@@ -3,6 +3,7 @@ package backup
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"go.yaml.in/yaml/v4"
 
@@ -58,6 +59,9 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             Type           `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	CreatedAt        time.Time      `json:"created_at,omitempty" yaml:"created_at,omitempty"`             // Time at which the backup was made.
+	Bitmaps          []string       `json:"bitmaps,omitempty" yaml:"bitmaps,omitempty"`                   // Dirty bitmaps present on the disk when the backup was made.
+	Incremental      *Incremental   `json:"incremental,omitempty" yaml:"incremental,omitempty"`           // Set on incremental backups.
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
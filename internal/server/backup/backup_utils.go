@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -53,14 +55,119 @@ func Upload(reader *io.PipeReader, req *api.BackupTarget) error {
 	// pipe that's unable to consume anything.
 	defer logger.WarnOnError(reader.Close, "Failed to close reader")
 
+	client, err := TargetClient(req)
+	if err != nil {
+		return err
+	}
+
+	uploader := transfermanager.New(client)
+
+	_, err = uploader.UploadObject(context.Background(), &transfermanager.UploadObjectInput{
+		Bucket: aws.String(req.BucketName),
+		Key:    aws.String(req.Path),
+		Body:   reader,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PruneTarget deletes the backups stored directly under the path of the backup target whose name starts with
+// namePrefix and which aren't covered by the retention policy. The path is treated as a directory and any other
+// object stored in it is left alone.
+func PruneTarget(ctx context.Context, req *api.BackupTarget, namePrefix string, retention Retention) error {
+	if retention.IsEmpty() {
+		return nil
+	}
+
+	client, err := TargetClient(req)
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.TrimSuffix(req.Path, "/") + "/"
+
+	// List the existing backups.
+	backups := map[string]time.Time{}
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(req.BucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed listing backups in %q: %w", prefix, err)
+		}
+
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if !strings.HasPrefix(strings.TrimPrefix(key, prefix), namePrefix) {
+				continue
+			}
+
+			backups[key] = aws.ToTime(obj.LastModified)
+		}
+	}
+
+	// Delete the expired ones.
+	for _, key := range retention.Expired(backups) {
+		_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(req.BucketName),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed deleting backup %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// TargetSecretKeyHidden is the value reported in place of the backups.target.secret_key config key.
+const TargetSecretKeyHidden = "(hidden)"
+
+// HideTargetSecret returns the config with the backup target secret key replaced by a placeholder so that it
+// isn't disclosed to API clients. The passed config is left untouched.
+func HideTargetSecret(config map[string]string) map[string]string {
+	if config["backups.target.secret_key"] == "" {
+		return config
+	}
+
+	config = maps.Clone(config)
+	config["backups.target.secret_key"] = TargetSecretKeyHidden
+
+	return config
+}
+
+// KeepTargetSecret restores the backup target secret key of oldConfig in newConfig when the client sent the
+// placeholder back, as happens when editing the config as returned by the API.
+func KeepTargetSecret(newConfig map[string]string, oldConfig map[string]string) {
+	if newConfig["backups.target.secret_key"] != TargetSecretKeyHidden {
+		return
+	}
+
+	if oldConfig["backups.target.secret_key"] == "" {
+		delete(newConfig, "backups.target.secret_key")
+		return
+	}
+
+	newConfig["backups.target.secret_key"] = oldConfig["backups.target.secret_key"]
+}
+
+// TargetClient returns an S3 client for the backup target.
+func TargetClient(req *api.BackupTarget) (*s3.Client, error) {
 	if req.Protocol != "s3" {
-		return fmt.Errorf("Unsupported backup target protocol %q", req.Protocol)
+		return nil, fmt.Errorf("Unsupported backup target protocol %q", req.Protocol)
 	}
 
 	// Set up an S3 client.
 	uri, err := url.Parse(req.URL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get a basic TLS client.
@@ -85,16 +192,5 @@ func Upload(reader *io.PipeReader, req *api.BackupTarget) error {
 		o.UsePathStyle = true
 	})
 
-	uploader := transfermanager.New(client)
-
-	_, err = uploader.UploadObject(context.Background(), &transfermanager.UploadObjectInput{
-		Bucket: aws.String(req.BucketName),
-		Key:    aws.String(req.Path),
-		Body:   reader,
-	})
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return client, nil
 }
@@ -0,0 +1,45 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHideTargetSecret(t *testing.T) {
+	config := map[string]string{"backups.target.secret_key": "secret", "limits.cpu": "2"}
+
+	hidden := HideTargetSecret(config)
+	assert.Equal(t, TargetSecretKeyHidden, hidden["backups.target.secret_key"])
+	assert.Equal(t, "2", hidden["limits.cpu"])
+
+	// The original config is left untouched.
+	assert.Equal(t, "secret", config["backups.target.secret_key"])
+
+	// Configs without a secret are returned as is.
+	assert.NotContains(t, HideTargetSecret(map[string]string{}), "backups.target.secret_key")
+}
+
+func TestKeepTargetSecret(t *testing.T) {
+	oldConfig := map[string]string{"backups.target.secret_key": "secret"}
+
+	// The placeholder is replaced by the current value.
+	newConfig := map[string]string{"backups.target.secret_key": TargetSecretKeyHidden}
+	KeepTargetSecret(newConfig, oldConfig)
+	assert.Equal(t, "secret", newConfig["backups.target.secret_key"])
+
+	// A new value replaces the current one.
+	newConfig = map[string]string{"backups.target.secret_key": "other"}
+	KeepTargetSecret(newConfig, oldConfig)
+	assert.Equal(t, "other", newConfig["backups.target.secret_key"])
+
+	// A removed key stays removed.
+	newConfig = map[string]string{}
+	KeepTargetSecret(newConfig, oldConfig)
+	assert.NotContains(t, newConfig, "backups.target.secret_key")
+
+	// The placeholder is never stored.
+	newConfig = map[string]string{"backups.target.secret_key": TargetSecretKeyHidden}
+	KeepTargetSecret(newConfig, map[string]string{})
+	assert.NotContains(t, newConfig, "backups.target.secret_key")
+}
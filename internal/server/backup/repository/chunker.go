@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	// chunkMinSize is the size below which no chunk boundary is looked for.
+	chunkMinSize = 512 * 1024
+
+	// chunkAvgSize is the targeted average chunk size.
+	chunkAvgSize = 2 * 1024 * 1024
+
+	// chunkMaxSize is the size at which a chunk is cut regardless of its content.
+	chunkMaxSize = 8 * 1024 * 1024
+)
+
+// Boundary masks, using the top bits of the rolling hash so that they depend on the last 64 bytes.
+// A stricter mask is used before the average size and a looser one after it, which narrows the chunk
+// size distribution around the average (normalized chunking).
+var (
+	chunkMaskSmall = uint64(1<<23-1) << (64 - 23)
+	chunkMaskLarge = uint64(1<<19-1) << (64 - 19)
+)
+
+// gearTable holds the random values of the gear rolling hash.
+// It's generated from a fixed seed as changing it would change all chunk boundaries.
+var gearTable [256]uint64
+
+func init() {
+	seed := uint64(0x696e637573)
+
+	for i := range gearTable {
+		// splitmix64.
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gearTable[i] = z ^ (z >> 31)
+	}
+}
+
+// chunker splits a stream into content-defined chunks, so that the same data produces the same chunks
+// regardless of its offset in the stream.
+type chunker struct {
+	r   io.Reader
+	buf []byte
+	eof bool
+}
+
+// newChunker returns a chunker reading from r.
+func newChunker(r io.Reader) *chunker {
+	return &chunker{r: r, buf: make([]byte, 0, chunkMaxSize)}
+}
+
+// Next returns the next chunk of the stream, or io.EOF once the stream is fully consumed.
+func (c *chunker) Next() ([]byte, error) {
+	// Fill the buffer.
+	if !c.eof && len(c.buf) < chunkMaxSize {
+		n, err := io.ReadFull(c.r, c.buf[len(c.buf):chunkMaxSize])
+		c.buf = c.buf[:len(c.buf)+n]
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, err
+			}
+
+			c.eof = true
+		}
+	}
+
+	if len(c.buf) == 0 {
+		return nil, io.EOF
+	}
+
+	size := cutPoint(c.buf)
+
+	// Keep the chunk in a separate slice and move the remainder to the front of the buffer.
+	chunk := make([]byte, size)
+	copy(chunk, c.buf[:size])
+	c.buf = c.buf[:copy(c.buf, c.buf[size:])]
+
+	return chunk, nil
+}
+
+// cutPoint returns the size of the chunk starting at the beginning of data.
+func cutPoint(data []byte) int {
+	if len(data) <= chunkMinSize {
+		return len(data)
+	}
+
+	end := min(len(data), chunkMaxSize)
+	normal := min(end, chunkAvgSize)
+
+	var hash uint64
+	for i := chunkMinSize; i < normal; i++ {
+		hash = (hash << 1) + gearTable[data[i]]
+		if hash&chunkMaskSmall == 0 {
+			return i + 1
+		}
+	}
+
+	for i := normal; i < end; i++ {
+		hash = (hash << 1) + gearTable[data[i]]
+		if hash&chunkMaskLarge == 0 {
+			return i + 1
+		}
+	}
+
+	return end
+}
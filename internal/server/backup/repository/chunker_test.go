@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testData returns size bytes of reproducible random data.
+func testData(seed int64, size int) []byte {
+	data := make([]byte, size)
+	_, _ = rand.New(rand.NewSource(seed)).Read(data)
+
+	return data
+}
+
+// chunkAll returns the chunks of data.
+func chunkAll(t *testing.T, data []byte) [][]byte {
+	t.Helper()
+
+	chunks := [][]byte{}
+	c := newChunker(bytes.NewReader(data))
+	for {
+		chunk, err := c.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+func TestChunkerSizes(t *testing.T) {
+	data := testData(1, 40*1024*1024)
+	chunks := chunkAll(t, data)
+
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+
+	for i, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), chunkMaxSize)
+		if i < len(chunks)-1 {
+			assert.GreaterOrEqual(t, len(chunk), chunkMinSize)
+		}
+	}
+
+	// Chunks average out around the targeted size.
+	avg := len(data) / len(chunks)
+	assert.Greater(t, avg, chunkAvgSize/2)
+	assert.Less(t, avg, chunkAvgSize*2)
+}
+
+func TestChunkerShift(t *testing.T) {
+	data := testData(2, 40*1024*1024)
+
+	hashes := map[[32]byte]bool{}
+	for _, chunk := range chunkAll(t, data) {
+		hashes[sha256.Sum256(chunk)] = true
+	}
+
+	// Inserting data at the start only changes the first chunks, the others are found at their new offset.
+	shifted := append([]byte("some inserted data"), data...)
+	chunks := chunkAll(t, shifted)
+
+	changed := 0
+	for _, chunk := range chunks {
+		if !hashes[sha256.Sum256(chunk)] {
+			changed++
+		}
+	}
+
+	assert.LessOrEqual(t, changed, 2)
+}
+
+func TestChunkerSmall(t *testing.T) {
+	assert.Empty(t, chunkAll(t, nil))
+
+	data := testData(3, 1000)
+	assert.Equal(t, [][]byte{data}, chunkAll(t, data))
+
+	// Data without any boundary is cut at the maximum size.
+	zeros := make([]byte, chunkMaxSize+10)
+	chunks := chunkAll(t, zeros)
+	require.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], chunkMaxSize)
+	assert.Len(t, chunks[1], 10)
+}
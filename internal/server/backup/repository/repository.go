@@ -0,0 +1,553 @@
+// Package repository implements a deduplicating backup repository.
+//
+// Backups are split into content-defined chunks which are stored compressed under their hash, so data shared
+// by several backups (or repeated within one) is only stored once. Each backup is described by a manifest
+// listing its chunks, and by a small index entry which is only written once the backup is complete.
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+const (
+	prefixChunks    = "chunks/"
+	prefixManifests = "manifests/"
+	prefixIndex     = "index/"
+	prefixLocks     = "locks/"
+)
+
+// Lock timings. Locks are refreshed while held, so a lock which wasn't refreshed for lockStaleAfter belongs to
+// a server which died while holding it.
+const (
+	lockRefreshInterval = 5 * time.Minute
+	lockStaleAfter      = 30 * time.Minute
+)
+
+// Backup represents a backup stored in a repository.
+type Backup struct {
+	// Name of the backup.
+	Name string `json:"name"`
+
+	// When the backup was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Size of the backup data.
+	Size int64 `json:"size"`
+
+	// Number of chunks the backup is made of.
+	Chunks int `json:"chunks"`
+}
+
+// manifest lists the chunks a backup is made of.
+type manifest struct {
+	Name      string          `json:"name"`
+	CreatedAt time.Time       `json:"created_at"`
+	Chunks    []manifestChunk `json:"chunks"`
+}
+
+// manifestChunk represents a chunk of a backup.
+type manifestChunk struct {
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// PruneResult represents what was removed from a repository by a prune.
+type PruneResult struct {
+	// Number of removed chunks.
+	Chunks int
+
+	// Number of removed manifests of incomplete backups.
+	Incomplete int
+}
+
+// Repository represents a backup repository.
+type Repository struct {
+	store Store
+	now   func() time.Time
+}
+
+// New returns the repository kept in the store.
+func New(store Store) *Repository {
+	return &Repository{store: store, now: time.Now}
+}
+
+// ValidName checks that the name can be used for a backup.
+// Names are slash separated paths, for example `instances/default/c1/backup0`.
+func ValidName(name string) error {
+	if name == "" {
+		return errors.New("Backup name can't be empty")
+	}
+
+	if path.Clean(name) != name || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "../") || name == ".." {
+		return fmt.Errorf("Invalid backup name %q", name)
+	}
+
+	return nil
+}
+
+// chunkKey returns the key of the object holding the chunk with the given hash.
+func chunkKey(hash string) string {
+	return prefixChunks + hash[:2] + "/" + hash
+}
+
+// Write stores the data read from the reader as the named backup.
+func (r *Repository) Write(ctx context.Context, name string, reader io.Reader) (*Backup, error) {
+	err := ValidName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := r.store.Exists(ctx, prefixIndex+name+".json")
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, api.StatusErrorf(http.StatusConflict, "Backup %q already exists", name)
+	}
+
+	unlock, err := r.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	m := manifest{Name: name, CreatedAt: r.now().UTC()}
+	stored := map[string]bool{}
+	var size int64
+
+	c := newChunker(reader)
+	for {
+		chunk, err := c.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		sum := sha256.Sum256(chunk)
+		hash := hex.EncodeToString(sum[:])
+
+		if !stored[hash] {
+			err = r.putChunk(ctx, hash, chunk)
+			if err != nil {
+				return nil, err
+			}
+
+			stored[hash] = true
+		}
+
+		m.Chunks = append(m.Chunks, manifestChunk{Hash: hash, Size: int64(len(chunk))})
+		size += int64(len(chunk))
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.store.Put(ctx, prefixManifests+name+".json", data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing manifest of %q: %w", name, err)
+	}
+
+	// The index entry is written last as it marks the backup as complete.
+	b := &Backup{Name: name, CreatedAt: m.CreatedAt, Size: size, Chunks: len(m.Chunks)}
+
+	data, err = json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.store.Put(ctx, prefixIndex+name+".json", data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing index of %q: %w", name, err)
+	}
+
+	return b, nil
+}
+
+// putChunk stores a chunk unless the repository already has it.
+func (r *Repository) putChunk(ctx context.Context, hash string, chunk []byte) error {
+	key := chunkKey(hash)
+
+	exists, err := r.store.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+
+	_, err = zw.Write(chunk)
+	if err != nil {
+		return err
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+
+	err = r.store.Put(ctx, key, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("Failed writing chunk %q: %w", hash, err)
+	}
+
+	return nil
+}
+
+// getChunk returns the data of a chunk, after checking that it matches its hash.
+func (r *Repository) getChunk(ctx context.Context, hash string) ([]byte, error) {
+	data, err := r.store.Get(ctx, chunkKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Chunk %q is corrupted: %w", hash, err)
+	}
+
+	chunk, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("Chunk %q is corrupted: %w", hash, err)
+	}
+
+	sum := sha256.Sum256(chunk)
+	if hex.EncodeToString(sum[:]) != hash {
+		return nil, fmt.Errorf("Chunk %q is corrupted: hash mismatch", hash)
+	}
+
+	return chunk, nil
+}
+
+// getManifest returns the manifest of a complete backup.
+func (r *Repository) getManifest(ctx context.Context, name string) (*manifest, error) {
+	err := ValidName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := r.store.Exists(ctx, prefixIndex+name+".json")
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Backup %q not found", name)
+	}
+
+	data, err := r.store.Get(ctx, prefixManifests+name+".json")
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading manifest of %q: %w", name, err)
+	}
+
+	m := &manifest{}
+
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing manifest of %q: %w", name, err)
+	}
+
+	return m, nil
+}
+
+// Read returns a reader for the data of the named backup.
+// Chunks are checked against their hash as they're read, with any mismatch failing the read.
+func (r *Repository) Read(ctx context.Context, name string) (io.ReadCloser, error) {
+	unlock, err := r.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := r.getManifest(ctx, name)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer unlock()
+
+		for _, c := range m.Chunks {
+			chunk, err := r.getChunk(ctx, c.Hash)
+			if err != nil {
+				_ = writer.CloseWithError(fmt.Errorf("Failed reading backup %q: %w", name, err))
+				return
+			}
+
+			_, err = writer.Write(chunk)
+			if err != nil {
+				return
+			}
+		}
+
+		_ = writer.Close()
+	}()
+
+	return reader, nil
+}
+
+// List returns the complete backups whose name starts with the prefix, sorted by name.
+func (r *Repository) List(ctx context.Context, prefix string) ([]Backup, error) {
+	objects, err := r.store.List(ctx, prefixIndex+prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(objects))
+	for _, object := range objects {
+		if !strings.HasSuffix(object.Key, ".json") {
+			continue
+		}
+
+		data, err := r.store.Get(ctx, object.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		b := Backup{}
+
+		err = json.Unmarshal(data, &b)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing %q: %w", object.Key, err)
+		}
+
+		backups = append(backups, b)
+	}
+
+	slices.SortFunc(backups, func(a Backup, b Backup) int { return strings.Compare(a.Name, b.Name) })
+
+	return backups, nil
+}
+
+// Verify checks that all the chunks of the backups whose name starts with the prefix are present and intact.
+// Each chunk is only checked once, even when shared by several backups.
+func (r *Repository) Verify(ctx context.Context, prefix string) error {
+	unlock, err := r.lock(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	backups, err := r.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	checked := map[string]error{}
+	var errs []error
+
+	for _, b := range backups {
+		m, err := r.getManifest(ctx, b.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, c := range m.Chunks {
+			chunkErr, ok := checked[c.Hash]
+			if !ok {
+				_, chunkErr = r.getChunk(ctx, c.Hash)
+				if chunkErr != nil && ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				checked[c.Hash] = chunkErr
+			}
+
+			if chunkErr != nil {
+				errs = append(errs, fmt.Errorf("Backup %q is damaged: %w", b.Name, chunkErr))
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Delete removes the named backups. Their chunks are only removed by the next prune.
+func (r *Repository) Delete(ctx context.Context, names ...string) error {
+	for _, name := range names {
+		err := ValidName(name)
+		if err != nil {
+			return err
+		}
+
+		// Remove the index entry first so that the backup is never seen as complete without its manifest.
+		err = r.store.Delete(ctx, prefixIndex+name+".json")
+		if err != nil {
+			return fmt.Errorf("Failed deleting %q: %w", name, err)
+		}
+
+		err = r.store.Delete(ctx, prefixManifests+name+".json")
+		if err != nil {
+			return fmt.Errorf("Failed deleting %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Prune removes the chunks which aren't used by any backup, as well as the manifests of incomplete backups.
+// It fails if the repository is being written to.
+func (r *Repository) Prune(ctx context.Context) (*PruneResult, error) {
+	unlock, err := r.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	result := &PruneResult{}
+
+	index, err := r.store.List(ctx, prefixIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	complete := map[string]bool{}
+	for _, object := range index {
+		complete[strings.TrimPrefix(object.Key, prefixIndex)] = true
+	}
+
+	manifests, err := r.store.List(ctx, prefixManifests)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark the chunks used by complete backups.
+	used := map[string]bool{}
+	for _, object := range manifests {
+		key := strings.TrimPrefix(object.Key, prefixManifests)
+		if !complete[key] {
+			err = r.store.Delete(ctx, object.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Incomplete++
+			continue
+		}
+
+		m, err := r.getManifest(ctx, strings.TrimSuffix(key, ".json"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range m.Chunks {
+			used[c.Hash] = true
+		}
+	}
+
+	// Sweep the others.
+	chunks, err := r.store.List(ctx, prefixChunks)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range chunks {
+		if used[path.Base(object.Key)] {
+			continue
+		}
+
+		err = r.store.Delete(ctx, object.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Chunks++
+	}
+
+	return result, nil
+}
+
+// lock takes a lock on the repository, which is shared by readers and writers and exclusive for prunes.
+// Locks are objects of the repository, so that servers sharing a repository exclude each other.
+func (r *Repository) lock(ctx context.Context, exclusive bool) (func(), error) {
+	kind := "shared"
+	if exclusive {
+		kind = "exclusive"
+	}
+
+	key := prefixLocks + uuid.New().String() + "." + kind
+
+	err := r.store.Put(ctx, key, []byte(kind))
+	if err != nil {
+		return nil, fmt.Errorf("Failed locking repository: %w", err)
+	}
+
+	// Check for conflicting locks once ours is visible, so that two conflicting lockers can't both succeed.
+	locks, err := r.store.List(ctx, prefixLocks)
+	if err != nil {
+		_ = r.store.Delete(context.Background(), key)
+		return nil, err
+	}
+
+	for _, l := range locks {
+		if l.Key == key {
+			continue
+		}
+
+		// Clean up the locks left behind by dead servers.
+		if r.now().Sub(l.ModTime) > lockStaleAfter {
+			_ = r.store.Delete(ctx, l.Key)
+			continue
+		}
+
+		if exclusive || strings.HasSuffix(l.Key, ".exclusive") {
+			_ = r.store.Delete(context.Background(), key)
+			return nil, api.StatusErrorf(http.StatusConflict, "Repository is in use")
+		}
+	}
+
+	// Refresh the lock while held.
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = r.store.Put(context.Background(), key, []byte(kind))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		_ = r.store.Delete(context.Background(), key)
+	}, nil
+}
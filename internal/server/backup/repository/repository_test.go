@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// readBackup returns the data of the named backup.
+func readBackup(t *testing.T, r *Repository, name string) ([]byte, error) {
+	t.Helper()
+
+	reader, err := r.Read(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	return io.ReadAll(reader)
+}
+
+func TestRepositoryWriteRead(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r := New(NewDirStore(dir))
+
+	data := testData(10, 20*1024*1024)
+
+	b, err := r.Write(ctx, "instances/default/c1/backup0", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), b.Size)
+
+	restored, err := readBackup(t, r, "instances/default/c1/backup0")
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+
+	// Backups can't be overwritten.
+	_, err = r.Write(ctx, "instances/default/c1/backup0", bytes.NewReader(data))
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+
+	// Missing backups.
+	_, err = r.Read(ctx, "instances/default/c1/backup1")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	// Invalid names.
+	for _, name := range []string{"", "/abs", "../escape", "a/../b", "a//b"} {
+		_, err = r.Write(ctx, name, bytes.NewReader(data))
+		assert.Error(t, err, name)
+	}
+
+	// No lock is left behind.
+	locks, err := r.store.List(ctx, prefixLocks)
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+}
+
+func TestRepositoryDeduplication(t *testing.T) {
+	ctx := context.Background()
+	r := New(NewDirStore(t.TempDir()))
+
+	data := testData(11, 20*1024*1024)
+
+	b0, err := r.Write(ctx, "backup0", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	chunks, err := r.store.List(ctx, prefixChunks)
+	require.NoError(t, err)
+	assert.Len(t, chunks, b0.Chunks)
+
+	// A second backup with a small change in the middle only adds a few chunks.
+	changed := bytes.Clone(data)
+	copy(changed[10*1024*1024:], "changed")
+
+	_, err = r.Write(ctx, "backup1", bytes.NewReader(changed))
+	require.NoError(t, err)
+
+	chunks, err = r.store.List(ctx, prefixChunks)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(chunks), b0.Chunks+2)
+
+	restored, err := readBackup(t, r, "backup1")
+	require.NoError(t, err)
+	assert.Equal(t, changed, restored)
+
+	backups, err := r.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "backup0", backups[0].Name)
+	assert.Equal(t, "backup1", backups[1].Name)
+}
+
+func TestRepositoryList(t *testing.T) {
+	ctx := context.Background()
+	r := New(NewDirStore(t.TempDir()))
+
+	for _, name := range []string{"instances/default/c1/b0", "instances/default/c10/b0", "instances/default/c1/b1", "volumes/default/pool/v1/b0"} {
+		_, err := r.Write(ctx, name, strings.NewReader(name))
+		require.NoError(t, err)
+	}
+
+	backups, err := r.List(ctx, "instances/default/c1/")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "instances/default/c1/b0", backups[0].Name)
+	assert.Equal(t, "instances/default/c1/b1", backups[1].Name)
+
+	backups, err = r.List(ctx, "instances/")
+	require.NoError(t, err)
+	assert.Len(t, backups, 3)
+
+	backups, err = r.List(ctx, "missing/")
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestRepositoryVerify(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r := New(NewDirStore(dir))
+
+	shared := testData(12, 4*1024*1024)
+
+	_, err := r.Write(ctx, "backup0", bytes.NewReader(shared))
+	require.NoError(t, err)
+
+	_, err = r.Write(ctx, "backup1", bytes.NewReader(append(bytes.Clone(shared), testData(13, 4*1024*1024)...)))
+	require.NoError(t, err)
+
+	require.NoError(t, r.Verify(ctx, ""))
+
+	// Corrupt a chunk shared by both backups.
+	m, err := r.getManifest(ctx, "backup0")
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, filepath.FromSlash(chunkKey(m.Chunks[0].Hash))), []byte("garbage"), 0o600)
+	require.NoError(t, err)
+
+	err = r.Verify(ctx, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"backup0"`)
+	assert.Contains(t, err.Error(), `"backup1"`)
+
+	// Reads fail rather than return corrupted data.
+	_, err = readBackup(t, r, "backup0")
+	assert.Error(t, err)
+
+	// A missing chunk is reported too.
+	err = os.Remove(filepath.Join(dir, filepath.FromSlash(chunkKey(m.Chunks[0].Hash))))
+	require.NoError(t, err)
+
+	err = r.Verify(ctx, "backup1")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), `"backup0"`)
+}
+
+func TestRepositoryPrune(t *testing.T) {
+	ctx := context.Background()
+	r := New(NewDirStore(t.TempDir()))
+
+	data0 := testData(14, 6*1024*1024)
+	data1 := append(bytes.Clone(data0[:3*1024*1024]), testData(15, 6*1024*1024)...)
+
+	_, err := r.Write(ctx, "backup0", bytes.NewReader(data0))
+	require.NoError(t, err)
+
+	_, err = r.Write(ctx, "backup1", bytes.NewReader(data1))
+	require.NoError(t, err)
+
+	// An incomplete backup, left behind by a failed write.
+	err = r.store.Put(ctx, prefixManifests+"backup2.json", []byte(`{"name": "backup2"}`))
+	require.NoError(t, err)
+
+	err = r.Delete(ctx, "backup0")
+	require.NoError(t, err)
+
+	result, err := r.Prune(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, result.Chunks)
+	assert.Equal(t, 1, result.Incomplete)
+
+	// The chunks shared with the remaining backup are kept.
+	restored, err := readBackup(t, r, "backup1")
+	require.NoError(t, err)
+	assert.Equal(t, data1, restored)
+
+	m, err := r.getManifest(ctx, "backup1")
+	require.NoError(t, err)
+
+	chunks, err := r.store.List(ctx, prefixChunks)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(chunks), len(m.Chunks))
+
+	// Nothing left to prune.
+	result, err = r.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &PruneResult{}, result)
+}
+
+func TestRepositoryLock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	r := New(NewDirStore(t.TempDir()))
+	r.now = func() time.Time { return now }
+
+	// Readers and writers share the repository.
+	unlock0, err := r.lock(ctx, false)
+	require.NoError(t, err)
+
+	unlock1, err := r.lock(ctx, false)
+	require.NoError(t, err)
+
+	unlock1()
+
+	// But prunes need it for themselves.
+	_, err = r.Prune(ctx)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+
+	// Unless the other locks are stale.
+	r.now = func() time.Time { return now.Add(lockStaleAfter + time.Minute) }
+
+	unlock2, err := r.lock(ctx, true)
+	require.NoError(t, err)
+
+	r.now = func() time.Time { return now }
+
+	_, err = r.lock(ctx, false)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+
+	unlock2()
+	unlock0()
+
+	locks, err := r.store.List(ctx, prefixLocks)
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Object represents an object held by a store.
+type Object struct {
+	// Key is the full key of the object.
+	Key string
+
+	// ModTime is when the object was last written.
+	ModTime time.Time
+}
+
+// Store is the object storage a repository is kept in.
+// Keys are slash separated paths. Getting a missing object must return a not found status error.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// DirStore is a store keeping objects as files in a local directory.
+type DirStore struct {
+	path string
+}
+
+// NewDirStore returns a store keeping objects in the given directory.
+func NewDirStore(path string) *DirStore {
+	return &DirStore{path: path}
+}
+
+// objectPath returns the path of the file holding the object.
+func (d *DirStore) objectPath(key string) string {
+	return filepath.Join(d.path, filepath.FromSlash(key))
+}
+
+// Put writes an object. The object is only visible once it's fully written.
+func (d *DirStore) Put(_ context.Context, key string, data []byte) error {
+	path := d.objectPath(key)
+
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp_")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get reads an object.
+func (d *DirStore) Get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(d.objectPath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Object %q not found", key)
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Exists returns whether an object exists.
+func (d *DirStore) Exists(_ context.Context, key string) (bool, error) {
+	_, err := os.Stat(d.objectPath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Delete removes an object. Removing a missing object isn't an error.
+func (d *DirStore) Delete(_ context.Context, key string) error {
+	err := os.Remove(d.objectPath(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// List returns the objects whose key starts with the prefix.
+func (d *DirStore) List(_ context.Context, prefix string) ([]Object, error) {
+	// Only walk the deepest directory covering the prefix.
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = filepath.ToSlash(filepath.Dir(dir))
+	}
+
+	objects := []Object{}
+
+	err := filepath.WalkDir(d.objectPath(dir), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp_") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(d.path, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("Failed getting information on %q: %w", path, err)
+		}
+
+		objects = append(objects, Object{Key: key, ModTime: info.ModTime()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
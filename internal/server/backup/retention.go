@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Retention represents how many scheduled backups are kept.
+type Retention struct {
+	// Last is the number of most recent backups to keep.
+	Last int
+
+	// Daily is the number of days for which the most recent backup is kept.
+	Daily int
+
+	// Weekly is the number of weeks for which the most recent backup is kept.
+	Weekly int
+}
+
+// RetentionFromConfig returns the retention policy set through the `backups.retention.*` keys of the config.
+func RetentionFromConfig(config map[string]string) (Retention, error) {
+	var r Retention
+
+	for key, value := range map[string]*int{
+		"backups.retention.last":   &r.Last,
+		"backups.retention.daily":  &r.Daily,
+		"backups.retention.weekly": &r.Weekly,
+	} {
+		if config[key] == "" {
+			continue
+		}
+
+		count, err := strconv.Atoi(config[key])
+		if err != nil || count < 0 {
+			return Retention{}, fmt.Errorf("Invalid value %q for %q", config[key], key)
+		}
+
+		*value = count
+	}
+
+	return r, nil
+}
+
+// IsEmpty returns true if no retention is configured, in which case all backups are kept.
+func (r Retention) IsEmpty() bool {
+	return r.Last <= 0 && r.Daily <= 0 && r.Weekly <= 0
+}
+
+// Expired returns the names of the backups (with their creation dates) which aren't covered by the retention
+// policy, newest first.
+func (r Retention) Expired(backups map[string]time.Time) []string {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	// Sort the backups from newest to oldest.
+	names := make([]string, 0, len(backups))
+	for name := range backups {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a string, b string) int {
+		c := backups[b].Compare(backups[a])
+		if c != 0 {
+			return c
+		}
+
+		return cmp.Compare(b, a)
+	})
+
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	expired := []string{}
+
+	for i, name := range names {
+		keep := i < r.Last
+
+		createdAt := backups[name].UTC()
+
+		day := createdAt.Format(time.DateOnly)
+		if !days[day] && len(days) < r.Daily {
+			days[day] = true
+			keep = true
+		}
+
+		year, week := createdAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && len(weeks) < r.Weekly {
+			weeks[weekKey] = true
+			keep = true
+		}
+
+		if !keep {
+			expired = append(expired, name)
+		}
+	}
+
+	return expired
+}
@@ -0,0 +1,68 @@
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionFromConfig(t *testing.T) {
+	r, err := RetentionFromConfig(map[string]string{})
+	require.NoError(t, err)
+	assert.True(t, r.IsEmpty())
+
+	r, err = RetentionFromConfig(map[string]string{
+		"backups.retention.last":   "2",
+		"backups.retention.daily":  "7",
+		"backups.retention.weekly": "4",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Retention{Last: 2, Daily: 7, Weekly: 4}, r)
+
+	_, err = RetentionFromConfig(map[string]string{"backups.retention.last": "-1"})
+	assert.Error(t, err)
+}
+
+func TestRetentionExpired(t *testing.T) {
+	// Two backups a day at 01:00 and 13:00 for 21 days, starting on a Monday.
+	start := time.Date(2024, time.January, 1, 1, 0, 0, 0, time.UTC)
+	backups := map[string]time.Time{}
+	for i := range 42 {
+		createdAt := start.Add(time.Duration(i) * 12 * time.Hour)
+		backups[createdAt.Format("20060102-1504")] = createdAt
+	}
+
+	// No retention keeps everything.
+	assert.Empty(t, Retention{}.Expired(backups))
+
+	// Keep the last three.
+	expired := Retention{Last: 3}.Expired(backups)
+	assert.Len(t, expired, 39)
+	assert.NotContains(t, expired, "20240121-1300")
+	assert.NotContains(t, expired, "20240121-0100")
+	assert.NotContains(t, expired, "20240120-1300")
+	assert.Contains(t, expired, "20240120-0100")
+
+	// Keep the newest backup of the last two days.
+	expired = Retention{Daily: 2}.Expired(backups)
+	assert.Len(t, expired, 40)
+	assert.NotContains(t, expired, "20240121-1300")
+	assert.NotContains(t, expired, "20240120-1300")
+
+	// Keep the newest backup of the last three weeks.
+	expired = Retention{Weekly: 3}.Expired(backups)
+	assert.Len(t, expired, 39)
+	assert.NotContains(t, expired, "20240121-1300")
+	assert.NotContains(t, expired, "20240114-1300")
+	assert.NotContains(t, expired, "20240107-1300")
+
+	// Policies are combined.
+	expired = Retention{Last: 1, Daily: 2, Weekly: 3}.Expired(backups)
+	assert.Len(t, expired, 38)
+	assert.NotContains(t, expired, "20240121-1300")
+	assert.NotContains(t, expired, "20240120-1300")
+	assert.NotContains(t, expired, "20240114-1300")
+	assert.NotContains(t, expired, "20240107-1300")
+}
@@ -0,0 +1,364 @@
+package bgp
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+
+	"github.com/google/uuid"
+	bgpAPI "github.com/osrg/gobgp/v4/api"
+	bgpAPIutil "github.com/osrg/gobgp/v4/pkg/apiutil"
+	bgpPacket "github.com/osrg/gobgp/v4/pkg/packet/bgp"
+)
+
+// EVPN constants (RFC 7432 and RFC 8365).
+const (
+	evpnRouteTargetSubType  = 0x02 // Route target extended community.
+	evpnPmsiIngressReplType = 6    // Ingress replication PMSI tunnel.
+	evpnTunnelTypeVXLAN     = 8    // VXLAN encapsulation.
+	evpnASTrans             = 23456
+)
+
+// EVPNRoute represents an EVPN route for a VXLAN network identifier.
+type EVPNRoute struct {
+	// VXLAN network identifier the route applies to.
+	VNI uint32
+
+	// MAC address of a MAC/IP advertisement route (type 2), unset for an inclusive multicast route (type 3).
+	MAC net.HardwareAddr
+
+	// Optional IP address of a MAC/IP advertisement route.
+	IP net.IP
+
+	// Address of the VTEP the route points to.
+	NextHop net.IP
+}
+
+// key returns a string uniquely identifying the route.
+func (r EVPNRoute) key() string {
+	return fmt.Sprintf("%d/%s/%s/%s", r.VNI, r.MAC, r.IP, r.NextHop)
+}
+
+type evpnPath struct {
+	owner string
+	route EVPNRoute
+}
+
+// SetEVPNRoutes replaces the EVPN routes advertised for the provided owner.
+func (s *Server) SetEVPNRoutes(owner string, routes []EVPNRoute) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]EVPNRoute, len(routes))
+	for _, route := range routes {
+		wanted[route.key()] = route
+	}
+
+	// Make a copy of the paths dict to safely iterate (path removal mutates it).
+	paths := map[string]evpnPath{}
+	maps.Copy(paths, s.evpnPaths)
+
+	// Remove the routes which are no longer wanted.
+	for pathUUID, path := range paths {
+		if path.owner != owner {
+			continue
+		}
+
+		_, found := wanted[path.route.key()]
+		if found {
+			delete(wanted, path.route.key())
+			continue
+		}
+
+		err := s.removeEVPNRouteByUUID(pathUUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add the new routes.
+	for _, route := range wanted {
+		err := s.addEVPNRoute(route, owner)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveEVPNRoutesByOwner removes all EVPN routes for the provided owner.
+func (s *Server) RemoveEVPNRoutesByOwner(owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Make a copy of the paths dict to safely iterate (path removal mutates it).
+	paths := map[string]evpnPath{}
+	maps.Copy(paths, s.evpnPaths)
+
+	for pathUUID, path := range paths {
+		if path.owner == owner {
+			err := s.removeEVPNRouteByUUID(pathUUID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// EVPNRoutes returns the best EVPN routes currently known for the provided VXLAN network identifier.
+// The VNI is matched against the label of the routes so that routes from VTEPs using other route targets
+// (such as those derived from a different ASN) are included.
+func (s *Server) EVPNRoutes(vni uint32) ([]EVPNRoute, error) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	routes := []EVPNRoute{}
+
+	// Skip if no instance.
+	if s.bgp == nil {
+		return routes, nil
+	}
+
+	err := s.bgp.ListPath(bgpAPIutil.ListPathRequest{
+		TableType: bgpAPI.TableType_TABLE_TYPE_GLOBAL,
+		Family:    bgpPacket.NewFamily(bgpPacket.AFI_L2VPN, bgpPacket.SAFI_EVPN),
+	}, func(_ bgpPacket.NLRI, paths []*bgpAPIutil.Path) {
+		for _, path := range paths {
+			if !path.Best || path.Withdrawal {
+				continue
+			}
+
+			route, ok := evpnRouteFromPath(path)
+			if !ok || route.VNI != vni {
+				continue
+			}
+
+			routes = append(routes, route)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// evpnRouteFromPath converts a MAC/IP advertisement or inclusive multicast path to an EVPNRoute.
+func evpnRouteFromPath(path *bgpAPIutil.Path) (EVPNRoute, bool) {
+	nlri, ok := path.Nlri.(*bgpPacket.EVPNNLRI)
+	if !ok {
+		return EVPNRoute{}, false
+	}
+
+	route := EVPNRoute{}
+
+	for _, attr := range path.Attrs {
+		switch attr := attr.(type) {
+		case *bgpPacket.PathAttributeMpReachNLRI:
+			route.NextHop = net.ParseIP(attr.Nexthop.String())
+		case *bgpPacket.PathAttributePmsiTunnel:
+			route.VNI = attr.Label
+		}
+	}
+
+	switch data := nlri.RouteTypeData.(type) {
+	case *bgpPacket.EVPNMacIPAdvertisementRoute:
+		if len(data.Labels) == 0 {
+			return EVPNRoute{}, false
+		}
+
+		route.VNI = data.Labels[0]
+		route.MAC = data.MacAddress
+		route.IP = net.ParseIP(data.IPAddress.String())
+	case *bgpPacket.EVPNMulticastEthernetTagRoute:
+		// Without a PMSI tunnel attribute, fallback to the originating address.
+		if route.NextHop == nil {
+			route.NextHop = net.ParseIP(data.IPAddress.String())
+		}
+	default:
+		return EVPNRoute{}, false
+	}
+
+	if route.NextHop == nil {
+		return EVPNRoute{}, false
+	}
+
+	return route, true
+}
+
+// evpnPathFromRoute converts an EVPNRoute to a MAC/IP advertisement or inclusive multicast path originated
+// by the router with the provided ID and ASN.
+func evpnPathFromRoute(route EVPNRoute, routerID net.IP, asn uint32) (*bgpAPIutil.Path, error) {
+	family := &bgpAPI.Family{
+		Afi:  bgpAPI.Family_AFI_L2VPN,
+		Safi: bgpAPI.Family_SAFI_EVPN,
+	}
+
+	// Route distinguisher and target are derived from the router ID, ASN and VNI (RFC 7432 section 7.9).
+	rd := &bgpAPI.RouteDistinguisher{Rd: &bgpAPI.RouteDistinguisher_IpAddress{IpAddress: &bgpAPI.RouteDistinguisherIPAddress{
+		Admin:    routerID.String(),
+		Assigned: route.VNI & 0xffff,
+	}}}
+
+	if asn > 0xffff {
+		asn = evpnASTrans
+	}
+
+	communities := []*bgpAPI.ExtendedCommunity{
+		{
+			Extcom: &bgpAPI.ExtendedCommunity_TwoOctetAsSpecific{TwoOctetAsSpecific: &bgpAPI.TwoOctetAsSpecificExtended{
+				IsTransitive: true,
+				SubType:      evpnRouteTargetSubType,
+				Asn:          asn,
+				LocalAdmin:   route.VNI,
+			}},
+		},
+		{
+			Extcom: &bgpAPI.ExtendedCommunity_Encap{Encap: &bgpAPI.EncapExtended{
+				TunnelType: evpnTunnelTypeVXLAN,
+			}},
+		},
+	}
+
+	attrs := []*bgpAPI.Attribute{
+		{
+			Attr: &bgpAPI.Attribute_Origin{Origin: &bgpAPI.OriginAttribute{
+				Origin: 0,
+			}},
+		},
+		{
+			Attr: &bgpAPI.Attribute_ExtendedCommunities{ExtendedCommunities: &bgpAPI.ExtendedCommunitiesAttribute{
+				Communities: communities,
+			}},
+		},
+	}
+
+	var nlri *bgpAPI.NLRI
+	if route.MAC != nil {
+		// MAC/IP advertisement route (type 2).
+		ipAddress := ""
+		if route.IP != nil {
+			ipAddress = route.IP.String()
+		}
+
+		nlri = &bgpAPI.NLRI{Nlri: &bgpAPI.NLRI_EvpnMacadv{EvpnMacadv: &bgpAPI.EVPNMACIPAdvertisementRoute{
+			Rd:          rd,
+			Esi:         &bgpAPI.EthernetSegmentIdentifier{},
+			EthernetTag: 0,
+			MacAddress:  route.MAC.String(),
+			IpAddress:   ipAddress,
+			Labels:      []uint32{route.VNI},
+		}}}
+	} else {
+		// Inclusive multicast Ethernet tag route (type 3), using ingress replication.
+		nlri = &bgpAPI.NLRI{Nlri: &bgpAPI.NLRI_EvpnMulticast{EvpnMulticast: &bgpAPI.EVPNInclusiveMulticastEthernetTagRoute{
+			Rd:          rd,
+			EthernetTag: 0,
+			IpAddress:   route.NextHop.String(),
+		}}}
+
+		tunnelID := route.NextHop.To4()
+		if tunnelID == nil {
+			tunnelID = route.NextHop.To16()
+		}
+
+		attrs = append(attrs, &bgpAPI.Attribute{
+			Attr: &bgpAPI.Attribute_PmsiTunnel{PmsiTunnel: &bgpAPI.PmsiTunnelAttribute{
+				Type:  evpnPmsiIngressReplType,
+				Label: route.VNI,
+				Id:    tunnelID,
+			}},
+		})
+	}
+
+	attrs = append(attrs, &bgpAPI.Attribute{
+		Attr: &bgpAPI.Attribute_MpReach{MpReach: &bgpAPI.MpReachNLRIAttribute{
+			Family:   family,
+			NextHops: []string{route.NextHop.String()},
+			Nlris:    []*bgpAPI.NLRI{nlri},
+		}},
+	})
+
+	path := &bgpAPI.Path{
+		Family: family,
+		Nlri:   nlri,
+		Pattrs: attrs,
+	}
+
+	utilNlri, err := bgpAPIutil.GetNativeNlri(path)
+	if err != nil {
+		return nil, err
+	}
+
+	utilAttrs, err := bgpAPIutil.GetNativePathAttributes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bgpAPIutil.Path{
+		Family: bgpPacket.NewFamily(bgpPacket.AFI_L2VPN, bgpPacket.SAFI_EVPN),
+		Nlri:   utilNlri,
+		Attrs:  utilAttrs,
+	}, nil
+}
+
+func (s *Server) addEVPNRoute(route EVPNRoute, owner string) error {
+	var pathUUID string
+	if s.bgp != nil {
+		utilPath, err := evpnPathFromRoute(route, s.routerID, s.asn)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.bgp.AddPath(bgpAPIutil.AddPathRequest{
+			Paths: []*bgpAPIutil.Path{utilPath},
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(resp) != 1 {
+			return errors.New("Expected single response from AddPath")
+		}
+
+		pathUUID = resp[0].UUID.String()
+	} else {
+		// Generate a dummy UUID.
+		pathUUID = uuid.New().String()
+	}
+
+	// Add path to the map.
+	s.evpnPaths[pathUUID] = evpnPath{
+		owner: owner,
+		route: route,
+	}
+
+	return nil
+}
+
+func (s *Server) removeEVPNRouteByUUID(pathUUID string) error {
+	// Remove it from the BGP server.
+	if s.bgp != nil {
+		nativeUUID, err := uuid.Parse(pathUUID)
+		if err != nil {
+			return err
+		}
+
+		err = s.bgp.DeletePath(bgpAPIutil.DeletePathRequest{UUIDs: []uuid.UUID{nativeUUID}})
+		if err != nil && err.Error() != "can't find a specified path" {
+			return err
+		}
+	}
+
+	// Remove the path from the map.
+	delete(s.evpnPaths, pathUUID)
+
+	return nil
+}
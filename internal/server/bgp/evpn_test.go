@@ -0,0 +1,148 @@
+package bgp
+
+import (
+	"net"
+	"testing"
+
+	bgpPacket "github.com/osrg/gobgp/v4/pkg/packet/bgp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evpnPathFromRoute(t *testing.T) {
+	mac, err := net.ParseMAC("00:16:3e:00:00:01")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		route     EVPNRoute
+		routeType uint8
+	}{
+		{
+			name:      "MAC advertisement",
+			route:     EVPNRoute{VNI: 100, MAC: mac, NextHop: net.ParseIP("192.0.2.1")},
+			routeType: bgpPacket.EVPN_ROUTE_TYPE_MAC_IP_ADVERTISEMENT,
+		},
+		{
+			name:      "MAC/IPv4 advertisement",
+			route:     EVPNRoute{VNI: 100, MAC: mac, IP: net.ParseIP("10.0.0.2"), NextHop: net.ParseIP("192.0.2.1")},
+			routeType: bgpPacket.EVPN_ROUTE_TYPE_MAC_IP_ADVERTISEMENT,
+		},
+		{
+			name:      "MAC/IPv6 advertisement",
+			route:     EVPNRoute{VNI: 16000000, MAC: mac, IP: net.ParseIP("fd00::2"), NextHop: net.ParseIP("192.0.2.1")},
+			routeType: bgpPacket.EVPN_ROUTE_TYPE_MAC_IP_ADVERTISEMENT,
+		},
+		{
+			name:      "Inclusive multicast",
+			route:     EVPNRoute{VNI: 100, NextHop: net.ParseIP("192.0.2.1")},
+			routeType: bgpPacket.EVPN_INCLUSIVE_MULTICAST_ETHERNET_TAG,
+		},
+		{
+			name:      "Inclusive multicast over IPv6",
+			route:     EVPNRoute{VNI: 100, NextHop: net.ParseIP("2001:db8::1")},
+			routeType: bgpPacket.EVPN_INCLUSIVE_MULTICAST_ETHERNET_TAG,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := evpnPathFromRoute(tt.route, net.ParseIP("192.0.2.1"), 65000)
+			require.NoError(t, err)
+
+			nlri, ok := path.Nlri.(*bgpPacket.EVPNNLRI)
+			require.True(t, ok)
+			assert.Equal(t, tt.routeType, nlri.RouteType)
+
+			route, ok := evpnRouteFromPath(path)
+			require.True(t, ok)
+			assert.Equal(t, tt.route.VNI, route.VNI)
+			assert.Equal(t, tt.route.MAC, route.MAC)
+			assert.True(t, tt.route.IP.Equal(route.IP), "IP %s != %s", tt.route.IP, route.IP)
+			assert.True(t, tt.route.NextHop.Equal(route.NextHop), "NextHop %s != %s", tt.route.NextHop, route.NextHop)
+			assert.Equal(t, tt.route.key(), route.key())
+		})
+	}
+}
+
+func Test_evpnPathFromRouteTarget(t *testing.T) {
+	route := EVPNRoute{VNI: 100, NextHop: net.ParseIP("192.0.2.1")}
+
+	tests := []struct {
+		name string
+		asn  uint32
+		rtAS uint16
+	}{
+		{name: "Two-octet ASN", asn: 65000, rtAS: 65000},
+		{name: "Four-octet ASN", asn: 4200000000, rtAS: evpnASTrans},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := evpnPathFromRoute(route, net.ParseIP("192.0.2.1"), tt.asn)
+			require.NoError(t, err)
+
+			var routeTarget *bgpPacket.TwoOctetAsSpecificExtended
+			for _, attr := range path.Attrs {
+				communities, ok := attr.(*bgpPacket.PathAttributeExtendedCommunities)
+				if !ok {
+					continue
+				}
+
+				for _, community := range communities.Value {
+					rt, ok := community.(*bgpPacket.TwoOctetAsSpecificExtended)
+					if ok {
+						routeTarget = rt
+					}
+				}
+			}
+
+			require.NotNil(t, routeTarget)
+			assert.Equal(t, tt.rtAS, routeTarget.AS)
+			assert.Equal(t, route.VNI, routeTarget.LocalAdmin)
+		})
+	}
+}
+
+func TestServer_SetEVPNRoutes(t *testing.T) {
+	mac, err := net.ParseMAC("00:16:3e:00:00:01")
+	require.NoError(t, err)
+
+	s := NewServer()
+
+	flood := EVPNRoute{VNI: 100, NextHop: net.ParseIP("192.0.2.1")}
+	host := EVPNRoute{VNI: 100, MAC: mac, NextHop: net.ParseIP("192.0.2.1")}
+	other := EVPNRoute{VNI: 200, NextHop: net.ParseIP("192.0.2.1")}
+
+	routes := func(owner string) []string {
+		keys := []string{}
+		for _, path := range s.evpnPaths {
+			if path.owner == owner {
+				keys = append(keys, path.route.key())
+			}
+		}
+
+		return keys
+	}
+
+	require.NoError(t, s.SetEVPNRoutes("net1", []EVPNRoute{flood, host}))
+	require.NoError(t, s.SetEVPNRoutes("net2", []EVPNRoute{other}))
+	assert.ElementsMatch(t, []string{flood.key(), host.key()}, routes("net1"))
+
+	// Unchanged routes are kept as is.
+	var floodUUID string
+	for pathUUID, path := range s.evpnPaths {
+		if path.route.key() == flood.key() {
+			floodUUID = pathUUID
+		}
+	}
+
+	require.NoError(t, s.SetEVPNRoutes("net1", []EVPNRoute{flood}))
+	assert.Equal(t, []string{flood.key()}, routes("net1"))
+	assert.Contains(t, s.evpnPaths, floodUUID)
+
+	// Removing the routes of an owner leaves the others alone.
+	require.NoError(t, s.RemoveEVPNRoutesByOwner("net1"))
+	assert.Empty(t, routes("net1"))
+	assert.Equal(t, []string{other.key()}, routes("net2"))
+}
@@ -27,11 +27,12 @@ type Server struct {
 	bgp *bgpServer.BgpServer
 
 	// Internal state (to handle reconfiguration)
-	address  string
-	asn      uint32
-	routerID net.IP
-	paths    map[string]path
-	peers    map[string]peer
+	address   string
+	asn       uint32
+	routerID  net.IP
+	paths     map[string]path
+	evpnPaths map[string]evpnPath
+	peers     map[string]peer
 
 	mu sync.Mutex
 }
@@ -64,8 +65,9 @@ func peerKey(address net.IP, iface string) string {
 func NewServer() *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		paths:     map[string]path{},
+		evpnPaths: map[string]evpnPath{},
+		peers:     map[string]peer{},
 	}
 
 	return s
@@ -113,8 +115,8 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		RouterId: routerID.String(),
 		Asn:      asn,
 
-		// Always setup for IPv4 and IPv6.
-		Families: []uint32{0, 1},
+		// Always setup for IPv4, IPv6 and EVPN.
+		Families: []uint32{0, 1, uint32(bgpConfig.AFI_SAFI_TYPE_L2VPN_EVPN.ToInt())},
 
 		// Listen address.
 		ListenAddresses: []string{addrHost},
@@ -140,6 +142,23 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		}
 	}
 
+	// Copy the EVPN path list.
+	oldEVPNPaths := map[string]evpnPath{}
+	maps.Copy(oldEVPNPaths, s.evpnPaths)
+
+	// Record the ASN and router ID (used for EVPN routes).
+	s.asn = asn
+	s.routerID = routerID
+
+	// Add existing EVPN paths.
+	s.evpnPaths = map[string]evpnPath{}
+	for _, path := range oldEVPNPaths {
+		err := s.addEVPNRoute(path.route, path.owner)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Copy the peer list.
 	oldPeers := map[string]peer{}
 	maps.Copy(oldPeers, s.peers)
@@ -540,9 +559,9 @@ func (s *Server) addPeer(address net.IP, iface string, asn uint32, password stri
 		}
 	}
 
-	// Setup peer for dual-stack.
+	// Setup peer for dual-stack and EVPN.
 	n.AfiSafis = make([]*bgpAPI.AfiSafi, 0)
-	for _, f := range []string{"ipv4-unicast", "ipv6-unicast"} {
+	for _, f := range []string{"ipv4-unicast", "ipv6-unicast", "l2vpn-evpn"} {
 		rf, err := bgpPacket.GetFamily(f)
 		if err != nil {
 			return err
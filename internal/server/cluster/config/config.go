@@ -51,6 +51,11 @@ func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
 }
 
+// AuditRetentionDays returns the number of days after which audit log entries are deleted.
+func (c *Config) AuditRetentionDays() int64 {
+	return c.m.GetInt64("core.audit_retention")
+}
+
 // BGPASN returns the BGP ASN setting.
 func (c *Config) BGPASN() int64 {
 	return c.m.GetInt64("core.bgp_asn")
@@ -188,6 +193,11 @@ func (c *Config) LinstorSSL() (string, string, string) {
 	return c.m.GetString("storage.linstor.ca_cert"), c.m.GetString("storage.linstor.client_cert"), c.m.GetString("storage.linstor.client_key")
 }
 
+// StorageKeystore returns the URL and CA certificate of the remote volume key store.
+func (c *Config) StorageKeystore() (string, string) {
+	return c.m.GetString("storage.keystore.url"), c.m.GetString("storage.keystore.ca_cert")
+}
+
 // ShutdownAction returns the action to perform when the server is being shut down.
 func (c *Config) ShutdownAction() string {
 	return c.m.GetString("core.shutdown_action")
@@ -337,6 +347,11 @@ func (c *Config) RemoteTokenExpiry() string {
 	return c.m.GetString("core.remote_token_expiry")
 }
 
+// Tracing returns the OTLP endpoint traces are sent to and the fraction of traces to record.
+func (c *Config) Tracing() (string, float64) {
+	return c.m.GetString("core.tracing.endpoint"), float64(c.m.GetInt64("core.tracing.sampling_rate")) / 100
+}
+
 // OIDCServer returns all the OpenID Connect settings needed to connect to a server.
 func (c *Config) OIDCServer() (string, string, string, string, string) {
 	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
@@ -795,6 +810,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to enforce authentication on the metrics endpoint
 	"core.metrics_authentication": {Type: config.Bool, Default: "true"},
 
+	// gendoc:generate(entity=server, group=core, key=core.audit_retention)
+	// Specify the number of days after which entries of the audit log are deleted.
+	// Set this option to `0` to keep the entries forever.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `30`
+	//  shortdesc: When audit log entries are deleted
+	"core.audit_retention": {Type: config.Int64, Default: "30", Validator: validate.IsUint32},
+
 	// gendoc:generate(entity=server, group=core, key=core.bgp_asn)
 	//
 	// ---
@@ -906,6 +931,24 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How long to wait before shutdown
 	"core.shutdown_timeout": {Type: config.Int64, Default: "5"},
 
+	// gendoc:generate(entity=server, group=core, key=core.tracing.endpoint)
+	// Specify the URL of an OpenTelemetry collector accepting traces over OTLP/HTTP, for example `http://collector.example.com:4318`.
+	// Spans are recorded for API requests, operations, cluster forwarding, storage calls and migrations.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: OTLP endpoint to send traces to
+	"core.tracing.endpoint": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=core, key=core.tracing.sampling_rate)
+	// Specify the percentage of traces to record. Requests coming from another cluster member follow the decision made for their parent.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `100`
+	//  shortdesc: Percentage of traces to record
+	"core.tracing.sampling_rate": {Type: config.Int64, Default: "100", Validator: validate.IsInRange(0, 100)},
+
 	// gendoc:generate(entity=server, group=core, key=core.trust_ca_certificates)
 	//
 	// ---
@@ -1214,6 +1257,24 @@ var ConfigSchema = config.Schema{
 	//  scope: global
 	//  shortdesc: LINSTOR SSL client key
 	"storage.linstor.client_key": {Default: ""},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.keystore.url)
+	// When set, the keys of encrypted storage volumes are kept on this remote HTTPS key store
+	// (using the server certificate for authentication) rather than on the local server.
+	// It's required to encrypt volumes on remote storage pools of a cluster.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Remote key store URL for encrypted volumes
+	"storage.keystore.url": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.keystore.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Remote key store SSL certificate authority
+	"storage.keystore.ca_cert": {Default: ""},
 }
 
 func expiryValidator(value string) error {
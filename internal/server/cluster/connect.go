@@ -10,12 +10,15 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	incus "github.com/lxc/incus/v7/client"
 	clusterRequest "github.com/lxc/incus/v7/internal/server/cluster/request"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/state"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
+	"github.com/lxc/incus/v7/internal/server/tracing"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
 	localtls "github.com/lxc/incus/v7/shared/tls"
@@ -33,6 +36,17 @@ func init() {
 // to the UserAgentNotifier value, which can be used in some cases to distinguish
 // between a regular client request and an internal cluster request.
 func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, r *http.Request, notify bool) (incus.InstanceServer, error) {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+
+	return ConnectWithContext(ctx, address, networkCert, serverCert, r, notify)
+}
+
+// ConnectWithContext is the same as Connect, except that the requests made by the client are traced as part of
+// the trace held by the given context.
+func ConnectWithContext(ctx context.Context, address string, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, r *http.Request, notify bool) (incus.InstanceServer, error) {
 	// Wait for a connection to the events API first for non-notify connections.
 	if !notify {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(10)*time.Second)
@@ -81,11 +95,50 @@ func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtl
 
 	args.Proxy = proxy
 
+	// Trace the requests and propagate the trace context to the target server.
+	traceCtx := context.WithoutCancel(ctx)
+	args.TransportWrapper = func(t *http.Transport) incus.HTTPTransporter {
+		return &tracingTransport{transport: t, ctx: traceCtx, address: address}
+	}
+
 	// Connect to the target server.
 	serverURL := fmt.Sprintf("https://%s", address)
 	return incus.ConnectIncus(serverURL, args)
 }
 
+// tracingTransport records a span for each request made to another cluster member.
+type tracingTransport struct {
+	transport *http.Transport
+	ctx       context.Context
+	address   string
+}
+
+// RoundTrip performs the request as part of a new span, passing the trace context along.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(t.ctx, fmt.Sprintf("cluster %s %s", req.Method, req.URL.Path),
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.String()),
+		attribute.String("server.address", t.address),
+	)
+
+	req = req.Clone(req.Context())
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+// Transport returns the wrapped transport.
+func (t *tracingTransport) Transport() *http.Transport {
+	return t.transport
+}
+
 // ConnectIfInstanceIsRemote figures out the address of the cluster member which is running the instance with the
 // given name in the specified project. If it's not the local member will connect to it and return the connected
 // client (configured with the specified project), otherwise it will just return nil.
@@ -0,0 +1,35 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DeleteAuditEntriesBefore deletes the audit log entries recorded before the given date and returns how many were
+// deleted.
+func (c *ClusterTx) DeleteAuditEntriesBefore(ctx context.Context, date time.Time) (int64, error) {
+	res, err := c.tx.ExecContext(ctx, "DELETE FROM audit_entries WHERE date < ?", date)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to delete audit log entries: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get affected rows when deleting audit log entries: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateAuditEntryResult updates the status code and error recorded for an audit log entry.
+func (c *ClusterTx) UpdateAuditEntryResult(ctx context.Context, id int64, statusCode int, errorMessage string) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE audit_entries SET status_code = ?, error = ? WHERE id = ?", statusCode, errorMessage, id)
+	if err != nil {
+		return fmt.Errorf("Failed to update audit log entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+)
+
+// Add, list and prune audit log entries.
+func TestAuditEntries(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	now := time.Now().UTC()
+
+	for i, method := range []string{"POST", "DELETE"} {
+		_, err := cluster.CreateAuditEntry(context.TODO(), tx.Tx(), cluster.AuditEntry{
+			Date:       now.Add(time.Duration(i-1) * 48 * time.Hour),
+			Node:       "none",
+			Username:   "foo",
+			Protocol:   "tls",
+			Address:    "10.0.2.15",
+			Method:     method,
+			EntityURL:  "/1.0/instances/c1",
+			StatusCode: 202,
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := cluster.GetAuditEntries(context.TODO(), tx.Tx())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "POST", entries[0].Method)
+	assert.Equal(t, "foo", entries[0].ToAPI().Requestor.Username)
+
+	count, err := tx.DeleteAuditEntriesBefore(context.TODO(), now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	entries, err = cluster.GetAuditEntries(context.TODO(), tx.Tx())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "DELETE", entries[0].Method)
+}
+
+// Select pages of audit log entries and update the result of an entry.
+func TestAuditEntriesPage(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	now := time.Now().UTC()
+
+	for i, username := range []string{"foo", "bar", "foo", "foo"} {
+		_, err := cluster.CreateAuditEntry(context.TODO(), tx.Tx(), cluster.AuditEntry{
+			Date:       now.Add(time.Duration(i) * time.Hour),
+			Node:       "none",
+			Username:   username,
+			Protocol:   "tls",
+			Method:     "POST",
+			EntityURL:  "/1.0/instances",
+			Project:    "default",
+			StatusCode: 202,
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := cluster.GetAuditEntriesPage(context.TODO(), tx.Tx(), cluster.AuditEntryQuery{Username: "foo", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(1), entries[0].ID)
+	assert.Equal(t, int64(3), entries[1].ID)
+
+	entries, err = cluster.GetAuditEntriesPage(context.TODO(), tx.Tx(), cluster.AuditEntryQuery{Username: "foo", After: entries[1].ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(4), entries[0].ID)
+
+	start := now.Add(time.Hour)
+	end := now.Add(3 * time.Hour)
+	entries, err = cluster.GetAuditEntriesPage(context.TODO(), tx.Tx(), cluster.AuditEntryQuery{Start: &start, End: &end})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "bar", entries[0].Username)
+
+	entries, err = cluster.GetAuditEntriesPage(context.TODO(), tx.Tx(), cluster.AuditEntryQuery{Project: "other"})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	err = tx.UpdateAuditEntryResult(context.TODO(), 2, 500, "Failed creating instance")
+	require.NoError(t, err)
+
+	entries, err = cluster.GetAuditEntriesPage(context.TODO(), tx.Tx(), cluster.AuditEntryQuery{Username: "bar"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 500, entries[0].StatusCode)
+	assert.Equal(t, "Failed creating instance", entries[0].Error)
+}
@@ -0,0 +1,124 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target audit_entries.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e audit_entry objects
+//generate-database:mapper stmt -e audit_entry objects-by-ID
+//generate-database:mapper stmt -e audit_entry create
+//
+//generate-database:mapper method -i -e audit_entry GetMany
+//generate-database:mapper method -i -e audit_entry Create
+
+// AuditEntry is a value object holding db-related details about a mutating API call.
+//
+// Entries only reference the caller, member and project by name so that they outlive them.
+type AuditEntry struct {
+	ID         int64 `db:"primary=yes"`
+	Date       time.Time
+	Node       string
+	Username   string
+	Protocol   string
+	Address    string
+	Method     string
+	EntityURL  string
+	Project    string
+	BodyDigest string
+	StatusCode int
+	Error      string
+	Operation  string
+}
+
+// AuditEntryFilter specifies potential query parameter fields.
+type AuditEntryFilter struct {
+	ID *int64
+}
+
+// AuditEntryQuery specifies the criteria selecting a page of audit log entries.
+type AuditEntryQuery struct {
+	// Only entries recorded at or after this date.
+	Start *time.Time
+
+	// Only entries recorded before this date.
+	End *time.Time
+
+	// Only entries of this caller.
+	Username string
+
+	// Only entries applying to this project.
+	Project string
+
+	// Only entries following the one with this ID.
+	After int64
+
+	// Maximum number of entries to return (0 for no limit).
+	Limit int
+}
+
+// GetAuditEntriesPage returns the audit log entries matching the query, oldest first.
+func GetAuditEntriesPage(ctx context.Context, db dbtx, query AuditEntryQuery) ([]AuditEntry, error) {
+	where := []string{"audit_entries.id > ?"}
+	args := []any{query.After}
+
+	if query.Start != nil {
+		where = append(where, "audit_entries.date >= ?")
+		args = append(args, query.Start.UTC())
+	}
+
+	if query.End != nil {
+		where = append(where, "audit_entries.date < ?")
+		args = append(args, query.End.UTC())
+	}
+
+	if query.Username != "" {
+		where = append(where, "audit_entries.username = ?")
+		args = append(args, query.Username)
+	}
+
+	if query.Project != "" {
+		where = append(where, "audit_entries.project = ?")
+		args = append(args, query.Project)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM audit_entries WHERE %s ORDER BY audit_entries.id", auditEntryColumns(), strings.Join(where, " AND "))
+	if query.Limit > 0 {
+		sql += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	return getAuditEntriesRaw(ctx, db, sql, args...)
+}
+
+// ToAPI returns an API entry.
+func (e AuditEntry) ToAPI() api.AuditEntry {
+	return api.AuditEntry{
+		ID:        e.ID,
+		Timestamp: e.Date,
+		Location:  e.Node,
+		Requestor: api.EventLifecycleRequestor{
+			Username: e.Username,
+			Protocol: e.Protocol,
+			Address:  e.Address,
+		},
+		Method:     e.Method,
+		EntityURL:  e.EntityURL,
+		Project:    e.Project,
+		BodyDigest: e.BodyDigest,
+		StatusCode: e.StatusCode,
+		Error:      e.Error,
+		Operation:  e.Operation,
+	}
+}
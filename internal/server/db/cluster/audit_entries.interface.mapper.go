@@ -0,0 +1,16 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// AuditEntryGenerated is an interface of generated methods for AuditEntry.
+type AuditEntryGenerated interface {
+	// GetAuditEntries returns all available audit_entries.
+	// generator: audit_entry GetMany
+	GetAuditEntries(ctx context.Context, db dbtx, filters ...AuditEntryFilter) ([]AuditEntry, error)
+
+	// CreateAuditEntry adds a new audit_entry to the database.
+	// generator: audit_entry Create
+	CreateAuditEntry(ctx context.Context, db dbtx, object AuditEntry) (int64, error)
+}
@@ -0,0 +1,74 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target networks_reservations.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+// Statements:
+//generate-database:mapper stmt -e NetworkReservation objects table=networks_reservations
+//generate-database:mapper stmt -e NetworkReservation objects-by-NetworkID table=networks_reservations
+//generate-database:mapper stmt -e NetworkReservation objects-by-NetworkID-and-Address table=networks_reservations
+//generate-database:mapper stmt -e NetworkReservation create table=networks_reservations
+//generate-database:mapper stmt -e NetworkReservation delete-by-NetworkID-and-Address table=networks_reservations
+//
+// Methods:
+//generate-database:mapper method -i -e NetworkReservation GetMany table=networks_reservations
+//generate-database:mapper method -i -e NetworkReservation Create table=networks_reservations
+//generate-database:mapper method -i -e NetworkReservation DeleteOne-by-NetworkID-and-Address table=networks_reservations
+
+// NetworkReservation is a value object holding db-related details about an address or range of addresses reserved
+// on a network.
+type NetworkReservation struct {
+	ID          int    `db:"order=yes"`
+	NetworkID   int64  `db:"primary=yes"`
+	Address     string `db:"primary=yes"`
+	Owner       string
+	Description string
+}
+
+// NetworkReservationFilter defines the optional WHERE-clause fields.
+type NetworkReservationFilter struct {
+	NetworkID *int64
+	Address   *string
+}
+
+// ToAPI converts the DB record into the external API type.
+func (n *NetworkReservation) ToAPI() *api.NetworkReservation {
+	return &api.NetworkReservation{
+		NetworkReservationPut: api.NetworkReservationPut{
+			Owner:       n.Owner,
+			Description: n.Description,
+		},
+		Address: n.Address,
+	}
+}
+
+// UpdateNetworkReservation changes the owner and description of the network reservation with the given ID.
+func UpdateNetworkReservation(ctx context.Context, tx *sql.Tx, id int, owner string, description string) error {
+	result, err := tx.ExecContext(ctx, "UPDATE networks_reservations SET owner = ?, description = ? WHERE id = ?", owner, description, id)
+	if err != nil {
+		return fmt.Errorf("Update \"networks_reservations\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// NetworkReservationGenerated is an interface of generated methods for NetworkReservation.
+type NetworkReservationGenerated interface {
+	// GetNetworkReservations returns all available NetworkReservations.
+	// generator: NetworkReservation GetMany
+	GetNetworkReservations(ctx context.Context, db dbtx, filters ...NetworkReservationFilter) ([]NetworkReservation, error)
+
+	// CreateNetworkReservation adds a new NetworkReservation to the database.
+	// generator: NetworkReservation Create
+	CreateNetworkReservation(ctx context.Context, db dbtx, object NetworkReservation) (int64, error)
+
+	// DeleteNetworkReservation deletes the NetworkReservation matching the given key parameters.
+	// generator: NetworkReservation DeleteOne-by-NetworkID-and-Address
+	DeleteNetworkReservation(ctx context.Context, db dbtx, networkID int64, address string) error
+}
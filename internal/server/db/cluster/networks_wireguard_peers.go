@@ -0,0 +1,63 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target networks_wireguard_peers.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+// Statements:
+//generate-database:mapper stmt -e NetworkWireguardPeer objects table=networks_wireguard_peers
+//generate-database:mapper stmt -e NetworkWireguardPeer objects-by-NetworkID table=networks_wireguard_peers
+//generate-database:mapper stmt -e NetworkWireguardPeer objects-by-NetworkID-and-NodeID table=networks_wireguard_peers
+//generate-database:mapper stmt -e NetworkWireguardPeer create table=networks_wireguard_peers
+//generate-database:mapper stmt -e NetworkWireguardPeer delete-by-NetworkID-and-NodeID table=networks_wireguard_peers
+//
+// Methods:
+//generate-database:mapper method -i -e NetworkWireguardPeer GetMany table=networks_wireguard_peers
+//generate-database:mapper method -i -e NetworkWireguardPeer Create table=networks_wireguard_peers
+//generate-database:mapper method -i -e NetworkWireguardPeer DeleteOne-by-NetworkID-and-NodeID table=networks_wireguard_peers
+
+// NetworkWireguardPeer is a value object holding db-related details about the WireGuard endpoint of a cluster member
+// for a network.
+type NetworkWireguardPeer struct {
+	ID          int   `db:"order=yes"`
+	NetworkID   int64 `db:"primary=yes"`
+	NodeID      int64
+	PublicKey   string
+	Endpoint    string
+	UpdatedDate time.Time
+}
+
+// NetworkWireguardPeerFilter defines the optional WHERE-clause fields.
+type NetworkWireguardPeerFilter struct {
+	NetworkID *int64
+	NodeID    *int64
+}
+
+// UpdateNetworkWireguardPeer changes the public key and endpoint of the WireGuard peer with the given ID.
+func UpdateNetworkWireguardPeer(ctx context.Context, tx *sql.Tx, id int, publicKey string, endpoint string, date time.Time) error {
+	result, err := tx.ExecContext(ctx, "UPDATE networks_wireguard_peers SET public_key = ?, endpoint = ?, updated_date = ? WHERE id = ?", publicKey, endpoint, date, id)
+	if err != nil {
+		return fmt.Errorf("Update \"networks_wireguard_peers\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// NetworkWireguardPeerGenerated is an interface of generated methods for NetworkWireguardPeer.
+type NetworkWireguardPeerGenerated interface {
+	// GetNetworkWireguardPeers returns all available NetworkWireguardPeers.
+	// generator: NetworkWireguardPeer GetMany
+	GetNetworkWireguardPeers(ctx context.Context, db dbtx, filters ...NetworkWireguardPeerFilter) ([]NetworkWireguardPeer, error)
+
+	// CreateNetworkWireguardPeer adds a new NetworkWireguardPeer to the database.
+	// generator: NetworkWireguardPeer Create
+	CreateNetworkWireguardPeer(ctx context.Context, db dbtx, object NetworkWireguardPeer) (int64, error)
+
+	// DeleteNetworkWireguardPeer deletes the NetworkWireguardPeer matching the given key parameters.
+	// generator: NetworkWireguardPeer DeleteOne-by-NetworkID-and-NodeID
+	DeleteNetworkWireguardPeer(ctx context.Context, db dbtx, networkID int64, nodeID int64) error
+}
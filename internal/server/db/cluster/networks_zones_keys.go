@@ -0,0 +1,82 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target networks_zones_keys.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+// Statements:
+//generate-database:mapper stmt -e NetworkZoneKey objects table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey objects-by-ID table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey objects-by-NetworkZoneID table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey create table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey delete-by-NetworkZoneID-and-ID table=networks_zones_keys
+//
+// Methods:
+//generate-database:mapper method -i -e NetworkZoneKey GetMany table=networks_zones_keys
+//generate-database:mapper method -i -e NetworkZoneKey Create table=networks_zones_keys
+//generate-database:mapper method -i -e NetworkZoneKey DeleteOne-by-NetworkZoneID-and-ID table=networks_zones_keys
+
+// NetworkZoneKey roles.
+const (
+	NetworkZoneKeyRoleKSK = "ksk"
+	NetworkZoneKeyRoleZSK = "zsk"
+)
+
+// NetworkZoneKey states.
+const (
+	// NetworkZoneKeyStatePublished is a key published in the zone ahead of being used for signing.
+	NetworkZoneKeyStatePublished = "published"
+
+	// NetworkZoneKeyStateActive is a key published in the zone and used for signing it.
+	NetworkZoneKeyStateActive = "active"
+
+	// NetworkZoneKeyStateRetired is a key no longer used for signing, published until its signatures expire from caches.
+	NetworkZoneKeyStateRetired = "retired"
+)
+
+// NetworkZoneKey is a value object holding db-related details about a DNSSEC key of a network zone.
+type NetworkZoneKey struct {
+	ID            int `db:"order=yes"`
+	NetworkZoneID int `db:"primary=yes"`
+	Role          string
+	State         string
+	PublicKey     string
+	PrivateKey    string
+	CreationDate  time.Time
+	UpdatedDate   time.Time
+}
+
+// NetworkZoneKeyFilter defines the optional WHERE-clause fields.
+type NetworkZoneKeyFilter struct {
+	ID            *int
+	NetworkZoneID *int
+}
+
+// UpdateNetworkZoneKeyState changes the state of the network zone key with the given ID.
+func UpdateNetworkZoneKeyState(ctx context.Context, tx *sql.Tx, id int, state string, date time.Time) error {
+	result, err := tx.ExecContext(ctx, "UPDATE networks_zones_keys SET state = ?, updated_date = ? WHERE id = ?", state, date, id)
+	if err != nil {
+		return fmt.Errorf("Update \"networks_zones_keys\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
@@ -6,6 +6,22 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE "audit_entries" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    date DATETIME NOT NULL,
+    node TEXT NOT NULL,
+    username TEXT NOT NULL,
+    protocol TEXT NOT NULL,
+    address TEXT NOT NULL,
+    method TEXT NOT NULL,
+    entity_url TEXT NOT NULL,
+    project TEXT NOT NULL,
+    body_digest TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    operation TEXT NOT NULL
+);
+CREATE INDEX audit_entries_date ON audit_entries (date);
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -354,6 +370,26 @@ CREATE TABLE "networks_peers_config" (
     FOREIGN KEY (network_peer_id) REFERENCES "networks_peers" (id) ON DELETE CASCADE
 );
 CREATE UNIQUE INDEX networks_unique_network_id_node_id_key ON "networks_config" (network_id, IFNULL(node_id, -1), key);
+CREATE TABLE "networks_reservations" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    owner TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (network_id, address),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_wireguard_peers" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    endpoint TEXT NOT NULL,
+    updated_date DATETIME NOT NULL,
+    UNIQUE (network_id, node_id),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_zones" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     project_id INTEGER NOT NULL,
@@ -370,6 +406,17 @@ CREATE TABLE "networks_zones_config" (
     UNIQUE (network_zone_id, key),
     FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
 );
+CREATE TABLE "networks_zones_keys" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    state TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    updated_date DATETIME NOT NULL,
+    FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_zones_records" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_zone_id INTEGER NOT NULL,
@@ -675,5 +722,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (77, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (81, strftime("%s"))
 `
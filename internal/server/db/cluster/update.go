@@ -114,6 +114,85 @@ var updates = map[int]schema.Update{
 	75: updateFromV74,
 	76: updateFromV75,
 	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+}
+
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "networks_reservations" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    owner TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (network_id, address),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "networks_wireguard_peers" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    endpoint TEXT NOT NULL,
+    updated_date DATETIME NOT NULL,
+    UNIQUE (network_id, node_id),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "networks_zones_keys" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    state TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    updated_date DATETIME NOT NULL,
+    FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "audit_entries" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    date DATETIME NOT NULL,
+    node TEXT NOT NULL,
+    username TEXT NOT NULL,
+    protocol TEXT NOT NULL,
+    address TEXT NOT NULL,
+    method TEXT NOT NULL,
+    entity_url TEXT NOT NULL,
+    project TEXT NOT NULL,
+    body_digest TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    operation TEXT NOT NULL
+);
+CREATE INDEX audit_entries_date ON audit_entries (date);
+`
+	_, err := tx.Exec(stmts)
+	return err
 }
 
 func updateFromV76(ctx context.Context, tx *sql.Tx) error {
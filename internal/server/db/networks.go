@@ -933,6 +933,7 @@ var nodeSpecificNetworkConfig = []string{
 	"bgp.ipv6.nexthop",
 	"bridge.external_interfaces",
 	"parent",
+	"wireguard.address",
 }
 
 // nodeSpecificNetworkConfigRe lists dynamic network config keys which are node-specific.
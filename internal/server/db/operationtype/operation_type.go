@@ -80,6 +80,16 @@ const (
 	BucketBackupRename
 	BucketBackupRestore
 	VolumeRebuild
+	ManifestApply
+	AuditPrune
+	BucketLifecycleApply
+	NetworkZoneKeysRollover
+	VolumeRekey
+	InstanceReplicate
+	CustomVolumeReplicate
+	BackupRepositoryVerify
+	BackupRepositoryPrune
+	StoragePoolScrub
 )
 
 // Description return a human-readable description of the operation type.
@@ -213,6 +223,26 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case ManifestApply:
+		return "Applying manifest"
+	case AuditPrune:
+		return "Pruning expired audit log entries"
+	case BucketLifecycleApply:
+		return "Applying bucket lifecycle rules"
+	case NetworkZoneKeysRollover:
+		return "Rolling over network zone DNSSEC keys"
+	case VolumeRekey:
+		return "Rotating storage volume passphrase"
+	case InstanceReplicate:
+		return "Replicating instances"
+	case CustomVolumeReplicate:
+		return "Replicating storage volumes"
+	case BackupRepositoryVerify:
+		return "Verifying backup repository"
+	case BackupRepositoryPrune:
+		return "Pruning backup repository"
+	case StoragePoolScrub:
+		return "Scrubbing storage pools"
 	default:
 		return "Executing operation"
 	}
@@ -268,6 +298,8 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
 	case InstanceRebuild:
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
+	case InstanceReplicate:
+		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
 	case SnapshotRestore:
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
 
@@ -296,6 +328,10 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
 	case VolumeRebuild:
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
+	case VolumeRekey:
+		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
+	case CustomVolumeReplicate:
+		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
 
 	case BucketBackupCreate:
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups
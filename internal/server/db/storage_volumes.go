@@ -692,6 +692,24 @@ func (c *ClusterTx) storageVolumeConfigGet(ctx context.Context, volumeID int64,
 	return config, nil
 }
 
+// StorageVolumeConfigValueUsed returns whether any storage volume or volume snapshot has the given config key set to the given value.
+func (c *ClusterTx) StorageVolumeConfigValueUsed(ctx context.Context, key string, value string) (bool, error) {
+	stmt := `
+SELECT COUNT(*) FROM (
+  SELECT storage_volume_id FROM storage_volumes_config WHERE key=? AND value=?
+  UNION ALL
+  SELECT storage_volume_snapshot_id FROM storage_volumes_snapshots_config WHERE key=? AND value=?
+)`
+
+	var count int
+	err := c.tx.QueryRowContext(ctx, stmt, key, value, key, value).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 // GetNextStorageVolumeSnapshotIndex returns the index of the next snapshot of the storage
 // volume with the given name should have.
 //
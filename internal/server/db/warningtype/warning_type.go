@@ -58,6 +58,14 @@ const (
 	UnableToUpdateClusterCertificate
 	// SELinuxNotAvailable represents the SELinux not available warning.
 	SELinuxNotAvailable
+	// InstanceUnhealthy represents an instance failing its health check.
+	InstanceUnhealthy
+	// ReplicationFailed represents a failure replicating an instance or custom volume.
+	ReplicationFailed
+	// StoragePoolDegraded represents a storage pool reporting degraded or failed health.
+	StoragePoolDegraded
+	// StoragePoolMetadataLow represents a thin pool whose metadata space is nearly exhausted.
+	StoragePoolMetadataLow
 )
 
 // TypeNames associates a warning code to its name.
@@ -87,6 +95,10 @@ var TypeNames = map[Type]string{
 	StoragePoolUnvailable:             "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:  "Unable to update cluster certificate",
 	SELinuxNotAvailable:               "SELinux support has been disabled",
+	InstanceUnhealthy:                 "Instance health check failing",
+	ReplicationFailed:                 "Replication failing",
+	StoragePoolDegraded:               "Storage pool degraded",
+	StoragePoolMetadataLow:            "Storage pool metadata space low",
 }
 
 // Severity returns the severity of the warning type.
@@ -142,6 +154,14 @@ func (t Type) Severity() Severity {
 		return SeverityLow
 	case SELinuxNotAvailable:
 		return SeverityLow
+	case InstanceUnhealthy:
+		return SeverityModerate
+	case ReplicationFailed:
+		return SeverityModerate
+	case StoragePoolDegraded:
+		return SeverityHigh
+	case StoragePoolMetadataLow:
+		return SeverityModerate
 	}
 
 	return SeverityLow
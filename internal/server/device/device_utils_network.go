@@ -708,6 +708,24 @@ func networkValidGatewayV6(value string) error {
 	return validate.IsNetworkAddressV6(value)
 }
 
+// networkCheckReservations checks that the NIC's static addresses aren't reserved on the network for
+// another owner than the instance.
+func networkCheckReservations(n network.Network, inst instance.Instance, config deviceConfig.Device) error {
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		ip := net.ParseIP(nicAddressIP(config[key]))
+		if ip == nil {
+			continue
+		}
+
+		err := n.ReservationCheckAddress(ip, inst.Project().Name, inst.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // bgpAddPrefix adds external routes to the BGP server.
 func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string) error {
 	// BGP is only valid when tied to a managed network.
@@ -542,6 +542,14 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader, partialValid
 		if err != nil {
 			return err
 		}
+
+		// Check the static addresses aren't reserved for another owner.
+		if d.network != nil {
+			err = networkCheckReservations(d.network, d.inst, d.config)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Check if security ACL(s) are configured.
@@ -1439,12 +1447,18 @@ func (d *nicBridged) setFilters() (err error) {
 
 	// If parent bridge is managed, allocate the static IPs (if needed).
 	if d.network != nil && (IPv4 == nil || IPv6 == nil) {
+		reserved, err := d.network.ReservedRanges()
+		if err != nil {
+			return err
+		}
+
 		opts := &dhcpalloc.Options{
 			ProjectName: d.inst.Project().Name,
 			HostName:    d.inst.Name(),
 			DeviceName:  d.Name(),
 			HostMAC:     mac,
 			Network:     d.network,
+			Reserved:    reserved,
 		}
 
 		err = dhcpalloc.AllocateTask(opts, func(t *dhcpalloc.Transaction) error {
@@ -497,6 +497,12 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader, partialValidatio
 		if err != nil {
 			return err
 		}
+
+		// Check the static addresses aren't reserved for another owner.
+		err = networkCheckReservations(n, d.inst, d.config)
+		if err != nil {
+			return err
+		}
 	}
 
 	rules := nicValidationRules(requiredFields, optionalFields, instConf)
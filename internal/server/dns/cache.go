@@ -0,0 +1,451 @@
+package dns
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// zoneCacheExpiry is how long the content of a zone is used to answer queries.
+// Changes to the zone itself invalidate it right away, the expiry bounds how long it takes for changes to the
+// instances and networks the zone is generated from, or for changes made on other cluster members, to show up.
+const zoneCacheExpiry = 30 * time.Second
+
+// zoneCacheMaxAnswers is the maximum number of answers cached for a zone.
+const zoneCacheMaxAnswers = 1024
+
+// maxCNAMEDepth is the maximum number of CNAME records followed within a zone.
+const maxCNAMEDepth = 8
+
+// cachedAnswer is the cached response to a query.
+type cachedAnswer struct {
+	answer []dns.RR
+	ns     []dns.RR
+	rcode  int
+}
+
+// cachedZone is the parsed content of a zone, along with the responses to the queries it answered.
+// A cachedZone without zone records a name which isn't a zone.
+type cachedZone struct {
+	zone    *Zone
+	name    string
+	soa     dns.RR
+	records map[string][]dns.RR
+	answers map[string]cachedAnswer
+	expiry  time.Time
+
+	// Denial of existence records (NSEC or NSEC3) of signed zones, and the records of each owner name
+	// along with their signatures.
+	signed        bool
+	denial        []dns.RR
+	denialRecords map[string][]dns.RR
+}
+
+// newCachedZone parses the content of the zone, signing it if it has DNSSEC keys.
+func newCachedZone(zone *Zone, expiry time.Time) (*cachedZone, error) {
+	z := &cachedZone{
+		zone:          zone,
+		name:          dns.CanonicalName(zone.Info.Name),
+		records:       map[string][]dns.RR{},
+		answers:       map[string]cachedAnswer{},
+		expiry:        expiry,
+		denialRecords: map[string][]dns.RR{},
+	}
+
+	records, err := parseZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(zone.Keys) > 0 && len(records) > 0 {
+		records, err = signZoneRecords(zone, records, false)
+		if err != nil {
+			return nil, err
+		}
+
+		z.signed = true
+	}
+
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeSOA {
+			z.soa = rr
+		}
+
+		owner := dns.CanonicalName(rr.Header().Name)
+		if !dns.IsSubDomain(z.name, owner) {
+			continue
+		}
+
+		// Keep the denial of existence records apart so the hashed NSEC3 names don't exist.
+		if isDenialRecord(rr) {
+			if rr.Header().Rrtype != dns.TypeRRSIG {
+				z.denial = append(z.denial, rr)
+			}
+
+			z.denialRecords[owner] = append(z.denialRecords[owner], rr)
+			continue
+		}
+
+		z.records[owner] = append(z.records[owner], rr)
+
+		// Record the names between the owner and the zone apex so they exist without records.
+		for parent := owner; parent != z.name; {
+			i, end := dns.NextLabel(parent, 0)
+			if end {
+				break
+			}
+
+			parent = parent[i:]
+
+			_, found := z.records[parent]
+			if !found {
+				z.records[parent] = []dns.RR{}
+			}
+		}
+	}
+
+	return z, nil
+}
+
+// parseZone returns the records of the zone content.
+// The content starts and ends with the SOA record as expected by zone transfers, only the first one is kept.
+func parseZone(zone *Zone) ([]dns.RR, error) {
+	records := []dns.RR{}
+	hasSOA := false
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(zone.Content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, fmt.Errorf("Bad DNS record in zone %q: %w", zone.Info.Name, err)
+			}
+
+			break
+		}
+
+		if rr.Header().Rrtype == dns.TypeSOA {
+			if hasSOA {
+				continue
+			}
+
+			hasSOA = true
+		}
+
+		records = append(records, rr)
+	}
+
+	return records, nil
+}
+
+// isDenialRecord returns whether the record is a NSEC or NSEC3 record, or the signature of one.
+func isDenialRecord(rr dns.RR) bool {
+	rrtype := rr.Header().Rrtype
+
+	rrsig, ok := rr.(*dns.RRSIG)
+	if ok {
+		rrtype = rrsig.TypeCovered
+	}
+
+	return rrtype == dns.TypeNSEC || rrtype == dns.TypeNSEC3
+}
+
+// answer returns the response to a query for a name of the zone.
+// The DNSSEC records are only included when requested and the zone is signed.
+func (z *cachedZone) answer(name string, qtype uint16, dnssec bool) cachedAnswer {
+	dnssec = dnssec && z.signed
+	key := fmt.Sprintf("%s/%d/%t", name, qtype, dnssec)
+
+	cached, ok := z.answers[key]
+	if ok {
+		return cached
+	}
+
+	answer := cachedAnswer{}
+	answer.answer, answer.rcode = z.resolve(name, qtype, dnssec, 0)
+
+	// The response is about the last name of the CNAME chain.
+	target := name
+	for _, rr := range answer.answer {
+		cname, ok := rr.(*dns.CNAME)
+		if ok {
+			target = dns.CanonicalName(cname.Target)
+		}
+	}
+
+	negative := qtype != dns.TypeCNAME && !slices.ContainsFunc(answer.answer, func(rr dns.RR) bool { return rr.Header().Rrtype == qtype })
+
+	if negative && z.soa != nil && dns.IsSubDomain(z.name, target) {
+		// Include the SOA record in negative responses so they can be cached.
+		answer.ns = filterRRset(z.records[z.name], dns.TypeSOA, dnssec)
+		if dnssec {
+			answer.ns = append(answer.ns, z.denialProof(target, answer.rcode == dns.RcodeNameError)...)
+		}
+	} else if dnssec && answer.rcode == dns.RcodeSuccess {
+		// Answers synthesized from a wildcard come with the proof that the name itself doesn't exist.
+		_, found := z.records[name]
+		if !found {
+			answer.ns = z.wildcardProof(name)
+		}
+	}
+
+	if len(z.answers) < zoneCacheMaxAnswers {
+		z.answers[key] = answer
+	}
+
+	return answer
+}
+
+// resolve looks up the records of a name of the zone, following CNAME records within the zone.
+func (z *cachedZone) resolve(name string, qtype uint16, dnssec bool, depth int) ([]dns.RR, int) {
+	records, found := z.records[name]
+	if !found {
+		// Look for a matching wildcard record.
+		i, end := dns.NextLabel(name, 0)
+		if !end && name != z.name {
+			wildcardRecords, ok := z.records["*."+name[i:]]
+			if ok {
+				found = true
+				records = make([]dns.RR, 0, len(wildcardRecords))
+				for _, rr := range wildcardRecords {
+					rr = dns.Copy(rr)
+					rr.Header().Name = name
+					records = append(records, rr)
+				}
+			}
+		}
+	}
+
+	if !found {
+		return nil, dns.RcodeNameError
+	}
+
+	answer := filterRRset(records, qtype, dnssec)
+	if len(answer) > 0 || qtype == dns.TypeCNAME {
+		return answer, dns.RcodeSuccess
+	}
+
+	for _, rr := range records {
+		cname, ok := rr.(*dns.CNAME)
+		if !ok {
+			continue
+		}
+
+		answer = filterRRset(records, dns.TypeCNAME, dnssec)
+
+		// The response code is the one of the last name of the chain.
+		target := dns.CanonicalName(cname.Target)
+		if depth < maxCNAMEDepth && dns.IsSubDomain(z.name, target) {
+			targetAnswer, rcode := z.resolve(target, qtype, dnssec, depth+1)
+			return append(answer, targetAnswer...), rcode
+		}
+
+		break
+	}
+
+	return answer, dns.RcodeSuccess
+}
+
+// filterRRset returns the records of the given type, along with their signatures if requested.
+func filterRRset(records []dns.RR, rrtype uint16, dnssec bool) []dns.RR {
+	rrset := []dns.RR{}
+	for _, rr := range records {
+		if rr.Header().Rrtype == rrtype {
+			rrset = append(rrset, rr)
+			continue
+		}
+
+		rrsig, ok := rr.(*dns.RRSIG)
+		if dnssec && ok && rrsig.TypeCovered == rrtype {
+			rrset = append(rrset, rr)
+		}
+	}
+
+	return rrset
+}
+
+// denialProof returns the signed NSEC or NSEC3 records proving that the name doesn't exist, or that it
+// doesn't have records of the queried type.
+func (z *cachedZone) denialProof(name string, nxdomain bool) []dns.RR {
+	if !nxdomain {
+		match := z.denialMatching(name)
+		if match == nil {
+			// Empty non-terminals don't have NSEC records, a covering one proves they are empty.
+			match = z.denialCovering(name)
+		}
+
+		return z.denialSet(match)
+	}
+
+	encloser, nextCloser := z.closestEncloser(name)
+
+	// Prove that the name doesn't exist and that no wildcard could have matched it.
+	if z.usesNSEC3() {
+		return z.denialSet(z.denialMatching(encloser), z.denialCovering(nextCloser), z.denialCovering("*."+encloser))
+	}
+
+	return z.denialSet(z.denialCovering(name), z.denialCovering("*."+encloser))
+}
+
+// wildcardProof returns the signed NSEC or NSEC3 records proving that the name answered from a wildcard
+// doesn't exist itself.
+func (z *cachedZone) wildcardProof(name string) []dns.RR {
+	if z.usesNSEC3() {
+		_, nextCloser := z.closestEncloser(name)
+		return z.denialSet(z.denialCovering(nextCloser))
+	}
+
+	return z.denialSet(z.denialCovering(name))
+}
+
+// closestEncloser returns the closest existing ancestor of the name, along with the name one label below it.
+func (z *cachedZone) closestEncloser(name string) (string, string) {
+	nextCloser := name
+	for i, end := dns.NextLabel(name, 0); !end; i, end = dns.NextLabel(name, i) {
+		_, found := z.records[name[i:]]
+		if found {
+			return name[i:], nextCloser
+		}
+
+		nextCloser = name[i:]
+	}
+
+	return z.name, nextCloser
+}
+
+// denialSet returns the given denial of existence records along with their signatures, without duplicates.
+func (z *cachedZone) denialSet(records ...dns.RR) []dns.RR {
+	owners := []string{}
+	for _, rr := range records {
+		if rr == nil {
+			continue
+		}
+
+		owner := dns.CanonicalName(rr.Header().Name)
+		if !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+
+	set := []dns.RR{}
+	for _, owner := range owners {
+		set = append(set, z.denialRecords[owner]...)
+	}
+
+	return set
+}
+
+// usesNSEC3 returns whether the zone is signed with NSEC3 rather than NSEC records.
+func (z *cachedZone) usesNSEC3() bool {
+	if len(z.denial) == 0 {
+		return false
+	}
+
+	_, ok := z.denial[0].(*dns.NSEC3)
+	return ok
+}
+
+// denialMatching returns the NSEC or NSEC3 record of the name.
+func (z *cachedZone) denialMatching(name string) dns.RR {
+	for _, rr := range z.denial {
+		switch denial := rr.(type) {
+		case *dns.NSEC:
+			if dns.CanonicalName(denial.Hdr.Name) == name {
+				return rr
+			}
+
+		case *dns.NSEC3:
+			if denial.Match(name) {
+				return rr
+			}
+		}
+	}
+
+	return nil
+}
+
+// denialCovering returns the NSEC or NSEC3 record covering the name.
+func (z *cachedZone) denialCovering(name string) dns.RR {
+	for _, rr := range z.denial {
+		switch denial := rr.(type) {
+		case *dns.NSEC:
+			if nsecCovers(denial, name) {
+				return rr
+			}
+
+		case *dns.NSEC3:
+			if denial.Cover(name) {
+				return rr
+			}
+		}
+	}
+
+	return nil
+}
+
+// cachedZone returns the zone with the given name from the cache, retrieving it if missing or expired.
+// Must be called with the server lock held.
+func (s *Server) cachedZone(name string) (*cachedZone, error) {
+	now := time.Now()
+
+	z, ok := s.zoneCache[name]
+	if ok && now.Before(z.expiry) {
+		return z, nil
+	}
+
+	zone, err := s.zoneRetriever(strings.TrimSuffix(name, "."), true)
+	if err != nil {
+		// Remember the name isn't a zone.
+		z = &cachedZone{expiry: now.Add(zoneCacheExpiry)}
+	} else {
+		z, err = newCachedZone(zone, now.Add(zoneCacheExpiry))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.zoneCache == nil {
+		s.zoneCache = map[string]*cachedZone{}
+	}
+
+	// Drop the expired entries so that queries for random names don't grow the cache.
+	for cachedName, cached := range s.zoneCache {
+		if !now.Before(cached.expiry) {
+			delete(s.zoneCache, cachedName)
+		}
+	}
+
+	s.zoneCache[name] = z
+
+	return z, nil
+}
+
+// findZone returns the closest zone the name belongs to, or nil if it doesn't belong to any.
+// Must be called with the server lock held.
+func (s *Server) findZone(name string) (*cachedZone, error) {
+	for i, end := 0, false; !end; i, end = dns.NextLabel(name, i) {
+		z, err := s.cachedZone(name[i:])
+		if err != nil {
+			return nil, err
+		}
+
+		if z.zone != nil {
+			return z, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// InvalidateZone drops the cached content of the zone, so that queries are answered from its new content.
+func (s *Server) InvalidateZone(name string) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.zoneCache, dns.CanonicalName(name))
+}
@@ -0,0 +1,302 @@
+package dns
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// testZoneContent is a zone with CNAME chains, an empty non-terminal and a wildcard.
+var testZoneContent = strings.Join([]string{
+	"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 120 60 86400 300",
+	"example.com. 3600 IN NS ns1.example.com.",
+	"ns1.example.com. 300 IN A 192.0.2.1",
+	"a.example.com. 300 IN A 192.0.2.2",
+	"alias.example.com. 300 IN CNAME a.example.com.",
+	"chain.example.com. 300 IN CNAME alias.example.com.",
+	"dangling.example.com. 300 IN CNAME missing.example.com.",
+	"external.example.com. 300 IN CNAME www.example.net.",
+	"loop1.example.com. 300 IN CNAME loop2.example.com.",
+	"loop2.example.com. 300 IN CNAME loop1.example.com.",
+	"host.ent.example.com. 300 IN A 192.0.2.3",
+	"*.wild.example.com. 300 IN A 192.0.2.4",
+	"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 120 60 86400 300",
+}, "\n")
+
+// newTestCachedZone returns the cached test zone, signed with NSEC or NSEC3 records if requested.
+func newTestCachedZone(t *testing.T, signed bool, nsec3 bool) *cachedZone {
+	t.Helper()
+
+	zone := &Zone{
+		Info:    api.NetworkZone{Name: "example.com", NetworkZonePut: api.NetworkZonePut{Config: map[string]string{}}},
+		Content: testZoneContent,
+	}
+
+	if nsec3 {
+		zone.Info.Config["dnssec.nsec3"] = "true"
+	}
+
+	if signed {
+		key, err := GenerateZoneKey(zone.Info.Name, false)
+		require.NoError(t, err)
+
+		key.Active = true
+		zone.Keys = append(zone.Keys, *key)
+	}
+
+	z, err := newCachedZone(zone, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	return z
+}
+
+// recordNames returns the owner and type of the records.
+func recordNames(records []dns.RR) []string {
+	names := []string{}
+	for _, rr := range records {
+		names = append(names, rr.Header().Name+" "+dns.TypeToString[rr.Header().Rrtype])
+	}
+
+	return names
+}
+
+func TestCachedZone_answer(t *testing.T) {
+	tests := []struct {
+		name     string
+		qname    string
+		qtype    uint16
+		answer   []string
+		rcode    int
+		negative bool
+	}{
+		{
+			name:   "Existing record",
+			qname:  "a.example.com.",
+			qtype:  dns.TypeA,
+			answer: []string{"a.example.com. A"},
+		},
+		{
+			name:     "Missing type",
+			qname:    "a.example.com.",
+			qtype:    dns.TypeAAAA,
+			answer:   []string{},
+			negative: true,
+		},
+		{
+			name:     "Missing name",
+			qname:    "missing.example.com.",
+			qtype:    dns.TypeA,
+			answer:   []string{},
+			rcode:    dns.RcodeNameError,
+			negative: true,
+		},
+		{
+			name:     "Empty non-terminal",
+			qname:    "ent.example.com.",
+			qtype:    dns.TypeA,
+			answer:   []string{},
+			negative: true,
+		},
+		{
+			name:   "CNAME",
+			qname:  "alias.example.com.",
+			qtype:  dns.TypeA,
+			answer: []string{"alias.example.com. CNAME", "a.example.com. A"},
+		},
+		{
+			name:   "CNAME chain",
+			qname:  "chain.example.com.",
+			qtype:  dns.TypeA,
+			answer: []string{"chain.example.com. CNAME", "alias.example.com. CNAME", "a.example.com. A"},
+		},
+		{
+			name:   "CNAME query",
+			qname:  "alias.example.com.",
+			qtype:  dns.TypeCNAME,
+			answer: []string{"alias.example.com. CNAME"},
+		},
+		{
+			name:     "CNAME to missing type",
+			qname:    "alias.example.com.",
+			qtype:    dns.TypeAAAA,
+			answer:   []string{"alias.example.com. CNAME"},
+			negative: true,
+		},
+		{
+			name:     "CNAME to missing name",
+			qname:    "dangling.example.com.",
+			qtype:    dns.TypeA,
+			answer:   []string{"dangling.example.com. CNAME"},
+			rcode:    dns.RcodeNameError,
+			negative: true,
+		},
+		{
+			name:   "CNAME out of the zone",
+			qname:  "external.example.com.",
+			qtype:  dns.TypeA,
+			answer: []string{"external.example.com. CNAME"},
+		},
+		{
+			name:   "Wildcard",
+			qname:  "host.wild.example.com.",
+			qtype:  dns.TypeA,
+			answer: []string{"host.wild.example.com. A"},
+		},
+	}
+
+	z := newTestCachedZone(t, false, false)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer := z.answer(tt.qname, tt.qtype, false)
+			assert.Equal(t, tt.answer, recordNames(answer.answer))
+			assert.Equal(t, tt.rcode, answer.rcode)
+
+			// Negative responses come with the SOA record so they can be cached.
+			if tt.negative {
+				assert.Equal(t, []string{"example.com. SOA"}, recordNames(answer.ns))
+			} else {
+				assert.Empty(t, answer.ns)
+			}
+		})
+	}
+}
+
+func TestCachedZone_answerCNAMELoop(t *testing.T) {
+	z := newTestCachedZone(t, false, false)
+
+	// The chain is only followed up to the maximum depth.
+	answer := z.answer("loop1.example.com.", dns.TypeA, false)
+	assert.Len(t, answer.answer, maxCNAMEDepth+1)
+	assert.Equal(t, dns.RcodeSuccess, answer.rcode)
+
+	for _, rr := range answer.answer {
+		assert.Equal(t, dns.TypeCNAME, rr.Header().Rrtype)
+	}
+}
+
+func TestCachedZone_answerNSEC(t *testing.T) {
+	z := newTestCachedZone(t, true, false)
+
+	nsecs := func(records []dns.RR) []*dns.NSEC {
+		nsecs := []*dns.NSEC{}
+		for _, rr := range records {
+			nsec, ok := rr.(*dns.NSEC)
+			if ok {
+				nsecs = append(nsecs, nsec)
+			}
+		}
+
+		return nsecs
+	}
+
+	// NODATA is proven by the NSEC record of the name, which doesn't list the type.
+	answer := z.answer("a.example.com.", dns.TypeAAAA, true)
+	assert.Equal(t, dns.RcodeSuccess, answer.rcode)
+	assert.Contains(t, recordNames(answer.ns), "example.com. SOA")
+
+	proof := nsecs(answer.ns)
+	require.Len(t, proof, 1)
+	assert.Equal(t, "a.example.com.", proof[0].Hdr.Name)
+	assert.NotContains(t, proof[0].TypeBitMap, dns.TypeAAAA)
+
+	// NODATA for an empty non-terminal is proven by the NSEC record covering it.
+	answer = z.answer("ent.example.com.", dns.TypeA, true)
+	assert.Equal(t, dns.RcodeSuccess, answer.rcode)
+
+	proof = nsecs(answer.ns)
+	require.Len(t, proof, 1)
+	assert.True(t, nsecCovers(proof[0], "ent.example.com."))
+
+	// NXDOMAIN is proven by the NSEC records covering the name and the wildcard which could have matched it.
+	for _, qname := range []string{"missing.example.com.", "dangling.example.com."} {
+		answer = z.answer(qname, dns.TypeA, true)
+		assert.Equal(t, dns.RcodeNameError, answer.rcode, qname)
+
+		proof = nsecs(answer.ns)
+		require.NotEmpty(t, proof, qname)
+
+		coversName := false
+		coversWildcard := false
+		for _, nsec := range proof {
+			coversName = coversName || nsecCovers(nsec, "missing.example.com.")
+			coversWildcard = coversWildcard || nsecCovers(nsec, "*.example.com.")
+		}
+
+		assert.True(t, coversName, qname)
+		assert.True(t, coversWildcard, qname)
+	}
+
+	// Every NSEC record comes with its signature.
+	signatures := filterRRset(answer.ns, dns.TypeNSEC, true)
+	assert.Len(t, signatures, 2*len(proof))
+
+	// Answers synthesized from a wildcard prove the name doesn't exist.
+	answer = z.answer("host.wild.example.com.", dns.TypeA, true)
+	assert.Equal(t, dns.RcodeSuccess, answer.rcode)
+	assert.Contains(t, recordNames(answer.answer), "host.wild.example.com. RRSIG")
+
+	proof = nsecs(answer.ns)
+	require.Len(t, proof, 1)
+	assert.True(t, nsecCovers(proof[0], "host.wild.example.com."))
+
+	// DNSSEC records are left out unless requested.
+	answer = z.answer("a.example.com.", dns.TypeAAAA, false)
+	assert.Equal(t, []string{"example.com. SOA"}, recordNames(answer.ns))
+}
+
+func TestCachedZone_answerNSEC3(t *testing.T) {
+	z := newTestCachedZone(t, true, true)
+
+	nsec3s := func(records []dns.RR) []*dns.NSEC3 {
+		nsec3s := []*dns.NSEC3{}
+		for _, rr := range records {
+			nsec3, ok := rr.(*dns.NSEC3)
+			if ok {
+				nsec3s = append(nsec3s, nsec3)
+			}
+		}
+
+		return nsec3s
+	}
+
+	// The hashed names aren't names of the zone.
+	for _, rr := range z.denial {
+		answer := z.answer(dns.CanonicalName(rr.Header().Name), dns.TypeNSEC3, true)
+		assert.Equal(t, dns.RcodeNameError, answer.rcode)
+	}
+
+	// NODATA is proven by the NSEC3 record matching the name, including empty non-terminals.
+	for _, qname := range []string{"a.example.com.", "ent.example.com."} {
+		answer := z.answer(qname, dns.TypeAAAA, true)
+		assert.Equal(t, dns.RcodeSuccess, answer.rcode, qname)
+
+		proof := nsec3s(answer.ns)
+		require.Len(t, proof, 1, qname)
+		assert.True(t, proof[0].Match(qname), qname)
+		assert.NotContains(t, proof[0].TypeBitMap, dns.TypeAAAA, qname)
+	}
+
+	// NXDOMAIN is proven by the closest encloser, the next closer name and the wildcard.
+	answer := z.answer("x.missing.example.com.", dns.TypeA, true)
+	assert.Equal(t, dns.RcodeNameError, answer.rcode)
+
+	matchesEncloser := false
+	coversNextCloser := false
+	coversWildcard := false
+	for _, nsec3 := range nsec3s(answer.ns) {
+		matchesEncloser = matchesEncloser || nsec3.Match("example.com.")
+		coversNextCloser = coversNextCloser || nsec3.Cover("missing.example.com.")
+		coversWildcard = coversWildcard || nsec3.Cover("*.example.com.")
+	}
+
+	assert.True(t, matchesEncloser)
+	assert.True(t, coversNextCloser)
+	assert.True(t, coversWildcard)
+}
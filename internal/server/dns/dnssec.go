@@ -0,0 +1,482 @@
+package dns
+
+import (
+	"cmp"
+	"crypto"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// zoneKeyAlgorithm is the algorithm of the generated zone keys.
+const zoneKeyAlgorithm = dns.ECDSAP256SHA256
+
+// zoneKeyTTL is the TTL of the DNSKEY records.
+const zoneKeyTTL = 3600
+
+// zoneSignatureValidity is how long the signatures of a zone are valid for.
+// Signatures are generated online, the validity only needs to cover the time they can be cached for.
+const zoneSignatureValidity = 7 * 24 * time.Hour
+
+// zoneSignatureSkew is how far back the signatures of a zone are valid from, to allow for clock skew.
+const zoneSignatureSkew = time.Hour
+
+// ZoneKey represents a DNSSEC key of a zone.
+type ZoneKey struct {
+	// KSK is true for key signing keys, which only sign the DNSKEY records.
+	KSK bool
+
+	// Active is true for keys signing the zone, other keys are only published.
+	Active bool
+
+	// PublicKey is the DNSKEY record of the key.
+	PublicKey string
+
+	// PrivateKey is the private key, in the BIND private key format.
+	PrivateKey string
+}
+
+// GenerateZoneKey generates a new DNSSEC key for the zone.
+func GenerateZoneKey(zoneName string, ksk bool) (*ZoneKey, error) {
+	dnskey := &dns.DNSKEY{
+		Hdr: dns.RR_Header{
+			Name:   dns.Fqdn(zoneName),
+			Rrtype: dns.TypeDNSKEY,
+			Class:  dns.ClassINET,
+			Ttl:    zoneKeyTTL,
+		},
+		Flags:     dns.ZONE,
+		Protocol:  3,
+		Algorithm: zoneKeyAlgorithm,
+	}
+
+	if ksk {
+		dnskey.Flags |= dns.SEP
+	}
+
+	privateKey, err := dnskey.Generate(256)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating DNSSEC key: %w", err)
+	}
+
+	return &ZoneKey{
+		KSK:        ksk,
+		PublicKey:  dnskey.String(),
+		PrivateKey: dnskey.PrivateKeyString(privateKey),
+	}, nil
+}
+
+// DNSKEY returns the parsed DNSKEY record of the key.
+func (k ZoneKey) DNSKEY() (*dns.DNSKEY, error) {
+	rr, err := dns.NewRR(k.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing DNSSEC public key: %w", err)
+	}
+
+	dnskey, ok := rr.(*dns.DNSKEY)
+	if !ok {
+		return nil, errors.New("DNSSEC public key isn't a DNSKEY record")
+	}
+
+	return dnskey, nil
+}
+
+// DS returns the DS record of the key, to be added to the parent zone.
+func (k ZoneKey) DS() (string, error) {
+	dnskey, err := k.DNSKEY()
+	if err != nil {
+		return "", err
+	}
+
+	ds := dnskey.ToDS(dns.SHA256)
+	if ds == nil {
+		return "", errors.New("Failed computing DS record")
+	}
+
+	return ds.String(), nil
+}
+
+// zoneSigningKey is a zone key ready to sign records.
+type zoneSigningKey struct {
+	dnskey *dns.DNSKEY
+	signer crypto.Signer
+}
+
+// zoneSigner signs the records of a zone.
+type zoneSigner struct {
+	name       string
+	dnskeys    []dns.RR
+	zsks       []zoneSigningKey
+	ksks       []zoneSigningKey
+	inception  uint32
+	expiration uint32
+}
+
+// newZoneSigner returns a signer for the zone using its active keys, with signatures valid from now.
+func newZoneSigner(zone *Zone, now time.Time) (*zoneSigner, error) {
+	s := &zoneSigner{
+		name:       dns.CanonicalName(zone.Info.Name),
+		inception:  uint32(now.Add(-zoneSignatureSkew).Unix()),
+		expiration: uint32(now.Add(zoneSignatureValidity).Unix()),
+	}
+
+	for _, key := range zone.Keys {
+		dnskey, err := key.DNSKEY()
+		if err != nil {
+			return nil, err
+		}
+
+		s.dnskeys = append(s.dnskeys, dnskey)
+
+		if !key.Active {
+			continue
+		}
+
+		privateKey, err := dnskey.ReadPrivateKey(strings.NewReader(key.PrivateKey), "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing DNSSEC private key: %w", err)
+		}
+
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("DNSSEC private key can't be used for signing")
+		}
+
+		if key.KSK {
+			s.ksks = append(s.ksks, zoneSigningKey{dnskey: dnskey, signer: signer})
+		} else {
+			s.zsks = append(s.zsks, zoneSigningKey{dnskey: dnskey, signer: signer})
+		}
+	}
+
+	if len(s.zsks) == 0 {
+		return nil, fmt.Errorf("No active DNSSEC zone signing key for zone %q", zone.Info.Name)
+	}
+
+	// Without key signing key, the zone signing keys sign the DNSKEY records too.
+	if len(s.ksks) == 0 {
+		s.ksks = s.zsks
+	}
+
+	return s, nil
+}
+
+// signRRset returns the signatures of the RRset.
+// The TTL of the records is aligned on the lowest one as all the records of a signed RRset must share it.
+func (s *zoneSigner) signRRset(rrset []dns.RR) ([]dns.RR, error) {
+	ttl := rrset[0].Header().Ttl
+	for _, rr := range rrset {
+		ttl = min(ttl, rr.Header().Ttl)
+	}
+
+	for _, rr := range rrset {
+		rr.Header().Ttl = ttl
+	}
+
+	keys := s.zsks
+	if rrset[0].Header().Rrtype == dns.TypeDNSKEY {
+		keys = s.ksks
+	}
+
+	sigs := make([]dns.RR, 0, len(keys))
+	for _, key := range keys {
+		rrsig := &dns.RRSIG{
+			Hdr:        dns.RR_Header{Ttl: ttl},
+			Algorithm:  key.dnskey.Algorithm,
+			KeyTag:     key.dnskey.KeyTag(),
+			SignerName: s.name,
+			Inception:  s.inception,
+			Expiration: s.expiration,
+		}
+
+		err := rrsig.Sign(key.signer, rrset)
+		if err != nil {
+			return nil, fmt.Errorf("Failed signing %s records of %q: %w", dns.TypeToString[rrset[0].Header().Rrtype], rrset[0].Header().Name, err)
+		}
+
+		sigs = append(sigs, rrsig)
+	}
+
+	return sigs, nil
+}
+
+// signZone returns the records of the zone along with its DNSKEY records, its NSEC or NSEC3 chain and the
+// signatures of all the authoritative RRsets. The records must start with the SOA record of the zone.
+func (s *zoneSigner) signZone(records []dns.RR, nsec3 bool) ([]dns.RR, error) {
+	soa, ok := records[0].(*dns.SOA)
+	if !ok {
+		return nil, errors.New("Zone doesn't start with a SOA record")
+	}
+
+	// Denial of existence records are cached for the negative caching TTL of the zone.
+	negativeTTL := min(soa.Hdr.Ttl, soa.Minttl)
+
+	records = append(records, s.dnskeys...)
+	if nsec3 {
+		records = append(records, &dns.NSEC3PARAM{
+			Hdr:  dns.RR_Header{Name: s.name, Rrtype: dns.TypeNSEC3PARAM, Class: dns.ClassINET},
+			Hash: dns.SHA1,
+		})
+	}
+
+	// Group the records into RRsets.
+	type rrsetKey struct {
+		name   string
+		rrtype uint16
+	}
+
+	rrsets := map[rrsetKey][]dns.RR{}
+	order := []rrsetKey{}
+	types := map[string][]uint16{}
+
+	for _, rr := range records {
+		owner := dns.CanonicalName(rr.Header().Name)
+		if !dns.IsSubDomain(s.name, owner) {
+			continue
+		}
+
+		key := rrsetKey{name: owner, rrtype: rr.Header().Rrtype}
+
+		_, found := rrsets[key]
+		if !found {
+			order = append(order, key)
+			types[owner] = append(types[owner], key.rrtype)
+		}
+
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	// Names with NS records below the apex are delegations, only their DS records are authoritative
+	// and the records below them are glue.
+	delegations := map[string]bool{}
+	for name, nameTypes := range types {
+		if name != s.name && slices.Contains(nameTypes, dns.TypeNS) {
+			delegations[name] = true
+		}
+	}
+
+	isGlue := func(name string) bool {
+		for i, end := dns.NextLabel(name, 0); !end; i, end = dns.NextLabel(name, i) {
+			parent := name[i:]
+			if !dns.IsSubDomain(s.name, parent) || parent == s.name {
+				return false
+			}
+
+			if delegations[parent] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// authoritativeTypes returns the types of the authoritative RRsets of a name.
+	authoritativeTypes := func(name string) []uint16 {
+		if !delegations[name] {
+			return types[name]
+		}
+
+		return slices.DeleteFunc(slices.Clone(types[name]), func(rrtype uint16) bool {
+			return rrtype != dns.TypeNS && rrtype != dns.TypeDS
+		})
+	}
+
+	// isSigned returns whether the RRset is signed.
+	isSigned := func(name string, rrtype uint16) bool {
+		return !delegations[name] || rrtype == dns.TypeDS
+	}
+
+	names := []string{}
+	for name := range types {
+		if !isGlue(name) {
+			names = append(names, name)
+		}
+	}
+
+	out := make([]dns.RR, 0, len(records)*2)
+	for _, key := range order {
+		if isGlue(key.name) {
+			out = append(out, rrsets[key]...)
+			continue
+		}
+
+		rrset := rrsets[key]
+		out = append(out, rrset...)
+
+		if !isSigned(key.name, key.rrtype) {
+			continue
+		}
+
+		sigs, err := s.signRRset(rrset)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, sigs...)
+	}
+
+	var chain []dns.RR
+	if nsec3 {
+		chain = s.nsec3Chain(names, negativeTTL, authoritativeTypes, isSigned)
+	} else {
+		chain = s.nsecChain(names, negativeTTL, authoritativeTypes)
+	}
+
+	for _, rr := range chain {
+		sigs, err := s.signRRset([]dns.RR{rr})
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, rr)
+		out = append(out, sigs...)
+	}
+
+	return out, nil
+}
+
+// nsecChain returns the NSEC records linking the names of the zone.
+func (s *zoneSigner) nsecChain(names []string, ttl uint32, authoritativeTypes func(name string) []uint16) []dns.RR {
+	slices.SortFunc(names, canonicalCompare)
+
+	chain := make([]dns.RR, 0, len(names))
+	for i, name := range names {
+		bitmap := append(slices.Clone(authoritativeTypes(name)), dns.TypeNSEC, dns.TypeRRSIG)
+		slices.Sort(bitmap)
+
+		chain = append(chain, &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: ttl},
+			NextDomain: names[(i+1)%len(names)],
+			TypeBitMap: slices.Compact(bitmap),
+		})
+	}
+
+	return chain
+}
+
+// nsec3Chain returns the NSEC3 records linking the hashed names of the zone, including its empty non-terminals.
+// The names are hashed without salt nor additional iterations as recommended by RFC 9276.
+func (s *zoneSigner) nsec3Chain(names []string, ttl uint32, authoritativeTypes func(name string) []uint16, isSigned func(name string, rrtype uint16) bool) []dns.RR {
+	bitmaps := map[string][]uint16{}
+	hashes := []string{}
+
+	addName := func(name string, bitmap []uint16) {
+		hash := dns.HashName(name, dns.SHA1, 0, "")
+
+		_, found := bitmaps[hash]
+		if found && bitmap == nil {
+			return
+		}
+
+		if !found {
+			hashes = append(hashes, hash)
+		}
+
+		bitmaps[hash] = bitmap
+	}
+
+	for _, name := range names {
+		bitmap := slices.Clone(authoritativeTypes(name))
+		if slices.ContainsFunc(bitmap, func(rrtype uint16) bool { return isSigned(name, rrtype) }) {
+			bitmap = append(bitmap, dns.TypeRRSIG)
+		}
+
+		slices.Sort(bitmap)
+		addName(name, slices.Compact(bitmap))
+
+		// Empty non-terminals get records too.
+		for i, end := dns.NextLabel(name, 0); !end && name[i:] != s.name && dns.IsSubDomain(s.name, name[i:]); i, end = dns.NextLabel(name, i) {
+			addName(name[i:], nil)
+		}
+	}
+
+	slices.Sort(hashes)
+
+	chain := make([]dns.RR, 0, len(hashes))
+	for i, hash := range hashes {
+		chain = append(chain, &dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: strings.ToLower(hash) + "." + s.name, Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: ttl},
+			Hash:       dns.SHA1,
+			HashLength: 20,
+			NextDomain: hashes[(i+1)%len(hashes)],
+			TypeBitMap: bitmaps[hash],
+		})
+	}
+
+	return chain
+}
+
+// canonicalLabels returns the lowercased labels of the domain name in wire format, with escapes resolved.
+func canonicalLabels(name string) []string {
+	buf := make([]byte, 256)
+	off, err := dns.PackDomainName(dns.Fqdn(name), buf, 0, nil, false)
+	if err != nil {
+		return dns.SplitDomainName(dns.CanonicalName(name))
+	}
+
+	labels := []string{}
+	for i := 0; i < off && buf[i] != 0; i += int(buf[i]) + 1 {
+		label := buf[i+1 : i+1+int(buf[i])]
+		for j, c := range label {
+			if c >= 'A' && c <= 'Z' {
+				label[j] = c + 'a' - 'A'
+			}
+		}
+
+		labels = append(labels, string(label))
+	}
+
+	return labels
+}
+
+// canonicalCompare compares domain names in the canonical order of RFC 4034, comparing labels right to left
+// as lowercased octet strings.
+func canonicalCompare(a string, b string) int {
+	aLabels := canonicalLabels(a)
+	bLabels := canonicalLabels(b)
+
+	for i := 1; i <= min(len(aLabels), len(bLabels)); i++ {
+		c := strings.Compare(aLabels[len(aLabels)-i], bLabels[len(bLabels)-i])
+		if c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(aLabels), len(bLabels))
+}
+
+// nsecCovers returns whether the name falls between the owner and the next name of the NSEC record.
+func nsecCovers(nsec *dns.NSEC, name string) bool {
+	if canonicalCompare(nsec.Hdr.Name, name) >= 0 {
+		return false
+	}
+
+	// The last record of the chain covers everything after its owner.
+	return canonicalCompare(name, nsec.NextDomain) < 0 || canonicalCompare(nsec.NextDomain, nsec.Hdr.Name) <= 0
+}
+
+// signZoneRecords signs the parsed records of the zone. When rrsetOnly is set, the records are signed
+// as a single RRset, otherwise the whole zone is signed.
+func signZoneRecords(zone *Zone, records []dns.RR, rrsetOnly bool) ([]dns.RR, error) {
+	signer, err := newZoneSigner(zone, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if rrsetOnly {
+		sigs, err := signer.signRRset(records)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(records, sigs...), nil
+	}
+
+	return signer.signZone(records, util.IsTrue(zone.Info.Config["dnssec.nsec3"]))
+}
@@ -0,0 +1,290 @@
+package dns
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func TestCanonicalCompare(t *testing.T) {
+	// Names in the canonical order of RFC 4034 section 6.1.
+	names := []string{
+		"example.",
+		"a.example.",
+		"yljkjljk.a.example.",
+		"Z.a.example.",
+		"zABC.a.EXAMPLE.",
+		"z.example.",
+		"\\001.z.example.",
+		"*.z.example.",
+		"\\200.z.example.",
+	}
+
+	for i := range names {
+		for j := range names {
+			got := canonicalCompare(names[i], names[j])
+
+			switch {
+			case i < j:
+				assert.Negative(t, got, "%q < %q", names[i], names[j])
+			case i > j:
+				assert.Positive(t, got, "%q > %q", names[i], names[j])
+			default:
+				assert.Zero(t, got, "%q == %q", names[i], names[j])
+			}
+		}
+	}
+
+	// Sorting restores the order.
+	shuffled := slices.Clone(names)
+	slices.Reverse(shuffled)
+	slices.SortFunc(shuffled, canonicalCompare)
+	assert.Equal(t, names, shuffled)
+}
+
+func TestNsecCovers(t *testing.T) {
+	tests := []struct {
+		name   string
+		owner  string
+		next   string
+		query  string
+		covers bool
+	}{
+		{name: "Between owner and next", owner: "a.example.", next: "c.example.", query: "b.example.", covers: true},
+		{name: "Below owner", owner: "a.example.", next: "c.example.", query: "x.a.example.", covers: true},
+		{name: "Owner itself", owner: "a.example.", next: "c.example.", query: "a.example.", covers: false},
+		{name: "Next itself", owner: "a.example.", next: "c.example.", query: "c.example.", covers: false},
+		{name: "After next", owner: "a.example.", next: "c.example.", query: "d.example.", covers: false},
+		{name: "Last record after owner", owner: "z.example.", next: "example.", query: "zz.example.", covers: true},
+		{name: "Last record before owner", owner: "z.example.", next: "example.", query: "b.example.", covers: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsec := &dns.NSEC{Hdr: dns.RR_Header{Name: tt.owner}, NextDomain: tt.next}
+			assert.Equal(t, tt.covers, nsecCovers(nsec, tt.query))
+		})
+	}
+}
+
+// testSignedZone returns the signed records of a zone holding a delegation with glue and an empty non-terminal.
+func testSignedZone(t *testing.T, nsec3 bool) (*Zone, []dns.RR) {
+	t.Helper()
+
+	zone := &Zone{Info: api.NetworkZone{Name: "example.com", NetworkZonePut: api.NetworkZonePut{Config: map[string]string{}}}}
+	if nsec3 {
+		zone.Info.Config["dnssec.nsec3"] = "true"
+	}
+
+	for _, ksk := range []bool{true, false} {
+		key, err := GenerateZoneKey(zone.Info.Name, ksk)
+		require.NoError(t, err)
+
+		key.Active = true
+		zone.Keys = append(zone.Keys, *key)
+	}
+
+	content := []string{
+		"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 120 60 86400 300",
+		"example.com. 3600 IN NS ns1.example.com.",
+		"ns1.example.com. 300 IN A 192.0.2.1",
+		"a.example.com. 300 IN A 192.0.2.2",
+		"a.example.com. 300 IN AAAA 2001:db8::2",
+		"host.ent.example.com. 300 IN A 192.0.2.3",
+		"sub.example.com. 300 IN NS ns.sub.example.com.",
+		"ns.sub.example.com. 300 IN A 192.0.2.4",
+	}
+
+	records := make([]dns.RR, 0, len(content))
+	for _, line := range content {
+		rr, err := dns.NewRR(line)
+		require.NoError(t, err)
+
+		records = append(records, rr)
+	}
+
+	signer, err := newZoneSigner(zone, time.Now())
+	require.NoError(t, err)
+
+	signed, err := signer.signZone(records, nsec3)
+	require.NoError(t, err)
+
+	return zone, signed
+}
+
+// verifySignatures checks that every signed RRset of the zone validates and returns the signed RRsets.
+func verifySignatures(t *testing.T, zone *Zone, records []dns.RR) map[string]bool {
+	t.Helper()
+
+	dnskeys := map[uint16]*dns.DNSKEY{}
+	for _, key := range zone.Keys {
+		dnskey, err := key.DNSKEY()
+		require.NoError(t, err)
+
+		dnskeys[dnskey.KeyTag()] = dnskey
+	}
+
+	signed := map[string]bool{}
+	for _, rr := range records {
+		rrsig, ok := rr.(*dns.RRSIG)
+		if !ok {
+			continue
+		}
+
+		rrset := []dns.RR{}
+		for _, other := range records {
+			if other.Header().Rrtype == rrsig.TypeCovered && strings.EqualFold(other.Header().Name, rrsig.Hdr.Name) {
+				rrset = append(rrset, other)
+			}
+		}
+
+		dnskey := dnskeys[rrsig.KeyTag]
+		require.NotNil(t, dnskey)
+		assert.NoError(t, rrsig.Verify(dnskey, rrset), "%s %s", rrsig.Hdr.Name, dns.TypeToString[rrsig.TypeCovered])
+		assert.True(t, rrsig.ValidityPeriod(time.Now()))
+
+		// Only the DNSKEY records are signed by the key signing key.
+		assert.Equal(t, rrsig.TypeCovered == dns.TypeDNSKEY, dnskey.Flags&dns.SEP != 0)
+
+		signed[rrsig.Hdr.Name+" "+dns.TypeToString[rrsig.TypeCovered]] = true
+	}
+
+	return signed
+}
+
+func TestSignZone_NSEC(t *testing.T) {
+	zone, records := testSignedZone(t, false)
+	signed := verifySignatures(t, zone, records)
+
+	// Glue and delegation NS records aren't signed.
+	assert.True(t, signed["a.example.com. A"])
+	assert.True(t, signed["example.com. DNSKEY"])
+	assert.False(t, signed["sub.example.com. NS"])
+	assert.False(t, signed["ns.sub.example.com. A"])
+
+	chain := map[string]*dns.NSEC{}
+	for _, rr := range records {
+		nsec, ok := rr.(*dns.NSEC)
+		if ok {
+			chain[nsec.Hdr.Name] = nsec
+			assert.True(t, signed[nsec.Hdr.Name+" NSEC"])
+			assert.EqualValues(t, 300, nsec.Hdr.Ttl)
+		}
+	}
+
+	// The chain covers the authoritative names in canonical order and loops back to the apex.
+	// Empty non-terminals don't get NSEC records and glue isn't part of the chain.
+	expected := []string{"example.com.", "a.example.com.", "host.ent.example.com.", "ns1.example.com.", "sub.example.com."}
+	require.Len(t, chain, len(expected))
+
+	for i, name := range expected {
+		nsec := chain[name]
+		require.NotNil(t, nsec, name)
+		assert.Equal(t, expected[(i+1)%len(expected)], nsec.NextDomain)
+	}
+
+	assert.Equal(t, []uint16{dns.TypeNS, dns.TypeSOA, dns.TypeRRSIG, dns.TypeNSEC, dns.TypeDNSKEY}, chain["example.com."].TypeBitMap)
+	assert.Equal(t, []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeRRSIG, dns.TypeNSEC}, chain["a.example.com."].TypeBitMap)
+	assert.Equal(t, []uint16{dns.TypeNS, dns.TypeRRSIG, dns.TypeNSEC}, chain["sub.example.com."].TypeBitMap)
+}
+
+func TestSignZone_NSEC3(t *testing.T) {
+	zone, records := testSignedZone(t, true)
+	signed := verifySignatures(t, zone, records)
+
+	assert.True(t, signed["example.com. NSEC3PARAM"])
+
+	chain := map[string]*dns.NSEC3{}
+	hashes := []string{}
+	for _, rr := range records {
+		nsec3, ok := rr.(*dns.NSEC3)
+		if !ok {
+			continue
+		}
+
+		label, _, _ := strings.Cut(nsec3.Hdr.Name, ".")
+		chain[label] = nsec3
+		hashes = append(hashes, strings.ToUpper(label))
+
+		assert.True(t, signed[nsec3.Hdr.Name+" NSEC3"])
+		assert.Zero(t, nsec3.Iterations)
+		assert.Empty(t, nsec3.Salt)
+	}
+
+	// Every authoritative name and empty non-terminal has a record, glue doesn't.
+	bitmaps := map[string][]uint16{
+		"example.com.":          {dns.TypeNS, dns.TypeSOA, dns.TypeRRSIG, dns.TypeDNSKEY, dns.TypeNSEC3PARAM},
+		"a.example.com.":        {dns.TypeA, dns.TypeAAAA, dns.TypeRRSIG},
+		"ent.example.com.":      nil,
+		"host.ent.example.com.": {dns.TypeA, dns.TypeRRSIG},
+		"ns1.example.com.":      {dns.TypeA, dns.TypeRRSIG},
+		"sub.example.com.":      {dns.TypeNS},
+	}
+
+	require.Len(t, chain, len(bitmaps))
+
+	for name, bitmap := range bitmaps {
+		nsec3 := chain[strings.ToLower(dns.HashName(name, dns.SHA1, 0, ""))]
+		require.NotNil(t, nsec3, name)
+		assert.Equal(t, bitmap, nsec3.TypeBitMap, name)
+	}
+
+	// The hashes are linked in order and the last one loops back to the first.
+	slices.Sort(hashes)
+	for i, hash := range hashes {
+		assert.Equal(t, hashes[(i+1)%len(hashes)], chain[strings.ToLower(hash)].NextDomain)
+	}
+}
+
+func TestNewZoneSigner(t *testing.T) {
+	newKey := func(ksk bool, active bool) ZoneKey {
+		key, err := GenerateZoneKey("example.com", ksk)
+		require.NoError(t, err)
+
+		key.Active = active
+
+		return *key
+	}
+
+	ksk := newKey(true, true)
+	zsk := newKey(false, true)
+	published := newKey(false, false)
+
+	tests := []struct {
+		name    string
+		keys    []ZoneKey
+		zsks    int
+		ksks    int
+		dnskeys int
+		err     bool
+	}{
+		{name: "KSK and ZSK", keys: []ZoneKey{ksk, zsk}, zsks: 1, ksks: 1, dnskeys: 2},
+		{name: "Published ZSK", keys: []ZoneKey{ksk, zsk, published}, zsks: 1, ksks: 1, dnskeys: 3},
+		{name: "ZSK only", keys: []ZoneKey{zsk}, zsks: 1, ksks: 1, dnskeys: 1},
+		{name: "No active ZSK", keys: []ZoneKey{ksk, published}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := &Zone{Info: api.NetworkZone{Name: "example.com"}, Keys: tt.keys}
+
+			signer, err := newZoneSigner(zone, time.Now())
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, signer.zsks, tt.zsks)
+			assert.Len(t, signer.ksks, tt.ksks)
+			assert.Len(t, signer.dnskeys, tt.dnskeys)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package dns
 import (
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,6 +13,9 @@ import (
 	"github.com/lxc/incus/v7/shared/logger"
 )
 
+// queryTypes are the record types answered out of the zone content.
+var queryTypes = []uint16{dns.TypeA, dns.TypeAAAA, dns.TypePTR, dns.TypeCNAME, dns.TypeTXT, dns.TypeSRV, dns.TypeNS, dns.TypeDNSKEY}
+
 type dnsHandler struct {
 	server *Server
 }
@@ -47,7 +51,8 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	}
 
 	// Check that it's a supported request type.
-	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
+	isQuery := slices.Contains(queryTypes, r.Question[0].Qtype)
+	if !isQuery && r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
 		m := &dns.Msg{}
 		m.SetRcode(r, dns.RcodeNotImplemented)
 		err := w.WriteMsg(m)
@@ -72,6 +77,12 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
+	// Answer regular queries out of the zone content.
+	if isQuery {
+		d.serveQuery(w, r, ip)
+		return
+	}
+
 	// Prepare the response.
 	m := &dns.Msg{}
 	m.SetReply(r)
@@ -104,28 +115,100 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	zoneRR := dns.NewZoneParser(strings.NewReader(zone.Content), "", "")
-	for {
-		rr, ok := zoneRR.Next()
-		if !ok {
-			err := zoneRR.Err()
-			if err != nil {
-				logger.Errorf("Bad DNS record in zone %q: %v", name, err)
+	records, err := parseZone(zone)
+	if err != nil {
+		logger.Errorf("Bad DNS record in zone %q: %v", name, err)
 
-				m := &dns.Msg{}
-				m.SetRcode(r, dns.RcodeFormatError)
-				err := w.WriteMsg(m)
-				if err != nil {
-					logger.Error("Unable to write message", logger.Ctx{"err": err})
-				}
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeFormatError)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	// Sign the zone transfers of signed zones, as well as SOA queries asking for DNSSEC records.
+	if len(zone.Keys) > 0 && len(records) > 0 && (r.Question[0].Qtype != dns.TypeSOA || wantsDNSSEC(r)) {
+		records, err = signZoneRecords(zone, records, r.Question[0].Qtype == dns.TypeSOA)
+		if err != nil {
+			logger.Error("Failed signing DNS zone", logger.Ctx{"name": name, "err": err})
 
-				return
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeServerFailure)
+			err := w.WriteMsg(m)
+			if err != nil {
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
 			}
 
-			break
+			return
+		}
+	}
+
+	m.Answer = records
+
+	// Zone transfers end with the SOA record.
+	if r.Question[0].Qtype != dns.TypeSOA && len(records) > 0 {
+		m.Answer = append(m.Answer, records[0])
+	}
+
+	tsig := r.IsTsig()
+	if tsig != nil && w.TsigStatus() == nil {
+		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+
+	err = w.WriteMsg(m)
+	if err != nil {
+		logger.Error("Unable to write message", logger.Ctx{"err": err})
+	}
+}
+
+// serveQuery answers a query for records of a name out of the content of the zone it belongs to.
+func (d dnsHandler) serveQuery(w dns.ResponseWriter, r *dns.Msg, ip string) {
+	name := dns.CanonicalName(r.Question[0].Name)
+
+	// Find the zone.
+	zone, err := d.server.findZone(name)
+	if err != nil {
+		logger.Error("Failed loading DNS zone", logger.Ctx{"name": name, "err": err})
+
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeServerFailure)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	// Check access.
+	if zone == nil || !isAllowed(zone.zone.Info, ip, r.IsTsig(), w.TsigStatus() == nil) {
+		// On unknown zone or auth failure, return NXDOMAIN to avoid information leaks.
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeNameError)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
 		}
 
-		m.Answer = append(m.Answer, rr)
+		return
+	}
+
+	// Prepare the response.
+	m := &dns.Msg{}
+	m.SetReply(r)
+	m.Authoritative = true
+
+	answer := zone.answer(name, r.Question[0].Qtype, wantsDNSSEC(r))
+	m.Answer = answer.answer
+	m.Ns = answer.ns
+	m.Rcode = answer.rcode
+
+	opt := r.IsEdns0()
+	if opt != nil {
+		m.SetEdns0(opt.UDPSize(), opt.Do())
 	}
 
 	tsig := r.IsTsig()
@@ -133,12 +216,28 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
 	}
 
+	// Signed answers may not fit in a UDP response, the client then retries over TCP.
+	if w.LocalAddr().Network() == "udp" {
+		size := dns.MinMsgSize
+		if opt != nil {
+			size = int(opt.UDPSize())
+		}
+
+		m.Truncate(size)
+	}
+
 	err = w.WriteMsg(m)
 	if err != nil {
 		logger.Error("Unable to write message", logger.Ctx{"err": err})
 	}
 }
 
+// wantsDNSSEC returns whether the DNSSEC records were requested.
+func wantsDNSSEC(r *dns.Msg) bool {
+	opt := r.IsEdns0()
+	return opt != nil && opt.Do()
+}
+
 func isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG, tsigStatus bool) bool {
 	type peer struct {
 		address string
@@ -0,0 +1,293 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v7/internal/ports"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// pushTimeout is how long to wait for a server to answer a zone transfer or a dynamic update.
+const pushTimeout = 10 * time.Second
+
+// pushBatchSize is the maximum number of changes sent in a single dynamic update.
+const pushBatchSize = 100
+
+// pushTarget is a primary DNS server the records of a zone are pushed to.
+type pushTarget struct {
+	name    string
+	address string
+	key     string
+}
+
+// pushKey identifies the records pushed to a server for a zone.
+type pushKey struct {
+	zone   string
+	target string
+}
+
+// pushedZone is the state of the server as of the last successful push.
+type pushedZone struct {
+	target  pushTarget
+	records map[string]dns.RR
+}
+
+// pushTargets returns the servers configured through the push.NAME.* options of the zone.
+func pushTargets(info api.NetworkZone) []pushTarget {
+	targets := []pushTarget{}
+
+	for key, value := range info.Config {
+		fields := strings.Split(key, ".")
+		if len(fields) != 3 || fields[0] != "push" || fields[2] != "address" || value == "" {
+			continue
+		}
+
+		targets = append(targets, pushTarget{
+			name:    fields[1],
+			address: internalUtil.CanonicalNetworkAddress(value, ports.DNSDefaultPort),
+			key:     info.Config[fmt.Sprintf("push.%s.key", fields[1])],
+		})
+	}
+
+	slices.SortFunc(targets, func(a pushTarget, b pushTarget) int { return strings.Compare(a.name, b.name) })
+
+	return targets
+}
+
+// keyName returns the name of the TSIG key used with the server, following the same format as for peers.
+func (t pushTarget) keyName(zoneName string) string {
+	return fmt.Sprintf("%s_%s.", zoneName, t.name)
+}
+
+// sign adds a TSIG signature to the message if the server has a key.
+func (t pushTarget) sign(zoneName string, m *dns.Msg) map[string]string {
+	if t.key == "" {
+		return nil
+	}
+
+	m.SetTsig(t.keyName(zoneName), dns.HmacSHA256, 300, time.Now().Unix())
+
+	return map[string]string{t.keyName(zoneName): t.key}
+}
+
+// transfer retrieves the records the server currently has for the zone.
+func (t pushTarget) transfer(zoneName string) (map[string]dns.RR, error) {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(zoneName))
+
+	tr := &dns.Transfer{DialTimeout: pushTimeout, ReadTimeout: pushTimeout}
+	tr.TsigSecret = t.sign(zoneName, m)
+
+	env, err := tr.In(m, t.address)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []dns.RR{}
+	for e := range env {
+		if e.Error != nil {
+			return nil, e.Error
+		}
+
+		records = append(records, e.RR...)
+	}
+
+	return pushableRecords(zoneName, records), nil
+}
+
+// update sends the changes to the server, split across as many dynamic updates as needed.
+func (t pushTarget) update(zoneName string, changes []dns.RR) error {
+	client := &dns.Client{Net: "tcp", Timeout: pushTimeout}
+
+	for len(changes) > 0 {
+		n := min(len(changes), pushBatchSize)
+
+		m := new(dns.Msg)
+		m.SetUpdate(dns.Fqdn(zoneName))
+		m.Ns = changes[:n]
+		client.TsigSecret = t.sign(zoneName, m)
+
+		resp, _, err := client.Exchange(m, t.address)
+		if err != nil {
+			return err
+		}
+
+		if resp.Rcode != dns.RcodeSuccess {
+			return fmt.Errorf("Server refused the update: %s", dns.RcodeToString[resp.Rcode])
+		}
+
+		changes = changes[n:]
+	}
+
+	return nil
+}
+
+// pushableRecords returns the records of the zone which are pushed to servers, indexed by their text form.
+// The SOA and apex NS records belong to the server the records are pushed to, and so do DNSSEC records as
+// the server signs the zone itself if needed.
+func pushableRecords(zoneName string, records []dns.RR) map[string]dns.RR {
+	apex := dns.CanonicalName(zoneName)
+	pushable := map[string]dns.RR{}
+
+	for _, rr := range records {
+		hdr := rr.Header()
+		hdr.Name = dns.CanonicalName(hdr.Name)
+
+		switch hdr.Rrtype {
+		case dns.TypeSOA, dns.TypeRRSIG, dns.TypeNSEC, dns.TypeNSEC3, dns.TypeNSEC3PARAM, dns.TypeDNSKEY:
+			continue
+		case dns.TypeNS:
+			if hdr.Name == apex {
+				continue
+			}
+		}
+
+		pushable[rr.String()] = rr
+	}
+
+	return pushable
+}
+
+// pushChanges returns the dynamic update entries turning the old records into the new ones.
+// Removals come first so that records which only had their TTL changed get added back.
+func pushChanges(zoneName string, oldRecords map[string]dns.RR, newRecords map[string]dns.RR) []dns.RR {
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zoneName))
+
+	removed := []dns.RR{}
+	for key, rr := range oldRecords {
+		_, found := newRecords[key]
+		if !found {
+			removed = append(removed, dns.Copy(rr))
+		}
+	}
+
+	added := []dns.RR{}
+	for key, rr := range newRecords {
+		_, found := oldRecords[key]
+		if !found {
+			added = append(added, dns.Copy(rr))
+		}
+	}
+
+	m.Remove(removed)
+	m.Insert(added)
+
+	return m.Ns
+}
+
+// replaceChanges returns the dynamic update entries replacing the record sets of the server with the new records,
+// for when the current records of the server are unknown.
+func replaceChanges(zoneName string, newRecords map[string]dns.RR) []dns.RR {
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zoneName))
+
+	rrsets := map[string]dns.RR{}
+	added := []dns.RR{}
+	for _, rr := range newRecords {
+		hdr := rr.Header()
+		rrsets[fmt.Sprintf("%s/%d", hdr.Name, hdr.Rrtype)] = rr
+		added = append(added, dns.Copy(rr))
+	}
+
+	removed := make([]dns.RR, 0, len(rrsets))
+	for _, rr := range rrsets {
+		removed = append(removed, rr)
+	}
+
+	m.RemoveRRset(removed)
+	m.Insert(added)
+
+	return m.Ns
+}
+
+// PushZone sends the changes to the records of the zone as dynamic updates (RFC 2136) to the servers configured
+// through its push.NAME.* options. Servers are resynchronised in full on the first push and after a failed one.
+// Only one cluster member may push the records, as the state of the servers is tracked in memory.
+func (s *Server) PushZone(name string) error {
+	// Locking.
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+
+	zone, err := s.zoneRetriever(name, true)
+	if err != nil {
+		return err
+	}
+
+	targets := pushTargets(zone.Info)
+
+	// Forget about the servers which were removed from the zone.
+	for key := range s.pushed {
+		if key.zone == name && !slices.ContainsFunc(targets, func(target pushTarget) bool { return target.name == key.target }) {
+			delete(s.pushed, key)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	records, err := parseZone(zone)
+	if err != nil {
+		return err
+	}
+
+	newRecords := pushableRecords(name, records)
+
+	if s.pushed == nil {
+		s.pushed = map[pushKey]*pushedZone{}
+	}
+
+	var errs []error
+	for _, target := range targets {
+		key := pushKey{zone: name, target: target.name}
+
+		var changes []dns.RR
+		pushed := s.pushed[key]
+		if pushed != nil && pushed.target == target {
+			changes = pushChanges(name, pushed.records, newRecords)
+		} else {
+			// Find out what the server currently has, replacing all the record sets if it doesn't allow transfers.
+			oldRecords, err := target.transfer(name)
+			if err != nil {
+				logger.Debug("Failed transferring zone from push server, replacing all records", logger.Ctx{"zone": name, "server": target.name, "err": err})
+				changes = replaceChanges(name, newRecords)
+			} else {
+				changes = pushChanges(name, oldRecords, newRecords)
+			}
+		}
+
+		err = target.update(name, changes)
+		if err != nil {
+			delete(s.pushed, key)
+			errs = append(errs, fmt.Errorf("Failed pushing zone %q to %q: %w", name, target.name, err))
+			continue
+		}
+
+		s.pushed[key] = &pushedZone{target: target, records: newRecords}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RetainZonePush forgets about the records pushed for the zones other than the given ones, so that the servers get
+// resynchronised in full if those zones get pushed again.
+func (s *Server) RetainZonePush(names []string) {
+	// Locking.
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+
+	for key := range s.pushed {
+		if !slices.Contains(names, key.zone) {
+			delete(s.pushed, key)
+		}
+	}
+}
@@ -31,6 +31,13 @@ type Server struct {
 	// Internal state (to handle reconfiguration).
 	address string
 
+	// Zones used to answer queries, by fully qualified name.
+	zoneCache map[string]*cachedZone
+
+	// Records last pushed to the servers configured on the zones.
+	pushed map[pushKey]*pushedZone
+	pushMu sync.Mutex
+
 	cmd chan serverCmdInfo
 
 	mu sync.Mutex
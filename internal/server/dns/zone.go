@@ -8,4 +8,7 @@ import (
 type Zone struct {
 	Info    api.NetworkZone
 	Content string
+
+	// DNSSEC keys of the zone, the zone is signed when set.
+	Keys []ZoneKey
 }
@@ -41,6 +41,17 @@ func DHCPValidIP(subnet *net.IPNet, ranges []iprange.Range, IP net.IP) bool {
 	return false
 }
 
+// reservedIP returns whether an IP falls inside one of the supplied reserved ranges.
+func reservedIP(reserved []iprange.Range, IP net.IP) bool {
+	for _, r := range reserved {
+		if r.ContainsIP(IP.To16()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetIP returns a net.IP representing the IP belonging to the subnet for the host number supplied.
 func GetIP(subnet *net.IPNet, host int64) net.IP {
 	// Convert IP to a big int.
@@ -94,6 +105,7 @@ type Options struct {
 	DeviceName  string
 	HostMAC     net.HardwareAddr
 	Network     Network
+	Reserved    []iprange.Range // Addresses which must not be handed out.
 }
 
 // Transaction is a locked transaction of the dnsmasq config files that allows IP allocations for a host.
@@ -222,6 +234,12 @@ func (t *Transaction) getDHCPFreeIPv4(usedIPs map[[4]byte]dnsmasq.DHCPAllocation
 				continue
 			}
 
+			// Check IP is not reserved.
+			if reservedIP(t.opts.Reserved, IP) {
+				startBig.Add(startBig, inc)
+				continue
+			}
+
 			// Check IP is not already allocated.
 			var IPKey [4]byte
 			copy(IPKey[:], IP.To4())
@@ -273,11 +291,11 @@ func (t *Transaction) getDHCPFreeIPv6(usedIPs map[[16]byte]dnsmasq.DHCPAllocatio
 			return nil, err
 		}
 
-		// Check IP is not already allocated and not the Incus IP.
+		// Check IP is not already allocated, not reserved and not the Incus IP.
 		var IPKey [16]byte
 		copy(IPKey[:], IP.To16())
 		_, inUse := usedIPs[IPKey]
-		if !inUse && !IP.Equal(ip) {
+		if !inUse && !IP.Equal(ip) && !reservedIP(t.opts.Reserved, IP) {
 			return IP, nil
 		}
 	}
@@ -310,6 +328,12 @@ func (t *Transaction) getDHCPFreeIPv6(usedIPs map[[16]byte]dnsmasq.DHCPAllocatio
 				continue
 			}
 
+			// Check IP is not reserved.
+			if reservedIP(t.opts.Reserved, IP) {
+				startBig.Add(startBig, inc)
+				continue
+			}
+
 			// Check IP is not already allocated.
 			var IPKey [16]byte
 			copy(IPKey[:], IP.To16())
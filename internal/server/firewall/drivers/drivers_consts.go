@@ -52,6 +52,37 @@ type AddressForward struct {
 	SNAT          bool
 }
 
+// LoadBalancer represents a NAT load balancer for a listen address and port.
+type LoadBalancer struct {
+	ListenAddress net.IP
+	Protocol      string
+	ListenPort    uint64
+	Targets       []LoadBalancerTarget
+}
+
+// LoadBalancerTarget represents a backend of a NAT load balancer.
+type LoadBalancerTarget struct {
+	Address net.IP
+	Port    uint64
+}
+
+// NetworkPeer represents a peering between a network and another network routed by the host.
+type NetworkPeer struct {
+	TargetNetwork string
+	LocalSubnets  []*net.IPNet
+	TargetSubnets []*net.IPNet
+}
+
+// NetworkFlow represents the accounted traffic of a flow routed through a network.
+type NetworkFlow struct {
+	Source          net.IP
+	Destination     net.IP
+	Protocol        uint8
+	DestinationPort uint16
+	Packets         uint64
+	Bytes           uint64
+}
+
 // AddressSet represent an address set.
 type AddressSet struct {
 	Name      string
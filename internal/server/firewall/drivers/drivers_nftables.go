@@ -320,8 +320,11 @@ func (d Nftables) NetworkSetup(networkName string, opts Opts) error {
 func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 	removeChains := []string{
 		"fwd", "pstrt", "in", "out", // Chains used for network operation rules.
+		"peer",                             // Chains used by network peering rules.
+		"flow",                             // Chains used by flow accounting rules.
 		"aclin", "aclout", "aclfwd", "acl", // Chains used by ACL rules.
 		"fwdprert", "fwdout", "fwdpstrt", // Chains used by Address Forward rules.
+		"lbprert", "lbout", "lbpstrt", // Chains used by Load Balancer rules.
 		"egress", // Chains added for limits.priority option
 	}
 
@@ -332,6 +335,9 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		return fmt.Errorf("Failed clearing nftables rules for network %q: %w", networkName, err)
 	}
 
+	// Remove the flow accounting sets (only referenced by the chains removed above).
+	d.networkClearFlowSets(networkName)
+
 	// Remove the network from the managed bridges set.
 	// This will fail if the set doesn't exist or the network was never added to it.
 	_, _ = subprocess.RunCommand("nft", "delete", "element", "inet", nftablesNamespace, "bridges", fmt.Sprintf("{ %q }", networkName))
@@ -1428,6 +1434,316 @@ func (d Nftables) NetworkApplyForwards(networkName string, rules []AddressForwar
 	return nil
 }
 
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Nftables) NetworkApplyLoadBalancers(networkName string, loadBalancers []LoadBalancer) error {
+	config, err := nftablesLoadBalancerRules(networkName, loadBalancers)
+	if err != nil {
+		return err
+	}
+
+	// Apply rules or remove chains if no rules generated.
+	if config != "" {
+		err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config), nil, "nft", "-f", "-")
+		if err != nil {
+			return err
+		}
+	} else {
+		err = d.removeChains([]string{"inet"}, networkName, "lbprert", "lbout", "lbpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables load balancer rules for network %q: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
+
+// nftablesLoadBalancerRules returns the nftables rules of the network load balancers, or an empty string if none
+// of them has a target. Connections are spread across the targets of a load balancer by hashing their source
+// address and port.
+func nftablesLoadBalancerRules(networkName string, loadBalancers []LoadBalancer) (string, error) {
+	var dnatRules []map[string]any
+	var snatRules []map[string]any
+
+	for lbIndex, lb := range loadBalancers {
+		// Validate the load balancer.
+		if lb.ListenAddress == nil {
+			return "", fmt.Errorf("Invalid load balancer %d, listen address is required", lbIndex)
+		}
+
+		if lb.Protocol == "" || lb.ListenPort == 0 {
+			return "", fmt.Errorf("Invalid load balancer %d, protocol and listen port are required", lbIndex)
+		}
+
+		// Load balancers without any usable target are left out.
+		if len(lb.Targets) == 0 {
+			continue
+		}
+
+		ipFamily := "ip"
+
+		if lb.ListenAddress.To4() == nil {
+			ipFamily = "ip6"
+		}
+
+		targets := make([]string, 0, len(lb.Targets))
+		for i, target := range lb.Targets {
+			if target.Address == nil {
+				return "", fmt.Errorf("Invalid load balancer %d, target %d address is required", lbIndex, i)
+			}
+
+			if (target.Address.To4() == nil) != (ipFamily == "ip6") {
+				return "", fmt.Errorf("Invalid load balancer %d, target %d address family doesn't match the listen address", lbIndex, i)
+			}
+
+			targetPort := target.Port
+			if targetPort == 0 {
+				targetPort = lb.ListenPort
+			}
+
+			targets = append(targets, fmt.Sprintf("%d : %s . %d", i, target.Address.String(), targetPort))
+
+			snatRules = append(snatRules, map[string]any{
+				"ipFamily":   ipFamily,
+				"protocol":   lb.Protocol,
+				"targetHost": target.Address.String(),
+				"targetPort": targetPort,
+			})
+		}
+
+		dnatRules = append(dnatRules, map[string]any{
+			"ipFamily":      ipFamily,
+			"protocol":      lb.Protocol,
+			"listenAddress": lb.ListenAddress.String(),
+			"listenPort":    lb.ListenPort,
+			"targetsLen":    len(targets),
+			"targets":       strings.Join(targets, ", "),
+		})
+	}
+
+	if len(dnatRules) == 0 {
+		return "", nil
+	}
+
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"chainSeparator": nftablesChainSeparator,
+		"chainPrefix":    "lb", // Differentiate from address forwards.
+		"family":         "inet",
+		"label":          networkName,
+		"dnatRules":      dnatRules,
+		"snatRules":      snatRules,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetLoadBalancer.Execute(config, tplFields)
+	if err != nil {
+		return "", fmt.Errorf("Failed running %q template: %w", nftablesNetLoadBalancer.Name(), err)
+	}
+
+	return config.String(), nil
+}
+
+// NetworkApplyPeers applies the rules allowing traffic to be routed between a network and its peer networks.
+// The rules are evaluated ahead of the forwarding policy of the network so that peered traffic is allowed even when
+// routing is otherwise disabled on the network.
+func (d Nftables) NetworkApplyPeers(networkName string, peers []NetworkPeer) error {
+	config, err := nftablesPeerRules(networkName, peers)
+	if err != nil {
+		return err
+	}
+
+	err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying nftables peer rules for network %q: %w", networkName, err)
+	}
+
+	return nil
+}
+
+// nftablesPeerRules returns the nftables rules allowing traffic between the subnets of a network and its peers.
+func nftablesPeerRules(networkName string, peers []NetworkPeer) (string, error) {
+	var rules []map[string]any
+
+	for peerIndex, peer := range peers {
+		if peer.TargetNetwork == "" {
+			return "", fmt.Errorf("Invalid peer %d, target network is required", peerIndex)
+		}
+
+		for _, localSubnet := range peer.LocalSubnets {
+			for _, targetSubnet := range peer.TargetSubnets {
+				// Only allow traffic between subnets of the same IP family.
+				if (localSubnet.IP.To4() == nil) != (targetSubnet.IP.To4() == nil) {
+					continue
+				}
+
+				ipFamily := "ip"
+				if localSubnet.IP.To4() == nil {
+					ipFamily = "ip6"
+				}
+
+				rules = append(rules, map[string]any{
+					"ipFamily":      ipFamily,
+					"targetNetwork": peer.TargetNetwork,
+					"localSubnet":   localSubnet.String(),
+					"targetSubnet":  targetSubnet.String(),
+				})
+			}
+		}
+	}
+
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"chainSeparator": nftablesChainSeparator,
+		"networkName":    networkName,
+		"family":         "inet",
+		"rules":          rules,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetPeers.Execute(config, tplFields)
+	if err != nil {
+		return "", fmt.Errorf("Failed running %q template: %w", nftablesNetPeers.Name(), err)
+	}
+
+	return config.String(), nil
+}
+
+// NetworkApplyFlowAccounting sets up or removes the accounting of the TCP and UDP flows routed through a network.
+// Flows are tracked per source, destination, protocol and destination port, and expire after the timeout (in
+// seconds) without traffic.
+func (d Nftables) NetworkApplyFlowAccounting(networkName string, enabled bool, timeout int) error {
+	if !enabled {
+		err := d.removeChains([]string{"inet"}, networkName, "flow")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables flow accounting rules for network %q: %w", networkName, err)
+		}
+
+		d.networkClearFlowSets(networkName)
+
+		return nil
+	}
+
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"chainSeparator": nftablesChainSeparator,
+		"networkName":    networkName,
+		"family":         "inet",
+		"timeout":        timeout,
+		"size":           65535,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetFlowAccounting.Execute(config, tplFields)
+	if err != nil {
+		return fmt.Errorf("Failed running %q template: %w", nftablesNetFlowAccounting.Name(), err)
+	}
+
+	err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying nftables flow accounting rules for network %q: %w", networkName, err)
+	}
+
+	return nil
+}
+
+// networkClearFlowSets removes the flow accounting sets of a network, ignoring missing ones.
+func (d Nftables) networkClearFlowSets(networkName string) {
+	for _, set := range []string{"flow4", "flow6"} {
+		_, _ = subprocess.RunCommand("nft", "delete", "set", "inet", nftablesNamespace, fmt.Sprintf("%s%s%s", set, nftablesChainSeparator, networkName))
+	}
+}
+
+// nftFlowSet represents the JSON output of a flow accounting set.
+type nftFlowSet struct {
+	Nftables []struct {
+		Set *struct {
+			Elem []struct {
+				Elem struct {
+					Val struct {
+						Concat []any `json:"concat"`
+					} `json:"val"`
+					Counter struct {
+						Packets uint64 `json:"packets"`
+						Bytes   uint64 `json:"bytes"`
+					} `json:"counter"`
+				} `json:"elem"`
+			} `json:"elem"`
+		} `json:"set,omitempty"`
+	} `json:"nftables"`
+}
+
+// NetworkFlows returns the flows currently accounted for a network.
+func (d Nftables) NetworkFlows(networkName string) ([]NetworkFlow, error) {
+	flows := []NetworkFlow{}
+
+	for _, set := range []string{"flow4", "flow6"} {
+		output, err := subprocess.RunCommand("nft", "-j", "list", "set", "inet", nftablesNamespace, fmt.Sprintf("%s%s%s", set, nftablesChainSeparator, networkName))
+		if err != nil {
+			return nil, fmt.Errorf("Failed listing nftables flow accounting set of network %q: %w", networkName, err)
+		}
+
+		var flowSet nftFlowSet
+		err = json.Unmarshal([]byte(output), &flowSet)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing nftables flow accounting set of network %q: %w", networkName, err)
+		}
+
+		for _, entry := range flowSet.Nftables {
+			if entry.Set == nil {
+				continue
+			}
+
+			for _, elem := range entry.Set.Elem {
+				values := elem.Elem.Val.Concat
+				if len(values) != 4 {
+					continue
+				}
+
+				source, _ := values[0].(string)
+				destination, _ := values[1].(string)
+
+				flow := NetworkFlow{
+					Source:      net.ParseIP(source),
+					Destination: net.ParseIP(destination),
+					Packets:     elem.Elem.Counter.Packets,
+					Bytes:       elem.Elem.Counter.Bytes,
+				}
+
+				if flow.Source == nil || flow.Destination == nil {
+					continue
+				}
+
+				// Protocols and ports may be printed by name or number depending on the nft version.
+				switch protocol := values[2].(type) {
+				case float64:
+					flow.Protocol = uint8(protocol)
+				case string:
+					switch protocol {
+					case "tcp":
+						flow.Protocol = 6
+					case "udp":
+						flow.Protocol = 17
+					}
+				}
+
+				switch port := values[3].(type) {
+				case float64:
+					flow.DestinationPort = uint16(port)
+				case string:
+					portNumber, err := net.LookupPort(map[uint8]string{6: "tcp", 17: "udp"}[flow.Protocol], port)
+					if err == nil {
+						flow.DestinationPort = uint16(portNumber)
+					}
+				}
+
+				flows = append(flows, flow)
+			}
+		}
+	}
+
+	return flows, nil
+}
+
 // NetworkApplyAddressSets creates or updates named nft sets for all address sets.
 func (d Nftables) NetworkApplyAddressSets(sets []AddressSet, nftTable string) error {
 	_, err := subprocess.RunCommand("nft", "create", "table", nftTable, nftablesNamespace)
@@ -11,9 +11,14 @@ table {{.family}} {{.namespace}} {
 `))
 
 var nftablesNetForwardingPolicy = template.Must(template.New("nftablesNetForwardingPolicy").Parse(`
+chain peer{{.chainSeparator}}{{.networkName}} {
+}
+
 chain fwd{{.chainSeparator}}{{.networkName}} {
 	type filter hook forward priority 0; policy accept;
 
+	jump peer{{.chainSeparator}}{{.networkName}}
+
 	{{ if .ip4Action }}
 	ip version 4 oifname "{{.networkName}}" {{.ip4Action}}
 	ip version 4 iifname "{{.networkName}}" {{.ip4Action}}
@@ -26,6 +31,38 @@ chain fwd{{.chainSeparator}}{{.networkName}} {
 }
 `))
 
+var nftablesNetPeers = template.Must(template.New("nftablesNetPeers").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} peer{{.chainSeparator}}{{.networkName}}
+flush chain {{.family}} {{.namespace}} peer{{.chainSeparator}}{{.networkName}}
+
+table {{.family}} {{.namespace}} {
+	chain peer{{.chainSeparator}}{{.networkName}} {
+		{{ range .rules }}
+		iifname "{{$.networkName}}" oifname "{{.targetNetwork}}" {{.ipFamily}} saddr {{.localSubnet}} {{.ipFamily}} daddr {{.targetSubnet}} accept
+		iifname "{{.targetNetwork}}" oifname "{{$.networkName}}" {{.ipFamily}} saddr {{.targetSubnet}} {{.ipFamily}} daddr {{.localSubnet}} accept
+		{{ end }}
+	}
+}
+`))
+
+var nftablesNetFlowAccounting = template.Must(template.New("nftablesNetFlowAccounting").Parse(`
+add table {{.family}} {{.namespace}}
+add set {{.family}} {{.namespace}} flow4{{.chainSeparator}}{{.networkName}} { type ipv4_addr . ipv4_addr . inet_proto . inet_service; flags dynamic,timeout; timeout {{.timeout}}s; size {{.size}}; }
+add set {{.family}} {{.namespace}} flow6{{.chainSeparator}}{{.networkName}} { type ipv6_addr . ipv6_addr . inet_proto . inet_service; flags dynamic,timeout; timeout {{.timeout}}s; size {{.size}}; }
+add chain {{.family}} {{.namespace}} flow{{.chainSeparator}}{{.networkName}} {type filter hook forward priority filter; policy accept;}
+flush chain {{.family}} {{.namespace}} flow{{.chainSeparator}}{{.networkName}}
+
+table {{.family}} {{.namespace}} {
+	chain flow{{.chainSeparator}}{{.networkName}} {
+		iifname "{{.networkName}}" meta l4proto { tcp, udp } update @flow4{{.chainSeparator}}{{.networkName}} { ip saddr . ip daddr . meta l4proto . th dport counter }
+		oifname "{{.networkName}}" meta l4proto { tcp, udp } update @flow4{{.chainSeparator}}{{.networkName}} { ip saddr . ip daddr . meta l4proto . th dport counter }
+		iifname "{{.networkName}}" meta l4proto { tcp, udp } update @flow6{{.chainSeparator}}{{.networkName}} { ip6 saddr . ip6 daddr . meta l4proto . th dport counter }
+		oifname "{{.networkName}}" meta l4proto { tcp, udp } update @flow6{{.chainSeparator}}{{.networkName}} { ip6 saddr . ip6 daddr . meta l4proto . th dport counter }
+	}
+}
+`))
+
 var nftablesNetBridgesSet = template.Must(template.New("nftablesNetBridgesSet").Parse(`
 set bridges {
 	type ifname
@@ -127,6 +164,39 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+var nftablesNetLoadBalancer = template.Must(template.New("nftablesNetLoadBalancer").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} {{.chainPrefix}}prert{{.chainSeparator}}{{.label}} {type nat hook prerouting priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} {{.chainPrefix}}out{{.chainSeparator}}{{.label}} {type nat hook output priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} {{.chainPrefix}}pstrt{{.chainSeparator}}{{.label}} {type nat hook postrouting priority 100; policy accept;}
+flush chain {{.family}} {{.namespace}} {{.chainPrefix}}prert{{.chainSeparator}}{{.label}}
+flush chain {{.family}} {{.namespace}} {{.chainPrefix}}out{{.chainSeparator}}{{.label}}
+flush chain {{.family}} {{.namespace}} {{.chainPrefix}}pstrt{{.chainSeparator}}{{.label}}
+
+table {{.family}} {{.namespace}} {
+	chain {{.chainPrefix}}prert{{.chainSeparator}}{{.label}} {
+		type nat hook prerouting priority -100; policy accept;
+		{{ range .dnatRules }}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} addr . port to jhash {{.ipFamily}} saddr . {{.protocol}} sport mod {{.targetsLen}} map { {{.targets}} }
+		{{ end }}
+	}
+
+	chain {{.chainPrefix}}out{{.chainSeparator}}{{.label}} {
+		type nat hook output priority -100; policy accept;
+		{{ range .dnatRules }}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} addr . port to jhash {{.ipFamily}} saddr . {{.protocol}} sport mod {{.targetsLen}} map { {{.targets}} }
+		{{ end }}
+	}
+
+	chain {{.chainPrefix}}pstrt{{.chainSeparator}}{{.label}} {
+		type nat hook postrouting priority 100; policy accept;
+		{{ range .snatRules }}
+		{{.ipFamily}} saddr {{.targetHost}} {{.ipFamily}} daddr {{.targetHost}} {{.protocol}} dport {{.targetPort}} masquerade
+		{{ end }}
+	}
+}
+`))
+
 var nftablesNetACLSetup = template.Must(template.New("nftablesNetACLSetup").Parse(`
 add table {{.family}} {{.namespace}}
 add chain {{.family}} {{.namespace}} acl{{.chainSeparator}}{{.networkName}}
@@ -0,0 +1,227 @@
+package drivers
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nftablesLoadBalancerRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		loadBalancers []LoadBalancer
+		prerouting    []string
+		postrouting   []string
+		err           string
+	}{
+		{
+			name: "IPv4 with target ports",
+			loadBalancers: []LoadBalancer{{
+				ListenAddress: net.ParseIP("192.0.2.1"),
+				Protocol:      "tcp",
+				ListenPort:    80,
+				Targets: []LoadBalancerTarget{
+					{Address: net.ParseIP("10.0.0.2"), Port: 8080},
+					{Address: net.ParseIP("10.0.0.3")},
+				},
+			}},
+			prerouting: []string{
+				"ip daddr 192.0.2.1 tcp dport 80 dnat ip addr . port to jhash ip saddr . tcp sport mod 2 map { 0 : 10.0.0.2 . 8080, 1 : 10.0.0.3 . 80 }",
+			},
+			postrouting: []string{
+				"ip saddr 10.0.0.2 ip daddr 10.0.0.2 tcp dport 8080 masquerade",
+				"ip saddr 10.0.0.3 ip daddr 10.0.0.3 tcp dport 80 masquerade",
+			},
+		},
+		{
+			name: "IPv6",
+			loadBalancers: []LoadBalancer{{
+				ListenAddress: net.ParseIP("2001:db8::1"),
+				Protocol:      "udp",
+				ListenPort:    53,
+				Targets:       []LoadBalancerTarget{{Address: net.ParseIP("fd00::2"), Port: 5353}},
+			}},
+			prerouting: []string{
+				"ip6 daddr 2001:db8::1 udp dport 53 dnat ip6 addr . port to jhash ip6 saddr . udp sport mod 1 map { 0 : fd00::2 . 5353 }",
+			},
+			postrouting: []string{
+				"ip6 saddr fd00::2 ip6 daddr fd00::2 udp dport 5353 masquerade",
+			},
+		},
+		{
+			name: "Load balancer without targets",
+			loadBalancers: []LoadBalancer{
+				{ListenAddress: net.ParseIP("192.0.2.1"), Protocol: "tcp", ListenPort: 80},
+				{
+					ListenAddress: net.ParseIP("192.0.2.1"),
+					Protocol:      "tcp",
+					ListenPort:    443,
+					Targets:       []LoadBalancerTarget{{Address: net.ParseIP("10.0.0.2")}},
+				},
+			},
+			prerouting: []string{
+				"ip daddr 192.0.2.1 tcp dport 443 dnat ip addr . port to jhash ip saddr . tcp sport mod 1 map { 0 : 10.0.0.2 . 443 }",
+			},
+			postrouting: []string{
+				"ip saddr 10.0.0.2 ip daddr 10.0.0.2 tcp dport 443 masquerade",
+			},
+		},
+		{
+			name: "No targets at all",
+			loadBalancers: []LoadBalancer{
+				{ListenAddress: net.ParseIP("192.0.2.1"), Protocol: "tcp", ListenPort: 80},
+			},
+		},
+		{
+			name: "Missing listen address",
+			loadBalancers: []LoadBalancer{
+				{Protocol: "tcp", ListenPort: 80},
+			},
+			err: "listen address is required",
+		},
+		{
+			name: "Missing listen port",
+			loadBalancers: []LoadBalancer{
+				{ListenAddress: net.ParseIP("192.0.2.1"), Protocol: "tcp"},
+			},
+			err: "protocol and listen port are required",
+		},
+		{
+			name: "Mixed address families",
+			loadBalancers: []LoadBalancer{{
+				ListenAddress: net.ParseIP("192.0.2.1"),
+				Protocol:      "tcp",
+				ListenPort:    80,
+				Targets:       []LoadBalancerTarget{{Address: net.ParseIP("fd00::2")}},
+			}},
+			err: "address family doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := nftablesLoadBalancerRules("br0", tt.loadBalancers)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if len(tt.prerouting) == 0 {
+				assert.Empty(t, config)
+				return
+			}
+
+			// Gather the rules of each chain.
+			chains := map[string][]string{}
+			chain := ""
+			for _, line := range strings.Split(config, "\n") {
+				line = strings.TrimSpace(line)
+
+				switch {
+				case strings.HasPrefix(line, "chain "):
+					chain = strings.Fields(line)[1]
+				case line == "}":
+					chain = ""
+				case chain != "" && line != "" && !strings.HasPrefix(line, "type "):
+					chains[chain] = append(chains[chain], line)
+				}
+			}
+
+			assert.Equal(t, tt.prerouting, chains["lbprert.br0"])
+			assert.Equal(t, tt.prerouting, chains["lbout.br0"])
+			assert.Equal(t, tt.postrouting, chains["lbpstrt.br0"])
+			assert.Contains(t, config, "flush chain inet incus lbprert.br0")
+		})
+	}
+}
+
+func Test_nftablesPeerRules(t *testing.T) {
+	subnet := func(cidr string) *net.IPNet {
+		_, subnet, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+
+		return subnet
+	}
+
+	tests := []struct {
+		name  string
+		peers []NetworkPeer
+		rules []string
+		err   string
+	}{
+		{
+			name: "Dual stack",
+			peers: []NetworkPeer{{
+				TargetNetwork: "br1",
+				LocalSubnets:  []*net.IPNet{subnet("10.0.0.0/24"), subnet("fd00:0::/64")},
+				TargetSubnets: []*net.IPNet{subnet("10.0.1.0/24"), subnet("fd00:1::/64")},
+			}},
+			rules: []string{
+				`iifname "br0" oifname "br1" ip saddr 10.0.0.0/24 ip daddr 10.0.1.0/24 accept`,
+				`iifname "br1" oifname "br0" ip saddr 10.0.1.0/24 ip daddr 10.0.0.0/24 accept`,
+				`iifname "br0" oifname "br1" ip6 saddr fd00::/64 ip6 daddr fd00:1::/64 accept`,
+				`iifname "br1" oifname "br0" ip6 saddr fd00:1::/64 ip6 daddr fd00::/64 accept`,
+			},
+		},
+		{
+			name: "Mismatched families",
+			peers: []NetworkPeer{{
+				TargetNetwork: "br1",
+				LocalSubnets:  []*net.IPNet{subnet("10.0.0.0/24")},
+				TargetSubnets: []*net.IPNet{subnet("fd00:1::/64")},
+			}},
+		},
+		{
+			name: "Multiple peers",
+			peers: []NetworkPeer{
+				{TargetNetwork: "br1", LocalSubnets: []*net.IPNet{subnet("10.0.0.0/24")}, TargetSubnets: []*net.IPNet{subnet("10.0.1.0/24")}},
+				{TargetNetwork: "br2", LocalSubnets: []*net.IPNet{subnet("10.0.0.0/24")}, TargetSubnets: []*net.IPNet{subnet("10.0.2.0/24")}},
+			},
+			rules: []string{
+				`iifname "br0" oifname "br1" ip saddr 10.0.0.0/24 ip daddr 10.0.1.0/24 accept`,
+				`iifname "br1" oifname "br0" ip saddr 10.0.1.0/24 ip daddr 10.0.0.0/24 accept`,
+				`iifname "br0" oifname "br2" ip saddr 10.0.0.0/24 ip daddr 10.0.2.0/24 accept`,
+				`iifname "br2" oifname "br0" ip saddr 10.0.2.0/24 ip daddr 10.0.0.0/24 accept`,
+			},
+		},
+		{
+			name:  "No peers",
+			peers: nil,
+		},
+		{
+			name:  "Missing target network",
+			peers: []NetworkPeer{{LocalSubnets: []*net.IPNet{subnet("10.0.0.0/24")}}},
+			err:   "target network is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := nftablesPeerRules("br0", tt.peers)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			// The chain is always flushed so that removed peers don't leave rules behind.
+			assert.Contains(t, config, "flush chain inet incus peer.br0")
+
+			var rules []string
+			for _, line := range strings.Split(config, "\n") {
+				line = strings.TrimSpace(line)
+				if strings.HasPrefix(line, "iifname ") {
+					rules = append(rules, line)
+				}
+			}
+
+			assert.Equal(t, tt.rules, rules)
+		})
+	}
+}
@@ -25,6 +25,10 @@ type Firewall interface {
 	NetworkClear(networkName string, removeChains bool, ipVersions []uint) error
 	NetworkApplyACLRules(networkName string, rules []drivers.ACLRule) error
 	NetworkApplyForwards(networkName string, rules []drivers.AddressForward) error
+	NetworkApplyLoadBalancers(networkName string, loadBalancers []drivers.LoadBalancer) error
+	NetworkApplyPeers(networkName string, peers []drivers.NetworkPeer) error
+	NetworkApplyFlowAccounting(networkName string, enabled bool, timeout int) error
+	NetworkFlows(networkName string) ([]drivers.NetworkFlow, error)
 	NetworkApplyAddressSets(sets []drivers.AddressSet, nftTable string) error
 	NetworkDeleteAddressSetsIfUnused(nftTable string) error
 
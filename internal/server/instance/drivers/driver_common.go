@@ -28,12 +28,14 @@ import (
 	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
 	"github.com/lxc/incus/v7/internal/server/device/nictype"
 	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/healthcheck"
 	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v7/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v7/internal/server/lifecycle"
 	"github.com/lxc/incus/v7/internal/server/locking"
 	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/replication"
 	"github.com/lxc/incus/v7/internal/server/selinux"
 	"github.com/lxc/incus/v7/internal/server/state"
 	storagePools "github.com/lxc/incus/v7/internal/server/storage"
@@ -211,6 +213,20 @@ func (d *common) shouldAutoRestart() bool {
 	return false
 }
 
+// healthState returns the health of the running instance or nil if it has no health check configured.
+func (d *common) healthState() *api.InstanceStateHealth {
+	if d.expandedConfig["healthcheck.type"] == "" {
+		return nil
+	}
+
+	return healthcheck.Get(d.project.Name, d.name)
+}
+
+// replicationState returns the replication state of the instance or nil if it's neither replicated nor a replica.
+func (d *common) replicationState() *api.ReplicationState {
+	return replication.State(d.expandedConfig, time.Now())
+}
+
 // ID gets instances's ID.
 func (d *common) ID() int {
 	return d.id
@@ -970,6 +986,11 @@ func (d *common) validateStartup(stateful bool, statusCode api.StatusCode) error
 		return errors.New("Instance has startup protection enabled")
 	}
 
+	// Check if instance is a replica.
+	if d.localConfig[replication.KeySource] != "" {
+		return fmt.Errorf("Instance is a replica of %q and must be promoted before it can be started", d.localConfig[replication.KeySource])
+	}
+
 	// Must happen before creating operation Start lock to avoid the status check returning Stopped due to the
 	// existence of a Start operation lock.
 	err = d.isStartableStatusCode(statusCode)
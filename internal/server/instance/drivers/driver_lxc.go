@@ -273,6 +273,14 @@ func lxcCreate(s *state.State, args db.InstanceArgs, p api.Project, partialDevic
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		// Copied, migrated and imported instances may depend on instances which don't exist here.
+		if !partialDeviceValidation {
+			err = instance.ValidDependencies(s, d.project.Name, d.name, d.expandedConfig)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid dependencies: %w", err)
+			}
+		}
 	}
 
 	_, rootDiskDevice, err := d.getRootDiskDevice()
@@ -4156,9 +4164,12 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 		if err != nil {
 			return nil, err
 		}
+
+		status.Health = d.healthState()
 	}
 
 	status.Disk = d.diskState()
+	status.Replication = d.replicationState()
 
 	d.release()
 
@@ -5159,6 +5170,14 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded devices: %w", err)
 		}
 
+		// Only check the dependencies when they change, so existing instances aren't blocked by missing ones.
+		if oldExpandedConfig["boot.depends_on"] != d.expandedConfig["boot.depends_on"] {
+			err = instance.ValidDependencies(d.state, d.project.Name, d.name, d.expandedConfig)
+			if err != nil {
+				return fmt.Errorf("Invalid dependencies: %w", err)
+			}
+		}
+
 		// Validate root device
 		_, oldRootDev, oldErr := internalInstance.GetRootDiskDevice(oldExpandedDevices.CloneNative())
 		_, newRootDev, newErr := internalInstance.GetRootDiskDevice(d.expandedDevices.CloneNative())
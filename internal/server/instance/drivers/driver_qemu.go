@@ -274,6 +274,14 @@ func qemuCreate(s *state.State, args db.InstanceArgs, p api.Project, partialDevi
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		// Copied, migrated and imported instances may depend on instances which don't exist here.
+		if !partialDeviceValidation {
+			err = instance.ValidDependencies(s, d.project.Name, d.name, d.expandedConfig)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid dependencies: %w", err)
+			}
+		}
 	}
 
 	// Retrieve the instance's storage pool.
@@ -7018,6 +7026,14 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded devices: %w", err)
 		}
 
+		// Only check the dependencies when they change, so existing instances aren't blocked by missing ones.
+		if oldExpandedConfig["boot.depends_on"] != d.expandedConfig["boot.depends_on"] {
+			err = instance.ValidDependencies(d.state, d.project.Name, d.name, d.expandedConfig)
+			if err != nil {
+				return fmt.Errorf("Invalid dependencies: %w", err)
+			}
+		}
+
 		// Validate root device
 		_, oldRootDev, oldErr := internalInstance.GetRootDiskDevice(oldExpandedDevices.CloneNative())
 		_, newRootDev, newErr := internalInstance.GetRootDiskDevice(d.expandedDevices.CloneNative())
@@ -8007,8 +8023,15 @@ func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties m
 		return nil, fmt.Errorf("Failed checking disk format: %w", err)
 	}
 
+	// The image is flattened, which needs to read the whole backing chain.
+	var backingPaths []string
 	if isQcow2 {
 		srcFormat = storageDrivers.BlockVolumeTypeQcow2
+
+		backingPaths, err = storageDrivers.Qcow2BackingChain(mountInfo.DiskPath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting disk backing chain: %w", err)
+		}
 	}
 
 	// Convert to qcow2 image.
@@ -8042,7 +8065,7 @@ func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties m
 
 	cmd = append(cmd, mountInfo.DiskPath, fPath)
 
-	_, err = apparmor.QemuImg(d.state.OS, cmd, mountInfo.DiskPath, fPath, tracker)
+	_, err = apparmor.QemuImg(d.state.OS, cmd, mountInfo.DiskPath, fPath, tracker, backingPaths...)
 	if err != nil {
 		return nil, fmt.Errorf("Failed converting instance to qcow2: %w", err)
 	}
@@ -10026,6 +10049,7 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 		}
 
 		status.Disk = diskState
+		status.Replication = d.replicationState()
 
 		return status, nil
 	}
@@ -10131,6 +10155,10 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 		return status, err
 	}
 
+	// Populate the health and replication information.
+	status.Health = d.healthState()
+	status.Replication = d.replicationState()
+
 	return status, nil
 }
 
@@ -11648,13 +11676,18 @@ func (d *qemu) CreateQcow2Snapshot(devPath string, devName string, snapshotName
 
 	reverter.Add(func() { _ = monitor.RemoveFDFromFDSet(nextOverlayName) })
 
+	fileDriver, err := qcow2FileDriver(f)
+	if err != nil {
+		return err
+	}
+
 	blockDev := map[string]any{
 		"driver":    "qcow2",
 		"discard":   "unmap", // Forward as an unmap request. This is the same as `discard=on` in the qemu config file.
 		"node-name": nextOverlayName,
 		"read-only": false,
 		"file": map[string]any{
-			"driver":   "host_device",
+			"driver":   fileDriver,
 			"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
 		},
 	}
@@ -11878,13 +11911,18 @@ func (d *qemu) qcow2BlockDev(m *qmp.Monitor, nodeName string, aioMode string, di
 		return "", fmt.Errorf("Failed sending file descriptor of %q for disk device %q: %w", f.Name(), devName, err)
 	}
 
+	fileDriver, err := qcow2FileDriver(f)
+	if err != nil {
+		return "", err
+	}
+
 	blockDev := map[string]any{
 		"driver":    "qcow2",
 		"discard":   "unmap", // Forward as an unmap request. This is the same as `discard=on` in the qemu config file.
 		"node-name": backingNodeName,
 		"read-only": false,
 		"file": map[string]any{
-			"driver":   "host_device",
+			"driver":   fileDriver,
 			"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
 			"aio":      aioMode,
 			"cache": map[string]any{
@@ -11912,6 +11950,21 @@ func (d *qemu) qcow2BlockDev(m *qmp.Monitor, nodeName string, aioMode string, di
 	return backingNodeName, nil
 }
 
+// qcow2FileDriver returns the QEMU driver of the file holding a qcow2 image, which is either a block device
+// or a regular file depending on the storage driver.
+func qcow2FileDriver(f *os.File) (string, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("Failed getting information on %q: %w", f.Name(), err)
+	}
+
+	if linux.IsBlockdev(fi.Mode()) {
+		return "host_device", nil
+	}
+
+	return "file", nil
+}
+
 // currentQcow2OverlayIndex returns the current maximum overlay index.
 func currentQcow2OverlayIndex(names []string, prefix string) int {
 	re := regexp.MustCompile(fmt.Sprintf(`^%s_overlay(\d+)$`, prefix))
@@ -12032,6 +12085,7 @@ func (d *qemu) ConnectNBD(diskName string, volSize int64, writable bool) (net.Co
 
 	blockExport := blockDevs[len(blockDevs)-1]
 	exportNode := blockExport
+	bitmapNode := blockExport
 
 	if !writable {
 		// Expose a frozen view of the disk through a copy-before-write overlay
@@ -12041,7 +12095,34 @@ func (d *qemu) ConnectNBD(diskName string, volSize int64, writable bool) (net.Co
 			return nil, nil, fmt.Errorf("Failed creating temporary snapshot: %w", err)
 		}
 
-		err = monitor.BlockDevBackupTransaction([]qmp.BlockDevBackupTarget{{Device: baseNode, Target: snapNode, Sync: "none", JobID: snapNode}})
+		actions := []qmp.TransactionAction{{
+			Type: "blockdev-backup",
+			Data: map[string]any{"device": baseNode, "target": snapNode, "sync": "none", "job-id": snapNode},
+		}}
+
+		// Freeze copies of the dirty bitmaps on the overlay at the same point in time, so that the exported
+		// bitmaps match the exported data instead of recording the guest writes made in the meantime.
+		for _, b := range bitmaps {
+			if b.Inconsistent {
+				continue
+			}
+
+			bitmapAdd := map[string]any{"node": snapNode, "name": b.Name, "persistent": false, "disabled": true}
+			if b.Granularity > 0 {
+				bitmapAdd["granularity"] = b.Granularity
+			}
+
+			actions = append(actions,
+				qmp.TransactionAction{Type: "block-dirty-bitmap-add", Data: bitmapAdd},
+				qmp.TransactionAction{Type: "block-dirty-bitmap-merge", Data: map[string]any{
+					"node":    snapNode,
+					"target":  b.Name,
+					"bitmaps": []map[string]string{{"node": baseNode, "name": b.Name}},
+				}},
+			)
+		}
+
+		err = monitor.RunTransaction(actions)
 		if err != nil {
 			removeOverlay()
 			return nil, nil, fmt.Errorf("Failed creating temporary snapshot: %w", err)
@@ -12049,9 +12130,10 @@ func (d *qemu) ConnectNBD(diskName string, volSize int64, writable bool) (net.Co
 
 		overlayNode = snapNode
 		exportNode = snapNode
+		bitmapNode = snapNode
 	}
 
-	err = monitor.NBDBlockExportAdd(exportNode, "", writable, blockExport, bitmapNames)
+	err = monitor.NBDBlockExportAdd(exportNode, "", writable, bitmapNode, bitmapNames)
 	if err != nil {
 		return nil, nil, fmt.Errorf("Failed adding disk to NBD server: %w", err)
 	}
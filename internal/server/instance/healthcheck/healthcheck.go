@@ -0,0 +1,163 @@
+// Package healthcheck tracks the health of the instances running on this server.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/probe"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Health status of an instance.
+const (
+	StatusStarting  = "starting"
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Remediation tracks the health check actions run on an instance since it was last healthy.
+type Remediation struct {
+	// Number of times the action was run.
+	Attempts int
+
+	// Earliest time at which the action can run again.
+	Next time.Time
+}
+
+var (
+	health       = map[string]api.InstanceStateHealth{}
+	remediations = map[string]Remediation{}
+	healthMu     sync.Mutex
+)
+
+func key(projectName string, instanceName string) string {
+	return projectName + "/" + instanceName
+}
+
+// Get returns the last recorded health of the instance.
+// If the instance has a health check configured but hasn't been checked yet, the starting status is returned.
+func Get(projectName string, instanceName string) *api.InstanceStateHealth {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	h, ok := health[key(projectName, instanceName)]
+	if !ok {
+		return &api.InstanceStateHealth{Status: StatusStarting}
+	}
+
+	return &h
+}
+
+// Set records the health of the instance.
+func Set(projectName string, instanceName string, h api.InstanceStateHealth) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	health[key(projectName, instanceName)] = h
+}
+
+// GetRemediation returns the health check actions run on the instance since it was last healthy.
+func GetRemediation(projectName string, instanceName string) Remediation {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	return remediations[key(projectName, instanceName)]
+}
+
+// SetRemediation records the health check actions run on the instance.
+func SetRemediation(projectName string, instanceName string, r Remediation) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	remediations[key(projectName, instanceName)] = r
+}
+
+// ClearRemediation forgets the health check actions run on the instance.
+func ClearRemediation(projectName string, instanceName string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	delete(remediations, key(projectName, instanceName))
+}
+
+// Prune forgets the recorded health and actions of all instances for which keep returns false.
+func Prune(keep func(projectName string, instanceName string) bool) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	for k := range health {
+		projectName, instanceName, _ := strings.Cut(k, "/")
+		if !keep(projectName, instanceName) {
+			delete(health, k)
+		}
+	}
+
+	for k := range remediations {
+		projectName, instanceName, _ := strings.Cut(k, "/")
+		if !keep(projectName, instanceName) {
+			delete(remediations, k)
+		}
+	}
+}
+
+// Check runs the health check configured on the instance once.
+func Check(ctx context.Context, inst instance.Instance) error {
+	config := inst.ExpandedConfig()
+
+	timeout := 5
+	if config["healthcheck.timeout"] != "" {
+		timeout, _ = strconv.Atoi(config["healthcheck.timeout"])
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	switch config["healthcheck.type"] {
+	case "exec":
+		return probe.Exec(ctx, inst, config["healthcheck.command"])
+
+	case "tcp":
+		var instState *api.InstanceState
+		if strings.HasPrefix(config["healthcheck.address"], ":") {
+			var err error
+
+			instState, err = inst.RenderState(nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		address, err := probe.Address(config["healthcheck.address"], instState)
+		if err != nil {
+			return err
+		}
+
+		return probe.TCP(ctx, address)
+
+	case "http":
+		var instState *api.InstanceState
+		u, err := url.Parse(config["healthcheck.url"])
+		if err == nil && u.Hostname() == "" {
+			instState, err = inst.RenderState(nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		requestURL, err := probe.URL(config["healthcheck.url"], instState)
+		if err != nil {
+			return err
+		}
+
+		return probe.HTTP(ctx, requestURL)
+	}
+
+	return fmt.Errorf("Unsupported health check type %q", config["healthcheck.type"])
+}
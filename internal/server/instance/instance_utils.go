@@ -148,9 +148,114 @@ func ValidConfig(sysOS *sys.OS, config map[string]string, expanded bool, instanc
 		return errors.New("nvidia.runtime is incompatible with privileged containers")
 	}
 
+	if expanded {
+		switch config["boot.ready.probe"] {
+		case "tcp":
+			if config["boot.ready.address"] == "" {
+				return errors.New("boot.ready.address is required when boot.ready.probe is set to tcp")
+			}
+
+		case "exec":
+			if config["boot.ready.command"] == "" {
+				return errors.New("boot.ready.command is required when boot.ready.probe is set to exec")
+			}
+		}
+
+		switch config["healthcheck.type"] {
+		case "exec":
+			if config["healthcheck.command"] == "" {
+				return errors.New("healthcheck.command is required when healthcheck.type is set to exec")
+			}
+
+		case "tcp":
+			if config["healthcheck.address"] == "" {
+				return errors.New("healthcheck.address is required when healthcheck.type is set to tcp")
+			}
+
+		case "http":
+			if config["healthcheck.url"] == "" {
+				return errors.New("healthcheck.url is required when healthcheck.type is set to http")
+			}
+		}
+
+		if config["backups.target.url"] != "" && config["backups.target.bucket"] == "" {
+			return errors.New("backups.target.bucket is required when backups.target.url is set")
+		}
+
+		if config["replication.target"] != "" && !strings.HasPrefix(config["replication.target"], "https://") {
+			return errors.New("replication.target must be the HTTPS URL of a remote server")
+		}
+
+		if config["replication.schedule"] != "" && config["replication.target"] == "" {
+			return errors.New("replication.target is required when replication.schedule is set")
+		}
+	}
+
 	return nil
 }
 
+// dependencyGraph returns the dependencies of the instances of the project, keyed by instance name.
+func dependencyGraph(s *state.State, projectName string) (map[string][]string, error) {
+	graph := map[string][]string{}
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, _ api.Project) error {
+			graph[dbInst.Name] = instance.DependsOn(db.ExpandInstanceConfig(dbInst.Config, dbInst.Profiles))
+
+			return nil
+		}, cluster.InstanceFilter{Project: &projectName})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance dependencies: %w", err)
+	}
+
+	return graph, nil
+}
+
+// ValidDependencies checks that the instance dependencies in the given expanded config are existing instances
+// of the project and don't introduce a dependency cycle going through the instance.
+func ValidDependencies(s *state.State, projectName string, instanceName string, config map[string]string) error {
+	deps := instance.DependsOn(config)
+	if len(deps) == 0 {
+		return nil
+	}
+
+	graph, err := dependencyGraph(s, projectName)
+	if err != nil {
+		return err
+	}
+
+	graph[instanceName] = deps
+
+	for _, dep := range deps {
+		_, ok := graph[dep]
+		if !ok {
+			return fmt.Errorf("Instance %q listed in boot.depends_on doesn't exist in project %q", dep, projectName)
+		}
+	}
+
+	return instance.DependencyCycle(graph, instanceName)
+}
+
+// DependentInstances returns the sorted names of the instances of the project listing the named instance in
+// their boot.depends_on.
+func DependentInstances(s *state.State, projectName string, instanceName string) ([]string, error) {
+	graph, err := dependencyGraph(s, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := []string{}
+	for name, deps := range graph {
+		if name != instanceName && slices.Contains(deps, instanceName) {
+			dependents = append(dependents, name)
+		}
+	}
+
+	slices.Sort(dependents)
+
+	return dependents, nil
+}
+
 func validConfigKey(sysOS *sys.OS, key string, value string, instanceType instancetype.Type) error {
 	f, err := instance.ConfigKeyChecker(key, instanceType.ToAPI())
 	if err != nil {
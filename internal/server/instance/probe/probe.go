@@ -0,0 +1,236 @@
+// Package probe implements checks used to determine whether a service running inside an instance is available.
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+
+	"github.com/kballard/go-shellquote"
+	"golang.org/x/sys/unix"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// execRequest returns the exec request used to run the command inside of an instance.
+func execRequest(command string) (*api.InstanceExecPost, error) {
+	args, err := shellquote.Split(command)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing command %q: %w", command, err)
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("No command provided")
+	}
+
+	return &api.InstanceExecPost{
+		Command: args,
+		Environment: map[string]string{
+			"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+			"HOME": "/root",
+			"USER": "root",
+			"LANG": "C.UTF-8",
+		},
+	}, nil
+}
+
+// Exec runs the command inside of the local instance and returns an error if it can't be run or exits with a
+// non-zero return code. The command is killed if the context is cancelled before it completes.
+func Exec(ctx context.Context, inst instance.Instance, command string) error {
+	req, err := execRequest(command)
+	if err != nil {
+		return err
+	}
+
+	if !inst.IsRunning() {
+		return errors.New("Instance isn't running")
+	}
+
+	// Commands get /dev/null for all their standard file descriptors.
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = devNull.Close() }()
+
+	cmd, err := inst.Exec(*req, devNull, devNull, devNull)
+	if err != nil {
+		return fmt.Errorf("Failed running command %q: %w", command, err)
+	}
+
+	type result struct {
+		exitStatus int
+		err        error
+	}
+
+	resultCh := make(chan result, 1)
+	go func() {
+		exitStatus, err := cmd.Wait()
+		resultCh <- result{exitStatus: exitStatus, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-resultCh:
+	case <-ctx.Done():
+		_ = cmd.Signal(unix.SIGKILL)
+		<-resultCh
+
+		return fmt.Errorf("Command %q timed out: %w", command, ctx.Err())
+	}
+
+	if res.err != nil {
+		return fmt.Errorf("Failed running command %q: %w", command, res.err)
+	}
+
+	if res.exitStatus != 0 {
+		return fmt.Errorf("Command %q exited with status %d", command, res.exitStatus)
+	}
+
+	return nil
+}
+
+// ExecRemote runs the command inside of an instance through the provided client and returns an error if it
+// can't be run or exits with a non-zero return code.
+func ExecRemote(ctx context.Context, client incus.InstanceServer, name string, command string) error {
+	req, err := execRequest(command)
+	if err != nil {
+		return err
+	}
+
+	op, err := client.ExecInstance(name, *req, nil)
+	if err != nil {
+		return fmt.Errorf("Failed running command %q: %w", command, err)
+	}
+
+	err = op.WaitContext(ctx)
+	if err != nil {
+		_ = op.Cancel()
+		return fmt.Errorf("Failed running command %q: %w", command, err)
+	}
+
+	exitStatus, ok := op.Get().Metadata["return"].(float64)
+	if !ok {
+		return fmt.Errorf("Failed getting return code of command %q", command)
+	}
+
+	if exitStatus != 0 {
+		return fmt.Errorf("Command %q exited with status %d", command, int(exitStatus))
+	}
+
+	return nil
+}
+
+// TCP attempts to connect to the address.
+func TCP(ctx context.Context, address string) error {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to %q: %w", address, err)
+	}
+
+	_ = conn.Close()
+
+	return nil
+}
+
+// HTTP sends a GET request to the URL and returns an error unless a 2xx or 3xx response is received.
+// Redirects aren't followed and certificates of HTTPS endpoints aren't verified.
+func HTTP(ctx context.Context, requestURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("Invalid URL %q: %w", requestURL, err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed querying %q: %w", requestURL, err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("Query to %q returned status %d", requestURL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// URL returns the URL to probe, filling in the first global address of the instance when the host is omitted.
+func URL(requestURL string, state *api.InstanceState) (string, error) {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("Invalid URL %q: %w", requestURL, err)
+	}
+
+	if u.Hostname() != "" {
+		return requestURL, nil
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	u.Host, err = Address(":"+port, state)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// Address returns the address to probe given an address in the `[HOST]:PORT` format.
+// When the host is omitted, the first global address of the instance (by interface name) is used, preferring IPv4.
+func Address(address string, state *api.InstanceState) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("Invalid address %q: %w", address, err)
+	}
+
+	if host != "" {
+		return address, nil
+	}
+
+	if state != nil {
+		for _, family := range []string{"inet", "inet6"} {
+			for _, name := range slices.Sorted(maps.Keys(state.Network)) {
+				if name == "lo" {
+					continue
+				}
+
+				for _, addr := range state.Network[name].Addresses {
+					if addr.Family == family && addr.Scope == "global" {
+						return net.JoinHostPort(addr.Address, port), nil
+					}
+				}
+			}
+		}
+	}
+
+	return "", errors.New("Instance has no global address")
+}
@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func TestAddress(t *testing.T) {
+	state := &api.InstanceState{
+		Network: map[string]api.InstanceStateNetwork{
+			"lo": {
+				Addresses: []api.InstanceStateNetworkAddress{
+					{Family: "inet", Address: "127.0.0.1", Scope: "local"},
+				},
+			},
+			"eth1": {
+				Addresses: []api.InstanceStateNetworkAddress{
+					{Family: "inet6", Address: "fd42::2", Scope: "global"},
+				},
+			},
+			"eth0": {
+				Addresses: []api.InstanceStateNetworkAddress{
+					{Family: "inet6", Address: "fe80::1", Scope: "link"},
+					{Family: "inet", Address: "10.0.0.2", Scope: "global"},
+				},
+			},
+		},
+	}
+
+	address, err := Address("192.0.2.1:80", state)
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1:80", address)
+
+	address, err = Address(":80", state)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2:80", address)
+
+	delete(state.Network, "eth0")
+	address, err = Address(":80", state)
+	require.NoError(t, err)
+	assert.Equal(t, "[fd42::2]:80", address)
+
+	_, err = Address(":80", nil)
+	assert.Error(t, err)
+
+	_, err = Address("80", state)
+	assert.Error(t, err)
+}
+
+func TestURL(t *testing.T) {
+	state := &api.InstanceState{
+		Network: map[string]api.InstanceStateNetwork{
+			"eth0": {
+				Addresses: []api.InstanceStateNetworkAddress{
+					{Family: "inet", Address: "10.0.0.2", Scope: "global"},
+				},
+			},
+		},
+	}
+
+	requestURL, err := URL("http://example.com/health", state)
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/health", requestURL)
+
+	requestURL, err = URL("http:///health", state)
+	require.NoError(t, err)
+	assert.Equal(t, "http://10.0.0.2:80/health", requestURL)
+
+	requestURL, err = URL("https://:8443/health", state)
+	require.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.2:8443/health", requestURL)
+}
+
+func TestTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+	assert.NoError(t, TCP(context.Background(), address))
+
+	_ = listener.Close()
+	assert.Error(t, TCP(context.Background(), address))
+}
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/redirect":
+			http.Redirect(w, r, "/missing", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	assert.NoError(t, HTTP(context.Background(), server.URL+"/ok"))
+	assert.NoError(t, HTTP(context.Background(), server.URL+"/redirect"))
+	assert.Error(t, HTTP(context.Background(), server.URL+"/fail"))
+}
+
+func TestExecRequest(t *testing.T) {
+	req, err := execRequest("pg_isready -h 'local host'")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pg_isready", "-h", "local host"}, req.Command)
+
+	_, err = execRequest("")
+	assert.Error(t, err)
+}
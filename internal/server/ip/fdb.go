@@ -0,0 +1,146 @@
+package ip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// FDB represents arguments for forwarding database manipulation.
+type FDB struct {
+	DevName string
+	MAC     net.HardwareAddr
+	Dst     net.IP
+}
+
+func (f *FDB) netlinkNeigh() (*netlink.Neigh, error) {
+	link, err := linkByName(f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &netlink.Neigh{
+		LinkIndex:    link.Attrs().Index,
+		Family:       unix.AF_BRIDGE,
+		State:        netlink.NUD_PERMANENT | netlink.NUD_NOARP,
+		Flags:        netlink.NTF_SELF,
+		IP:           f.Dst,
+		HardwareAddr: f.MAC,
+	}, nil
+}
+
+// Append adds a forwarding database entry to the device, keeping existing entries for the same MAC address.
+func (f *FDB) Append() error {
+	neigh, err := f.netlinkNeigh()
+	if err != nil {
+		return err
+	}
+
+	err = netlink.NeighAppend(neigh)
+	if err != nil {
+		return fmt.Errorf("Failed to append FDB entry %q to %q: %w", f.MAC, f.DevName, err)
+	}
+
+	return nil
+}
+
+// Replace adds or replaces the forwarding database entry for the MAC address on the device.
+func (f *FDB) Replace() error {
+	neigh, err := f.netlinkNeigh()
+	if err != nil {
+		return err
+	}
+
+	err = netlink.NeighSet(neigh)
+	if err != nil {
+		return fmt.Errorf("Failed to set FDB entry %q on %q: %w", f.MAC, f.DevName, err)
+	}
+
+	return nil
+}
+
+// Delete removes the forwarding database entry from the device.
+func (f *FDB) Delete() error {
+	neigh, err := f.netlinkNeigh()
+	if err != nil {
+		return err
+	}
+
+	err = netlink.NeighDel(neigh)
+	if err != nil {
+		return fmt.Errorf("Failed to delete FDB entry %q from %q: %w", f.MAC, f.DevName, err)
+	}
+
+	return nil
+}
+
+// Show lists the forwarding database entries of the device itself which have a destination address
+// (such as those of VXLAN interfaces).
+func (f *FDB) Show() ([]FDB, error) {
+	link, err := linkByName(f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	netlinkNeighbors, err := netlink.NeighList(link.Attrs().Index, unix.AF_BRIDGE)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get FDB entries for link %q: %w", f.DevName, err)
+	}
+
+	entries := make([]FDB, 0, len(netlinkNeighbors))
+	for _, neighbor := range netlinkNeighbors {
+		if neighbor.Flags&netlink.NTF_SELF == 0 || neighbor.IP == nil {
+			continue
+		}
+
+		entries = append(entries, FDB{
+			DevName: f.DevName,
+			MAC:     neighbor.HardwareAddr,
+			Dst:     neighbor.IP,
+		})
+	}
+
+	return entries, nil
+}
+
+// ShowLearned lists the MAC addresses dynamically learned by the bridge, with the name of the port they were
+// learned on as the DevName.
+func (f *FDB) ShowLearned() ([]FDB, error) {
+	link, err := linkByName(f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	netlinkNeighbors, err := netlink.NeighList(0, unix.AF_BRIDGE)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get FDB entries for bridge %q: %w", f.DevName, err)
+	}
+
+	portNames := map[int]string{}
+	entries := []FDB{}
+	for _, neighbor := range netlinkNeighbors {
+		if neighbor.MasterIndex != link.Attrs().Index || neighbor.State&netlink.NUD_PERMANENT != 0 {
+			continue
+		}
+
+		portName, found := portNames[neighbor.LinkIndex]
+		if !found {
+			port, err := netlink.LinkByIndex(neighbor.LinkIndex)
+			if err != nil {
+				continue
+			}
+
+			portName = port.Attrs().Name
+			portNames[neighbor.LinkIndex] = portName
+		}
+
+		entries = append(entries, FDB{
+			DevName: portName,
+			MAC:     neighbor.HardwareAddr,
+		})
+	}
+
+	return entries, nil
+}
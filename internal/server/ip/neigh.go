@@ -65,7 +65,7 @@ func (n *Neigh) Show() ([]Neigh, error) {
 	neighbors := make([]Neigh, 0, len(netlinkNeighbors))
 
 	for _, neighbor := range netlinkNeighbors {
-		if neighbor.HardwareAddr.String() != n.MAC.String() {
+		if n.MAC != nil && neighbor.HardwareAddr.String() != n.MAC.String() {
 			continue
 		}
 
@@ -29,6 +29,7 @@ const (
 	InstanceFileDeleted      = InstanceAction(api.EventLifecycleInstanceFileDeleted)
 	InstanceFilePushed       = InstanceAction(api.EventLifecycleInstanceFilePushed)
 	InstanceFileRetrieved    = InstanceAction(api.EventLifecycleInstanceFileRetrieved)
+	InstanceHealthy          = InstanceAction(api.EventLifecycleInstanceHealthy)
 	InstanceMigrated         = InstanceAction(api.EventLifecycleInstanceMigrated)
 	InstancePaused           = InstanceAction(api.EventLifecycleInstancePaused)
 	InstanceReady            = InstanceAction(api.EventLifecycleInstanceReady)
@@ -39,6 +40,7 @@ const (
 	InstanceShutdown         = InstanceAction(api.EventLifecycleInstanceShutdown)
 	InstanceStarted          = InstanceAction(api.EventLifecycleInstanceStarted)
 	InstanceStopped          = InstanceAction(api.EventLifecycleInstanceStopped)
+	InstanceUnhealthy        = InstanceAction(api.EventLifecycleInstanceUnhealthy)
 	InstanceUpdated          = InstanceAction(api.EventLifecycleInstanceUpdated)
 )
 
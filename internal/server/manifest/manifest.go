@@ -0,0 +1,430 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	incus "github.com/lxc/incus/v7/client"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// Change actions.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Resource types.
+const (
+	TypeProject       = "project"
+	TypeNetworkACL    = "network-acl"
+	TypeNetwork       = "network"
+	TypeStorageVolume = "storage-volume"
+	TypeProfile       = "profile"
+	TypeInstance      = "instance"
+)
+
+// step is a single change along with the function applying it.
+type step struct {
+	change api.ManifestChange
+	run    func() error
+}
+
+// planner computes the steps needed to converge a server to a manifest.
+type planner struct {
+	client   incus.InstanceServer
+	manifest api.Manifest
+	steps    []step
+}
+
+// Plan holds the changes needed to converge a server to a manifest along with the functions applying them.
+type Plan struct {
+	steps []step
+}
+
+// NewPlan computes the changes needed to converge the server to the manifest.
+func NewPlan(client incus.InstanceServer, manifest api.Manifest, prune bool) (*Plan, error) {
+	steps, err := plan(client, manifest, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{steps: steps}, nil
+}
+
+// Changes returns the changes of the plan.
+func (p *Plan) Changes() *api.ManifestPlan {
+	return changes(p.steps)
+}
+
+// Apply makes the changes of the plan in order and returns the ones that were made, stopping at the first failure.
+func (p *Plan) Apply() (*api.ManifestPlan, error) {
+	for i, s := range p.steps {
+		err := s.run()
+		if err != nil {
+			return changes(p.steps[:i]), fmt.Errorf("Failed to %s %s %q in project %q: %w", s.change.Action, s.change.Type, s.change.Name, s.change.Project, err)
+		}
+	}
+
+	return changes(p.steps), nil
+}
+
+// changes returns the plan made of the given steps.
+func changes(steps []step) *api.ManifestPlan {
+	plan := &api.ManifestPlan{Changes: make([]api.ManifestChange, 0, len(steps))}
+	for _, s := range steps {
+		plan.Changes = append(plan.Changes, s.change)
+	}
+
+	return plan
+}
+
+// plan validates the manifest and returns the steps needed to converge the server to it.
+func plan(client incus.InstanceServer, manifest api.Manifest, prune bool) ([]step, error) {
+	err := Normalize(&manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if prune && manifest.Name == "" {
+		return nil, errors.New("Pruning requires the manifest to have a name")
+	}
+
+	p := &planner{client: client, manifest: manifest}
+
+	planners := []func() error{
+		p.planProjects,
+		p.planNetworkACLs,
+		p.planNetworks,
+		p.planStorageVolumes,
+		p.planProfiles,
+		p.planInstances,
+	}
+
+	if prune {
+		planners = append(planners, p.pruneInstances, p.pruneProfiles, p.pruneStorageVolumes, p.pruneNetworks, p.pruneNetworkACLs, p.pruneProjects)
+	}
+
+	for _, planFunc := range planners {
+		err := planFunc()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.steps, nil
+}
+
+// Normalize fills in the defaults of the manifest and checks that it is consistent.
+func Normalize(manifest *api.Manifest) error {
+	seen := map[string]bool{}
+	check := func(resourceType string, project string, pool string, name string) error {
+		if name == "" {
+			return fmt.Errorf("Missing %s name", resourceType)
+		}
+
+		key := strings.Join([]string{resourceType, project, pool, name}, "/")
+		if seen[key] {
+			return fmt.Errorf("Duplicate %s %q in project %q", resourceType, name, project)
+		}
+
+		seen[key] = true
+
+		return nil
+	}
+
+	for _, project := range manifest.Projects {
+		err := check(TypeProject, "", "", project.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.NetworkACLs {
+		acl := &manifest.NetworkACLs[i]
+		acl.Project = projectName(acl.Project)
+
+		err := check(TypeNetworkACL, acl.Project, "", acl.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Networks {
+		network := &manifest.Networks[i]
+		network.Project = projectName(network.Project)
+
+		err := check(TypeNetwork, network.Project, "", network.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.StorageVolumes {
+		volume := &manifest.StorageVolumes[i]
+		volume.Project = projectName(volume.Project)
+
+		if volume.Type == "" {
+			volume.Type = "custom"
+		}
+
+		if volume.Type != "custom" {
+			return fmt.Errorf("Storage volume %q must be of type \"custom\"", volume.Name)
+		}
+
+		if volume.Pool == "" {
+			return fmt.Errorf("Missing storage pool for storage volume %q", volume.Name)
+		}
+
+		err := check(TypeStorageVolume, volume.Project, volume.Pool, volume.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Profiles {
+		profile := &manifest.Profiles[i]
+		profile.Project = projectName(profile.Project)
+
+		err := check(TypeProfile, profile.Project, "", profile.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Instances {
+		inst := &manifest.Instances[i]
+		inst.Project = projectName(inst.Project)
+
+		if inst.Type == "" {
+			inst.Type = api.InstanceTypeContainer
+		}
+
+		err := check(TypeInstance, inst.Project, "", inst.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// projectName returns the project name, defaulting to the default project.
+func projectName(project string) string {
+	if project == "" {
+		return api.ProjectDefaultName
+	}
+
+	return project
+}
+
+// add records a change along with the function applying it.
+func (p *planner) add(change api.ManifestChange, run func() error) {
+	p.steps = append(p.steps, step{change: change, run: run})
+}
+
+// ownedConfig returns a copy of the target configuration recording the manifest as the owner.
+func (p *planner) ownedConfig(config map[string]string) map[string]string {
+	result := make(map[string]string, len(config)+1)
+	for k, v := range config {
+		result[k] = v
+	}
+
+	if p.manifest.Name != "" {
+		result[api.ManifestConfigKey] = p.manifest.Name
+	}
+
+	return result
+}
+
+// checkOwner checks that an existing resource isn't owned by another manifest.
+func (p *planner) checkOwner(resourceType string, project string, name string, config map[string]string) error {
+	owner := config[api.ManifestConfigKey]
+	if owner != "" && owner != p.manifest.Name {
+		return fmt.Errorf("The %s %q in project %q is owned by manifest %q", resourceType, name, project, owner)
+	}
+
+	return nil
+}
+
+// owned returns whether the resource configuration marks it as owned by the manifest.
+func (p *planner) owned(config map[string]string) bool {
+	return config[api.ManifestConfigKey] == p.manifest.Name
+}
+
+// effectiveProject returns the project holding the resources governed by the given project feature.
+func (p *planner) effectiveProject(project string, feature string) (string, error) {
+	if project == api.ProjectDefaultName {
+		return project, nil
+	}
+
+	enabled := dbCluster.ProjectFeatures[feature].DefaultEnabled
+
+	current, _, err := p.client.GetProject(project)
+	if err == nil {
+		enabled = util.IsTrue(current.Config[feature])
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return "", fmt.Errorf("Failed to retrieve project %q: %w", project, err)
+	} else {
+		for _, target := range p.manifest.Projects {
+			value, ok := target.Config[feature]
+			if target.Name == project && ok {
+				enabled = util.IsTrue(value)
+			}
+		}
+	}
+
+	if !enabled {
+		return api.ProjectDefaultName, nil
+	}
+
+	return project, nil
+}
+
+// diffValue returns the change of a single field, if any.
+func diffValue(key string, old string, new string) []api.ManifestFieldChange {
+	if old == new {
+		return nil
+	}
+
+	return []api.ManifestFieldChange{{Key: key, Old: old, New: new}}
+}
+
+// diffDescription returns the change of the description, if one is requested.
+func diffDescription(old string, new string) []api.ManifestFieldChange {
+	if new == "" {
+		return nil
+	}
+
+	return diffValue("description", old, new)
+}
+
+// diffConfig returns the changes needed for the current configuration to contain all target keys.
+// Keys which aren't part of the target are left untouched.
+func diffConfig(prefix string, current map[string]string, target map[string]string) []api.ManifestFieldChange {
+	keys := make([]string, 0, len(target))
+	for k := range target {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	result := []api.ManifestFieldChange{}
+	for _, k := range keys {
+		result = append(result, diffValue(prefix+"."+k, current[k], target[k])...)
+	}
+
+	return result
+}
+
+// mergeConfig returns the current configuration updated with the target keys.
+func mergeConfig(current map[string]string, target map[string]string) map[string]string {
+	result := make(map[string]string, len(current)+len(target))
+	for k, v := range current {
+		result[k] = v
+	}
+
+	for k, v := range target {
+		if v == "" {
+			delete(result, k)
+			continue
+		}
+
+		result[k] = v
+	}
+
+	return result
+}
+
+// diffDevices returns the changes needed for the current devices to match the target devices.
+// Each target device is enforced as a whole while devices which aren't part of the target are left untouched.
+func diffDevices(current map[string]map[string]string, target map[string]map[string]string) []api.ManifestFieldChange {
+	names := make([]string, 0, len(target))
+	for name := range target {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	result := []api.ManifestFieldChange{}
+	for _, name := range names {
+		keys := []string{}
+		for k := range target[name] {
+			keys = append(keys, k)
+		}
+
+		for k := range current[name] {
+			_, ok := target[name][k]
+			if !ok {
+				keys = append(keys, k)
+			}
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			result = append(result, diffValue("devices."+name+"."+k, current[name][k], target[name][k])...)
+		}
+	}
+
+	return result
+}
+
+// mergeDevices returns the current devices with the target devices replacing them.
+func mergeDevices(current map[string]map[string]string, target map[string]map[string]string) map[string]map[string]string {
+	result := make(map[string]map[string]string, len(current)+len(target))
+	for name, device := range current {
+		result[name] = device
+	}
+
+	for name, device := range target {
+		result[name] = device
+	}
+
+	return result
+}
+
+// formatRules returns a human readable representation of network ACL rules.
+func formatRules(rules []api.NetworkACLRule) string {
+	parts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		fields := []string{"action=" + rule.Action}
+		for _, field := range []struct {
+			key   string
+			value string
+		}{
+			{"source", rule.Source},
+			{"destination", rule.Destination},
+			{"protocol", rule.Protocol},
+			{"source_port", rule.SourcePort},
+			{"destination_port", rule.DestinationPort},
+			{"icmp_type", rule.ICMPType},
+			{"icmp_code", rule.ICMPCode},
+			{"state", rule.State},
+		} {
+			if field.value != "" {
+				fields = append(fields, field.key+"="+field.value)
+			}
+		}
+
+		parts = append(parts, strings.Join(fields, " "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// diffRules returns the change of a list of network ACL rules, if one is requested.
+func diffRules(key string, current []api.NetworkACLRule, target []api.NetworkACLRule) []api.ManifestFieldChange {
+	if target == nil || slices.Equal(current, target) {
+		return nil
+	}
+
+	return []api.ManifestFieldChange{{Key: key, Old: formatRules(current), New: formatRules(target)}}
+}
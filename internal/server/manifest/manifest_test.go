@@ -0,0 +1,88 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func TestDiffConfig(t *testing.T) {
+	current := map[string]string{"limits.cpu": "2", "limits.memory": "1GiB", "user.foo": "bar"}
+	target := map[string]string{"limits.cpu": "4", "limits.memory": "1GiB", "security.nesting": "true", "user.foo": ""}
+
+	assert.Equal(t, []api.ManifestFieldChange{
+		{Key: "config.limits.cpu", Old: "2", New: "4"},
+		{Key: "config.security.nesting", Old: "", New: "true"},
+		{Key: "config.user.foo", Old: "bar", New: ""},
+	}, diffConfig("config", current, target))
+
+	// Merging converges the configuration.
+	merged := mergeConfig(current, target)
+	assert.Equal(t, map[string]string{"limits.cpu": "4", "limits.memory": "1GiB", "security.nesting": "true"}, merged)
+	assert.Empty(t, diffConfig("config", merged, target))
+
+	// The current configuration isn't modified.
+	assert.Equal(t, "2", current["limits.cpu"])
+}
+
+func TestDiffDevices(t *testing.T) {
+	current := map[string]map[string]string{
+		"eth0": {"type": "nic", "network": "incusbr0", "mtu": "1400"},
+		"root": {"type": "disk", "path": "/", "pool": "default"},
+	}
+
+	target := map[string]map[string]string{
+		"eth0": {"type": "nic", "network": "net1"},
+		"data": {"type": "disk", "path": "/data", "source": "vol1", "pool": "default"},
+	}
+
+	assert.Equal(t, []api.ManifestFieldChange{
+		{Key: "devices.data.path", Old: "", New: "/data"},
+		{Key: "devices.data.pool", Old: "", New: "default"},
+		{Key: "devices.data.source", Old: "", New: "vol1"},
+		{Key: "devices.data.type", Old: "", New: "disk"},
+		{Key: "devices.eth0.mtu", Old: "1400", New: ""},
+		{Key: "devices.eth0.network", Old: "incusbr0", New: "net1"},
+	}, diffDevices(current, target))
+
+	// Unlisted devices are kept.
+	merged := mergeDevices(current, target)
+	assert.Len(t, merged, 3)
+	assert.Equal(t, current["root"], merged["root"])
+	assert.Empty(t, diffDevices(merged, target))
+}
+
+func TestDiffRules(t *testing.T) {
+	rules := []api.NetworkACLRule{{Action: "allow", Destination: "10.0.0.0/8", Protocol: "tcp", DestinationPort: "22", State: "enabled"}}
+
+	assert.Empty(t, diffRules("ingress", rules, nil))
+	assert.Empty(t, diffRules("ingress", rules, rules))
+	assert.Equal(t, []api.ManifestFieldChange{
+		{Key: "ingress", Old: "", New: "action=allow destination=10.0.0.0/8 protocol=tcp destination_port=22 state=enabled"},
+	}, diffRules("ingress", nil, rules))
+}
+
+func TestNormalize(t *testing.T) {
+	manifest := api.Manifest{
+		Instances:      []api.ManifestInstance{{InstancesPost: api.InstancesPost{Name: "c1"}}},
+		StorageVolumes: []api.ManifestStorageVolume{{StorageVolumesPost: api.StorageVolumesPost{Name: "vol1"}, Pool: "default"}},
+	}
+
+	assert.NoError(t, Normalize(&manifest))
+	assert.Equal(t, api.ProjectDefaultName, manifest.Instances[0].Project)
+	assert.Equal(t, api.InstanceTypeContainer, manifest.Instances[0].Type)
+	assert.Equal(t, "custom", manifest.StorageVolumes[0].Type)
+
+	// Names must be unique within a project.
+	manifest.Instances = append(manifest.Instances, api.ManifestInstance{InstancesPost: api.InstancesPost{Name: "c1"}})
+	assert.Error(t, Normalize(&manifest))
+
+	manifest.Instances[1].Project = "other"
+	assert.NoError(t, Normalize(&manifest))
+
+	// Volumes need a pool.
+	manifest.StorageVolumes[0].Pool = ""
+	assert.Error(t, Normalize(&manifest))
+}
@@ -0,0 +1,639 @@
+package manifest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// planProjects plans the creation and update of the manifest projects.
+func (p *planner) planProjects() error {
+	for _, target := range p.manifest.Projects {
+		config := p.ownedConfig(target.Config)
+		change := api.ManifestChange{Type: TypeProject, Project: target.Name, Name: target.Name}
+
+		current, _, err := p.client.GetProject(target.Name)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return fmt.Errorf("Failed to retrieve project %q: %w", target.Name, err)
+			}
+
+			req := target
+			req.Config = config
+
+			change.Action = ActionCreate
+			change.Fields = append(diffDescription("", target.Description), diffConfig("config", nil, config)...)
+			p.add(change, func() error { return p.client.CreateProject(req) })
+			continue
+		}
+
+		err = p.checkOwner(TypeProject, target.Name, target.Name, current.Config)
+		if err != nil {
+			return err
+		}
+
+		change.Action = ActionUpdate
+		change.Fields = append(diffDescription(current.Description, target.Description), diffConfig("config", current.Config, config)...)
+		if len(change.Fields) == 0 {
+			continue
+		}
+
+		p.add(change, func() error {
+			current, etag, err := p.client.GetProject(target.Name)
+			if err != nil {
+				return err
+			}
+
+			req := current.Writable()
+			if target.Description != "" {
+				req.Description = target.Description
+			}
+
+			req.Config = mergeConfig(current.Config, config)
+
+			return p.client.UpdateProject(target.Name, req, etag)
+		})
+	}
+
+	return nil
+}
+
+// planNetworkACLs plans the creation and update of the manifest network ACLs.
+func (p *planner) planNetworkACLs() error {
+	for _, target := range p.manifest.NetworkACLs {
+		client := p.client.UseProject(target.Project)
+		config := p.ownedConfig(target.Config)
+		change := api.ManifestChange{Type: TypeNetworkACL, Project: target.Project, Name: target.Name}
+
+		current, _, err := client.GetNetworkACL(target.Name)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return fmt.Errorf("Failed to retrieve network ACL %q in project %q: %w", target.Name, target.Project, err)
+			}
+
+			req := target.NetworkACLsPost
+			req.Config = config
+
+			change.Action = ActionCreate
+			change.Fields = append(diffDescription("", target.Description), diffConfig("config", nil, config)...)
+			change.Fields = append(change.Fields, diffRules("ingress", nil, target.Ingress)...)
+			change.Fields = append(change.Fields, diffRules("egress", nil, target.Egress)...)
+			p.add(change, func() error { return client.CreateNetworkACL(req) })
+			continue
+		}
+
+		err = p.checkOwner(TypeNetworkACL, target.Project, target.Name, current.Config)
+		if err != nil {
+			return err
+		}
+
+		change.Action = ActionUpdate
+		change.Fields = append(diffDescription(current.Description, target.Description), diffConfig("config", current.Config, config)...)
+		change.Fields = append(change.Fields, diffRules("ingress", current.Ingress, target.Ingress)...)
+		change.Fields = append(change.Fields, diffRules("egress", current.Egress, target.Egress)...)
+		if len(change.Fields) == 0 {
+			continue
+		}
+
+		p.add(change, func() error {
+			current, etag, err := client.GetNetworkACL(target.Name)
+			if err != nil {
+				return err
+			}
+
+			req := current.Writable()
+			if target.Description != "" {
+				req.Description = target.Description
+			}
+
+			if target.Ingress != nil {
+				req.Ingress = target.Ingress
+			}
+
+			if target.Egress != nil {
+				req.Egress = target.Egress
+			}
+
+			req.Config = mergeConfig(current.Config, config)
+
+			return client.UpdateNetworkACL(target.Name, req, etag)
+		})
+	}
+
+	return nil
+}
+
+// planNetworks plans the creation and update of the manifest networks.
+func (p *planner) planNetworks() error {
+	for _, target := range p.manifest.Networks {
+		client := p.client.UseProject(target.Project)
+		config := p.ownedConfig(target.Config)
+		change := api.ManifestChange{Type: TypeNetwork, Project: target.Project, Name: target.Name}
+
+		current, _, err := client.GetNetwork(target.Name)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed to retrieve network %q in project %q: %w", target.Name, target.Project, err)
+		}
+
+		if err != nil || !current.Managed {
+			req := target.NetworksPost
+			req.Config = config
+
+			change.Action = ActionCreate
+			change.Fields = append(diffValue("type", "", target.Type), diffDescription("", target.Description)...)
+			change.Fields = append(change.Fields, diffConfig("config", nil, config)...)
+			p.add(change, func() error { return client.CreateNetwork(req) })
+			continue
+		}
+
+		if target.Type != "" && current.Type != target.Type {
+			return fmt.Errorf("Network %q in project %q is of type %q instead of %q", target.Name, target.Project, current.Type, target.Type)
+		}
+
+		err = p.checkOwner(TypeNetwork, target.Project, target.Name, current.Config)
+		if err != nil {
+			return err
+		}
+
+		change.Action = ActionUpdate
+		change.Fields = append(diffDescription(current.Description, target.Description), diffConfig("config", current.Config, config)...)
+		if len(change.Fields) == 0 {
+			continue
+		}
+
+		p.add(change, func() error {
+			current, etag, err := client.GetNetwork(target.Name)
+			if err != nil {
+				return err
+			}
+
+			req := current.Writable()
+			if target.Description != "" {
+				req.Description = target.Description
+			}
+
+			req.Config = mergeConfig(current.Config, config)
+
+			return client.UpdateNetwork(target.Name, req, etag)
+		})
+	}
+
+	return nil
+}
+
+// planStorageVolumes plans the creation and update of the manifest custom storage volumes.
+func (p *planner) planStorageVolumes() error {
+	for _, target := range p.manifest.StorageVolumes {
+		client := p.client.UseProject(target.Project)
+		config := p.ownedConfig(target.Config)
+		change := api.ManifestChange{Type: TypeStorageVolume, Project: target.Project, Pool: target.Pool, Name: target.Name}
+
+		current, _, err := client.GetStoragePoolVolume(target.Pool, target.Type, target.Name)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return fmt.Errorf("Failed to retrieve storage volume %q in project %q on pool %q: %w", target.Name, target.Project, target.Pool, err)
+			}
+
+			req := target.StorageVolumesPost
+			req.Config = config
+
+			change.Action = ActionCreate
+			change.Fields = append(diffValue("content_type", "", target.ContentType), diffDescription("", target.Description)...)
+			change.Fields = append(change.Fields, diffConfig("config", nil, config)...)
+			p.add(change, func() error { return client.CreateStoragePoolVolume(target.Pool, req) })
+			continue
+		}
+
+		if target.ContentType != "" && current.ContentType != target.ContentType {
+			return fmt.Errorf("Storage volume %q in project %q is of content type %q instead of %q", target.Name, target.Project, current.ContentType, target.ContentType)
+		}
+
+		err = p.checkOwner(TypeStorageVolume, target.Project, target.Name, current.Config)
+		if err != nil {
+			return err
+		}
+
+		change.Action = ActionUpdate
+		change.Fields = append(diffDescription(current.Description, target.Description), diffConfig("config", current.Config, config)...)
+		if len(change.Fields) == 0 {
+			continue
+		}
+
+		p.add(change, func() error {
+			current, etag, err := client.GetStoragePoolVolume(target.Pool, target.Type, target.Name)
+			if err != nil {
+				return err
+			}
+
+			req := current.Writable()
+			if target.Description != "" {
+				req.Description = target.Description
+			}
+
+			req.Config = mergeConfig(current.Config, config)
+
+			return client.UpdateStoragePoolVolume(target.Pool, target.Type, target.Name, req, etag)
+		})
+	}
+
+	return nil
+}
+
+// planProfiles plans the creation and update of the manifest profiles.
+func (p *planner) planProfiles() error {
+	for _, target := range p.manifest.Profiles {
+		client := p.client.UseProject(target.Project)
+		config := p.ownedConfig(target.Config)
+		change := api.ManifestChange{Type: TypeProfile, Project: target.Project, Name: target.Name}
+
+		current, _, err := client.GetProfile(target.Name)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed to retrieve profile %q in project %q: %w", target.Name, target.Project, err)
+		}
+
+		// The default profile comes with its project, so it's only ever updated.
+		if err != nil && target.Name == "default" {
+			current = &api.Profile{}
+		} else if err != nil {
+			req := target.ProfilesPost
+			req.Config = config
+
+			change.Action = ActionCreate
+			change.Fields = append(diffDescription("", target.Description), diffConfig("config", nil, config)...)
+			change.Fields = append(change.Fields, diffDevices(nil, target.Devices)...)
+			p.add(change, func() error { return client.CreateProfile(req) })
+			continue
+		}
+
+		// The default profile always exists and may be converged without being owned.
+		if target.Name != "default" {
+			err = p.checkOwner(TypeProfile, target.Project, target.Name, current.Config)
+			if err != nil {
+				return err
+			}
+		}
+
+		change.Action = ActionUpdate
+		change.Fields = append(diffDescription(current.Description, target.Description), diffConfig("config", current.Config, config)...)
+		change.Fields = append(change.Fields, diffDevices(current.Devices, target.Devices)...)
+		if len(change.Fields) == 0 {
+			continue
+		}
+
+		p.add(change, func() error {
+			current, etag, err := client.GetProfile(target.Name)
+			if err != nil {
+				return err
+			}
+
+			req := current.Writable()
+			if target.Description != "" {
+				req.Description = target.Description
+			}
+
+			req.Config = mergeConfig(current.Config, config)
+			req.Devices = mergeDevices(current.Devices, target.Devices)
+
+			return client.UpdateProfile(target.Name, req, etag)
+		})
+	}
+
+	return nil
+}
+
+// planInstances plans the creation and update of the manifest instances.
+func (p *planner) planInstances() error {
+	for _, target := range p.manifest.Instances {
+		client := p.client.UseProject(target.Project)
+		config := p.ownedConfig(target.Config)
+		change := api.ManifestChange{Type: TypeInstance, Project: target.Project, Name: target.Name}
+
+		current, _, err := client.GetInstance(target.Name)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return fmt.Errorf("Failed to retrieve instance %q in project %q: %w", target.Name, target.Project, err)
+			}
+
+			req := target.InstancesPost
+			req.Config = config
+
+			change.Action = ActionCreate
+			change.Fields = append(diffValue("type", "", string(target.Type)), diffDescription("", target.Description)...)
+			if target.Profiles != nil {
+				change.Fields = append(change.Fields, diffValue("profiles", "", strings.Join(target.Profiles, ", "))...)
+			}
+
+			change.Fields = append(change.Fields, diffConfig("config", nil, config)...)
+			change.Fields = append(change.Fields, diffDevices(nil, target.Devices)...)
+			p.add(change, func() error {
+				op, err := client.CreateInstance(req)
+				if err != nil {
+					return err
+				}
+
+				return op.Wait()
+			})
+
+			continue
+		}
+
+		if current.Type != string(target.Type) {
+			return fmt.Errorf("Instance %q in project %q is of type %q instead of %q", target.Name, target.Project, current.Type, target.Type)
+		}
+
+		err = p.checkOwner(TypeInstance, target.Project, target.Name, current.Config)
+		if err != nil {
+			return err
+		}
+
+		change.Action = ActionUpdate
+		change.Fields = diffDescription(current.Description, target.Description)
+		if target.Profiles != nil && !slices.Equal(current.Profiles, target.Profiles) {
+			change.Fields = append(change.Fields, diffValue("profiles", strings.Join(current.Profiles, ", "), strings.Join(target.Profiles, ", "))...)
+		}
+
+		change.Fields = append(change.Fields, diffConfig("config", current.Config, config)...)
+		change.Fields = append(change.Fields, diffDevices(current.Devices, target.Devices)...)
+
+		start := target.Start && current.StatusCode == api.Stopped
+		if start {
+			change.Fields = append(change.Fields, diffValue("status", current.Status, api.Running.String())...)
+		}
+
+		if len(change.Fields) == 0 {
+			continue
+		}
+
+		p.add(change, func() error {
+			current, etag, err := client.GetInstance(target.Name)
+			if err != nil {
+				return err
+			}
+
+			req := current.Writable()
+			if target.Description != "" {
+				req.Description = target.Description
+			}
+
+			if target.Profiles != nil {
+				req.Profiles = target.Profiles
+			}
+
+			req.Config = mergeConfig(current.Config, config)
+			req.Devices = mergeDevices(current.Devices, target.Devices)
+
+			op, err := client.UpdateInstance(target.Name, req, etag)
+			if err != nil {
+				return err
+			}
+
+			err = op.Wait()
+			if err != nil {
+				return err
+			}
+
+			if !start {
+				return nil
+			}
+
+			op, err = client.UpdateInstanceState(target.Name, api.InstanceStatePut{Action: "start", Timeout: -1}, "")
+			if err != nil {
+				return err
+			}
+
+			return op.Wait()
+		})
+	}
+
+	return nil
+}
+
+// listed returns whether a resource is part of the manifest.
+func listed[T any](items []T, match func(T) bool) bool {
+	return slices.ContainsFunc(items, match)
+}
+
+// pruneInstances plans the deletion of the owned instances which aren't part of the manifest.
+func (p *planner) pruneInstances() error {
+	instances, err := p.client.GetInstancesAllProjects(api.InstanceTypeAny)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve instances: %w", err)
+	}
+
+	for _, inst := range instances {
+		if !p.owned(inst.Config) || listed(p.manifest.Instances, func(target api.ManifestInstance) bool {
+			return target.Project == inst.Project && target.Name == inst.Name
+		}) {
+			continue
+		}
+
+		client := p.client.UseProject(inst.Project)
+		change := api.ManifestChange{Action: ActionDelete, Type: TypeInstance, Project: inst.Project, Name: inst.Name, Fields: []api.ManifestFieldChange{}}
+		p.add(change, func() error {
+			current, _, err := client.GetInstance(change.Name)
+			if err != nil {
+				return err
+			}
+
+			if current.StatusCode != api.Stopped {
+				op, err := client.UpdateInstanceState(change.Name, api.InstanceStatePut{Action: "stop", Timeout: -1, Force: true}, "")
+				if err != nil {
+					return err
+				}
+
+				err = op.Wait()
+				if err != nil {
+					return err
+				}
+			}
+
+			op, err := client.DeleteInstance(change.Name)
+			if err != nil {
+				return err
+			}
+
+			return op.Wait()
+		})
+	}
+
+	return nil
+}
+
+// pruneProfiles plans the deletion of the owned profiles which aren't part of the manifest.
+func (p *planner) pruneProfiles() error {
+	profiles, err := p.client.GetProfilesAllProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve profiles: %w", err)
+	}
+
+	for _, profile := range profiles {
+		if profile.Name == "default" || !p.owned(profile.Config) {
+			continue
+		}
+
+		isListed := false
+		for _, target := range p.manifest.Profiles {
+			project, err := p.effectiveProject(target.Project, "features.profiles")
+			if err != nil {
+				return err
+			}
+
+			if project == profile.Project && target.Name == profile.Name {
+				isListed = true
+				break
+			}
+		}
+
+		if isListed {
+			continue
+		}
+
+		client := p.client.UseProject(profile.Project)
+		change := api.ManifestChange{Action: ActionDelete, Type: TypeProfile, Project: profile.Project, Name: profile.Name, Fields: []api.ManifestFieldChange{}}
+		p.add(change, func() error { return client.DeleteProfile(change.Name) })
+	}
+
+	return nil
+}
+
+// pruneStorageVolumes plans the deletion of the owned custom storage volumes which aren't part of the manifest.
+func (p *planner) pruneStorageVolumes() error {
+	pools, err := p.client.GetStoragePoolNames()
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve storage pools: %w", err)
+	}
+
+	for _, pool := range pools {
+		volumes, err := p.client.GetStoragePoolVolumesAllProjects(pool)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve storage volumes on pool %q: %w", pool, err)
+		}
+
+		for _, volume := range volumes {
+			if volume.Type != "custom" || !p.owned(volume.Config) {
+				continue
+			}
+
+			isListed := false
+			for _, target := range p.manifest.StorageVolumes {
+				project, err := p.effectiveProject(target.Project, "features.storage.volumes")
+				if err != nil {
+					return err
+				}
+
+				if project == volume.Project && target.Pool == pool && target.Name == volume.Name {
+					isListed = true
+					break
+				}
+			}
+
+			if isListed {
+				continue
+			}
+
+			client := p.client.UseProject(volume.Project)
+			change := api.ManifestChange{Action: ActionDelete, Type: TypeStorageVolume, Project: volume.Project, Pool: pool, Name: volume.Name, Fields: []api.ManifestFieldChange{}}
+			p.add(change, func() error { return client.DeleteStoragePoolVolume(change.Pool, "custom", change.Name) })
+		}
+	}
+
+	return nil
+}
+
+// pruneNetworks plans the deletion of the owned networks which aren't part of the manifest.
+func (p *planner) pruneNetworks() error {
+	networks, err := p.client.GetNetworksAllProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve networks: %w", err)
+	}
+
+	for _, network := range networks {
+		if !network.Managed || !p.owned(network.Config) {
+			continue
+		}
+
+		isListed := false
+		for _, target := range p.manifest.Networks {
+			project, err := p.effectiveProject(target.Project, "features.networks")
+			if err != nil {
+				return err
+			}
+
+			if project == network.Project && target.Name == network.Name {
+				isListed = true
+				break
+			}
+		}
+
+		if isListed {
+			continue
+		}
+
+		client := p.client.UseProject(network.Project)
+		change := api.ManifestChange{Action: ActionDelete, Type: TypeNetwork, Project: network.Project, Name: network.Name, Fields: []api.ManifestFieldChange{}}
+		p.add(change, func() error { return client.DeleteNetwork(change.Name) })
+	}
+
+	return nil
+}
+
+// pruneNetworkACLs plans the deletion of the owned network ACLs which aren't part of the manifest.
+func (p *planner) pruneNetworkACLs() error {
+	acls, err := p.client.GetNetworkACLsAllProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve network ACLs: %w", err)
+	}
+
+	for _, acl := range acls {
+		if !p.owned(acl.Config) {
+			continue
+		}
+
+		isListed := false
+		for _, target := range p.manifest.NetworkACLs {
+			project, err := p.effectiveProject(target.Project, "features.networks")
+			if err != nil {
+				return err
+			}
+
+			if project == acl.Project && target.Name == acl.Name {
+				isListed = true
+				break
+			}
+		}
+
+		if isListed {
+			continue
+		}
+
+		client := p.client.UseProject(acl.Project)
+		change := api.ManifestChange{Action: ActionDelete, Type: TypeNetworkACL, Project: acl.Project, Name: acl.Name, Fields: []api.ManifestFieldChange{}}
+		p.add(change, func() error { return client.DeleteNetworkACL(change.Name) })
+	}
+
+	return nil
+}
+
+// pruneProjects plans the deletion of the owned projects which aren't part of the manifest.
+func (p *planner) pruneProjects() error {
+	projects, err := p.client.GetProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve projects: %w", err)
+	}
+
+	for _, project := range projects {
+		if project.Name == api.ProjectDefaultName || !p.owned(project.Config) || listed(p.manifest.Projects, func(target api.ProjectsPost) bool {
+			return target.Name == project.Name
+		}) {
+			continue
+		}
+
+		change := api.ManifestChange{Action: ActionDelete, Type: TypeProject, Project: project.Name, Name: project.Name, Fields: []api.ManifestFieldChange{}}
+		p.add(change, func() error { return p.client.DeleteProject(change.Name) })
+	}
+
+	return nil
+}
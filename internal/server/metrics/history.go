@@ -0,0 +1,358 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Metrics kept in the history.
+const (
+	HistoryCPU             = "cpu"
+	HistoryMemory          = "memory"
+	HistoryDiskRead        = "disk_read"
+	HistoryDiskWrite       = "disk_write"
+	HistoryNetworkReceive  = "network_receive"
+	HistoryNetworkTransmit = "network_transmit"
+	HistoryProcesses       = "processes"
+)
+
+// historyCounters lists the history metrics recorded from counters and kept as a per-second rate.
+var historyCounters = []string{HistoryCPU, HistoryDiskRead, HistoryDiskWrite, HistoryNetworkReceive, HistoryNetworkTransmit}
+
+// HistoryTier defines a resolution at which the history is kept.
+type HistoryTier struct {
+	Step time.Duration
+	Size int
+}
+
+// HistoryTiers are the resolutions at which the history is kept, from finest to coarsest.
+// Every sample is recorded in all tiers, each tier averaging the samples falling within the same step.
+var HistoryTiers = []HistoryTier{
+	{Step: time.Minute, Size: 120},      // Two hours at one minute resolution.
+	{Step: 10 * time.Minute, Size: 144}, // A day at ten minutes resolution.
+	{Step: time.Hour, Size: 168},        // A week at one hour resolution.
+}
+
+// historyRing is a fixed size ring buffer of averaged values.
+type historyRing struct {
+	Step   int64     `json:"step"`
+	Last   int64     `json:"last"` // Index of the most recent bucket (unix time divided by step).
+	Values []float64 `json:"values"`
+	Counts []uint32  `json:"counts"`
+}
+
+func newHistoryRing(tier HistoryTier) *historyRing {
+	return &historyRing{
+		Step:   int64(tier.Step / time.Second),
+		Values: make([]float64, tier.Size),
+		Counts: make([]uint32, tier.Size),
+	}
+}
+
+// add records a value in the bucket holding the given time.
+func (r *historyRing) add(t time.Time, value float64) {
+	size := int64(len(r.Values))
+	bucket := t.Unix() / r.Step
+
+	// Drop values which are too old to be kept.
+	if bucket <= r.Last-size {
+		return
+	}
+
+	// Clear the buckets which were skipped over.
+	if bucket > r.Last {
+		for b := max(r.Last+1, bucket-size+1); b <= bucket; b++ {
+			r.Values[b%size] = 0
+			r.Counts[b%size] = 0
+		}
+
+		r.Last = bucket
+	}
+
+	slot := bucket % size
+	r.Values[slot] = (r.Values[slot]*float64(r.Counts[slot]) + value) / float64(r.Counts[slot]+1)
+	r.Counts[slot]++
+}
+
+// covers returns whether the ring still holds the bucket of the given time.
+func (r *historyRing) covers(t time.Time) bool {
+	return t.Unix()/r.Step > r.Last-int64(len(r.Values))
+}
+
+// points returns the values recorded between start and end, averaged over step.
+func (r *historyRing) points(start time.Time, end time.Time, step int64) []api.MetricsHistoryPoint {
+	size := int64(len(r.Values))
+	step = max(step, r.Step)
+
+	first := max(start.Unix()/r.Step, r.Last-size+1)
+	last := min(end.Unix()/r.Step, r.Last)
+
+	points := []api.MetricsHistoryPoint{}
+	var sum float64
+	var count uint32
+	var current int64 = -1
+
+	flush := func() {
+		if count > 0 {
+			points = append(points, api.MetricsHistoryPoint{Time: time.Unix(current*step, 0).UTC(), Value: sum / float64(count)})
+		}
+
+		sum = 0
+		count = 0
+	}
+
+	for b := first; b <= last; b++ {
+		slot := b % size
+		if r.Counts[slot] == 0 {
+			continue
+		}
+
+		group := b * r.Step / step
+		if group != current {
+			flush()
+			current = group
+		}
+
+		sum += r.Values[slot] * float64(r.Counts[slot])
+		count += r.Counts[slot]
+	}
+
+	flush()
+
+	return points
+}
+
+// historySeries is the history of a single metric.
+type historySeries struct {
+	Project  string         `json:"project"`
+	Instance string         `json:"instance"`
+	Metric   string         `json:"metric"`
+	Rings    []*historyRing `json:"rings"`
+
+	// Last raw counter value, used to compute rates.
+	LastValue float64   `json:"last_value"`
+	LastTime  time.Time `json:"last_time"`
+}
+
+// History keeps a bounded and downsampled history of instance and host metrics.
+type History struct {
+	mu     sync.Mutex
+	series map[string]*historySeries
+}
+
+// NewHistory returns a new empty History.
+func NewHistory() *History {
+	return &History{series: map[string]*historySeries{}}
+}
+
+// historyKey returns the key of a series. Host series have an empty project and instance.
+func historyKey(projectName string, instanceName string, metric string) string {
+	return strings.Join([]string{projectName, instanceName, metric}, "/")
+}
+
+// Record adds the values sampled at the given time for an instance, or for the host when projectName and
+// instanceName are empty. Values of counter metrics are converted to per-second rates.
+func (h *History) Record(projectName string, instanceName string, t time.Time, values map[string]float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for metric, value := range values {
+		key := historyKey(projectName, instanceName, metric)
+
+		series, ok := h.series[key]
+		if !ok {
+			series = &historySeries{Project: projectName, Instance: instanceName, Metric: metric}
+			for _, tier := range HistoryTiers {
+				series.Rings = append(series.Rings, newHistoryRing(tier))
+			}
+
+			h.series[key] = series
+		}
+
+		isCounter := false
+		for _, counter := range historyCounters {
+			if counter == metric {
+				isCounter = true
+				break
+			}
+		}
+
+		if isCounter {
+			lastValue := series.LastValue
+			lastTime := series.LastTime
+			series.LastValue = value
+			series.LastTime = t
+
+			// Skip the first sample and counter resets.
+			elapsed := t.Sub(lastTime).Seconds()
+			if lastTime.IsZero() || elapsed <= 0 || value < lastValue {
+				continue
+			}
+
+			value = (value - lastValue) / elapsed
+		}
+
+		for _, ring := range series.Rings {
+			ring.add(t, value)
+		}
+	}
+}
+
+// Expire removes the series which haven't been updated since the given time.
+func (h *History) Expire(before time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, series := range h.series {
+		ring := series.Rings[0]
+		if time.Unix((ring.Last+1)*ring.Step, 0).Before(before) {
+			delete(h.series, key)
+		}
+	}
+}
+
+// Query returns the history of the metrics between start and end, averaged over step.
+// The finest resolution still covering start is used and step is raised to it if needed.
+// The filter is called for each series, with empty project and instance names for the host.
+func (h *History) Query(start time.Time, end time.Time, step time.Duration, filter func(projectName string, instanceName string, metric string) bool) []api.MetricsHistorySeries {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := []api.MetricsHistorySeries{}
+	for _, series := range h.series {
+		if filter != nil && !filter(series.Project, series.Instance, series.Metric) {
+			continue
+		}
+
+		ring := series.Rings[len(series.Rings)-1]
+		for _, r := range series.Rings {
+			if r.covers(start) {
+				ring = r
+				break
+			}
+		}
+
+		result = append(result, api.MetricsHistorySeries{
+			Project:  series.Project,
+			Instance: series.Instance,
+			Metric:   series.Metric,
+			Step:     max(int64(step/time.Second), ring.Step),
+			Points:   ring.points(start, end, int64(step/time.Second)),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return historyKey(result[i].Project, result[i].Instance, result[i].Metric) < historyKey(result[j].Project, result[j].Instance, result[j].Metric)
+	})
+
+	return result
+}
+
+// Save writes the history to the given path.
+func (h *History) Save(path string) error {
+	h.mu.Lock()
+	data, err := json.Marshal(h.series)
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path+".tmp", data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// Load reads the history from the given path, if present.
+func (h *History) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	series := map[string]*historySeries{}
+	err = json.Unmarshal(data, &series)
+	if err != nil {
+		return fmt.Errorf("Failed parsing metrics history: %w", err)
+	}
+
+	// Discard series recorded with different tiers.
+	for key, s := range series {
+		if len(s.Rings) != len(HistoryTiers) {
+			delete(series, key)
+			continue
+		}
+
+		for i, tier := range HistoryTiers {
+			if s.Rings[i].Step != int64(tier.Step/time.Second) || len(s.Rings[i].Values) != tier.Size || len(s.Rings[i].Counts) != tier.Size {
+				delete(series, key)
+				break
+			}
+		}
+	}
+
+	h.mu.Lock()
+	h.series = series
+	h.mu.Unlock()
+
+	return nil
+}
+
+// HistoryValues returns the values kept in the history from an instance metric set.
+func HistoryValues(m *MetricSet) map[string]float64 {
+	sum := func(metricType MetricType, skip func(labels map[string]string) bool) (float64, bool) {
+		samples, ok := m.set[metricType]
+		if !ok {
+			return 0, false
+		}
+
+		var total float64
+		for _, sample := range samples {
+			if skip != nil && skip(sample.Labels) {
+				continue
+			}
+
+			total += sample.Value
+		}
+
+		return total, true
+	}
+
+	values := map[string]float64{}
+	add := func(metric string, metricType MetricType, skip func(labels map[string]string) bool) {
+		value, ok := sum(metricType, skip)
+		if ok {
+			values[metric] = value
+		}
+	}
+
+	isLoopback := func(labels map[string]string) bool { return labels["device"] == "lo" }
+
+	add(HistoryCPU, CPUSecondsTotal, func(labels map[string]string) bool { return labels["mode"] == "idle" })
+	add(HistoryDiskRead, DiskReadBytesTotal, nil)
+	add(HistoryDiskWrite, DiskWrittenBytesTotal, nil)
+	add(HistoryNetworkReceive, NetworkReceiveBytesTotal, isLoopback)
+	add(HistoryNetworkTransmit, NetworkTransmitBytesTotal, isLoopback)
+	add(HistoryProcesses, ProcsTotal, nil)
+
+	total, hasTotal := sum(MemoryMemTotalBytes, nil)
+	available, hasAvailable := sum(MemoryMemAvailableBytes, nil)
+	if hasTotal && hasAvailable {
+		values[HistoryMemory] = total - available
+	}
+
+	return values
+}
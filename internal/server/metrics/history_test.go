@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func TestHistoryRing(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	r := newHistoryRing(HistoryTier{Step: time.Minute, Size: 4})
+
+	// Values within a step are averaged.
+	r.add(start, 1)
+	r.add(start.Add(30*time.Second), 3)
+	r.add(start.Add(time.Minute), 4)
+	r.add(start.Add(3*time.Minute), 8)
+
+	assert.Equal(t, []api.MetricsHistoryPoint{
+		{Time: start, Value: 2},
+		{Time: start.Add(time.Minute), Value: 4},
+		{Time: start.Add(3 * time.Minute), Value: 8},
+	}, r.points(start, start.Add(time.Hour), 60))
+
+	// Downsampling weighs each step by its number of values.
+	assert.Equal(t, []api.MetricsHistoryPoint{
+		{Time: start, Value: 8.0 / 3},
+		{Time: start.Add(2 * time.Minute), Value: 8},
+	}, r.points(start, start.Add(time.Hour), 120))
+
+	// Old values are overwritten and skipped steps are cleared.
+	r.add(start.Add(5*time.Minute), 10)
+	assert.False(t, r.covers(start.Add(time.Minute)))
+	assert.True(t, r.covers(start.Add(2*time.Minute)))
+	assert.Equal(t, []api.MetricsHistoryPoint{
+		{Time: start.Add(3 * time.Minute), Value: 8},
+		{Time: start.Add(5 * time.Minute), Value: 10},
+	}, r.points(start, start.Add(time.Hour), 60))
+
+	// Values older than the ring are dropped.
+	r.add(start, 100)
+	assert.Len(t, r.points(start, start.Add(time.Hour), 60), 2)
+}
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	h := NewHistory()
+
+	for i := range 5 {
+		now := start.Add(time.Duration(i) * time.Minute)
+		h.Record("default", "c1", now, map[string]float64{HistoryCPU: float64(i * 30), HistoryMemory: 1024})
+		h.Record("", "", now, map[string]float64{HistoryMemory: 2048})
+	}
+
+	onlyInstances := func(projectName string, instanceName string, metric string) bool { return instanceName != "" }
+	series := h.Query(start, start.Add(time.Hour), time.Minute, onlyInstances)
+	require.Len(t, series, 2)
+
+	// Counters are recorded as rates, starting from the second sample.
+	assert.Equal(t, HistoryCPU, series[0].Metric)
+	assert.Equal(t, int64(60), series[0].Step)
+	require.Len(t, series[0].Points, 4)
+	assert.Equal(t, 0.5, series[0].Points[0].Value)
+
+	assert.Equal(t, HistoryMemory, series[1].Metric)
+	assert.Len(t, series[1].Points, 5)
+
+	// Persistence.
+	path := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, h.Save(path))
+
+	loaded := NewHistory()
+	require.NoError(t, loaded.Load(path))
+	assert.Equal(t, h.Query(start, start.Add(time.Hour), time.Minute, nil), loaded.Query(start, start.Add(time.Hour), time.Minute, nil))
+
+	// Series which are no longer updated expire.
+	loaded.Expire(start.Add(time.Hour))
+	assert.Empty(t, loaded.Query(start, start.Add(time.Hour), time.Minute, nil))
+}
+
+func TestHistoryValues(t *testing.T) {
+	m := NewMetricSet(map[string]string{"project": "default", "name": "c1"})
+	m.AddSamples(CPUSecondsTotal, Sample{Value: 10, Labels: map[string]string{"mode": "user"}}, Sample{Value: 5, Labels: map[string]string{"mode": "system"}}, Sample{Value: 100, Labels: map[string]string{"mode": "idle"}})
+	m.AddSamples(MemoryMemTotalBytes, Sample{Value: 4096})
+	m.AddSamples(MemoryMemAvailableBytes, Sample{Value: 1024})
+	m.AddSamples(NetworkReceiveBytesTotal, Sample{Value: 10, Labels: map[string]string{"device": "eth0"}}, Sample{Value: 20, Labels: map[string]string{"device": "lo"}})
+
+	assert.Equal(t, map[string]float64{
+		HistoryCPU:            15,
+		HistoryMemory:         3072,
+		HistoryNetworkReceive: 10,
+	}, HistoryValues(m))
+}
@@ -19,6 +19,7 @@ var metricTypeGauges = []MetricType{
 	ProjectLimit,
 	ProjectResourcesTotal,
 	ProjectUsage,
+	StoragePoolHealthy,
 }
 
 // NewMetricSet returns a new MetricSet.
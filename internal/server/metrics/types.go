@@ -113,6 +113,14 @@ const (
 	StoragePoolUsedBytes
 	// StoragePoolSizeBytes represents the total space in bytes on a storage pool.
 	StoragePoolSizeBytes
+	// StoragePoolHealthy represents whether a storage pool is healthy.
+	StoragePoolHealthy
+	// StoragePoolErrorsTotal represents the number of errors recorded on a device of a storage pool.
+	StoragePoolErrorsTotal
+	// StoragePoolMetaUsedBytes represents the used metadata space in bytes on a thin storage pool.
+	StoragePoolMetaUsedBytes
+	// StoragePoolMetaSizeBytes represents the total metadata space in bytes on a thin storage pool.
+	StoragePoolMetaSizeBytes
 	// GoGoroutines represents the number of goroutines that currently exist.
 	GoGoroutines
 	// GoAllocBytes represents the number of bytes allocated and still in use.
@@ -231,6 +239,10 @@ var MetricNames = map[MetricType]string{
 	ProjectUsage:                "incus_project_usage",
 	StoragePoolUsedBytes:        "incus_storage_pool_used_bytes",
 	StoragePoolSizeBytes:        "incus_storage_pool_size_bytes",
+	StoragePoolHealthy:          "incus_storage_pool_healthy",
+	StoragePoolErrorsTotal:      "incus_storage_pool_device_errors_total",
+	StoragePoolMetaUsedBytes:    "incus_storage_pool_metadata_used_bytes",
+	StoragePoolMetaSizeBytes:    "incus_storage_pool_metadata_size_bytes",
 	TimeSeconds:                 "incus_time_seconds",
 	UptimeSeconds:               "incus_uptime_seconds",
 	WarningsTotal:               "incus_warnings_total",
@@ -306,6 +318,10 @@ var MetricHeaders = map[MetricType]string{
 	ProjectUsage:                "# HELP incus_project_usage Current project resource usage.",
 	StoragePoolUsedBytes:        "# HELP incus_storage_pool_used_bytes The used space in bytes on a storage pool.",
 	StoragePoolSizeBytes:        "# HELP incus_storage_pool_size_bytes The total space in bytes on a storage pool.",
+	StoragePoolHealthy:          "# HELP incus_storage_pool_healthy Whether the storage pool is healthy.",
+	StoragePoolErrorsTotal:      "# HELP incus_storage_pool_device_errors_total The number of errors recorded on a device of a storage pool.",
+	StoragePoolMetaUsedBytes:    "# HELP incus_storage_pool_metadata_used_bytes The used metadata space in bytes on a thin storage pool.",
+	StoragePoolMetaSizeBytes:    "# HELP incus_storage_pool_metadata_size_bytes The total metadata space in bytes on a thin storage pool.",
 	TimeSeconds:                 "# HELP incus_time_seconds The current unix epoch.",
 	UptimeSeconds:               "# HELP incus_uptime_seconds The daemon uptime in seconds.",
 	WarningsTotal:               "# HELP incus_warnings_total The number of active warnings.",
@@ -0,0 +1,557 @@
+// Package nbd implements a minimal client for the Network Block Device protocol.
+//
+// Only the parts of the protocol needed to read an export and query its block status (such as the dirty
+// bitmaps exported by QEMU) are implemented.
+package nbd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Handshake magic values.
+const (
+	magicInit        = 0x4e42444d41474943 // "NBDMAGIC"
+	magicOption      = 0x49484156454f5054 // "IHAVEOPT"
+	magicOptionReply = 0x0003e889045565a9
+)
+
+// Handshake flags.
+const (
+	flagFixedNewstyle = 1 << 0
+	flagNoZeroes      = 1 << 1
+)
+
+// Options.
+const (
+	optGo              = 7
+	optStructuredReply = 8
+	optSetMetaContext  = 10
+)
+
+// Option replies.
+const (
+	repAck         = 1
+	repInfo        = 3
+	repMetaContext = 4
+	repFlagError   = 1 << 31
+)
+
+// Export information types.
+const (
+	infoExport = 0
+)
+
+// Transmission magic values.
+const (
+	magicRequest         = 0x25609513
+	magicSimpleReply     = 0x67446698
+	magicStructuredReply = 0x668e33ef
+)
+
+// Commands.
+const (
+	cmdRead        = 0
+	cmdDisconnect  = 2
+	cmdBlockStatus = 7
+)
+
+// Structured reply chunk types and flags.
+const (
+	replyFlagDone        = 1 << 0
+	replyTypeNone        = 0
+	replyTypeOffsetData  = 1
+	replyTypeOffsetHole  = 2
+	replyTypeBlockStatus = 5
+	replyTypeErrorBit    = 1 << 15
+)
+
+// maxReadSize is the largest read request sent to the server.
+const maxReadSize = 32 * 1024 * 1024
+
+// maxBlockStatusSize is the largest range queried in a single block status request.
+const maxBlockStatusSize = 1024 * 1024 * 1024
+
+// StateDirty is the flag set on the extents of a dirty bitmap context which changed since the bitmap was created.
+const StateDirty = 1 << 0
+
+// DirtyBitmapContext returns the name of the metadata context exposing the given dirty bitmap.
+func DirtyBitmapContext(bitmap string) string {
+	return "qemu:dirty-bitmap:" + bitmap
+}
+
+// Extent represents a range of the export along with its status flags in a metadata context.
+type Extent struct {
+	Offset int64
+	Length int64
+	Flags  uint32
+}
+
+// Client is an NBD client connected to a single export.
+type Client struct {
+	conn     net.Conn
+	size     int64
+	contexts map[string]uint32
+
+	mu     sync.Mutex
+	cookie uint64
+}
+
+// Connect performs the NBD handshake on the connection, selecting the named export and negotiating the
+// requested metadata contexts (such as "qemu:dirty-bitmap:NAME"). Contexts unknown to the server are
+// silently left out, use HasContext to check for them.
+func Connect(conn net.Conn, exportName string, metaContexts []string) (*Client, error) {
+	c := &Client{
+		conn:     conn,
+		contexts: map[string]uint32{},
+	}
+
+	err := c.handshake(exportName, metaContexts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed NBD handshake: %w", err)
+	}
+
+	return c, nil
+}
+
+// Size returns the size of the export in bytes.
+func (c *Client) Size() int64 {
+	return c.size
+}
+
+// HasContext returns whether the metadata context was negotiated with the server.
+func (c *Client) HasContext(name string) bool {
+	_, ok := c.contexts[name]
+	return ok
+}
+
+// Close disconnects from the server and closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	_ = c.writeRequest(cmdDisconnect, 0, 0)
+	c.mu.Unlock()
+
+	return c.conn.Close()
+}
+
+// ReadAt reads len(p) bytes of the export starting at offset off.
+func (c *Client) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > c.size {
+		return 0, fmt.Errorf("Read of %d bytes at offset %d is beyond the end of the export", len(p), off)
+	}
+
+	done := 0
+	for done < len(p) {
+		chunk := min(len(p)-done, maxReadSize)
+
+		err := c.read(p[done:done+chunk], off+int64(done))
+		if err != nil {
+			return done, err
+		}
+
+		done += chunk
+	}
+
+	return done, nil
+}
+
+// BlockStatus returns the extents covering (part of) the requested range in the given metadata context.
+// The server may describe less than the requested range, in which case the caller should query again from
+// the end of the last returned extent.
+func (c *Client) BlockStatus(metaContext string, offset int64, length uint32) ([]Extent, error) {
+	contextID, ok := c.contexts[metaContext]
+	if !ok {
+		return nil, fmt.Errorf("Metadata context %q wasn't negotiated", metaContext)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.writeRequest(cmdBlockStatus, offset, length)
+	if err != nil {
+		return nil, err
+	}
+
+	extents := []Extent{}
+	err = c.readReplies(func(chunkType uint16, payload []byte) error {
+		if chunkType != replyTypeBlockStatus {
+			return fmt.Errorf("Unexpected reply chunk type %d to block status request", chunkType)
+		}
+
+		if len(payload) < 4 || (len(payload)-4)%8 != 0 {
+			return errors.New("Invalid block status reply")
+		}
+
+		if binary.BigEndian.Uint32(payload) != contextID {
+			return nil
+		}
+
+		extentOffset := offset
+		for i := 4; i < len(payload); i += 8 {
+			extentLength := int64(binary.BigEndian.Uint32(payload[i:]))
+			flags := binary.BigEndian.Uint32(payload[i+4:])
+
+			// Don't report anything past the requested range.
+			extentLength = min(extentLength, offset+int64(length)-extentOffset)
+			if extentLength <= 0 {
+				break
+			}
+
+			extents = append(extents, Extent{Offset: extentOffset, Length: extentLength, Flags: flags})
+			extentOffset += extentLength
+		}
+
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return extents, nil
+}
+
+// DirtyExtents returns the extents of the whole export flagged as dirty in the given metadata context.
+// Adjacent dirty extents are merged together.
+func (c *Client) DirtyExtents(metaContext string) ([]Extent, error) {
+	dirty := []Extent{}
+
+	offset := int64(0)
+	for offset < c.size {
+		extents, err := c.BlockStatus(metaContext, offset, uint32(min(c.size-offset, maxBlockStatusSize)))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(extents) == 0 {
+			return nil, fmt.Errorf("No block status returned for offset %d", offset)
+		}
+
+		for _, extent := range extents {
+			if extent.Flags&StateDirty == 0 {
+				continue
+			}
+
+			last := len(dirty) - 1
+			if last >= 0 && dirty[last].Offset+dirty[last].Length == extent.Offset {
+				dirty[last].Length += extent.Length
+				continue
+			}
+
+			dirty = append(dirty, Extent{Offset: extent.Offset, Length: extent.Length, Flags: StateDirty})
+		}
+
+		last := extents[len(extents)-1]
+		offset = last.Offset + last.Length
+	}
+
+	return dirty, nil
+}
+
+// read reads a single chunk of the export.
+func (c *Client) read(p []byte, offset int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.writeRequest(cmdRead, offset, uint32(len(p)))
+	if err != nil {
+		return err
+	}
+
+	return c.readReplies(func(chunkType uint16, payload []byte) error {
+		switch chunkType {
+		case replyTypeOffsetData:
+			if len(payload) < 8 {
+				return errors.New("Invalid read reply")
+			}
+
+			start := int64(binary.BigEndian.Uint64(payload)) - offset
+			data := payload[8:]
+			if start < 0 || start+int64(len(data)) > int64(len(p)) {
+				return errors.New("Read reply is outside of the requested range")
+			}
+
+			copy(p[start:], data)
+		case replyTypeOffsetHole:
+			if len(payload) != 12 {
+				return errors.New("Invalid read reply")
+			}
+
+			start := int64(binary.BigEndian.Uint64(payload)) - offset
+			holeLength := int64(binary.BigEndian.Uint32(payload[8:]))
+			if start < 0 || start+holeLength > int64(len(p)) {
+				return errors.New("Read reply is outside of the requested range")
+			}
+
+			clear(p[start : start+holeLength])
+		default:
+			return fmt.Errorf("Unexpected reply chunk type %d to read request", chunkType)
+		}
+
+		return nil
+	}, p)
+}
+
+// writeRequest sends a transmission request to the server.
+func (c *Client) writeRequest(command uint16, offset int64, length uint32) error {
+	c.cookie++
+
+	buf := make([]byte, 28)
+	binary.BigEndian.PutUint32(buf[0:], magicRequest)
+	binary.BigEndian.PutUint16(buf[4:], 0)
+	binary.BigEndian.PutUint16(buf[6:], command)
+	binary.BigEndian.PutUint64(buf[8:], c.cookie)
+	binary.BigEndian.PutUint64(buf[16:], uint64(offset))
+	binary.BigEndian.PutUint32(buf[24:], length)
+
+	_, err := c.conn.Write(buf)
+	if err != nil {
+		return fmt.Errorf("Failed sending NBD request: %w", err)
+	}
+
+	return nil
+}
+
+// readReplies reads the replies to the last request until the final one, passing the payload of each
+// structured reply chunk to the handler. A simple reply is read into simpleData, if any.
+func (c *Client) readReplies(handler func(chunkType uint16, payload []byte) error, simpleData []byte) error {
+	for {
+		var magic uint32
+		err := binary.Read(c.conn, binary.BigEndian, &magic)
+		if err != nil {
+			return fmt.Errorf("Failed reading NBD reply: %w", err)
+		}
+
+		switch magic {
+		case magicSimpleReply:
+			var reply struct {
+				Error  uint32
+				Cookie uint64
+			}
+
+			err = binary.Read(c.conn, binary.BigEndian, &reply)
+			if err != nil {
+				return fmt.Errorf("Failed reading NBD reply: %w", err)
+			}
+
+			if reply.Cookie != c.cookie {
+				return fmt.Errorf("Unexpected NBD reply cookie %d", reply.Cookie)
+			}
+
+			if reply.Error != 0 {
+				return fmt.Errorf("NBD request failed with error %d", reply.Error)
+			}
+
+			if simpleData != nil {
+				_, err = io.ReadFull(c.conn, simpleData)
+				if err != nil {
+					return fmt.Errorf("Failed reading NBD reply: %w", err)
+				}
+			}
+
+			return nil
+
+		case magicStructuredReply:
+			var reply struct {
+				Flags  uint16
+				Type   uint16
+				Cookie uint64
+				Length uint32
+			}
+
+			err = binary.Read(c.conn, binary.BigEndian, &reply)
+			if err != nil {
+				return fmt.Errorf("Failed reading NBD reply: %w", err)
+			}
+
+			if reply.Cookie != c.cookie {
+				return fmt.Errorf("Unexpected NBD reply cookie %d", reply.Cookie)
+			}
+
+			payload := make([]byte, reply.Length)
+			_, err = io.ReadFull(c.conn, payload)
+			if err != nil {
+				return fmt.Errorf("Failed reading NBD reply: %w", err)
+			}
+
+			if reply.Type&replyTypeErrorBit != 0 {
+				return replyError(payload)
+			}
+
+			if reply.Type != replyTypeNone {
+				err = handler(reply.Type, payload)
+				if err != nil {
+					return err
+				}
+			}
+
+			if reply.Flags&replyFlagDone != 0 {
+				return nil
+			}
+
+		default:
+			return fmt.Errorf("Invalid NBD reply magic %#x", magic)
+		}
+	}
+}
+
+// replyError returns the error carried by an error reply chunk.
+func replyError(payload []byte) error {
+	if len(payload) < 6 {
+		return errors.New("NBD request failed")
+	}
+
+	code := binary.BigEndian.Uint32(payload)
+	msgLength := int(binary.BigEndian.Uint16(payload[4:]))
+	if msgLength > 0 && 6+msgLength <= len(payload) {
+		return fmt.Errorf("NBD request failed with error %d: %s", code, payload[6:6+msgLength])
+	}
+
+	return fmt.Errorf("NBD request failed with error %d", code)
+}
+
+// handshake performs the fixed newstyle negotiation.
+func (c *Client) handshake(exportName string, metaContexts []string) error {
+	var greeting struct {
+		Magic       uint64
+		OptionMagic uint64
+		Flags       uint16
+	}
+
+	err := binary.Read(c.conn, binary.BigEndian, &greeting)
+	if err != nil {
+		return err
+	}
+
+	if greeting.Magic != magicInit || greeting.OptionMagic != magicOption {
+		return errors.New("Server doesn't speak the newstyle protocol")
+	}
+
+	if greeting.Flags&flagFixedNewstyle == 0 {
+		return errors.New("Server doesn't support the fixed newstyle protocol")
+	}
+
+	clientFlags := uint32(flagFixedNewstyle)
+	if greeting.Flags&flagNoZeroes != 0 {
+		clientFlags |= flagNoZeroes
+	}
+
+	err = binary.Write(c.conn, binary.BigEndian, clientFlags)
+	if err != nil {
+		return err
+	}
+
+	// Block status requires structured replies.
+	if len(metaContexts) > 0 {
+		err = c.option(optStructuredReply, nil, nil)
+		if err != nil {
+			return fmt.Errorf("Failed enabling structured replies: %w", err)
+		}
+
+		data := binary.BigEndian.AppendUint32(nil, uint32(len(exportName)))
+		data = append(data, exportName...)
+		data = binary.BigEndian.AppendUint32(data, uint32(len(metaContexts)))
+		for _, name := range metaContexts {
+			data = binary.BigEndian.AppendUint32(data, uint32(len(name)))
+			data = append(data, name...)
+		}
+
+		err = c.option(optSetMetaContext, data, func(replyType uint32, payload []byte) error {
+			if replyType != repMetaContext || len(payload) < 4 {
+				return nil
+			}
+
+			c.contexts[string(payload[4:])] = binary.BigEndian.Uint32(payload)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Failed setting metadata contexts: %w", err)
+		}
+	}
+
+	data := binary.BigEndian.AppendUint32(nil, uint32(len(exportName)))
+	data = append(data, exportName...)
+	data = binary.BigEndian.AppendUint16(data, 0)
+
+	err = c.option(optGo, data, func(replyType uint32, payload []byte) error {
+		if replyType != repInfo || len(payload) < 2 {
+			return nil
+		}
+
+		if binary.BigEndian.Uint16(payload) == infoExport {
+			if len(payload) < 12 {
+				return errors.New("Invalid export information")
+			}
+
+			c.size = int64(binary.BigEndian.Uint64(payload[2:]))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed selecting export %q: %w", exportName, err)
+	}
+
+	return nil
+}
+
+// option sends an option request and reads its replies until the final acknowledgement, passing the other
+// replies to the handler.
+func (c *Client) option(option uint32, data []byte, handler func(replyType uint32, payload []byte) error) error {
+	buf := binary.BigEndian.AppendUint64(nil, magicOption)
+	buf = binary.BigEndian.AppendUint32(buf, option)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+	buf = append(buf, data...)
+
+	_, err := c.conn.Write(buf)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var reply struct {
+			Magic  uint64
+			Option uint32
+			Type   uint32
+			Length uint32
+		}
+
+		err = binary.Read(c.conn, binary.BigEndian, &reply)
+		if err != nil {
+			return err
+		}
+
+		if reply.Magic != magicOptionReply || reply.Option != option {
+			return errors.New("Invalid option reply")
+		}
+
+		payload := make([]byte, reply.Length)
+		_, err = io.ReadFull(c.conn, payload)
+		if err != nil {
+			return err
+		}
+
+		if reply.Type&repFlagError != 0 {
+			if len(payload) > 0 {
+				return fmt.Errorf("Server returned error %d: %s", reply.Type&^repFlagError, payload)
+			}
+
+			return fmt.Errorf("Server returned error %d", reply.Type&^repFlagError)
+		}
+
+		if reply.Type == repAck {
+			return nil
+		}
+
+		if handler != nil {
+			err = handler(reply.Type, payload)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
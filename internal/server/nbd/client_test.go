@@ -0,0 +1,172 @@
+package nbd
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer is a minimal NBD server exposing a single export with a dirty bitmap.
+type testServer struct {
+	conn  net.Conn
+	data  []byte
+	dirty []Extent
+}
+
+func (s *testServer) optionReply(option uint32, replyType uint32, payload []byte) {
+	buf := binary.BigEndian.AppendUint64(nil, magicOptionReply)
+	buf = binary.BigEndian.AppendUint32(buf, option)
+	buf = binary.BigEndian.AppendUint32(buf, replyType)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	_, _ = s.conn.Write(append(buf, payload...))
+}
+
+func (s *testServer) chunk(flags uint16, chunkType uint16, cookie uint64, payload []byte) {
+	buf := binary.BigEndian.AppendUint32(nil, magicStructuredReply)
+	buf = binary.BigEndian.AppendUint16(buf, flags)
+	buf = binary.BigEndian.AppendUint16(buf, chunkType)
+	buf = binary.BigEndian.AppendUint64(buf, cookie)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	_, _ = s.conn.Write(append(buf, payload...))
+}
+
+func (s *testServer) serve() {
+	greeting := binary.BigEndian.AppendUint64(nil, magicInit)
+	greeting = binary.BigEndian.AppendUint64(greeting, magicOption)
+	greeting = binary.BigEndian.AppendUint16(greeting, flagFixedNewstyle|flagNoZeroes)
+	_, _ = s.conn.Write(greeting)
+
+	var clientFlags uint32
+	_ = binary.Read(s.conn, binary.BigEndian, &clientFlags)
+
+	// Options.
+	for {
+		var req struct {
+			Magic  uint64
+			Option uint32
+			Length uint32
+		}
+
+		err := binary.Read(s.conn, binary.BigEndian, &req)
+		if err != nil {
+			return
+		}
+
+		data := make([]byte, req.Length)
+		_, _ = io.ReadFull(s.conn, data)
+
+		switch req.Option {
+		case optSetMetaContext:
+			nameLength := binary.BigEndian.Uint32(data)
+			pos := 4 + nameLength + 4
+			for pos < uint32(len(data)) {
+				queryLength := binary.BigEndian.Uint32(data[pos:])
+				query := string(data[pos+4 : pos+4+queryLength])
+				pos += 4 + queryLength
+
+				if query == "qemu:dirty-bitmap:bitmap0" {
+					s.optionReply(req.Option, repMetaContext, append(binary.BigEndian.AppendUint32(nil, 1), query...))
+				}
+			}
+
+		case optGo:
+			info := binary.BigEndian.AppendUint16(nil, infoExport)
+			info = binary.BigEndian.AppendUint64(info, uint64(len(s.data)))
+			info = binary.BigEndian.AppendUint16(info, 0)
+			s.optionReply(req.Option, repInfo, info)
+		}
+
+		s.optionReply(req.Option, repAck, nil)
+
+		if req.Option == optGo {
+			break
+		}
+	}
+
+	// Transmission.
+	for {
+		var req struct {
+			Magic   uint32
+			Flags   uint16
+			Command uint16
+			Cookie  uint64
+			Offset  uint64
+			Length  uint32
+		}
+
+		err := binary.Read(s.conn, binary.BigEndian, &req)
+		if err != nil || req.Command == cmdDisconnect {
+			return
+		}
+
+		switch req.Command {
+		case cmdRead:
+			// Send the first half as data and the second half as a hole.
+			half := uint64(req.Length / 2)
+			payload := binary.BigEndian.AppendUint64(nil, req.Offset)
+			s.chunk(0, replyTypeOffsetData, req.Cookie, append(payload, s.data[req.Offset:req.Offset+half]...))
+
+			payload = binary.BigEndian.AppendUint64(nil, req.Offset+half)
+			payload = binary.BigEndian.AppendUint32(payload, req.Length-uint32(half))
+			s.chunk(replyFlagDone, replyTypeOffsetHole, req.Cookie, payload)
+
+		case cmdBlockStatus:
+			payload := binary.BigEndian.AppendUint32(nil, 1)
+			for _, extent := range s.dirty {
+				payload = binary.BigEndian.AppendUint32(payload, uint32(extent.Length))
+				payload = binary.BigEndian.AppendUint32(payload, extent.Flags)
+			}
+
+			s.chunk(replyFlagDone, replyTypeBlockStatus, req.Cookie, payload)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	server := &testServer{
+		conn: serverConn,
+		data: []byte("0123456789abcdef"),
+		dirty: []Extent{
+			{Length: 4, Flags: 0},
+			{Length: 6, Flags: 1},
+			{Length: 2, Flags: 1},
+			{Length: 8, Flags: 0},
+		},
+	}
+
+	go server.serve()
+
+	c, err := Connect(clientConn, "", []string{"qemu:dirty-bitmap:bitmap0", "qemu:dirty-bitmap:missing"})
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+
+	assert.Equal(t, int64(16), c.Size())
+	assert.True(t, c.HasContext("qemu:dirty-bitmap:bitmap0"))
+	assert.False(t, c.HasContext("qemu:dirty-bitmap:missing"))
+
+	buf := []byte("xxxxxxxx")
+	n, err := c.ReadAt(buf, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 8, n)
+	assert.Equal(t, []byte("4567\x00\x00\x00\x00"), buf)
+
+	_, err = c.ReadAt(buf, 12)
+	assert.Error(t, err)
+
+	// The last extent is truncated to the requested range.
+	extents, err := c.BlockStatus("qemu:dirty-bitmap:bitmap0", 0, 16)
+	require.NoError(t, err)
+	assert.Equal(t, []Extent{{Offset: 0, Length: 4, Flags: 0}, {Offset: 4, Length: 6, Flags: 1}, {Offset: 10, Length: 2, Flags: 1}, {Offset: 12, Length: 4, Flags: 0}}, extents)
+
+	_, err = c.BlockStatus("qemu:dirty-bitmap:missing", 0, 16)
+	assert.Error(t, err)
+
+	extents, err = c.DirtyExtents(DirtyBitmapContext("bitmap0"))
+	require.NoError(t, err)
+	assert.Equal(t, []Extent{{Offset: 4, Length: 8, Flags: StateDirty}}, extents)
+}
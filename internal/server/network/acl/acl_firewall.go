@@ -3,6 +3,8 @@ package acl
 import (
 	"context"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
@@ -37,11 +39,26 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 				continue
 			}
 
+			source, err := firewallPeerSubjects(s, aclProjectName, rule.Source)
+			if err != nil {
+				return err
+			}
+
+			destination, err := firewallPeerSubjects(s, aclProjectName, rule.Destination)
+			if err != nil {
+				return err
+			}
+
+			// Skip rules whose subjects only referenced network peers without any subnet.
+			if (source == "" && rule.Source != "") || (destination == "" && rule.Destination != "") {
+				continue
+			}
+
 			firewallACLRule := firewallDrivers.ACLRule{
 				Direction:       direction,
 				Action:          rule.Action,
-				Source:          rule.Source,
-				Destination:     rule.Destination,
+				Source:          source,
+				Destination:     destination,
 				Protocol:        rule.Protocol,
 				SourcePort:      rule.SourcePort,
 				DestinationPort: rule.DestinationPort,
@@ -127,6 +144,70 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 	return rules, nil
 }
 
+// firewallPeerSubjects replaces the network peer subjects ("@<network>/<peer>") of a rule source or destination
+// with the subnets of the peer's target network, as the firewall only matches on addresses.
+// Peers which aren't linked to their target network yet don't match anything.
+func firewallPeerSubjects(s *state.State, aclProjectName string, subjects string) (string, error) {
+	if !strings.Contains(subjects, "@") {
+		return subjects, nil
+	}
+
+	criteria := []string{}
+	for _, subject := range util.SplitNTrimSpace(subjects, ",", -1, false) {
+		after, ok := strings.CutPrefix(subject, "@")
+		peerParts := strings.SplitN(after, "/", 2)
+		if !ok || len(peerParts) != 2 {
+			criteria = append(criteria, subject)
+			continue
+		}
+
+		var targetNet *api.Network
+
+		err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			networkID, err := tx.GetNetworkID(ctx, aclProjectName, peerParts[0])
+			if err != nil {
+				return fmt.Errorf("Failed loading network %q: %w", peerParts[0], err)
+			}
+
+			dbPeer, err := dbCluster.GetNetworkPeer(ctx, tx.Tx(), networkID, peerParts[1])
+			if err != nil {
+				return fmt.Errorf("Failed loading network peer %q: %w", subject, err)
+			}
+
+			peer, err := dbPeer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			if peer.Status != api.NetworkStatusCreated {
+				return nil
+			}
+
+			_, targetNet, _, err = tx.GetNetworkInAnyState(ctx, peer.TargetProject, peer.TargetNetwork)
+
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if targetNet == nil {
+			continue
+		}
+
+		for _, key := range []string{"ipv4.address", "ipv6.address"} {
+			_, subnet, err := net.ParseCIDR(targetNet.Config[key])
+			if err != nil {
+				continue
+			}
+
+			criteria = append(criteria, subnet.String())
+		}
+	}
+
+	return strings.Join(criteria, ","), nil
+}
+
 // firewallACLDefaults returns the action and logging mode to use for the specified direction's default rule.
 // If the security.acls.default.{in,e}gress.action or security.acls.default.{in,e}gress.logged settings are not
 // specified in the network config, then it returns "reject" and false respectively.
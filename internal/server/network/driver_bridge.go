@@ -19,6 +19,7 @@ import (
 	"github.com/mdlayher/netx/eui64"
 
 	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/iprange"
 	"github.com/lxc/incus/v7/internal/server/apparmor"
 	"github.com/lxc/incus/v7/internal/server/cluster"
 	"github.com/lxc/incus/v7/internal/server/cluster/request"
@@ -61,10 +62,30 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.LoadBalancers = true
+	info.Peering = true
+	info.Reservations = true
 
 	return info
 }
 
+// State returns the current state of the network.
+func (n *bridge) State() (*api.NetworkState, error) {
+	state, err := n.common.State()
+	if err != nil {
+		return nil, err
+	}
+
+	if util.IsTrue(n.config["wireguard.enabled"]) && InterfaceExists(n.wireguardInterface()) {
+		state.WireGuard, err = n.wireguardState()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
 // checkClusterWideMACSafe returns whether it is safe to use the same MAC address for the bridge interface on all
 // cluster nodes. It is not suitable to use a static MAC address when "bridge.external_interfaces" is non-empty and
 // the bridge interface has no IPv4 or IPv6 address set. This is because in a clustered environment the same bridge
@@ -87,6 +108,10 @@ func (n *bridge) checkClusterWideMACSafe(config map[string]string) error {
 		}
 	}
 
+	if util.IsTrue(config["wireguard.enabled"]) {
+		return errors.New(`Cannot use static "bridge.hwaddr" MAC address when bridge is extended over WireGuard`)
+	}
+
 	// If using a generated IPv6 address, we need a unique MAC.
 	if config["ipv6.address"] != "none" && validate.IsNetworkV6(config["ipv6.address"]) == nil {
 		return errors.New(`Cannot use static "bridge.hwaddr" MAC address when bridge uses a host-specific IPv6 address`)
@@ -581,6 +606,15 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: Additional dnsmasq configuration to append to the configuration file
 		"raw.dnsmasq": validate.IsAny,
 
+		// gendoc:generate(entity=network_bridge, group=common, key=flow_export.ipfix.targets)
+		//
+		// ---
+		//  type: string
+		//  condition: -
+		//  default: -
+		//  shortdesc: Comma-separated list of IPFIX collectors (`<IP>:<port>`) to export the TCP and UDP flows routed through the bridge to
+		"flow_export.ipfix.targets": validate.Optional(validate.IsListOf(validate.IsListenAddress(false, false, true))),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=security.acls)
 		//
 		// ---
@@ -624,6 +658,42 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  default: `false`
 		//  shortdesc: Whether to log egress traffic that doesn't match any ACL rule
 		"security.acls.default.egress.logged": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=wireguard.enabled)
+		//
+		// ---
+		//  type: bool
+		//  condition: -
+		//  default: `false`
+		//  shortdesc: Whether to extend the bridge across cluster members over an encrypted WireGuard mesh
+		"wireguard.enabled": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=wireguard.address)
+		//
+		// ---
+		//  type: string
+		//  condition: `wireguard.enabled`
+		//  default: cluster address
+		//  shortdesc: Address other cluster members reach the WireGuard endpoint of this member on
+		"wireguard.address": validate.Optional(validate.IsNetworkAddress),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=wireguard.port)
+		//
+		// ---
+		//  type: integer
+		//  condition: `wireguard.enabled`
+		//  default: `51820`
+		//  shortdesc: UDP port used by WireGuard
+		"wireguard.port": validate.Optional(validate.IsNetworkPort),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=wireguard.key_lifetime)
+		//
+		// ---
+		//  type: integer
+		//  condition: `wireguard.enabled`
+		//  default: `30`
+		//  shortdesc: Number of days the WireGuard key of a cluster member is used before being replaced
+		"wireguard.key_lifetime": validate.Optional(validate.IsInRange(1, 3650)),
 	}
 
 	// Add dynamic validation rules.
@@ -716,6 +786,15 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 				//  default: `1`
 				//  shortdesc: Specific TTL to use for multicast routing topologies
 				rules[k] = validate.Optional(validate.IsUint8)
+			case "evpn":
+				// gendoc:generate(entity=network_bridge, group=common, key=tunnel.NAME.evpn)
+				//
+				// ---
+				//  type: bool
+				//  condition: `vxlan`
+				//  default: `false`
+				//  shortdesc: Whether to use BGP EVPN to learn the remote MAC addresses and VTEPs of the `vxlan` tunnel
+				rules[k] = validate.Optional(validate.IsBool)
 			}
 		}
 	}
@@ -811,6 +890,40 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		}
 	}
 
+	// Check the EVPN tunnels are complete.
+	for k, v := range config {
+		if !strings.HasPrefix(k, "tunnel.") || !strings.HasSuffix(k, ".evpn") || util.IsFalseOrEmpty(v) {
+			continue
+		}
+
+		tunnelKey := func(key string) string {
+			return fmt.Sprintf("%s.%s", strings.TrimSuffix(k, ".evpn"), key)
+		}
+
+		if config[tunnelKey("protocol")] != "vxlan" {
+			return fmt.Errorf("%q can only be used with the \"vxlan\" protocol", tunnelKey("evpn"))
+		}
+
+		if config[tunnelKey("local")] == "" {
+			return fmt.Errorf("%q is required when %q is enabled", tunnelKey("local"), tunnelKey("evpn"))
+		}
+
+		if config[tunnelKey("remote")] != "" || config[tunnelKey("group")] != "" {
+			return fmt.Errorf("%q and %q cannot be used when %q is enabled", tunnelKey("remote"), tunnelKey("group"), tunnelKey("evpn"))
+		}
+	}
+
+	// Check the WireGuard mesh can be set up.
+	if util.IsTrue(config["wireguard.enabled"]) {
+		if config["bridge.driver"] == "openvswitch" {
+			return errors.New(`"wireguard.enabled" cannot be used with the "openvswitch" bridge driver`)
+		}
+
+		if len(n.name) > wireguardMaxNameLength {
+			return fmt.Errorf("Network name too long for WireGuard interfaces (%d characters maximum)", wireguardMaxNameLength)
+		}
+	}
+
 	// Check using same MAC address on every cluster node is safe.
 	if config["bridge.hwaddr"] != "" {
 		err = n.checkClusterWideMACSafe(config)
@@ -1047,6 +1160,8 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 
 		bridge.MTU = uint32(mtuInt)
+	} else if util.IsTrue(n.config["wireguard.enabled"]) {
+		bridge.MTU = wireguardBridgeMTU
 	} else if len(tunnels) > 0 {
 		bridge.MTU = 1400
 	}
@@ -1489,13 +1604,24 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 				expiry = n.config["ipv4.dhcp.expiry"]
 			}
 
-			if n.config["ipv4.dhcp.ranges"] != "" {
-				for _, dhcpRange := range strings.Split(n.config["ipv4.dhcp.ranges"], ",") {
-					dhcpRange = strings.TrimSpace(dhcpRange)
-					dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,%s", strings.ReplaceAll(dhcpRange, "-", ","), expiry)}...)
-				}
-			} else {
-				dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,%s,%s", dhcpalloc.GetIP(subnet, 2).String(), dhcpalloc.GetIP(subnet, -2).String(), expiry)}...)
+			dhcpRanges := n.DHCPv4Ranges()
+			if len(dhcpRanges) == 0 {
+				dhcpRanges = []iprange.Range{{Start: dhcpalloc.GetIP(subnet, 2), End: dhcpalloc.GetIP(subnet, -2)}}
+			}
+
+			// Keep reserved addresses out of the dynamic pool.
+			dhcpRanges, err = n.dhcpRangesExcludingReservations(dhcpRanges)
+			if err != nil {
+				return err
+			}
+
+			for _, dhcpRange := range dhcpRanges {
+				dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,%s,%s", dhcpRange.Start.String(), dhcpRange.End.String(), expiry)}...)
+			}
+
+			// Static allocations still need a DHCP range covering the subnet.
+			if len(dhcpRanges) == 0 {
+				dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,static,%s", subnet.IP.String(), expiry)}...)
 			}
 		}
 
@@ -1654,13 +1780,24 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			}
 
 			if util.IsTrue(n.config["ipv6.dhcp.stateful"]) {
-				if n.config["ipv6.dhcp.ranges"] != "" {
-					for _, dhcpRange := range strings.Split(n.config["ipv6.dhcp.ranges"], ",") {
-						dhcpRange = strings.TrimSpace(dhcpRange)
-						dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,%d,%s", strings.ReplaceAll(dhcpRange, "-", ","), subnetSize, expiry)}...)
-					}
-				} else {
-					dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,%s,%d,%s", dhcpalloc.GetIP(subnet, 2), dhcpalloc.GetIP(subnet, -1), subnetSize, expiry)}...)
+				dhcpRanges := n.DHCPv6Ranges()
+				if len(dhcpRanges) == 0 {
+					dhcpRanges = []iprange.Range{{Start: dhcpalloc.GetIP(subnet, 2), End: dhcpalloc.GetIP(subnet, -1)}}
+				}
+
+				// Keep reserved addresses out of the dynamic pool.
+				dhcpRanges, err = n.dhcpRangesExcludingReservations(dhcpRanges)
+				if err != nil {
+					return err
+				}
+
+				for _, dhcpRange := range dhcpRanges {
+					dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,%s,%d,%s", dhcpRange.Start.String(), dhcpRange.End.String(), subnetSize, expiry)}...)
+				}
+
+				// Static allocations still need a DHCP range covering the subnet.
+				if len(dhcpRanges) == 0 {
+					dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("%s,static,%d,%s", subnet.IP.String(), subnetSize, expiry)}...)
 				}
 			} else if util.IsTrueOrEmpty(n.config["ipv6.ra"]) {
 				dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("::,constructor:%s,ra-stateless,ra-names", n.name)}...)
@@ -1823,7 +1960,15 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 				Local: tunLocal,
 			}
 
-			if tunRemote != nil {
+			if util.IsTrue(getConfig("evpn")) {
+				// Skip partial configs.
+				if tunLocal == nil {
+					continue
+				}
+
+				// Remote VTEPs are programmed from the EVPN routes.
+				vxlan.DevName = tunInterface
+			} else if tunRemote != nil {
 				// Skip partial configs.
 				if tunLocal == nil {
 					continue
@@ -1906,6 +2051,19 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Extend the bridge across cluster members over WireGuard.
+	if util.IsTrue(n.config["wireguard.enabled"]) {
+		err = n.wireguardSetup()
+		if err != nil {
+			return fmt.Errorf("Failed setting up WireGuard mesh: %w", err)
+		}
+	} else {
+		err = n.wireguardClear()
+		if err != nil {
+			return fmt.Errorf("Failed removing WireGuard mesh: %w", err)
+		}
+	}
+
 	// Generate and load apparmor profiles.
 	err = apparmor.NetworkLoad(n.state.OS, n)
 	if err != nil {
@@ -2088,12 +2246,41 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup network load balancers.
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Setup network peers.
+	err = n.peerSetupFirewall(-1)
+	if err != nil {
+		return err
+	}
+
+	// Setup flow accounting.
+	err = n.flowExportSetupFirewall()
+	if err != nil {
+		return err
+	}
+
 	// Setup BGP.
 	err = n.bgpSetup(oldConfig)
 	if err != nil {
 		return err
 	}
 
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	// Setup EVPN for the VXLAN tunnels.
+	err = n.evpnSync()
+	if err != nil {
+		return fmt.Errorf("Failed setting up EVPN: %w", err)
+	}
+
 	reverter.Success()
 
 	return nil
@@ -2113,11 +2300,21 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	err = n.evpnClear()
+	if err != nil {
+		return err
+	}
+
 	err = n.deleteChildren()
 	if err != nil {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
 	}
 
+	err = n.wireguardStop()
+	if err != nil {
+		return fmt.Errorf("Failed to delete WireGuard interface: %w", err)
+	}
+
 	// Destroy the bridge interface
 	if n.config["bridge.driver"] == "openvswitch" {
 		vswitch, err := n.state.OVS()
@@ -2611,6 +2808,7 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 	var err error
 	var projectNetworks map[string]map[int64]api.Network
 	var projectNetworksForwardsOnUplink map[string]map[int64][]string
+	projectNetworksLoadBalancers := make(map[string]map[int64][]string)
 	var externalSubnets []externalSubnetUsage
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -2652,6 +2850,22 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 						projectNetworksForwardsOnUplink[projectName][networkID] = append(projectNetworksForwardsOnUplink[projectName][networkID], forward.ListenAddress)
 					}
 				}
+
+				// Get all network load balancer listen addresses, those are shared by all cluster members.
+				networkLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+					NetworkID: &networkID,
+				})
+				if err != nil {
+					return fmt.Errorf("Failed loading network load balancer listen addresses: %w", err)
+				}
+
+				for _, lb := range networkLoadBalancers {
+					if projectNetworksLoadBalancers[projectName] == nil {
+						projectNetworksLoadBalancers[projectName] = make(map[int64][]string)
+					}
+
+					projectNetworksLoadBalancers[projectName][networkID] = append(projectNetworksLoadBalancers[projectName][networkID], lb.ListenAddress)
+				}
 			}
 		}
 
@@ -2743,6 +2957,32 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 		}
 	}
 
+	// Add load balancer listen addresses of bridge networks to this list.
+	// Those of the networks using this one as uplink were already added above.
+	for projectName, networks := range projectNetworksLoadBalancers {
+		for networkID, listenAddresses := range networks {
+			network, found := projectNetworks[projectName][networkID]
+			if !found || network.Type != "bridge" {
+				continue
+			}
+
+			for _, listenAddress := range listenAddresses {
+				// Convert listen address to subnet.
+				listenAddressNet, err := ParseIPToNet(listenAddress)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid existing load balancer listen address %q", listenAddress)
+				}
+
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					subnet:         *listenAddressNet,
+					networkProject: projectName,
+					networkName:    network.Name,
+					usageType:      subnetUsageNetworkLoadBalancer,
+				})
+			}
+		}
+	}
+
 	return externalSubnets, nil
 }
 
@@ -3312,6 +3552,957 @@ func (n *bridge) forwardSetupFirewall() error {
 	return nil
 }
 
+// loadBalancerConvertToFirewallLoadBalancers flattens load balancer port maps into the format compatible with the
+// firewall package, with a load balancer per listen port.
+func (n *bridge) loadBalancerConvertToFirewallLoadBalancers(listenAddress net.IP, portMaps []*loadBalancerPortMap) []firewallDrivers.LoadBalancer {
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+
+	for _, portMap := range portMaps {
+		for i, lp := range portMap.listenPorts {
+			fwLoadBalancer := firewallDrivers.LoadBalancer{
+				ListenAddress: listenAddress,
+				Protocol:      portMap.protocol,
+				ListenPort:    lp,
+			}
+
+			for _, target := range portMap.targets {
+				targetPort := lp // Default to using same port as listen port for target port.
+				targetPortsLen := len(target.ports)
+
+				if targetPortsLen == 1 {
+					// If a single target port is specified, forward all listen ports to it.
+					targetPort = target.ports[0]
+				} else if targetPortsLen > 1 {
+					// If more than 1 target port specified, use listen port index to get the
+					// target port to use.
+					targetPort = target.ports[i]
+				}
+
+				fwLoadBalancer.Targets = append(fwLoadBalancer.Targets, firewallDrivers.LoadBalancerTarget{
+					Address: target.address,
+					Port:    targetPort,
+				})
+			}
+
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
+		}
+	}
+
+	return fwLoadBalancers
+}
+
+// LoadBalancerCreate creates a network load balancer.
+func (n *bridge) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Check if there is an existing load balancer using the same listen address.
+			_, err := dbCluster.GetNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), loadBalancer.ListenAddress)
+
+			return err
+		})
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "A load balancer for that listen address already exists")
+		}
+
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		_, err = n.loadBalancerValidate(listenAddressNet.IP, &loadBalancer.NetworkLoadBalancerPut)
+		if err != nil {
+			return err
+		}
+
+		externalSubnetsInUse, err := n.getExternalSubnetInUse()
+		if err != nil {
+			return err
+		}
+
+		// Check the listen address subnet doesn't fall within any existing network external subnets.
+		for _, externalSubnetUser := range externalSubnetsInUse {
+			// Check if usage is from our own network.
+			if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
+				// Skip checking conflict with our own network's subnet or SNAT address.
+				// But do not allow other conflict with other usage types within our own network.
+				if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
+					continue
+				}
+			}
+
+			if SubnetContains(&externalSubnetUser.subnet, listenAddressNet) || SubnetContains(listenAddressNet, &externalSubnetUser.subnet) {
+				// This error is purposefully vague so that it doesn't reveal any names of
+				// resources potentially outside of the network.
+				return fmt.Errorf("Load balancer listen address %q overlaps with another network or NIC", listenAddressNet.String())
+			}
+		}
+
+		var loadBalancerID int64
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Create load balancer DB record.
+			lb := dbCluster.NetworkLoadBalancer{
+				NetworkID:     n.ID(),
+				ListenAddress: loadBalancer.ListenAddress,
+				Description:   loadBalancer.Description,
+				Backends:      loadBalancer.Backends,
+				Ports:         loadBalancer.Ports,
+			}
+
+			loadBalancerID, err = dbCluster.CreateNetworkLoadBalancer(ctx, tx.Tx(), lb)
+			if err != nil {
+				return err
+			}
+
+			// Save the load balancer configuration.
+			err = dbCluster.CreateNetworkLoadBalancerConfig(ctx, tx.Tx(), loadBalancerID, loadBalancer.Config)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return dbCluster.DeleteNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), loadBalancerID)
+			})
+
+			_ = n.loadBalancerSetupFirewall()
+			_ = n.loadBalancerBGPSetupPrefixes()
+		})
+
+		err = n.loadBalancerSetupFirewall()
+		if err != nil {
+			return err
+		}
+
+		// Notify all other members to refresh their firewall rules and BGP prefixes.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).CreateNetworkLoadBalancer(n.name, loadBalancer)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		err := n.loadBalancerSetupFirewall()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err := n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// LoadBalancerUpdate updates a network load balancer.
+func (n *bridge) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalancerPut, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		var curLoadBalancer *api.NetworkLoadBalancer
+		var curLoadBalancerID int64
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			networkID := n.ID()
+
+			// Get the load balancer.
+			dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+				NetworkID:     &networkID,
+				ListenAddress: &listenAddress,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(dbLoadBalancers) != 1 {
+				return api.StatusErrorf(http.StatusNotFound, "Network load balancer not found")
+			}
+
+			// Get the API struct.
+			curLoadBalancer, err = dbLoadBalancers[0].ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			curLoadBalancerID = dbLoadBalancers[0].ID
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), &req)
+		if err != nil {
+			return err
+		}
+
+		curEtagHash, err := localUtil.EtagHash(curLoadBalancer.Etag())
+		if err != nil {
+			return err
+		}
+
+		newLoadBalancer := api.NetworkLoadBalancer{
+			ListenAddress:          curLoadBalancer.ListenAddress,
+			NetworkLoadBalancerPut: req,
+		}
+
+		newLoadBalancerEtagHash, err := localUtil.EtagHash(newLoadBalancer.Etag())
+		if err != nil {
+			return err
+		}
+
+		if curEtagHash == newLoadBalancerEtagHash {
+			return nil // Nothing has changed.
+		}
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			lb := dbCluster.NetworkLoadBalancer{
+				NetworkID:     n.ID(),
+				ListenAddress: listenAddress,
+				Description:   newLoadBalancer.Description,
+				Backends:      newLoadBalancer.Backends,
+				Ports:         newLoadBalancer.Ports,
+			}
+
+			err = dbCluster.UpdateNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), listenAddress, lb)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateNetworkLoadBalancerConfig(ctx, tx.Tx(), curLoadBalancerID, newLoadBalancer.Config)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				lb := dbCluster.NetworkLoadBalancer{
+					NetworkID:     n.ID(),
+					ListenAddress: listenAddress,
+					Description:   curLoadBalancer.Description,
+					Backends:      curLoadBalancer.Backends,
+					Ports:         curLoadBalancer.Ports,
+				}
+
+				err = dbCluster.UpdateNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), listenAddress, lb)
+				if err != nil {
+					return err
+				}
+
+				err = dbCluster.UpdateNetworkLoadBalancerConfig(ctx, tx.Tx(), curLoadBalancerID, curLoadBalancer.Config)
+				if err != nil {
+					return err
+				}
+
+				return nil
+			})
+
+			_ = n.loadBalancerSetupFirewall()
+			_ = n.loadBalancerBGPSetupPrefixes()
+		})
+
+		err = n.loadBalancerSetupFirewall()
+		if err != nil {
+			return err
+		}
+
+		// Notify all other members to refresh their firewall rules and BGP prefixes.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).UpdateNetworkLoadBalancer(n.name, curLoadBalancer.ListenAddress, req, "")
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		err := n.loadBalancerSetupFirewall()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err := n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// LoadBalancerState returns the current state of the load balancer, as seen by the health checks of this member.
+func (n *bridge) LoadBalancerState(lb api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	lbState := &api.NetworkLoadBalancerState{}
+
+	if !util.IsTrue(lb.Config["healthcheck"]) {
+		return lbState, nil
+	}
+
+	listenAddressNet, err := ParseIPToNet(lb.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing load balancer listen address %q: %w", lb.ListenAddress, err)
+	}
+
+	lbState.BackendHealth = map[string]api.NetworkLoadBalancerStateBackendHealth{}
+
+	for _, backend := range lb.Backends {
+		// Restrict the load balancer to the single backend to get its target ports.
+		backendLoadBalancer := api.NetworkLoadBalancerPut{
+			Backends: []api.NetworkLoadBalancerBackend{backend},
+		}
+
+		for _, lbPort := range lb.Ports {
+			if !slices.Contains(lbPort.TargetBackend, backend.Name) {
+				continue
+			}
+
+			lbPort.TargetBackend = []string{backend.Name}
+			backendLoadBalancer.Ports = append(backendLoadBalancer.Ports, lbPort)
+		}
+
+		portMaps, err := n.loadBalancerValidate(listenAddressNet.IP, &backendLoadBalancer)
+		if err != nil {
+			return nil, err
+		}
+
+		backendHealth := api.NetworkLoadBalancerStateBackendHealth{
+			Address: backend.TargetAddress,
+			Ports:   []api.NetworkLoadBalancerStateBackendHealthPort{},
+		}
+
+		for _, fwLoadBalancer := range n.loadBalancerConvertToFirewallLoadBalancers(listenAddressNet.IP, portMaps) {
+			for _, target := range fwLoadBalancer.Targets {
+				backendHealth.Ports = append(backendHealth.Ports, api.NetworkLoadBalancerStateBackendHealthPort{
+					Protocol: fwLoadBalancer.Protocol,
+					Port:     int(target.Port),
+					Status:   loadBalancerHealthStatus(n.id, fwLoadBalancer.Protocol, target.Address, target.Port),
+				})
+			}
+		}
+
+		lbState.BackendHealth[backend.Name] = backendHealth
+	}
+
+	return lbState, nil
+}
+
+// LoadBalancerDelete deletes a network load balancer.
+func (n *bridge) LoadBalancerDelete(listenAddress string, clientType request.ClientType) error {
+	if clientType == request.ClientTypeNormal {
+		var lb *dbCluster.NetworkLoadBalancer
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			networkID := n.ID()
+
+			dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+				NetworkID:     &networkID,
+				ListenAddress: &listenAddress,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(dbLoadBalancers) != 1 {
+				return api.StatusErrorf(http.StatusNotFound, "Network load balancer not found")
+			}
+
+			lb = &dbLoadBalancers[0]
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Delete the database records.
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), lb.ID)
+		})
+		if err != nil {
+			return err
+		}
+
+		err = n.loadBalancerSetupFirewall()
+		if err != nil {
+			return err
+		}
+
+		// Notify all other members to refresh their firewall rules and BGP prefixes.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).DeleteNetworkLoadBalancer(n.name, lb.ListenAddress)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		err := n.loadBalancerSetupFirewall()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err := n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	return nil
+}
+
+// loadBalancerSetupFirewall applies all network load balancers defined for this network.
+// Backends found to be offline by the health checks of this member are left out.
+func (n *bridge) loadBalancerSetupFirewall() error {
+	var loadBalancers []*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+
+		dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+			NetworkID: &networkID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dbLoadBalancer := range dbLoadBalancers {
+			lb, err := dbLoadBalancer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			loadBalancers = append(loadBalancers, lb)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+
+	for _, lb := range loadBalancers {
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(lb.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing load balancer listen address %q: %w", lb.ListenAddress, err)
+		}
+
+		portMaps, err := n.loadBalancerValidate(listenAddressNet.IP, &lb.NetworkLoadBalancerPut)
+		if err != nil {
+			return fmt.Errorf("Failed validating firewall load balancer for listen address %q: %w", lb.ListenAddress, err)
+		}
+
+		for _, fwLoadBalancer := range n.loadBalancerConvertToFirewallLoadBalancers(listenAddressNet.IP, portMaps) {
+			targets := make([]firewallDrivers.LoadBalancerTarget, 0, len(fwLoadBalancer.Targets))
+			for _, target := range fwLoadBalancer.Targets {
+				if loadBalancerTargetUsable(n.id, lb.Config, fwLoadBalancer.Protocol, target) {
+					targets = append(targets, target)
+				}
+			}
+
+			fwLoadBalancer.Targets = targets
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
+		}
+	}
+
+	err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, fwLoadBalancers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+	}
+
+	return nil
+}
+
+// loadBalancerBGPSetupPrefixes exports external load balancer addresses as prefixes.
+// Load balancers whose backends are all offline aren't exported.
+func (n *bridge) loadBalancerBGPSetupPrefixes() error {
+	var loadBalancers []*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+
+		dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+			NetworkID: &networkID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dbLoadBalancer := range dbLoadBalancers {
+			lb, err := dbLoadBalancer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			loadBalancers = append(loadBalancers, lb)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	// Use load balancer specific owner string (different from the network prefixes) so that these can be
+	// reapplied independently of the network's own prefixes.
+	bgpOwner := fmt.Sprintf("network_%d_load_balancer", n.id)
+
+	// Clear existing load balancer prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(bgpOwner)
+	if err != nil {
+		return err
+	}
+
+	// Add the new prefixes.
+	for _, lb := range loadBalancers {
+		listenAddr := net.ParseIP(lb.ListenAddress)
+		if listenAddr == nil {
+			continue
+		}
+
+		ipVersion := uint(4)
+		routeSubnetSize := 32
+		if listenAddr.To4() == nil {
+			ipVersion = 6
+			routeSubnetSize = 128
+		}
+
+		// Don't export internal load balancers (those inside the NAT enabled network's subnet).
+		natEnabled := util.IsTrue(n.config[fmt.Sprintf("ipv%d.nat", ipVersion)])
+		_, netSubnet, _ := net.ParseCIDR(n.config[fmt.Sprintf("ipv%d.address", ipVersion)])
+		if natEnabled && netSubnet != nil && netSubnet.Contains(listenAddr) {
+			continue
+		}
+
+		// Check health of load balancer (if enabled).
+		portMaps, err := n.loadBalancerValidate(listenAddr, &lb.NetworkLoadBalancerPut)
+		if err != nil {
+			return err
+		}
+
+		online := false
+		for _, fwLoadBalancer := range n.loadBalancerConvertToFirewallLoadBalancers(listenAddr, portMaps) {
+			for _, target := range fwLoadBalancer.Targets {
+				if loadBalancerTargetUsable(n.id, lb.Config, fwLoadBalancer.Protocol, target) {
+					online = true
+					break
+				}
+			}
+		}
+
+		if !online {
+			continue
+		}
+
+		_, ipRouteSubnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", listenAddr.String(), routeSubnetSize))
+		if err != nil {
+			return err
+		}
+
+		err = n.state.BGP.AddPrefix(*ipRouteSubnet, n.bgpNextHopAddress(ipVersion), bgpOwner)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// peerTargetBridge loads the bridge network targeted by a local peering. Returns nil if the target network
+// doesn't exist.
+func (n *bridge) peerTargetBridge(peer api.NetworkPeersPost) (*bridge, error) {
+	targetNet, err := LoadByName(n.state, peer.TargetProject, peer.TargetNetwork)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed loading target network: %w", err)
+	}
+
+	targetBridge, ok := targetNet.(*bridge)
+	if !ok {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Target network %q is not of type bridge", peer.TargetNetwork)
+	}
+
+	return targetBridge, nil
+}
+
+// peerSubnets returns the subnets of a bridge network config which are routed to its peers.
+func (n *bridge) peerSubnets(config map[string]string) []*net.IPNet {
+	subnets := []*net.IPNet{}
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		_, subnet, err := net.ParseCIDR(config[key])
+		if err != nil {
+			continue
+		}
+
+		subnets = append(subnets, subnet)
+	}
+
+	return subnets
+}
+
+// peerSetupFirewall applies the firewall rules allowing traffic to be routed between the bridge and the networks it
+// is peered with. Peerings with the network of ID skipNetworkID are left out (used when removing a peering).
+func (n *bridge) peerSetupFirewall(skipNetworkID int64) error {
+	// Peering rules are only applied if the bridge is running on this member.
+	if !n.isRunning() {
+		return nil
+	}
+
+	fwPeers := []firewallDrivers.NetworkPeer{}
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		dbPeers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{NetworkID: &networkID})
+		if err != nil {
+			return err
+		}
+
+		for _, dbPeer := range dbPeers {
+			if !dbPeer.TargetNetworkID.Valid || dbPeer.TargetNetworkID.Int64 == skipNetworkID {
+				continue
+			}
+
+			peer, err := dbPeer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			if peer.Type != "local" || peer.Status != api.NetworkStatusCreated {
+				continue
+			}
+
+			_, targetNet, _, err := tx.GetNetworkInAnyState(ctx, peer.TargetProject, peer.TargetNetwork)
+			if err != nil {
+				return fmt.Errorf("Failed loading target network %q of peer %q: %w", peer.TargetNetwork, peer.Name, err)
+			}
+
+			fwPeers = append(fwPeers, firewallDrivers.NetworkPeer{
+				TargetNetwork: targetNet.Name,
+				LocalSubnets:  n.peerSubnets(n.config),
+				TargetSubnets: n.peerSubnets(targetNet.Config),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network peers: %w", err)
+	}
+
+	err = n.state.Firewall.NetworkApplyPeers(n.name, fwPeers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall network peers: %w", err)
+	}
+
+	return nil
+}
+
+// PeerCreate creates a network peering with another bridge network.
+// Traffic between the peered networks is routed by the host and allowed by the firewall, subject to the ACLs of
+// both networks. When called from a cluster notification, only the local firewall rules are applied.
+func (n *bridge) PeerCreate(peer api.NetworkPeersPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if peer.Type != "" && peer.Type != "local" {
+		return api.StatusErrorf(http.StatusBadRequest, "Only local peerings are supported by bridge networks")
+	}
+
+	if peer.TargetProject == "" {
+		peer.TargetProject = n.Project()
+	}
+
+	targetBridge, err := n.peerTargetBridge(peer)
+	if err != nil {
+		return err
+	}
+
+	if targetBridge != nil && targetBridge.ID() == n.ID() {
+		return api.StatusErrorf(http.StatusBadRequest, "A network cannot be peered with itself")
+	}
+
+	if clientType == request.ClientTypeNormal {
+		peerID, _, err := n.peerCreateRecord(&peer)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				err := dbCluster.DeleteNetworkPeer(ctx, tx.Tx(), n.ID(), peerID)
+				if errors.Is(err, dbCluster.ErrNotFound) {
+					return nil
+				}
+
+				return err
+			})
+			_ = n.peerSetupFirewall(-1)
+		})
+	}
+
+	// Apply the firewall rules on both sides of the peering (only has an effect once the peering is mutual).
+	err = n.peerSetupFirewall(-1)
+	if err != nil {
+		return err
+	}
+
+	if targetBridge != nil {
+		reverter.Add(func() { _ = targetBridge.peerSetupFirewall(-1) })
+
+		err = targetBridge.peerSetupFirewall(-1)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh the ACLs on both sides so that rules referencing the peering match its subnets.
+	for _, b := range []*bridge{n, targetBridge} {
+		if b == nil || !b.isRunning() || b.config["security.acls"] == "" {
+			continue
+		}
+
+		aclNet := acl.NetworkACLUsage{
+			Name:   b.Name(),
+			Type:   b.Type(),
+			ID:     b.ID(),
+			Config: b.Config(),
+		}
+
+		err = acl.FirewallApplyACLRules(b.state, b.logger, b.Project(), aclNet)
+		if err != nil {
+			return err
+		}
+	}
+
+	if clientType == request.ClientTypeNormal {
+		// Notify all other members to refresh their firewall rules.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).CreateNetworkPeer(n.name, peer)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// PeerUpdate updates a network peering.
+func (n *bridge) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
+	return n.peerUpdate(peerName, req)
+}
+
+// PeerDelete deletes a network peering.
+// When called from a cluster notification, only the local firewall rules are removed.
+func (n *bridge) PeerDelete(peerName string, clientType request.ClientType) error {
+	peerID, peer, err := n.peerLoad(peerName)
+	if err != nil {
+		return err
+	}
+
+	if clientType == request.ClientTypeNormal {
+		isUsed, err := n.peerIsUsed(peer.Name)
+		if err != nil {
+			return err
+		}
+
+		if isUsed {
+			return errors.New("Cannot delete a peer that is in use")
+		}
+
+		// Notify all other members to remove their firewall rules while the peering still exists.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).DeleteNetworkPeer(n.name, peerName)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove the firewall rules on both sides of the peering.
+	if peer.Status == api.NetworkStatusCreated {
+		targetBridge, err := n.peerTargetBridge(api.NetworkPeersPost{TargetProject: peer.TargetProject, TargetNetwork: peer.TargetNetwork})
+		if err != nil {
+			return err
+		}
+
+		if targetBridge != nil {
+			err = n.peerSetupFirewall(targetBridge.ID())
+			if err != nil {
+				return err
+			}
+
+			err = targetBridge.peerSetupFirewall(n.ID())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if clientType != request.ClientTypeNormal {
+		return nil
+	}
+
+	return n.peerDeleteRecord(peerID, peer)
+}
+
+// dhcpRangesExcludingReservations removes the network's reserved addresses from the supplied DHCP ranges.
+func (n *bridge) dhcpRangesExcludingReservations(ranges []iprange.Range) ([]iprange.Range, error) {
+	reserved, err := n.ReservedRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	return subtractRanges(ranges, reserved), nil
+}
+
+// reservationsApply regenerates the DHCP ranges of the local bridge so they skip reserved addresses.
+func (n *bridge) reservationsApply() error {
+	if !n.isRunning() {
+		return nil
+	}
+
+	return n.setup(n.config)
+}
+
+// ReservationCreate creates a network address reservation.
+func (n *bridge) ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		err := n.reservationCreateRecord(&reservation)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_, _ = n.reservationDeleteRecord(reservation.Address)
+			_ = n.reservationsApply()
+		})
+	}
+
+	err := n.reservationsApply()
+	if err != nil {
+		return fmt.Errorf("Failed applying address reservation: %w", err)
+	}
+
+	if clientType == request.ClientTypeNormal {
+		// Notify all other members to refresh their DHCP ranges.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).CreateNetworkReservation(n.name, reservation)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// ReservationDelete deletes a network address reservation.
+func (n *bridge) ReservationDelete(address string, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		record, err := n.reservationDeleteRecord(address)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			n.reservationRestoreRecord(record)
+			_ = n.reservationsApply()
+		})
+
+		address = record.Address
+	}
+
+	err := n.reservationsApply()
+	if err != nil {
+		return fmt.Errorf("Failed applying address reservation removal: %w", err)
+	}
+
+	if clientType == request.ClientTypeNormal {
+		// Notify all other members to refresh their DHCP ranges.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).DeleteNetworkReservation(n.name, address)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
 // The projectName passed here refers to the initial project from the API request which may differ from the network's project.
 func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
@@ -3566,6 +4757,7 @@ func (n *bridge) deleteChildren() error {
 	kinds := []string{
 		"vxlan",
 		"gretap",
+		"ip6gretap",
 		"dummy",
 	}
 
@@ -3,11 +3,13 @@ package network
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"maps"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"slices"
 	"strconv"
@@ -24,11 +26,13 @@ import (
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
 	"github.com/lxc/incus/v7/internal/server/network/acl"
 	"github.com/lxc/incus/v7/internal/server/state"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/logger"
 	"github.com/lxc/incus/v7/shared/resources"
+	"github.com/lxc/incus/v7/shared/revert"
 	"github.com/lxc/incus/v7/shared/util"
 	"github.com/lxc/incus/v7/shared/validate"
 )
@@ -40,6 +44,7 @@ type Info struct {
 	AddressForwards    bool // Indicates if driver supports address forwards.
 	LoadBalancers      bool // Indicates if driver supports load balancers.
 	Peering            bool // Indicates if the driver supports network peering.
+	Reservations       bool // Indicates if the driver supports address reservations.
 }
 
 // forwardTarget represents a single port forward target.
@@ -694,6 +699,12 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	// Clear existing load balancer prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d_load_balancer", n.id))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1528,7 +1539,7 @@ func (n *common) Leases(projectName string, clientType request.ClientType) ([]ap
 }
 
 // PeerCreate returns ErrNotImplemented for drivers that do not support forwards.
-func (n *common) PeerCreate(forward api.NetworkPeersPost) error {
+func (n *common) PeerCreate(forward api.NetworkPeersPost, clientType request.ClientType) error {
 	return ErrNotImplemented
 }
 
@@ -1538,10 +1549,327 @@ func (n *common) PeerUpdate(peerName string, newPeer api.NetworkPeerPut) error {
 }
 
 // PeerDelete returns ErrNotImplemented for drivers that do not support forwards.
-func (n *common) PeerDelete(peerName string) error {
+func (n *common) PeerDelete(peerName string, clientType request.ClientType) error {
 	return ErrNotImplemented
 }
 
+// peerCreateRecord validates a new network peering and creates its database record, turning an existing
+// peering from the target network into a mutual one. Returns the ID of the new record and whether the peering
+// is mutual.
+func (n *common) peerCreateRecord(peer *api.NetworkPeersPost) (int64, bool, error) {
+	// Default type is local.
+	if peer.Type == "" {
+		peer.Type = "local"
+	}
+
+	// Perform create-time validation.
+	switch peer.Type {
+	case "local":
+		// Default to network's project if target project not specified.
+		if peer.TargetProject == "" {
+			peer.TargetProject = n.Project()
+		}
+
+		// Target network name is required.
+		if peer.TargetNetwork == "" {
+			return -1, false, api.StatusErrorf(http.StatusBadRequest, "Target network is required")
+		}
+
+	case "remote":
+		// Target integration name is required.
+		if peer.TargetIntegration == "" {
+			return -1, false, api.StatusErrorf(http.StatusBadRequest, "Target integration is required")
+		}
+	}
+
+	// Look for an existing entry.
+	var peers map[int64]*api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Use generated function to get peers.
+		netID := n.ID()
+		filter := dbCluster.NetworkPeerFilter{NetworkID: &netID}
+		dbPeers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), filter)
+		if err != nil {
+			return fmt.Errorf("Failed loading network peer DB objects: %w", err)
+		}
+
+		// Convert DB objects to API objects and build the map.
+		peers = make(map[int64]*api.NetworkPeer, len(dbPeers))
+		for _, dbPeer := range dbPeers {
+			peer, err := dbPeer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
+			}
+
+			peers[dbPeer.ID] = peer
+		}
+
+		return nil
+	})
+	if err != nil {
+		return -1, false, err
+	}
+
+	for _, existingPeer := range peers {
+		if peer.Name == existingPeer.Name {
+			return -1, false, api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
+		}
+
+		if peer.Type == "local" && peer.TargetProject == existingPeer.TargetProject && peer.TargetNetwork == existingPeer.TargetNetwork {
+			return -1, false, api.StatusErrorf(http.StatusConflict, "A peer for that target network already exists")
+		}
+	}
+
+	// Perform general (create and update) validation.
+	err = n.peerValidate(peer.Name, &peer.NetworkPeerPut)
+	if err != nil {
+		return -1, false, err
+	}
+
+	var peerID int64
+	var mutualExists bool
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error { // Create peer DB record.
+		record := dbCluster.NetworkPeer{
+			NetworkID:   n.ID(),
+			Name:        peer.Name,
+			Description: peer.Description,
+			Type:        dbCluster.NetworkPeerTypes[peer.Type],
+		}
+
+		switch peer.Type {
+		case "remote":
+			integrationID, err := dbCluster.GetNetworkIntegrationID(ctx, tx.Tx(), peer.TargetIntegration)
+			if err != nil {
+				return err
+			}
+
+			id := sql.NullInt64{}
+			err = id.Scan(integrationID)
+			if err != nil {
+				return err
+			}
+
+			record.TargetNetworkIntegrationID = id
+
+		case "local":
+			// Check if target peer already exists.
+			peers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{
+				Type:                 &record.Type,
+				TargetNetworkProject: &n.project,
+				TargetNetworkName:    &n.name,
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			if len(peers) == 1 {
+				// Update the target peer.
+				peer := peers[0]
+
+				empty := sql.NullString{}
+				peer.TargetNetworkProject = empty
+				peer.TargetNetworkName = empty
+
+				targetID := sql.NullInt64{}
+				err = targetID.Scan(n.id)
+				if err != nil {
+					return err
+				}
+
+				peer.TargetNetworkID = targetID
+
+				err = dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), peer.NetworkID, peer.Name, peer)
+				if err != nil {
+					return err
+				}
+
+				// Set our target network ID to match.
+				id := sql.NullInt64{}
+				err = id.Scan(peer.NetworkID)
+				if err != nil {
+					return err
+				}
+
+				record.TargetNetworkID = id
+
+				mutualExists = true
+			} else if len(peers) == 0 {
+				networkProjectName := sql.NullString{}
+				err = networkProjectName.Scan(peer.TargetProject)
+				if err != nil {
+					return err
+				}
+
+				networkName := sql.NullString{}
+				err = networkName.Scan(peer.TargetNetwork)
+				if err != nil {
+					return err
+				}
+
+				record.TargetNetworkProject = networkProjectName
+				record.TargetNetworkName = networkName
+			} else {
+				return errors.New("More than one matching network peer was found")
+			}
+		}
+
+		peerID, err = dbCluster.CreateNetworkPeer(ctx, tx.Tx(), record)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return -1, false, err
+	}
+
+	return peerID, mutualExists, nil
+}
+
+// peerUpdate updates the database record of a network peering.
+func (n *common) peerUpdate(peerName string, req api.NetworkPeerPut) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	var curPeer *api.NetworkPeer
+	var dbCurPeer *dbCluster.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbCurPeer, err = dbCluster.GetNetworkPeer(ctx, tx.Tx(), n.id, peerName)
+		if err != nil {
+			return fmt.Errorf("Failed getting network peer DB object: %w", err)
+		}
+
+		curPeer, err = dbCurPeer.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = n.peerValidate(peerName, &req)
+	if err != nil {
+		return err
+	}
+
+	curPeerEtagHash, err := localUtil.EtagHash(curPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newPeer := api.NetworkPeer{
+		Name:           curPeer.Name,
+		NetworkPeerPut: req,
+	}
+
+	newPeerEtagHash, err := localUtil.EtagHash(newPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curPeerEtagHash == newPeerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Update the description field from the input.
+		dbCurPeer.Description = newPeer.Description
+
+		// Update the main peer object.
+		err = dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), n.id, dbCurPeer.Name, *dbCurPeer)
+		if err != nil {
+			return fmt.Errorf("Failed to update network peer: %w", err)
+		}
+
+		// Update the peer configuration.
+		err = dbCluster.UpdateNetworkPeerConfig(ctx, tx.Tx(), dbCurPeer.ID, newPeer.Config)
+		if err != nil {
+			return fmt.Errorf("Failed to update network peer config: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// peerLoad returns the database ID and API object of a network peering.
+func (n *common) peerLoad(peerName string) (int64, *api.NetworkPeer, error) {
+	var peerID int64
+	var peer *api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbPeer, err := dbCluster.GetNetworkPeer(ctx, tx.Tx(), n.id, peerName)
+		if err != nil {
+			return fmt.Errorf("Failed getting network peer DB object: %w", err)
+		}
+
+		peerID = dbPeer.ID
+		peer, err = dbPeer.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return peerID, peer, nil
+}
+
+// peerDeleteRecord deletes the database record of a network peering, deactivating the mutual peering of the
+// target network.
+func (n *common) peerDeleteRecord(peerID int64, peer *api.NetworkPeer) error {
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Deactivate any existing peer.
+		if peer.Type == "local" {
+			peers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{TargetNetworkID: &n.id})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			for _, peer := range peers {
+				peer.TargetNetworkID = sql.NullInt64{}
+
+				err = dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), peer.NetworkID, peer.Name, peer)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Delete the peer.
+		err := dbCluster.DeleteNetworkPeer(ctx, tx.Tx(), n.id, peerID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // peerValidate validates the peer request.
 func (n *common) peerValidate(peerName string, peer *api.NetworkPeerPut) error {
 	err := acl.ValidName(peerName)
@@ -152,6 +152,7 @@ func (n *ovn) Info() Info {
 	info.AddressForwards = true
 	info.LoadBalancers = true
 	info.Peering = true
+	info.Reservations = true
 
 	return info
 }
@@ -755,6 +756,22 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  condition: `security.acls`
 		"security.acls.default.egress.action": validate.Optional(validate.IsOneOf(acl.ValidActions...)),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=flow_export.ipfix.targets)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Comma-separated list of IPFIX collectors (`<IP>:<port>`) to export sampled flows to
+		"flow_export.ipfix.targets": validate.Optional(validate.IsListOf(validate.IsListenAddress(false, false, true))),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=flow_export.sampling)
+		//
+		// ---
+		//  type: integer
+		//  condition: `flow_export.ipfix.targets`
+		//  shortdesc: Sampling rate of the exported flows (one packet out of this many)
+		//  default: `400`
+		"flow_export.sampling": validate.Optional(validate.IsInRange(1, 4294967295)),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=security.acls.default.ingress.logged)
 		//
 		// ---
@@ -2612,6 +2629,18 @@ func (n *ovn) getDHCPv4Reservations() ([]iprange.Range, error) {
 		return nil, err
 	}
 
+	// Exclude the IPv4 address reservations of the network.
+	reservedRanges, err := n.ReservedRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservedRange := range reservedRanges {
+		if reservedRange.Start.To4() != nil {
+			dhcpReserveIPv4s = append(dhcpReserveIPv4s, reservedRange)
+		}
+	}
+
 	return dhcpReserveIPv4s, nil
 }
 
@@ -3453,6 +3482,12 @@ func (n *ovn) setup(update bool) error {
 		return fmt.Errorf("Failed applying baseline ACL rules to internal switch: %w", err)
 	}
 
+	// Sample the packets of the internal switch for flow export, the baseline rules replaced any previous sampling.
+	err = n.flowExportSetupSampling()
+	if err != nil {
+		return err
+	}
+
 	// Create network port group if needed.
 	err = n.ensureNetworkPortGroup(projectID)
 	if err != nil {
@@ -3713,6 +3748,14 @@ func (n *ovn) Delete(clientType request.ClientType) error {
 			return err
 		}
 
+		// Delete the flow sampling of the internal logical switch, its collector isn't tied to the switch.
+		if n.config["flow_export.ipfix.targets"] != "" {
+			err = n.ovnnb.LogicalSwitchDeleteFlowSampling(context.TODO(), n.getIntSwitchName(), int(n.id))
+			if err != nil && !errors.Is(err, networkOVN.ErrNotFound) {
+				return err
+			}
+		}
+
 		// Delete the internal logical switch and anything tied to it (ports, ...).
 		err = n.ovnnb.DeleteLogicalSwitch(context.TODO(), n.getIntSwitchName())
 		if err != nil && !errors.Is(err, networkOVN.ErrNotFound) {
@@ -4054,6 +4097,14 @@ func (n *ovn) Start() error {
 		return err
 	}
 
+	// Setup the flow export collectors on the integration bridge.
+	if n.config["flow_export.ipfix.targets"] != "" {
+		err = n.flowExportSetupChassis(n.config)
+		if err != nil {
+			return fmt.Errorf("Failed applying flow export: %w", err)
+		}
+	}
+
 	reverter.Success()
 
 	// Ensure network is marked as available now its started.
@@ -4091,6 +4142,14 @@ func (n *ovn) Stop() error {
 		return err
 	}
 
+	// Stop exporting the flows of the network.
+	if n.config["flow_export.ipfix.targets"] != "" {
+		err = n.flowExportSetupChassis(nil)
+		if err != nil {
+			return fmt.Errorf("Failed clearing flow export: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -4148,6 +4207,13 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 			}
 		}
 
+		if flowExportChanged(changedKeys) {
+			err = n.flowExportSetupChassis(newNetwork.Config)
+			if err != nil {
+				return fmt.Errorf("Failed applying flow export: %w", err)
+			}
+		}
+
 		return nil
 	}
 
@@ -4494,6 +4560,13 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 		return err
 	}
 
+	if flowExportChanged(changedKeys) {
+		err = n.flowExportSetupChassis(newNetwork.Config)
+		if err != nil {
+			return fmt.Errorf("Failed applying flow export: %w", err)
+		}
+	}
+
 	if len(n.getTunnelsFromChangedKeys(changedKeys)) > 0 {
 		// Notify all other members about tunnels configuration change.
 		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
@@ -6616,6 +6689,73 @@ func (n *ovn) loadBalancerFlattenVIPs(listenAddress net.IP, portMaps []*loadBala
 	return vips
 }
 
+// reservationsApply refreshes the addresses excluded from OVN's dynamic IPv4 allocation.
+func (n *ovn) reservationsApply() error {
+	if util.IsNoneOrEmpty(n.config["ipv4.address"]) {
+		return nil
+	}
+
+	dhcpReservations, err := n.getDHCPv4Reservations()
+	if err != nil {
+		return fmt.Errorf("Failed getting DHCPv4 IP reservations: %w", err)
+	}
+
+	err = n.ovnnb.UpdateLogicalSwitchDHCPv4Revervations(context.TODO(), n.getIntSwitchName(), dhcpReservations)
+	if err != nil {
+		return fmt.Errorf("Failed updating DHCPv4 reservations: %w", err)
+	}
+
+	return nil
+}
+
+// ReservationCreate creates a network address reservation.
+func (n *ovn) ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	err := n.reservationCreateRecord(&reservation)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_, _ = n.reservationDeleteRecord(reservation.Address)
+		_ = n.reservationsApply()
+	})
+
+	err = n.reservationsApply()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// ReservationDelete deletes a network address reservation.
+func (n *ovn) ReservationDelete(address string, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	record, err := n.reservationDeleteRecord(address)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		n.reservationRestoreRecord(record)
+		_ = n.reservationsApply()
+	})
+
+	err = n.reservationsApply()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // LoadBalancerCreate creates a network load balancer.
 func (n *ovn) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error {
 	if n.config["network"] == "none" {
@@ -7559,177 +7699,11 @@ func (n *ovn) remotePeerCreate(peer api.NetworkPeersPost) error {
 }
 
 // PeerCreate creates a network peering.
-func (n *ovn) PeerCreate(peer api.NetworkPeersPost) error {
+func (n *ovn) PeerCreate(peer api.NetworkPeersPost, clientType request.ClientType) error {
 	reverter := revert.New()
 	defer reverter.Fail()
 
-	// Default type is local.
-	if peer.Type == "" {
-		peer.Type = "local"
-	}
-
-	// Perform create-time validation.
-	switch peer.Type {
-	case "local":
-		// Default to network's project if target project not specified.
-		if peer.TargetProject == "" {
-			peer.TargetProject = n.Project()
-		}
-
-		// Target network name is required.
-		if peer.TargetNetwork == "" {
-			return api.StatusErrorf(http.StatusBadRequest, "Target network is required")
-		}
-
-	case "remote":
-		// Target integration name is required.
-		if peer.TargetIntegration == "" {
-			return api.StatusErrorf(http.StatusBadRequest, "Target integration is required")
-		}
-	}
-
-	// Look for an existing entry.
-	var peers map[int64]*api.NetworkPeer
-
-	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		var err error
-
-		// Use generated function to get peers.
-		netID := n.ID()
-		filter := dbCluster.NetworkPeerFilter{NetworkID: &netID}
-		dbPeers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), filter)
-		if err != nil {
-			return fmt.Errorf("Failed loading network peer DB objects: %w", err)
-		}
-
-		// Convert DB objects to API objects and build the map.
-		peers = make(map[int64]*api.NetworkPeer, len(dbPeers))
-		for _, dbPeer := range dbPeers {
-			peer, err := dbPeer.ToAPI(ctx, tx.Tx())
-			if err != nil {
-				return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
-			}
-
-			peers[dbPeer.ID] = peer
-		}
-
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	for _, existingPeer := range peers {
-		if peer.Name == existingPeer.Name {
-			return api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
-		}
-
-		if peer.Type == "local" && peer.TargetProject == existingPeer.TargetProject && peer.TargetNetwork == existingPeer.TargetNetwork {
-			return api.StatusErrorf(http.StatusConflict, "A peer for that target network already exists")
-		}
-	}
-
-	// Perform general (create and update) validation.
-	err = n.peerValidate(peer.Name, &peer.NetworkPeerPut)
-	if err != nil {
-		return err
-	}
-
-	var peerID int64
-	var mutualExists bool
-
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error { // Create peer DB record.
-		record := dbCluster.NetworkPeer{
-			NetworkID:   n.ID(),
-			Name:        peer.Name,
-			Description: peer.Description,
-			Type:        dbCluster.NetworkPeerTypes[peer.Type],
-		}
-
-		switch peer.Type {
-		case "remote":
-			integrationID, err := dbCluster.GetNetworkIntegrationID(ctx, tx.Tx(), peer.TargetIntegration)
-			if err != nil {
-				return err
-			}
-
-			id := sql.NullInt64{}
-			err = id.Scan(integrationID)
-			if err != nil {
-				return err
-			}
-
-			record.TargetNetworkIntegrationID = id
-
-		case "local":
-			// Check if target peer already exists.
-			peers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{
-				Type:                 &record.Type,
-				TargetNetworkProject: &n.project,
-				TargetNetworkName:    &n.name,
-			})
-			if err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return err
-			}
-
-			if len(peers) == 1 {
-				// Update the target peer.
-				peer := peers[0]
-
-				empty := sql.NullString{}
-				peer.TargetNetworkProject = empty
-				peer.TargetNetworkName = empty
-
-				targetID := sql.NullInt64{}
-				err = targetID.Scan(n.id)
-				if err != nil {
-					return err
-				}
-
-				peer.TargetNetworkID = targetID
-
-				err = dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), peer.NetworkID, peer.Name, peer)
-				if err != nil {
-					return err
-				}
-
-				// Set our target network ID to match.
-				id := sql.NullInt64{}
-				err = id.Scan(peer.NetworkID)
-				if err != nil {
-					return err
-				}
-
-				record.TargetNetworkID = id
-
-				mutualExists = true
-			} else if len(peers) == 0 {
-				networkProjectName := sql.NullString{}
-				err = networkProjectName.Scan(peer.TargetProject)
-				if err != nil {
-					return err
-				}
-
-				networkName := sql.NullString{}
-				err = networkName.Scan(peer.TargetNetwork)
-				if err != nil {
-					return err
-				}
-
-				record.TargetNetworkProject = networkProjectName
-				record.TargetNetworkName = networkName
-			} else {
-				return errors.New("More than one matching network peer was found")
-			}
-		}
-
-		peerID, err = dbCluster.CreateNetworkPeer(ctx, tx.Tx(), record)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
+	peerID, mutualExists, err := n.peerCreateRecord(&peer)
 	if err != nil {
 		return err
 	}
@@ -7896,79 +7870,7 @@ func (n *ovn) peerSetup(ovnnb *networkOVN.NB, targetOVNNet *ovn, opts networkOVN
 
 // PeerUpdate updates a network peering.
 func (n *ovn) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
-	reverter := revert.New()
-	defer reverter.Fail()
-
-	var curPeer *api.NetworkPeer
-	var dbCurPeer *dbCluster.NetworkPeer
-
-	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		var err error
-
-		dbCurPeer, err = dbCluster.GetNetworkPeer(ctx, tx.Tx(), n.id, peerName)
-		if err != nil {
-			return fmt.Errorf("Failed getting network peer DB object: %w", err)
-		}
-
-		curPeer, err = dbCurPeer.ToAPI(ctx, tx.Tx())
-		if err != nil {
-			return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
-		}
-
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	err = n.peerValidate(peerName, &req)
-	if err != nil {
-		return err
-	}
-
-	curPeerEtagHash, err := localUtil.EtagHash(curPeer.Etag())
-	if err != nil {
-		return err
-	}
-
-	newPeer := api.NetworkPeer{
-		Name:           curPeer.Name,
-		NetworkPeerPut: req,
-	}
-
-	newPeerEtagHash, err := localUtil.EtagHash(newPeer.Etag())
-	if err != nil {
-		return err
-	}
-
-	if curPeerEtagHash == newPeerEtagHash {
-		return nil // Nothing has changed.
-	}
-
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Update the description field from the input.
-		dbCurPeer.Description = newPeer.Description
-
-		// Update the main peer object.
-		err = dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), n.id, dbCurPeer.Name, *dbCurPeer)
-		if err != nil {
-			return fmt.Errorf("Failed to update network peer: %w", err)
-		}
-
-		// Update the peer configuration.
-		err = dbCluster.UpdateNetworkPeerConfig(ctx, tx.Tx(), dbCurPeer.ID, newPeer.Config)
-		if err != nil {
-			return fmt.Errorf("Failed to update network peer config: %w", err)
-		}
-
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	reverter.Success()
-	return nil
+	return n.peerUpdate(peerName, req)
 }
 
 // localPeerDelete deletes a network peering with another local network.
@@ -8111,24 +8013,8 @@ func (n *ovn) remotePeerDelete(peer *api.NetworkPeer) error {
 }
 
 // PeerDelete deletes a network peering.
-func (n *ovn) PeerDelete(peerName string) error {
-	var peerID int64
-	var peer *api.NetworkPeer
-
-	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		dbPeer, err := dbCluster.GetNetworkPeer(ctx, tx.Tx(), n.id, peerName)
-		if err != nil {
-			return fmt.Errorf("Failed getting network peer DB object: %w", err)
-		}
-
-		peerID = dbPeer.ID
-		peer, err = dbPeer.ToAPI(ctx, tx.Tx())
-		if err != nil {
-			return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
-		}
-
-		return nil
-	})
+func (n *ovn) PeerDelete(peerName string, clientType request.ClientType) error {
+	peerID, peer, err := n.peerLoad(peerName)
 	if err != nil {
 		return err
 	}
@@ -8158,37 +8044,7 @@ func (n *ovn) PeerDelete(peerName string) error {
 		}
 	}
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Deactivate any existing peer.
-		if peer.Type == "local" {
-			peers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{TargetNetworkID: &n.id})
-			if err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return err
-			}
-
-			for _, peer := range peers {
-				peer.TargetNetworkID = sql.NullInt64{}
-
-				err = dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), peer.NetworkID, peer.Name, peer)
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		// Delete the peer.
-		err := dbCluster.DeleteNetworkPeer(ctx, tx.Tx(), n.id, peerID)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return n.peerDeleteRecord(peerID, peer)
 }
 
 // forPeers runs f for each target peer network that this network is connected to.
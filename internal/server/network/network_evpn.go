@@ -0,0 +1,288 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v7/internal/server/bgp"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/ip"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// evpnFloodMAC is the MAC address of the forwarding database entries used to flood broadcast, unknown unicast and
+// multicast traffic to the VTEPs of a VXLAN network identifier.
+var evpnFloodMAC = net.HardwareAddr{0, 0, 0, 0, 0, 0}
+
+// evpnTunnel represents a VXLAN tunnel of a bridge using EVPN.
+type evpnTunnel struct {
+	name  string
+	vni   uint32
+	local net.IP
+}
+
+// evpnEnabled returns whether any of the tunnels in the network config use EVPN.
+func evpnEnabled(config map[string]string) bool {
+	for k, v := range config {
+		if strings.HasPrefix(k, "tunnel.") && strings.HasSuffix(k, ".evpn") && util.IsTrue(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evpnOwner returns the owner of the EVPN routes advertised for the bridge.
+func (n *bridge) evpnOwner() string {
+	return fmt.Sprintf("network_%d_evpn", n.id)
+}
+
+// evpnTunnels returns the VXLAN tunnels of the bridge using EVPN.
+func (n *bridge) evpnTunnels() []evpnTunnel {
+	tunnels := []evpnTunnel{}
+	for _, tunnel := range n.getTunnels() {
+		getConfig := func(key string) string {
+			return n.config[fmt.Sprintf("tunnel.%s.%s", tunnel, key)]
+		}
+
+		if getConfig("protocol") != "vxlan" || util.IsFalseOrEmpty(getConfig("evpn")) {
+			continue
+		}
+
+		local := net.ParseIP(getConfig("local"))
+		if local == nil {
+			continue
+		}
+
+		vni := uint64(1)
+		if getConfig("id") != "" {
+			var err error
+
+			vni, err = strconv.ParseUint(getConfig("id"), 10, 32)
+			if err != nil {
+				continue
+			}
+		}
+
+		tunnels = append(tunnels, evpnTunnel{
+			name:  fmt.Sprintf("%s-%s", n.name, tunnel),
+			vni:   uint32(vni),
+			local: local,
+		})
+	}
+
+	return tunnels
+}
+
+// evpnLocalRoutes returns the EVPN routes to advertise for the instances and hosts reachable through the bridge on
+// this server: an inclusive multicast route per tunnel and a MAC/IP advertisement route per learned MAC and IP.
+func (n *bridge) evpnLocalRoutes(tunnels []evpnTunnel) ([]bgp.EVPNRoute, error) {
+	// Get the MAC addresses learned by the bridge from ports other than tunnels.
+	learned, err := (&ip.FDB{DevName: n.name}).ShowLearned()
+	if err != nil {
+		return nil, err
+	}
+
+	tunnelPorts := map[string]bool{}
+	macs := map[string]net.HardwareAddr{}
+	for _, entry := range learned {
+		isTunnel, found := tunnelPorts[entry.DevName]
+		if !found {
+			link, err := ip.LinkByName(entry.DevName)
+			if err != nil {
+				continue
+			}
+
+			isTunnel = slices.Contains([]string{"vxlan", "gretap", "ip6gretap"}, link.Kind)
+			tunnelPorts[entry.DevName] = isTunnel
+		}
+
+		if isTunnel {
+			continue
+		}
+
+		macs[entry.MAC.String()] = entry.MAC
+	}
+
+	// Get the IP addresses of those MAC addresses from the neighbor table of the bridge.
+	neighbors, err := (&ip.Neigh{DevName: n.name}).Show()
+	if err != nil {
+		return nil, err
+	}
+
+	ips := map[string][]net.IP{}
+	for _, neighbor := range neighbors {
+		if neighbor.MAC == nil || neighbor.Addr.IsLinkLocalUnicast() {
+			continue
+		}
+
+		if neighbor.State&(ip.NeighborIPStateReachable|ip.NeighborIPStateStale|ip.NeighborIPStateDelay|ip.NeighborIPStateProbe|ip.NeighborIPStatePermanent) == 0 {
+			continue
+		}
+
+		ips[neighbor.MAC.String()] = append(ips[neighbor.MAC.String()], neighbor.Addr)
+	}
+
+	routes := []bgp.EVPNRoute{}
+	for _, tunnel := range tunnels {
+		routes = append(routes, bgp.EVPNRoute{VNI: tunnel.vni, NextHop: tunnel.local})
+
+		for key, mac := range macs {
+			routes = append(routes, bgp.EVPNRoute{VNI: tunnel.vni, MAC: mac, NextHop: tunnel.local})
+
+			for _, addr := range ips[key] {
+				routes = append(routes, bgp.EVPNRoute{VNI: tunnel.vni, MAC: mac, IP: addr, NextHop: tunnel.local})
+			}
+		}
+	}
+
+	return routes, nil
+}
+
+// evpnSyncTunnel programs the forwarding database of a tunnel from the EVPN routes of the other VTEPs.
+func (n *bridge) evpnSyncTunnel(tunnel evpnTunnel) error {
+	routes, err := n.state.BGP.EVPNRoutes(tunnel.vni)
+	if err != nil {
+		return fmt.Errorf("Failed getting EVPN routes: %w", err)
+	}
+
+	// Build the wanted entries, flood entries for inclusive multicast routes and unicast ones for MAC routes.
+	wanted := map[string]ip.FDB{}
+	for _, route := range routes {
+		if route.NextHop.Equal(tunnel.local) {
+			continue
+		}
+
+		mac := route.MAC
+		if mac == nil {
+			mac = evpnFloodMAC
+		}
+
+		entry := ip.FDB{DevName: tunnel.name, MAC: mac, Dst: route.NextHop}
+		wanted[fmt.Sprintf("%s/%s", entry.MAC, entry.Dst)] = entry
+	}
+
+	existing, err := (&ip.FDB{DevName: tunnel.name}).Show()
+	if err != nil {
+		return err
+	}
+
+	// Remove stale entries first so that moved MAC addresses get their new destination.
+	for _, entry := range existing {
+		key := fmt.Sprintf("%s/%s", entry.MAC, entry.Dst)
+
+		_, found := wanted[key]
+		if found {
+			delete(wanted, key)
+			continue
+		}
+
+		err = entry.Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add the new entries.
+	for _, entry := range wanted {
+		if entry.MAC.String() == evpnFloodMAC.String() {
+			err = entry.Append()
+		} else {
+			err = entry.Replace()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evpnSync advertises the local EVPN routes of the bridge and programs its tunnels from the routes of the other VTEPs.
+func (n *bridge) evpnSync() error {
+	tunnels := n.evpnTunnels()
+	if len(tunnels) == 0 {
+		return n.evpnClear()
+	}
+
+	routes, err := n.evpnLocalRoutes(tunnels)
+	if err != nil {
+		return fmt.Errorf("Failed getting local EVPN routes: %w", err)
+	}
+
+	err = n.state.BGP.SetEVPNRoutes(n.evpnOwner(), routes)
+	if err != nil {
+		return fmt.Errorf("Failed advertising EVPN routes: %w", err)
+	}
+
+	for _, tunnel := range tunnels {
+		if !InterfaceExists(tunnel.name) {
+			continue
+		}
+
+		err = n.evpnSyncTunnel(tunnel)
+		if err != nil {
+			return fmt.Errorf("Failed programming tunnel %q from EVPN routes: %w", tunnel.name, err)
+		}
+	}
+
+	return nil
+}
+
+// evpnClear withdraws the EVPN routes advertised for the bridge.
+func (n *bridge) evpnClear() error {
+	return n.state.BGP.RemoveEVPNRoutesByOwner(n.evpnOwner())
+}
+
+// EVPNRefresh advertises the MAC addresses learned by bridge networks using EVPN and programs their tunnels from
+// the routes of the other VTEPs.
+func EVPNRefresh(ctx context.Context, s *state.State) error {
+	var projectNetworks map[string]map[int64]api.Network
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading networks: %w", err)
+	}
+
+	var errs []error
+
+	for projectName, networks := range projectNetworks {
+		for _, network := range networks {
+			if network.Type != "bridge" || !evpnEnabled(network.Config) {
+				continue
+			}
+
+			netw, err := LoadByName(s, projectName, network.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed loading network %q: %w", network.Name, err))
+				continue
+			}
+
+			n, ok := netw.(*bridge)
+			if !ok || !n.isRunning() {
+				continue
+			}
+
+			err = n.evpnSync()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed refreshing EVPN of network %q: %w", network.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,303 @@
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	firewallDrivers "github.com/lxc/incus/v7/internal/server/firewall/drivers"
+	networkOVN "github.com/lxc/incus/v7/internal/server/network/ovn"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// flowExportDefaultSampling is the default sampling rate of the packets exported by OVN networks.
+const flowExportDefaultSampling = 400
+
+// flowExportTimeout is the time (in seconds) after which a flow without traffic stops being accounted on bridge
+// networks. It's longer than the export interval so that every active flow gets exported.
+const flowExportTimeout = 120
+
+// IPFIX constants (RFC 7011 and the IANA IPFIX information elements).
+const (
+	ipfixVersion         = 10
+	ipfixTemplateSetID   = 2
+	ipfixTemplateIPv4    = 256
+	ipfixTemplateIPv6    = 257
+	ipfixMaxRecordsBytes = 1200 // Keep the datagrams below the usual MTU.
+)
+
+// ipfixTemplates lists the information elements (ID and length) of the data records of each template.
+var ipfixTemplates = map[uint16][][2]uint16{
+	ipfixTemplateIPv4: {{8, 4}, {12, 4}, {4, 1}, {11, 2}, {85, 8}, {86, 8}},
+	ipfixTemplateIPv6: {{27, 16}, {28, 16}, {4, 1}, {11, 2}, {85, 8}, {86, 8}},
+}
+
+// flowExportSequences tracks the IPFIX sequence number of each bridge network, keyed by network ID.
+var flowExportSequences = map[int64]uint32{}
+var flowExportSequencesMu sync.Mutex
+
+// flowExportChanged returns whether any of the flow export settings is part of the changed keys.
+func flowExportChanged(changedKeys []string) bool {
+	for _, k := range changedKeys {
+		if strings.HasPrefix(k, "flow_export.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flowExportSetupChassis sets up the IPFIX collectors of the network on the integration bridge of this member.
+// The collectors form a flow sample collector set identified by the network ID, which the samples of the network's
+// logical switch get sent to. A nil config removes the collector set.
+func (n *ovn) flowExportSetupChassis(config map[string]string) error {
+	vswitch, err := n.state.OVS()
+	if err != nil {
+		return fmt.Errorf("Failed to connect to OVS: %w", err)
+	}
+
+	bridgeName := n.state.GlobalConfig.NetworkOVNIntegrationBridge()
+
+	if config["flow_export.ipfix.targets"] == "" {
+		err = vswitch.DeleteFlowSampleCollectorSet(context.TODO(), bridgeName, int(n.id))
+		if err != nil {
+			return fmt.Errorf("Failed removing flow sample collector set: %w", err)
+		}
+
+		return nil
+	}
+
+	targets := util.SplitNTrimSpace(config["flow_export.ipfix.targets"], ",", -1, true)
+	externalIDs := map[string]string{"incus-network": strconv.FormatInt(n.id, 10)}
+
+	err = vswitch.SetFlowSampleCollectorSet(context.TODO(), bridgeName, int(n.id), targets, externalIDs)
+	if err != nil {
+		return fmt.Errorf("Failed setting up flow sample collector set: %w", err)
+	}
+
+	return nil
+}
+
+// flowExportSetupSampling sets up the sampling of the packets going through the internal switch of the network.
+func (n *ovn) flowExportSetupSampling() error {
+	if n.config["flow_export.ipfix.targets"] == "" {
+		err := n.ovnnb.LogicalSwitchDeleteFlowSampling(context.TODO(), n.getIntSwitchName(), int(n.id))
+		if err != nil && !errors.Is(err, networkOVN.ErrNotFound) {
+			return fmt.Errorf("Failed removing flow sampling: %w", err)
+		}
+
+		return nil
+	}
+
+	sampling := flowExportDefaultSampling
+	if n.config["flow_export.sampling"] != "" {
+		var err error
+
+		sampling, err = strconv.Atoi(n.config["flow_export.sampling"])
+		if err != nil {
+			return fmt.Errorf("Invalid flow export sampling rate: %w", err)
+		}
+	}
+
+	err := n.ovnnb.LogicalSwitchSetFlowSampling(context.TODO(), n.getIntSwitchName(), int(n.id), sampling)
+	if err != nil {
+		if errors.Is(err, networkOVN.ErrNotSupported) {
+			return errors.New("Flow export requires an OVN version supporting ACL sampling")
+		}
+
+		return fmt.Errorf("Failed setting up flow sampling: %w", err)
+	}
+
+	return nil
+}
+
+// flowExportSetupFirewall sets up the accounting of the flows routed through the bridge when flows are exported.
+func (n *bridge) flowExportSetupFirewall() error {
+	err := n.state.Firewall.NetworkApplyFlowAccounting(n.name, n.config["flow_export.ipfix.targets"] != "", flowExportTimeout)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall flow accounting: %w", err)
+	}
+
+	return nil
+}
+
+// ipfixTemplateSet returns the IPFIX template set describing the data records of the exported flows.
+func ipfixTemplateSet() []byte {
+	set := binary.BigEndian.AppendUint16(nil, ipfixTemplateSetID)
+	set = binary.BigEndian.AppendUint16(set, 0) // Length, set below.
+
+	for _, templateID := range []uint16{ipfixTemplateIPv4, ipfixTemplateIPv6} {
+		set = binary.BigEndian.AppendUint16(set, templateID)
+		set = binary.BigEndian.AppendUint16(set, uint16(len(ipfixTemplates[templateID])))
+
+		for _, field := range ipfixTemplates[templateID] {
+			set = binary.BigEndian.AppendUint16(set, field[0])
+			set = binary.BigEndian.AppendUint16(set, field[1])
+		}
+	}
+
+	binary.BigEndian.PutUint16(set[2:4], uint16(len(set)))
+
+	return set
+}
+
+// ipfixRecord encodes a flow as an IPFIX data record, returning the ID of its template.
+func ipfixRecord(flow firewallDrivers.NetworkFlow) (uint16, []byte) {
+	templateID := uint16(ipfixTemplateIPv6)
+	source := flow.Source.To16()
+	destination := flow.Destination.To16()
+
+	if flow.Source.To4() != nil && flow.Destination.To4() != nil {
+		templateID = ipfixTemplateIPv4
+		source = flow.Source.To4()
+		destination = flow.Destination.To4()
+	}
+
+	record := slices.Concat(source, destination, []byte{flow.Protocol})
+	record = binary.BigEndian.AppendUint16(record, flow.DestinationPort)
+	record = binary.BigEndian.AppendUint64(record, flow.Bytes)
+	record = binary.BigEndian.AppendUint64(record, flow.Packets)
+
+	return templateID, record
+}
+
+// ipfixMessage encodes an IPFIX message carrying the templates and a data set of records of the same template.
+func ipfixMessage(domainID uint32, sequence uint32, exportTime time.Time, templateID uint16, records [][]byte) []byte {
+	msg := binary.BigEndian.AppendUint16(nil, ipfixVersion)
+	msg = binary.BigEndian.AppendUint16(msg, 0) // Length, set below.
+	msg = binary.BigEndian.AppendUint32(msg, uint32(exportTime.Unix()))
+	msg = binary.BigEndian.AppendUint32(msg, sequence)
+	msg = binary.BigEndian.AppendUint32(msg, domainID)
+	msg = append(msg, ipfixTemplateSet()...)
+
+	dataSetLength := 4
+	for _, record := range records {
+		dataSetLength += len(record)
+	}
+
+	msg = binary.BigEndian.AppendUint16(msg, templateID)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(dataSetLength))
+	for _, record := range records {
+		msg = append(msg, record...)
+	}
+
+	binary.BigEndian.PutUint16(msg[2:4], uint16(len(msg)))
+
+	return msg
+}
+
+// flowExport sends the flows accounted for the bridge to its IPFIX collectors.
+// The network ID is used as the observation domain and the counters are totals since the start of each flow.
+func (n *bridge) flowExport() error {
+	flows, err := n.state.Firewall.NetworkFlows(n.name)
+	if err != nil {
+		return err
+	}
+
+	// Group the records by template, splitting them across messages.
+	messageRecords := map[uint16][][][]byte{}
+	for _, flow := range flows {
+		templateID, record := ipfixRecord(flow)
+
+		batches := messageRecords[templateID]
+		if len(batches) == 0 || (len(batches[len(batches)-1])+1)*len(record) > ipfixMaxRecordsBytes {
+			batches = append(batches, [][]byte{})
+		}
+
+		batches[len(batches)-1] = append(batches[len(batches)-1], record)
+		messageRecords[templateID] = batches
+	}
+
+	flowExportSequencesMu.Lock()
+	sequence := flowExportSequences[n.id]
+	flowExportSequencesMu.Unlock()
+
+	now := time.Now()
+	messages := [][]byte{}
+	for _, templateID := range []uint16{ipfixTemplateIPv4, ipfixTemplateIPv6} {
+		for _, records := range messageRecords[templateID] {
+			messages = append(messages, ipfixMessage(uint32(n.id), sequence, now, templateID, records))
+			sequence += uint32(len(records))
+		}
+	}
+
+	flowExportSequencesMu.Lock()
+	flowExportSequences[n.id] = sequence
+	flowExportSequencesMu.Unlock()
+
+	var errs []error
+
+	for _, target := range util.SplitNTrimSpace(n.config["flow_export.ipfix.targets"], ",", -1, true) {
+		conn, err := net.Dial("udp", target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed connecting to IPFIX collector %q: %w", target, err))
+			continue
+		}
+
+		for _, msg := range messages {
+			_, err = conn.Write(msg)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed sending flows to IPFIX collector %q: %w", target, err))
+				break
+			}
+		}
+
+		_ = conn.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+// FlowExport sends the flows routed through the bridge networks exporting them to their IPFIX collectors.
+func FlowExport(ctx context.Context, s *state.State) error {
+	var projectNetworks map[string]map[int64]api.Network
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading networks: %w", err)
+	}
+
+	var errs []error
+
+	for projectName, networks := range projectNetworks {
+		for _, network := range networks {
+			if network.Type != "bridge" || network.Config["flow_export.ipfix.targets"] == "" {
+				continue
+			}
+
+			netw, err := LoadByName(s, projectName, network.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed loading network %q: %w", network.Name, err))
+				continue
+			}
+
+			n, ok := netw.(*bridge)
+			if !ok || !n.isRunning() {
+				continue
+			}
+
+			err = n.flowExport()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed exporting flows of network %q: %w", network.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
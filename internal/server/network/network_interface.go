@@ -63,6 +63,13 @@ type Network interface {
 	ForwardUpdate(listenAddress string, newForward api.NetworkForwardPut, clientType request.ClientType) error
 	ForwardDelete(listenAddress string, clientType request.ClientType) error
 
+	// Address Reservations.
+	ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error
+	ReservationUpdate(address string, newReservation api.NetworkReservationPut) error
+	ReservationDelete(address string, clientType request.ClientType) error
+	ReservationCheckAddress(address net.IP, projectName string, instName string) error
+	ReservedRanges() ([]iprange.Range, error)
+
 	// Load Balancers.
 	LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error
 	LoadBalancerUpdate(listenAddress string, newLoadBalancer api.NetworkLoadBalancerPut, clientType request.ClientType) error
@@ -70,8 +77,8 @@ type Network interface {
 	LoadBalancerDelete(listenAddress string, clientType request.ClientType) error
 
 	// Peerings.
-	PeerCreate(forward api.NetworkPeersPost) error
+	PeerCreate(forward api.NetworkPeersPost, clientType request.ClientType) error
 	PeerUpdate(peerName string, newPeer api.NetworkPeerPut) error
-	PeerDelete(peerName string) error
+	PeerDelete(peerName string, clientType request.ClientType) error
 	PeerUsedBy(peerName string) ([]string, error)
 }
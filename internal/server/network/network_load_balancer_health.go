@@ -0,0 +1,315 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	firewallDrivers "github.com/lxc/incus/v7/internal/server/firewall/drivers"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// Health status of a load balancer backend.
+const (
+	loadBalancerHealthOnline  = "online"
+	loadBalancerHealthOffline = "offline"
+	loadBalancerHealthUnknown = "unknown"
+)
+
+// loadBalancerBackendHealth is the recorded health of a load balancer backend port.
+type loadBalancerBackendHealth struct {
+	status    string
+	successes int
+	failures  int
+}
+
+var (
+	loadBalancerHealth            = map[string]*loadBalancerBackendHealth{}
+	loadBalancerHealthLastChecked = map[string]time.Time{}
+	loadBalancerHealthMu          sync.Mutex
+)
+
+func loadBalancerHealthKey(networkID int64, protocol string, address net.IP, port uint64) string {
+	return fmt.Sprintf("%d/%s/%s", networkID, protocol, net.JoinHostPort(address.String(), strconv.FormatUint(port, 10)))
+}
+
+// loadBalancerHealthStatus returns the last recorded health status of a load balancer backend port.
+func loadBalancerHealthStatus(networkID int64, protocol string, address net.IP, port uint64) string {
+	loadBalancerHealthMu.Lock()
+	defer loadBalancerHealthMu.Unlock()
+
+	health, ok := loadBalancerHealth[loadBalancerHealthKey(networkID, protocol, address, port)]
+	if !ok {
+		return loadBalancerHealthUnknown
+	}
+
+	return health.status
+}
+
+// loadBalancerHealthConfig returns the health check interval, timeout and the number of consecutive
+// successful and failed checks needed to change the status of a backend.
+func loadBalancerHealthConfig(config map[string]string) (time.Duration, time.Duration, int, int) {
+	interval, err := strconv.Atoi(config["healthcheck.interval"])
+	if err != nil {
+		interval = 10
+	}
+
+	timeout, err := strconv.Atoi(config["healthcheck.timeout"])
+	if err != nil {
+		timeout = 30
+	}
+
+	successCount, err := strconv.Atoi(config["healthcheck.success_count"])
+	if err != nil {
+		successCount = 3
+	}
+
+	failureCount, err := strconv.Atoi(config["healthcheck.failure_count"])
+	if err != nil {
+		failureCount = 3
+	}
+
+	return time.Duration(interval) * time.Second, time.Duration(timeout) * time.Second, successCount, failureCount
+}
+
+// loadBalancerHealthRecord records the result of a backend port check.
+// Returns whether the health status of the backend port changed.
+func loadBalancerHealthRecord(key string, checkErr error, successCount int, failureCount int) bool {
+	loadBalancerHealthMu.Lock()
+	defer loadBalancerHealthMu.Unlock()
+
+	health, ok := loadBalancerHealth[key]
+	if !ok {
+		health = &loadBalancerBackendHealth{status: loadBalancerHealthUnknown}
+		loadBalancerHealth[key] = health
+	}
+
+	previous := health.status
+
+	if checkErr != nil {
+		health.successes = 0
+		health.failures++
+		if health.failures >= failureCount {
+			health.status = loadBalancerHealthOffline
+		}
+	} else {
+		health.failures = 0
+		health.successes++
+		if health.successes >= successCount {
+			health.status = loadBalancerHealthOnline
+		}
+	}
+
+	return health.status != previous
+}
+
+// loadBalancerTargetUsable returns whether traffic can be sent to the target of a load balancer.
+// Backends are only left out once they have been found to be offline.
+func loadBalancerTargetUsable(networkID int64, config map[string]string, protocol string, target firewallDrivers.LoadBalancerTarget) bool {
+	if util.IsFalseOrEmpty(config["healthcheck"]) {
+		return true
+	}
+
+	return loadBalancerHealthStatus(networkID, protocol, target.Address, target.Port) != loadBalancerHealthOffline
+}
+
+// LoadBalancerHealthCheck runs the due health checks of the load balancers of the bridge networks running on
+// this member. The firewall rules of a network are refreshed when the health of one of its backends changes.
+func LoadBalancerHealthCheck(ctx context.Context, s *state.State) error {
+	now := time.Now()
+
+	// Get the load balancers with health checking enabled, by network.
+	loadBalancers := map[string]map[string][]*api.NetworkLoadBalancer{}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNetworks, err := tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		for projectName, networks := range projectNetworks {
+			for networkID, network := range networks {
+				if network.Type != "bridge" {
+					continue
+				}
+
+				dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+					NetworkID: &networkID,
+				})
+				if err != nil {
+					return err
+				}
+
+				for _, dbLoadBalancer := range dbLoadBalancers {
+					lb, err := dbLoadBalancer.ToAPI(ctx, tx.Tx())
+					if err != nil {
+						return err
+					}
+
+					if util.IsFalseOrEmpty(lb.Config["healthcheck"]) {
+						continue
+					}
+
+					if loadBalancers[projectName] == nil {
+						loadBalancers[projectName] = map[string][]*api.NetworkLoadBalancer{}
+					}
+
+					loadBalancers[projectName][network.Name] = append(loadBalancers[projectName][network.Name], lb)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	seenLoadBalancers := map[string]bool{}
+	seenBackends := map[string]bool{}
+
+	for projectName, networks := range loadBalancers {
+		for networkName, lbs := range networks {
+			n, err := LoadByName(s, projectName, networkName)
+			if err != nil {
+				logger.Warn("Failed loading network for load balancer health checks", logger.Ctx{"project": projectName, "network": networkName, "err": err})
+				continue
+			}
+
+			br, ok := n.(*bridge)
+			if !ok || !br.isRunning() {
+				continue
+			}
+
+			changed := false
+			for _, lb := range lbs {
+				lbChanged, err := br.loadBalancerHealthCheck(ctx, lb, now, seenLoadBalancers, seenBackends)
+				if err != nil {
+					br.logger.Warn("Failed checking load balancer health", logger.Ctx{"listenAddress": lb.ListenAddress, "err": err})
+					continue
+				}
+
+				changed = changed || lbChanged
+			}
+
+			if !changed {
+				continue
+			}
+
+			err = br.loadBalancerSetupFirewall()
+			if err != nil {
+				br.logger.Error("Failed applying firewall load balancers", logger.Ctx{"err": err})
+			}
+
+			err = br.loadBalancerBGPSetupPrefixes()
+			if err != nil {
+				br.logger.Error("Failed applying BGP prefixes for load balancers", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	// Forget about load balancers and backends which are gone or no longer have health checking enabled.
+	loadBalancerHealthMu.Lock()
+	defer loadBalancerHealthMu.Unlock()
+
+	for key := range loadBalancerHealthLastChecked {
+		if !seenLoadBalancers[key] {
+			delete(loadBalancerHealthLastChecked, key)
+		}
+	}
+
+	for key := range loadBalancerHealth {
+		if !seenBackends[key] {
+			delete(loadBalancerHealth, key)
+		}
+	}
+
+	return nil
+}
+
+// loadBalancerHealthCheck checks the backends of the load balancer if they are due for a health check.
+// Only TCP backends are checked, UDP backends are always considered usable.
+// Returns whether the health status of any of the backends changed.
+func (n *bridge) loadBalancerHealthCheck(ctx context.Context, lb *api.NetworkLoadBalancer, now time.Time, seenLoadBalancers map[string]bool, seenBackends map[string]bool) (bool, error) {
+	listenAddressNet, err := ParseIPToNet(lb.ListenAddress)
+	if err != nil {
+		return false, err
+	}
+
+	portMaps, err := n.loadBalancerValidate(listenAddressNet.IP, &lb.NetworkLoadBalancerPut)
+	if err != nil {
+		return false, err
+	}
+
+	// Get the distinct backend ports to check.
+	targets := map[string]firewallDrivers.LoadBalancerTarget{}
+	for _, fwLoadBalancer := range n.loadBalancerConvertToFirewallLoadBalancers(listenAddressNet.IP, portMaps) {
+		if fwLoadBalancer.Protocol != "tcp" {
+			continue
+		}
+
+		for _, target := range fwLoadBalancer.Targets {
+			key := loadBalancerHealthKey(n.id, fwLoadBalancer.Protocol, target.Address, target.Port)
+			targets[key] = target
+			seenBackends[key] = true
+		}
+	}
+
+	lbKey := fmt.Sprintf("%d/%s", n.id, lb.ListenAddress)
+	seenLoadBalancers[lbKey] = true
+
+	interval, timeout, successCount, failureCount := loadBalancerHealthConfig(lb.Config)
+
+	loadBalancerHealthMu.Lock()
+	due := now.Sub(loadBalancerHealthLastChecked[lbKey]) >= interval
+	if due {
+		loadBalancerHealthLastChecked[lbKey] = now
+	}
+
+	loadBalancerHealthMu.Unlock()
+
+	if !due {
+		return false, nil
+	}
+
+	var changed bool
+	var changedMu sync.Mutex
+	wg := sync.WaitGroup{}
+
+	for key, target := range targets {
+		wg.Go(func() {
+			address := net.JoinHostPort(target.Address.String(), strconv.FormatUint(target.Port, 10))
+			dialer := net.Dialer{Timeout: timeout}
+
+			conn, checkErr := dialer.DialContext(ctx, "tcp", address)
+			if checkErr == nil {
+				_ = conn.Close()
+			}
+
+			// Don't count checks interrupted by the daemon shutting down.
+			if ctx.Err() != nil {
+				return
+			}
+
+			if loadBalancerHealthRecord(key, checkErr, successCount, failureCount) {
+				n.logger.Info("Load balancer backend health changed", logger.Ctx{"listenAddress": lb.ListenAddress, "target": address, "status": loadBalancerHealthStatus(n.id, "tcp", target.Address, target.Port)})
+
+				changedMu.Lock()
+				changed = true
+				changedMu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return changed, nil
+}
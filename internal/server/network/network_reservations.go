@@ -0,0 +1,407 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/lxc/incus/v7/internal/iprange"
+	"github.com/lxc/incus/v7/internal/server/cluster/request"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// parseReservationAddress parses a reservation address (a single IP or a range in the form start-end).
+// If allowedNets are supplied, the address must fall entirely within one of them.
+// The returned range always uses the 16-byte form of the addresses, End is nil for single addresses.
+func parseReservationAddress(address string, allowedNets ...*net.IPNet) (*iprange.Range, error) {
+	if strings.Contains(address, "-") {
+		r, err := parseIPRange(address, allowedNets...)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Start = r.Start.To16()
+		r.End = r.End.To16()
+
+		if r.Start.Equal(r.End) {
+			r.End = nil
+		}
+
+		return r, nil
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("Invalid IP address %q", address)
+	}
+
+	if len(allowedNets) > 0 {
+		matchFound := false
+		for _, allowedNet := range allowedNets {
+			if allowedNet != nil && allowedNet.Contains(ip) {
+				matchFound = true
+				break
+			}
+		}
+
+		if !matchFound {
+			return nil, fmt.Errorf("IP address %q does not fall within any of the allowed networks %v", address, allowedNets)
+		}
+	}
+
+	return &iprange.Range{Start: ip.To16()}, nil
+}
+
+// reservationAddress returns the canonical string form of a reservation range.
+func reservationAddress(r *iprange.Range) string {
+	if r.End == nil {
+		return r.Start.String()
+	}
+
+	return fmt.Sprintf("%s-%s", r.Start.String(), r.End.String())
+}
+
+// normaliseReservationAddress returns the canonical form of a reservation address, or the address
+// unchanged if it can't be parsed.
+func normaliseReservationAddress(address string) string {
+	r, err := parseReservationAddress(address)
+	if err != nil {
+		return address
+	}
+
+	return reservationAddress(r)
+}
+
+// rangeBounds returns the first and last address of the range as netip.Addr.
+func rangeBounds(r iprange.Range) (netip.Addr, netip.Addr) {
+	start, _ := netip.AddrFromSlice(r.Start)
+	start = start.Unmap()
+
+	end := start
+	if r.End != nil {
+		end, _ = netip.AddrFromSlice(r.End)
+		end = end.Unmap()
+	}
+
+	return start, end
+}
+
+// rangesOverlap returns whether the two supplied ranges share at least one address.
+func rangesOverlap(a iprange.Range, b iprange.Range) bool {
+	aStart, aEnd := rangeBounds(a)
+	bStart, bEnd := rangeBounds(b)
+
+	if aStart.Is4() != bStart.Is4() {
+		return false
+	}
+
+	return aStart.Compare(bEnd) <= 0 && bStart.Compare(aEnd) <= 0
+}
+
+// subtractRanges returns the supplied ranges with any address covered by one of the exclude ranges removed.
+// Ranges of a different IP family than an exclude range are left untouched.
+func subtractRanges(ranges []iprange.Range, exclude []iprange.Range) []iprange.Range {
+	result := ranges
+
+	for _, ex := range exclude {
+		exStart, exEnd := rangeBounds(ex)
+		remaining := make([]iprange.Range, 0, len(result))
+
+		for _, r := range result {
+			start, end := rangeBounds(r)
+
+			if !rangesOverlap(r, ex) {
+				remaining = append(remaining, r)
+				continue
+			}
+
+			if start.Less(exStart) {
+				remaining = append(remaining, iprange.Range{Start: net.ParseIP(start.String()), End: net.ParseIP(exStart.Prev().String())})
+			}
+
+			if exEnd.Less(end) {
+				remaining = append(remaining, iprange.Range{Start: net.ParseIP(exEnd.Next().String()), End: net.ParseIP(end.String())})
+			}
+		}
+
+		result = remaining
+	}
+
+	return result
+}
+
+// reservationSubnets returns the subnets of the network that addresses can be reserved from.
+func (n *common) reservationSubnets() []*net.IPNet {
+	subnets := []*net.IPNet{}
+
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		_, subnet, err := net.ParseCIDR(n.config[key])
+		if err != nil {
+			continue
+		}
+
+		subnets = append(subnets, subnet)
+	}
+
+	return subnets
+}
+
+// reservations returns the address reservation records of the network.
+func (n *common) reservations() ([]dbCluster.NetworkReservation, error) {
+	var records []dbCluster.NetworkReservation
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		networkID := n.ID()
+		records, err = dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{
+			NetworkID: &networkID,
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading network address reservations: %w", err)
+	}
+
+	return records, nil
+}
+
+// ReservedRanges returns the address ranges reserved on the network.
+func (n *common) ReservedRanges() ([]iprange.Range, error) {
+	records, err := n.reservations()
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]iprange.Range, 0, len(records))
+	for _, record := range records {
+		r, err := parseReservationAddress(record.Address)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing network address reservation %q: %w", record.Address, err)
+		}
+
+		ranges = append(ranges, *r)
+	}
+
+	return ranges, nil
+}
+
+// reservationOwner returns the reservation owner referring to the instance of the project.
+// Instances of the network's project are referred to by name, others as "<project>/<instance>".
+func (n *common) reservationOwner(projectName string, instName string) string {
+	if projectName == n.Project() {
+		return instName
+	}
+
+	return projectName + "/" + instName
+}
+
+// reservationNormaliseOwner strips the network's own project from a project qualified owner.
+func (n *common) reservationNormaliseOwner(owner string) string {
+	return strings.TrimPrefix(owner, n.Project()+"/")
+}
+
+// ReservationCheckAddress returns an error if the address is reserved on the network for another owner
+// than the instance of the project supplied.
+func (n *common) ReservationCheckAddress(address net.IP, projectName string, instName string) error {
+	records, err := n.reservations()
+	if err != nil {
+		return err
+	}
+
+	owner := n.reservationOwner(projectName, instName)
+
+	for _, record := range records {
+		r, err := parseReservationAddress(record.Address)
+		if err != nil {
+			return fmt.Errorf("Failed parsing network address reservation %q: %w", record.Address, err)
+		}
+
+		if r.ContainsIP(address.To16()) && record.Owner != owner {
+			return api.StatusErrorf(http.StatusConflict, "IP address %q is reserved on network %q", address.String(), n.name)
+		}
+	}
+
+	return nil
+}
+
+// reservationValidate checks that the reservation range can be used on the network.
+// The reservation with the address to skip (if any) is ignored when looking for overlaps.
+func (n *common) reservationValidate(r *iprange.Range, owner string, skipAddress string) error {
+	// Check the reservation doesn't cover the network's own addresses.
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		ip, _, err := net.ParseCIDR(n.config[key])
+		if err != nil {
+			continue
+		}
+
+		if r.ContainsIP(ip.To16()) {
+			return api.StatusErrorf(http.StatusBadRequest, "Reservation %q covers the network address %q", reservationAddress(r), ip.String())
+		}
+	}
+
+	// Check the reservation doesn't overlap with an existing one.
+	records, err := n.reservations()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.Address == skipAddress {
+			continue
+		}
+
+		existing, err := parseReservationAddress(record.Address)
+		if err != nil {
+			return fmt.Errorf("Failed parsing network address reservation %q: %w", record.Address, err)
+		}
+
+		if rangesOverlap(*r, *existing) {
+			return api.StatusErrorf(http.StatusConflict, "Reservation %q overlaps with existing reservation %q", reservationAddress(r), record.Address)
+		}
+	}
+
+	// Check that no other instance already uses a static address within the reservation.
+	return UsedByInstanceDevices(n.state, n.Project(), n.Name(), n.Type(), func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		if n.reservationOwner(inst.Project, inst.Name) == owner {
+			return nil
+		}
+
+		for _, key := range []string{"ipv4.address", "ipv6.address"} {
+			ip := net.ParseIP(nicAddressIP(nicConfig[key]))
+			if ip != nil && r.ContainsIP(ip.To16()) {
+				return api.StatusErrorf(http.StatusConflict, "IP address %q is already used by instance %q in project %q", ip.String(), inst.Name, inst.Project)
+			}
+		}
+
+		return nil
+	})
+}
+
+// reservationCreateRecord validates and stores a new reservation, normalising its address.
+func (n *common) reservationCreateRecord(reservation *api.NetworkReservationsPost) error {
+	subnets := n.reservationSubnets()
+	if len(subnets) == 0 {
+		return api.StatusErrorf(http.StatusBadRequest, "Network %q has no managed subnet to reserve addresses from", n.name)
+	}
+
+	r, err := parseReservationAddress(reservation.Address, subnets...)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid reservation address: %v", err)
+	}
+
+	reservation.Address = reservationAddress(r)
+	reservation.Owner = n.reservationNormaliseOwner(reservation.Owner)
+
+	err = n.reservationValidate(r, reservation.Owner, "")
+	if err != nil {
+		return err
+	}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.CreateNetworkReservation(ctx, tx.Tx(), dbCluster.NetworkReservation{
+			NetworkID:   n.ID(),
+			Address:     reservation.Address,
+			Owner:       reservation.Owner,
+			Description: reservation.Description,
+		})
+
+		return err
+	})
+}
+
+// reservationDeleteRecord removes a reservation and returns the deleted record.
+func (n *common) reservationDeleteRecord(address string) (*dbCluster.NetworkReservation, error) {
+	var record *dbCluster.NetworkReservation
+
+	address = normaliseReservationAddress(address)
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		records, err := dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{
+			NetworkID: &networkID,
+			Address:   &address,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(records) != 1 {
+			return api.StatusErrorf(http.StatusNotFound, "Network reservation not found")
+		}
+
+		record = &records[0]
+
+		return dbCluster.DeleteNetworkReservation(ctx, tx.Tx(), networkID, address)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// reservationRestoreRecord recreates a previously deleted reservation record.
+func (n *common) reservationRestoreRecord(record *dbCluster.NetworkReservation) {
+	_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.CreateNetworkReservation(ctx, tx.Tx(), *record)
+		return err
+	})
+}
+
+// ReservationCreate returns ErrNotImplemented for drivers that do not support address reservations.
+func (n *common) ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// ReservationUpdate updates the owner and description of an address reservation.
+func (n *common) ReservationUpdate(address string, newReservation api.NetworkReservationPut) error {
+	address = normaliseReservationAddress(address)
+
+	records, err := n.reservations()
+	if err != nil {
+		return err
+	}
+
+	var record *dbCluster.NetworkReservation
+	for i := range records {
+		if records[i].Address == address {
+			record = &records[i]
+			break
+		}
+	}
+
+	if record == nil {
+		return api.StatusErrorf(http.StatusNotFound, "Network reservation not found")
+	}
+
+	newReservation.Owner = n.reservationNormaliseOwner(newReservation.Owner)
+
+	// A change of owner must not take addresses away from instances already using them.
+	if record.Owner != newReservation.Owner {
+		r, err := parseReservationAddress(record.Address)
+		if err != nil {
+			return err
+		}
+
+		err = n.reservationValidate(r, newReservation.Owner, record.Address)
+		if err != nil {
+			return err
+		}
+	}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.UpdateNetworkReservation(ctx, tx.Tx(), record.ID, newReservation.Owner, newReservation.Description)
+	})
+}
+
+// ReservationDelete returns ErrNotImplemented for drivers that do not support address reservations.
+func (n *common) ReservationDelete(address string, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
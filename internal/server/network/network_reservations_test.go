@@ -0,0 +1,84 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/lxc/incus/v7/internal/iprange"
+)
+
+func Example_parseReservationAddress() {
+	_, allowedNet, _ := net.ParseCIDR("10.1.1.0/24")
+
+	addresses := []string{
+		"10.1.1.10",
+		"10.1.1.10-10.1.1.20",
+		"10.1.1.10-10.1.1.10",
+		"fd42::10",
+		"10.1.2.10",
+		"10.1.1.250-10.1.2.10",
+		"10.1.1.20-10.1.1.10",
+		"foo",
+	}
+
+	for _, address := range addresses {
+		r, err := parseReservationAddress(address, allowedNet)
+		if err != nil {
+			fmt.Printf("Err: %v\n", err)
+			continue
+		}
+
+		fmt.Println(reservationAddress(r))
+	}
+
+	// Output:
+	// 10.1.1.10
+	// 10.1.1.10-10.1.1.20
+	// 10.1.1.10
+	// Err: IP address "fd42::10" does not fall within any of the allowed networks [10.1.1.0/24]
+	// Err: IP address "10.1.2.10" does not fall within any of the allowed networks [10.1.1.0/24]
+	// Err: IP range "10.1.1.250-10.1.2.10" does not fall within any of the allowed networks [10.1.1.0/24]
+	// Err: Start IP "10.1.1.20" must be less than End IP "10.1.1.10"
+	// Err: Invalid IP address "foo"
+}
+
+func Example_subtractRanges() {
+	parse := func(list string) []iprange.Range {
+		ranges := []iprange.Range{}
+		for _, address := range strings.Split(list, ",") {
+			r, _ := parseReservationAddress(address)
+			ranges = append(ranges, *r)
+		}
+
+		return ranges
+	}
+
+	tests := [][2]string{
+		{"10.1.1.2-10.1.1.254", "10.1.1.10"},
+		{"10.1.1.2-10.1.1.254", "10.1.1.10-10.1.1.20,10.1.1.100-10.1.1.254"},
+		{"10.1.1.2-10.1.1.100,10.1.1.150-10.1.1.200", "10.1.1.50-10.1.1.160"},
+		{"10.1.1.2-10.1.1.254", "10.1.1.1-10.1.1.254"},
+		{"10.1.1.2-10.1.1.254", "fd42::1-fd42::ff"},
+		{"fd42::2-fd42::ffff", "fd42::2"},
+	}
+
+	for _, test := range tests {
+		result := subtractRanges(parse(test[0]), parse(test[1]))
+
+		parts := make([]string, len(result))
+		for i, r := range result {
+			parts[i] = fmt.Sprintf("%s-%s", r.Start.String(), r.End.String())
+		}
+
+		fmt.Printf("%s minus %s: [%s]\n", test[0], test[1], strings.Join(parts, ", "))
+	}
+
+	// Output:
+	// 10.1.1.2-10.1.1.254 minus 10.1.1.10: [10.1.1.2-10.1.1.9, 10.1.1.11-10.1.1.254]
+	// 10.1.1.2-10.1.1.254 minus 10.1.1.10-10.1.1.20,10.1.1.100-10.1.1.254: [10.1.1.2-10.1.1.9, 10.1.1.21-10.1.1.99]
+	// 10.1.1.2-10.1.1.100,10.1.1.150-10.1.1.200 minus 10.1.1.50-10.1.1.160: [10.1.1.2-10.1.1.49, 10.1.1.161-10.1.1.200]
+	// 10.1.1.2-10.1.1.254 minus 10.1.1.1-10.1.1.254: []
+	// 10.1.1.2-10.1.1.254 minus fd42::1-fd42::ff: [10.1.1.2-10.1.1.254]
+	// fd42::2-fd42::ffff minus fd42::2: [fd42::3-fd42::ffff]
+}
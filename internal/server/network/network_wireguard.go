@@ -0,0 +1,661 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/cluster"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/ip"
+	"github.com/lxc/incus/v7/internal/server/state"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/revert"
+	"github.com/lxc/incus/v7/shared/subprocess"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// wireguardListenPortDefault is the default UDP port used by WireGuard.
+const wireguardListenPortDefault = 51820
+
+// wireguardKeyLifetimeDefault is the default number of days the WireGuard key of a cluster member is used for.
+const wireguardKeyLifetimeDefault = 30
+
+// wireguardMTU is the MTU of the WireGuard interface, leaving room for the WireGuard headers over an IPv6 underlay.
+const wireguardMTU = 1420
+
+// wireguardBridgeMTU is the default MTU of bridges extended over WireGuard, leaving room for the GRE headers of the
+// tunnels between cluster members.
+const wireguardBridgeMTU = 1360
+
+// wireguardMaxNameLength is the maximum length of the name of a bridge extended over WireGuard, so that the names of
+// the tunnels to the other cluster members fit in an interface name.
+const wireguardMaxNameLength = 8
+
+// wireguardKeepalive is the interval in seconds at which keepalives are sent to peers, so that they can reach
+// members behind NAT.
+const wireguardKeepalive = "25"
+
+// generateWireguardKey returns a new WireGuard private key and its public key, base64 encoded.
+func generateWireguardKey() (string, string, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed generating WireGuard key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.Bytes()), base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// wireguardPublicKey returns the public key of a base64 encoded WireGuard private key.
+func wireguardPublicKey(privateKey string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil {
+		return "", fmt.Errorf("Failed decoding WireGuard key: %w", err)
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return "", fmt.Errorf("Failed parsing WireGuard key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// wireguardInterface returns the name of the WireGuard interface of the bridge.
+func (n *bridge) wireguardInterface() string {
+	return fmt.Sprintf("%s-wg", n.name)
+}
+
+// wireguardTunnelInterface returns the name of the tunnel to a cluster member over the WireGuard interface.
+func (n *bridge) wireguardTunnelInterface(nodeID int64) string {
+	return fmt.Sprintf("%s-wg%d", n.name, nodeID)
+}
+
+// wireguardKeyPath returns the path of the WireGuard private key of the bridge on this cluster member.
+func (n *bridge) wireguardKeyPath() string {
+	return internalUtil.VarPath("networks", n.name, "wireguard.key")
+}
+
+// wireguardOverlayAddress returns the address of a cluster member on the WireGuard mesh of the bridge.
+// Addresses come from a unique local prefix derived from the network ID, with the member ID as the interface ID.
+func (n *bridge) wireguardOverlayAddress(nodeID int64) net.IP {
+	hash := sha256.Sum256(fmt.Appendf(nil, "wireguard.%d", n.ID()))
+
+	address := make(net.IP, net.IPv6len)
+	address[0] = 0xfd
+	copy(address[1:6], hash[:5])
+	binary.BigEndian.PutUint64(address[8:], uint64(nodeID))
+
+	return address
+}
+
+// wireguardListenPort returns the UDP port used by WireGuard.
+func (n *bridge) wireguardListenPort() int {
+	port, err := strconv.Atoi(n.config["wireguard.port"])
+	if err != nil {
+		return wireguardListenPortDefault
+	}
+
+	return port
+}
+
+// wireguardEndpoint returns the endpoint other cluster members reach this member on.
+// It is empty if the member has neither a WireGuard address nor a cluster address.
+func (n *bridge) wireguardEndpoint() string {
+	address := n.config["wireguard.address"]
+	if address == "" {
+		host, _, err := net.SplitHostPort(n.state.LocalConfig.ClusterAddress())
+		if err != nil {
+			return ""
+		}
+
+		address = host
+	}
+
+	return net.JoinHostPort(address, strconv.Itoa(n.wireguardListenPort()))
+}
+
+// wireguardTunnelMTU returns the MTU of the tunnels to the other cluster members, matching the bridge MTU.
+func (n *bridge) wireguardTunnelMTU() uint32 {
+	mtu, err := strconv.ParseUint(n.config["bridge.mtu"], 10, 32)
+	if err != nil {
+		return wireguardBridgeMTU
+	}
+
+	return uint32(mtu)
+}
+
+// wireguardSetup creates the WireGuard interface of the bridge and connects it to the other cluster members.
+func (n *bridge) wireguardSetup() error {
+	iface := n.wireguardInterface()
+	nodeID := n.state.DB.Cluster.GetNodeID()
+
+	// Load the key of the member, generating it on first use.
+	keyPath := n.wireguardKeyPath()
+	var publicKey string
+
+	content, err := os.ReadFile(keyPath)
+	if err == nil {
+		publicKey, err = wireguardPublicKey(string(content))
+		if err != nil {
+			return err
+		}
+	} else if errors.Is(err, os.ErrNotExist) {
+		var privateKey string
+		privateKey, publicKey, err = generateWireguardKey()
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(keyPath, []byte(privateKey+"\n"), 0o600)
+		if err != nil {
+			return fmt.Errorf("Failed writing WireGuard key: %w", err)
+		}
+	} else {
+		return fmt.Errorf("Failed reading WireGuard key: %w", err)
+	}
+
+	// Create the WireGuard interface.
+	if !InterfaceExists(iface) {
+		wg := &ip.Wireguard{Link: ip.Link{Name: iface, MTU: wireguardMTU}}
+		err = wg.Add()
+		if err != nil {
+			return fmt.Errorf("Failed creating WireGuard interface: %w", err)
+		}
+
+		err = localUtil.SysctlSet(fmt.Sprintf("net/ipv6/conf/%s/disable_ipv6", iface), "0")
+		if err != nil {
+			return err
+		}
+
+		addr := &ip.Addr{
+			DevName: iface,
+			Address: &net.IPNet{IP: n.wireguardOverlayAddress(nodeID), Mask: net.CIDRMask(64, 128)},
+			Family:  ip.FamilyV6,
+		}
+
+		err = addr.Add()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = subprocess.RunCommand("wg", "set", iface, "listen-port", strconv.Itoa(n.wireguardListenPort()), "private-key", keyPath)
+	if err != nil {
+		return fmt.Errorf("Failed configuring WireGuard interface: %w", err)
+	}
+
+	link := &ip.Link{Name: iface}
+	err = link.SetUp()
+	if err != nil {
+		return err
+	}
+
+	// Let the other cluster members know about the key and endpoint of this member.
+	err = n.wireguardPublish(publicKey, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return n.wireguardSyncPeers()
+}
+
+// wireguardPublish records the public key and endpoint of this cluster member in the database.
+func (n *bridge) wireguardPublish(publicKey string, now time.Time) error {
+	networkID := n.ID()
+	nodeID := n.state.DB.Cluster.GetNodeID()
+	endpoint := n.wireguardEndpoint()
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		peers, err := dbCluster.GetNetworkWireguardPeers(ctx, tx.Tx(), dbCluster.NetworkWireguardPeerFilter{NetworkID: &networkID, NodeID: &nodeID})
+		if err != nil {
+			return err
+		}
+
+		if len(peers) == 0 {
+			_, err = dbCluster.CreateNetworkWireguardPeer(ctx, tx.Tx(), dbCluster.NetworkWireguardPeer{
+				NetworkID:   networkID,
+				NodeID:      nodeID,
+				PublicKey:   publicKey,
+				Endpoint:    endpoint,
+				UpdatedDate: now,
+			})
+
+			return err
+		}
+
+		peer := peers[0]
+		if peer.PublicKey == publicKey && peer.Endpoint == endpoint {
+			return nil
+		}
+
+		// Only a new key resets its age.
+		if peer.PublicKey != publicKey {
+			peer.UpdatedDate = now
+		}
+
+		return dbCluster.UpdateNetworkWireguardPeer(ctx, tx.Tx(), peer.ID, publicKey, endpoint, peer.UpdatedDate)
+	})
+}
+
+// wireguardPeers returns the WireGuard endpoints of the cluster members for the bridge.
+func (n *bridge) wireguardPeers() ([]dbCluster.NetworkWireguardPeer, error) {
+	var peers []dbCluster.NetworkWireguardPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		networkID := n.ID()
+		peers, err = dbCluster.GetNetworkWireguardPeers(ctx, tx.Tx(), dbCluster.NetworkWireguardPeerFilter{NetworkID: &networkID})
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading WireGuard peers: %w", err)
+	}
+
+	return peers, nil
+}
+
+// wireguardSyncPeers configures the other cluster members as WireGuard peers and bridges them over GRE tunnels.
+// The tunnels are isolated bridge ports so that frames received from a member never get forwarded to another one,
+// avoiding loops in the full mesh.
+func (n *bridge) wireguardSyncPeers() error {
+	iface := n.wireguardInterface()
+	nodeID := n.state.DB.Cluster.GetNodeID()
+	localAddress := n.wireguardOverlayAddress(nodeID)
+
+	peers, err := n.wireguardPeers()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"set", iface}
+	publicKeys := map[string]bool{}
+	tunnels := map[string]bool{}
+
+	for _, peer := range peers {
+		if peer.NodeID == nodeID {
+			continue
+		}
+
+		publicKeys[peer.PublicKey] = true
+		args = append(args, "peer", peer.PublicKey, "allowed-ips", fmt.Sprintf("%s/128", n.wireguardOverlayAddress(peer.NodeID)), "persistent-keepalive", wireguardKeepalive)
+		if peer.Endpoint != "" {
+			args = append(args, "endpoint", peer.Endpoint)
+		}
+	}
+
+	// Remove the peers which left the mesh or replaced their key.
+	output, err := subprocess.RunCommand("wg", "show", iface, "peers")
+	if err != nil {
+		return fmt.Errorf("Failed listing WireGuard peers: %w", err)
+	}
+
+	for _, publicKey := range strings.Fields(output) {
+		if !publicKeys[publicKey] {
+			args = append(args, "peer", publicKey, "remove")
+		}
+	}
+
+	if len(args) > 2 {
+		_, err = subprocess.RunCommand("wg", args...)
+		if err != nil {
+			return fmt.Errorf("Failed configuring WireGuard peers: %w", err)
+		}
+	}
+
+	// Bridge the other cluster members.
+	for _, peer := range peers {
+		if peer.NodeID == nodeID {
+			continue
+		}
+
+		tunName := n.wireguardTunnelInterface(peer.NodeID)
+		tunnels[tunName] = true
+
+		if InterfaceExists(tunName) {
+			continue
+		}
+
+		gretap := &ip.Gretap{
+			Link:   ip.Link{Name: tunName, MTU: n.wireguardTunnelMTU()},
+			Local:  localAddress,
+			Remote: n.wireguardOverlayAddress(peer.NodeID),
+		}
+
+		err = gretap.Add()
+		if err != nil {
+			return fmt.Errorf("Failed creating tunnel to cluster member %d: %w", peer.NodeID, err)
+		}
+
+		err = AttachInterface(n.state, n.name, tunName)
+		if err != nil {
+			return err
+		}
+
+		tunLink := &ip.Link{Name: tunName}
+		err = tunLink.BridgeLinkSetIsolated(true)
+		if err != nil {
+			return err
+		}
+
+		err = tunLink.SetUp()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Delete the tunnels to the members which left the mesh.
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return err
+	}
+
+	for _, netIf := range ifaces {
+		if !strings.HasPrefix(netIf.Name, iface) || tunnels[netIf.Name] {
+			continue
+		}
+
+		tunLink, err := ip.LinkByName(netIf.Name)
+		if err != nil || tunLink.Master != n.name || tunLink.Kind != "ip6gretap" {
+			continue
+		}
+
+		err = tunLink.Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wireguardRotateKey replaces the WireGuard key of this cluster member once it reaches the end of its lifetime.
+// The new key is published before being used, after which the other members are asked to pick it up right away.
+func (n *bridge) wireguardRotateKey(now time.Time) error {
+	lifetimeDays, err := strconv.Atoi(n.config["wireguard.key_lifetime"])
+	if err != nil {
+		lifetimeDays = wireguardKeyLifetimeDefault
+	}
+
+	nodeID := n.state.DB.Cluster.GetNodeID()
+
+	peers, err := n.wireguardPeers()
+	if err != nil {
+		return err
+	}
+
+	// The key of the member is published when setting up the bridge.
+	idx := slices.IndexFunc(peers, func(peer dbCluster.NetworkWireguardPeer) bool { return peer.NodeID == nodeID })
+	if idx < 0 || now.Sub(peers[idx].UpdatedDate) < time.Duration(lifetimeDays)*24*time.Hour {
+		return nil
+	}
+
+	oldPeer := peers[idx]
+
+	n.logger.Info("Replacing WireGuard key")
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	keyPath := n.wireguardKeyPath()
+	oldPrivateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("Failed reading WireGuard key: %w", err)
+	}
+
+	privateKey, publicKey, err := generateWireguardKey()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(keyPath, []byte(privateKey+"\n"), 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed writing WireGuard key: %w", err)
+	}
+
+	reverter.Add(func() { _ = os.WriteFile(keyPath, oldPrivateKey, 0o600) })
+
+	err = n.wireguardPublish(publicKey, now)
+	if err != nil {
+		return fmt.Errorf("Failed publishing WireGuard key: %w", err)
+	}
+
+	reverter.Add(func() { _ = n.wireguardPublish(oldPeer.PublicKey, oldPeer.UpdatedDate) })
+
+	_, err = subprocess.RunCommand("wg", "set", n.wireguardInterface(), "private-key", keyPath)
+	if err != nil {
+		return fmt.Errorf("Failed configuring WireGuard interface: %w", err)
+	}
+
+	reverter.Success()
+
+	// Have the other members switch to the new key rather than wait for their next refresh.
+	notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	err = notifier(func(client incus.InstanceServer) error {
+		_, _, err := client.RawQuery(http.MethodPost, "/internal/networks/wireguard-sync", nil, "")
+		return err
+	})
+	if err != nil {
+		n.logger.Warn("Failed notifying cluster members of the new WireGuard key", logger.Ctx{"err": err})
+	}
+
+	return nil
+}
+
+// wireguardStop deletes the WireGuard interface of the bridge.
+// The tunnels to the other cluster members are deleted along with the other bridge ports.
+func (n *bridge) wireguardStop() error {
+	iface := n.wireguardInterface()
+	if !InterfaceExists(iface) {
+		return nil
+	}
+
+	link := &ip.Link{Name: iface}
+	return link.Delete()
+}
+
+// wireguardClear removes this cluster member from the WireGuard mesh of the bridge, forgetting its key.
+func (n *bridge) wireguardClear() error {
+	err := n.wireguardStop()
+	if err != nil {
+		return err
+	}
+
+	if !util.PathExists(n.wireguardKeyPath()) {
+		return nil
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := dbCluster.DeleteNetworkWireguardPeer(ctx, tx.Tx(), n.ID(), n.state.DB.Cluster.GetNodeID())
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed removing WireGuard peer: %w", err)
+	}
+
+	return os.Remove(n.wireguardKeyPath())
+}
+
+// wireguardState returns the state of the WireGuard mesh of the bridge.
+func (n *bridge) wireguardState() (*api.NetworkStateWireGuard, error) {
+	output, err := subprocess.RunCommand("wg", "show", n.wireguardInterface(), "dump")
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting WireGuard state: %w", err)
+	}
+
+	// Map the public keys to the cluster members.
+	members := map[string]string{}
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		peers, err := dbCluster.GetNetworkWireguardPeers(ctx, tx.Tx(), dbCluster.NetworkWireguardPeerFilter{NetworkID: &networkID})
+		if err != nil {
+			return err
+		}
+
+		nodes, err := tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, peer := range peers {
+			for _, node := range nodes {
+				if node.ID == peer.NodeID {
+					members[peer.PublicKey] = node.Name
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := &api.NetworkStateWireGuard{Peers: []api.NetworkStateWireGuardPeer{}}
+
+	// The first line describes the interface, the following ones its peers.
+	for i, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\t")
+
+		if i == 0 {
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("Unexpected WireGuard interface state %q", line)
+			}
+
+			state.PublicKey = fields[1]
+			state.ListenPort, _ = strconv.Atoi(fields[2])
+			continue
+		}
+
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("Unexpected WireGuard peer state %q", line)
+		}
+
+		peer := api.NetworkStateWireGuardPeer{
+			Member:    members[fields[0]],
+			PublicKey: fields[0],
+		}
+
+		if fields[2] != "(none)" {
+			peer.Endpoint = fields[2]
+		}
+
+		handshake, _ := strconv.ParseInt(fields[4], 10, 64)
+		if handshake > 0 {
+			peer.LatestHandshake = time.Unix(handshake, 0)
+		}
+
+		peer.BytesReceived, _ = strconv.ParseInt(fields[5], 10, 64)
+		peer.BytesSent, _ = strconv.ParseInt(fields[6], 10, 64)
+
+		state.Peers = append(state.Peers, peer)
+	}
+
+	return state, nil
+}
+
+// WireguardRefresh connects the bridges extended over WireGuard to the cluster members which joined their mesh or
+// replaced their key, and replaces the keys of this member which reached the end of their lifetime.
+func WireguardRefresh(ctx context.Context, s *state.State) error {
+	now := time.Now()
+
+	return wireguardForEach(ctx, s, func(n *bridge) error {
+		var errs []error
+
+		err := n.wireguardRotateKey(now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed replacing WireGuard key of network %q: %w", n.name, err))
+		}
+
+		err = n.wireguardSyncPeers()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed refreshing WireGuard mesh of network %q: %w", n.name, err))
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// WireguardSync connects the bridges extended over WireGuard to the cluster members which joined their mesh or
+// replaced their key.
+func WireguardSync(ctx context.Context, s *state.State) error {
+	return wireguardForEach(ctx, s, func(n *bridge) error {
+		err := n.wireguardSyncPeers()
+		if err != nil {
+			return fmt.Errorf("Failed refreshing WireGuard mesh of network %q: %w", n.name, err)
+		}
+
+		return nil
+	})
+}
+
+// wireguardForEach calls f for each bridge extended over WireGuard running on this cluster member.
+func wireguardForEach(ctx context.Context, s *state.State, f func(n *bridge) error) error {
+	var projectNetworks map[string]map[int64]api.Network
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading networks: %w", err)
+	}
+
+	var errs []error
+
+	for projectName, networks := range projectNetworks {
+		for _, network := range networks {
+			if network.Type != "bridge" || util.IsFalseOrEmpty(network.Config["wireguard.enabled"]) {
+				continue
+			}
+
+			netw, err := LoadByName(s, projectName, network.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed loading network %q: %w", network.Name, err))
+				continue
+			}
+
+			n, ok := netw.(*bridge)
+			if !ok || !n.isRunning() || !InterfaceExists(n.wireguardInterface()) {
+				continue
+			}
+
+			err = f(n)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
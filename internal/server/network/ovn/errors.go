@@ -17,3 +17,6 @@ var ErrTooMany = errors.New("too many objects found")
 
 // ErrNotManaged indicates that a DB record wasn't created by Incus.
 var ErrNotManaged = errors.New("object not incus-managed")
+
+// ErrNotSupported indicates that the OVN database doesn't support the requested feature.
+var ErrNotSupported = errors.New("feature not supported by the OVN database")
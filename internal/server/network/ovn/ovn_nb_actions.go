@@ -83,6 +83,8 @@ const (
 	ovnExtIDIncusProjectID  = "incus_project_id"
 	ovnExtIDIncusPortGroup  = "incus_port_group"
 	ovnExtIDIncusLocation   = "incus_location"
+
+	ovnExtIDIncusFlowSampling = "incus_flow_sampling"
 )
 
 // OVNIPv6RAOpts IPv6 router advertisements options that can be applied to a router.
@@ -1790,6 +1792,219 @@ func (o *NB) UpdateLogicalSwitchACLRules(ctx context.Context, switchName OVNSwit
 	return nil
 }
 
+// LogicalSwitchSetFlowSampling samples the packets going through the logical switch to the OVS flow sample
+// collector set with the given ID, one packet out of sampling. The collector set ID also identifies the samples.
+// This relies on the ACL sampling tables of recent northbound databases which aren't part of the generated schema,
+// so those rows are managed through raw database operations.
+func (o *NB) LogicalSwitchSetFlowSampling(ctx context.Context, switchName OVNSwitch, collectorSetID int, sampling int) error {
+	_, ok := o.client.Schema().Tables["Sample_Collector"]
+	if !ok {
+		return ErrNotSupported
+	}
+
+	ls, err := o.GetLogicalSwitch(ctx, switchName)
+	if err != nil {
+		return err
+	}
+
+	// Remove any existing sampling of the switch.
+	operations, err := o.logicalSwitchFlowSamplingDeleteOperations(ctx, ls, collectorSetID)
+	if err != nil {
+		return err
+	}
+
+	// Find a collector ID which isn't used by another switch, those are limited to 255.
+	resp, err := o.client.Transact(ctx, ovsdb.Operation{
+		Op:      ovsdb.OperationSelect,
+		Table:   "Sample_Collector",
+		Columns: []string{"id", "external_ids"},
+	})
+	if err != nil {
+		return err
+	}
+
+	usedIDs := map[int]bool{}
+	for _, row := range resp[0].Rows {
+		externalIDs, _ := row["external_ids"].(ovsdb.OvsMap)
+		if externalIDs.GoMap[ovnExtIDIncusSwitch] == string(switchName) {
+			continue
+		}
+
+		id, _ := row["id"].(float64)
+		usedIDs[int(id)] = true
+	}
+
+	collectorID := 1
+	for usedIDs[collectorID] {
+		collectorID++
+	}
+
+	if collectorID > 255 {
+		return errors.New("No free OVN sample collector ID")
+	}
+
+	// Add the collector and the sample referencing it.
+	operations = append(operations,
+		ovsdb.Operation{
+			Op:       ovsdb.OperationInsert,
+			Table:    "Sample_Collector",
+			UUIDName: "collector",
+			Row: ovsdb.Row{
+				"id":           collectorID,
+				"name":         string(switchName),
+				"probability":  max(1, 65535/sampling),
+				"set_id":       collectorSetID,
+				"external_ids": ovsdb.OvsMap{GoMap: map[any]any{ovnExtIDIncusSwitch: string(switchName)}},
+			},
+		},
+		ovsdb.Operation{
+			Op:       ovsdb.OperationInsert,
+			Table:    "Sample",
+			UUIDName: "sample",
+			Row: ovsdb.Row{
+				"collector": ovsdb.OvsSet{GoSet: []any{ovsdb.UUID{GoUUID: "collector"}}},
+				"metadata":  collectorSetID,
+			},
+		},
+	)
+
+	// Sample every packet once the regular ACLs have been applied, the pass action leaves their verdict alone.
+	acl := ovnNB.ACL{
+		UUID:      "acl",
+		Action:    ovnNB.ACLActionPass,
+		Direction: ovnNB.ACLDirectionFromLport,
+		Priority:  0,
+		Match:     "1",
+		Options:   map[string]string{"apply-after-lb": "true"},
+		ExternalIDs: map[string]string{
+			ovnExtIDIncusSwitch:       string(switchName),
+			ovnExtIDIncusFlowSampling: "true",
+		},
+	}
+
+	createOps, err := o.client.Create(&acl)
+	if err != nil {
+		return err
+	}
+
+	createOps[0].Row["sample_new"] = ovsdb.UUID{GoUUID: "sample"}
+	createOps[0].Row["sample_est"] = ovsdb.UUID{GoUUID: "sample"}
+	operations = append(operations, createOps...)
+
+	updateOps, err := o.client.Where(ls).Mutate(ls, ovsModel.Mutation{
+		Field:   &ls.ACLs,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   []string{acl.UUID},
+	})
+	if err != nil {
+		return err
+	}
+
+	operations = append(operations, updateOps...)
+
+	// Apply the database changes.
+	resp, err = o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LogicalSwitchDeleteFlowSampling stops sampling the packets going through the logical switch.
+func (o *NB) LogicalSwitchDeleteFlowSampling(ctx context.Context, switchName OVNSwitch, collectorSetID int) error {
+	ls, err := o.GetLogicalSwitch(ctx, switchName)
+	if err != nil {
+		return err
+	}
+
+	operations, err := o.logicalSwitchFlowSamplingDeleteOperations(ctx, ls, collectorSetID)
+	if err != nil {
+		return err
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	// Apply the database changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// logicalSwitchFlowSamplingDeleteOperations returns the operations removing the sampling ACL of the logical switch
+// along with its sample and collector. The rows are deleted explicitly, as garbage collection would only happen
+// after the referential integrity of the collector deletion gets checked.
+func (o *NB) logicalSwitchFlowSamplingDeleteOperations(ctx context.Context, ls *ovnNB.LogicalSwitch, collectorSetID int) ([]ovsdb.Operation, error) {
+	operations := []ovsdb.Operation{}
+
+	for _, aclUUID := range ls.ACLs {
+		acl := ovnNB.ACL{UUID: aclUUID}
+
+		err := o.get(ctx, &acl)
+		if err != nil {
+			return nil, err
+		}
+
+		if acl.ExternalIDs[ovnExtIDIncusFlowSampling] == "" {
+			continue
+		}
+
+		updateOps, err := o.client.Where(ls).Mutate(ls, ovsModel.Mutation{
+			Field:   &ls.ACLs,
+			Mutator: ovsdb.MutateOperationDelete,
+			Value:   []string{acl.UUID},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, updateOps...)
+
+		deleteOps, err := o.client.Where(&acl).Delete()
+		if err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, deleteOps...)
+	}
+
+	// Without the sampling tables, there can't be anything else to remove.
+	_, ok := o.client.Schema().Tables["Sample_Collector"]
+	if !ok {
+		return operations, nil
+	}
+
+	operations = append(operations,
+		ovsdb.Operation{
+			Op:    ovsdb.OperationDelete,
+			Table: "Sample",
+			Where: []ovsdb.Condition{ovsdb.NewCondition("metadata", ovsdb.ConditionEqual, collectorSetID)},
+		},
+		ovsdb.Operation{
+			Op:    ovsdb.OperationDelete,
+			Table: "Sample_Collector",
+			Where: []ovsdb.Condition{ovsdb.NewCondition("external_ids", ovsdb.ConditionIncludes, ovsdb.OvsMap{GoMap: map[any]any{ovnExtIDIncusSwitch: ls.Name}})},
+		},
+	)
+
+	return operations, nil
+}
+
 // logicalSwitchPortQoSRules returns the QoS rule UUIDs belonging to a logical switch port.
 func (o *NB) logicalSwitchPortQoSRules(ctx context.Context, portName OVNSwitchPort) ([]string, error) {
 	var qosRules []ovnNB.QoS
@@ -796,3 +796,125 @@ func (o *VSwitch) GetOVNSouthboundDBRemoteAddress(ctx context.Context) (string,
 
 	return val, nil
 }
+
+// SetFlowSampleCollectorSet creates or updates the flow sample collector set with the given ID on a bridge,
+// sending the packets sampled for the set to the IPFIX targets.
+func (o *VSwitch) SetFlowSampleCollectorSet(ctx context.Context, bridgeName string, id int, targets []string, externalIDs map[string]string) error {
+	bridge := &ovsSwitch.Bridge{Name: bridgeName}
+
+	err := o.client.Get(ctx, bridge)
+	if err != nil {
+		return err
+	}
+
+	ipfix := ovsSwitch.IPFIX{
+		UUID:        "ipfix",
+		Targets:     targets,
+		ExternalIDs: externalIDs,
+	}
+
+	operations, err := o.client.Create(&ipfix)
+	if err != nil {
+		return err
+	}
+
+	collectorSet, err := o.getFlowSampleCollectorSet(ctx, bridge.UUID, id)
+	if err != nil && !errors.Is(err, ovsdbClient.ErrNotFound) {
+		return err
+	}
+
+	if collectorSet != nil {
+		// Point the existing set to the new exporter, the previous one gets garbage collected.
+		collectorSet.IPFIX = &ipfix.UUID
+		collectorSet.ExternalIDs = externalIDs
+
+		ops, err := o.client.Where(collectorSet).Update(collectorSet, &collectorSet.IPFIX, &collectorSet.ExternalIDs)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, ops...)
+	} else {
+		collectorSet = &ovsSwitch.FlowSampleCollectorSet{
+			UUID:        "collector",
+			Bridge:      bridge.UUID,
+			ID:          id,
+			IPFIX:       &ipfix.UUID,
+			ExternalIDs: externalIDs,
+		}
+
+		ops, err := o.client.Create(collectorSet)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, ops...)
+	}
+
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFlowSampleCollectorSet removes the flow sample collector set with the given ID from a bridge.
+// No error is returned if the set doesn't exist.
+func (o *VSwitch) DeleteFlowSampleCollectorSet(ctx context.Context, bridgeName string, id int) error {
+	bridge := &ovsSwitch.Bridge{Name: bridgeName}
+
+	err := o.client.Get(ctx, bridge)
+	if err != nil {
+		return err
+	}
+
+	collectorSet, err := o.getFlowSampleCollectorSet(ctx, bridge.UUID, id)
+	if err != nil {
+		if errors.Is(err, ovsdbClient.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	operations, err := o.client.Where(collectorSet).Delete()
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getFlowSampleCollectorSet returns the flow sample collector set with the given ID on the bridge.
+func (o *VSwitch) getFlowSampleCollectorSet(ctx context.Context, bridgeUUID string, id int) (*ovsSwitch.FlowSampleCollectorSet, error) {
+	collectorSets := []ovsSwitch.FlowSampleCollectorSet{}
+
+	err := o.client.WhereCache(func(collectorSet *ovsSwitch.FlowSampleCollectorSet) bool {
+		return collectorSet.Bridge == bridgeUUID && collectorSet.ID == id
+	}).List(ctx, &collectorSets)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(collectorSets) == 0 {
+		return nil, ovsdbClient.ErrNotFound
+	}
+
+	return &collectorSets[0], nil
+}
@@ -0,0 +1,271 @@
+package zone
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/dns"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// zoneKeyPropagationDelay is how long a new zone signing key is published before it signs the zone, and how long
+// a replaced one stays published, so that resolvers caching the DNSKEY records and signatures can validate the zone.
+const zoneKeyPropagationDelay = 24 * time.Hour
+
+// zoneKeyDefaultLifetime is the default number of days a zone signing key is used for.
+const zoneKeyDefaultLifetime = 30
+
+// updateZoneKeys generates the DNSSEC keys of a zone when DNSSEC gets enabled and removes them when it gets disabled.
+func updateZoneKeys(ctx context.Context, tx *sql.Tx, zoneID int, zoneName string, config map[string]string) error {
+	keys, err := dbCluster.GetNetworkZoneKeys(ctx, tx, dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &zoneID})
+	if err != nil {
+		return err
+	}
+
+	if util.IsFalseOrEmpty(config["dnssec.enabled"]) {
+		for _, key := range keys {
+			err = dbCluster.DeleteNetworkZoneKey(ctx, tx, zoneID, key.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if len(keys) > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, role := range []string{dbCluster.NetworkZoneKeyRoleKSK, dbCluster.NetworkZoneKeyRoleZSK} {
+		err = createZoneKey(ctx, tx, zoneID, zoneName, role, dbCluster.NetworkZoneKeyStateActive, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createZoneKey generates a new DNSSEC key for the zone.
+func createZoneKey(ctx context.Context, tx *sql.Tx, zoneID int, zoneName string, role string, state string, now time.Time) error {
+	key, err := dns.GenerateZoneKey(zoneName, role == dbCluster.NetworkZoneKeyRoleKSK)
+	if err != nil {
+		return err
+	}
+
+	_, err = dbCluster.CreateNetworkZoneKey(ctx, tx, dbCluster.NetworkZoneKey{
+		NetworkZoneID: zoneID,
+		Role:          role,
+		State:         state,
+		PublicKey:     key.PublicKey,
+		PrivateKey:    key.PrivateKey,
+		CreationDate:  now,
+		UpdatedDate:   now,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed storing DNSSEC key: %w", err)
+	}
+
+	return nil
+}
+
+// Keys returns the DNSSEC keys of the zone, or nil if the zone isn't signed.
+func (d *zone) Keys() ([]dns.ZoneKey, error) {
+	if util.IsFalseOrEmpty(d.info.Config["dnssec.enabled"]) {
+		return nil, nil
+	}
+
+	var dbKeys []dbCluster.NetworkZoneKey
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		zoneID := int(d.id)
+		dbKeys, err = dbCluster.GetNetworkZoneKeys(ctx, tx.Tx(), dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &zoneID})
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	keys := make([]dns.ZoneKey, 0, len(dbKeys))
+	for _, dbKey := range dbKeys {
+		keys = append(keys, dns.ZoneKey{
+			KSK:        dbKey.Role == dbCluster.NetworkZoneKeyRoleKSK,
+			Active:     dbKey.State == dbCluster.NetworkZoneKeyStateActive,
+			PublicKey:  dbKey.PublicKey,
+			PrivateKey: dbKey.PrivateKey,
+		})
+	}
+
+	return keys, nil
+}
+
+// DS returns the DS records of the key signing keys of the zone, to be added to the parent zone.
+func (d *zone) DS() ([]string, error) {
+	keys, err := d.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []string{}
+	for _, key := range keys {
+		if !key.KSK {
+			continue
+		}
+
+		ds, err := key.DS()
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, ds)
+	}
+
+	return records, nil
+}
+
+// zoneKeyRollover is a step of the rollover of the zone signing keys of a zone.
+type zoneKeyRollover struct {
+	// remove lists the IDs of the retired keys to remove.
+	remove []int
+
+	// activate is the ID of the published key to start signing with, zero if none.
+	activate int
+
+	// retire lists the IDs of the active keys replaced by the activated key.
+	retire []int
+
+	// publish is true when a new key should be published.
+	publish bool
+}
+
+// planZoneKeyRollover returns the next step of the rollover of the zone signing keys among the given keys.
+// A new key is published ahead of the end of the lifetime of the active one, it replaces it once it had time to
+// propagate and the replaced key is removed once its signatures are gone from caches.
+func planZoneKeyRollover(keys []dbCluster.NetworkZoneKey, now time.Time, lifetime time.Duration) zoneKeyRollover {
+	var step zoneKeyRollover
+	var active []int
+	var published *dbCluster.NetworkZoneKey
+
+	for _, key := range keys {
+		if key.Role != dbCluster.NetworkZoneKeyRoleZSK {
+			continue
+		}
+
+		switch key.State {
+		case dbCluster.NetworkZoneKeyStateActive:
+			if now.Sub(key.UpdatedDate) >= max(lifetime-zoneKeyPropagationDelay, 0) {
+				step.publish = true
+			}
+
+			active = append(active, key.ID)
+		case dbCluster.NetworkZoneKeyStatePublished:
+			published = &key
+		case dbCluster.NetworkZoneKeyStateRetired:
+			if now.Sub(key.UpdatedDate) >= zoneKeyPropagationDelay {
+				step.remove = append(step.remove, key.ID)
+			}
+		}
+	}
+
+	// Only one new key is published at a time.
+	if published != nil {
+		step.publish = false
+
+		if now.Sub(published.UpdatedDate) >= zoneKeyPropagationDelay {
+			step.activate = published.ID
+			step.retire = active
+		}
+	}
+
+	return step
+}
+
+// RolloverKeys replaces the zone signing key of the zone once it reaches the end of its lifetime.
+// The new key is published ahead of being used and the old one is kept published for a while after being replaced.
+// Key signing keys aren't rolled over automatically as this requires updating the DS records of the parent zone.
+func (d *zone) RolloverKeys(now time.Time) error {
+	if util.IsFalseOrEmpty(d.info.Config["dnssec.enabled"]) {
+		return nil
+	}
+
+	lifetimeDays, err := strconv.Atoi(d.info.Config["dnssec.zsk_lifetime"])
+	if err != nil {
+		lifetimeDays = zoneKeyDefaultLifetime
+	}
+
+	lifetime := time.Duration(lifetimeDays) * 24 * time.Hour
+
+	changed := false
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		zoneID := int(d.id)
+		keys, err := dbCluster.GetNetworkZoneKeys(ctx, tx.Tx(), dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &zoneID})
+		if err != nil {
+			return err
+		}
+
+		step := planZoneKeyRollover(keys, now, lifetime)
+
+		// Forget about replaced keys once their signatures are gone from caches.
+		for _, id := range step.remove {
+			d.logger.Info("Removing retired DNSSEC zone signing key")
+
+			err = dbCluster.DeleteNetworkZoneKey(ctx, tx.Tx(), zoneID, id)
+			if err != nil {
+				return err
+			}
+
+			changed = true
+		}
+
+		// Start signing with the new key once it had time to propagate.
+		if step.activate != 0 {
+			d.logger.Info("Activating new DNSSEC zone signing key")
+
+			err = dbCluster.UpdateNetworkZoneKeyState(ctx, tx.Tx(), step.activate, dbCluster.NetworkZoneKeyStateActive, now)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range step.retire {
+				err = dbCluster.UpdateNetworkZoneKeyState(ctx, tx.Tx(), id, dbCluster.NetworkZoneKeyStateRetired, now)
+				if err != nil {
+					return err
+				}
+			}
+
+			changed = true
+		}
+
+		// Publish a new key ahead of the end of the lifetime of the current one.
+		if step.publish {
+			d.logger.Info("Publishing new DNSSEC zone signing key")
+
+			err = createZoneKey(ctx, tx.Tx(), zoneID, d.info.Name, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStatePublished, now)
+			if err != nil {
+				return err
+			}
+
+			changed = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed rolling over DNSSEC keys: %w", err)
+	}
+
+	if changed {
+		d.state.DNS.InvalidateZone(d.info.Name)
+	}
+
+	return nil
+}
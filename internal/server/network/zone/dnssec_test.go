@@ -0,0 +1,160 @@
+package zone
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+)
+
+func TestPlanZoneKeyRollover(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	lifetime := 30 * 24 * time.Hour
+
+	key := func(id int, role string, state string, age time.Duration) dbCluster.NetworkZoneKey {
+		return dbCluster.NetworkZoneKey{ID: id, Role: role, State: state, UpdatedDate: now.Add(-age)}
+	}
+
+	ksk := key(1, dbCluster.NetworkZoneKeyRoleKSK, dbCluster.NetworkZoneKeyStateActive, 365*24*time.Hour)
+
+	tests := []struct {
+		name     string
+		keys     []dbCluster.NetworkZoneKey
+		lifetime time.Duration
+		expected zoneKeyRollover
+	}{
+		{
+			name: "Fresh active key",
+			keys: []dbCluster.NetworkZoneKey{ksk, key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, time.Hour)},
+		},
+		{
+			name:     "Active key nearing the end of its lifetime",
+			keys:     []dbCluster.NetworkZoneKey{ksk, key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, lifetime-zoneKeyPropagationDelay)},
+			expected: zoneKeyRollover{publish: true},
+		},
+		{
+			name: "Published key still propagating",
+			keys: []dbCluster.NetworkZoneKey{
+				ksk,
+				key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, lifetime-time.Hour),
+				key(3, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStatePublished, zoneKeyPropagationDelay-time.Hour),
+			},
+		},
+		{
+			name: "Published key propagated",
+			keys: []dbCluster.NetworkZoneKey{
+				ksk,
+				key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, lifetime),
+				key(3, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStatePublished, zoneKeyPropagationDelay),
+			},
+			expected: zoneKeyRollover{activate: 3, retire: []int{2}},
+		},
+		{
+			name: "Retired key still cached",
+			keys: []dbCluster.NetworkZoneKey{
+				ksk,
+				key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateRetired, time.Hour),
+				key(3, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, time.Hour),
+			},
+		},
+		{
+			name: "Retired key expired from caches",
+			keys: []dbCluster.NetworkZoneKey{
+				ksk,
+				key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateRetired, zoneKeyPropagationDelay),
+				key(3, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, zoneKeyPropagationDelay),
+			},
+			expected: zoneKeyRollover{remove: []int{2}},
+		},
+		{
+			name: "Lifetime shorter than the propagation delay",
+			keys: []dbCluster.NetworkZoneKey{
+				ksk,
+				key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, 0),
+			},
+			lifetime: time.Hour,
+			expected: zoneKeyRollover{publish: true},
+		},
+		{
+			name: "Old key signing key",
+			keys: []dbCluster.NetworkZoneKey{
+				key(1, dbCluster.NetworkZoneKeyRoleKSK, dbCluster.NetworkZoneKeyStateRetired, 365*24*time.Hour),
+				key(2, dbCluster.NetworkZoneKeyRoleZSK, dbCluster.NetworkZoneKeyStateActive, time.Hour),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyLifetime := lifetime
+			if tt.lifetime != 0 {
+				keyLifetime = tt.lifetime
+			}
+
+			assert.Equal(t, tt.expected, planZoneKeyRollover(tt.keys, now, keyLifetime))
+		})
+	}
+}
+
+func TestPlanZoneKeyRollover_fullRollover(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	lifetime := 30 * 24 * time.Hour
+
+	keys := []dbCluster.NetworkZoneKey{
+		{ID: 1, Role: dbCluster.NetworkZoneKeyRoleKSK, State: dbCluster.NetworkZoneKeyStateActive, UpdatedDate: start},
+		{ID: 2, Role: dbCluster.NetworkZoneKeyRoleZSK, State: dbCluster.NetworkZoneKeyStateActive, UpdatedDate: start},
+	}
+
+	// apply updates the keys the way RolloverKeys does.
+	apply := func(step zoneKeyRollover, now time.Time) {
+		keys = slices.DeleteFunc(keys, func(key dbCluster.NetworkZoneKey) bool { return slices.Contains(step.remove, key.ID) })
+
+		for i, key := range keys {
+			if key.ID == step.activate {
+				keys[i].State = dbCluster.NetworkZoneKeyStateActive
+				keys[i].UpdatedDate = now
+			} else if slices.Contains(step.retire, key.ID) {
+				keys[i].State = dbCluster.NetworkZoneKeyStateRetired
+				keys[i].UpdatedDate = now
+			}
+		}
+
+		if step.publish {
+			keys = append(keys, dbCluster.NetworkZoneKey{ID: len(keys) + 1, Role: dbCluster.NetworkZoneKeyRoleZSK, State: dbCluster.NetworkZoneKeyStatePublished, UpdatedDate: now})
+		}
+	}
+
+	// The new key is published a day ahead of the end of the lifetime of the current one, replaces it a day
+	// later and the replaced key is removed after another day.
+	steps := []struct {
+		day      int
+		expected zoneKeyRollover
+	}{
+		{day: 1},
+		{day: 28},
+		{day: 29, expected: zoneKeyRollover{publish: true}},
+		{day: 29},
+		{day: 30, expected: zoneKeyRollover{activate: 3, retire: []int{2}}},
+		{day: 30},
+		{day: 31, expected: zoneKeyRollover{remove: []int{2}}},
+		{day: 31},
+	}
+
+	for _, step := range steps {
+		now := start.Add(time.Duration(step.day) * 24 * time.Hour)
+
+		got := planZoneKeyRollover(keys, now, lifetime)
+		assert.Equal(t, step.expected, got, "day %d", step.day)
+
+		apply(got, now)
+	}
+
+	// The KSK is left alone and the new ZSK signs the zone.
+	assert.Equal(t, []dbCluster.NetworkZoneKey{
+		{ID: 1, Role: dbCluster.NetworkZoneKeyRoleKSK, State: dbCluster.NetworkZoneKeyStateActive, UpdatedDate: start},
+		{ID: 3, Role: dbCluster.NetworkZoneKeyRoleZSK, State: dbCluster.NetworkZoneKeyStateActive, UpdatedDate: start.Add(30 * 24 * time.Hour)},
+	}, keys)
+}
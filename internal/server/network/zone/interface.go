@@ -2,8 +2,10 @@ package zone
 
 import (
 	"strings"
+	"time"
 
 	"github.com/lxc/incus/v7/internal/server/cluster/request"
+	"github.com/lxc/incus/v7/internal/server/dns"
 	"github.com/lxc/incus/v7/internal/server/state"
 	"github.com/lxc/incus/v7/shared/api"
 )
@@ -22,6 +24,14 @@ type NetworkZone interface {
 	Content() (*strings.Builder, error)
 	SOA() (*strings.Builder, error)
 
+	// DNSSEC.
+	Keys() ([]dns.ZoneKey, error)
+	DS() ([]string, error)
+	RolloverKeys(now time.Time) error
+
+	// Dynamic updates.
+	PushRecords() (bool, error)
+
 	// Records.
 	AddRecord(req api.NetworkZoneRecordsPost) error
 	GetRecords() ([]api.NetworkZoneRecord, error)
@@ -152,6 +152,11 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 			return err
 		}
 
+		err = updateZoneKeys(ctx, tx.Tx(), int(id), zoneInfo.Name, zoneInfo.Config)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -164,6 +169,9 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 		return err
 	}
 
+	// Stop remembering the name as not being a zone.
+	s.DNS.InvalidateZone(zoneInfo.Name)
+
 	return nil
 }
 
@@ -60,6 +60,9 @@ func (d *zone) AddRecord(req api.NetworkZoneRecordsPost) error {
 		return err
 	}
 
+	// Stop answering queries from the previous zone content.
+	d.state.DNS.InvalidateZone(d.info.Name)
+
 	return nil
 }
 
@@ -190,6 +193,9 @@ func (d *zone) UpdateRecord(name string, req api.NetworkZoneRecordPut, clientTyp
 		return err
 	}
 
+	// Stop answering queries from the previous zone content.
+	d.state.DNS.InvalidateZone(d.info.Name)
+
 	return nil
 }
 
@@ -226,6 +232,9 @@ func (d *zone) DeleteRecord(name string) error {
 		return err
 	}
 
+	// Stop answering queries from the previous zone content.
+	d.state.DNS.InvalidateZone(d.info.Name)
+
 	return nil
 }
 
@@ -198,6 +198,33 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 	//  shortdesc: Whether to generate records for NAT-ed subnets
 	rules["network.nat"] = validate.Optional(validate.IsBool)
 
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.enabled)
+	//
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to sign the zone with DNSSEC
+	rules["dnssec.enabled"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.nsec3)
+	//
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use NSEC3 rather than NSEC records to prove the absence of records
+	rules["dnssec.nsec3"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.zsk_lifetime)
+	//
+	// ---
+	//  type: integer
+	//  required: no
+	//  defaultdesc: `30`
+	//  shortdesc: Number of days a zone signing key is used before being replaced
+	rules["dnssec.zsk_lifetime"] = validate.Optional(validate.IsInRange(1, 3650))
+
 	// Validate peer config.
 	for k := range info.Config {
 		if !strings.HasPrefix(k, "peers.") {
@@ -233,6 +260,41 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 		}
 	}
 
+	// Validate push config.
+	for k := range info.Config {
+		if !strings.HasPrefix(k, "push.") {
+			continue
+		}
+
+		// Validate server name in key.
+		fields := strings.Split(k, ".")
+		if len(fields) != 3 {
+			return fmt.Errorf("Invalid network zone configuration key %q", k)
+		}
+
+		pushKey := fields[2]
+
+		// Add the correct validation rule for the dynamic field based on last part of key.
+		switch pushKey {
+		case "address":
+			// gendoc:generate(entity=network_zone, group=common, key=push.NAME.address)
+			//
+			// ---
+			//  type: string
+			//  required: no
+			//  shortdesc: Address (and optional port) of a primary DNS server to push the records to
+			rules[k] = validate.Optional(validate.IsListenAddress(true, false, false))
+		case "key":
+			// gendoc:generate(entity=network_zone, group=common, key=push.NAME.key)
+			//
+			// ---
+			//  type: string
+			//  required: no
+			//  shortdesc: TSIG key (`hmac-sha256`) used to sign the dynamic updates sent to the server
+			rules[k] = validate.Optional(validate.IsAny)
+		}
+	}
+
 	// gendoc:generate(entity=network_zone, group=common, key=user.*)
 	//
 	// ---
@@ -311,6 +373,11 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 				return err
 			}
 
+			err = updateZoneKeys(ctx, tx.Tx(), dbZone.ID, dbZone.Name, config.Config)
+			if err != nil {
+				return err
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -340,6 +407,11 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 					return err
 				}
 
+				err = updateZoneKeys(ctx, tx.Tx(), dbZone.ID, dbZone.Name, oldConfig.Config)
+				if err != nil {
+					return err
+				}
+
 				return nil
 			})
 			d.info.NetworkZonePut = oldConfig
@@ -366,6 +438,9 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 		return err
 	}
 
+	// Stop answering queries from the previous zone content.
+	d.state.DNS.InvalidateZone(d.info.Name)
+
 	reverter.Success()
 	return nil
 }
@@ -397,9 +472,24 @@ func (d *zone) Delete() error {
 		return err
 	}
 
+	// Stop answering queries from the previous zone content.
+	d.state.DNS.InvalidateZone(d.info.Name)
+
 	return nil
 }
 
+// PushRecords sends the changes to the zone records to the servers configured through push.NAME.*.
+// It returns whether the zone has any such server.
+func (d *zone) PushRecords() (bool, error) {
+	for k := range d.info.Config {
+		if strings.HasPrefix(k, "push.") {
+			return true, d.state.DNS.PushZone(d.info.Name)
+		}
+	}
+
+	return false, nil
+}
+
 // Content returns the DNS zone content.
 func (d *zone) Content() (*strings.Builder, error) {
 	var err error
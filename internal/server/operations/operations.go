@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/lxc/incus/v7/internal/server/auth"
 	"github.com/lxc/incus/v7/internal/server/db/operationtype"
@@ -17,6 +19,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/tracing"
 	"github.com/lxc/incus/v7/internal/version"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/cancel"
@@ -109,11 +112,18 @@ type Operation struct {
 	requestor   *api.EventLifecycleRequestor
 	logger      logger.Logger
 
+	// Tracing span covering the lifetime of the operation and the context holding it.
+	ctx  context.Context
+	span trace.Span
+
 	// Those functions are called at various points in the Operation lifecycle
 	onRun     func(*Operation) error
 	onCancel  func(*Operation) error
 	onConnect func(*Operation, *http.Request, http.ResponseWriter) error
 
+	// Functions called once the operation has completed.
+	onDone []func(api.StatusCode, error)
+
 	// Indicates if operation has finished.
 	finished *cancel.Canceller
 
@@ -149,6 +159,18 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 	op.state = s
 	op.logger = logger.AddContext(logger.Ctx{"operation": op.id, "project": op.projectName, "class": op.class.String(), "description": op.description})
 
+	// Trace the operation as part of the request which created it, without being cancelled along with it.
+	parentCtx := context.Background()
+	if r != nil {
+		parentCtx = context.WithoutCancel(r.Context())
+	}
+
+	op.ctx, op.span = tracing.Start(parentCtx, fmt.Sprintf("operation %s", op.description),
+		attribute.String("incus.operation.id", op.id),
+		attribute.String("incus.operation.class", op.class.String()),
+		attribute.String("incus.project", op.projectName),
+	)
+
 	if s != nil {
 		op.SetEventServer(s.Events)
 	}
@@ -198,6 +220,8 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 		delete(operations, op.id)
 		operationsLock.Unlock()
 
+		tracing.End(op.span, err)
+
 		return nil, err
 	}
 
@@ -211,6 +235,16 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 	return &op, nil
 }
 
+// Context returns a context holding the tracing span of the operation, for use by the work it performs.
+// The context isn't cancelled when the operation is.
+func (op *Operation) Context() context.Context {
+	if op == nil || op.ctx == nil {
+		return context.Background()
+	}
+
+	return op.ctx
+}
+
 // SetEventServer allows injection of event server.
 func (op *Operation) SetEventServer(eventServer *events.Server) {
 	op.events = eventServer
@@ -258,8 +292,21 @@ func (op *Operation) done() {
 	op.onCancel = nil
 	op.onConnect = nil
 	op.finished.Cancel()
+	if op.span != nil {
+		op.span.SetAttributes(attribute.String("incus.operation.status", op.status.String()))
+		tracing.End(op.span, op.err)
+	}
+
+	onDone := op.onDone
+	op.onDone = nil
+	status := op.status
+	err := op.err
 	op.lock.Unlock()
 
+	for _, f := range onDone {
+		f(status, err)
+	}
+
 	go func() {
 		shutdownCtx := context.Background()
 		if op.state != nil {
@@ -296,6 +343,23 @@ func (op *Operation) done() {
 	}()
 }
 
+// OnDone registers a function to be called with the final status and error of the operation once it has completed.
+// The function is called right away if the operation has already completed.
+func (op *Operation) OnDone(f func(status api.StatusCode, err error)) {
+	op.lock.Lock()
+	if op.readonly {
+		status := op.status
+		err := op.err
+		op.lock.Unlock()
+
+		f(status, err)
+		return
+	}
+
+	op.onDone = append(op.onDone, f)
+	op.lock.Unlock()
+}
+
 // Start a pending operation. It returns an error if the operation cannot be started.
 func (op *Operation) Start() error {
 	op.lock.Lock()
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/idmap"
 )
 
@@ -75,3 +76,54 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestGetInstanceLimitsNetwork(t *testing.T) {
+	inst := api.Instance{
+		Name:    "c1",
+		Project: "p1",
+		Type:    "container",
+		Devices: map[string]map[string]string{
+			"eth0": {"type": "nic", "limits.ingress": "10Mbit", "limits.egress": "20Mbit"},
+			"eth1": {"type": "nic", "limits.max": "1Gbit", "limits.ingress": "1Mbit"},
+			"root": {"type": "disk", "path": "/", "pool": "default"},
+		},
+	}
+
+	networkTypes := map[string]string{"incusbr0": "bridge", "macvlan0": "macvlan"}
+
+	limits, err := getInstanceLimits(inst, []string{"limits.network.ingress", "limits.network.egress"}, networkTypes, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1010000000), limits["limits.network.ingress"])
+	assert.Equal(t, int64(1020000000), limits["limits.network.egress"])
+
+	// NICs without a limit are rejected unless skipped.
+	inst.Devices["eth2"] = map[string]string{"type": "nic", "network": "incusbr0", "limits.egress": "5Mbit"}
+
+	_, err = getInstanceLimits(inst, []string{"limits.network.ingress"}, networkTypes, false)
+	assert.EqualError(t, err, `Device "eth2" of instance "c1" in project "p1" has no "limits.ingress" config set, either directly or via a profile`)
+
+	limits, err = getInstanceLimits(inst, []string{"limits.network.ingress", "limits.network.egress"}, networkTypes, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1010000000), limits["limits.network.ingress"])
+	assert.Equal(t, int64(1025000000), limits["limits.network.egress"])
+
+	// NICs which don't support bandwidth limits are rejected unless skipped.
+	delete(inst.Devices, "eth2")
+	inst.Devices["eth3"] = map[string]string{"type": "nic", "network": "macvlan0"}
+
+	_, err = getInstanceLimits(inst, []string{"limits.network.egress"}, networkTypes, false)
+	assert.EqualError(t, err, `Device "eth3" of instance "c1" in project "p1" can't be used with "limits.network.egress" as "macvlan" NICs don't support bandwidth limits`)
+
+	inst.Devices["eth3"] = map[string]string{"type": "nic", "nictype": "physical", "parent": "eth0"}
+
+	limits, err = getInstanceLimits(inst, []string{"limits.network.egress"}, networkTypes, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1020000000), limits["limits.network.egress"])
+
+	// Parsing errors name the key the value came from.
+	delete(inst.Devices, "eth3")
+	inst.Devices["eth1"]["limits.max"] = "fast"
+
+	_, err = getInstanceLimits(inst, []string{"limits.network.ingress"}, networkTypes, false)
+	assert.EqualError(t, err, `Failed parsing "limits.max" of device "eth1" for instance "c1" in project "p1"`)
+}
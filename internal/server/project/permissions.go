@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -973,6 +974,8 @@ var allAggregateLimits = []string{
 	"limits.cpu",
 	"limits.disk",
 	"limits.memory",
+	"limits.network.egress",
+	"limits.network.ingress",
 	"limits.processes",
 }
 
@@ -1241,6 +1244,10 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 		case "limits.memory":
 			fallthrough
 		case "limits.disk":
+			fallthrough
+		case "limits.network.ingress":
+			fallthrough
+		case "limits.network.egress":
 			aggregateKeys = append(aggregateKeys, key)
 		}
 	}
@@ -1378,6 +1385,9 @@ type projectInfo struct {
 	Profiles  []api.Profile
 	Instances []api.Instance
 	Volumes   []db.StorageVolumeArgs
+
+	// Types of the networks available to the project's instances, indexed by network name.
+	NetworkTypes map[string]string
 }
 
 // Fetch the given project from the database along with its profiles, instances
@@ -1469,16 +1479,55 @@ func fetchProject(tx *db.ClusterTx, projectName string, skipIfNoLimits bool) (*p
 		return nil, fmt.Errorf("Fetch project custom volumes from database: %w", err)
 	}
 
+	networkTypes, err := fetchNetworkTypes(ctx, tx, project)
+	if err != nil {
+		return nil, err
+	}
+
 	info := &projectInfo{
-		Project:   *project,
-		Profiles:  profiles,
-		Instances: instances,
-		Volumes:   volumes,
+		Project:      *project,
+		Profiles:     profiles,
+		Instances:    instances,
+		Volumes:      volumes,
+		NetworkTypes: networkTypes,
 	}
 
 	return info, nil
 }
 
+// Fetch the types of the networks available to the instances of the given
+// project, including the networks shared from the default project.
+func fetchNetworkTypes(ctx context.Context, tx *db.ClusterTx, project *api.Project) (map[string]string, error) {
+	networkTypes := map[string]string{}
+
+	networkProjectName := NetworkProjectFromRecord(project)
+	networks, err := tx.GetCreatedNetworksByProject(ctx, networkProjectName)
+	if err != nil {
+		return nil, fmt.Errorf("Fetch project networks from database: %w", err)
+	}
+
+	for _, network := range networks {
+		networkTypes[network.Name] = network.Type
+	}
+
+	if networkProjectName == api.ProjectDefaultName || project.Config["restricted.networks.access"] == "" {
+		return networkTypes, nil
+	}
+
+	networks, err = tx.GetCreatedNetworksByProject(ctx, api.ProjectDefaultName)
+	if err != nil {
+		return nil, fmt.Errorf("Fetch default project networks from database: %w", err)
+	}
+
+	for _, network := range networks {
+		if NetworkSharedFromDefault(project, network.Name) {
+			networkTypes[network.Name] = network.Type
+		}
+	}
+
+	return networkTypes, nil
+}
+
 // Expand the configuration and devices of the given instances, taking the give
 // project profiles into account.
 func expandInstancesConfigAndDevices(instances []api.Instance, profiles []api.Profile) ([]api.Instance, error) {
@@ -1545,7 +1594,7 @@ func getTotalsAcrossProjectEntities(info *projectInfo, keys []string, skipUnset
 	}
 
 	for _, inst := range info.Instances {
-		limits, err := getInstanceLimits(inst, keys, skipUnset)
+		limits, err := getInstanceLimits(inst, keys, info.NetworkTypes, skipUnset)
 		if err != nil {
 			return nil, err
 		}
@@ -1559,7 +1608,9 @@ func getTotalsAcrossProjectEntities(info *projectInfo, keys []string, skipUnset
 }
 
 // Return the effective instance-level values for the limits with the given keys.
-func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[string]int64, error) {
+// The types of the networks are used to resolve the type of NICs connected to a
+// managed network.
+func getInstanceLimits(inst api.Instance, keys []string, networkTypes map[string]string, skipUnset bool) (map[string]int64, error) {
 	var err error
 	limits := map[string]int64{}
 
@@ -1628,6 +1679,11 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 
 				limit += sizeStateLimit
 			}
+		} else if key == "limits.network.ingress" || key == "limits.network.egress" {
+			limit, err = getInstanceNetworkLimit(inst, key, networkTypes, skipUnset)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			// Skip processing for 'limits.processes' if the instance type is VM,
 			// as this limit is only applicable to containers.
@@ -1660,6 +1716,88 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 	return limits, nil
 }
 
+// NIC types which don't support bandwidth limits.
+var nicTypesWithoutLimits = []string{"ipvlan", "macvlan", "physical", "sriov"}
+
+// Return the type of the NIC, resolving it from the type of its network if connected to a managed network.
+func getNICType(device map[string]string, networkTypes map[string]string) string {
+	if device["network"] == "" {
+		return device["nictype"]
+	}
+
+	switch networkTypes[device["network"]] {
+	case "bridge":
+		return "bridged"
+	case "macvlan":
+		return "macvlan"
+	case "ovn":
+		return "ovn"
+	case "physical":
+		return "physical"
+	case "sriov":
+		return "sriov"
+	}
+
+	return ""
+}
+
+// Return the sum of the ingress or egress limits of the NICs of the instance.
+func getInstanceNetworkLimit(inst api.Instance, key string, networkTypes map[string]string, skipUnset bool) (int64, error) {
+	nicKey := "limits.ingress"
+	if key == "limits.network.egress" {
+		nicKey = "limits.egress"
+	}
+
+	parser := aggregateLimitConfigValueParsers[key]
+
+	var total int64
+	for _, devName := range slices.Sorted(maps.Keys(inst.Devices)) {
+		device := inst.Devices[devName]
+		if device["type"] != "nic" {
+			continue
+		}
+
+		// NICs which can't be limited would escape the project limit.
+		nicType := getNICType(device, networkTypes)
+		if slices.Contains(nicTypesWithoutLimits, nicType) {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Device %q of instance %q in project %q can't be used with %q as %q NICs don't support bandwidth limits", devName, inst.Name, inst.Project, key, nicType)
+		}
+
+		// The "limits.max" key takes precedence over the per-direction ones.
+		valueKey := "limits.max"
+		value := device[valueKey]
+		if value == "" {
+			valueKey = nicKey
+			value = device[valueKey]
+		}
+
+		if value == "" {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Device %q of instance %q in project %q has no %q config set, either directly or via a profile", devName, inst.Name, inst.Project, nicKey)
+		}
+
+		limit, err := parser(value)
+		if err != nil {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Failed parsing %q of device %q for instance %q in project %q", valueKey, devName, inst.Name, inst.Project)
+		}
+
+		total += limit
+	}
+
+	return total, nil
+}
+
 var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.memory": func(value string) (int64, error) {
 		if strings.HasSuffix(value, "%") {
@@ -1691,6 +1829,12 @@ var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.disk": func(value string) (int64, error) {
 		return units.ParseByteSizeString(value)
 	},
+	"limits.network.ingress": func(value string) (int64, error) {
+		return units.ParseBitSizeString(value)
+	},
+	"limits.network.egress": func(value string) (int64, error) {
+		return units.ParseBitSizeString(value)
+	},
 }
 
 var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
@@ -1706,6 +1850,12 @@ var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
 	"limits.disk": func(limit int64) string {
 		return units.GetByteSizeStringIEC(limit, 1)
 	},
+	"limits.network.ingress": func(limit int64) string {
+		return units.GetBitSizeString(limit, 1)
+	},
+	"limits.network.egress": func(limit int64) string {
+		return units.GetBitSizeString(limit, 1)
+	},
 }
 
 // FilterUsedBy filters a UsedBy list based on project access.
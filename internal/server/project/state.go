@@ -49,6 +49,8 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 	result["cpu"] = raw["limits.cpu"]
 	result["disk"] = raw["limits.disk"]
 	result["memory"] = raw["limits.memory"]
+	result["network.egress"] = raw["limits.network.egress"]
+	result["network.ingress"] = raw["limits.network.ingress"]
 	result["networks"] = raw["limits.networks"]
 	result["processes"] = raw["limits.processes"]
 
@@ -0,0 +1,133 @@
+// Package replication holds the logic shared by the scheduled replication of instances and custom volumes.
+package replication
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// SnapshotPrefix is the name prefix of the snapshots created for replication.
+// Retention only ever applies to snapshots with this prefix.
+const SnapshotPrefix = "replication-"
+
+// Volatile keys recording the replication state.
+const (
+	KeyLastAttempt = "volatile.replication.last_attempt"
+	KeyLastSuccess = "volatile.replication.last_success"
+	KeyLastError   = "volatile.replication.last_error"
+	KeySource      = "volatile.replication.source"
+)
+
+// SnapshotName returns the name of a replication snapshot created at the given time.
+func SnapshotName(createdAt time.Time) string {
+	return SnapshotPrefix + createdAt.UTC().Format("20060102-150405")
+}
+
+// IsRemote returns true if the replication target is a remote server rather than a local storage pool.
+func IsRemote(target string) bool {
+	return strings.Contains(target, "://")
+}
+
+// Retention returns the number of replication snapshots to keep as set through `replication.retention`.
+func Retention(config map[string]string) (int, error) {
+	value := config["replication.retention"]
+	if value == "" {
+		return 1, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return -1, fmt.Errorf("Invalid value %q for %q", value, "replication.retention")
+	}
+
+	return count, nil
+}
+
+// Expired returns the names of the replication snapshots exceeding the retention, oldest first.
+// Snapshots without the replication prefix are never returned.
+func Expired(snapshotNames []string, retention int) []string {
+	var names []string
+	for _, name := range snapshotNames {
+		if strings.HasPrefix(name, SnapshotPrefix) {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) <= retention {
+		return nil
+	}
+
+	// The names embed their creation time so sort chronologically.
+	slices.Sort(names)
+
+	return names[:len(names)-retention]
+}
+
+// ReplicaConfig returns the config to apply to the replica of an entity with the given config.
+// The replication settings and state are dropped and the replica is marked as received from source.
+func ReplicaConfig(config map[string]string, source string) map[string]string {
+	replicaConfig := maps.Clone(config)
+	for key := range replicaConfig {
+		if strings.HasPrefix(key, "replication.") || strings.HasPrefix(key, "volatile.replication.") {
+			delete(replicaConfig, key)
+		}
+	}
+
+	replicaConfig[KeySource] = source
+
+	return replicaConfig
+}
+
+// StateChanges returns the volatile keys to record the outcome of a replication attempt started at the given time.
+func StateChanges(attemptedAt time.Time, err error) map[string]string {
+	changes := map[string]string{
+		KeyLastAttempt: strconv.FormatInt(attemptedAt.Unix(), 10),
+		KeyLastError:   "",
+	}
+
+	if err != nil {
+		changes[KeyLastError] = err.Error()
+	} else {
+		changes[KeyLastSuccess] = changes[KeyLastAttempt]
+	}
+
+	return changes
+}
+
+// State returns the replication state recorded in the config or nil if the entity
+// is neither replicated nor a replica.
+func State(config map[string]string, now time.Time) *api.ReplicationState {
+	if config["replication.target"] == "" && config[KeySource] == "" {
+		return nil
+	}
+
+	state := &api.ReplicationState{
+		Target:    config["replication.target"],
+		LastError: config[KeyLastError],
+		Source:    config[KeySource],
+	}
+
+	parseTime := func(key string) time.Time {
+		ts, err := strconv.ParseInt(config[key], 10, 64)
+		if err != nil || ts <= 0 {
+			return time.Time{}
+		}
+
+		return time.Unix(ts, 0)
+	}
+
+	state.LastAttempt = parseTime(KeyLastAttempt)
+	state.LastSuccess = parseTime(KeyLastSuccess)
+
+	if !state.LastSuccess.IsZero() {
+		state.Lag = int64(now.Sub(state.LastSuccess).Seconds())
+	}
+
+	return state
+}
@@ -0,0 +1,93 @@
+package replication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetention(t *testing.T) {
+	count, err := Retention(map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = Retention(map[string]string{"replication.retention": "3"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	_, err = Retention(map[string]string{"replication.retention": "0"})
+	assert.Error(t, err)
+}
+
+func TestExpired(t *testing.T) {
+	snapshots := []string{
+		"replication-20240103-000000",
+		"snap0",
+		"replication-20240101-000000",
+		"replication-20240102-000000",
+		"scheduled-20240101-000000",
+	}
+
+	assert.Equal(t, []string{"replication-20240101-000000", "replication-20240102-000000"}, Expired(snapshots, 1))
+	assert.Equal(t, []string{"replication-20240101-000000"}, Expired(snapshots, 2))
+	assert.Empty(t, Expired(snapshots, 3))
+	assert.Empty(t, Expired([]string{"snap0", "snap1"}, 1))
+}
+
+func TestReplicaConfig(t *testing.T) {
+	config := map[string]string{
+		"limits.cpu":           "2",
+		"replication.target":   "https://dr.example.net:8443",
+		"replication.schedule": "@hourly",
+		KeyLastSuccess:         "1700000000",
+	}
+
+	assert.Equal(t, map[string]string{
+		"limits.cpu": "2",
+		KeySource:    "server01",
+	}, ReplicaConfig(config, "server01"))
+
+	// The original config is left untouched.
+	assert.Equal(t, "@hourly", config["replication.schedule"])
+}
+
+func TestState(t *testing.T) {
+	now := time.Unix(1700000600, 0)
+
+	assert.Nil(t, State(map[string]string{}, now))
+
+	// Successful replication.
+	config := map[string]string{"replication.target": "backup-pool"}
+	for k, v := range StateChanges(time.Unix(1700000000, 0), nil) {
+		config[k] = v
+	}
+
+	state := State(config, now)
+	require.NotNil(t, state)
+	assert.Equal(t, "backup-pool", state.Target)
+	assert.Equal(t, time.Unix(1700000000, 0), state.LastSuccess)
+	assert.Equal(t, state.LastSuccess, state.LastAttempt)
+	assert.Empty(t, state.LastError)
+	assert.Equal(t, int64(600), state.Lag)
+
+	// A failure keeps the last success and the lag keeps growing.
+	for k, v := range StateChanges(time.Unix(1700000300, 0), errors.New("Connection refused")) {
+		config[k] = v
+	}
+
+	state = State(config, now)
+	require.NotNil(t, state)
+	assert.Equal(t, time.Unix(1700000000, 0), state.LastSuccess)
+	assert.Equal(t, time.Unix(1700000300, 0), state.LastAttempt)
+	assert.Equal(t, "Connection refused", state.LastError)
+	assert.Equal(t, int64(600), state.Lag)
+
+	// Replica which was never replicated from.
+	state = State(map[string]string{KeySource: "server01"}, now)
+	require.NotNil(t, state)
+	assert.Equal(t, "server01", state.Source)
+	assert.Zero(t, state.Lag)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/network/ovn"
 	"github.com/lxc/incus/v7/internal/server/network/ovs"
 	"github.com/lxc/incus/v7/internal/server/node"
+	"github.com/lxc/incus/v7/internal/server/storage/keystore"
 	"github.com/lxc/incus/v7/internal/server/storage/linstor"
 	"github.com/lxc/incus/v7/internal/server/sys"
 	localtls "github.com/lxc/incus/v7/shared/tls"
@@ -99,4 +100,7 @@ type State struct {
 
 	// Linstor.
 	Linstor func() (*linstor.Client, error)
+
+	// Keystore for encrypted storage volumes.
+	Keystore func() (keystore.Store, error)
 }
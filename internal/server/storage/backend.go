@@ -24,6 +24,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.yaml.in/yaml/v4"
 	"golang.org/x/sync/errgroup"
 
@@ -50,8 +53,11 @@ import (
 	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/state"
 	"github.com/lxc/incus/v7/internal/server/storage/drivers"
+	"github.com/lxc/incus/v7/internal/server/storage/keystore"
 	"github.com/lxc/incus/v7/internal/server/storage/memorypipe"
 	"github.com/lxc/incus/v7/internal/server/storage/s3"
+	"github.com/lxc/incus/v7/internal/server/storage/s3/local"
+	"github.com/lxc/incus/v7/internal/server/tracing"
 	localUtil "github.com/lxc/incus/v7/internal/server/util"
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/shared/api"
@@ -154,6 +160,14 @@ func (b *backend) Driver() drivers.Driver {
 	return b.driver
 }
 
+// startSpan starts a tracing span for a storage call, as a child of the span of the operation if any.
+func (b *backend) startSpan(op *operations.Operation, name string, attrs ...attribute.KeyValue) trace.Span {
+	attrs = append(attrs, attribute.String("incus.storage.pool", b.name), attribute.String("incus.storage.driver", b.driver.Info().Name))
+	_, span := tracing.Start(op.Context(), "storage "+name, attrs...)
+
+	return span
+}
+
 // MigrationTypes returns the migration transport method preferred when sending a migration, based
 // on the migration method requested by the driver's ability. The copySnapshots argument indicates
 // whether snapshots are migrated as well. clusterMove determines whether the migration is done
@@ -242,6 +256,76 @@ func (b *backend) GetVolume(volType drivers.VolumeType, contentType drivers.Cont
 	return drivers.NewVolume(b.driver, b.name, volType, contentType, volName, volConfig, b.db.Config).Clone()
 }
 
+// checkVolumeKey returns an error if the volume described by the config is encrypted and its key isn't in the key store.
+// This is used before receiving a volume from another server which can only be unlocked with the original key.
+func (b *backend) checkVolumeKey(volConfig map[string]string) error {
+	keyID := volConfig["volatile.encryption.key_id"]
+	if !util.IsTrue(volConfig["security.encrypted"]) || keyID == "" {
+		return nil
+	}
+
+	if !b.driver.Info().Encryption {
+		return fmt.Errorf("Storage pool %q doesn't support encrypted volumes", b.name)
+	}
+
+	err := drivers.CheckKeystore(b.state, b.driver.Info())
+	if err != nil {
+		return err
+	}
+
+	store, err := b.state.Keystore()
+	if err != nil {
+		return fmt.Errorf("Failed loading key store: %w", err)
+	}
+
+	_, err = store.Get(context.TODO(), keyID)
+	if err != nil {
+		if errors.Is(err, keystore.ErrNotFound) {
+			return fmt.Errorf("Encryption key %q of the volume isn't available in the key store", keyID)
+		}
+
+		return fmt.Errorf("Failed loading encryption key %q: %w", keyID, err)
+	}
+
+	return nil
+}
+
+// releaseVolumeKeys removes the given encryption keys from the key store once no volume uses them anymore.
+// Failures are only logged as a leftover key doesn't prevent anything from working.
+func (b *backend) releaseVolumeKeys(keyIDs ...string) {
+	for _, keyID := range keyIDs {
+		if keyID == "" {
+			continue
+		}
+
+		var used bool
+		err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			used, err = tx.StorageVolumeConfigValueUsed(ctx, "volatile.encryption.key_id", keyID)
+
+			return err
+		})
+		if err != nil {
+			b.logger.Warn("Failed checking encryption key usage", logger.Ctx{"keyID": keyID, "err": err})
+			continue
+		}
+
+		if used {
+			continue
+		}
+
+		store, err := b.state.Keystore()
+		if err == nil {
+			err = store.Delete(context.TODO(), keyID)
+		}
+
+		if err != nil {
+			b.logger.Warn("Failed deleting encryption key", logger.Ctx{"keyID": keyID, "err": err})
+		}
+	}
+}
+
 // GetResources returns utilisation information about the pool.
 func (b *backend) GetResources() (*api.ResourcesStoragePool, error) {
 	l := b.logger.AddContext(nil)
@@ -255,6 +339,32 @@ func (b *backend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// GetHealth returns the health of the pool.
+func (b *backend) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	l := b.logger.AddContext(nil)
+	l.Debug("GetHealth started")
+	defer l.Debug("GetHealth finished")
+
+	if b.Status() == api.StoragePoolStatusPending {
+		return nil, errors.New("The pool is in pending state")
+	}
+
+	return b.driver.GetHealth()
+}
+
+// Scrub verifies the integrity of the data stored on the pool.
+func (b *backend) Scrub(op *operations.Operation) error {
+	l := b.logger.AddContext(nil)
+	l.Debug("Scrub started")
+	defer l.Debug("Scrub finished")
+
+	if b.Status() == api.StoragePoolStatusPending {
+		return errors.New("The pool is in pending state")
+	}
+
+	return b.driver.Scrub(op)
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *backend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, db.StoragePoolVolumeTypeNameImage)
@@ -647,6 +757,7 @@ func (b *backend) CreateInstance(inst instance.Instance, op *operations.Operatio
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 	l.Debug("CreateInstance started")
 	defer l.Debug("CreateInstance finished")
+	defer b.startSpan(op, "CreateInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -820,6 +931,7 @@ func (b *backend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.Rea
 	l := b.logger.AddContext(logger.Ctx{"project": srcBackup.Project, "instance": srcBackup.Name, "snapshots": srcBackup.Snapshots, "optimizedStorage": *srcBackup.OptimizedStorage})
 	l.Debug("CreateInstanceFromBackup started")
 	defer l.Debug("CreateInstanceFromBackup finished")
+	defer b.startSpan(op, "CreateInstanceFromBackup").End()
 
 	// Get the volume name on storage.
 	volStorageName := project.Instance(srcBackup.Project, srcBackup.Name)
@@ -859,6 +971,11 @@ func (b *backend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.Rea
 		}
 	}
 
+	err = b.checkVolumeKey(volumeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Import dependent disks
 	err = b.createDependentVolumesFromBackup(srcBackup, srcData, op)
 	if err != nil {
@@ -1126,6 +1243,7 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "snapshots": snapshots})
 	l.Debug("CreateInstanceFromCopy started")
 	defer l.Debug("CreateInstanceFromCopy finished")
+	defer b.startSpan(op, "CreateInstanceFromCopy", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -1673,6 +1791,7 @@ func (b *backend) RefreshInstance(inst instance.Instance, src instance.Instance,
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "srcSnapshots": len(srcSnapshots)})
 	l.Debug("RefreshInstance started")
 	defer l.Debug("RefreshInstance finished")
+	defer b.startSpan(op, "RefreshInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	// This indicates whether or not it's a volume-only refresh.
 	snapshots := len(srcSnapshots) > 0
@@ -2001,6 +2120,7 @@ func (b *backend) CreateInstanceFromImage(inst instance.Instance, fingerprint st
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 	l.Debug("CreateInstanceFromImage started")
 	defer l.Debug("CreateInstanceFromImage finished")
+	defer b.startSpan(op, "CreateInstanceFromImage", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -2183,6 +2303,7 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "args": fmt.Sprintf("%+v", args)})
 	l.Debug("CreateInstanceFromMigration started")
 	defer l.Debug("CreateInstanceFromMigration finished")
+	defer b.startSpan(op, "CreateInstanceFromMigration", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -2277,6 +2398,17 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 		if err != nil {
 			return fmt.Errorf("Failed filling volume config: %w", err)
 		}
+
+		// The received data can't be encrypted on the fly, so only keep encryption if the source volume was.
+		if !util.IsTrue(volumeConfig["security.encrypted"]) {
+			delete(vol.Config(), "security.encrypted")
+			delete(vol.Config(), "volatile.encryption.key_id")
+		}
+	}
+
+	err = b.checkVolumeKey(vol.Config())
+	if err != nil {
+		return err
 	}
 
 	// Check if the volume exists on storage.
@@ -2510,6 +2642,7 @@ func (b *backend) RenameInstance(inst instance.Instance, newName string, op *ope
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "newName": newName})
 	l.Debug("RenameInstance started")
 	defer l.Debug("RenameInstance finished")
+	defer b.startSpan(op, "RenameInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	if inst.IsSnapshot() {
 		return errors.New("Instance cannot be a snapshot")
@@ -2666,6 +2799,7 @@ func (b *backend) DeleteInstance(inst instance.Instance, op *operations.Operatio
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 	l.Debug("DeleteInstance started")
 	defer l.Debug("DeleteInstance finished")
+	defer b.startSpan(op, "DeleteInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	if inst.IsSnapshot() {
 		return errors.New("Instance must not be a snapshot")
@@ -2695,6 +2829,13 @@ func (b *backend) DeleteInstance(inst instance.Instance, op *operations.Operatio
 	// There's no need to pass config as it's not needed when deleting a volume.
 	vol := b.GetVolume(volType, contentType, volStorageName, nil)
 
+	// Keep track of the encryption key so it can be released once the volume record is gone.
+	var keyID string
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err == nil {
+		keyID = dbVol.Config["volatile.encryption.key_id"]
+	}
+
 	// Delete the volume from the storage device. Must come after snapshots are removed.
 	// Must come before DB VolumeDBDelete so that the volume ID is still available.
 	l.Debug("Deleting instance volume", logger.Ctx{"volName": volStorageName})
@@ -2728,6 +2869,8 @@ func (b *backend) DeleteInstance(inst instance.Instance, op *operations.Operatio
 		return err
 	}
 
+	b.releaseVolumeKeys(keyID)
+
 	// Record volume deletion with authorizer.
 	err = b.state.Authorizer.DeleteStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), vol.Type().Singular(), inst.Name(), "")
 	if err != nil {
@@ -2742,6 +2885,7 @@ func (b *backend) UpdateInstance(inst instance.Instance, newDesc string, newConf
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "newDesc": newDesc, "newConfig": newConfig})
 	l.Debug("UpdateInstance started")
 	defer l.Debug("UpdateInstance finished")
+	defer b.startSpan(op, "UpdateInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	if inst.IsSnapshot() {
 		return errors.New("Instance cannot be a snapshot")
@@ -2855,6 +2999,7 @@ func (b *backend) MigrateInstance(inst instance.Instance, conn io.ReadWriteClose
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "args": fmt.Sprintf("%+v", args)})
 	l.Debug("MigrateInstance started")
 	defer l.Debug("MigrateInstance finished")
+	defer b.startSpan(op, "MigrateInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
@@ -3044,10 +3189,11 @@ func (b *backend) CleanupInstancePaths(inst instance.Instance, op *operations.Op
 }
 
 // BackupInstance creates an instance backup.
-func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, dependentVolumes bool, op *operations.Operation) error {
+func (b *backend) BackupInstance(inst instance.Instance, tarWriter instancewriter.InstanceWriter, optimized bool, snapshots bool, dependentVolumes bool, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
+	defer b.startSpan(op, "BackupInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
@@ -3434,6 +3580,12 @@ func (b *backend) getInstanceDisk(inst instance.Instance) (string, error) {
 	// location of the disk block device.
 	vol := b.GetVolume(volType, contentType, volStorageName, nil)
 
+	// Use the unlocked device of encrypted volumes.
+	mappedPath := drivers.LUKSMappedPath(vol)
+	if mappedPath != "" {
+		return mappedPath, nil
+	}
+
 	// Get the location of the disk block device.
 	diskPath, err := b.driver.GetVolumeDiskPath(vol)
 	if err != nil {
@@ -3448,6 +3600,7 @@ func (b *backend) CreateInstanceSnapshot(inst instance.Instance, src instance.In
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name()})
 	l.Debug("CreateInstanceSnapshot started")
 	defer l.Debug("CreateInstanceSnapshot finished")
+	defer b.startSpan(op, "CreateInstanceSnapshot", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	if inst.Type() != src.Type() {
 		return errors.New("Instance types must match")
@@ -3685,6 +3838,7 @@ func (b *backend) DeleteInstanceSnapshot(inst instance.Instance, cleanupDependen
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 	l.Debug("DeleteInstanceSnapshot started")
 	defer l.Debug("DeleteInstanceSnapshot finished")
+	defer b.startSpan(op, "DeleteInstanceSnapshot", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	parentName, snapName, isSnap := api.GetParentAndSnapshotName(inst.Name())
 	if !inst.IsSnapshot() || !isSnap {
@@ -3779,6 +3933,8 @@ func (b *backend) DeleteInstanceSnapshot(inst instance.Instance, cleanupDependen
 		if err != nil {
 			return err
 		}
+
+		b.releaseVolumeKeys(srcDBVol.Config["volatile.encryption.key_id"])
 	}
 
 	if !cleanupDependencies {
@@ -3899,6 +4055,7 @@ func (b *backend) RestoreInstanceSnapshot(inst instance.Instance, src instance.I
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name()})
 	l.Debug("RestoreInstanceSnapshot started")
 	defer l.Debug("RestoreInstanceSnapshot finished")
+	defer b.startSpan(op, "RestoreInstanceSnapshot", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	reverter := revert.New()
 	defer reverter.Fail()
@@ -4081,6 +4238,12 @@ func (b *backend) RestoreInstanceSnapshot(inst instance.Instance, src instance.I
 	}
 
 	reverter.Success()
+
+	// The snapshot brings back its own encryption key, release the one the volume used until now.
+	if dbVol.Config["volatile.encryption.key_id"] != srcDBVol.Config["volatile.encryption.key_id"] {
+		b.releaseVolumeKeys(dbVol.Config["volatile.encryption.key_id"])
+	}
+
 	return nil
 }
 
@@ -4440,6 +4603,16 @@ func (b *backend) EnsureImage(fingerprint string, op *operations.Operation) erro
 func (b *backend) shouldUseOptimizedImage(fingerprint string, contentType drivers.ContentType, volConfig map[string]string, op *operations.Operation) (bool, error) {
 	canOptimizeImage := b.driver.Info().OptimizedImages
 
+	// Encrypted volumes are formatted with their own key on creation, so can't be cloned from an optimized image.
+	encrypted := b.db.Config["volume.security.encrypted"]
+	if volConfig["security.encrypted"] != "" {
+		encrypted = volConfig["security.encrypted"]
+	}
+
+	if contentType == drivers.ContentTypeBlock && b.driver.Info().Encryption && util.IsTrue(encrypted) {
+		return false, nil
+	}
+
 	// If the volume config is empty, the default pool configuration is used, making the driver's support
 	// for optimized images the determining factor. However, an optimized image cannot be utilized if the
 	// driver lacks support for it.
@@ -5218,6 +5391,96 @@ func (b *backend) MountLocalBucket(projectName string, bucketName string, op *op
 	return bucketVol.MountPath(), unmount, nil
 }
 
+// getBucketVolume returns the volume of an existing bucket.
+func (b *backend) getBucketVolume(projectName string, bucketName string) (drivers.Volume, error) {
+	memberSpecific := !b.Driver().Info().Remote // Member specific if storage pool isn't remote.
+
+	var bucket *db.StorageBucket
+	err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, memberSpecific, bucketName)
+		return err
+	})
+	if err != nil {
+		return drivers.Volume{}, err
+	}
+
+	bucketVolName := project.StorageVolume(projectName, bucket.Name)
+
+	return b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config), nil
+}
+
+// GetBucketLifecycle returns the lifecycle configuration of a bucket.
+func (b *backend) GetBucketLifecycle(projectName string, bucketName string) (*api.StorageBucketLifecycle, error) {
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	if !b.Driver().Info().Buckets {
+		return nil, errors.New("Storage pool does not support buckets")
+	}
+
+	bucketVol, err := b.getBucketVolume(projectName, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Driver().Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		return b.driver.GetBucketLifecycle(bucketVol)
+	}
+
+	bucketDir, unmount, err := b.MountLocalBucket(projectName, bucketName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = unmount() }()
+
+	return local.LoadLifecycle(bucketDir)
+}
+
+// UpdateBucketLifecycle replaces the lifecycle configuration of a bucket.
+func (b *backend) UpdateBucketLifecycle(projectName string, bucketName string, lifecycle api.StorageBucketLifecycle, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName, "rules": len(lifecycle.Rules)})
+	l.Debug("UpdateBucketLifecycle started")
+	defer l.Debug("UpdateBucketLifecycle finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if !b.Driver().Info().Buckets {
+		return errors.New("Storage pool does not support buckets")
+	}
+
+	err = s3.ValidateLifecycle(lifecycle)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "%w", err)
+	}
+
+	bucketVol, err := b.getBucketVolume(projectName, bucketName)
+	if err != nil {
+		return err
+	}
+
+	if b.Driver().Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		return b.driver.UpdateBucketLifecycle(bucketVol, lifecycle)
+	}
+
+	bucketDir, unmount, err := b.MountLocalBucket(projectName, bucketName, op)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = unmount() }()
+
+	return local.SaveLifecycle(bucketDir, lifecycle)
+}
+
 // GetBucketURL returns S3 URL for bucket.
 func (b *backend) GetBucketURL(bucketName string) *url.URL {
 	err := b.isStatusReady()
@@ -5262,6 +5525,7 @@ func (b *backend) CreateCustomVolume(projectName string, volName string, desc st
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "desc": desc, "config": config, "contentType": contentType})
 	l.Debug("CreateCustomVolume started")
 	defer l.Debug("CreateCustomVolume finished")
+	defer b.startSpan(op, "CreateCustomVolume", attribute.String("incus.project", projectName), attribute.String("incus.volume", volName)).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -5320,6 +5584,7 @@ func (b *backend) CreateCustomVolumeFromCopy(projectName string, srcProjectName
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "srcProjectName": srcProjectName, "volName": volName, "desc": desc, "config": config, "srcPoolName": srcPoolName, "srcVolName": srcVolName, "snapshots": snapshots})
 	l.Debug("CreateCustomVolumeFromCopy started")
 	defer l.Debug("CreateCustomVolumeFromCopy finished")
+	defer b.startSpan(op, "CreateCustomVolumeFromCopy", attribute.String("incus.project", projectName)).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -5661,6 +5926,7 @@ func (b *backend) MigrateCustomVolume(projectName string, conn io.ReadWriteClose
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": args.Name, "args": fmt.Sprintf("%+v", args)})
 	l.Debug("MigrateCustomVolume started")
 	defer l.Debug("MigrateCustomVolume finished")
+	defer b.startSpan(op, "MigrateCustomVolume", attribute.String("incus.project", projectName)).End()
 
 	// Get the volume name on storage.
 	volStorageName := project.StorageVolume(projectName, args.Name)
@@ -5720,6 +5986,7 @@ func (b *backend) CreateCustomVolumeFromMigration(projectName string, conn io.Re
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": args.Name, "args": fmt.Sprintf("%+v", args)})
 	l.Debug("CreateCustomVolumeFromMigration started")
 	defer l.Debug("CreateCustomVolumeFromMigration finished")
+	defer b.startSpan(op, "CreateCustomVolumeFromMigration", attribute.String("incus.project", projectName)).End()
 
 	err := b.isStatusReady()
 	if err != nil {
@@ -5747,6 +6014,11 @@ func (b *backend) CreateCustomVolumeFromMigration(projectName string, conn io.Re
 		volumeConfig = args.Config
 	}
 
+	err = b.checkVolumeKey(volumeConfig)
+	if err != nil {
+		return err
+	}
+
 	// Check if the volume exists on storage.
 	volStorageName := project.StorageVolume(projectName, args.Name)
 	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(args.ContentType), volStorageName, volumeConfig)
@@ -6283,6 +6555,7 @@ func (b *backend) DeleteCustomVolume(projectName string, volName string, op *ope
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName})
 	l.Debug("DeleteCustomVolume started")
 	defer l.Debug("DeleteCustomVolume finished")
+	defer b.startSpan(op, "DeleteCustomVolume", attribute.String("incus.project", projectName), attribute.String("incus.volume", volName)).End()
 
 	_, _, isSnap := api.GetParentAndSnapshotName(volName)
 	if isSnap {
@@ -6357,6 +6630,8 @@ func (b *backend) DeleteCustomVolume(projectName string, volName string, op *ope
 		return err
 	}
 
+	b.releaseVolumeKeys(curVol.Config["volatile.encryption.key_id"])
+
 	var location string
 	if b.state.ServerClustered && !b.Driver().Info().Remote {
 		location = b.state.ServerName
@@ -6453,12 +6728,118 @@ func (b *backend) RebuildCustomVolume(projectName string, volName string, op *op
 		}
 	}
 
+	// Encrypted volumes are formatted again with a new key.
+	oldKeyID := curVol.Config["volatile.encryption.key_id"]
+	if vol.IsEncrypted() {
+		newConfig := maps.Clone(curVol.Config)
+		newConfig["volatile.encryption.key_id"] = uuid.New().String()
+		vol = b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, newConfig)
+	}
+
 	// Re-create the empty custom volume on the storage device.
 	err = b.driver.CreateVolume(vol, nil, op)
 	if err != nil {
 		return err
 	}
 
+	if vol.IsEncrypted() {
+		err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateStoragePoolVolume(ctx, projectName, volName, db.StoragePoolVolumeTypeCustom, b.ID(), curVol.Description, vol.Config())
+		})
+		if err != nil {
+			return err
+		}
+
+		b.releaseVolumeKeys(oldKeyID)
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(vol, string(vol.Type()), projectName, op, nil))
+
+	return nil
+}
+
+// RekeyVolume replaces the passphrase unlocking an encrypted custom or virtual-machine volume.
+// The volume key encrypting the data stays the same and snapshots keep using the passphrase they were taken with.
+func (b *backend) RekeyVolume(projectName string, volName string, volType drivers.VolumeType, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "volType": volType})
+	l.Debug("RekeyVolume started")
+	defer l.Debug("RekeyVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if internalInstance.IsSnapshot(volName) {
+		return errors.New("Volume name cannot be a snapshot")
+	}
+
+	var volStorageName string
+	switch volType {
+	case drivers.VolumeTypeCustom:
+		volStorageName = project.StorageVolume(projectName, volName)
+	case drivers.VolumeTypeVM:
+		volStorageName = project.Instance(projectName, volName)
+	default:
+		return fmt.Errorf("Volume type %q cannot be encrypted", volType)
+	}
+
+	// Get the volume.
+	curVol, err := VolumeDBGet(b, projectName, volName, volType)
+	if err != nil {
+		return err
+	}
+
+	dbContentType, err := VolumeContentTypeNameToContentType(curVol.ContentType)
+	if err != nil {
+		return err
+	}
+
+	contentType, err := VolumeDBContentTypeToContentType(dbContentType)
+	if err != nil {
+		return err
+	}
+
+	vol := b.GetVolume(volType, contentType, volStorageName, curVol.Config)
+	if !vol.IsEncrypted() {
+		return api.StatusErrorf(http.StatusBadRequest, "Storage volume %q isn't encrypted", volName)
+	}
+
+	volDBType, err := VolumeTypeToDBType(volType)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Record the new key first so that a failure to do so leaves the volume untouched.
+	oldKeyID := curVol.Config["volatile.encryption.key_id"]
+	newConfig := maps.Clone(curVol.Config)
+	newConfig["volatile.encryption.key_id"] = uuid.New().String()
+
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateStoragePoolVolume(ctx, projectName, volName, volDBType, b.ID(), curVol.Description, newConfig)
+	})
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_ = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateStoragePoolVolume(ctx, projectName, volName, volDBType, b.ID(), curVol.Description, curVol.Config)
+		})
+	})
+
+	err = b.driver.RekeyVolume(vol, newConfig["volatile.encryption.key_id"], op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	b.releaseVolumeKeys(oldKeyID)
+
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(vol, string(vol.Type()), projectName, op, nil))
 
 	return nil
@@ -6474,8 +6855,17 @@ func (b *backend) GetCustomVolumeDisk(projectName, volName string) (string, erro
 	// Get the volume name on storage.
 	volStorageName := project.StorageVolume(projectName, volName)
 
-	// There's no need to pass config as it's not needed when getting the volume usage.
-	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, nil)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
+
+	// Encrypted volumes are only usable through their unlocked device.
+	if vol.IsEncrypted() {
+		mappedPath := drivers.LUKSMappedPath(vol)
+		if mappedPath == "" {
+			return "", fmt.Errorf("Encrypted volume %q isn't unlocked", volName)
+		}
+
+		return mappedPath, nil
+	}
 
 	return b.driver.GetVolumeDiskPath(vol)
 }
@@ -6718,6 +7108,7 @@ func (b *backend) CreateCustomVolumeSnapshot(projectName, volName string, newSna
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "newSnapshotName": newSnapshotName, "newExpiryDate": newExpiryDate})
 	l.Debug("CreateCustomVolumeSnapshot started")
 	defer l.Debug("CreateCustomVolumeSnapshot finished")
+	defer b.startSpan(op, "CreateCustomVolumeSnapshot", attribute.String("incus.project", projectName), attribute.String("incus.volume", volName)).End()
 
 	if internalInstance.IsSnapshot(volName) {
 		return errors.New("Volume does not support snapshots")
@@ -6888,6 +7279,7 @@ func (b *backend) DeleteCustomVolumeSnapshot(projectName, volName string, op *op
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName})
 	l.Debug("DeleteCustomVolumeSnapshot started")
 	defer l.Debug("DeleteCustomVolumeSnapshot finished")
+	defer b.startSpan(op, "DeleteCustomVolumeSnapshot", attribute.String("incus.project", projectName), attribute.String("incus.volume", volName)).End()
 
 	parentName, _, isSnap := api.GetParentAndSnapshotName(volName)
 
@@ -6956,6 +7348,8 @@ func (b *backend) DeleteCustomVolumeSnapshot(projectName, volName string, op *op
 		return err
 	}
 
+	b.releaseVolumeKeys(volume.Config["volatile.encryption.key_id"])
+
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeSnapshotDeleted.Event(vol, string(vol.Type()), projectName, op, nil))
 
 	return nil
@@ -7049,23 +7443,45 @@ func (b *backend) RestoreCustomVolume(projectName, volName string, snapshotName
 		return nil
 	}
 
+	// Get the snapshot, its encryption key is the one the volume uses once restored.
+	snapDBVol, err := VolumeDBGet(b, projectName, fmt.Sprintf("%s/%s", volName, snapshotName), drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
 	err = b.driver.RestoreVolume(vol, snapshotName, op)
 	if err != nil {
 		var snapErr drivers.ErrDeleteSnapshots
-		if errors.As(err, &snapErr) {
-			err = deleteSnapshots(snapErr.Snapshots)
-			if err != nil {
-				return err
-			}
+		if !errors.As(err, &snapErr) {
+			return err
+		}
 
-			// Now try again.
-			err = b.driver.RestoreVolume(vol, snapshotName, op)
-			if err != nil {
-				return err
-			}
+		err = deleteSnapshots(snapErr.Snapshots)
+		if err != nil {
+			return err
 		}
 
-		return err
+		// Now try again.
+		err = b.driver.RestoreVolume(vol, snapshotName, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	curKeyID := curVol.Config["volatile.encryption.key_id"]
+	snapKeyID := snapDBVol.Config["volatile.encryption.key_id"]
+	if curKeyID != snapKeyID {
+		newConfig := maps.Clone(curVol.Config)
+		newConfig["volatile.encryption.key_id"] = snapKeyID
+
+		err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateStoragePoolVolume(ctx, projectName, volName, db.StoragePoolVolumeTypeCustom, b.ID(), curVol.Description, newConfig)
+		})
+		if err != nil {
+			return err
+		}
+
+		b.releaseVolumeKeys(curKeyID)
 	}
 
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeRestored.Event(vol, string(vol.Type()), projectName, op, logger.Ctx{"snapshot": snapshotName}))
@@ -7824,6 +8240,7 @@ func (b *backend) ImportInstance(inst instance.Instance, poolVol *backupConfig.C
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 	l.Debug("ImportInstance started")
 	defer l.Debug("ImportInstance finished")
+	defer b.startSpan(op, "ImportInstance", attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name())).End()
 
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
@@ -7995,6 +8412,7 @@ func (b *backend) BackupCustomVolume(projectName string, volName string, writer
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volume": volName, "optimized": optimized, "snapshots": snapshots})
 	l.Debug("BackupCustomVolume started")
 	defer l.Debug("BackupCustomVolume finished")
+	defer b.startSpan(op, "BackupCustomVolume", attribute.String("incus.project", projectName), attribute.String("incus.volume", volName)).End()
 
 	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
 	if err != nil {
@@ -8055,6 +8473,7 @@ func (b *backend) CreateCustomVolumeFromISO(projectName string, volName string,
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volume": volName})
 	l.Debug("CreateCustomVolumeFromISO started")
 	defer l.Debug("CreateCustomVolumeFromISO finished")
+	defer b.startSpan(op, "CreateCustomVolumeFromISO", attribute.String("incus.project", projectName), attribute.String("incus.volume", volName)).End()
 
 	// Check whether we are allowed to create volumes.
 	req := api.StorageVolumesPost{
@@ -8140,6 +8559,7 @@ func (b *backend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData io
 	l := b.logger.AddContext(logger.Ctx{"project": srcBackup.Project, "volume": srcBackup.Name, "snapshots": srcBackup.Snapshots, "optimizedStorage": *srcBackup.OptimizedStorage})
 	l.Debug("CreateCustomVolumeFromBackup started")
 	defer l.Debug("CreateCustomVolumeFromBackup finished")
+	defer b.startSpan(op, "CreateCustomVolumeFromBackup").End()
 
 	if srcBackup.Config == nil || srcBackup.Config.Volume == nil {
 		return errors.New("Valid volume config not found in index")
@@ -8182,6 +8602,11 @@ func (b *backend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData io
 		return fmt.Errorf("Volume %q already exists in pool %q", srcBackup.Name, b.name)
 	}
 
+	err = b.checkVolumeKey(vol.Config())
+	if err != nil {
+		return err
+	}
+
 	// Validate config and create database entry for new storage volume.
 	// Strip unsupported config keys (in case the export was made from a different type of storage pool).
 	err = VolumeDBCreate(b, srcBackup.Project, srcBackup.Name, srcBackup.Config.Volume.Description, vol.Type(), false, vol.Config(), srcBackup.Config.Volume.CreatedAt, time.Time{}, vol.ContentType(), true, true)
@@ -8641,8 +9066,9 @@ func (b *backend) qcow2CanRestoreSnapshot(vol drivers.Volume, snapVol drivers.Vo
 
 		// Restoring is allowed only for the most recent snapshot.
 		if imgInfo.BackingFilename != snapVolDevPath {
-			if util.IsFalseOrEmpty(vol.ExpandedConfig("lvmcluster.remove_snapshots")) {
-				return fmt.Errorf("Snapshot %q cannot be restored due to subsequent snapshot(s). Set lvmcluster.remove_snapshots to override", snapVol.Name())
+			removeSnapshotsKey := b.driver.Info().Name + ".remove_snapshots"
+			if util.IsFalseOrEmpty(vol.ExpandedConfig(removeSnapshotsKey)) {
+				return fmt.Errorf("Snapshot %q cannot be restored due to subsequent snapshot(s). Set %s to override", snapVol.Name(), removeSnapshotsKey)
 			}
 
 			snapshots := []string{}
@@ -9082,7 +9508,7 @@ func (b *backend) qcow2MigrateVolume(s *state.State, vol drivers.Volume, project
 			return drivers.ErrNotSupported
 		}
 
-		rsyncArgs = []string{"--exclude", "root.img"}
+		rsyncArgs = []string{"--exclude", "root.img", "--exclude", drivers.Qcow2LayersDir}
 	} else if vol.ContentType() == drivers.ContentTypeBlock && volSrcArgs.MigrationType.FSType != migration.MigrationFSType_BLOCK_AND_RSYNC || vol.ContentType() == drivers.ContentTypeFS && volSrcArgs.MigrationType.FSType != migration.MigrationFSType_RSYNC {
 		return drivers.ErrNotSupported
 	}
@@ -10059,8 +10485,13 @@ func (b *backend) GetInstanceNBD(inst instance.Instance, writable bool) (net.Con
 		return nil, nil, err
 	}
 
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), drivers.VolumeTypeVM)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	volStorageName := project.Instance(inst.Project().Name, inst.Name())
-	vol := b.GetVolume(drivers.VolumeTypeVM, drivers.ContentTypeBlock, volStorageName, nil)
+	vol := b.GetVolume(drivers.VolumeTypeVM, drivers.ContentTypeBlock, volStorageName, dbVol.Config)
 
 	unlock, err := nbdOperationLock(inst.Project().Name, inst.Name())
 	if err != nil {
@@ -10092,9 +10523,12 @@ func (b *backend) GetInstanceNBD(inst instance.Instance, writable bool) (net.Con
 	var volSize int64
 	var volDiskPath string
 	err = vol.MountTask(func(devPath string, op *operations.Operation) error {
-		volDiskPath, err = b.Driver().GetVolumeDiskPath(vol)
-		if err != nil {
-			return err
+		volDiskPath = drivers.LUKSMappedPath(vol)
+		if volDiskPath == "" {
+			volDiskPath, err = b.Driver().GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		volSize, err = drivers.BlockDiskSizeBytes(volDiskPath)
@@ -10168,7 +10602,7 @@ func (b *backend) GetCustomVolumeNBD(projectName string, volName string, writabl
 	}
 
 	volStorageName := project.StorageVolume(projectName, volName)
-	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentTypeBlock, volStorageName, nil)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentTypeBlock, volStorageName, dbVol.Config)
 
 	// Convert the volume type name to our internal integer representation.
 	volumeDbType, err := VolumeTypeNameToDBType(dbVol.Type)
@@ -10198,9 +10632,12 @@ func (b *backend) GetCustomVolumeNBD(projectName string, volName string, writabl
 	var volSize int64
 	var volDiskPath string
 	err = vol.MountTask(func(devPath string, op *operations.Operation) error {
-		volDiskPath, err = b.Driver().GetVolumeDiskPath(vol)
-		if err != nil {
-			return err
+		volDiskPath = drivers.LUKSMappedPath(vol)
+		if volDiskPath == "" {
+			volDiskPath, err = b.Driver().GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		volSize, err = drivers.BlockDiskSizeBytes(volDiskPath)
@@ -10235,10 +10672,16 @@ func (b *backend) connectOfflineNBD(vol drivers.Volume, writable bool) (net.Conn
 	errCh := make(chan string, 1)
 
 	go func() {
-		err := b.Driver().ActivateTask(vol, func(devPath string, op *operations.Operation) error {
-			volDiskPath, err := b.Driver().GetVolumeDiskPath(vol)
-			if err != nil {
-				return err
+		task := func(devPath string, op *operations.Operation) error {
+			// Use the unlocked device of encrypted volumes.
+			volDiskPath := drivers.LUKSMappedPath(vol)
+			if volDiskPath == "" {
+				var err error
+
+				volDiskPath, err = b.Driver().GetVolumeDiskPath(vol)
+				if err != nil {
+					return err
+				}
 			}
 
 			imgInfo, err := drivers.Qcow2Info(volDiskPath)
@@ -10275,7 +10718,17 @@ func (b *backend) connectOfflineNBD(vol drivers.Volume, writable bool) (net.Conn
 			}
 
 			return nil
-		}, nil)
+		}
+
+		var err error
+
+		// Encrypted volumes need to be mounted to be unlocked.
+		if vol.IsEncrypted() {
+			err = vol.MountTask(task, nil)
+		} else {
+			err = b.Driver().ActivateTask(vol, task, nil)
+		}
+
 		if err != nil {
 			b.logger.Error("Failed when running qemu-nbd", logger.Ctx{"err": err})
 			errCh <- fmt.Sprintf("Failed when running qemu-nbd: %v", err)
@@ -82,6 +82,16 @@ func (b *mockBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+// GetHealth returns the health of the storage pool.
+func (b *mockBackend) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	return nil, nil
+}
+
+// Scrub verifies the integrity of the data stored on the storage pool.
+func (b *mockBackend) Scrub(op *operations.Operation) error {
+	return nil
+}
+
 // IsUsed returns whether the storage pool is in use.
 func (b *mockBackend) IsUsed() (bool, error) {
 	return false, nil
@@ -213,7 +223,7 @@ func (b *mockBackend) RefreshInstance(inst instance.Instance, src instance.Insta
 }
 
 // BackupInstance creates an instance backup.
-func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, dependentVolumes bool, op *operations.Operation) error {
+func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter instancewriter.InstanceWriter, optimized bool, snapshots bool, dependentVolumes bool, op *operations.Operation) error {
 	return nil
 }
 
@@ -333,6 +343,16 @@ func (b *mockBackend) MountLocalBucket(projectName string, bucketName string, op
 	return "", func() error { return nil }, nil
 }
 
+// GetBucketLifecycle returns the lifecycle configuration of a bucket.
+func (b *mockBackend) GetBucketLifecycle(projectName string, bucketName string) (*api.StorageBucketLifecycle, error) {
+	return nil, nil
+}
+
+// UpdateBucketLifecycle replaces the lifecycle configuration of a bucket.
+func (b *mockBackend) UpdateBucketLifecycle(projectName string, bucketName string, lifecycle api.StorageBucketLifecycle, op *operations.Operation) error {
+	return nil
+}
+
 // GetBucketURL returns the URL of a storage bucket.
 func (b *mockBackend) GetBucketURL(bucketName string) *url.URL {
 	return nil
@@ -369,6 +389,11 @@ func (b *mockBackend) RebuildCustomVolume(projectName string, volName string, op
 	return nil
 }
 
+// RekeyVolume replaces the passphrase unlocking an encrypted volume.
+func (b *mockBackend) RekeyVolume(projectName string, volName string, volType drivers.VolumeType, op *operations.Operation) error {
+	return nil
+}
+
 // MigrateCustomVolume migrates a custom volume to another member.
 func (b *mockBackend) MigrateCustomVolume(projectName string, conn io.ReadWriteCloser, args *migration.VolumeSourceArgs, op *operations.Operation) error {
 	return nil
@@ -69,6 +69,11 @@ func (d *btrfs) load() error {
 
 // Info returns info about the driver and its environment.
 func (d *btrfs) Info() Info {
+	targetFormat := BlockVolumeTypeRaw
+	if d.config["volume.block.type"] == BlockVolumeTypeQcow2 {
+		targetFormat = BlockVolumeTypeQcow2
+	}
+
 	return Info{
 		Name:                         "btrfs",
 		Version:                      btrfsVersion,
@@ -87,6 +92,7 @@ func (d *btrfs) Info() Info {
 		IOUring:                      true,
 		MountedRoot:                  true,
 		Buckets:                      true,
+		TargetFormat:                 targetFormat,
 	}
 }
 
@@ -352,6 +358,15 @@ func (d *btrfs) Validate(config map[string]string) error {
 		//  default: -
 		//  shortdesc: Additional options to pass to `mkfs.btrfs` when creating the pool
 		"btrfs.create_options": validate.IsAny,
+
+		// gendoc:generate(entity=storage_btrfs, group=common, key=scrub.schedule)
+		// Pools using an existing Btrfs subvolume scrub the whole filesystem it's on.
+		// ---
+		//  type: string
+		//  scope: global
+		//  default: -
+		//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable scheduled scrubs (the default)
+		"scrub.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
 	}
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
@@ -359,7 +374,12 @@ func (d *btrfs) Validate(config map[string]string) error {
 
 // Update applies any driver changes required from a configuration change.
 func (d *btrfs) Update(changedConfig map[string]string) error {
-	// We only care about btrfs.mount_options.
+	_, changed := changedConfig["volume.block.type"]
+	if changed {
+		return errors.New("volume.block.type cannot be changed after creation")
+	}
+
+	// Remount when btrfs.mount_options changes.
 	val, ok := changedConfig["btrfs.mount_options"]
 	if ok {
 		// Custom mount options don't work inside containers
@@ -512,6 +532,27 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// GetHealth returns the health of the storage pool.
+func (d *btrfs) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	out, err := subprocess.RunCommandCLocale("btrfs", "device", "stats", GetPoolMountPath(d.name))
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting btrfs device stats: %w", err)
+	}
+
+	return btrfsParseDeviceStats(out)
+}
+
+// Scrub verifies the integrity of the data stored on the pool.
+func (d *btrfs) Scrub(op *operations.Operation) error {
+	// Run in the foreground so the scrub errors are returned.
+	_, err := subprocess.RunCommand("btrfs", "scrub", "start", "-B", GetPoolMountPath(d.name))
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing btrfs filesystem: %w", err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
@@ -543,6 +584,16 @@ func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapsh
 		}
 	}
 
+	// The qcow2 images are sent through their NBD export, layer by layer.
+	if IsContentBlock(contentType) && d.config["volume.block.type"] == BlockVolumeTypeQcow2 {
+		return []localMigration.Type{
+			{
+				FSType:   migration.MigrationFSType_BLOCK_AND_RSYNC,
+				Features: rsyncFeatures,
+			},
+		}
+	}
+
 	if IsContentBlock(contentType) {
 		return []localMigration.Type{
 			{
@@ -84,6 +84,18 @@ func (d *btrfs) getMountOptions() string {
 	return "user_subvol_rm_allowed"
 }
 
+// qcow2LayerPath returns the path of the qcow2 layer of a snapshot of a qcow2 block volume, or an empty
+// path for other volumes. The layers are kept in the parent volume as the snapshots are read-only.
+func (d *btrfs) qcow2LayerPath(vol Volume) string {
+	if !vol.IsSnapshot() || vol.contentType != ContentTypeBlock || !isQcow2Volume(vol) {
+		return ""
+	}
+
+	parentName, snapName, _ := api.GetParentAndSnapshotName(vol.name)
+
+	return filepath.Join(GetVolumeMountPath(d.name, vol.volType, parentName), Qcow2LayersDir, snapName+".img")
+}
+
 func (d *btrfs) isSubvolume(path string) bool {
 	// Stat the path.
 	stat := unix.Stat_t{}
@@ -660,3 +672,76 @@ func (d *btrfs) receiveSubVolume(r io.Reader, receivePath string, tracker *iopro
 
 	return subVolPath, nil
 }
+
+// btrfsParseDeviceStats parses the output of `btrfs device stats`.
+// Devices missing from the filesystem are reported as `devid:<id>` and make it degraded.
+func btrfsParseDeviceStats(output string) (*api.ResourcesStoragePoolHealth, error) {
+	names := []string{}
+	devices := map[string]*api.ResourcesStoragePoolHealthDevice{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		// Lines are `[<device>].<counter> <value>`.
+		name, counter, found := strings.Cut(strings.TrimPrefix(fields[0], "["), "].")
+		if !found {
+			return nil, fmt.Errorf("Unexpected btrfs device stats line %q", line)
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing %q of device %q: %w", counter, name, err)
+		}
+
+		device, ok := devices[name]
+		if !ok {
+			device = &api.ResourcesStoragePoolHealthDevice{Name: name, Status: "online"}
+			if strings.HasPrefix(name, "devid:") {
+				device.Status = "missing"
+			}
+
+			devices[name] = device
+			names = append(names, name)
+		}
+
+		switch counter {
+		case "read_io_errs":
+			device.ReadErrors += value
+		case "write_io_errs", "flush_io_errs":
+			device.WriteErrors += value
+		case "corruption_errs", "generation_errs":
+			device.ChecksumErrors += value
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, errors.New("No devices found in btrfs device stats")
+	}
+
+	health := &api.ResourcesStoragePoolHealth{Status: api.StoragePoolHealthHealthy}
+
+	missing := 0
+	errorCount := uint64(0)
+	for _, name := range names {
+		device := devices[name]
+		if device.Status == "missing" {
+			missing++
+		}
+
+		errorCount += device.ReadErrors + device.WriteErrors + device.ChecksumErrors
+		health.Devices = append(health.Devices, *device)
+	}
+
+	if missing > 0 {
+		health.Status = api.StoragePoolHealthDegraded
+		health.Message = fmt.Sprintf("%d device(s) missing from the filesystem", missing)
+	} else if errorCount > 0 {
+		health.Status = api.StoragePoolHealthDegraded
+		health.Message = fmt.Sprintf("%d device error(s) recorded since the counters were last reset", errorCount)
+	}
+
+	return health, nil
+}
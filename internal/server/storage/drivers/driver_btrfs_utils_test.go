@@ -0,0 +1,46 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func Test_btrfsParseDeviceStats(t *testing.T) {
+	stats := `[/dev/sdb].write_io_errs    0
+[/dev/sdb].read_io_errs     0
+[/dev/sdb].flush_io_errs    0
+[/dev/sdb].corruption_errs  0
+[/dev/sdb].generation_errs  0
+[/dev/sdc].write_io_errs    1
+[/dev/sdc].read_io_errs     2
+[/dev/sdc].flush_io_errs    3
+[/dev/sdc].corruption_errs  4
+[/dev/sdc].generation_errs  5
+`
+
+	health, err := btrfsParseDeviceStats(stats)
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+	assert.Equal(t, []api.ResourcesStoragePoolHealthDevice{
+		{Name: "/dev/sdb", Status: "online"},
+		{Name: "/dev/sdc", Status: "online", ReadErrors: 2, WriteErrors: 4, ChecksumErrors: 9},
+	}, health.Devices)
+
+	health, err = btrfsParseDeviceStats("[/dev/sdb].write_io_errs 0\n[/dev/sdb].read_io_errs 0\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthHealthy, health.Status)
+	assert.Empty(t, health.Message)
+
+	// Missing devices are reported by ID.
+	health, err = btrfsParseDeviceStats("[/dev/sdb].write_io_errs 0\n[devid:2].write_io_errs 0\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+	assert.Equal(t, "missing", health.Devices[1].Status)
+
+	_, err = btrfsParseDeviceStats("")
+	assert.Error(t, err)
+}
@@ -119,6 +119,19 @@ func (d *btrfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Op
 		}
 	}
 
+	// The filler overwrites the empty image with the qcow2 one of the source.
+	if IsQcow2Block(vol) {
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		err = ensureQcow2File(rootBlockPath, sizeBytes)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = genericRunFiller(d, vol, rootBlockPath, filler, false)
 	if err != nil {
 		return err
@@ -126,7 +139,18 @@ func (d *btrfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Op
 
 	// If we are creating a block volume, resize it to the requested size or the default.
 	// We expect the filler function to have converted the qcow2 image to raw into the rootBlockPath.
-	if IsContentBlock(vol.contentType) {
+	if IsQcow2Block(vol) {
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		// The qcow2 image only needs to be grown, its data was already placed by the filler.
+		err = ensureQcow2File(rootBlockPath, sizeBytes)
+		if err != nil {
+			return err
+		}
+	} else if IsContentBlock(vol.contentType) {
 		// Convert to bytes.
 		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
 		if err != nil {
@@ -442,6 +466,21 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *btrfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	// The qcow2 images of instances and custom volumes are layered on their snapshots, which have to be
+	// copied and rebased one by one. Images have no snapshots and so can be copied as a subvolume.
+	if IsQcow2Block(srcVol) && srcVol.volType != VolumeTypeImage {
+		var srcSnapshots []Volume
+		if copySnapshots && !srcVol.IsSnapshot() {
+			var err error
+			srcSnapshots, err = srcVol.Snapshots(op)
+			if err != nil {
+				return err
+			}
+		}
+
+		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	}
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -834,7 +873,8 @@ func (d *btrfs) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume,
 
 	// Optimized refresh only makes sense if the source and target have at least one identical snapshot,
 	// as btrfs can then use an incremental streams instead of just copying the datasets.
-	if len(targetSnapshots) == 0 || len(srcSnapshotsAll) == 0 {
+	// The qcow2 images are layered on the snapshots and so always go through the generic refresh.
+	if len(targetSnapshots) == 0 || len(srcSnapshotsAll) == 0 || IsQcow2Block(srcVol) {
 		d.logger.Debug("Performing generic volume refresh")
 		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, false, op)
 	}
@@ -1050,7 +1090,37 @@ func (d *btrfs) commonVolumeRules() map[string]func(value string) error {
 
 			return nil
 		}),
+
+		// gendoc:generate(entity=storage_volume_btrfs, group=common, key=block.type)
+		// Always follows the pool's `volume.block.type`, which can only be set when creating the pool.
+		// ---
+		//  type: string
+		//  condition: block-based volume
+		//  default: same as `volume.block.type` or `raw`
+		//  shortdesc: Format of the disk file (`raw` or `qcow2`)
+		"block.type": validate.Optional(validate.IsOneOf(BlockVolumeTypeRaw, BlockVolumeTypeQcow2)),
+
+		// gendoc:generate(entity=storage_volume_btrfs, group=common, key=btrfs.remove_snapshots)
+		//
+		// ---
+		//  type: bool
+		//  condition: `qcow2` block-based volume
+		//  default: same as `volume.btrfs.remove_snapshots` or `false`
+		//  shortdesc: Remove snapshots as needed
+		"btrfs.remove_snapshots": validate.Optional(validate.IsBool),
+	}
+}
+
+// FillVolumeConfig populate volume with default config.
+func (d *btrfs) FillVolumeConfig(vol Volume) error {
+	err := d.fillVolumeConfig(&vol, "block.type")
+	if err != nil {
+		return err
 	}
+
+	d.fillVolumeBlockType(&vol)
+
+	return nil
 }
 
 // ValidateVolume validates the supplied volume config.
@@ -1143,6 +1213,137 @@ func (d *btrfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.expiry)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: When scheduled backups stored on the server are to be deleted (expects an expression like `1M 2H 3d 4w 5m 6y`)
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.retention.daily)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of daily scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.retention.last)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of most recent scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.retention.weekly)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of weekly scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable automatic backups (the default)
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 access key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 bucket for scheduled backups (required when `backups.target.url` is set)
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.target.format)
+	// `tarball` or `repository` (see {ref}`storage-backup-schedule`)
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  condition: custom volume
+	//  shortdesc: How scheduled backups are stored on the backup target
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.target.path)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 secret key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.target.url)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: URL of the S3 server to upload scheduled backups to
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=backups.volume_only)
+	//
+	// ---
+	//  type: bool
+	//  condition: custom volume
+	//  default: `false`
+	//  shortdesc: Whether to leave volume snapshots out of scheduled backups
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=replication.retention)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  default: `1`
+	//  shortdesc: Number of replication snapshots to keep
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=replication.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable replication (the default)
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=replication.target)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Name of a local storage pool or URL of a remote server (`https://<address>:<port>`) to replicate the volume to
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=replication.target.certificate)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: PEM encoded certificate of the remote replication server (the system trust store is used when unset)
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=replication.target.pool)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's pool
+	//  shortdesc: Storage pool on the remote replication server
+
+	// gendoc:generate(entity=storage_volume_btrfs, group=common, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's project
+	//  shortdesc: Project on the remote replication server
+
 	// gendoc:generate(entity=storage_bucket_btrfs, group=common, key=size)
 	//
 	// ---
@@ -1151,7 +1352,30 @@ func (d *btrfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.size`
 	//  shortdesc: Size/quota of the storage bucket
 
-	return d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
+	// gendoc:generate(entity=storage_bucket_btrfs, group=common, key=versioning)
+	// When unset, versioning was never configured on the bucket.
+	// Setting it to `false` suspends versioning, keeping the existing object versions.
+	// ---
+	//  type: bool
+	//  condition: appropriate driver
+	//  default: -
+	//  shortdesc: Whether to keep every version of the objects
+
+	err := d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	err = d.validateVolumeBlockType(vol)
+	if err != nil {
+		return err
+	}
+
+	if vol.config["block.type"] == BlockVolumeTypeQcow2 && util.IsTrue(vol.config["security.shared"]) {
+		return errors.New("QCOW2 volume type is incompatible with the 'security.shared' option.")
+	}
+
+	return nil
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -1164,6 +1388,11 @@ func (d *btrfs) UpdateVolume(vol Volume, changedConfig map[string]string) error
 		}
 	}
 
+	_, changed := changedConfig["block.type"]
+	if changed {
+		return errors.New("block.type cannot be changed after creation")
+	}
+
 	// Apply a changed compression property to the volume. This affects newly written data; the
 	// existing contents keep whatever compression they were written with.
 	compression, compressionChanged := changedConfig["btrfs.compression"]
@@ -1204,7 +1433,8 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 	// For VM block files, resize the file if needed.
 	if vol.contentType == ContentTypeBlock {
 		// Do nothing if size isn't specified.
-		if sizeBytes <= 0 {
+		// qcow2 images are resized by the backend as the running instance has to be told about it.
+		if sizeBytes <= 0 || IsQcow2Block(vol) {
 			return nil
 		}
 
@@ -1239,6 +1469,11 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 	// For non-VM block volumes, set filesystem quota.
 	volPath := vol.MountPath()
 
+	// The qcow2 disk grows with its content and holds the snapshot layers, so no limit is applied.
+	if vol.volType == VolumeTypeVM && vol.ExpandedConfig("block.type") == BlockVolumeTypeQcow2 {
+		sizeBytes = 0
+	}
+
 	// Try to locate an existing quota group.
 	qgroup, _, err := d.getQGroup(volPath)
 	if err != nil && !d.state.OS.RunningInUserNS {
@@ -1348,6 +1583,11 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 
 // GetVolumeDiskPath returns the location and file format of a disk volume.
 func (d *btrfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	layerPath := d.qcow2LayerPath(vol)
+	if layerPath != "" {
+		return layerPath, nil
+	}
+
 	return genericVFSGetVolumeDiskPath(vol)
 }
 
@@ -1995,6 +2235,32 @@ func (d *btrfs) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) e
 		reverter.Add(cleanup)
 	}
 
+	// The qcow2 image becomes the snapshot layer and the backend then creates a new image on top of it.
+	// Moving it keeps the file open by a running instance valid. The copies of the image and of the
+	// earlier layers in the snapshot would only hold on to their data.
+	layerPath := d.qcow2LayerPath(snapVol)
+	if layerPath != "" {
+		for _, name := range []string{genericVolumeDiskFile, Qcow2LayersDir} {
+			err = os.RemoveAll(filepath.Join(snapPath, name))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = os.MkdirAll(filepath.Dir(layerPath), 0o700)
+		if err != nil {
+			return err
+		}
+
+		diskPath := filepath.Join(srcPath, genericVolumeDiskFile)
+		err = os.Rename(diskPath, layerPath)
+		if err != nil {
+			return fmt.Errorf("Failed moving qcow2 image %q to snapshot: %w", diskPath, err)
+		}
+
+		reverter.Add(func() { _ = os.Rename(layerPath, diskPath) })
+	}
+
 	err = d.setSubvolumeReadonlyProperty(snapPath, true)
 	if err != nil {
 		return err
@@ -2187,7 +2453,37 @@ func (d *btrfs) RestoreVolume(vol Volume, snapshotName string, op *operations.Op
 
 // RenameVolumeSnapshot renames a volume snapshot.
 func (d *btrfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
-	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	layerPath := d.qcow2LayerPath(snapVol)
+	if layerPath != "" {
+		newLayerPath := filepath.Join(filepath.Dir(layerPath), newSnapshotName+".img")
+		err := os.Rename(layerPath, newLayerPath)
+		if err != nil {
+			return fmt.Errorf("Failed renaming qcow2 image %q: %w", layerPath, err)
+		}
+
+		reverter.Add(func() { _ = os.Rename(newLayerPath, layerPath) })
+	}
+
+	err := genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// GetQcow2BackingFilePath generates the backing file path for the specified volume.
+func (d *btrfs) GetQcow2BackingFilePath(vol Volume) (string, error) {
+	return d.GetVolumeDiskPath(vol)
+}
+
+// Qcow2DeletionCleanup performs post block-commit cleanup of qcow2 snapshot artifacts.
+func (d *btrfs) Qcow2DeletionCleanup(snapVol Volume, childName string) error {
+	return genericVFSQcow2DeletionCleanup(d, snapVol, childName)
 }
 
 // ActivateTask allows running a function while the volume is active (but not mounted).
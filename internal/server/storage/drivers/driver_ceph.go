@@ -101,6 +101,7 @@ func (d *ceph) Info() Info {
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  false,
+		Encryption:                   true,
 	}
 }
 
@@ -413,6 +414,8 @@ func (d *ceph) Validate(config map[string]string) error {
 		//  default: `true`
 		//  shortdesc: Whether the pool was empty on creation time
 		"volatile.pool.pristine": validate.IsAny,
+
+		"volume.security.encrypted": validate.Optional(validate.IsBool),
 	}
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
@@ -502,6 +505,24 @@ func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the storage pool.
+// This is the health of the whole Ceph cluster as any of its problems may affect the OSD pool.
+func (d *ceph) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	var stdout bytes.Buffer
+
+	err := subprocess.RunCommandWithFds(context.TODO(), nil, &stdout,
+		"ceph",
+		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
+		"--cluster", d.config["ceph.cluster_name"],
+		"health",
+		"-f", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	return cephParseHealth(stdout.Bytes())
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
@@ -159,6 +159,8 @@ func (d *ceph) rbdCreateVolume(vol Volume, size string) error {
 		return err
 	}
 
+	sizeBytes = luksBackingSizeBytes(vol, sizeBytes)
+
 	cmd := []string{
 		"--id", d.config["ceph.user.name"],
 		"--cluster", d.config["ceph.cluster_name"],
@@ -1468,3 +1470,53 @@ func (d *ceph) resizeVolume(vol Volume, sizeBytes int64, allowShrink bool) error
 
 	return err
 }
+
+// cephParseHealth parses the output of `ceph health --format json`.
+func cephParseHealth(data []byte) (*api.ResourcesStoragePoolHealth, error) {
+	type cephHealthCheck struct {
+		Summary struct {
+			Message string `json:"message"`
+		} `json:"summary"`
+	}
+
+	type cephHealth struct {
+		Status string                     `json:"status"`
+		Checks map[string]cephHealthCheck `json:"checks"`
+	}
+
+	status := cephHealth{}
+	err := json.Unmarshal(data, &status)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ceph health: %w", err)
+	}
+
+	health := &api.ResourcesStoragePoolHealth{}
+
+	switch status.Status {
+	case "HEALTH_OK":
+		health.Status = api.StoragePoolHealthHealthy
+	case "HEALTH_WARN":
+		health.Status = api.StoragePoolHealthDegraded
+	case "HEALTH_ERR":
+		health.Status = api.StoragePoolHealthFailed
+	default:
+		return nil, fmt.Errorf("Unknown ceph health status %q", status.Status)
+	}
+
+	// Sort the checks for a stable message.
+	names := make([]string, 0, len(status.Checks))
+	for name := range status.Checks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, status.Checks[name].Summary.Message)
+	}
+
+	health.Message = strings.Join(messages, "; ")
+
+	return health, nil
+}
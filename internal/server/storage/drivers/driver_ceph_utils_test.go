@@ -3,6 +3,11 @@ package drivers
 import (
 	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
 )
 
 func Test_ceph_getRBDVolumeName(t *testing.T) {
@@ -226,3 +231,22 @@ func Example_ceph_parseParent() {
 	//   contentType: filesystem
 	//   config: map[]
 }
+
+func Test_cephParseHealth(t *testing.T) {
+	health, err := cephParseHealth([]byte(`{"status":"HEALTH_OK","checks":{},"mutes":[]}`))
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthHealthy, health.Status)
+	assert.Empty(t, health.Message)
+
+	health, err = cephParseHealth([]byte(`{"status":"HEALTH_WARN","checks":{"PG_DEGRADED":{"severity":"HEALTH_WARN","summary":{"message":"Degraded data redundancy: 12/36 objects degraded","count":12},"muted":false},"OSD_DOWN":{"severity":"HEALTH_WARN","summary":{"message":"1 osds down","count":1},"muted":false}},"mutes":[]}`))
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+	assert.Equal(t, "1 osds down; Degraded data redundancy: 12/36 objects degraded", health.Message)
+
+	health, err = cephParseHealth([]byte(`{"status":"HEALTH_ERR","checks":{}}`))
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthFailed, health.Status)
+
+	_, err = cephParseHealth([]byte(`{"status":"HEALTH_UNKNOWN"}`))
+	assert.Error(t, err)
+}
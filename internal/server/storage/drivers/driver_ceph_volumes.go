@@ -155,6 +155,14 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 
 	reverter.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 
+	// Format the volume for encryption.
+	if vol.IsEncrypted() {
+		err = d.luksFormat(vol, devPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get filesystem.
 	RBDFilesystem := vol.ConfigBlockFilesystem()
 
@@ -190,6 +198,12 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 				if err != nil {
 					return err
 				}
+
+				// Fill encrypted volumes through the unlocked device.
+				devPath, err = d.luksDiskPath(vol, devPath)
+				if err != nil {
+					return err
+				}
 			}
 
 			allowUnsafeResize := false
@@ -963,6 +977,15 @@ func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.initial.uid` or `0`
 	//  shortdesc: UID of the volume owner in the instance
 
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=security.encrypted)
+	// The volume is formatted with LUKS2 when created and unlocked with a key from the server's key store when mounted.
+	// This can only be set when creating the volume.
+	// ---
+	//  type: bool
+	//  condition: custom block volume or virtual-machine volume
+	//  default: same as `volume.security.encrypted` or `false`
+	//  shortdesc: Encrypt the volume
+
 	// gendoc:generate(entity=storage_volume_ceph, group=common, key=security.shared)
 	//
 	// ---
@@ -1027,6 +1050,137 @@ func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.expiry)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: When scheduled backups stored on the server are to be deleted (expects an expression like `1M 2H 3d 4w 5m 6y`)
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.retention.daily)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of daily scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.retention.last)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of most recent scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.retention.weekly)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of weekly scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable automatic backups (the default)
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 access key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 bucket for scheduled backups (required when `backups.target.url` is set)
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.target.format)
+	// `tarball` or `repository` (see {ref}`storage-backup-schedule`)
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  condition: custom volume
+	//  shortdesc: How scheduled backups are stored on the backup target
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.target.path)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 secret key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.target.url)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: URL of the S3 server to upload scheduled backups to
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=backups.volume_only)
+	//
+	// ---
+	//  type: bool
+	//  condition: custom volume
+	//  default: `false`
+	//  shortdesc: Whether to leave volume snapshots out of scheduled backups
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=replication.retention)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  default: `1`
+	//  shortdesc: Number of replication snapshots to keep
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=replication.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable replication (the default)
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=replication.target)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Name of a local storage pool or URL of a remote server (`https://<address>:<port>`) to replicate the volume to
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=replication.target.certificate)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: PEM encoded certificate of the remote replication server (the system trust store is used when unset)
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=replication.target.pool)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's pool
+	//  shortdesc: Storage pool on the remote replication server
+
+	// gendoc:generate(entity=storage_volume_ceph, group=common, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's project
+	//  shortdesc: Project on the remote replication server
+
 	commonRules := d.commonVolumeRules()
 
 	// Disallow block.* settings for regular custom block volumes. These settings only make sense
@@ -1163,6 +1317,8 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 		defer logger.WarnOnError(func() error { return d.rbdUnmapVolume(vol, true) }, "Failed to unmap volume")
 	}
 
+	sizeBytes = luksBackingSizeBytes(vol, sizeBytes)
+
 	oldSizeBytes, err := BlockDiskSizeBytes(devPath)
 	if err != nil {
 		return fmt.Errorf("Error getting current size: %w", err)
@@ -1250,10 +1406,16 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 			return err
 		}
 
+		// Grow the unlocked device of encrypted volumes.
+		err = d.luksResize(vol)
+		if err != nil {
+			return err
+		}
+
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
 		// expected the caller will do all necessary post resize actions themselves).
 		if vol.IsVMBlock() && !allowUnsafeResize {
-			err = d.moveGPTAltHeader(devPath)
+			err = d.moveVolumeGPTAltHeader(vol, devPath)
 			if err != nil {
 				return err
 			}
@@ -1435,6 +1597,16 @@ func (d *ceph) MountVolume(vol Volume, op *operations.Operation) error {
 				return err
 			}
 		}
+
+		// Unlock encrypted volumes.
+		unlocked, err := d.luksMount(vol, volDevPath)
+		if err != nil {
+			return err
+		}
+
+		if unlocked {
+			reverter.Add(func() { _ = d.luksClose(vol) })
+		}
 	}
 
 	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
@@ -1499,8 +1671,13 @@ func (d *ceph) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Opera
 					return false, ErrInUse
 				}
 
+				err = d.luksClose(vol)
+				if err != nil {
+					return false, err
+				}
+
 				// Attempt to unmap.
-				err := d.rbdUnmapVolume(vol, true)
+				err = d.rbdUnmapVolume(vol, true)
 				if err != nil {
 					return false, err
 				}
@@ -1866,11 +2043,23 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 		d.logger.Debug("Mounted RBD volume snapshot", logger.Ctx{"dev": rbdDevPath, "path": mountPath, "options": mountOptions})
 	} else if snapVol.contentType == ContentTypeBlock {
 		// Activate RBD volume if needed.
-		_, _, err := d.getRBDMappedDevPath(snapVol, true)
+		_, volDevPath, err := d.getRBDMappedDevPath(snapVol, true)
 		if err != nil {
 			return err
 		}
 
+		// Unlock encrypted volumes.
+		if snapVol.IsEncrypted() {
+			unlocked, err := d.luksMount(snapVol, volDevPath)
+			if err != nil {
+				return err
+			}
+
+			if unlocked {
+				reverter.Add(func() { _ = d.luksClose(snapVol) })
+			}
+		}
+
 		// For VMs, mount the filesystem volume.
 		if snapVol.IsVMBlock() {
 			fsVol := snapVol.NewVMBlockFilesystemVolume()
@@ -1954,7 +2143,12 @@ func (d *ceph) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (
 				return false, ErrInUse
 			}
 
-			err := d.rbdUnmapVolume(snapVol, true)
+			err := d.luksClose(snapVol)
+			if err != nil {
+				return false, err
+			}
+
+			err = d.rbdUnmapVolume(snapVol, true)
 			if err != nil {
 				return false, err
 			}
@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 
 	"github.com/lxc/incus/v7/internal/server/operations"
@@ -34,6 +35,11 @@ func (d *cephobject) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: -
 	//  shortdesc: Quota of the storage bucket
 
+	// Object versioning is only implemented by the built-in S3 server used for local buckets.
+	if vol.config["versioning"] != "" {
+		return errors.New("Bucket versioning isn't supported by the cephobject driver")
+	}
+
 	return d.validateVolume(vol, nil, removeUnknownKeys)
 }
 
@@ -214,6 +220,137 @@ func (d *cephobject) UpdateBucket(bucket Volume, changedConfig map[string]string
 	return nil
 }
 
+// bucketS3Client returns an S3 client using the credentials of the bucket user.
+func (d *cephobject) bucketS3Client(bucket Volume) (*s3.Client, string, error) {
+	_, bucketName := project.StorageVolumeParts(bucket.name)
+	storageBucketName := d.radosgwBucketName(bucketName)
+
+	creds, _, err := d.radosgwadminGetUser(context.TODO(), storageBucketName)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed getting bucket user: %w", err)
+	}
+
+	s3Client, err := d.s3Client(*creds)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s3Client, storageBucketName, nil
+}
+
+// GetBucketLifecycle returns the lifecycle configuration of a bucket.
+func (d *cephobject) GetBucketLifecycle(bucket Volume) (*api.StorageBucketLifecycle, error) {
+	s3Client, storageBucketName, err := d.bucketS3Client(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, ctxCancel := context.WithTimeout(context.TODO(), time.Duration(time.Second*30))
+	defer ctxCancel()
+
+	lifecycle := &api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{}}
+
+	resp, err := s3Client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(storageBucketName),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration" {
+			return lifecycle, nil
+		}
+
+		return nil, fmt.Errorf("Failed getting bucket lifecycle: %w", err)
+	}
+
+	for _, r := range resp.Rules {
+		rule := api.StorageBucketLifecycleRule{
+			ID:      aws.ToString(r.ID),
+			Prefix:  aws.ToString(r.Prefix),
+			Enabled: r.Status == s3types.ExpirationStatusEnabled,
+		}
+
+		if r.Filter != nil && r.Filter.Prefix != nil {
+			rule.Prefix = *r.Filter.Prefix
+		}
+
+		if r.Expiration != nil {
+			rule.ExpirationDays = int(aws.ToInt32(r.Expiration.Days))
+		}
+
+		if r.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentVersionExpirationDays = int(aws.ToInt32(r.NoncurrentVersionExpiration.NoncurrentDays))
+		}
+
+		if r.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteMultipartUploadDays = int(aws.ToInt32(r.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+		}
+
+		lifecycle.Rules = append(lifecycle.Rules, rule)
+	}
+
+	return lifecycle, nil
+}
+
+// UpdateBucketLifecycle replaces the lifecycle configuration of a bucket.
+// The rules are applied by radosgw itself.
+func (d *cephobject) UpdateBucketLifecycle(bucket Volume, lifecycle api.StorageBucketLifecycle) error {
+	s3Client, storageBucketName, err := d.bucketS3Client(bucket)
+	if err != nil {
+		return err
+	}
+
+	ctx, ctxCancel := context.WithTimeout(context.TODO(), time.Duration(time.Second*30))
+	defer ctxCancel()
+
+	if len(lifecycle.Rules) == 0 {
+		_, err = s3Client.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(storageBucketName),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed deleting bucket lifecycle: %w", err)
+		}
+
+		return nil
+	}
+
+	rules := make([]s3types.LifecycleRule, 0, len(lifecycle.Rules))
+	for _, rule := range lifecycle.Rules {
+		r := s3types.LifecycleRule{
+			ID:     aws.String(rule.ID),
+			Filter: &s3types.LifecycleRuleFilter{Prefix: aws.String(rule.Prefix)},
+			Status: s3types.ExpirationStatusDisabled,
+		}
+
+		if rule.Enabled {
+			r.Status = s3types.ExpirationStatusEnabled
+		}
+
+		if rule.ExpirationDays > 0 {
+			r.Expiration = &s3types.LifecycleExpiration{Days: aws.Int32(int32(rule.ExpirationDays))}
+		}
+
+		if rule.NoncurrentVersionExpirationDays > 0 {
+			r.NoncurrentVersionExpiration = &s3types.NoncurrentVersionExpiration{NoncurrentDays: aws.Int32(int32(rule.NoncurrentVersionExpirationDays))}
+		}
+
+		if rule.AbortIncompleteMultipartUploadDays > 0 {
+			r.AbortIncompleteMultipartUpload = &s3types.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int32(int32(rule.AbortIncompleteMultipartUploadDays))}
+		}
+
+		rules = append(rules, r)
+	}
+
+	_, err = s3Client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(storageBucketName),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: rules},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed setting bucket lifecycle: %w", err)
+	}
+
+	return nil
+}
+
 // bucketKeyRadosgwAccessRole returns the radosgw access setting for the specified role name.
 func (d *cephobject) bucketKeyRadosgwAccessRole(roleName string) (string, error) {
 	switch roleName {
@@ -11,6 +11,8 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/google/uuid"
+
 	"github.com/lxc/incus/v7/internal/instancewriter"
 	"github.com/lxc/incus/v7/internal/linux"
 	"github.com/lxc/incus/v7/internal/migration"
@@ -19,10 +21,12 @@ import (
 	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
 	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/logger"
 	"github.com/lxc/incus/v7/shared/revert"
 	"github.com/lxc/incus/v7/shared/subprocess"
 	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/validate"
 )
 
 type common struct {
@@ -133,19 +137,81 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// security.encrypted is only relevant for new block volumes on drivers supporting encryption.
+		if volKey == "security.encrypted" && (vol.hasSource || vol.contentType != ContentTypeBlock || !d.supportsEncryption(*vol)) {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
 	}
 
+	// Allocate the key identifier of new encrypted volumes.
+	if vol.IsEncrypted() && vol.config["volatile.encryption.key_id"] == "" {
+		if vol.hasSource {
+			return errors.New("Encryption can only be enabled on new volumes")
+		}
+
+		err := CheckKeystore(d.state, vol.driver.Info())
+		if err != nil {
+			return err
+		}
+
+		vol.config["volatile.encryption.key_id"] = uuid.New().String()
+	}
+
 	return nil
 }
 
+// supportsEncryption returns true if the volume can be encrypted.
+// Virtual machine filesystem volumes share the config of their block volume so are included.
+func (d *common) supportsEncryption(vol Volume) bool {
+	if vol.volType != VolumeTypeVM && !vol.IsCustomBlock() {
+		return false
+	}
+
+	return vol.driver.Info().Encryption
+}
+
 // FillVolumeConfig populate volume with default config.
 func (d *common) FillVolumeConfig(vol Volume) error {
 	return d.fillVolumeConfig(&vol)
 }
 
+// fillVolumeBlockType sets the format of a block volume kept as a disk file, which follows the pool's
+// volume.block.type as the layout of the volume's snapshots depends on it.
+func (d *common) fillVolumeBlockType(vol *Volume) {
+	if !vol.IsVMBlock() && !vol.IsCustomBlock() {
+		return
+	}
+
+	if d.config["volume.block.type"] == BlockVolumeTypeQcow2 {
+		vol.config["block.type"] = BlockVolumeTypeQcow2
+	} else {
+		delete(vol.config, "block.type")
+	}
+}
+
+// validateVolumeBlockType checks that the format of a block volume kept as a disk file matches the pool's.
+func (d *common) validateVolumeBlockType(vol Volume) error {
+	blockType := vol.config["block.type"]
+	if blockType == "" {
+		return nil
+	}
+
+	poolBlockType := d.config["volume.block.type"]
+	if poolBlockType == "" {
+		poolBlockType = BlockVolumeTypeRaw
+	}
+
+	if blockType != poolBlockType {
+		return fmt.Errorf("block.type must match the pool's volume.block.type (%q)", poolBlockType)
+	}
+
+	return nil
+}
+
 // validateVolume validates a volume config against common rules and optional driver specific rules.
 // This functions has a removeUnknownKeys option that if set to true will remove any unknown fields
 // (excluding those starting with "user.") which can be used when translating a volume config to a
@@ -159,6 +225,12 @@ func (d *common) validateVolume(vol Volume, driverRules map[string]func(value st
 	// Merge driver specific rules into common rules.
 	maps.Copy(rules, driverRules)
 
+	// Encryption settings are only relevant for volumes which can be encrypted.
+	if d.supportsEncryption(vol) {
+		rules["security.encrypted"] = validate.Optional(validate.IsBool)
+		rules["volatile.encryption.key_id"] = validate.IsAny
+	}
+
 	// Run the validator against each field.
 	for k, validator := range rules {
 		checkedFields[k] = struct{}{} // Mark field as checked.
@@ -205,6 +277,10 @@ key:
 		return errors.New("security.unmapped and security.shifted are mutually exclusive")
 	}
 
+	if util.IsTrue(vol.config["security.encrypted"]) && vol.config["block.type"] == BlockVolumeTypeQcow2 {
+		return errors.New("security.encrypted cannot be used with QCOW2 volumes")
+	}
+
 	if util.IsTrue(vol.config["dependent"]) {
 		err := ValidateDependentConfigKey(vol.config)
 		if err != nil {
@@ -222,6 +298,11 @@ func (d *common) updateVolume(vol Volume, changedConfig map[string]string) error
 		return errors.New("dependent cannot be changed")
 	}
 
+	_, changed = changedConfig["security.encrypted"]
+	if changed {
+		return errors.New("security.encrypted cannot be changed")
+	}
+
 	return nil
 }
 
@@ -552,6 +633,26 @@ func (d *common) DeleteBucketKey(bucket Volume, keyName string, op *operations.O
 	return nil
 }
 
+// GetBucketLifecycle returns the lifecycle configuration of a bucket.
+func (d *common) GetBucketLifecycle(bucket Volume) (*api.StorageBucketLifecycle, error) {
+	return nil, ErrNotSupported
+}
+
+// UpdateBucketLifecycle replaces the lifecycle configuration of a bucket.
+func (d *common) UpdateBucketLifecycle(bucket Volume, lifecycle api.StorageBucketLifecycle) error {
+	return ErrNotSupported
+}
+
+// GetHealth returns the health of the storage pool.
+func (d *common) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	return nil, ErrNotSupported
+}
+
+// Scrub verifies the integrity of the data stored on the pool.
+func (d *common) Scrub(op *operations.Operation) error {
+	return ErrNotSupported
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of MinBlockBoundary.
 func (d *common) roundVolumeBlockSizeBytes(vol Volume, sizeBytes int64) (int64, error) {
@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/validate"
 )
 
 type dir struct {
@@ -25,6 +27,11 @@ func (d *dir) load() error {
 
 // Info returns info about the driver and its environment.
 func (d *dir) Info() Info {
+	targetFormat := BlockVolumeTypeRaw
+	if d.config["volume.block.type"] == BlockVolumeTypeQcow2 {
+		targetFormat = BlockVolumeTypeQcow2
+	}
+
 	return Info{
 		Name:                         "dir",
 		Version:                      "1",
@@ -41,6 +48,8 @@ func (d *dir) Info() Info {
 		IOUring:                      true,
 		MountedRoot:                  true,
 		Buckets:                      true,
+		Encryption:                   true,
+		TargetFormat:                 targetFormat,
 	}
 }
 
@@ -131,11 +140,20 @@ func (d *dir) Validate(config map[string]string) error {
 	//  default: -
 	//  shortdesc: Path to an existing directory
 
-	return d.validatePool(config, nil, nil)
+	rules := map[string]func(value string) error{
+		"volume.security.encrypted": validate.Optional(validate.IsBool),
+	}
+
+	return d.validatePool(config, rules, d.commonVolumeRules())
 }
 
 // Update applies any driver changes required from a configuration change.
 func (d *dir) Update(changedConfig map[string]string) error {
+	_, changed := changedConfig["volume.block.type"]
+	if changed {
+		return errors.New("volume.block.type cannot be changed after creation")
+	}
+
 	return nil
 }
 
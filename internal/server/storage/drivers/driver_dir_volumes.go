@@ -20,6 +20,7 @@ import (
 	"github.com/lxc/incus/v7/shared/revert"
 	"github.com/lxc/incus/v7/shared/units"
 	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/validate"
 )
 
 // CreateVolume creates an empty volume and can optionally fill it by executing the supplied
@@ -64,8 +65,47 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		}
 	}
 
+	// Encrypted volumes are formatted up front and filled through the unlocked device.
+	fillPath := rootBlockPath
+	if IsQcow2Block(vol) {
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		// The filler overwrites the empty image with the qcow2 one of the source.
+		err = ensureQcow2File(rootBlockPath, sizeBytes)
+		if err != nil {
+			return err
+		}
+	} else if vol.IsEncrypted() {
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		_, err = ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
+		if err != nil {
+			return err
+		}
+
+		err = d.luksFormat(vol, rootBlockPath)
+		if err != nil {
+			return err
+		}
+
+		if filler != nil && filler.Fill != nil {
+			fillPath, err = d.luksOpen(vol, rootBlockPath)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = d.luksClose(vol) }()
+		}
+	}
+
 	// Run the volume filler function if supplied.
-	err = genericRunFiller(d, vol, rootBlockPath, filler, false)
+	err = genericRunFiller(d, vol, fillPath, filler, false)
 	if err != nil {
 		return err
 	}
@@ -80,6 +120,17 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 			return err
 		}
 
+		// The qcow2 image only needs to be grown, its data was already placed by the filler.
+		if IsQcow2Block(vol) {
+			err = ensureQcow2File(rootBlockPath, sizeBytes)
+			if err != nil {
+				return err
+			}
+
+			reverter.Success()
+			return nil
+		}
+
 		// Ignore ErrCannotBeShrunk when setting size this just means the filler run above has needed to
 		// increase the volume size beyond the default block volume size.
 		_, err = ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
@@ -89,7 +140,7 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 
 		// Move the GPT alt header to end of disk if needed and if filler specified.
 		if vol.IsVMBlock() && filler != nil && filler.Fill != nil {
-			err = d.moveGPTAltHeader(rootBlockPath)
+			err = d.moveVolumeGPTAltHeader(vol, rootBlockPath)
 			if err != nil {
 				return err
 			}
@@ -205,6 +256,11 @@ func (d *dir) DeleteVolume(vol Volume, op *operations.Operation) error {
 		return nil
 	}
 
+	err = d.luksClose(vol)
+	if err != nil {
+		return err
+	}
+
 	// Get the volume ID for the volume, which is used to remove project quota.
 	if vol.Type() != VolumeTypeBucket {
 		volID, err := d.getVolID(vol.volType, vol.name)
@@ -244,11 +300,13 @@ func (d *dir) HasVolume(vol Volume) (bool, error) {
 func (d *dir) FillVolumeConfig(vol Volume) error {
 	initialSize := vol.config["size"]
 
-	err := d.fillVolumeConfig(&vol)
+	err := d.fillVolumeConfig(&vol, "block.type")
 	if err != nil {
 		return err
 	}
 
+	d.fillVolumeBlockType(&vol)
+
 	// Buckets do not support default volume size.
 	// If size is specified manually, do not remove, so it triggers validation failure and an error to user.
 	if vol.volType == VolumeTypeBucket && initialSize == "" {
@@ -284,6 +342,15 @@ func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.initial.uid` or `0`
 	//  shortdesc: UID of the volume owner in the instance
 
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=security.encrypted)
+	// The volume is formatted with LUKS2 when created and unlocked with a key from the server's key store when mounted.
+	// This can only be set when creating the volume.
+	// ---
+	//  type: bool
+	//  condition: custom block volume or virtual-machine volume
+	//  default: same as `volume.security.encrypted` or `false`
+	//  shortdesc: Encrypt the volume
+
 	// gendoc:generate(entity=storage_volume_dir, group=common, key=security.shared)
 	//
 	// ---
@@ -348,7 +415,138 @@ func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
-	err := d.validateVolume(vol, nil, removeUnknownKeys)
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.expiry)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: When scheduled backups stored on the server are to be deleted (expects an expression like `1M 2H 3d 4w 5m 6y`)
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.retention.daily)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of daily scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.retention.last)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of most recent scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.retention.weekly)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of weekly scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable automatic backups (the default)
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 access key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 bucket for scheduled backups (required when `backups.target.url` is set)
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.target.format)
+	// `tarball` or `repository` (see {ref}`storage-backup-schedule`)
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  condition: custom volume
+	//  shortdesc: How scheduled backups are stored on the backup target
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.target.path)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 secret key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.target.url)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: URL of the S3 server to upload scheduled backups to
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=backups.volume_only)
+	//
+	// ---
+	//  type: bool
+	//  condition: custom volume
+	//  default: `false`
+	//  shortdesc: Whether to leave volume snapshots out of scheduled backups
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=replication.retention)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  default: `1`
+	//  shortdesc: Number of replication snapshots to keep
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=replication.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable replication (the default)
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=replication.target)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Name of a local storage pool or URL of a remote server (`https://<address>:<port>`) to replicate the volume to
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=replication.target.certificate)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: PEM encoded certificate of the remote replication server (the system trust store is used when unset)
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=replication.target.pool)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's pool
+	//  shortdesc: Storage pool on the remote replication server
+
+	// gendoc:generate(entity=storage_volume_dir, group=common, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's project
+	//  shortdesc: Project on the remote replication server
+
+	err := d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
 	if err != nil {
 		return err
 	}
@@ -357,9 +555,42 @@ func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		return errors.New("Size cannot be specified for buckets")
 	}
 
+	err = d.validateVolumeBlockType(vol)
+	if err != nil {
+		return err
+	}
+
+	if vol.config["block.type"] == BlockVolumeTypeQcow2 && util.IsTrue(vol.config["security.shared"]) {
+		return errors.New("QCOW2 volume type is incompatible with the 'security.shared' option.")
+	}
+
 	return nil
 }
 
+// commonVolumeRules returns validation rules which are common for pool and volume.
+func (d *dir) commonVolumeRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// gendoc:generate(entity=storage_volume_dir, group=common, key=block.type)
+		// The format of the disk file can only be chosen for the whole pool, through `volume.block.type`, when creating it.
+		// With `qcow2`, snapshots are kept as layers of the disk's backing chain and can be taken while the instance is running.
+		// ---
+		//  type: string
+		//  condition: block-based volume
+		//  default: same as `volume.block.type` or `raw`
+		//  shortdesc: Format of the disk file (`raw` or `qcow2`)
+		"block.type": validate.Optional(validate.IsOneOf(BlockVolumeTypeRaw, BlockVolumeTypeQcow2)),
+
+		// gendoc:generate(entity=storage_volume_dir, group=common, key=dir.remove_snapshots)
+		//
+		// ---
+		//  type: bool
+		//  condition: `qcow2` block-based volume
+		//  default: same as `volume.dir.remove_snapshots` or `false`
+		//  shortdesc: Remove snapshots as needed
+		"dir.remove_snapshots": validate.Optional(validate.IsBool),
+	}
+}
+
 // UpdateVolume applies config changes to the volume.
 func (d *dir) UpdateVolume(vol Volume, changedConfig map[string]string) error {
 	newSize, sizeChanged := changedConfig["size"]
@@ -370,6 +601,11 @@ func (d *dir) UpdateVolume(vol Volume, changedConfig map[string]string) error {
 		}
 	}
 
+	_, changed := changedConfig["block.type"]
+	if changed {
+		return errors.New("block.type cannot be changed after creation")
+	}
+
 	return d.updateVolume(vol, changedConfig)
 }
 
@@ -415,7 +651,8 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 	// For VM block files, resize the file if needed.
 	if vol.contentType == ContentTypeBlock {
 		// Do nothing if size isn't specified.
-		if sizeBytes <= 0 {
+		// qcow2 images are resized by the backend as the running instance has to be told about it.
+		if sizeBytes <= 0 || IsQcow2Block(vol) {
 			return nil
 		}
 
@@ -429,11 +666,19 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return err
 		}
 
+		// Grow the unlocked device of encrypted volumes.
+		if resized {
+			err = d.luksResize(vol)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Move the GPT alt header to end of disk if needed and resize has taken place (not needed in
 		// unsafe resize mode as it is expected the caller will do all necessary post resize actions
 		// themselves).
 		if vol.IsVMBlock() && resized && !allowUnsafeResize {
-			err = d.moveGPTAltHeader(rootBlockPath)
+			err = d.moveVolumeGPTAltHeader(vol, rootBlockPath)
 			if err != nil {
 				return err
 			}
@@ -449,7 +694,11 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 
 		// Custom handling for filesystem volume associated with a VM.
 		volPath := vol.MountPath()
-		if sizeBytes > 0 && vol.volType == VolumeTypeVM && util.PathExists(filepath.Join(volPath, genericVolumeDiskFile)) {
+		if vol.volType == VolumeTypeVM && vol.ExpandedConfig("block.type") == BlockVolumeTypeQcow2 {
+			// The qcow2 disk grows with its content and holds the snapshot layers, so the
+			// quota project is only used to track usage.
+			sizeBytes = 0
+		} else if sizeBytes > 0 && vol.volType == VolumeTypeVM && util.PathExists(filepath.Join(volPath, genericVolumeDiskFile)) {
 			// Get the size of the VM image.
 			blockSize, err := BlockDiskSizeBytes(filepath.Join(volPath, genericVolumeDiskFile))
 			if err != nil {
@@ -495,6 +744,21 @@ func (d *dir) MountVolume(vol Volume, op *operations.Operation) error {
 		}
 	}
 
+	// Unlock encrypted volumes.
+	if vol.IsEncrypted() {
+		rootBlockPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		if util.PathExists(rootBlockPath) {
+			_, err = d.luksMount(vol, rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
 	return nil
 }
@@ -515,6 +779,16 @@ func (d *dir) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operat
 		return false, ErrInUse
 	}
 
+	// Lock encrypted volumes.
+	if !keepBlockDev && LUKSMappedPath(vol) != "" {
+		err = d.luksClose(vol)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
 	return false, nil
 }
 
@@ -580,6 +854,22 @@ func (d *dir) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 			return err
 		}
 
+		// The qcow2 image becomes the snapshot layer and the backend then creates a new image on top of it.
+		// Moving it keeps the file open by a running instance valid.
+		if IsQcow2Block(snapVol) {
+			d.Logger().Debug("Moving qcow2 image to snapshot", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
+
+			err = os.Rename(srcDevPath, targetDevPath)
+			if err != nil {
+				return fmt.Errorf("Failed moving qcow2 image %q to snapshot: %w", srcDevPath, err)
+			}
+
+			reverter.Add(func() { _ = os.Rename(targetDevPath, srcDevPath) })
+
+			reverter.Success()
+			return nil
+		}
+
 		d.Logger().Debug("Copying block volume", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
 
 		err = ensureSparseFile(targetDevPath, 0)
@@ -639,11 +929,34 @@ func (d *dir) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) erro
 		}
 	}
 
-	_, err = mountReadOnly(snapPath, snapPath)
+	// The qcow2 snapshot layers are written to when merging them, so they can't be mounted read-only.
+	if isQcow2Volume(snapVol) {
+		snapVol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolumeSnapshot() when done.
+		return nil
+	}
+
+	ourMount, err := mountReadOnly(snapPath, snapPath)
 	if err != nil {
 		return err
 	}
 
+	// Unlock encrypted volumes.
+	if snapVol.IsEncrypted() {
+		rootBlockPath, err := d.GetVolumeDiskPath(snapVol)
+		if err != nil {
+			return err
+		}
+
+		_, err = d.luksMount(snapVol, rootBlockPath)
+		if err != nil {
+			if ourMount {
+				_, _ = forceUnmount(snapPath)
+			}
+
+			return err
+		}
+	}
+
 	snapVol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolumeSnapshot() when done.
 	return nil
 }
@@ -667,6 +980,11 @@ func (d *dir) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 			return false, ErrInUse
 		}
 
+		err = d.luksClose(snapVol)
+		if err != nil {
+			return false, err
+		}
+
 		snapPath := snapVol.MountPath()
 		return forceUnmount(snapPath)
 	}
@@ -741,6 +1059,16 @@ func (d *dir) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *o
 	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
 }
 
+// GetQcow2BackingFilePath generates the backing file path for the specified volume.
+func (d *dir) GetQcow2BackingFilePath(vol Volume) (string, error) {
+	return d.GetVolumeDiskPath(vol)
+}
+
+// Qcow2DeletionCleanup performs post block-commit cleanup of qcow2 snapshot artifacts.
+func (d *dir) Qcow2DeletionCleanup(snapVol Volume, childName string) error {
+	return genericVFSQcow2DeletionCleanup(d, snapVol, childName)
+}
+
 // ActivateTask allows running a function while the volume is active (but not mounted).
 func (d *dir) ActivateTask(vol Volume, task func(devPath string, op *operations.Operation) error, op *operations.Operation) error {
 	// Prevent concurrent mounting actions.
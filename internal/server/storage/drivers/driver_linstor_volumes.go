@@ -230,6 +230,137 @@ func (d *linstor) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.expiry)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: When scheduled backups stored on the server are to be deleted (expects an expression like `1M 2H 3d 4w 5m 6y`)
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.retention.daily)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of daily scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.retention.last)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of most recent scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.retention.weekly)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of weekly scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable automatic backups (the default)
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 access key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 bucket for scheduled backups (required when `backups.target.url` is set)
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.target.format)
+	// `tarball` or `repository` (see {ref}`storage-backup-schedule`)
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  condition: custom volume
+	//  shortdesc: How scheduled backups are stored on the backup target
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.target.path)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 secret key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.target.url)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: URL of the S3 server to upload scheduled backups to
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=backups.volume_only)
+	//
+	// ---
+	//  type: bool
+	//  condition: custom volume
+	//  default: `false`
+	//  shortdesc: Whether to leave volume snapshots out of scheduled backups
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=replication.retention)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  default: `1`
+	//  shortdesc: Number of replication snapshots to keep
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=replication.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable replication (the default)
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=replication.target)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Name of a local storage pool or URL of a remote server (`https://<address>:<port>`) to replicate the volume to
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=replication.target.certificate)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: PEM encoded certificate of the remote replication server (the system trust store is used when unset)
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=replication.target.pool)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's pool
+	//  shortdesc: Storage pool on the remote replication server
+
+	// gendoc:generate(entity=storage_volume_linstor, group=common, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's project
+	//  shortdesc: Project on the remote replication server
+
 	// gendoc:generate(entity=storage_volume_linstor, group=common, key=linstor.raw.*)
 	//
 	// ---
@@ -169,6 +169,7 @@ func (d *lvm) Info() Info {
 		Deactivate:                   d.isRemote(),
 		ZeroUnpack:                   !d.usesThinpool(),
 		TargetFormat:                 targetFormat,
+		Encryption:                   true,
 	}
 }
 
@@ -796,6 +797,8 @@ func (d *lvm) Validate(config map[string]string) error {
 		rules["lvm.vg.force_reuse"] = validate.Optional(validate.IsBool)
 	}
 
+	rules["volume.security.encrypted"] = validate.Optional(validate.IsBool)
+
 	err := d.validatePool(config, rules, d.commonVolumeRules())
 	if err != nil {
 		return err
@@ -1133,6 +1136,31 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the storage pool.
+func (d *lvm) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	vgName := d.config["lvm.vg_name"]
+
+	vgAttr, err := subprocess.RunCommandCLocale("vgs", "--noheadings", "-o", "vg_attr", vgName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting attributes of LVM volume group %q: %w", vgName, err)
+	}
+
+	pvs, err := subprocess.RunCommandCLocale("pvs", "--noheadings", "--separator", ",", "-o", "pv_name,pv_attr", "--select", "vg_name="+vgName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing physical volumes of LVM volume group %q: %w", vgName, err)
+	}
+
+	thinpool := ""
+	if d.usesThinpool() {
+		thinpool, err = subprocess.RunCommandCLocale("lvs", "--noheadings", "--units", "b", "--nosuffix", "--separator", ",", "-o", "lv_attr,lv_metadata_size,metadata_percent", fmt.Sprintf("%s/%s", vgName, d.thinpoolName()))
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting LVM thin pool %q: %w", d.thinpoolName(), err)
+		}
+	}
+
+	return lvmParseHealth(vgAttr, pvs, thinpool)
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of the volume group extent size.
 func (d *lvm) roundVolumeBlockSizeBytes(vol Volume, sizeBytes int64) (int64, error) {
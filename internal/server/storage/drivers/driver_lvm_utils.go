@@ -460,7 +460,7 @@ func (d *lvm) volumeBackingSizeBytes(vol Volume, size string) (int64, error) {
 		sizeBytes = RoundAbove(512, sizeBytes)
 	}
 
-	return sizeBytes, nil
+	return luksBackingSizeBytes(vol, sizeBytes), nil
 }
 
 // createLogicalVolume creates a logical volume.
@@ -1151,3 +1151,78 @@ func (d *lvm) getSourceType(source string) lvmSourceType {
 
 	return lvmSourceTypeUnknown
 }
+
+// lvmParseHealth returns the health of a pool from the `vg_attr` of its volume group, the `pv_name,pv_attr` list of
+// its physical volumes and, if a thin pool is used, the `lv_attr,lv_metadata_size,metadata_percent` of the thin pool.
+func lvmParseHealth(vgAttr string, pvs string, thinpool string) (*api.ResourcesStoragePoolHealth, error) {
+	health := &api.ResourcesStoragePoolHealth{Status: api.StoragePoolHealthHealthy}
+
+	for _, line := range util.SplitNTrimSpace(strings.TrimSpace(pvs), "\n", -1, true) {
+		parts := util.SplitNTrimSpace(line, ",", -1, false)
+		if len(parts) < 2 || len(parts[1]) < 3 {
+			return nil, fmt.Errorf("Unexpected output from pvs command: %q", line)
+		}
+
+		device := api.ResourcesStoragePoolHealthDevice{Name: parts[0], Status: "online"}
+		if parts[1][2] == 'm' {
+			device.Status = "missing"
+		}
+
+		health.Devices = append(health.Devices, device)
+	}
+
+	// The fourth volume group attribute is set when physical volumes are missing.
+	vgAttr = strings.TrimSpace(vgAttr)
+	if len(vgAttr) < 4 {
+		return nil, fmt.Errorf("Unexpected volume group attributes %q", vgAttr)
+	}
+
+	if vgAttr[3] == 'p' {
+		health.Status = api.StoragePoolHealthDegraded
+		health.Message = "One or more physical volumes of the volume group are missing"
+	}
+
+	if thinpool == "" {
+		return health, nil
+	}
+
+	parts := util.SplitNTrimSpace(strings.TrimSpace(thinpool), ",", -1, false)
+	if len(parts) < 3 || len(parts[0]) < 9 {
+		return nil, fmt.Errorf("Unexpected output from lvs command: %q", thinpool)
+	}
+
+	// The ninth logical volume attribute holds the health of the thin pool.
+	switch parts[0][8] {
+	case 'F':
+		health.Status = api.StoragePoolHealthFailed
+		health.Message = "The thin pool has failed"
+	case 'D':
+		health.Status = api.StoragePoolHealthFailed
+		health.Message = "The thin pool is out of data space"
+	case 'M':
+		health.Status = api.StoragePoolHealthFailed
+		health.Message = "The thin pool metadata is read-only"
+	}
+
+	// Metadata usage isn't available if the thin pool isn't active.
+	if parts[2] == "" {
+		return health, nil
+	}
+
+	metadataSize, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing thin pool metadata size (%q): %w", parts[1], err)
+	}
+
+	metadataPerc, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing thin pool metadata percentage (%q): %w", parts[2], err)
+	}
+
+	health.Metadata = &api.ResourcesStoragePoolSpace{
+		Total: metadataSize,
+		Used:  uint64(float64(metadataSize) * (metadataPerc / 100)),
+	}
+
+	return health, nil
+}
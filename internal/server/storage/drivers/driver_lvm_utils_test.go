@@ -2,6 +2,12 @@ package drivers
 
 import (
 	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
 )
 
 func Example_lvm_parseLogicalVolumeName() {
@@ -66,3 +72,36 @@ func Example_lvm_parseLogicalVolumeName() {
 	// custom_proj_testvol--with--hyphens.block: Unrecognised
 	// custom_proj_testvol--with--hyphens.block-snap1--with--hyphens.block: snap1-with-hyphens.block
 }
+
+func Test_lvmParseHealth(t *testing.T) {
+	pvs := "  /dev/sdb,a--\n  [unknown],a-m\n"
+
+	// Volume group missing a physical volume.
+	health, err := lvmParseHealth("  wz-pn-\n", pvs, "")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+	assert.Equal(t, []api.ResourcesStoragePoolHealthDevice{
+		{Name: "/dev/sdb", Status: "online"},
+		{Name: "[unknown]", Status: "missing"},
+	}, health.Devices)
+	assert.Nil(t, health.Metadata)
+
+	// Healthy thin pool reporting its metadata usage.
+	health, err = lvmParseHealth("  wz--n-\n", "  /dev/sdb,a--\n", "  twi-aotz--,8388608,85.00\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthHealthy, health.Status)
+	assert.Equal(t, &api.ResourcesStoragePoolSpace{Total: 8388608, Used: 7130316}, health.Metadata)
+
+	// Thin pool out of data space.
+	health, err = lvmParseHealth("  wz--n-\n", "  /dev/sdb,a--\n", "  twi-aotzD-,8388608,10.00\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthFailed, health.Status)
+
+	// Inactive thin pool.
+	health, err = lvmParseHealth("  wz--n-\n", "  /dev/sdb,a--\n", "  twi---tz--,8388608,\n")
+	require.NoError(t, err)
+	assert.Nil(t, health.Metadata)
+
+	_, err = lvmParseHealth("", pvs, "")
+	assert.Error(t, err)
+}
@@ -59,6 +59,19 @@ func (d *lvm) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		reverter.Add(func() { _ = d.DeleteVolume(fsVol, op) })
 	}
 
+	// Format the LV for encryption.
+	if vol.IsEncrypted() {
+		devPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		err = d.luksFormat(vol, devPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Format LV as qcow2 (lvmcluster).
 	if IsQcow2Block(vol) {
 		// Get the device path.
@@ -95,6 +108,12 @@ func (d *lvm) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 				if err != nil {
 					return err
 				}
+
+				// Fill encrypted volumes through the unlocked device.
+				devPath, err = d.luksDiskPath(vol, devPath)
+				if err != nil {
+					return err
+				}
 			}
 
 			allowUnsafeResize := false
@@ -256,6 +275,11 @@ func (d *lvm) DeleteVolume(vol Volume, op *operations.Operation) error {
 			}
 		}
 
+		err = d.luksClose(vol)
+		if err != nil {
+			return err
+		}
+
 		err = d.removeLogicalVolume(d.lvmPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
 		if err != nil {
 			return fmt.Errorf("Error removing LVM logical volume: %w", err)
@@ -461,6 +485,15 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.initial.uid` or `0`
 	//  shortdesc: UID of the volume owner in the instance
 
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=security.encrypted)
+	// The volume is formatted with LUKS2 when created and unlocked with a key from the server's key store when mounted.
+	// This can only be set when creating the volume.
+	// ---
+	//  type: bool
+	//  condition: custom block volume or virtual-machine volume
+	//  default: same as `volume.security.encrypted` or `false`
+	//  shortdesc: Encrypt the volume
+
 	// gendoc:generate(entity=storage_volume_lvm, group=common, key=security.shared)
 	//
 	// ---
@@ -525,6 +558,137 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.expiry)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: When scheduled backups stored on the server are to be deleted (expects an expression like `1M 2H 3d 4w 5m 6y`)
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.retention.daily)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of daily scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.retention.last)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of most recent scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.retention.weekly)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of weekly scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable automatic backups (the default)
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 access key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 bucket for scheduled backups (required when `backups.target.url` is set)
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.target.format)
+	// `tarball` or `repository` (see {ref}`storage-backup-schedule`)
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  condition: custom volume
+	//  shortdesc: How scheduled backups are stored on the backup target
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.target.path)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 secret key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.target.url)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: URL of the S3 server to upload scheduled backups to
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=backups.volume_only)
+	//
+	// ---
+	//  type: bool
+	//  condition: custom volume
+	//  default: `false`
+	//  shortdesc: Whether to leave volume snapshots out of scheduled backups
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=replication.retention)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  default: `1`
+	//  shortdesc: Number of replication snapshots to keep
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=replication.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable replication (the default)
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=replication.target)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Name of a local storage pool or URL of a remote server (`https://<address>:<port>`) to replicate the volume to
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=replication.target.certificate)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: PEM encoded certificate of the remote replication server (the system trust store is used when unset)
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=replication.target.pool)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's pool
+	//  shortdesc: Storage pool on the remote replication server
+
+	// gendoc:generate(entity=storage_volume_lvm, group=common, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's project
+	//  shortdesc: Project on the remote replication server
+
 	// gendoc:generate(entity=storage_bucket_lvm, group=common, key=size)
 	//
 	// ---
@@ -533,6 +697,15 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.size`
 	//  shortdesc: Size/quota of the storage bucket
 
+	// gendoc:generate(entity=storage_bucket_lvm, group=common, key=versioning)
+	// When unset, versioning was never configured on the bucket.
+	// Setting it to `false` suspends versioning, keeping the existing object versions.
+	// ---
+	//  type: bool
+	//  condition: appropriate driver
+	//  default: -
+	//  shortdesc: Whether to keep every version of the objects
+
 	commonRules := d.commonVolumeRules()
 
 	// Disallow block.* settings for regular custom block volumes. These settings only make sense
@@ -785,6 +958,12 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return err
 		}
 
+		// Grow the unlocked device of encrypted volumes.
+		err = d.luksResize(vol)
+		if err != nil {
+			return err
+		}
+
 		// On thick pools, discard the blocks in the additional space when the volume is grown.
 		if !d.usesThinpool() && oldSizeBytes < sizeBytes {
 			// Activate the volume for discarding.
@@ -832,7 +1011,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 				return err
 			}
 
-			err = d.moveGPTAltHeader(volDevPath)
+			err = d.moveVolumeGPTAltHeader(vol, volDevPath)
 			if err != nil {
 				return err
 			}
@@ -1078,6 +1257,23 @@ func (d *lvm) MountVolume(vol Volume, op *operations.Operation) error {
 				return err
 			}
 		}
+
+		// Unlock encrypted volumes.
+		if vol.IsEncrypted() {
+			volDevPath, err := d.lvmDevPath(d.lvmPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
+			if err != nil {
+				return err
+			}
+
+			unlocked, err := d.luksMount(vol, volDevPath)
+			if err != nil {
+				return err
+			}
+
+			if unlocked {
+				reverter.Add(func() { _ = d.luksClose(vol) })
+			}
+		}
 	}
 
 	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
@@ -1145,6 +1341,11 @@ func (d *lvm) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operat
 				return false, ErrInUse
 			}
 
+			err = d.luksClose(vol)
+			if err != nil {
+				return false, err
+			}
+
 			_, err = d.deactivateVolume(vol)
 			if err != nil {
 				return false, err
@@ -1613,6 +1814,23 @@ func (d *lvm) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) erro
 			return err
 		}
 
+		// Unlock encrypted volumes.
+		if snapVol.IsEncrypted() {
+			volDevPath, err := d.lvmDevPath(d.lvmPath(d.config["lvm.vg_name"], snapVol.volType, snapVol.contentType, snapVol.name))
+			if err != nil {
+				return err
+			}
+
+			unlocked, err := d.luksMount(snapVol, volDevPath)
+			if err != nil {
+				return err
+			}
+
+			if unlocked {
+				reverter.Add(func() { _ = d.luksClose(snapVol) })
+			}
+		}
+
 		// For VMs, mount the filesystem volume.
 		if snapVol.IsVMBlock() {
 			fsVol := snapVol.NewVMBlockFilesystemVolume()
@@ -1739,6 +1957,11 @@ func (d *lvm) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 				return false, ErrInUse
 			}
 
+			err = d.luksClose(snapVol)
+			if err != nil {
+				return false, err
+			}
+
 			_, err = d.deactivateVolume(snapVol)
 			if err != nil {
 				return false, err
@@ -23,6 +23,7 @@ type Info struct {
 	Deactivate                   bool         // Whether an unmount action is required prior to removing the pool.
 	ZeroUnpack                   bool         // Whether to write zeroes (no discard) during unpacking.
 	TargetFormat                 string       // Whether the output image format should be raw or qcow2.
+	Encryption                   bool         // Whether the driver supports LUKS encrypted block volumes.
 }
 
 // VolumeFiller provides a struct for filling a volume.
@@ -129,6 +129,7 @@ func (d *zfs) Info() Info {
 		DirectIO:                     true,
 		MountedRoot:                  false,
 		Buckets:                      true,
+		Encryption:                   true,
 	}
 
 	return info
@@ -574,6 +575,17 @@ func (d *zfs) Validate(config map[string]string) error {
 		//  default: `true`
 		//  shortdesc: Disable zpool export while unmount performed
 		"zfs.export": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=storage_zfs, group=common, key=scrub.schedule)
+		// The scrub covers the whole zpool, including when the pool only uses one of its datasets.
+		// ---
+		//  type: string
+		//  scope: global
+		//  default: -
+		//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable scheduled scrubs (the default)
+		"scrub.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+		"volume.security.encrypted": validate.Optional(validate.IsBool),
 	}
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
@@ -767,6 +779,31 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the storage pool.
+func (d *zfs) GetHealth() (*api.ResourcesStoragePoolHealth, error) {
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+
+	out, err := subprocess.RunCommandCLocale("zpool", "status", "-p", "-P", poolName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting status of zpool %q: %w", poolName, err)
+	}
+
+	return zfsParsePoolStatus(poolName, out)
+}
+
+// Scrub verifies the integrity of the data stored on the pool.
+// As the scrub covers the whole zpool, pools using a dataset of an existing zpool scrub all of it.
+func (d *zfs) Scrub(op *operations.Operation) error {
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+
+	_, err := subprocess.RunCommand("zpool", "scrub", "-w", poolName)
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing zpool %q: %w", poolName, err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
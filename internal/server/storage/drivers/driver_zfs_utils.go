@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -505,3 +506,91 @@ func (d *zfs) delegateDataset(vol Volume, pid int) error {
 func ZFSSupportsDelegation() bool {
 	return zfsDelegate
 }
+
+// zfsPoolStatusFields are the fields of the `zpool status` output.
+var zfsPoolStatusFields = []string{"pool", "state", "status", "action", "see", "scan", "remove", "checkpoint", "config", "errors"}
+
+// zfsParsePoolStatus parses the output of `zpool status -p` for the given zpool.
+func zfsParsePoolStatus(poolName string, output string) (*api.ResourcesStoragePoolHealth, error) {
+	fields := map[string]string{}
+	devices := []api.ResourcesStoragePoolHealthDevice{}
+
+	field := ""
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if found && !strings.HasPrefix(line, "\t") && slices.Contains(zfsPoolStatusFields, key) {
+			field = key
+			fields[field] = strings.TrimSpace(value)
+			continue
+		}
+
+		if field != "config" {
+			// Continuation of a multi-line field.
+			if field != "" && strings.TrimSpace(line) != "" {
+				fields[field] = strings.TrimSpace(fields[field] + " " + strings.TrimSpace(line))
+			}
+
+			continue
+		}
+
+		// Device rows hold the name, state and read, write and checksum error counters.
+		// Header, section (logs, cache, spares) and spare device rows are skipped.
+		row := strings.Fields(line)
+		if len(row) < 5 || row[0] == "NAME" || row[0] == poolName {
+			continue
+		}
+
+		counters := make([]uint64, 3)
+		for i := range counters {
+			var err error
+
+			counters[i], err = strconv.ParseUint(row[i+2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing error counter of device %q: %w", row[0], err)
+			}
+		}
+
+		devices = append(devices, api.ResourcesStoragePoolHealthDevice{
+			Name:           row[0],
+			Status:         row[1],
+			ReadErrors:     counters[0],
+			WriteErrors:    counters[1],
+			ChecksumErrors: counters[2],
+		})
+	}
+
+	if fields["state"] == "" {
+		return nil, fmt.Errorf("Failed finding state of zpool %q", poolName)
+	}
+
+	health := &api.ResourcesStoragePoolHealth{
+		Message: fields["status"],
+		Devices: devices,
+	}
+
+	switch fields["state"] {
+	case "ONLINE":
+		health.Status = api.StoragePoolHealthHealthy
+	case "DEGRADED":
+		health.Status = api.StoragePoolHealthDegraded
+	default:
+		health.Status = api.StoragePoolHealthFailed
+	}
+
+	// An online pool may still have hit errors that it couldn't repair.
+	if health.Status == api.StoragePoolHealthHealthy {
+		if fields["errors"] != "" && fields["errors"] != "No known data errors" {
+			health.Status = api.StoragePoolHealthDegraded
+			health.Message = strings.TrimSpace(health.Message + " " + fields["errors"])
+		}
+
+		for _, device := range devices {
+			if device.ReadErrors+device.WriteErrors+device.ChecksumErrors > 0 {
+				health.Status = api.StoragePoolHealthDegraded
+				break
+			}
+		}
+	}
+
+	return health, nil
+}
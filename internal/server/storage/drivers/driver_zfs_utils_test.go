@@ -0,0 +1,80 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func Test_zfsParsePoolStatus(t *testing.T) {
+	degraded := `  pool: tank
+ state: DEGRADED
+status: One or more devices could not be used because the label is missing or
+	invalid.  Sufficient replicas exist for the pool to continue
+	functioning in a degraded state.
+action: Replace the device using 'zpool replace'.
+   see: https://openzfs.github.io/openzfs-docs/msg/ZFS-8000-4J
+  scan: scrub repaired 0B in 00:00:01 with 0 errors on Sun Oct 11 00:24:01 2026
+config:
+
+	NAME                   STATE     READ WRITE CKSUM
+	tank                   DEGRADED     0     0     0
+	  mirror-0             DEGRADED     0     0     0
+	    /dev/sdb1          ONLINE       0     0     3
+	    12345678901234567  UNAVAIL      0     0     0  was /dev/sdc1
+	spares
+	  /dev/sdd1            AVAIL
+
+errors: No known data errors
+`
+
+	health, err := zfsParsePoolStatus("tank", degraded)
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+	assert.Equal(t, "One or more devices could not be used because the label is missing or invalid.  Sufficient replicas exist for the pool to continue functioning in a degraded state.", health.Message)
+	assert.Equal(t, []api.ResourcesStoragePoolHealthDevice{
+		{Name: "mirror-0", Status: "DEGRADED"},
+		{Name: "/dev/sdb1", Status: "ONLINE", ChecksumErrors: 3},
+		{Name: "12345678901234567", Status: "UNAVAIL"},
+	}, health.Devices)
+
+	// Online pools with device errors are degraded.
+	online := `  pool: tank
+ state: ONLINE
+config:
+
+	NAME         STATE     READ WRITE CKSUM
+	tank         ONLINE       0     0     0
+	  /dev/sdb1  ONLINE       2     0     0
+
+errors: No known data errors
+`
+
+	health, err = zfsParsePoolStatus("tank", online)
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+
+	health, err = zfsParsePoolStatus("tank", `  pool: tank
+ state: ONLINE
+config:
+
+	NAME         STATE     READ WRITE CKSUM
+	tank         ONLINE       0     0     0
+	  /dev/sdb1  ONLINE       0     0     0
+
+errors: 1 data errors, use '-v' for a list
+`)
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthDegraded, health.Status)
+	assert.Equal(t, "1 data errors, use '-v' for a list", health.Message)
+
+	health, err = zfsParsePoolStatus("tank", " state: SUSPENDED\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthFailed, health.Status)
+
+	_, err = zfsParsePoolStatus("tank", "")
+	assert.Error(t, err)
+}
@@ -177,8 +177,8 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 	} else {
 		var opts []string
 
-		if vol.contentType == ContentTypeFS {
-			// Use volmode=dev so volume is visible as we need to run makeFSType.
+		if vol.contentType == ContentTypeFS || vol.IsEncrypted() {
+			// Use volmode=dev so volume is visible as we need to run makeFSType or luksFormat.
 			opts = []string{"volmode=dev"}
 		} else {
 			// Use volmode=none so volume is invisible until mounted.
@@ -228,6 +228,8 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 			return err
 		}
 
+		sizeBytes = luksBackingSizeBytes(vol, sizeBytes)
+
 		// Create the volume dataset.
 		err = d.createVolume(d.dataset(vol, false), sizeBytes, opts...)
 		if err != nil {
@@ -237,7 +239,26 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		// After this point we'll have a volume, so setup revert.
 		reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
 
-		if vol.contentType == ContentTypeFS {
+		if vol.IsEncrypted() {
+			// Wait up to 30 seconds for the device to appear.
+			ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
+			defer cancel()
+
+			devPath, err := d.tryGetVolumeDiskPathFromDataset(ctx, d.dataset(vol, false))
+			if err != nil {
+				return err
+			}
+
+			err = d.luksFormat(vol, devPath)
+			if err != nil {
+				return err
+			}
+
+			err = d.setDatasetProperties(d.dataset(vol, false), "volmode=none")
+			if err != nil {
+				return err
+			}
+		} else if vol.contentType == ContentTypeFS {
 			// Wait up to 30 seconds for the device to appear.
 			ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
 			defer cancel()
@@ -285,6 +306,12 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 				if err != nil {
 					return err
 				}
+
+				// Fill encrypted volumes through the unlocked device.
+				devPath, err = d.luksDiskPath(vol, devPath)
+				if err != nil {
+					return err
+				}
 			}
 
 			allowUnsafeResize := false
@@ -1693,6 +1720,11 @@ func (d *zfs) deleteVolume(vol Volume, op *operations.Operation) error {
 	}
 
 	if exists {
+		err = d.luksClose(vol)
+		if err != nil {
+			return err
+		}
+
 		// Handle clones.
 		clones, err := d.getClones(d.dataset(vol, false))
 		if err != nil {
@@ -1857,6 +1889,15 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.initial.uid` or `0`
 	//  shortdesc: UID of the volume owner in the instance
 
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=security.encrypted)
+	// The volume is formatted with LUKS2 when created and unlocked with a key from the server's key store when mounted.
+	// This can only be set when creating the volume.
+	// ---
+	//  type: bool
+	//  condition: custom block volume or virtual-machine volume
+	//  default: same as `volume.security.encrypted` or `false`
+	//  shortdesc: Encrypt the volume
+
 	// gendoc:generate(entity=storage_volume_zfs, group=common, key=security.shared)
 	//
 	// ---
@@ -1921,6 +1962,137 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.expiry)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: When scheduled backups stored on the server are to be deleted (expects an expression like `1M 2H 3d 4w 5m 6y`)
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.retention.daily)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of daily scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.retention.last)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of most recent scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.retention.weekly)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  shortdesc: Number of weekly scheduled backups to keep
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable automatic backups (the default)
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.target.access_key)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 access key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.target.bucket)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 bucket for scheduled backups (required when `backups.target.url` is set)
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.target.format)
+	// `tarball` or `repository` (see {ref}`storage-backup-schedule`)
+	// ---
+	//  type: string
+	//  defaultdesc: `tarball`
+	//  condition: custom volume
+	//  shortdesc: How scheduled backups are stored on the backup target
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.target.path)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Path prefix for scheduled backups in the S3 bucket
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.target.secret_key)
+	// The value is replaced by `(hidden)` in API responses and sending that placeholder back keeps the current value.
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: S3 secret key for scheduled backups
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.target.url)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: URL of the S3 server to upload scheduled backups to
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=backups.volume_only)
+	//
+	// ---
+	//  type: bool
+	//  condition: custom volume
+	//  default: `false`
+	//  shortdesc: Whether to leave volume snapshots out of scheduled backups
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=replication.retention)
+	//
+	// ---
+	//  type: integer
+	//  condition: custom volume
+	//  default: `1`
+	//  shortdesc: Number of replication snapshots to keep
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=replication.schedule)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or empty to disable replication (the default)
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=replication.target)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: Name of a local storage pool or URL of a remote server (`https://<address>:<port>`) to replicate the volume to
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=replication.target.certificate)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  shortdesc: PEM encoded certificate of the remote replication server (the system trust store is used when unset)
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=replication.target.pool)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's pool
+	//  shortdesc: Storage pool on the remote replication server
+
+	// gendoc:generate(entity=storage_volume_zfs, group=common, key=replication.target.project)
+	//
+	// ---
+	//  type: string
+	//  condition: custom volume
+	//  default: same as the volume's project
+	//  shortdesc: Project on the remote replication server
+
 	// gendoc:generate(entity=storage_bucket_zfs, group=common, key=size)
 	//
 	// ---
@@ -1929,6 +2101,15 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.size`
 	//  shortdesc: Size/quota of the storage bucket
 
+	// gendoc:generate(entity=storage_bucket_zfs, group=common, key=versioning)
+	// When unset, versioning was never configured on the bucket.
+	// Setting it to `false` suspends versioning, keeping the existing object versions.
+	// ---
+	//  type: bool
+	//  condition: appropriate driver
+	//  default: -
+	//  shortdesc: Whether to keep every version of the objects
+
 	commonRules := d.commonVolumeRules()
 
 	// Disallow block.* settings for regular custom block volumes. These settings only make sense
@@ -2049,6 +2230,8 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return err
 		}
 
+		sizeBytes = luksBackingSizeBytes(vol, sizeBytes)
+
 		oldSizeBytesStr, err := d.getDatasetProperty(d.dataset(vol, false), "volsize")
 		if err != nil {
 			return err
@@ -2147,6 +2330,12 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			if err != nil {
 				return err
 			}
+
+			// Grow the unlocked device of encrypted volumes.
+			err = d.luksResize(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as
@@ -2158,7 +2347,7 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 					return err
 				}
 
-				return d.moveGPTAltHeader(devPath)
+				return d.moveVolumeGPTAltHeader(vol, devPath)
 			}, op)
 			if err != nil {
 				return err
@@ -2639,6 +2828,23 @@ func (d *zfs) MountVolume(vol Volume, op *operations.Operation) error {
 				return err
 			}
 		}
+
+		// Unlock encrypted volumes.
+		if vol.IsEncrypted() {
+			volPath, err := d.GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
+
+			unlocked, err := d.luksMount(vol, volPath)
+			if err != nil {
+				return err
+			}
+
+			if unlocked {
+				reverter.Add(func() { _ = d.luksClose(vol) })
+			}
+		}
 	}
 
 	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
@@ -2713,6 +2919,11 @@ func (d *zfs) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operat
 				return false, ErrInUse
 			}
 
+			err = d.luksClose(vol)
+			if err != nil {
+				return false, err
+			}
+
 			// For block devices, we make them disappear if active.
 			ourUnmount, err = d.deactivateVolume(vol)
 			if err != nil {
@@ -3446,6 +3657,23 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 			d.logger.Debug("Activated ZFS snapshot volume", logger.Ctx{"dev": snapshotDataset})
 		}
 
+		// Unlock encrypted volumes.
+		if snapVol.IsEncrypted() {
+			volPath, err := d.getVolumeDiskPathFromDataset(snapshotDataset)
+			if err != nil {
+				return nil, err
+			}
+
+			unlocked, err := d.luksMount(snapVol, volPath)
+			if err != nil {
+				return nil, err
+			}
+
+			if unlocked {
+				reverter.Add(func() { _ = d.luksClose(snapVol) })
+			}
+		}
+
 		if snapVol.contentType != ContentTypeBlock && d.isBlockBacked(snapVol) && !linux.IsMountPoint(mountPath) {
 			err = snapVol.EnsureMountPath(false)
 			if err != nil {
@@ -3638,7 +3866,12 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 				return false, ErrInUse
 			}
 
-			err := d.setDatasetProperties(parentDataset, "snapdev=hidden")
+			err := d.luksClose(snapVol)
+			if err != nil {
+				return false, err
+			}
+
+			err = d.setDatasetProperties(parentDataset, "snapdev=hidden")
 			if err != nil {
 				return false, err
 			}
@@ -759,12 +759,24 @@ func genericVFSCopyVolume(d Driver, initVolume func(vol Volume) (revert.Hook, er
 	var rsyncArgs []string
 
 	if srcVol.IsVMBlock() {
-		rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile)
+		rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile, "--exclude", Qcow2LayersDir)
 	}
 
 	reverter := revert.New()
 	defer reverter.Fail()
 
+	// qcow2Layers maps the source's qcow2 snapshot layers to the copied ones, so that the copied images can
+	// be layered on the target's snapshots rather than flattened. This requires copying the snapshots in
+	// the order of the backing chain.
+	qcow2Layers := map[string]string{}
+	if IsQcow2Block(srcVol) && !d.Info().BlockBacking && len(srcSnapshots) > 0 && !srcVol.IsSnapshot() {
+		var err error
+		srcSnapshots, err = qcow2SortSnapshots(srcVol, srcSnapshots)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create the main volume if not refreshing.
 	if !refresh {
 		err := d.CreateVolume(vol, nil, op)
@@ -800,6 +812,33 @@ func genericVFSCopyVolume(d Driver, initVolume func(vol Volume) (revert.Hook, er
 			return err
 		}
 
+		// qcow2 images kept in files are copied along with their position in the backing chain.
+		if IsQcow2Block(srcVol) && !d.Info().BlockBacking {
+			imgInfo, err := Qcow2Info(srcDevPath)
+			if err != nil {
+				return err
+			}
+
+			backingPath, found := qcow2Layers[imgInfo.BackingFilename]
+			if !found {
+				d.Logger().Debug("Flattening qcow2 image", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
+				return Qcow2Convert(srcDevPath, targetDevPath)
+			}
+
+			d.Logger().Debug("Copying qcow2 image", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath, "backingPath": backingPath})
+			err = ensureSparseFile(targetDevPath, 0)
+			if err != nil {
+				return err
+			}
+
+			err = copyDevice(srcDevPath, targetDevPath)
+			if err != nil {
+				return err
+			}
+
+			return Qcow2Rebase(targetDevPath, backingPath)
+		}
+
 		d.Logger().Debug("Copying block volume", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
 		err = copyDevice(srcDevPath, targetDevPath)
 		if err != nil {
@@ -855,6 +894,18 @@ func genericVFSCopyVolume(d Driver, initVolume func(vol Volume) (revert.Hook, er
 				reverter.Add(func() {
 					_ = d.DeleteVolumeSnapshot(snapVol, op)
 				})
+
+				if IsQcow2Block(srcVol) {
+					srcDevPath, err := d.GetVolumeDiskPath(srcVol)
+					if err != nil {
+						return err
+					}
+
+					qcow2Layers[srcDevPath], err = d.GetVolumeDiskPath(snapVol)
+					if err != nil {
+						return err
+					}
+				}
 			}
 		}
 
@@ -882,6 +933,24 @@ func genericVFSCopyVolume(d Driver, initVolume func(vol Volume) (revert.Hook, er
 				}
 			}
 
+			// The copied qcow2 image keeps the virtual size of the source, grow it to the volume's size.
+			if IsQcow2Block(vol) && !d.Info().BlockBacking {
+				targetDevPath, err := d.GetVolumeDiskPath(vol)
+				if err != nil {
+					return err
+				}
+
+				sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+				if err != nil {
+					return err
+				}
+
+				err = ensureQcow2File(targetDevPath, sizeBytes)
+				if err != nil {
+					return err
+				}
+			}
+
 			return nil
 		}, op)
 		if err != nil {
@@ -952,7 +1021,10 @@ func genericRunFiller(d Driver, vol Volume, devPath string, filler *VolumeFiller
 	}
 
 	vol.driver.Logger().Debug("Running filler function", logger.Ctx{"dev": devPath, "path": vol.MountPath()})
-	volSize, err := filler.Fill(vol, devPath, allowUnsafeResize, !d.Info().ZeroUnpack, d.Info().TargetFormat)
+	// The unlocked device of an encrypted volume doesn't read back as zeroes.
+	targetIsZero := !d.Info().ZeroUnpack && !vol.IsEncrypted()
+
+	volSize, err := filler.Fill(vol, devPath, allowUnsafeResize, targetIsZero, d.Info().TargetFormat)
 	if err != nil {
 		return err
 	}
@@ -961,3 +1033,26 @@ func genericRunFiller(d Driver, vol Volume, devPath string, filler *VolumeFiller
 
 	return nil
 }
+
+// genericVFSQcow2DeletionCleanup replaces the child image of a deleted qcow2 snapshot with the snapshot
+// layer it was committed into and then removes the snapshot.
+func genericVFSQcow2DeletionCleanup(d Driver, snapVol Volume, childName string) error {
+	childVol := NewVolume(d, d.Name(), snapVol.volType, snapVol.contentType, childName, snapVol.config, snapVol.poolConfig)
+
+	snapDiskPath, err := d.GetVolumeDiskPath(snapVol)
+	if err != nil {
+		return err
+	}
+
+	childDiskPath, err := d.GetVolumeDiskPath(childVol)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(snapDiskPath, childDiskPath)
+	if err != nil {
+		return fmt.Errorf("Failed moving qcow2 image %q: %w", snapDiskPath, err)
+	}
+
+	return d.DeleteVolumeSnapshot(snapVol, nil)
+}
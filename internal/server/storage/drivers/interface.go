@@ -47,6 +47,12 @@ type Driver interface {
 	// Unmount unmounts a storage pool if needed, returns true if unmounted, false if was not mounted.
 	Unmount() (bool, error)
 	GetResources() (*api.ResourcesStoragePool, error)
+
+	// GetHealth returns the health of the storage pool.
+	GetHealth() (*api.ResourcesStoragePoolHealth, error)
+
+	// Scrub verifies the integrity of the data stored on the pool, returning once done.
+	Scrub(op *operations.Operation) error
 	Validate(config map[string]string) error
 	Update(changedConfig map[string]string) error
 	ApplyPatch(name string) error
@@ -61,6 +67,8 @@ type Driver interface {
 	CreateBucketKey(bucket Volume, keyName string, creds S3Credentials, roleName string, op *operations.Operation) (*S3Credentials, error)
 	UpdateBucketKey(bucket Volume, keyName string, creds S3Credentials, roleName string, op *operations.Operation) (*S3Credentials, error)
 	DeleteBucketKey(bucket Volume, keyName string, op *operations.Operation) error
+	GetBucketLifecycle(bucket Volume) (*api.StorageBucketLifecycle, error)
+	UpdateBucketLifecycle(bucket Volume, lifecycle api.StorageBucketLifecycle) error
 
 	// Volumes.
 	FillVolumeConfig(vol Volume) error
@@ -76,6 +84,9 @@ type Driver interface {
 	GetVolumeDiskPath(vol Volume) (string, error)
 	ListVolumes() ([]Volume, error)
 
+	// RekeyVolume replaces the passphrase unlocking an encrypted volume.
+	RekeyVolume(vol Volume, newKeyID string, op *operations.Operation) error
+
 	// ActivateTask is a low-level access function to get to the underlying storage.
 	ActivateTask(vol Volume, task func(devPath string, op *operations.Operation) error, op *operations.Operation) error
 
@@ -366,6 +366,8 @@ func ensureVolumeBlockFile(vol Volume, path string, sizeBytes int64, allowUnsafe
 		return false, err
 	}
 
+	sizeBytes = luksBackingSizeBytes(vol, sizeBytes)
+
 	if util.PathExists(path) {
 		fi, err := os.Stat(path)
 		if err != nil {
@@ -1087,6 +1089,9 @@ func BackupVolume(d Driver, v Volume, writer instancewriter.InstanceWriter, moun
 			// Exclude the volume root disk file from the filesystem volume backup.
 			// We will read it as a block device later instead.
 			exclude = append(exclude, blockPath)
+		} else if IsQcow2Block(v) && !d.Info().BlockBacking {
+			// The qcow2 image and its snapshot layers are read through the NBD device instead.
+			exclude = append(exclude, filepath.Join(mountPath, genericVolumeDiskFile), filepath.Join(mountPath, Qcow2LayersDir))
 		}
 
 		if v.IsVMBlock() {
@@ -0,0 +1,404 @@
+package drivers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/storage/keystore"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/revert"
+	"github.com/lxc/incus/v7/shared/subprocess"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// luksHeaderSize is the space taken by the LUKS2 header at the start of an encrypted volume.
+const luksHeaderSize = 16 * 1024 * 1024
+
+// luksBackingSizeBytes returns the size of the backing device needed for the volume's data to be sizeBytes,
+// making room for the LUKS header of encrypted volumes.
+func luksBackingSizeBytes(vol Volume, sizeBytes int64) int64 {
+	if sizeBytes > 0 && vol.IsEncrypted() {
+		return sizeBytes + luksHeaderSize
+	}
+
+	return sizeBytes
+}
+
+// luksMapperName returns the device-mapper name used for the unlocked volume.
+// The name is derived from a hash as the volume name may exceed the device-mapper name limit.
+func luksMapperName(vol Volume) string {
+	hash := sha256.Sum256([]byte(vol.pool + "/" + string(vol.volType) + "/" + vol.name))
+
+	return "incus-luks-" + hex.EncodeToString(hash[:])[:32]
+}
+
+// LUKSMappedPath returns the path of the unlocked device for an encrypted volume, or an empty string if the
+// volume isn't currently unlocked.
+func LUKSMappedPath(vol Volume) string {
+	devPath := filepath.Join("/dev/mapper", luksMapperName(vol))
+	if !util.PathExists(devPath) {
+		return ""
+	}
+
+	return devPath
+}
+
+// luksKeyID returns the key identifier of an encrypted volume.
+func luksKeyID(vol Volume) (string, error) {
+	keyID := vol.config["volatile.encryption.key_id"]
+	if keyID == "" {
+		return "", fmt.Errorf("Encrypted volume %q has no key identifier", vol.name)
+	}
+
+	return keyID, nil
+}
+
+// luksRun runs cryptsetup passing the supplied keys through pipes, available to the command as
+// /proc/self/fd/3, /proc/self/fd/4 and so on.
+func luksRun(keys [][]byte, args ...string) error {
+	files := make([]*os.File, 0, len(keys))
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	for _, key := range keys {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, r)
+
+		// Keys are small enough to fit in the pipe buffer.
+		_, err = w.Write(key)
+		_ = w.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := subprocess.RunCommandInheritFds(context.TODO(), files, "cryptsetup", args...)
+
+	return err
+}
+
+// luksIsFormatted returns true if the device has a LUKS header.
+func luksIsFormatted(devPath string) bool {
+	_, err := subprocess.RunCommand("cryptsetup", "isLuks", devPath)
+
+	return err == nil
+}
+
+// luksKey returns the key for an encrypted volume from the key store.
+func (d *common) luksKey(keyID string) ([]byte, error) {
+	store, err := d.state.Keystore()
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading key store: %w", err)
+	}
+
+	key, err := store.Get(context.TODO(), keyID)
+	if err != nil {
+		if errors.Is(err, keystore.ErrNotFound) {
+			return nil, fmt.Errorf("Encryption key %q not found in key store", keyID)
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// CheckKeystore returns an error if the encrypted volumes of a pool using a driver with the given info couldn't be
+// unlocked from every cluster member. The local key store only holds the keys of the member which created them, so
+// remote pools of a cluster require an external key store.
+func CheckKeystore(s *state.State, info Info) error {
+	if !info.Remote || !s.ServerClustered {
+		return nil
+	}
+
+	storeURL, _ := s.GlobalConfig.StorageKeystore()
+	if storeURL == "" {
+		return errors.New("Encrypted volumes on remote storage pools of a cluster require an external key store set with storage.keystore.url")
+	}
+
+	return nil
+}
+
+// luksPutKey generates a new key and stores it in the key store under the given identifier.
+func (d *common) luksPutKey(keyID string) ([]byte, error) {
+	store, err := d.state.Keystore()
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading key store: %w", err)
+	}
+
+	key, err := keystore.NewKey()
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.Put(context.TODO(), keyID, key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed storing encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// luksDeleteKey removes a key from the key store.
+func (d *common) luksDeleteKey(keyID string) {
+	store, err := d.state.Keystore()
+	if err == nil {
+		err = store.Delete(context.TODO(), keyID)
+	}
+
+	if err != nil {
+		d.logger.Warn("Failed deleting encryption key", logger.Ctx{"keyID": keyID, "err": err})
+	}
+}
+
+// luksFormat generates a key for the volume and formats the device with a LUKS2 header.
+// If the volume's key already exists, the volume is being created from a source sharing that key
+// (copy, migration or backup) which provides the LUKS header so no formatting is done.
+func (d *common) luksFormat(vol Volume, devPath string) error {
+	keyID, err := luksKeyID(vol)
+	if err != nil {
+		return err
+	}
+
+	store, err := d.state.Keystore()
+	if err != nil {
+		return fmt.Errorf("Failed loading key store: %w", err)
+	}
+
+	_, err = store.Get(context.TODO(), keyID)
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, keystore.ErrNotFound) {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	key, err := d.luksPutKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { d.luksDeleteKey(keyID) })
+
+	// The key is random so there is no need for an expensive key derivation.
+	err = luksRun([][]byte{key}, "luksFormat", "--batch-mode", "--type", "luks2", "--pbkdf", "pbkdf2", "--pbkdf-force-iterations", "1000", "--key-file", "/proc/self/fd/3", devPath)
+	if err != nil {
+		return fmt.Errorf("Failed formatting encrypted volume: %w", err)
+	}
+
+	d.logger.Debug("Formatted encrypted volume", logger.Ctx{"vol": vol.name, "dev": devPath})
+
+	reverter.Success()
+
+	return nil
+}
+
+// luksOpen unlocks the encrypted volume on devPath and returns the path of the unlocked device.
+func (d *common) luksOpen(vol Volume, devPath string) (string, error) {
+	mappedPath := LUKSMappedPath(vol)
+	if mappedPath != "" {
+		return mappedPath, nil
+	}
+
+	keyID, err := luksKeyID(vol)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := d.luksKey(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"open", "--type", "luks2", "--allow-discards", "--key-file", "/proc/self/fd/3"}
+	if vol.IsSnapshot() {
+		args = append(args, "--readonly")
+	}
+
+	args = append(args, devPath, luksMapperName(vol))
+
+	err = luksRun([][]byte{key}, args...)
+	if err != nil {
+		return "", fmt.Errorf("Failed unlocking encrypted volume: %w", err)
+	}
+
+	d.logger.Debug("Unlocked encrypted volume", logger.Ctx{"vol": vol.name, "dev": devPath})
+
+	return filepath.Join("/dev/mapper", luksMapperName(vol)), nil
+}
+
+// luksMount unlocks the encrypted volume on devPath as part of mounting it, returning whether it was unlocked.
+// Volumes being populated from a source (copy, migration or backup) don't have a LUKS header yet and are
+// left locked.
+func (d *common) luksMount(vol Volume, devPath string) (bool, error) {
+	if !vol.IsEncrypted() || LUKSMappedPath(vol) != "" || !luksIsFormatted(devPath) {
+		return false, nil
+	}
+
+	_, err := d.luksOpen(vol, devPath)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// luksClose locks the encrypted volume if it is currently unlocked.
+func (d *common) luksClose(vol Volume) error {
+	if LUKSMappedPath(vol) == "" {
+		return nil
+	}
+
+	_, err := subprocess.TryRunCommand("cryptsetup", "close", luksMapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed locking encrypted volume: %w", err)
+	}
+
+	d.logger.Debug("Locked encrypted volume", logger.Ctx{"vol": vol.name})
+
+	return nil
+}
+
+// luksResize grows the unlocked device to match a resized backing device.
+func (d *common) luksResize(vol Volume) error {
+	if LUKSMappedPath(vol) == "" {
+		return nil
+	}
+
+	keyID, err := luksKeyID(vol)
+	if err != nil {
+		return err
+	}
+
+	key, err := d.luksKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	// Volumes backed by a file are unlocked through a loop device which must pick up the new file size first.
+	out, err := subprocess.RunCommand("cryptsetup", "status", luksMapperName(vol))
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		field, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found || field != "device" || !strings.HasPrefix(strings.TrimSpace(value), "/dev/loop") {
+			continue
+		}
+
+		err = loopDeviceSetCapacity(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = luksRun([][]byte{key}, "resize", "--key-file", "/proc/self/fd/3", luksMapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed resizing encrypted volume: %w", err)
+	}
+
+	return nil
+}
+
+// luksDiskPath returns the path to use for accessing the volume's data, unlocking it if needed.
+// For unencrypted volumes the supplied raw device path is returned.
+func (d *common) luksDiskPath(vol Volume, devPath string) (string, error) {
+	if !vol.IsEncrypted() {
+		return devPath, nil
+	}
+
+	return d.luksOpen(vol, devPath)
+}
+
+// moveVolumeGPTAltHeader moves the GPT alternative header of the volume's data, unlocking the volume for the
+// duration of the operation if needed.
+func (d *common) moveVolumeGPTAltHeader(vol Volume, devPath string) error {
+	if !vol.IsEncrypted() {
+		return d.moveGPTAltHeader(devPath)
+	}
+
+	mappedPath := LUKSMappedPath(vol)
+	if mappedPath == "" {
+		var err error
+
+		mappedPath, err = d.luksOpen(vol, devPath)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = d.luksClose(vol) }()
+	}
+
+	return d.moveGPTAltHeader(mappedPath)
+}
+
+// luksRotatePassphrase replaces the passphrase of the encrypted volume on devPath with a newly generated one stored
+// under newKeyID.
+//
+// Only the key slot is replaced, the volume key encrypting the data stays the same. Copies of the LUKS header, such as
+// the ones of the snapshots and copies of the volume, can still unlock the data with the previous passphrase.
+func (d *common) luksRotatePassphrase(vol Volume, devPath string, newKeyID string) error {
+	keyID, err := luksKeyID(vol)
+	if err != nil {
+		return err
+	}
+
+	oldKey, err := d.luksKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	newKey, err := d.luksPutKey(newKeyID)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { d.luksDeleteKey(newKeyID) })
+
+	err = luksRun([][]byte{oldKey, newKey}, "luksChangeKey", "--batch-mode", "--pbkdf", "pbkdf2", "--pbkdf-force-iterations", "1000", "--key-file", "/proc/self/fd/3", devPath, "/proc/self/fd/4")
+	if err != nil {
+		return fmt.Errorf("Failed changing encryption passphrase: %w", err)
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// RekeyVolume replaces the passphrase unlocking the encrypted volume with a new one stored under newKeyID.
+func (d *common) RekeyVolume(vol Volume, newKeyID string, op *operations.Operation) error {
+	if !vol.IsEncrypted() {
+		return ErrNotSupported
+	}
+
+	// The LUKS header can be updated while the volume is in use, so just make sure it is active.
+	return vol.MountTask(func(_ string, op *operations.Operation) error {
+		devPath, err := vol.driver.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		return d.luksRotatePassphrase(vol, devPath, newKeyID)
+	}, op)
+}
@@ -7,15 +7,18 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/lxc/incus/v7/internal/linux"
+	"github.com/lxc/incus/v7/internal/rsync"
 	"github.com/lxc/incus/v7/internal/server/operations"
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/subprocess"
+	"github.com/lxc/incus/v7/shared/util"
 )
 
 // Type of the block volume.
@@ -27,6 +30,10 @@ const (
 // Qcow2ConfigVolumeBase represents the base component of a Btrfs subvolume name.
 const Qcow2ConfigVolumeBase = "instance"
 
+// Qcow2LayersDir is the directory of a volume holding the qcow2 layers of its snapshots, for drivers
+// that can't keep them in the snapshots themselves.
+const Qcow2LayersDir = ".qcow2-layers"
+
 // Bitmap represents a dirty bitmap.
 type Bitmap struct {
 	Name string `json:"name"`
@@ -114,6 +121,39 @@ func Qcow2Resize(path string, newSize int64) error {
 	return nil
 }
 
+// Qcow2Convert writes the content of a qcow2 image, including that of its backing chain, to a new
+// standalone qcow2 image.
+func Qcow2Convert(path string, dstPath string) error {
+	_, err := subprocess.RunCommand("nice", "-n19", "qemu-img", "convert", "-f", "qcow2", "-O", "qcow2", path, dstPath)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureQcow2File creates an empty qcow2 image of the given virtual size at filePath if missing, or grows
+// the existing image to that size. Existing images are never shrunk.
+func ensureQcow2File(filePath string, sizeBytes int64) error {
+	// qemu-img only deals with sizes that are a multiple of the sector size.
+	sizeBytes = RoundAbove(512, sizeBytes)
+
+	if !util.PathExists(filePath) {
+		return Qcow2Create(filePath, "", sizeBytes)
+	}
+
+	imgInfo, err := Qcow2Info(filePath)
+	if err != nil {
+		return err
+	}
+
+	if int64(imgInfo.VirtualSize) >= sizeBytes {
+		return nil
+	}
+
+	return Qcow2Resize(filePath, sizeBytes)
+}
+
 // Qcow2Rebase changes the backing file of a qcow2 image.
 func Qcow2Rebase(path string, backingPath string) error {
 	_, err := subprocess.RunCommand("qemu-img", "rebase", "-u", "-b", backingPath, "-F", "qcow2", path)
@@ -179,6 +219,44 @@ func Qcow2BackingChain(path string) ([]string, error) {
 	return result, nil
 }
 
+// qcow2SortSnapshots returns the snapshots of a qcow2 block volume ordered as the layers of its backing
+// chain, from the oldest to the most recent one.
+func qcow2SortSnapshots(vol Volume, snapshots []Volume) ([]Volume, error) {
+	diskPath, err := vol.driver.GetVolumeDiskPath(vol)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := Qcow2BackingChain(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotsByPath := make(map[string]Volume, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapDiskPath, err := vol.driver.GetVolumeDiskPath(snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotsByPath[snapDiskPath] = snapshot
+	}
+
+	sorted := make([]Volume, 0, len(snapshots))
+	for _, backingPath := range slices.Backward(chain) {
+		snapshot, found := snapshotsByPath[backingPath]
+		if found {
+			sorted = append(sorted, snapshot)
+		}
+	}
+
+	if len(sorted) != len(snapshots) {
+		return nil, fmt.Errorf("Snapshots of volume %q don't match its qcow2 backing chain", vol.name)
+	}
+
+	return sorted, nil
+}
+
 // Qcow2MountConfigTask mounts the config filesystem volume with its snapshots and performs the task specified by the parameter.
 func Qcow2MountConfigTask(vol Volume, op *operations.Operation, task func(mountPath string) error) error {
 	mountPath := fmt.Sprintf("%s%s", vol.MountPath(), tmpVolSuffix)
@@ -254,6 +332,11 @@ func Qcow2CreateConfig(vol Volume, op *operations.Operation) error {
 
 // Qcow2CreateConfigSnapshot creates the btrfs snapshot of the config filesystem associated with the QCOW2 block volume.
 func Qcow2CreateConfigSnapshot(vol Volume, snapVol Volume, op *operations.Operation) error {
+	// On filesystem drivers, the config filesystem is snapshotted with the volume.
+	if !vol.driver.Info().BlockBacking {
+		return nil
+	}
+
 	err := Qcow2MountConfigTask(vol, op, func(mountPath string) error {
 		_, snapName, _ := api.GetParentAndSnapshotName(snapVol.Name())
 		dstPath := filepath.Join(mountPath, fmt.Sprintf("%s-%s", Qcow2ConfigVolumeBase, snapName))
@@ -280,6 +363,17 @@ func Qcow2CreateConfigSnapshot(vol Volume, snapVol Volume, op *operations.Operat
 
 // Qcow2RestoreConfigSnapshot restores the btrfs snapshot of the config filesystem associated with the QCOW2 block volume.
 func Qcow2RestoreConfigSnapshot(vol Volume, snapVol Volume, op *operations.Operation) error {
+	// On filesystem drivers, the config filesystem is copied back from the snapshot, leaving the disk alone.
+	if !vol.driver.Info().BlockBacking {
+		bwlimit := vol.driver.Config()["rsync.bwlimit"]
+		_, err := rsync.LocalCopy(snapVol.MountPath(), vol.MountPath(), bwlimit, true, "--exclude", genericVolumeDiskFile, "--exclude", Qcow2LayersDir)
+		if err != nil {
+			return fmt.Errorf("Failed to rsync volume: %w", err)
+		}
+
+		return nil
+	}
+
 	err := Qcow2MountConfigTask(vol, op, func(mountPath string) error {
 		_, snapName, _ := api.GetParentAndSnapshotName(snapVol.Name())
 		snapPath := fmt.Sprintf("%s-%s", Qcow2ConfigVolumeBase, snapName)
@@ -311,6 +405,11 @@ func Qcow2RestoreConfigSnapshot(vol Volume, snapVol Volume, op *operations.Opera
 
 // Qcow2RenameConfigSnapshot renames the btrfs snapshot of the config filesystem associated with the QCOW2 block volume.
 func Qcow2RenameConfigSnapshot(vol Volume, snapVol Volume, newName string, op *operations.Operation) error {
+	// On filesystem drivers, the config filesystem is renamed with the snapshot.
+	if !vol.driver.Info().BlockBacking {
+		return nil
+	}
+
 	err := Qcow2MountConfigTask(vol, op, func(mountPath string) error {
 		_, snapName, _ := api.GetParentAndSnapshotName(snapVol.Name())
 		oldPath := filepath.Join(mountPath, fmt.Sprintf("%s-%s", Qcow2ConfigVolumeBase, snapName))
@@ -337,6 +436,11 @@ func Qcow2RenameConfigSnapshot(vol Volume, snapVol Volume, newName string, op *o
 
 // Qcow2DeleteConfigSnapshot deletes the btrfs snapshot of the config filesystem associated with the QCOW2 block volume.
 func Qcow2DeleteConfigSnapshot(vol Volume, snapVol Volume, op *operations.Operation) error {
+	// On filesystem drivers, the config filesystem is deleted with the snapshot by Qcow2DeletionCleanup.
+	if !vol.driver.Info().BlockBacking {
+		return nil
+	}
+
 	err := Qcow2MountConfigTask(vol, op, func(mountPath string) error {
 		_, snapName, _ := api.GetParentAndSnapshotName(snapVol.Name())
 		path := filepath.Join(mountPath, fmt.Sprintf("%s-%s", Qcow2ConfigVolumeBase, snapName))
@@ -389,6 +493,17 @@ func IsQcow2Block(vol Volume) bool {
 	return vol.Config()["block.type"] == BlockVolumeTypeQcow2 && vol.ContentType() == ContentTypeBlock
 }
 
+// isQcow2Volume returns whether the volume is a qcow2 block volume or the filesystem volume of a virtual
+// machine whose disk is a qcow2 image.
+func isQcow2Volume(vol Volume) bool {
+	if vol.volType != VolumeTypeVM && !vol.IsCustomBlock() {
+		return false
+	}
+
+	// The format of the disk files follows the pool's.
+	return vol.ExpandedConfig("block.type") == BlockVolumeTypeQcow2
+}
+
 // getFreeNbd returns the first free NBD device.
 func getFreeNbd() (string, error) {
 	nbdIndex := 0
@@ -0,0 +1,97 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the format of block volumes kept as disk files follows the pool's.
+func Test_common_fillVolumeBlockType(t *testing.T) {
+	tests := []struct {
+		name        string
+		poolConfig  map[string]string
+		volType     VolumeType
+		contentType ContentType
+		config      map[string]string
+		blockType   string
+	}{
+		{
+			name:        "VM on qcow2 pool",
+			poolConfig:  map[string]string{"volume.block.type": BlockVolumeTypeQcow2},
+			volType:     VolumeTypeVM,
+			contentType: ContentTypeBlock,
+			config:      map[string]string{},
+			blockType:   BlockVolumeTypeQcow2,
+		},
+		{
+			name:        "Custom block volume overriding qcow2 pool",
+			poolConfig:  map[string]string{"volume.block.type": BlockVolumeTypeQcow2},
+			volType:     VolumeTypeCustom,
+			contentType: ContentTypeBlock,
+			config:      map[string]string{"block.type": BlockVolumeTypeRaw},
+			blockType:   BlockVolumeTypeQcow2,
+		},
+		{
+			name:        "VM on raw pool",
+			poolConfig:  map[string]string{},
+			volType:     VolumeTypeVM,
+			contentType: ContentTypeBlock,
+			config:      map[string]string{"block.type": BlockVolumeTypeQcow2},
+			blockType:   "",
+		},
+		{
+			name:        "Custom filesystem volume on qcow2 pool",
+			poolConfig:  map[string]string{"volume.block.type": BlockVolumeTypeQcow2},
+			volType:     VolumeTypeCustom,
+			contentType: ContentTypeFS,
+			config:      map[string]string{},
+			blockType:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := &common{config: test.poolConfig}
+			vol := Volume{volType: test.volType, contentType: test.contentType, config: test.config, poolConfig: test.poolConfig}
+
+			d.fillVolumeBlockType(&vol)
+			assert.Equal(t, test.blockType, vol.config["block.type"])
+			assert.NoError(t, d.validateVolumeBlockType(vol))
+		})
+	}
+}
+
+// Test that a block type different from the pool's is rejected.
+func Test_common_validateVolumeBlockType(t *testing.T) {
+	d := &common{config: map[string]string{}}
+
+	vol := Volume{volType: VolumeTypeVM, contentType: ContentTypeBlock, config: map[string]string{"block.type": BlockVolumeTypeRaw}}
+	assert.NoError(t, d.validateVolumeBlockType(vol))
+
+	vol.config["block.type"] = BlockVolumeTypeQcow2
+	assert.Error(t, d.validateVolumeBlockType(vol))
+
+	d.config["volume.block.type"] = BlockVolumeTypeQcow2
+	assert.NoError(t, d.validateVolumeBlockType(vol))
+}
+
+// Test that the qcow2 layers of btrfs snapshots are kept in the parent volume.
+func Test_btrfs_qcow2LayerPath(t *testing.T) {
+	d := &btrfs{common{name: "pool", config: map[string]string{"volume.block.type": BlockVolumeTypeQcow2}}}
+
+	snapVol := NewVolume(d, "pool", VolumeTypeVM, ContentTypeBlock, "vm/snap0", nil, d.config)
+	assert.Equal(t, GetVolumeMountPath("pool", VolumeTypeVM, "vm")+"/.qcow2-layers/snap0.img", d.qcow2LayerPath(snapVol))
+
+	// Not a snapshot.
+	vol := NewVolume(d, "pool", VolumeTypeVM, ContentTypeBlock, "vm", nil, d.config)
+	assert.Empty(t, d.qcow2LayerPath(vol))
+
+	// Filesystem volume of the snapshot.
+	assert.Empty(t, d.qcow2LayerPath(snapVol.NewVMBlockFilesystemVolume()))
+
+	// Raw pool.
+	d.config = map[string]string{}
+	snapVol = NewVolume(d, "pool", VolumeTypeVM, ContentTypeBlock, "vm/snap0", nil, d.config)
+	assert.Empty(t, d.qcow2LayerPath(snapVol))
+}
@@ -418,13 +418,18 @@ func (v Volume) MountWithSnapshotsTask(task func(parentMountPath string, snapsho
 			return err
 		}
 
-		// Check whether the backing path and the LVM volume resolve to the same /dev/dm-X device.
+		// Check whether the backing path and the snapshot disk resolve to the same file (the same /dev/dm-X device on LVM).
 		target, err := filepath.EvalSymlinks(backingPath)
 		if err != nil {
 			return err
 		}
 
-		if target != snapDiskPath {
+		snapDiskTarget, err := filepath.EvalSymlinks(snapDiskPath)
+		if err != nil {
+			return err
+		}
+
+		if target != snapDiskTarget {
 			return fmt.Errorf("/dev symlinks are in an inconsistent state")
 		}
 
@@ -546,6 +551,15 @@ func (v Volume) IsCustomBlock() bool {
 	return (v.volType == VolumeTypeCustom && v.contentType == ContentTypeBlock)
 }
 
+// IsEncrypted returns true if the volume's block data is encrypted with LUKS.
+func (v Volume) IsEncrypted() bool {
+	if v.contentType != ContentTypeBlock || (v.volType != VolumeTypeVM && v.volType != VolumeTypeCustom) {
+		return false
+	}
+
+	return v.driver.Info().Encryption && util.IsTrue(v.config["security.encrypted"])
+}
+
 // NewVMBlockFilesystemVolume returns a copy of the volume with the content type set to ContentTypeFS and the
 // config "size" property set to "size.state" or DefaultVMBlockFilesystemSize if not set.
 func (v Volume) NewVMBlockFilesystemVolume() Volume {
@@ -0,0 +1,138 @@
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	localtls "github.com/lxc/incus/v7/shared/tls"
+)
+
+// httpStore is a key store backed by a remote HTTPS service.
+// The service is expected to follow a Vault KV-like layout, with each key stored at {url}/{id}.
+type httpStore struct {
+	url    string
+	client *http.Client
+}
+
+// httpKey is the JSON representation of a key on the remote service.
+type httpKey struct {
+	Key string `json:"key"`
+}
+
+// NewHTTP returns a key store using the remote HTTPS service at the given URL.
+// Requests are authenticated with the supplied client certificate and the server is validated
+// against caCert when set, or the system CAs otherwise.
+func NewHTTP(storeURL string, caCert string, cert *localtls.CertInfo) (Store, error) {
+	_, err := url.Parse(storeURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid key store URL: %w", err)
+	}
+
+	tlsConfig, err := localtls.GetTLSConfigMem(string(cert.PublicKey()), string(cert.PrivateKey()), caCert, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed configuring key store TLS: %w", err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+		Timeout: 30 * time.Second,
+	}
+
+	return &httpStore{url: strings.TrimSuffix(storeURL, "/"), client: client}, nil
+}
+
+// do sends a request for the given key and returns the response body.
+func (s *httpStore) do(ctx context.Context, method string, id string, body []byte) ([]byte, error) {
+	err := validateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.url+"/"+id, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Failed contacting key store: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading key store response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Key store returned %q for key %q", resp.Status, id)
+	}
+
+	return data, nil
+}
+
+// Get returns the key with the given identifier.
+func (s *httpStore) Get(ctx context.Context, id string) ([]byte, error) {
+	data, err := s.do(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Data httpKey `json:"data"`
+	}{}
+
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing key store response: %w", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Data.Key)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("Invalid key %q returned by key store", id)
+	}
+
+	return key, nil
+}
+
+// Put stores the key under the given identifier, replacing any existing key.
+func (s *httpStore) Put(ctx context.Context, id string, key []byte) error {
+	body, err := json.Marshal(httpKey{Key: base64.StdEncoding.EncodeToString(key)})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.do(ctx, http.MethodPost, id, body)
+	return err
+}
+
+// Delete removes the key with the given identifier.
+func (s *httpStore) Delete(ctx context.Context, id string) error {
+	_, err := s.do(ctx, http.MethodDelete, id, nil)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
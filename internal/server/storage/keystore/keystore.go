@@ -0,0 +1,49 @@
+// Package keystore stores the keys used to unlock encrypted storage volumes.
+package keystore
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrNotFound is returned when the requested key doesn't exist in the store.
+var ErrNotFound = errors.New("Key not found")
+
+// keyIDPattern restricts key identifiers to characters safe for use in file names and URLs.
+var keyIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Store represents a volume key store.
+type Store interface {
+	// Get returns the key with the given identifier.
+	Get(ctx context.Context, id string) ([]byte, error)
+
+	// Put stores the key under the given identifier, replacing any existing key.
+	Put(ctx context.Context, id string, key []byte) error
+
+	// Delete removes the key with the given identifier.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewKey generates a new random volume key.
+func NewKey() ([]byte, error) {
+	key := make([]byte, 64)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating key: %w", err)
+	}
+
+	return key, nil
+}
+
+// validateID checks that the key identifier is valid.
+func validateID(id string) error {
+	if !keyIDPattern.MatchString(id) {
+		return fmt.Errorf("Invalid key identifier %q", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,306 @@
+package keystore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// credentialName is the name of the systemd credential the master key is derived from when it's provided.
+const credentialName = "incus-keystore"
+
+// local is a key store keeping keys on the local filesystem, wrapped with a master key.
+//
+// The master key is derived from the "incus-keystore" systemd credential when the service is given one, which can
+// be bound to the TPM of the system or supplied by the operator. Otherwise, it's kept in clear in the master.key
+// file next to the wrapped keys, which only protects them from being read directly from the store.
+type local struct {
+	path           string
+	credentialsDir string
+	mu             sync.Mutex
+}
+
+// NewLocal returns a key store keeping its keys in the given directory.
+func NewLocal(path string) Store {
+	return &local{path: path, credentialsDir: os.Getenv("CREDENTIALS_DIRECTORY")}
+}
+
+// newAEAD returns the cipher wrapping keys with the given master key.
+func newAEAD(master []byte) (cipher.AEAD, error) {
+	if len(master) != 32 {
+		return nil, errors.New("Invalid master key length")
+	}
+
+	block, err := aes.NewCipher(master)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// credentialKey returns the master key derived from the systemd credential, or nil if there's none.
+func (s *local) credentialKey() ([]byte, error) {
+	if s.credentialsDir == "" {
+		return nil, nil
+	}
+
+	secret, err := os.ReadFile(filepath.Join(s.credentialsDir, credentialName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed reading %q credential: %w", credentialName, err)
+	}
+
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("Empty %q credential", credentialName)
+	}
+
+	return hkdf.Key(sha256.New, secret, nil, "incus keystore master key", 32)
+}
+
+// fileKey returns the master key kept in the master.key file, creating it on first use if requested.
+func (s *local) fileKey(create bool) ([]byte, error) {
+	masterPath := filepath.Join(s.path, "master.key")
+
+	master, err := os.ReadFile(masterPath)
+	if errors.Is(err, fs.ErrNotExist) && create {
+		master = make([]byte, 32)
+
+		_, err = rand.Read(master)
+		if err != nil {
+			return nil, fmt.Errorf("Failed generating master key: %w", err)
+		}
+
+		err = writeFileAtomic(masterPath, master)
+		if err != nil {
+			return nil, fmt.Errorf("Failed writing master key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed reading master key: %w", err)
+	}
+
+	return master, nil
+}
+
+// migrate rewraps the keys wrapped with the master key file with the given cipher and removes the file.
+func (s *local) migrate(aead cipher.AEAD) error {
+	master, err := s.fileKey(false)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	old, err := newAEAD(master)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return fmt.Errorf("Failed listing keys: %w", err)
+	}
+
+	for _, entry := range entries {
+		id := entry.Name()
+		if !entry.Type().IsRegular() || validateID(id) != nil {
+			continue
+		}
+
+		// Skip the keys already wrapped with the new cipher.
+		_, err := unwrap(aead, filepath.Join(s.path, id), id)
+		if err == nil {
+			continue
+		}
+
+		key, err := unwrap(old, filepath.Join(s.path, id), id)
+		if err != nil {
+			return err
+		}
+
+		err = wrap(aead, filepath.Join(s.path, id), id, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Remove(filepath.Join(s.path, "master.key"))
+	if err != nil {
+		return fmt.Errorf("Failed removing master key: %w", err)
+	}
+
+	return nil
+}
+
+// aead returns the cipher used to wrap keys.
+//
+// When the systemd credential is provided, the keys wrapped with the master key file are rewrapped and the file is
+// removed. Otherwise the master key file is used, creating it if requested.
+func (s *local) aead(create bool) (cipher.AEAD, error) {
+	err := os.MkdirAll(s.path, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating key store directory: %w", err)
+	}
+
+	master, err := s.credentialKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if master == nil {
+		master, err = s.fileKey(create)
+		if err != nil {
+			return nil, err
+		}
+
+		return newAEAD(master)
+	}
+
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.migrate(aead)
+	if err != nil {
+		return nil, fmt.Errorf("Failed rewrapping keys with the %q credential: %w", credentialName, err)
+	}
+
+	return aead, nil
+}
+
+// wrap writes the key wrapped with the cipher and bound to its identifier to the given path.
+func wrap(aead cipher.AEAD, path string, id string, key []byte) error {
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return fmt.Errorf("Failed generating nonce: %w", err)
+	}
+
+	err = writeFileAtomic(path, aead.Seal(nonce, nonce, key, []byte(id)))
+	if err != nil {
+		return fmt.Errorf("Failed writing key %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// unwrap reads the key with the given identifier from the path and unwraps it with the cipher.
+func unwrap(aead cipher.AEAD, path string, id string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading key %q: %w", id, err)
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("Invalid key file %q", id)
+	}
+
+	key, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("Failed unwrapping key %q: %w", id, err)
+	}
+
+	return key, nil
+}
+
+// Get returns the key with the given identifier.
+func (s *local) Get(ctx context.Context, id string) ([]byte, error) {
+	err := validateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keyPath := filepath.Join(s.path, id)
+	if !util.PathExists(keyPath) {
+		return nil, ErrNotFound
+	}
+
+	aead, err := s.aead(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return unwrap(aead, keyPath, id)
+}
+
+// Put stores the key under the given identifier, replacing any existing key.
+func (s *local) Put(ctx context.Context, id string, key []byte) error {
+	err := validateID(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	aead, err := s.aead(true)
+	if err != nil {
+		return err
+	}
+
+	return wrap(aead, filepath.Join(s.path, id), id, key)
+}
+
+// Delete removes the key with the given identifier.
+func (s *local) Delete(ctx context.Context, id string) error {
+	err := validateID(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = os.Remove(filepath.Join(s.path, id))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed deleting key %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes the file through a temporary file so that readers never see a partial key.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	_, err = f.Write(data)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,138 @@
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocal(dir)
+
+	key, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.Get(context.Background(), "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	err = store.Put(context.Background(), "vol1", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The key must not be stored in clear.
+	data, err := os.ReadFile(filepath.Join(dir, "vol1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(data, key) {
+		t.Fatal("Key stored unwrapped")
+	}
+
+	// A fresh store on the same directory reuses the master key.
+	got, err := NewLocal(dir).Get(context.Background(), "vol1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, key) {
+		t.Fatal("Key mismatch")
+	}
+
+	// Wrapped keys are bound to their identifier.
+	err = os.Rename(filepath.Join(dir, "vol1"), filepath.Join(dir, "vol2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.Get(context.Background(), "vol2")
+	if err == nil {
+		t.Fatal("Expected failure unwrapping a renamed key")
+	}
+
+	err = store.Delete(context.Background(), "vol2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Delete(context.Background(), "vol2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", "../vol", "master.key", ".hidden"} {
+		err = store.Put(context.Background(), id, key)
+		if err == nil {
+			t.Fatalf("Expected invalid identifier %q to be rejected", id)
+		}
+	}
+}
+
+func TestLocalCredential(t *testing.T) {
+	dir := t.TempDir()
+	credentialsDir := t.TempDir()
+
+	key, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys stored before the credential is provided are wrapped with the master key file.
+	err = NewLocal(dir).Put(context.Background(), "vol1", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(credentialsDir, credentialName), []byte("secret"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// They're rewrapped with the credential and the master key file is removed.
+	store := &local{path: dir, credentialsDir: credentialsDir}
+
+	got, err := store.Get(context.Background(), "vol1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, key) {
+		t.Fatal("Key mismatch")
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "master.key"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected the master key file to be removed, got %v", err)
+	}
+
+	err = store.Put(context.Background(), "vol2", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both keys can only be unwrapped with the credential.
+	for _, id := range []string{"vol1", "vol2"} {
+		_, err = NewLocal(dir).Get(context.Background(), id)
+		if err == nil {
+			t.Fatalf("Expected failure unwrapping %q without the credential", id)
+		}
+
+		got, err = (&local{path: dir, credentialsDir: credentialsDir}).Get(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, key) {
+			t.Fatalf("Key mismatch for %q", id)
+		}
+	}
+}
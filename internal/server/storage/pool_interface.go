@@ -50,6 +50,8 @@ type Pool interface {
 	ToAPI() api.StoragePool
 
 	GetResources() (*api.ResourcesStoragePool, error)
+	GetHealth() (*api.ResourcesStoragePoolHealth, error)
+	Scrub(op *operations.Operation) error
 	IsUsed() (bool, error)
 	Delete(clientType request.ClientType, op *operations.Operation) error
 	Update(clientType request.ClientType, newDesc string, newConfig map[string]string, op *operations.Operation) error
@@ -96,7 +98,7 @@ type Pool interface {
 	UpdateInstanceSnapshot(inst instance.Instance, newDesc string, newConfig map[string]string, op *operations.Operation) error
 
 	// Instance backups.
-	BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, dependentVolumes bool, op *operations.Operation) error
+	BackupInstance(inst instance.Instance, tarWriter instancewriter.InstanceWriter, optimized bool, snapshots bool, dependentVolumes bool, op *operations.Operation) error
 	CreateInstanceFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (func(instance.Instance) error, revert.Hook, error)
 	GetInstanceNBD(inst instance.Instance, writable bool) (net.Conn, func(), error)
 	GetInstanceAllDisksNBD(inst instance.Instance, reuse bool) (net.Conn, func(), error)
@@ -115,6 +117,8 @@ type Pool interface {
 	UpdateBucketKey(projectName string, bucketName string, keyName string, key api.StorageBucketKeyPut, op *operations.Operation) error
 	DeleteBucketKey(projectName string, bucketName string, keyName string, op *operations.Operation) error
 	MountLocalBucket(projectName string, bucketName string, op *operations.Operation) (string, func() error, error)
+	GetBucketLifecycle(projectName string, bucketName string) (*api.StorageBucketLifecycle, error)
+	UpdateBucketLifecycle(projectName string, bucketName string, lifecycle api.StorageBucketLifecycle, op *operations.Operation) error
 	GetBucketURL(bucketName string) *url.URL
 	GenerateBucketBackupConfig(projectName string, bucketName string, op *operations.Operation) (*backupConfig.Config, error)
 	BackupBucket(projectName string, bucketName string, tarWriter *instancewriter.InstanceTarWriter, op *operations.Operation) error
@@ -127,6 +131,7 @@ type Pool interface {
 	RenameCustomVolume(projectName string, volName string, newVolName string, op *operations.Operation) error
 	DeleteCustomVolume(projectName string, volName string, op *operations.Operation) error
 	RebuildCustomVolume(projectName string, volName string, op *operations.Operation) error
+	RekeyVolume(projectName string, volName string, volType drivers.VolumeType, op *operations.Operation) error
 	GetCustomVolumeDisk(projectName string, volName string) (string, error)
 	GetCustomVolumeUsage(projectName string, volName string) (*VolumeUsage, error)
 	MountCustomVolume(projectName string, volName string, op *operations.Operation) (*MountInfo, error)
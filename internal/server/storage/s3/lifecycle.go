@@ -0,0 +1,173 @@
+package s3
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// LifecycleConfiguration is the XML body of the bucket-level ?lifecycle sub-resource.
+type LifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Xmlns   string          `xml:"xmlns,attr,omitempty"`
+	Rules   []LifecycleRule `xml:"Rule"`
+}
+
+// LifecycleRule is a rule of a LifecycleConfiguration.
+type LifecycleRule struct {
+	ID     string           `xml:"ID"`
+	Prefix *string          `xml:"Prefix"`
+	Filter *LifecycleFilter `xml:"Filter"`
+	Status string           `xml:"Status"`
+
+	Expiration                     *LifecycleExpiration                     `xml:"Expiration"`
+	NoncurrentVersionExpiration    *LifecycleNoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration"`
+	AbortIncompleteMultipartUpload *LifecycleAbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload"`
+	Transitions                    []struct{}                               `xml:"Transition"`
+}
+
+// LifecycleExpiration is the expiration action of a LifecycleRule.
+type LifecycleExpiration struct {
+	Days int    `xml:"Days,omitempty"`
+	Date string `xml:"Date,omitempty"`
+}
+
+// LifecycleNoncurrentVersionExpiration is the noncurrent version expiration action of a LifecycleRule.
+type LifecycleNoncurrentVersionExpiration struct {
+	NoncurrentDays int `xml:"NoncurrentDays"`
+}
+
+// LifecycleAbortIncompleteMultipartUpload is the multipart upload abort action of a LifecycleRule.
+type LifecycleAbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+// LifecycleFilter selects the objects a LifecycleRule applies to.
+type LifecycleFilter struct {
+	Prefix *string   `xml:"Prefix"`
+	Tag    *struct{} `xml:"Tag"`
+	And    *struct{} `xml:"And"`
+}
+
+// ToAPI converts the lifecycle configuration to its API representation.
+// Rule elements which can't be represented, like tag filters and transitions, are rejected.
+func (c *LifecycleConfiguration) ToAPI() (*api.StorageBucketLifecycle, error) {
+	lifecycle := &api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{}}
+
+	for _, r := range c.Rules {
+		rule := api.StorageBucketLifecycleRule{
+			ID:      r.ID,
+			Enabled: r.Status == "Enabled",
+		}
+
+		if r.Status != "Enabled" && r.Status != "Disabled" {
+			return nil, fmt.Errorf("Invalid status %q for lifecycle rule %q", r.Status, r.ID)
+		}
+
+		if r.Prefix != nil {
+			rule.Prefix = *r.Prefix
+		}
+
+		if r.Filter != nil {
+			if r.Filter.Tag != nil || r.Filter.And != nil {
+				return nil, fmt.Errorf("Lifecycle rule %q filters on tags, only prefix filters are supported", r.ID)
+			}
+
+			if r.Filter.Prefix != nil {
+				rule.Prefix = *r.Filter.Prefix
+			}
+		}
+
+		if len(r.Transitions) > 0 {
+			return nil, fmt.Errorf("Lifecycle rule %q has transitions, which aren't supported", r.ID)
+		}
+
+		if r.Expiration != nil {
+			if r.Expiration.Date != "" {
+				return nil, fmt.Errorf("Lifecycle rule %q expires objects at a date, only a number of days is supported", r.ID)
+			}
+
+			rule.ExpirationDays = r.Expiration.Days
+		}
+
+		if r.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentVersionExpirationDays = r.NoncurrentVersionExpiration.NoncurrentDays
+		}
+
+		if r.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteMultipartUploadDays = r.AbortIncompleteMultipartUpload.DaysAfterInitiation
+		}
+
+		lifecycle.Rules = append(lifecycle.Rules, rule)
+	}
+
+	return lifecycle, nil
+}
+
+// LifecycleConfigurationFromAPI converts the API representation of a lifecycle configuration.
+func LifecycleConfigurationFromAPI(lifecycle api.StorageBucketLifecycle) *LifecycleConfiguration {
+	c := &LifecycleConfiguration{Xmlns: "http://s3.amazonaws.com/doc/2006-03-01/"}
+
+	for _, rule := range lifecycle.Rules {
+		prefix := rule.Prefix
+
+		r := LifecycleRule{
+			ID:     rule.ID,
+			Filter: &LifecycleFilter{Prefix: &prefix},
+			Status: "Disabled",
+		}
+
+		if rule.Enabled {
+			r.Status = "Enabled"
+		}
+
+		if rule.ExpirationDays > 0 {
+			r.Expiration = &LifecycleExpiration{Days: rule.ExpirationDays}
+		}
+
+		if rule.NoncurrentVersionExpirationDays > 0 {
+			r.NoncurrentVersionExpiration = &LifecycleNoncurrentVersionExpiration{NoncurrentDays: rule.NoncurrentVersionExpirationDays}
+		}
+
+		if rule.AbortIncompleteMultipartUploadDays > 0 {
+			r.AbortIncompleteMultipartUpload = &LifecycleAbortIncompleteMultipartUpload{DaysAfterInitiation: rule.AbortIncompleteMultipartUploadDays}
+		}
+
+		c.Rules = append(c.Rules, r)
+	}
+
+	return c
+}
+
+// ValidateLifecycle checks the rules of a lifecycle configuration.
+func ValidateLifecycle(lifecycle api.StorageBucketLifecycle) error {
+	seen := map[string]bool{}
+
+	for _, rule := range lifecycle.Rules {
+		if rule.ID == "" {
+			return errors.New("Lifecycle rule ID is required")
+		}
+
+		if len(rule.ID) > 255 {
+			return fmt.Errorf("Lifecycle rule ID %q is longer than 255 characters", rule.ID)
+		}
+
+		if seen[rule.ID] {
+			return fmt.Errorf("Duplicate lifecycle rule ID %q", rule.ID)
+		}
+
+		seen[rule.ID] = true
+
+		if rule.ExpirationDays < 0 || rule.NoncurrentVersionExpirationDays < 0 || rule.AbortIncompleteMultipartUploadDays < 0 {
+			return fmt.Errorf("Lifecycle rule %q has a negative number of days", rule.ID)
+		}
+
+		if rule.ExpirationDays == 0 && rule.NoncurrentVersionExpirationDays == 0 && rule.AbortIncompleteMultipartUploadDays == 0 {
+			return fmt.Errorf("Lifecycle rule %q has no action", rule.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,300 @@
+package local
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/storage/s3"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// lifecycleFile holds the lifecycle configuration of the bucket, next to the data directory.
+const lifecycleFile = "lifecycle.json"
+
+// LoadLifecycle returns the lifecycle configuration of the bucket at bucketDir.
+// A configuration without rules is returned if none is set.
+func LoadLifecycle(bucketDir string) (*api.StorageBucketLifecycle, error) {
+	lifecycle := &api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{}}
+
+	b, err := os.ReadFile(filepath.Join(bucketDir, lifecycleFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return lifecycle, nil
+		}
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, lifecycle)
+	if err != nil {
+		return nil, err
+	}
+
+	return lifecycle, nil
+}
+
+// SaveLifecycle replaces the lifecycle configuration of the bucket at bucketDir.
+// Saving a configuration without rules removes it.
+func SaveLifecycle(bucketDir string, lifecycle api.StorageBucketLifecycle) error {
+	path := filepath.Join(bucketDir, lifecycleFile)
+
+	if len(lifecycle.Rules) == 0 {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	b, err := json.Marshal(lifecycle)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, b, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// getBucketLifecycle implements GetBucketLifecycleConfiguration.
+func (s *Server) getBucketLifecycle(w http.ResponseWriter) {
+	lifecycle, err := LoadLifecycle(s.bucketDir)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	if len(lifecycle.Rules) == 0 {
+		(&s3.Error{Code: s3.ErrorCodeNoSuchLifecycleConfiguration, Message: "The lifecycle configuration does not exist."}).Response(w)
+		return
+	}
+
+	body, err := xml.Marshal(s3.LifecycleConfigurationFromAPI(*lifecycle))
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>`))
+	_, _ = w.Write(body)
+}
+
+// putBucketLifecycle implements PutBucketLifecycleConfiguration.
+func (s *Server) putBucketLifecycle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	config := &s3.LifecycleConfiguration{}
+	err = xml.Unmarshal(body, config)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: err.Error()}).Response(w)
+		return
+	}
+
+	lifecycle, err := config.ToAPI()
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeNotImplemented, Message: err.Error()}).Response(w)
+		return
+	}
+
+	err = s3.ValidateLifecycle(*lifecycle)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: err.Error()}).Response(w)
+		return
+	}
+
+	err = SaveLifecycle(s.bucketDir, *lifecycle)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// deleteBucketLifecycle implements DeleteBucketLifecycle.
+func (s *Server) deleteBucketLifecycle(w http.ResponseWriter) {
+	err := SaveLifecycle(s.bucketDir, api.StorageBucketLifecycle{})
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApplyLifecycle applies the enabled lifecycle rules of the bucket: current
+// versions of objects past their expiration are deleted, noncurrent versions
+// past their expiration are removed, as are stale incomplete multipart uploads.
+func (s *Server) ApplyLifecycle(now time.Time) error {
+	lifecycle, err := LoadLifecycle(s.bucketDir)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range lifecycle.Rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		if rule.ExpirationDays > 0 || rule.NoncurrentVersionExpirationDays > 0 {
+			err = s.expireObjects(rule, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if rule.AbortIncompleteMultipartUploadDays > 0 {
+			err = s.abortStaleUploads(rule, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expireObjects applies the expiration actions of a lifecycle rule.
+func (s *Server) expireObjects(rule api.StorageBucketLifecycleRule, now time.Time) error {
+	keys, err := s.collectKeys(true)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, rule.Prefix) {
+			continue
+		}
+
+		err = s.expireObject(rule, key, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expireObject applies the expiration actions of a lifecycle rule to a single object.
+func (s *Server) expireObject(rule api.StorageBucketLifecycleRule, key string, now time.Time) error {
+	dataPath := filepath.Join(s.dataDir(), key)
+
+	objectWriteMu.Lock()
+	defer objectWriteMu.Unlock()
+
+	record, err := loadRecord(dataPath)
+	if err != nil || record == nil {
+		return err
+	}
+
+	if rule.ExpirationDays > 0 && !record.DeleteMarker && record.LastMod.Before(now.AddDate(0, 0, -rule.ExpirationDays)) {
+		logger.Debug("Expiring bucket object", logger.Ctx{"bucketDir": s.bucketDir, "key": key, "rule": rule.ID})
+
+		_, err = s.removeCurrent(key, dataPath, record)
+		if err != nil {
+			return err
+		}
+
+		record, err = loadRecord(dataPath)
+		if err != nil || record == nil {
+			return err
+		}
+	}
+
+	if rule.NoncurrentVersionExpirationDays > 0 {
+		cutoff := now.AddDate(0, 0, -rule.NoncurrentVersionExpirationDays)
+
+		// A version became noncurrent when the next more recent one was written.
+		superseded := record.LastMod
+		expired := []string{}
+		for _, version := range record.Versions {
+			if superseded.Before(cutoff) {
+				expired = append(expired, versionIDOrNull(version.VersionID))
+			}
+
+			superseded = version.LastMod
+		}
+
+		for _, versionID := range expired {
+			logger.Debug("Expiring noncurrent bucket object version", logger.Ctx{"bucketDir": s.bucketDir, "key": key, "versionID": versionID, "rule": rule.ID})
+
+			_, err = s.removeVersion(key, dataPath, record, versionID)
+			if err != nil {
+				return err
+			}
+
+			record, err = loadRecord(dataPath)
+			if err != nil || record == nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// abortStaleUploads applies the multipart upload abort action of a lifecycle rule.
+func (s *Server) abortStaleUploads(rule api.StorageBucketLifecycleRule, now time.Time) error {
+	entries, err := os.ReadDir(s.uploadsDir())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	cutoff := now.AddDate(0, 0, -rule.AbortIncompleteMultipartUploadDays)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		uploadDir := filepath.Join(s.uploadsDir(), entry.Name())
+
+		b, err := os.ReadFile(filepath.Join(uploadDir, "upload.json"))
+		if err != nil {
+			continue
+		}
+
+		info := &uploadInfo{}
+		err = json.Unmarshal(b, info)
+		if err != nil {
+			continue
+		}
+
+		if !strings.HasPrefix(info.Key, rule.Prefix) || !info.Initiated.Before(cutoff) {
+			continue
+		}
+
+		logger.Debug("Aborting stale multipart upload", logger.Ctx{"bucketDir": s.bucketDir, "key": info.Key, "uploadID": entry.Name(), "rule": rule.ID})
+
+		err = os.RemoveAll(uploadDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package local
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Test that noncurrent versions expire based on when they were superseded rather than when they were written.
+func TestServer_expireNoncurrentVersions(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+	s.Versioning = VersioningEnabled
+
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, n-30) }
+
+	putVersionAt(t, s, "obj", "a", day(0))
+	putVersionAt(t, s, "obj", "b", day(10))
+	third := putVersionAt(t, s, "obj", "c", day(20))
+	current := putVersionAt(t, s, "obj", "d", day(25))
+
+	// Objects outside of the prefix are left alone.
+	putVersionAt(t, s, "other/obj", "a", day(0))
+	putVersionAt(t, s, "other/obj", "b", day(1))
+
+	err := SaveLifecycle(s.bucketDir, api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{
+		{ID: "disabled", Prefix: "obj", Enabled: false, NoncurrentVersionExpirationDays: 1},
+	}})
+	require.NoError(t, err)
+
+	err = s.ApplyLifecycle(now)
+	require.NoError(t, err)
+
+	record, _ := loadObject(t, s, "obj")
+	assert.Len(t, record.Versions, 3)
+
+	err = SaveLifecycle(s.bucketDir, api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{
+		{ID: "noncurrent", Prefix: "obj", Enabled: true, NoncurrentVersionExpirationDays: 7},
+	}})
+	require.NoError(t, err)
+
+	err = s.ApplyLifecycle(now)
+	require.NoError(t, err)
+
+	// "c" was superseded 5 days ago and is kept, "b" and "a" were superseded 10 and 20 days ago.
+	record, content := loadObject(t, s, "obj")
+	assert.Equal(t, "d", content)
+	assert.Equal(t, current.VersionID, record.VersionID)
+	require.Len(t, record.Versions, 1)
+	assert.Equal(t, third.VersionID, record.Versions[0].VersionID)
+	assert.Equal(t, "c", readVersion(t, s, "obj", third.VersionID))
+
+	record, _ = loadObject(t, s, "other/obj")
+	assert.Len(t, record.Versions, 1)
+
+	// Once superseded for long enough, the last noncurrent version goes too.
+	err = s.ApplyLifecycle(now.AddDate(0, 0, 3))
+	require.NoError(t, err)
+
+	record, content = loadObject(t, s, "obj")
+	assert.Equal(t, "d", content)
+	assert.Empty(t, record.Versions)
+	assert.NoFileExists(t, s.versionPath("obj", third.VersionID))
+}
+
+// Test that expired current versions are replaced by a delete marker in versioned buckets and removed otherwise.
+func TestServer_expireCurrentVersions(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	rules := api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{
+		{ID: "current", Enabled: true, ExpirationDays: 7},
+	}}
+
+	t.Run("Unversioned", func(t *testing.T) {
+		s := NewServer(t.TempDir(), nil)
+
+		putVersionAt(t, s, "old", "a", now.AddDate(0, 0, -8))
+		putVersionAt(t, s, "new", "a", now.AddDate(0, 0, -6))
+
+		err := SaveLifecycle(s.bucketDir, rules)
+		require.NoError(t, err)
+
+		err = s.ApplyLifecycle(now)
+		require.NoError(t, err)
+
+		record, _ := loadObject(t, s, "old")
+		assert.Nil(t, record)
+
+		record, content := loadObject(t, s, "new")
+		assert.NotNil(t, record)
+		assert.Equal(t, "a", content)
+	})
+
+	t.Run("Versioned", func(t *testing.T) {
+		s := NewServer(t.TempDir(), nil)
+		s.Versioning = VersioningEnabled
+
+		version := putVersionAt(t, s, "old", "a", now.AddDate(0, 0, -8))
+
+		err := SaveLifecycle(s.bucketDir, rules)
+		require.NoError(t, err)
+
+		err = s.ApplyLifecycle(now)
+		require.NoError(t, err)
+
+		record, content := loadObject(t, s, "old")
+		assert.Empty(t, content)
+		assert.True(t, record.DeleteMarker)
+		require.Len(t, record.Versions, 1)
+		assert.Equal(t, version.VersionID, record.Versions[0].VersionID)
+
+		// The delete marker itself doesn't expire.
+		err = s.ApplyLifecycle(now.AddDate(0, 0, 30))
+		require.NoError(t, err)
+
+		record, _ = loadObject(t, s, "old")
+		assert.True(t, record.DeleteMarker)
+		assert.Len(t, record.Versions, 1)
+	})
+}
+
+// Test that only stale incomplete multipart uploads within the prefix are aborted.
+func TestServer_abortStaleUploads(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	uploads := map[string]uploadInfo{
+		"stale":  {Key: "logs/a", Initiated: now.AddDate(0, 0, -3)},
+		"recent": {Key: "logs/b", Initiated: now.AddDate(0, 0, -1)},
+		"other":  {Key: "data/c", Initiated: now.AddDate(0, 0, -3)},
+	}
+
+	for id, info := range uploads {
+		err := os.MkdirAll(filepath.Join(s.uploadsDir(), id), 0o700)
+		require.NoError(t, err)
+
+		b, err := json.Marshal(info)
+		require.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(s.uploadsDir(), id, "upload.json"), b, 0o600)
+		require.NoError(t, err)
+	}
+
+	err := SaveLifecycle(s.bucketDir, api.StorageBucketLifecycle{Rules: []api.StorageBucketLifecycleRule{
+		{ID: "uploads", Prefix: "logs/", Enabled: true, AbortIncompleteMultipartUploadDays: 2},
+	}})
+	require.NoError(t, err)
+
+	err = s.ApplyLifecycle(now)
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, filepath.Join(s.uploadsDir(), "stale"))
+	assert.DirExists(t, filepath.Join(s.uploadsDir(), "recent"))
+	assert.DirExists(t, filepath.Join(s.uploadsDir(), "other"))
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/lxc/incus/v7/internal/server/storage/s3"
+	"github.com/lxc/incus/v7/shared/util"
 )
 
 // listObjectsV2Result is the XML root for ListObjectsV2 responses.
@@ -62,7 +63,7 @@ func (s *Server) listObjects(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	keys, err := s.collectKeys()
+	keys, err := s.collectKeys(false)
 	if err != nil {
 		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
 		return
@@ -147,8 +148,10 @@ func (s *Server) listObjects(w http.ResponseWriter, r *http.Request) {
 }
 
 // collectKeys walks the data directory and returns the list of object keys.
-// Sidecar files, the uploads directory, and temporary files are skipped.
-func (s *Server) collectKeys() ([]string, error) {
+// Sidecar files, the uploads and versions directories, and temporary files are
+// skipped. When deleted is true, the keys of objects whose current version is
+// a delete marker are included too.
+func (s *Server) collectKeys(deleted bool) ([]string, error) {
 	root := s.dataDir()
 	keys := []string{}
 
@@ -171,7 +174,7 @@ func (s *Server) collectKeys() ([]string, error) {
 		}
 
 		if d.IsDir() {
-			if rel == uploadsSubdir {
+			if rel == uploadsSubdir || rel == versionsSubdir {
 				return filepath.SkipDir
 			}
 
@@ -180,7 +183,17 @@ func (s *Server) collectKeys() ([]string, error) {
 
 		// Skip metadata, in-flight temporary files, and dotfiles.
 		base := filepath.Base(rel)
-		if strings.HasSuffix(base, metaSuffix) || strings.HasSuffix(base, ".tmp") {
+		if strings.HasSuffix(base, ".tmp") {
+			return nil
+		}
+
+		if strings.HasSuffix(base, metaSuffix) {
+			// Objects with a data file are listed through it.
+			if !deleted || util.PathExists(strings.TrimSuffix(path, metaSuffix)) {
+				return nil
+			}
+
+			keys = append(keys, filepath.ToSlash(strings.TrimSuffix(rel, metaSuffix)))
 			return nil
 		}
 
@@ -17,12 +17,19 @@ import (
 const metaSuffix = ".meta"
 
 // objectMeta is the metadata stored alongside object data files.
+//
+// Much like minio's xl.meta, the record of an object in a versioned bucket
+// also journals its noncurrent versions, newest first. The current version
+// may then be a delete marker, in which case no data file exists.
 type objectMeta struct {
-	ContentType string            `json:"content_type,omitempty"`
-	ETag        string            `json:"etag"`
-	Size        int64             `json:"size"`
-	LastMod     time.Time         `json:"last_modified"`
-	UserMeta    map[string]string `json:"user_meta,omitempty"`
+	ContentType  string            `json:"content_type,omitempty"`
+	ETag         string            `json:"etag"`
+	Size         int64             `json:"size"`
+	LastMod      time.Time         `json:"last_modified"`
+	UserMeta     map[string]string `json:"user_meta,omitempty"`
+	VersionID    string            `json:"version_id,omitempty"`
+	DeleteMarker bool              `json:"delete_marker,omitempty"`
+	Versions     []objectMeta      `json:"versions,omitempty"`
 }
 
 func readMeta(metaPath string) (*objectMeta, error) {
@@ -68,9 +75,16 @@ func removeMeta(metaPath string) error {
 	return nil
 }
 
+// loadOrInferMeta returns the metadata of the current version of the object
+// at dataPath, inferring it from the data file if no metadata was recorded.
+// fs.ErrNotExist is returned if the current version is a delete marker.
 func loadOrInferMeta(dataPath string) (*objectMeta, error) {
 	meta, err := readMeta(metaPathFor(dataPath))
 	if err == nil {
+		if meta.DeleteMarker {
+			return nil, fs.ErrNotExist
+		}
+
 		return meta, nil
 	}
 
@@ -110,3 +124,28 @@ func loadOrInferMeta(dataPath string) (*objectMeta, error) {
 
 	return meta, nil
 }
+
+// loadRecord returns the full metadata record of the object at dataPath,
+// including a current delete marker and the noncurrent versions.
+// A nil record is returned if the object doesn't exist at all.
+func loadRecord(dataPath string) (*objectMeta, error) {
+	meta, err := readMeta(metaPathFor(dataPath))
+	if err == nil {
+		return meta, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	meta, err = loadOrInferMeta(dataPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return meta, nil
+}
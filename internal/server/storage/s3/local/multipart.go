@@ -279,13 +279,6 @@ func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	err = os.Rename(tmp, dataPath)
-	if err != nil {
-		_ = os.Remove(tmp)
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
-		return
-	}
-
 	etag := hex.EncodeToString(combined.Sum(nil))
 	meta := &objectMeta{
 		ContentType: info.ContentType,
@@ -295,9 +288,9 @@ func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request,
 		UserMeta:    info.UserMeta,
 	}
 
-	err = writeMeta(metaPathFor(dataPath), meta)
+	err = s.publishObject(key, dataPath, tmp, meta)
 	if err != nil {
-		_ = os.Remove(dataPath)
+		_ = os.Remove(tmp)
 		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
 		return
 	}
@@ -317,6 +310,7 @@ func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	s.writeVersionHeader(w, meta)
 	w.Header().Set("Content-Type", "application/xml")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>`))
@@ -70,7 +70,7 @@ func (s *Server) objectPath(key string) (string, error) {
 	}
 
 	first, _, _ := strings.Cut(key, "/")
-	if first == uploadsSubdir || strings.HasSuffix(key, metaSuffix) {
+	if first == uploadsSubdir || first == versionsSubdir || strings.HasSuffix(key, metaSuffix) {
 		return "", errors.New("Reserved object key")
 	}
 
@@ -84,18 +84,13 @@ func (s *Server) headObject(w http.ResponseWriter, r *http.Request, key string)
 		return
 	}
 
-	meta, err := loadOrInferMeta(dataPath)
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			(&s3.Error{Code: s3.ErrorCodeNoSuchBucket, Message: "Object not found."}).Response(w)
-			return
-		}
-
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+	meta, _, ok := s.resolveVersion(w, key, dataPath, r.URL.Query().Get("versionId"))
+	if !ok {
 		return
 	}
 
 	writeObjectHeaders(w, meta)
+	s.writeVersionHeader(w, meta)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -106,18 +101,12 @@ func (s *Server) getObject(w http.ResponseWriter, r *http.Request, key string) {
 		return
 	}
 
-	meta, err := loadOrInferMeta(dataPath)
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			(&s3.Error{Code: s3.ErrorCodeNoSuchBucket, Message: "Object not found."}).Response(w)
-			return
-		}
-
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+	meta, path, ok := s.resolveVersion(w, key, dataPath, r.URL.Query().Get("versionId"))
+	if !ok {
 		return
 	}
 
-	f, err := os.Open(dataPath)
+	f, err := os.Open(path)
 	if err != nil {
 		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
 		return
@@ -125,6 +114,8 @@ func (s *Server) getObject(w http.ResponseWriter, r *http.Request, key string) {
 
 	defer logger.WarnOnError(f.Close, "Failed to close file")
 
+	s.writeVersionHeader(w, meta)
+
 	rangeHeader := r.Header.Get("Range")
 	if rangeHeader == "" {
 		writeObjectHeaders(w, meta)
@@ -206,13 +197,6 @@ func (s *Server) putObject(w http.ResponseWriter, r *http.Request, key string) {
 		return
 	}
 
-	err = os.Rename(tmp, dataPath)
-	if err != nil {
-		_ = os.Remove(tmp)
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
-		return
-	}
-
 	etag := hex.EncodeToString(hasher.Sum(nil))
 
 	meta := &objectMeta{
@@ -223,14 +207,15 @@ func (s *Server) putObject(w http.ResponseWriter, r *http.Request, key string) {
 		UserMeta:    extractUserMeta(r.Header),
 	}
 
-	err = writeMeta(metaPathFor(dataPath), meta)
+	err = s.publishObject(key, dataPath, tmp, meta)
 	if err != nil {
-		_ = os.Remove(dataPath)
+		_ = os.Remove(tmp)
 		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
 		return
 	}
 
 	w.Header().Set("ETag", `"`+etag+`"`)
+	s.writeVersionHeader(w, meta)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -241,7 +226,7 @@ func (s *Server) putObject(w http.ResponseWriter, r *http.Request, key string) {
 // object's content-type and user metadata. REPLACE substitutes the values
 // supplied on the request.
 func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, key string) {
-	srcKey, ok := parseCopySource(r.Header.Get("X-Amz-Copy-Source"))
+	srcKey, srcVersionID, ok := parseCopySource(r.Header.Get("X-Amz-Copy-Source"))
 	if !ok {
 		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: "Invalid X-Amz-Copy-Source header."}).Response(w)
 		return
@@ -259,14 +244,8 @@ func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, key string)
 		return
 	}
 
-	srcMeta, err := loadOrInferMeta(srcPath)
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			(&s3.Error{Code: s3.ErrorCodeNoSuchBucket, Message: "Source object not found."}).Response(w)
-			return
-		}
-
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+	srcMeta, srcPath, ok := s.resolveVersion(w, srcKey, srcPath, srcVersionID)
+	if !ok {
 		return
 	}
 
@@ -310,13 +289,6 @@ func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, key string)
 		return
 	}
 
-	err = os.Rename(tmp, dstPath)
-	if err != nil {
-		_ = os.Remove(tmp)
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
-		return
-	}
-
 	contentType := srcMeta.ContentType
 	userMeta := srcMeta.UserMeta
 	if strings.EqualFold(r.Header.Get("X-Amz-Metadata-Directive"), "REPLACE") {
@@ -334,9 +306,11 @@ func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, key string)
 		UserMeta:    userMeta,
 	}
 
-	err = writeMeta(metaPathFor(dstPath), meta)
+	objectWriteMu.Lock()
+	err = s.publishObject(key, dstPath, tmp, meta)
+	objectWriteMu.Unlock()
 	if err != nil {
-		_ = os.Remove(dstPath)
+		_ = os.Remove(tmp)
 		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
 		return
 	}
@@ -356,39 +330,45 @@ func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, key string)
 		return
 	}
 
+	if s.Versioning != VersioningUnversioned || srcMeta.VersionID != "" {
+		w.Header().Set("X-Amz-Copy-Source-Version-Id", versionIDOrNull(srcMeta.VersionID))
+	}
+
+	s.writeVersionHeader(w, meta)
 	w.Header().Set("Content-Type", "application/xml")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>`))
 	_, _ = w.Write(resp)
 }
 
-// parseCopySource extracts the source object key from an X-Amz-Copy-Source
-// header value. The value has the form "[/]bucket/key" with the key
-// optionally percent-encoded and an optional "?versionId=..." suffix.
-func parseCopySource(v string) (string, bool) {
+// parseCopySource extracts the source object key and version ID from an
+// X-Amz-Copy-Source header value. The value has the form "[/]bucket/key" with
+// the key optionally percent-encoded and an optional "?versionId=..." suffix.
+func parseCopySource(v string) (string, string, bool) {
 	if v == "" {
-		return "", false
+		return "", "", false
 	}
 
-	// Drop the optional version-id query suffix.
-	idx := strings.Index(v, "?")
-	if idx >= 0 {
-		v = v[:idx]
+	v, rawQuery, _ := strings.Cut(v, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", "", false
 	}
 
 	decoded, err := url.PathUnescape(v)
 	if err != nil {
-		return "", false
+		return "", "", false
 	}
 
 	decoded = strings.TrimPrefix(decoded, "/")
 
 	_, key, ok := strings.Cut(decoded, "/")
 	if !ok || key == "" {
-		return "", false
+		return "", "", false
 	}
 
-	return key, true
+	return key, query.Get("versionId"), true
 }
 
 // handleObjectACL stubs the object-level ?acl sub-resource.
@@ -429,28 +409,6 @@ func (s *Server) handleObjectACL(w http.ResponseWriter, r *http.Request, key str
 	}
 }
 
-func (s *Server) deleteObject(w http.ResponseWriter, key string) {
-	dataPath, err := s.objectPath(key)
-	if err != nil {
-		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: err.Error()}).Response(w)
-		return
-	}
-
-	err = os.Remove(dataPath)
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
-		return
-	}
-
-	err = removeMeta(metaPathFor(dataPath))
-	if err != nil {
-		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
-}
-
 func writeObjectHeaders(w http.ResponseWriter, meta *objectMeta) {
 	if meta.ContentType != "" {
 		w.Header().Set("Content-Type", meta.ContentType)
@@ -9,6 +9,9 @@
 //	data/<key>           object data
 //	data/<key>.meta      object metadata (JSON)
 //	data/.uploads/<id>/  in-flight multipart upload state
+//	data/.versions/<key>/<version-id>
+//	                     data of the noncurrent versions of an object
+//	lifecycle.json       lifecycle configuration of the bucket (JSON)
 package local
 
 import (
@@ -21,8 +24,9 @@ import (
 )
 
 const (
-	dataSubdir    = "data"
-	uploadsSubdir = ".uploads"
+	dataSubdir     = "data"
+	uploadsSubdir  = ".uploads"
+	versionsSubdir = ".versions"
 )
 
 // Role describes what operations a Credential is permitted to perform.
@@ -53,6 +57,14 @@ type Server struct {
 	// Errors are returned to the client as an internal-error response and
 	// dispatch is aborted.
 	OnAuthenticated func() error
+
+	// Versioning is the versioning state of the bucket.
+	Versioning VersioningStatus
+
+	// OnVersioningChange, if set, is invoked to persist the versioning state
+	// requested by a client through PutBucketVersioning. Without it, the
+	// versioning state can't be changed over S3.
+	OnVersioningChange func(status VersioningStatus) error
 }
 
 // NewServer returns a Server rooted at bucketDir.
@@ -171,10 +183,51 @@ func (s *Server) handleBucket(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		_, ok = q["versions"]
+		if ok {
+			s.listObjectVersions(w, r)
+			return
+		}
+
+		_, ok = q["lifecycle"]
+		if ok {
+			s.getBucketLifecycle(w)
+			return
+		}
+
 		s.listObjects(w, r)
 	case http.MethodHead:
 		// Bucket exist if we made it this far.
 		w.WriteHeader(http.StatusOK)
+	case http.MethodPut:
+		_, ok := r.URL.Query()["versioning"]
+		if ok {
+			s.putBucketVersioning(w, r)
+			return
+		}
+
+		_, ok = r.URL.Query()["lifecycle"]
+		if ok {
+			s.putBucketLifecycle(w, r)
+			return
+		}
+
+		(&s3.Error{
+			Code:    s3.ErrorInvalidRequest,
+			Message: "Bucket lifecycle is managed by the Incus API.",
+		}).Response(w)
+	case http.MethodDelete:
+		_, ok := r.URL.Query()["lifecycle"]
+		if ok {
+			s.deleteBucketLifecycle(w)
+			return
+		}
+
+		// We don't allow bucket deletion.
+		(&s3.Error{
+			Code:    s3.ErrorInvalidRequest,
+			Message: "Bucket lifecycle is managed by the Incus API.",
+		}).Response(w)
 	default:
 		// We don't allow bucket creation/deletion.
 		(&s3.Error{
@@ -184,17 +237,6 @@ func (s *Server) handleBucket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// getBucketVersioning stubs the bucket-level ?versioning sub-resource.
-// Versioning is never enabled, so return an empty configuration.
-func (s *Server) getBucketVersioning(w http.ResponseWriter) {
-	const body = `<?xml version="1.0" encoding="UTF-8"?>` +
-		`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></VersioningConfiguration>`
-
-	w.Header().Set("Content-Type", "application/xml")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(body))
-}
-
 func (s *Server) handleObject(w http.ResponseWriter, r *http.Request, objectKey string) {
 	q := r.URL.Query()
 	_, ok := q["uploads"]
@@ -239,7 +281,7 @@ func (s *Server) handleObject(w http.ResponseWriter, r *http.Request, objectKey
 
 		s.putObject(w, r, objectKey)
 	case http.MethodDelete:
-		s.deleteObject(w, objectKey)
+		s.deleteObject(w, r, objectKey)
 	default:
 		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: "Unsupported method."}).Response(w)
 	}
@@ -0,0 +1,603 @@
+package local
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lxc/incus/v7/internal/server/storage/s3"
+)
+
+// nullVersionID is the version ID of objects written while versioning wasn't enabled.
+const nullVersionID = "null"
+
+// VersioningStatus is the versioning state of a bucket.
+type VersioningStatus string
+
+const (
+	// VersioningUnversioned is the state of a bucket on which versioning was never configured.
+	VersioningUnversioned VersioningStatus = ""
+
+	// VersioningEnabled keeps every version of the objects.
+	VersioningEnabled VersioningStatus = "Enabled"
+
+	// VersioningSuspended keeps the existing versions but new writes replace the null version.
+	VersioningSuspended VersioningStatus = "Suspended"
+)
+
+// versioningConfiguration is the XML body of the bucket-level ?versioning sub-resource.
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
+// listVersionsResult is the XML root for ListObjectVersions responses.
+type listVersionsResult struct {
+	XMLName             xml.Name           `xml:"ListVersionsResult"`
+	Name                string             `xml:"Name,omitempty"`
+	Prefix              string             `xml:"Prefix"`
+	KeyMarker           string             `xml:"KeyMarker"`
+	VersionIDMarker     string             `xml:"VersionIdMarker"`
+	NextKeyMarker       string             `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string             `xml:"NextVersionIdMarker,omitempty"`
+	Delimiter           string             `xml:"Delimiter,omitempty"`
+	MaxKeys             int                `xml:"MaxKeys"`
+	IsTruncated         bool               `xml:"IsTruncated"`
+	Entries             []any              // listVersion and listDeleteMarker, in listing order.
+	CommonPrefixes      []listCommonPrefix `xml:"CommonPrefixes"`
+}
+
+type listVersion struct {
+	XMLName      xml.Name `xml:"Version"`
+	Key          string   `xml:"Key"`
+	VersionID    string   `xml:"VersionId"`
+	IsLatest     bool     `xml:"IsLatest"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+	Size         int64    `xml:"Size"`
+	StorageClass string   `xml:"StorageClass"`
+}
+
+type listDeleteMarker struct {
+	XMLName      xml.Name `xml:"DeleteMarker"`
+	Key          string   `xml:"Key"`
+	VersionID    string   `xml:"VersionId"`
+	IsLatest     bool     `xml:"IsLatest"`
+	LastModified string   `xml:"LastModified"`
+}
+
+// versionIDOrNull returns the version ID as reported to clients.
+func versionIDOrNull(versionID string) string {
+	if versionID == "" {
+		return nullVersionID
+	}
+
+	return versionID
+}
+
+// versionPath returns the path of the data of a noncurrent version of an object.
+func (s *Server) versionPath(key string, versionID string) string {
+	return filepath.Join(s.dataDir(), versionsSubdir, key, versionIDOrNull(versionID))
+}
+
+// newVersionID returns the version ID of a new version of an object.
+// Versions written while versioning isn't enabled get the null version ID.
+func (s *Server) newVersionID() string {
+	if s.Versioning != VersioningEnabled {
+		return ""
+	}
+
+	return uuid.New().String()
+}
+
+// writeVersionHeader reports the version ID of the object version being served or written.
+func (s *Server) writeVersionHeader(w http.ResponseWriter, meta *objectMeta) {
+	if s.Versioning == VersioningUnversioned && meta.VersionID == "" {
+		return
+	}
+
+	w.Header().Set("X-Amz-Version-Id", versionIDOrNull(meta.VersionID))
+}
+
+// supersede retires the current version of an object in favor of a new version
+// with the given version ID, and returns the resulting noncurrent versions.
+//
+// The data of the current version is moved aside unless the new version
+// replaces it, as is the case for null versions. The caller must hold
+// objectWriteMu.
+func (s *Server) supersede(key string, dataPath string, record *objectMeta, versionID string) ([]objectMeta, error) {
+	if record == nil {
+		return nil, nil
+	}
+
+	current := *record
+	current.Versions = nil
+
+	// There is at most one null version.
+	versions := make([]objectMeta, 0, len(record.Versions)+1)
+	for _, version := range record.Versions {
+		if versionID == "" && version.VersionID == "" {
+			err := os.Remove(s.versionPath(key, version.VersionID))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+
+			continue
+		}
+
+		versions = append(versions, version)
+	}
+
+	if versionID == "" && current.VersionID == "" {
+		return versions, nil
+	}
+
+	if !current.DeleteMarker {
+		versionPath := s.versionPath(key, current.VersionID)
+
+		err := os.MkdirAll(filepath.Dir(versionPath), 0o700)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.Rename(dataPath, versionPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append([]objectMeta{current}, versions...), nil
+}
+
+// publishObject moves the data file at tmp in place as the new current
+// version of the object and records its metadata. The previous version is kept
+// as a noncurrent version if versioning is, or was, enabled on the bucket.
+// The caller must hold objectWriteMu.
+func (s *Server) publishObject(key string, dataPath string, tmp string, meta *objectMeta) error {
+	record, err := loadRecord(dataPath)
+	if err != nil {
+		return err
+	}
+
+	meta.VersionID = s.newVersionID()
+	meta.Versions, err = s.supersede(key, dataPath, record, meta.VersionID)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp, dataPath)
+	if err != nil {
+		return err
+	}
+
+	return writeMeta(metaPathFor(dataPath), meta)
+}
+
+// resolveVersion returns the metadata and data path of the requested version of
+// the object, or of its current version if no version ID is given.
+// On failure, the error response is written and false is returned.
+func (s *Server) resolveVersion(w http.ResponseWriter, key string, dataPath string, versionID string) (*objectMeta, string, bool) {
+	record, err := loadRecord(dataPath)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return nil, "", false
+	}
+
+	if record == nil {
+		(&s3.Error{Code: s3.ErrorCodeNoSuchBucket, Message: "Object not found."}).Response(w)
+		return nil, "", false
+	}
+
+	meta := record
+	path := dataPath
+	if versionID != "" && versionID != versionIDOrNull(record.VersionID) {
+		meta = nil
+		for i, version := range record.Versions {
+			if versionID == versionIDOrNull(version.VersionID) {
+				meta = &record.Versions[i]
+				path = s.versionPath(key, version.VersionID)
+				break
+			}
+		}
+
+		if meta == nil {
+			(&s3.Error{Code: s3.ErrorCodeNoSuchVersion, Message: "Object version not found."}).Response(w)
+			return nil, "", false
+		}
+	}
+
+	if meta.DeleteMarker {
+		w.Header().Set("X-Amz-Delete-Marker", "true")
+		s.writeVersionHeader(w, meta)
+
+		if versionID != "" {
+			(&s3.Error{Code: s3.ErrorCodeMethodNotAllowed, Message: "Object version is a delete marker."}).Response(w)
+		} else {
+			(&s3.Error{Code: s3.ErrorCodeNoSuchBucket, Message: "Object not found."}).Response(w)
+		}
+
+		return nil, "", false
+	}
+
+	return meta, path, true
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request, key string) {
+	dataPath, err := s.objectPath(key)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: err.Error()}).Response(w)
+		return
+	}
+
+	objectWriteMu.Lock()
+	defer objectWriteMu.Unlock()
+
+	record, err := loadRecord(dataPath)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	versionID := r.URL.Query().Get("versionId")
+	if versionID != "" {
+		removed, err := s.removeVersion(key, dataPath, record, versionID)
+		if err != nil {
+			(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+			return
+		}
+
+		// Deleting a version that doesn't exist is a no-op.
+		if removed != nil && removed.DeleteMarker {
+			w.Header().Set("X-Amz-Delete-Marker", "true")
+		}
+
+		w.Header().Set("X-Amz-Version-Id", versionID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	marker, err := s.removeCurrent(key, dataPath, record)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	if marker != nil {
+		w.Header().Set("X-Amz-Delete-Marker", "true")
+		s.writeVersionHeader(w, marker)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeCurrent removes the current version of an object. Unless the bucket is
+// unversioned, a delete marker takes its place and is returned.
+// The caller must hold objectWriteMu.
+func (s *Server) removeCurrent(key string, dataPath string, record *objectMeta) (*objectMeta, error) {
+	// Without versioning, the object is simply removed.
+	if s.Versioning == VersioningUnversioned && (record == nil || len(record.Versions) == 0) {
+		err := os.Remove(dataPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		return nil, removeMeta(metaPathFor(dataPath))
+	}
+
+	marker := &objectMeta{
+		LastMod:      time.Now().UTC(),
+		VersionID:    s.newVersionID(),
+		DeleteMarker: true,
+	}
+
+	var err error
+
+	marker.Versions, err = s.supersede(key, dataPath, record, marker.VersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A current null version is replaced by the delete marker.
+	err = os.Remove(dataPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(dataPath), 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeMeta(metaPathFor(dataPath), marker)
+	if err != nil {
+		return nil, err
+	}
+
+	return marker, nil
+}
+
+// removeVersion permanently removes a single version of an object and returns
+// it, or nil if there is no such version. When the current version is removed,
+// the most recent noncurrent version takes its place.
+// The caller must hold objectWriteMu.
+func (s *Server) removeVersion(key string, dataPath string, record *objectMeta, versionID string) (*objectMeta, error) {
+	if record == nil {
+		return nil, nil
+	}
+
+	if versionID == versionIDOrNull(record.VersionID) {
+		err := os.Remove(dataPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		if len(record.Versions) == 0 {
+			return record, removeMeta(metaPathFor(dataPath))
+		}
+
+		// Promote the most recent noncurrent version.
+		promoted := record.Versions[0]
+		promoted.Versions = record.Versions[1:]
+
+		if !promoted.DeleteMarker {
+			versionPath := s.versionPath(key, promoted.VersionID)
+
+			err = os.Rename(versionPath, dataPath)
+			if err != nil {
+				return nil, err
+			}
+
+			_ = os.Remove(filepath.Dir(versionPath))
+		}
+
+		return record, writeMeta(metaPathFor(dataPath), &promoted)
+	}
+
+	var removed *objectMeta
+
+	versions := make([]objectMeta, 0, len(record.Versions))
+	for i, version := range record.Versions {
+		if versionID != versionIDOrNull(version.VersionID) {
+			versions = append(versions, version)
+			continue
+		}
+
+		removed = &record.Versions[i]
+
+		if !version.DeleteMarker {
+			versionPath := s.versionPath(key, version.VersionID)
+
+			err := os.Remove(versionPath)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+
+			_ = os.Remove(filepath.Dir(versionPath))
+		}
+	}
+
+	if removed == nil {
+		return nil, nil
+	}
+
+	record.Versions = versions
+
+	// Nothing is left behind a current delete marker.
+	if record.DeleteMarker && len(versions) == 0 {
+		return removed, removeMeta(metaPathFor(dataPath))
+	}
+
+	return removed, writeMeta(metaPathFor(dataPath), record)
+}
+
+// getBucketVersioning implements GetBucketVersioning.
+func (s *Server) getBucketVersioning(w http.ResponseWriter) {
+	body, err := xml.Marshal(&versioningConfiguration{
+		Xmlns:  "http://s3.amazonaws.com/doc/2006-03-01/",
+		Status: string(s.Versioning),
+	})
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>`))
+	_, _ = w.Write(body)
+}
+
+// putBucketVersioning implements PutBucketVersioning.
+func (s *Server) putBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	config := &versioningConfiguration{}
+	err = xml.Unmarshal(body, config)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: err.Error()}).Response(w)
+		return
+	}
+
+	status := VersioningStatus(config.Status)
+	if status != VersioningEnabled && status != VersioningSuspended {
+		(&s3.Error{Code: s3.ErrorInvalidRequest, Message: "Versioning status must be Enabled or Suspended."}).Response(w)
+		return
+	}
+
+	if s.OnVersioningChange == nil {
+		(&s3.Error{Code: s3.ErrorCodeNotImplemented, Message: "Bucket versioning can't be changed."}).Response(w)
+		return
+	}
+
+	if status != s.Versioning {
+		err = s.OnVersioningChange(status)
+		if err != nil {
+			(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+			return
+		}
+
+		s.Versioning = status
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// listObjectVersions implements ListObjectVersions.
+func (s *Server) listObjectVersions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+	delimiter := q.Get("delimiter")
+	keyMarker := q.Get("key-marker")
+	versionIDMarker := q.Get("version-id-marker")
+
+	maxKeys := 1000
+
+	v := q.Get("max-keys")
+	if v != "" {
+		n, err := strconv.Atoi(v)
+		if err == nil && n > 0 && n < 1000 {
+			maxKeys = n
+		}
+	}
+
+	keys, err := s.collectKeys(true)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	sort.Strings(keys)
+
+	result := &listVersionsResult{
+		Prefix:          prefix,
+		KeyMarker:       keyMarker,
+		VersionIDMarker: versionIDMarker,
+		Delimiter:       delimiter,
+		MaxKeys:         maxKeys,
+	}
+
+	count := 0
+	lastKey := ""
+	lastVersionID := ""
+	seenPrefix := map[string]bool{}
+
+keys:
+	for _, k := range keys {
+		if keyMarker != "" && k < keyMarker {
+			continue
+		}
+
+		if k == keyMarker && versionIDMarker == "" {
+			continue
+		}
+
+		if prefix != "" && !strings.HasPrefix(k, prefix) {
+			continue
+		}
+
+		if delimiter != "" {
+			rest := strings.TrimPrefix(k, prefix)
+
+			idx := strings.Index(rest, delimiter)
+			if idx >= 0 {
+				cp := prefix + rest[:idx+len(delimiter)]
+				if !seenPrefix[cp] && cp != keyMarker {
+					seenPrefix[cp] = true
+					if count >= maxKeys {
+						result.IsTruncated = true
+						break
+					}
+
+					result.CommonPrefixes = append(result.CommonPrefixes, listCommonPrefix{Prefix: cp})
+					count++
+					lastKey = cp
+					lastVersionID = ""
+				}
+
+				continue
+			}
+		}
+
+		record, err := loadRecord(filepath.Join(s.dataDir(), k))
+		if err != nil || record == nil {
+			// Object vanished between walk and read.
+			continue
+		}
+
+		// Skip the versions up to the version ID marker.
+		skip := k == keyMarker
+
+		versions := append([]objectMeta{*record}, record.Versions...)
+		for i, version := range versions {
+			versionID := versionIDOrNull(version.VersionID)
+
+			if skip {
+				if versionID == versionIDMarker {
+					skip = false
+				}
+
+				continue
+			}
+
+			if count >= maxKeys {
+				result.IsTruncated = true
+				break keys
+			}
+
+			lastModified := version.LastMod.UTC().Format("2006-01-02T15:04:05.000Z")
+			if version.DeleteMarker {
+				result.Entries = append(result.Entries, listDeleteMarker{
+					Key:          k,
+					VersionID:    versionID,
+					IsLatest:     i == 0,
+					LastModified: lastModified,
+				})
+			} else {
+				result.Entries = append(result.Entries, listVersion{
+					Key:          k,
+					VersionID:    versionID,
+					IsLatest:     i == 0,
+					LastModified: lastModified,
+					ETag:         `"` + version.ETag + `"`,
+					Size:         version.Size,
+					StorageClass: "STANDARD",
+				})
+			}
+
+			count++
+			lastKey = k
+			lastVersionID = versionID
+		}
+	}
+
+	if result.IsTruncated {
+		result.NextKeyMarker = lastKey
+		result.NextVersionIDMarker = lastVersionID
+	}
+
+	body, err := xml.Marshal(result)
+	if err != nil {
+		(&s3.Error{Code: s3.ErrorCodeInternalError, Message: err.Error()}).Response(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>`))
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,250 @@
+package local
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// putVersion writes a new current version of the object holding the given content.
+func putVersion(t *testing.T, s *Server, key string, content string) *objectMeta {
+	t.Helper()
+
+	return putVersionAt(t, s, key, content, time.Now().UTC())
+}
+
+// putVersionAt writes a new current version of the object holding the given content, last modified at the given time.
+func putVersionAt(t *testing.T, s *Server, key string, content string, lastMod time.Time) *objectMeta {
+	t.Helper()
+
+	dataPath, err := s.objectPath(key)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Dir(dataPath), 0o700)
+	require.NoError(t, err)
+
+	tmp := filepath.Join(s.dataDir(), "tmp")
+	err = os.WriteFile(tmp, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	meta := &objectMeta{ETag: content, Size: int64(len(content)), LastMod: lastMod}
+	err = s.publishObject(key, dataPath, tmp, meta)
+	require.NoError(t, err)
+
+	return meta
+}
+
+// loadObject returns the record of the object and the content of its current version.
+func loadObject(t *testing.T, s *Server, key string) (*objectMeta, string) {
+	t.Helper()
+
+	dataPath, err := s.objectPath(key)
+	require.NoError(t, err)
+
+	record, err := loadRecord(dataPath)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(dataPath)
+	if os.IsNotExist(err) {
+		return record, ""
+	}
+
+	require.NoError(t, err)
+
+	return record, string(content)
+}
+
+// readVersion returns the content of a noncurrent version of the object.
+func readVersion(t *testing.T, s *Server, key string, versionID string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(s.versionPath(key, versionID))
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+// Test that objects written without versioning become the null version once versioning is enabled.
+func TestServer_nullVersions(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+
+	putVersion(t, s, "obj", "a")
+	putVersion(t, s, "obj", "b")
+
+	record, content := loadObject(t, s, "obj")
+	assert.Equal(t, "b", content)
+	assert.Empty(t, record.VersionID)
+	assert.Empty(t, record.Versions)
+
+	s.Versioning = VersioningEnabled
+	meta := putVersion(t, s, "obj", "c")
+	assert.NotEmpty(t, meta.VersionID)
+
+	record, content = loadObject(t, s, "obj")
+	assert.Equal(t, "c", content)
+	assert.Equal(t, meta.VersionID, record.VersionID)
+	require.Len(t, record.Versions, 1)
+	assert.Empty(t, record.Versions[0].VersionID)
+	assert.Equal(t, "b", readVersion(t, s, "obj", ""))
+
+	// The null version can be retrieved by its version ID.
+	dataPath, _ := s.objectPath("obj")
+	version, path, ok := s.resolveVersion(httptest.NewRecorder(), "obj", dataPath, nullVersionID)
+	require.True(t, ok)
+	assert.Equal(t, "b", version.ETag)
+	assert.Equal(t, s.versionPath("obj", ""), path)
+}
+
+// Test that writes while versioning is suspended replace the null version and keep the other versions.
+func TestServer_suspendedOverwrites(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+
+	putVersion(t, s, "obj", "a")
+
+	s.Versioning = VersioningEnabled
+	versioned := putVersion(t, s, "obj", "b")
+
+	s.Versioning = VersioningSuspended
+	putVersion(t, s, "obj", "c")
+
+	// The noncurrent null version is replaced by the new current null version.
+	record, content := loadObject(t, s, "obj")
+	assert.Equal(t, "c", content)
+	assert.Empty(t, record.VersionID)
+	require.Len(t, record.Versions, 1)
+	assert.Equal(t, versioned.VersionID, record.Versions[0].VersionID)
+	assert.Equal(t, "b", readVersion(t, s, "obj", versioned.VersionID))
+	assert.NoFileExists(t, s.versionPath("obj", ""))
+
+	// The current null version is overwritten in place.
+	putVersion(t, s, "obj", "d")
+
+	record, content = loadObject(t, s, "obj")
+	assert.Equal(t, "d", content)
+	assert.Empty(t, record.VersionID)
+	require.Len(t, record.Versions, 1)
+	assert.Equal(t, versioned.VersionID, record.Versions[0].VersionID)
+}
+
+// Test that deleting an object of a versioned bucket leaves a delete marker which can itself be deleted.
+func TestServer_deleteMarkers(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+	s.Versioning = VersioningEnabled
+
+	version := putVersion(t, s, "obj", "a")
+
+	dataPath, _ := s.objectPath("obj")
+	record, _ := loadObject(t, s, "obj")
+
+	marker, err := s.removeCurrent("obj", dataPath, record)
+	require.NoError(t, err)
+	require.NotNil(t, marker)
+	assert.True(t, marker.DeleteMarker)
+	assert.NotEmpty(t, marker.VersionID)
+
+	record, content := loadObject(t, s, "obj")
+	assert.Empty(t, content)
+	assert.True(t, record.DeleteMarker)
+	require.Len(t, record.Versions, 1)
+	assert.Equal(t, version.VersionID, record.Versions[0].VersionID)
+
+	// The object is gone but its previous version is still there.
+	w := httptest.NewRecorder()
+	_, _, ok := s.resolveVersion(w, "obj", dataPath, "")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "true", w.Header().Get("X-Amz-Delete-Marker"))
+
+	w = httptest.NewRecorder()
+	_, _, ok = s.resolveVersion(w, "obj", dataPath, marker.VersionID)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	_, path, ok := s.resolveVersion(httptest.NewRecorder(), "obj", dataPath, version.VersionID)
+	require.True(t, ok)
+	assert.Equal(t, s.versionPath("obj", version.VersionID), path)
+
+	// Deleting the delete marker brings the object back.
+	removed, err := s.removeVersion("obj", dataPath, record, marker.VersionID)
+	require.NoError(t, err)
+	require.NotNil(t, removed)
+	assert.True(t, removed.DeleteMarker)
+
+	record, content = loadObject(t, s, "obj")
+	assert.Equal(t, "a", content)
+	assert.Equal(t, version.VersionID, record.VersionID)
+	assert.Empty(t, record.Versions)
+}
+
+// Test that deleting a null delete marker while versioning is suspended leaves nothing behind.
+func TestServer_suspendedDeleteMarker(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+	s.Versioning = VersioningSuspended
+
+	putVersion(t, s, "obj", "a")
+
+	dataPath, _ := s.objectPath("obj")
+	record, _ := loadObject(t, s, "obj")
+
+	// The null version is replaced by a null delete marker.
+	marker, err := s.removeCurrent("obj", dataPath, record)
+	require.NoError(t, err)
+	require.NotNil(t, marker)
+	assert.Empty(t, marker.VersionID)
+
+	record, content := loadObject(t, s, "obj")
+	assert.Empty(t, content)
+	assert.True(t, record.DeleteMarker)
+	assert.Empty(t, record.Versions)
+
+	_, err = s.removeVersion("obj", dataPath, record, nullVersionID)
+	require.NoError(t, err)
+
+	record, _ = loadObject(t, s, "obj")
+	assert.Nil(t, record)
+}
+
+// Test that deleting the current version promotes the most recent noncurrent version.
+func TestServer_removeCurrentVersion(t *testing.T) {
+	s := NewServer(t.TempDir(), nil)
+	s.Versioning = VersioningEnabled
+
+	first := putVersion(t, s, "obj", "a")
+	second := putVersion(t, s, "obj", "b")
+	third := putVersion(t, s, "obj", "c")
+
+	dataPath, _ := s.objectPath("obj")
+	record, _ := loadObject(t, s, "obj")
+
+	removed, err := s.removeVersion("obj", dataPath, record, third.VersionID)
+	require.NoError(t, err)
+	require.NotNil(t, removed)
+	assert.Equal(t, third.VersionID, removed.VersionID)
+
+	record, content := loadObject(t, s, "obj")
+	assert.Equal(t, "b", content)
+	assert.Equal(t, second.VersionID, record.VersionID)
+	require.Len(t, record.Versions, 1)
+	assert.Equal(t, first.VersionID, record.Versions[0].VersionID)
+	assert.NoFileExists(t, s.versionPath("obj", second.VersionID))
+
+	// Deleting a noncurrent version leaves the current one alone.
+	record, _ = loadObject(t, s, "obj")
+	_, err = s.removeVersion("obj", dataPath, record, first.VersionID)
+	require.NoError(t, err)
+
+	record, content = loadObject(t, s, "obj")
+	assert.Equal(t, "b", content)
+	assert.Empty(t, record.Versions)
+
+	// Deleting an unknown version is a no-op.
+	removed, err = s.removeVersion("obj", dataPath, record, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, removed)
+}
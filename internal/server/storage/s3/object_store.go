@@ -0,0 +1,118 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/lxc/incus/v7/internal/server/backup/repository"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// ObjectStore is a backup repository store keeping objects under a prefix of an S3 bucket.
+type ObjectStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewObjectStore returns a store keeping objects under the given prefix of the bucket.
+func NewObjectStore(client *s3.Client, bucket string, prefix string) *ObjectStore {
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return &ObjectStore{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Put writes an object.
+func (o *ObjectStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := o.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(o.bucket),
+		Key:           aws.String(o.prefix + key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+
+	return err
+}
+
+// Get reads an object.
+func (o *ObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := o.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(o.prefix + key),
+	})
+	if err != nil {
+		var notFound *s3types.NoSuchKey
+		if errors.As(err, &notFound) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Object %q not found", key)
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	return io.ReadAll(resp.Body)
+}
+
+// Exists returns whether an object exists.
+func (o *ObjectStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := o.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(o.prefix + key),
+	})
+	if err != nil {
+		var notFound *s3types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Delete removes an object.
+func (o *ObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := o.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(o.prefix + key),
+	})
+
+	return err
+}
+
+// List returns the objects whose key starts with the prefix.
+func (o *ObjectStore) List(ctx context.Context, prefix string) ([]repository.Object, error) {
+	objects := []repository.Object{}
+
+	paginator := s3.NewListObjectsV2Paginator(o.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(o.bucket),
+		Prefix: aws.String(o.prefix + prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range page.Contents {
+			objects = append(objects, repository.Object{
+				Key:     aws.ToString(obj.Key)[len(o.prefix):],
+				ModTime: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
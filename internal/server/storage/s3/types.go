@@ -24,6 +24,15 @@ const ErrorCodePreconditionFailed = "PreconditionFailed"
 // ErrorCodeNotImplemented means the requested functionality isn't implemented.
 const ErrorCodeNotImplemented = "NotImplemented"
 
+// ErrorCodeNoSuchVersion means the specified object version does not exist.
+const ErrorCodeNoSuchVersion = "NoSuchVersion"
+
+// ErrorCodeMethodNotAllowed means the method isn't allowed against the resource, like reading a delete marker.
+const ErrorCodeMethodNotAllowed = "MethodNotAllowed"
+
+// ErrorCodeNoSuchLifecycleConfiguration means the bucket has no lifecycle configuration.
+const ErrorCodeNoSuchLifecycleConfiguration = "NoSuchLifecycleConfiguration"
+
 var errorHTTPStatusCodes = map[string]int{
 	ErrorCodeNoSuchBucket:       http.StatusNotFound,
 	ErrorCodeInternalError:      http.StatusInternalServerError,
@@ -31,6 +40,10 @@ var errorHTTPStatusCodes = map[string]int{
 	ErrorInvalidRequest:         http.StatusBadRequest,
 	ErrorCodePreconditionFailed: http.StatusPreconditionFailed,
 	ErrorCodeNotImplemented:     http.StatusNotImplemented,
+	ErrorCodeNoSuchVersion:      http.StatusNotFound,
+	ErrorCodeMethodNotAllowed:   http.StatusMethodNotAllowed,
+
+	ErrorCodeNoSuchLifecycleConfiguration: http.StatusNotFound,
 }
 
 // Error S3 error response.
@@ -562,8 +562,55 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["volatile.rootfs.size"] = validate.Optional(validate.IsInt64)
 	}
 
+	// Object versioning is only relevant for buckets.
+	if vol.Type() == drivers.VolumeTypeBucket {
+		rules["versioning"] = validate.Optional(validate.IsBool)
+	}
+
 	if vol.Type() == drivers.VolumeTypeCustom {
 		rules["dependent"] = validate.Optional(validate.IsBool)
+
+		// Scheduled backups.
+		rules["backups.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+		rules["backups.expiry"] = func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		}
+
+		rules["backups.volume_only"] = validate.Optional(validate.IsBool)
+		rules["backups.retention.last"] = validate.Optional(validate.IsUint32)
+		rules["backups.retention.daily"] = validate.Optional(validate.IsUint32)
+		rules["backups.retention.weekly"] = validate.Optional(validate.IsUint32)
+		rules["backups.target.format"] = validate.Optional(validate.IsOneOf("tarball", "repository"))
+		rules["backups.target.url"] = validate.Optional(validate.IsRequestURL)
+		rules["backups.target.bucket"] = validate.IsAny
+		rules["backups.target.path"] = validate.IsAny
+		rules["backups.target.access_key"] = validate.IsAny
+		rules["backups.target.secret_key"] = validate.IsAny
+
+		// Scheduled replication.
+		rules["replication.target"] = validate.Optional(func(value string) error {
+			// Either the name of a local storage pool or the URL of a remote server.
+			if !strings.Contains(value, "://") {
+				return nil
+			}
+
+			if !strings.HasPrefix(value, "https://") {
+				return errors.New("Remote replication targets must be HTTPS URLs")
+			}
+
+			return validate.IsRequestURL(value)
+		})
+		rules["replication.target.certificate"] = validate.IsAny
+		rules["replication.target.project"] = validate.IsAny
+		rules["replication.target.pool"] = validate.IsAny
+		rules["replication.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+		rules["replication.retention"] = validate.Optional(validate.IsInRange(1, 100))
+		rules["volatile.replication.last_attempt"] = validate.Optional(validate.IsInt64)
+		rules["volatile.replication.last_error"] = validate.IsAny
+		rules["volatile.replication.last_success"] = validate.Optional(validate.IsInt64)
+		rules["volatile.replication.source"] = validate.IsAny
 	}
 
 	return rules
@@ -0,0 +1,123 @@
+// Package tracing records OpenTelemetry traces of the API requests and of the operations they start, and
+// exports them to an OTLP/HTTP collector.
+//
+// The trace context is propagated across cluster members so that a request forwarded to another member shows up
+// as part of the same trace. Tracing is disabled (and spans are no-ops) until Setup is called.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// tracerName is the instrumentation scope of the spans recorded by Incus.
+const tracerName = "github.com/lxc/incus"
+
+// propagator carries the trace context across cluster-internal requests.
+var propagator = propagation.TraceContext{}
+
+var (
+	mu            sync.Mutex
+	provider      *sdktrace.TracerProvider
+	curEndpoint   string
+	curRatio      float64
+	curServerName string
+)
+
+// Setup configures the export of traces to the OTLP/HTTP collector at the given endpoint, recording the given
+// fraction of the traces started locally. An empty endpoint disables tracing.
+func Setup(ctx context.Context, endpoint string, ratio float64, serverName string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if endpoint == curEndpoint && ratio == curRatio && serverName == curServerName {
+		return nil
+	}
+
+	// Stop the current exporter, flushing the pending spans.
+	if provider != nil {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			logger.Warn("Failed flushing traces", logger.Ctx{"endpoint": curEndpoint, "err": err})
+		}
+
+		provider = nil
+	}
+
+	curEndpoint = ""
+
+	if endpoint == "" {
+		return nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return err
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "incus"),
+		attribute.String("service.version", version.Version),
+		attribute.String("service.instance.id", serverName),
+	)
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	curEndpoint = endpoint
+	curRatio = ratio
+	curServerName = serverName
+
+	return nil
+}
+
+// Shutdown flushes the pending spans and disables tracing.
+func Shutdown(ctx context.Context) error {
+	return Setup(ctx, "", 0, "")
+}
+
+// Start starts a new span as a child of the span held by the context, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Inject adds the trace context held by the context to the headers of an outgoing request.
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns a context holding the trace context found in the headers of an incoming request.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
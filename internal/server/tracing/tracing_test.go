@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagation(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05, 0x06},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	header := http.Header{}
+	Inject(trace.ContextWithSpanContext(context.Background(), spanContext), header)
+	assert.NotEmpty(t, header.Get("traceparent"))
+
+	extracted := trace.SpanContextFromContext(Extract(context.Background(), header))
+	assert.Equal(t, spanContext.TraceID(), extracted.TraceID())
+	assert.Equal(t, spanContext.SpanID(), extracted.SpanID())
+	assert.True(t, extracted.IsRemote())
+
+	// Nothing is injected without a span.
+	header = http.Header{}
+	Inject(context.Background(), header)
+	assert.Empty(t, header)
+}
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Spans are no-ops when tracing is disabled.
+	_, span := Start(ctx, "test")
+	assert.False(t, span.SpanContext().IsValid())
+	End(span, errors.New("failed"))
+
+	require.NoError(t, Setup(ctx, "http://127.0.0.1:4318", 1, "server01"))
+	defer func() { require.NoError(t, Shutdown(ctx)) }()
+
+	parentCtx, parent := Start(ctx, "parent")
+	_, child := Start(parentCtx, "child")
+	assert.True(t, parent.SpanContext().IsSampled())
+	assert.Equal(t, parent.SpanContext().TraceID(), child.SpanContext().TraceID())
+
+	// Reconfiguring with the same settings keeps the provider.
+	current := provider
+	require.NoError(t, Setup(ctx, "http://127.0.0.1:4318", 1, "server01"))
+	assert.Same(t, current, provider)
+
+	// Nothing is recorded with a zero rate.
+	require.NoError(t, Setup(ctx, "http://127.0.0.1:4318", 0, "server01"))
+	_, span = Start(ctx, "test")
+	assert.False(t, span.SpanContext().IsSampled())
+}
@@ -570,6 +570,31 @@ var APIExtensions = []string{
 	"device_burst_limits",
 	"network_ipv6_ra",
 	"qemu_scriptlet_nvram",
+	"instance_boot_dependencies",
+	"instance_healthcheck",
+	"backup_schedule",
+	"backup_incremental",
+	"manifest_apply",
+	"metrics_history",
+	"tracing",
+	"audit_log",
+	"storage_bucket_versioning",
+	"storage_bucket_lifecycle",
+	"network_load_balancer_bridge",
+	"network_zone_dns_queries",
+	"network_zone_dnssec",
+	"network_zone_push",
+	"network_bridge_wireguard",
+	"network_bridge_evpn",
+	"network_bridge_peering",
+	"network_flow_export",
+	"projects_limits_network",
+	"network_reservations",
+	"storage_volume_encryption",
+	"replication",
+	"backup_repository",
+	"storage_qcow2_dir_btrfs",
+	"storage_pool_health",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"time"
+)
+
+// AuditEntry represents an entry of the audit log, recording a mutating API call.
+//
+// swagger:model
+//
+// API extension: audit_log.
+type AuditEntry struct {
+	// Identifier of the entry
+	// Example: 42
+	ID int64 `json:"id" yaml:"id"`
+
+	// Time at which the call was handled
+	// Example: 2021-03-23T20:00:00-04:00
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// Cluster member which handled the call
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Caller of the API
+	Requestor EventLifecycleRequestor `json:"requestor" yaml:"requestor"`
+
+	// HTTP method of the call
+	// Example: POST
+	Method string `json:"method" yaml:"method"`
+
+	// URL of the entity the call applied to
+	// Example: /1.0/instances/c1?project=default
+	EntityURL string `json:"entity_url" yaml:"entity_url"`
+
+	// Project the call applied to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// SHA-256 digest of the request body (empty when there was no body)
+	// Example: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	BodyDigest string `json:"body_digest" yaml:"body_digest"`
+
+	// HTTP status code of the response, or of the result of the background operation once it completed
+	// Example: 200
+	StatusCode int `json:"status_code" yaml:"status_code"`
+
+	// Error returned by the call or its background operation, if any
+	// Example: Instance not found
+	Error string `json:"error" yaml:"error"`
+
+	// Identifier of the background operation started by the call, if any
+	// Example: 66e83638-9dd7-4a26-aef2-5462814869a1
+	Operation string `json:"operation" yaml:"operation"`
+}
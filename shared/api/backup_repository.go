@@ -0,0 +1,50 @@
+package api
+
+import (
+	"time"
+)
+
+// BackupRepositoryPost represents an action on a backup repository.
+//
+// swagger:model
+//
+// API extension: backup_repository.
+type BackupRepositoryPost struct {
+	// Action to perform (list, verify or prune)
+	// Example: verify
+	Action string `json:"action" yaml:"action"`
+
+	// Repository to act on
+	Target BackupTarget `json:"target" yaml:"target"`
+
+	// Only apply the action to the backups whose name starts with this prefix (list and verify)
+	// Example: instances/default/c1/
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Backups to delete before removing the unused data (prune)
+	// Example: ["instances/default/c1/backup0"]
+	Delete []string `json:"delete" yaml:"delete"`
+}
+
+// BackupRepositoryBackup represents a backup stored in a backup repository.
+//
+// swagger:model
+//
+// API extension: backup_repository.
+type BackupRepositoryBackup struct {
+	// Backup name
+	// Example: instances/default/c1/backup0
+	Name string `json:"name" yaml:"name"`
+
+	// When the backup was created
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Size of the backup data in bytes
+	// Example: 1073741824
+	Size int64 `json:"size" yaml:"size"`
+
+	// Number of chunks the backup is made of
+	// Example: 512
+	Chunks int `json:"chunks" yaml:"chunks"`
+}
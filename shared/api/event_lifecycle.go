@@ -44,6 +44,7 @@ const (
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
+	EventLifecycleInstanceHealthy                   = "instance-healthy"
 	EventLifecycleInstanceLogDeleted                = "instance-log-deleted"
 	EventLifecycleInstanceLogRetrieved              = "instance-log-retrieved"
 	EventLifecycleInstanceMetadataRetrieved         = "instance-metadata-retrieved"
@@ -67,6 +68,7 @@ const (
 	EventLifecycleInstanceSnapshotUpdated           = "instance-snapshot-updated"
 	EventLifecycleInstanceStarted                   = "instance-started"
 	EventLifecycleInstanceStopped                   = "instance-stopped"
+	EventLifecycleInstanceUnhealthy                 = "instance-unhealthy"
 	EventLifecycleInstanceUpdated                   = "instance-updated"
 	EventLifecycleNetworkACLCreated                 = "network-acl-created"
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
@@ -92,6 +94,9 @@ const (
 	EventLifecycleNetworkPeerDeleted                = "network-peer-deleted"
 	EventLifecycleNetworkPeerUpdated                = "network-peer-updated"
 	EventLifecycleNetworkRenamed                    = "network-renamed"
+	EventLifecycleNetworkReservationCreated         = "network-reservation-created"
+	EventLifecycleNetworkReservationDeleted         = "network-reservation-deleted"
+	EventLifecycleNetworkReservationUpdated         = "network-reservation-updated"
 	EventLifecycleNetworkUpdated                    = "network-updated"
 	EventLifecycleNetworkZoneCreated                = "network-zone-created"
 	EventLifecycleNetworkZoneDeleted                = "network-zone-deleted"
@@ -33,6 +33,12 @@ type BackupTarget struct {
 	// SecretKey is the S3 API access key
 	// Example: secret123
 	SecretKey string `json:"secret_key" yaml:"secret_key"`
+
+	// Format is how the backup is stored on the target (tarball or repository)
+	// Example: repository
+	//
+	// API extension: backup_repository
+	Format string `json:"format" yaml:"format"`
 }
 
 // InstanceBackupsPost represents the fields available for a new instance backup.
@@ -72,6 +78,13 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_s3_upload
 	Target *BackupTarget `json:"target" yaml:"target"`
+
+	// Name of the dirty bitmap to make an incremental backup from
+	// Only the blocks changed since the bitmap was created are included in the backup.
+	// Example: bitmap0
+	//
+	// API extension: backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
 }
 
 // InstanceBackup represents an instance backup.
@@ -70,6 +70,16 @@ type InstanceState struct {
 	//
 	// API extension: instances_state_os_info.
 	OSInfo *InstanceStateOSInfo `json:"os_info" yaml:"os_info"`
+
+	// Health check information (only set when a health check is configured).
+	//
+	// API extension: instance_healthcheck.
+	Health *InstanceStateHealth `json:"health,omitempty" yaml:"health,omitempty"`
+
+	// Replication information (only set when replication is configured or on replicas).
+	//
+	// API extension: replication.
+	Replication *ReplicationState `json:"replication,omitempty" yaml:"replication,omitempty"`
 }
 
 // InstanceStateDisk represents the disk information section of an instance's state.
@@ -258,3 +268,26 @@ type InstanceStateOSInfo struct {
 	// Example: myhost.mydomain.local
 	FQDN string `json:"fqdn" yaml:"fqdn"`
 }
+
+// InstanceStateHealth represents the health check section of an instance's state.
+//
+// swagger:model
+//
+// API extension: instance_healthcheck.
+type InstanceStateHealth struct {
+	// Health status (starting, healthy or unhealthy)
+	// Example: healthy
+	Status string `json:"status" yaml:"status"`
+
+	// Number of consecutive failed checks
+	// Example: 0
+	Failures int `json:"failures" yaml:"failures"`
+
+	// Error returned by the last failed check
+	// Example: Failed connecting to "10.0.0.2:80": connection refused
+	LastError string `json:"last_error" yaml:"last_error"`
+
+	// When the last check was performed
+	// Example: 2021-03-23T20:00:00-04:00
+	LastCheckedAt time.Time `json:"last_checked_at" yaml:"last_checked_at"`
+}
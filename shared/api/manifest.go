@@ -0,0 +1,180 @@
+package api
+
+// ManifestConfigKey is the configuration key used to record which manifest a resource belongs to.
+//
+// API extension: manifest_apply.
+const ManifestConfigKey = "user.manifest"
+
+// Manifest represents a declarative description of a set of resources.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type Manifest struct {
+	// Name of the manifest, used to track ownership of the resources it creates
+	// Example: web
+	Name string `json:"name" yaml:"name"`
+
+	// Projects to converge
+	Projects []ProjectsPost `json:"projects" yaml:"projects"`
+
+	// Network ACLs to converge
+	NetworkACLs []ManifestNetworkACL `json:"network_acls" yaml:"network_acls"`
+
+	// Networks to converge
+	Networks []ManifestNetwork `json:"networks" yaml:"networks"`
+
+	// Custom storage volumes to converge
+	StorageVolumes []ManifestStorageVolume `json:"storage_volumes" yaml:"storage_volumes"`
+
+	// Profiles to converge
+	Profiles []ManifestProfile `json:"profiles" yaml:"profiles"`
+
+	// Instances to converge
+	Instances []ManifestInstance `json:"instances" yaml:"instances"`
+}
+
+// ManifestNetworkACL represents a network ACL along with its project.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestNetworkACL struct {
+	NetworkACLsPost `yaml:",inline"`
+
+	// Project in which the ACL resides
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// ManifestNetwork represents a network along with its project.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestNetwork struct {
+	NetworksPost `yaml:",inline"`
+
+	// Project in which the network resides
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// ManifestStorageVolume represents a custom storage volume along with its pool and project.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestStorageVolume struct {
+	StorageVolumesPost `yaml:",inline"`
+
+	// Storage pool in which the volume resides
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Project in which the volume resides
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// ManifestProfile represents a profile along with its project.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestProfile struct {
+	ProfilesPost `yaml:",inline"`
+
+	// Project in which the profile resides
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// ManifestInstance represents an instance along with its project.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestInstance struct {
+	InstancesPost `yaml:",inline"`
+
+	// Project in which the instance resides
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// ManifestPost represents a request to apply a manifest.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestPost struct {
+	Manifest `yaml:",inline"`
+
+	// Whether to delete resources owned by the manifest that it no longer lists
+	// Example: false
+	Prune bool `json:"prune" yaml:"prune"`
+
+	// Whether to only compute the plan without applying it
+	// Example: true
+	DryRun bool `json:"dry_run" yaml:"dry_run"`
+}
+
+// ManifestPlan represents the changes needed to converge the server to a manifest.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestPlan struct {
+	// List of changes, in the order they are applied
+	Changes []ManifestChange `json:"changes" yaml:"changes"`
+}
+
+// ManifestChange represents a change to a single resource.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestChange struct {
+	// Action taken on the resource (create, update or delete)
+	// Example: update
+	Action string `json:"action" yaml:"action"`
+
+	// Type of resource (project, network-acl, network, storage-volume, profile or instance)
+	// Example: instance
+	Type string `json:"type" yaml:"type"`
+
+	// Project of the resource
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Storage pool of the resource (storage volumes only)
+	// Example: default
+	Pool string `json:"pool,omitempty" yaml:"pool,omitempty"`
+
+	// Name of the resource
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// List of changed fields
+	Fields []ManifestFieldChange `json:"fields" yaml:"fields"`
+}
+
+// ManifestFieldChange represents a change to a single field of a resource.
+//
+// swagger:model
+//
+// API extension: manifest_apply.
+type ManifestFieldChange struct {
+	// Field name
+	// Example: config.limits.cpu
+	Key string `json:"key" yaml:"key"`
+
+	// Current value
+	// Example: 2
+	Old string `json:"old" yaml:"old"`
+
+	// New value
+	// Example: 4
+	New string `json:"new" yaml:"new"`
+}
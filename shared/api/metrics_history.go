@@ -0,0 +1,60 @@
+package api
+
+import (
+	"time"
+)
+
+// MetricsHistory represents the recorded history of instance and host metrics.
+//
+// swagger:model
+//
+// API extension: metrics_history.
+type MetricsHistory struct {
+	// Cluster member the history was recorded on
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// List of series
+	Series []MetricsHistorySeries `json:"series" yaml:"series"`
+}
+
+// MetricsHistorySeries represents the history of a single metric.
+//
+// swagger:model
+//
+// API extension: metrics_history.
+type MetricsHistorySeries struct {
+	// Project of the instance (empty for the host)
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance (empty for the host)
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Name of the metric (cpu, memory, disk_read, disk_write, network_receive, network_transmit or processes)
+	// Example: cpu
+	Metric string `json:"metric" yaml:"metric"`
+
+	// Interval between points in seconds
+	// Example: 60
+	Step int64 `json:"step" yaml:"step"`
+
+	// List of points
+	Points []MetricsHistoryPoint `json:"points" yaml:"points"`
+}
+
+// MetricsHistoryPoint represents the average value of a metric over a step.
+//
+// swagger:model
+//
+// API extension: metrics_history.
+type MetricsHistoryPoint struct {
+	// Start of the step
+	// Example: 2021-03-23T20:00:00-04:00
+	Time time.Time `json:"time" yaml:"time"`
+
+	// Average value (CPU in cores, memory in bytes, disk and network in bytes per second)
+	// Example: 0.25
+	Value float64 `json:"value" yaml:"value"`
+}
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworksPost represents the fields of a new network
 //
 // swagger:model
@@ -188,6 +192,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Additional WireGuard mesh information
+	//
+	// API extension: network_bridge_wireguard
+	WireGuard *NetworkStateWireGuard `json:"wireguard" yaml:"wireguard"`
 }
 
 // NetworkStateAddress represents a network address
@@ -347,3 +356,52 @@ type NetworkStateOVN struct {
 	// API extension: network_ovn_state_addresses
 	UplinkIPv6 string `json:"uplink_ipv6" yaml:"uplink_ipv6"`
 }
+
+// NetworkStateWireGuard represents the state of the WireGuard mesh of a bridge network
+//
+// swagger:model
+//
+// API extension: network_bridge_wireguard.
+type NetworkStateWireGuard struct {
+	// Public key of the cluster member
+	// Example: 8Rz3zv0Mmnhq2tWQfFxzQ4ufZl8c8hVuVtRB5CJ6vWo=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// UDP port WireGuard listens on
+	// Example: 51820
+	ListenPort int `json:"listen_port" yaml:"listen_port"`
+
+	// Other cluster members of the mesh
+	Peers []NetworkStateWireGuardPeer `json:"peers" yaml:"peers"`
+}
+
+// NetworkStateWireGuardPeer represents the state of a peer of the WireGuard mesh of a bridge network
+//
+// swagger:model
+//
+// API extension: network_bridge_wireguard.
+type NetworkStateWireGuardPeer struct {
+	// Name of the cluster member
+	// Example: server02
+	Member string `json:"member" yaml:"member"`
+
+	// Public key of the cluster member
+	// Example: 3xU0kD8k4S2y3m2Bq5r3R1wI7b1cZ0wT9z5Yq8dNn2E=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// Endpoint the cluster member is reached on
+	// Example: 10.0.0.2:51820
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Time of the latest handshake with the cluster member
+	// Example: 2021-03-23T20:00:00-04:00
+	LatestHandshake time.Time `json:"latest_handshake" yaml:"latest_handshake"`
+
+	// Number of bytes received from the cluster member
+	// Example: 250542118
+	BytesReceived int64 `json:"bytes_received" yaml:"bytes_received"`
+
+	// Number of bytes sent to the cluster member
+	// Example: 17524040140
+	BytesSent int64 `json:"bytes_sent" yaml:"bytes_sent"`
+}
@@ -0,0 +1,53 @@
+package api
+
+// NetworkReservationsPost represents the fields of a new network address reservation
+//
+// swagger:model
+//
+// API extension: network_reservations.
+type NetworkReservationsPost struct {
+	NetworkReservationPut `yaml:",inline"`
+
+	// Reserved address or range of addresses (first and last address separated by a dash)
+	// Example: 192.0.2.10-192.0.2.20
+	Address string `json:"address" yaml:"address"`
+}
+
+// NetworkReservationPut represents the modifiable fields of a network address reservation
+//
+// swagger:model
+//
+// API extension: network_reservations.
+type NetworkReservationPut struct {
+	// Owner of the reservation (instance name, or "<project>/<instance>" for instances of another project)
+	// Example: load-balancer-vip
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Description of the reservation
+	// Example: Addresses kept for the upcoming database cluster
+	Description string `json:"description" yaml:"description"`
+}
+
+// NetworkReservation used for displaying a network address reservation.
+//
+// swagger:model
+//
+// API extension: network_reservations.
+type NetworkReservation struct {
+	NetworkReservationPut `yaml:",inline"`
+
+	// Reserved address or range of addresses (first and last address separated by a dash)
+	// Read only: true
+	// Example: 192.0.2.10-192.0.2.20
+	Address string `json:"address" yaml:"address"`
+}
+
+// Etag returns the values used for etag generation.
+func (r *NetworkReservation) Etag() []any {
+	return []any{r.Address, r.Owner, r.Description}
+}
+
+// Writable converts a full NetworkReservation struct into a NetworkReservationPut struct (filters read-only fields).
+func (r *NetworkReservation) Writable() NetworkReservationPut {
+	return r.NetworkReservationPut
+}
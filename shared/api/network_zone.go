@@ -50,6 +50,13 @@ type NetworkZone struct {
 	//
 	// API extension: network_zones_all_projects
 	Project string `json:"project" yaml:"project"`
+
+	// DS records to add to the parent zone when the zone is signed with DNSSEC
+	// Read only: true
+	// Example: ["example.net. 3600 IN DS 16551 13 2 64EABA719693999C294C053FDDA21E34158B3BF56D99A8329CA351BF444E9442"]
+	//
+	// API extension: network_zone_dnssec
+	DS []string `json:"ds,omitempty" yaml:"ds,omitempty"`
 }
 
 // Writable converts a full NetworkZone struct into a NetworkZonePut struct (filters read-only fields).
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"time"
+)
+
+// ReplicationState represents the state of the scheduled replication of an instance or custom storage volume.
+//
+// swagger:model
+//
+// API extension: replication.
+type ReplicationState struct {
+	// Remote server or storage pool the replicas are pushed to (only set on replicated entities)
+	// Example: https://dr.example.net:8443
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// When the last successful replication completed
+	// Example: 2021-03-23T20:00:00-04:00
+	LastSuccess time.Time `json:"last_success" yaml:"last_success"`
+
+	// When the last replication was attempted
+	// Example: 2021-03-23T20:00:00-04:00
+	LastAttempt time.Time `json:"last_attempt" yaml:"last_attempt"`
+
+	// Error returned by the last failed replication
+	// Example: Failed connecting to "https://dr.example.net:8443": connection refused
+	LastError string `json:"last_error,omitempty" yaml:"last_error,omitempty"`
+
+	// Number of seconds since the last successful replication
+	// Example: 300
+	Lag int64 `json:"lag" yaml:"lag"`
+
+	// Server the replica is received from (only set on replicas which haven't been promoted)
+	// Example: server01
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
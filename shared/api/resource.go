@@ -812,6 +812,11 @@ type ResourcesStoragePool struct {
 
 	// Disk inode usage
 	Inodes ResourcesStoragePoolInodes `json:"inodes" yaml:"inodes"`
+
+	// Health of the storage pool, if reported by the driver
+	//
+	// API extension: storage_pool_health
+	Health *ResourcesStoragePoolHealth `json:"health,omitempty" yaml:"health,omitempty"`
 }
 
 // ResourcesStoragePoolSpace represents the space available to a given storage pool
@@ -844,6 +849,66 @@ type ResourcesStoragePoolInodes struct {
 	Total uint64 `json:"total" yaml:"total"`
 }
 
+// Storage pool health statuses.
+const (
+	// StoragePoolHealthHealthy is a storage pool operating normally.
+	StoragePoolHealthHealthy = "healthy"
+
+	// StoragePoolHealthDegraded is a storage pool still serving data but with reduced redundancy or recorded errors.
+	StoragePoolHealthDegraded = "degraded"
+
+	// StoragePoolHealthFailed is a storage pool that can no longer reliably serve data.
+	StoragePoolHealthFailed = "failed"
+)
+
+// ResourcesStoragePoolHealth represents the health of a given storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_health.
+type ResourcesStoragePoolHealth struct {
+	// Health status (healthy, degraded or failed)
+	// Example: degraded
+	Status string `json:"status" yaml:"status"`
+
+	// Description of the problem reported by the storage
+	// Example: One or more devices could not be used because the label is missing or invalid.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Devices backing the storage pool
+	Devices []ResourcesStoragePoolHealthDevice `json:"devices,omitempty" yaml:"devices,omitempty"`
+
+	// Metadata space usage (thin pools only)
+	Metadata *ResourcesStoragePoolSpace `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// ResourcesStoragePoolHealthDevice represents the health of a device backing a given storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_health.
+type ResourcesStoragePoolHealthDevice struct {
+	// Device name
+	// Example: /dev/sdb
+	Name string `json:"name" yaml:"name"`
+
+	// Device status as reported by the storage
+	// Example: ONLINE
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// Number of read errors
+	// Example: 0
+	ReadErrors uint64 `json:"read_errors" yaml:"read_errors"`
+
+	// Number of write errors
+	// Example: 0
+	WriteErrors uint64 `json:"write_errors" yaml:"write_errors"`
+
+	// Number of checksum errors
+	// Example: 2
+	ChecksumErrors uint64 `json:"checksum_errors" yaml:"checksum_errors"`
+}
+
 // ResourcesUSB represents the USB devices available on the system
 //
 // swagger:model
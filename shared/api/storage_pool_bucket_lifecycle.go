@@ -0,0 +1,56 @@
+package api
+
+// StorageBucketLifecycle represents the lifecycle configuration of a storage bucket
+//
+// swagger:model
+//
+// API extension: storage_bucket_lifecycle.
+type StorageBucketLifecycle struct {
+	// Lifecycle rules
+	//
+	// API extension: storage_bucket_lifecycle
+	Rules []StorageBucketLifecycleRule `json:"rules" yaml:"rules"`
+}
+
+// StorageBucketLifecycleRule represents a lifecycle rule of a storage bucket
+//
+// swagger:model
+//
+// API extension: storage_bucket_lifecycle.
+type StorageBucketLifecycleRule struct {
+	// Rule identifier
+	// Example: expire-artifacts
+	//
+	// API extension: storage_bucket_lifecycle
+	ID string `json:"id" yaml:"id"`
+
+	// Prefix of the object keys the rule applies to
+	// Example: artifacts/
+	//
+	// API extension: storage_bucket_lifecycle
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Whether the rule is applied
+	// Example: true
+	//
+	// API extension: storage_bucket_lifecycle
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Number of days after which objects expire, 0 to never expire them
+	// Example: 30
+	//
+	// API extension: storage_bucket_lifecycle
+	ExpirationDays int `json:"expiration_days" yaml:"expiration_days"`
+
+	// Number of days after which noncurrent object versions are deleted, 0 to keep them
+	// Example: 7
+	//
+	// API extension: storage_bucket_lifecycle
+	NoncurrentVersionExpirationDays int `json:"noncurrent_version_expiration_days" yaml:"noncurrent_version_expiration_days"`
+
+	// Number of days after which incomplete multipart uploads are aborted, 0 to keep them
+	// Example: 1
+	//
+	// API extension: storage_bucket_lifecycle
+	AbortIncompleteMultipartUploadDays int `json:"abort_incomplete_multipart_upload_days" yaml:"abort_incomplete_multipart_upload_days"`
+}
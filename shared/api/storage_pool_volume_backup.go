@@ -62,6 +62,13 @@ type StorageVolumeBackupsPost struct {
 	//
 	// API extension: backup_s3_upload
 	Target *BackupTarget `json:"target" yaml:"target"`
+
+	// Name of the dirty bitmap to make an incremental backup from
+	// Only the blocks changed since the bitmap was created are included in the backup.
+	// Example: bitmap0
+	//
+	// API extension: backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
 }
 
 // StorageVolumeBackupPost represents the fields available for the renaming of a volume backup
@@ -8,6 +8,11 @@ package api
 type StorageVolumeState struct {
 	// Volume usage
 	Usage *StorageVolumeStateUsage `json:"usage" yaml:"usage"`
+
+	// Replication information (only set when replication is configured or on replicas)
+	//
+	// API extension: replication.
+	Replication *ReplicationState `json:"replication,omitempty" yaml:"replication,omitempty"`
 }
 
 // StorageVolumeStateUsage represents the disk usage of a volume
@@ -0,0 +1,29 @@
+package units_test
+
+import (
+	"fmt"
+
+	"github.com/lxc/incus/v7/shared/units"
+)
+
+func ExampleGetBitSizeString() {
+	tests := []int64{
+		0,
+		999,
+		1000,
+		1500000,
+		10000000000,
+		2500000000000000000,
+	}
+
+	for _, v := range tests {
+		fmt.Println(units.GetBitSizeString(v, 1))
+	}
+
+	// Output: 0bit
+	// 999bit
+	// 1.0kbit
+	// 1.5Mbit
+	// 10.0Gbit
+	// 2.5Ebit
+}
@@ -104,6 +104,29 @@ func ExampleRequired() {
 	// <nil> <nil>
 }
 
+func ExampleIsBitSize() {
+	tests := []string{
+		"100",    // valid
+		"10Mbit", // valid
+		"1Gibit", // valid
+		"10MB",   // byte suffix
+		"1.5Gbit",
+		"",
+	}
+
+	for _, v := range tests {
+		err := validate.IsBitSize(v)
+		fmt.Printf("%s, %t\n", v, err == nil)
+	}
+
+	// Output: 100, true
+	// 10Mbit, true
+	// 1Gibit, true
+	// 10MB, false
+	// 1.5Gbit, false
+	// , true
+}
+
 func ExampleIsValidCPUSet() {
 	tests := []string{
 		"1",       // valid